@@ -28,7 +28,19 @@ type approveOutput struct {
 
 func runApprove(arguments []string) int {
 	if hasExplainFlag(arguments) {
-		return writeExplain("Mint a signed approval token with scope and TTL for gate decisions that require explicit approval.")
+		return writeExplain("Mint a signed approval token with scope and TTL for gate decisions that require explicit approval, or list, show, grant, and deny queued approval requests.")
+	}
+	if len(arguments) > 0 {
+		switch arguments[0] {
+		case "list":
+			return runApproveList(arguments[1:])
+		case "show":
+			return runApproveShow(arguments[1:])
+		case "grant":
+			return runApproveDecide(arguments[1:], gate.ApprovalDecisionGrant)
+		case "deny":
+			return runApproveDecide(arguments[1:], gate.ApprovalDecisionDeny)
+		}
 	}
 	flagSet := flag.NewFlagSet("approve", flag.ContinueOnError)
 	flagSet.SetOutput(io.Discard)
@@ -160,4 +172,8 @@ func writeApproveOutput(jsonOutput bool, output approveOutput, exitCode int) int
 func printApproveUsage() {
 	fmt.Println("Usage:")
 	fmt.Println("  gait approve --intent-digest <sha256> --policy-digest <sha256> [--delegation-binding-digest <sha256>] --ttl <duration> --scope <csv> --approver <identity> --reason-code <code> [--max-targets <n>] [--max-ops <n>] [--out token.json] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait approve list [--queue <dir>] [--status pending|granted|denied|expired] [--json]")
+	fmt.Println("  gait approve show --request-id <id> [--queue <dir>] [--wait <duration>] [--request-public-key <path>|--request-public-key-env <VAR>] [--json]")
+	fmt.Println("  gait approve grant --request-id <id> --approver <identity> --reason-code <code> [--queue <dir>] [--ttl <duration>] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] (--request-public-key <path>|--request-public-key-env <VAR>) [--json]")
+	fmt.Println("  gait approve deny --request-id <id> --approver <identity> --reason-code <code> (--request-public-key <path>|--request-public-key-env <VAR>) [--queue <dir>] [--json]")
 }
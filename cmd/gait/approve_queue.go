@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/gate"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+const defaultApprovalQueueDir = "./.gait-out/approval_queue"
+
+type approveQueueOutput struct {
+	OK                bool                        `json:"ok"`
+	Action            string                      `json:"action,omitempty"`
+	Request           *gate.ApprovalRequestState  `json:"request,omitempty"`
+	Requests          []gate.ApprovalRequestState `json:"requests,omitempty"`
+	SignatureVerified *bool                       `json:"signature_verified,omitempty"`
+	Warnings          []string                    `json:"warnings,omitempty"`
+	Error             string                      `json:"error,omitempty"`
+}
+
+func runApproveList(arguments []string) int {
+	flagSet := flag.NewFlagSet("approve-list", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+	var queueDir string
+	var status string
+	var jsonOutput bool
+	flagSet.StringVar(&queueDir, "queue", defaultApprovalQueueDir, "path to approval queue directory")
+	flagSet.StringVar(&status, "status", "", "optional status filter: pending|granted|denied|expired")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeApproveQueueOutput(jsonOutput, approveQueueOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
+	states, err := gate.ListApprovalRequests(strings.TrimSpace(queueDir), time.Now().UTC())
+	if err != nil {
+		return writeApproveQueueOutput(jsonOutput, approveQueueOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	status = strings.ToLower(strings.TrimSpace(status))
+	filtered := make([]gate.ApprovalRequestState, 0, len(states))
+	for _, state := range states {
+		if status == "" || state.Status == status {
+			filtered = append(filtered, state)
+		}
+	}
+	return writeApproveQueueOutput(jsonOutput, approveQueueOutput{OK: true, Action: "list", Requests: filtered}, exitOK)
+}
+
+func runApproveShow(arguments []string) int {
+	flagSet := flag.NewFlagSet("approve-show", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+	var queueDir string
+	var requestID string
+	var wait time.Duration
+	var publicKeyPath string
+	var publicKeyEnv string
+	var jsonOutput bool
+	flagSet.StringVar(&queueDir, "queue", defaultApprovalQueueDir, "path to approval queue directory")
+	flagSet.StringVar(&requestID, "request-id", "", "approval request id")
+	flagSet.DurationVar(&wait, "wait", 0, "wait up to this long for a pending request to be decided")
+	flagSet.StringVar(&publicKeyPath, "request-public-key", "", "path to base64 verify key for the request signature")
+	flagSet.StringVar(&publicKeyEnv, "request-public-key-env", "", "env var containing base64 verify key for the request signature")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeApproveQueueOutput(jsonOutput, approveQueueOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
+	if strings.TrimSpace(requestID) == "" {
+		return writeApproveQueueOutput(jsonOutput, approveQueueOutput{OK: false, Error: "--request-id is required"}, exitInvalidInput)
+	}
+	var state gate.ApprovalRequestState
+	var err error
+	if wait > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), wait)
+		state, err = gate.WaitApprovalRequest(ctx, strings.TrimSpace(queueDir), requestID, 0)
+		cancel()
+	} else {
+		state, err = gate.LoadApprovalRequest(strings.TrimSpace(queueDir), requestID, time.Now().UTC())
+	}
+	if err != nil {
+		return writeApproveQueueOutput(jsonOutput, approveQueueOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	output := approveQueueOutput{OK: true, Action: "show", Request: &state}
+	verifyConfig := sign.KeyConfig{PublicKeyPath: publicKeyPath, PublicKeyEnv: publicKeyEnv}
+	if hasAnyKeySource(verifyConfig) {
+		publicKey, err := sign.LoadVerifyKey(verifyConfig)
+		if err != nil {
+			return writeApproveQueueOutput(jsonOutput, approveQueueOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+		}
+		verified := gate.VerifyApprovalRequest(state.Request, publicKey) == nil
+		output.SignatureVerified = &verified
+		if !verified {
+			output.OK = false
+			output.Error = "approval request signature verification failed"
+			return writeApproveQueueOutput(jsonOutput, output, exitVerifyFailed)
+		}
+	}
+	return writeApproveQueueOutput(jsonOutput, output, exitOK)
+}
+
+func runApproveDecide(arguments []string, decision string) int {
+	flagSet := flag.NewFlagSet("approve-"+decision, flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+	var queueDir string
+	var requestID string
+	var approver string
+	var reasonCode string
+	var ttl time.Duration
+	var keyMode string
+	var privateKeyPath string
+	var privateKeyEnv string
+	var publicKeyPath string
+	var publicKeyEnv string
+	var jsonOutput bool
+	flagSet.StringVar(&queueDir, "queue", defaultApprovalQueueDir, "path to approval queue directory")
+	flagSet.StringVar(&requestID, "request-id", "", "approval request id")
+	flagSet.StringVar(&approver, "approver", "", "approver identity")
+	flagSet.StringVar(&publicKeyPath, "request-public-key", "", "path to base64 verify key for the request signature")
+	flagSet.StringVar(&publicKeyEnv, "request-public-key-env", "", "env var containing base64 verify key for the request signature")
+	flagSet.StringVar(&reasonCode, "reason-code", "", "decision reason code")
+	if decision == gate.ApprovalDecisionGrant {
+		flagSet.DurationVar(&ttl, "ttl", 0, "approval token ttl (default and maximum: request expiry)")
+		flagSet.StringVar(&keyMode, "key-mode", string(sign.ModeDev), "signing key mode: dev or prod")
+		flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private signing key")
+		flagSet.StringVar(&privateKeyEnv, "private-key-env", "", "env var containing base64 private signing key")
+	}
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeApproveQueueOutput(jsonOutput, approveQueueOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
+	if strings.TrimSpace(requestID) == "" {
+		return writeApproveQueueOutput(jsonOutput, approveQueueOutput{OK: false, Error: "--request-id is required"}, exitInvalidInput)
+	}
+	verifyConfig := sign.KeyConfig{PublicKeyPath: publicKeyPath, PublicKeyEnv: publicKeyEnv}
+	if !hasAnyKeySource(verifyConfig) {
+		return writeApproveQueueOutput(jsonOutput, approveQueueOutput{OK: false, Error: "--request-public-key or --request-public-key-env is required to verify the request signature"}, exitInvalidInput)
+	}
+	requestPublicKey, err := sign.LoadVerifyKey(verifyConfig)
+	if err != nil {
+		return writeApproveQueueOutput(jsonOutput, approveQueueOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+
+	var warnings []string
+	opts := gate.DecideApprovalRequestOptions{
+		ProducerVersion:  currentVersion(),
+		RequestID:        requestID,
+		Decision:         decision,
+		ApproverIdentity: approver,
+		ReasonCode:       reasonCode,
+		TTL:              ttl,
+		VerifyPublicKey:  requestPublicKey,
+	}
+	if decision == gate.ApprovalDecisionGrant {
+		keyPair, keyWarnings, err := sign.LoadSigningKey(sign.KeyConfig{
+			Mode:           sign.KeyMode(strings.ToLower(strings.TrimSpace(keyMode))),
+			PrivateKeyPath: privateKeyPath,
+			PrivateKeyEnv:  privateKeyEnv,
+		})
+		if err != nil {
+			return writeApproveQueueOutput(jsonOutput, approveQueueOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+		}
+		opts.SigningPrivateKey = keyPair.Private
+		warnings = keyWarnings
+	}
+	state, err := gate.DecideApprovalRequest(strings.TrimSpace(queueDir), opts)
+	if err != nil {
+		return writeApproveQueueOutput(jsonOutput, approveQueueOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	return writeApproveQueueOutput(jsonOutput, approveQueueOutput{OK: true, Action: decision, Request: &state, Warnings: warnings}, exitOK)
+}
+
+func writeApproveQueueOutput(jsonOutput bool, output approveQueueOutput, exitCode int) int {
+	if jsonOutput {
+		return writeJSONOutput(output, exitCode)
+	}
+	if output.Error != "" {
+		fmt.Fprintf(os.Stderr, "approve error: %s\n", output.Error)
+		return exitCode
+	}
+	if output.Request != nil {
+		request := output.Request.Request
+		fmt.Printf("%s: %s tool=%s status=%s approvals=%d/%d expires_at=%s\n",
+			output.Action,
+			request.RequestID,
+			request.ToolName,
+			output.Request.Status,
+			len(output.Request.TokenPaths),
+			request.MinApprovals,
+			request.ExpiresAt.UTC().Format(time.RFC3339),
+		)
+		return exitCode
+	}
+	fmt.Printf("%s: %d requests\n", output.Action, len(output.Requests))
+	for _, state := range output.Requests {
+		fmt.Printf("  %s %s tool=%s approvals=%d/%d\n", state.Request.RequestID, state.Status, state.Request.ToolName, len(state.TokenPaths), state.Request.MinApprovals)
+	}
+	return exitCode
+}
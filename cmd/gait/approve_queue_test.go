@@ -0,0 +1,271 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/authn"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+func TestApprovalQueueGateEvalFlow(t *testing.T) {
+	workDir := t.TempDir()
+	withWorkingDir(t, workDir)
+
+	intentPath := filepath.Join(workDir, "intent.json")
+	writeIntentFixture(t, intentPath, "tool.write")
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, strings.Join([]string{
+		"default_verdict: allow",
+		"rules:",
+		"  - name: approve-writes",
+		"    effect: require_approval",
+		"    min_approvals: 2",
+		"    require_distinct_approvers: true",
+		"    match:",
+		"      tool_names: [tool.write]",
+	}, "\n")+"\n")
+	privateKeyPath := filepath.Join(workDir, "private.key")
+	writePrivateKey(t, privateKeyPath)
+	queueDir := filepath.Join(workDir, "queue")
+	evalArgs := []string{
+		"--policy", policyPath,
+		"--intent", intentPath,
+		"--approval-queue", queueDir,
+		"--key-mode", "prod",
+		"--private-key", privateKeyPath,
+		"--json",
+	}
+
+	var gateOutput gateEvalOutput
+	raw := captureStdout(t, func() {
+		if code := runGateEval(evalArgs); code != exitApprovalRequired {
+			t.Fatalf("first eval: expected %d got %d", exitApprovalRequired, code)
+		}
+	})
+	if err := json.Unmarshal([]byte(raw), &gateOutput); err != nil {
+		t.Fatalf("decode gate output: %v (%s)", err, raw)
+	}
+	requestID := gateOutput.ApprovalRequestID
+	if requestID == "" || gateOutput.ApprovalRequestStatus != "pending" || gateOutput.ApprovalRequestPath == "" {
+		t.Fatalf("expected pending approval request in output: %#v", gateOutput)
+	}
+
+	var listOutput approveQueueOutput
+	raw = captureStdout(t, func() {
+		if code := runApprove([]string{"list", "--queue", queueDir, "--status", "pending", "--json"}); code != exitOK {
+			t.Fatalf("approve list: expected %d got %d", exitOK, code)
+		}
+	})
+	if err := json.Unmarshal([]byte(raw), &listOutput); err != nil {
+		t.Fatalf("decode list output: %v", err)
+	}
+	if len(listOutput.Requests) != 1 || listOutput.Requests[0].Request.RequestID != requestID || listOutput.Requests[0].Request.MinApprovals != 2 {
+		t.Fatalf("unexpected list output: %#v", listOutput)
+	}
+
+	keyPair, _, err := sign.LoadSigningKey(sign.KeyConfig{Mode: sign.ModeProd, PrivateKeyPath: privateKeyPath})
+	if err != nil {
+		t.Fatalf("load signing key: %v", err)
+	}
+	publicKeyPath := filepath.Join(workDir, "public.key")
+	mustWriteFile(t, publicKeyPath, base64.StdEncoding.EncodeToString(keyPair.Public)+"\n")
+	var showOutput approveQueueOutput
+	raw = captureStdout(t, func() {
+		if code := runApprove([]string{"show", "--queue", queueDir, "--request-id", requestID, "--request-public-key", publicKeyPath, "--json"}); code != exitOK {
+			t.Fatalf("approve show: expected %d got %d", exitOK, code)
+		}
+	})
+	if err := json.Unmarshal([]byte(raw), &showOutput); err != nil {
+		t.Fatalf("decode show output: %v", err)
+	}
+	if showOutput.SignatureVerified == nil || !*showOutput.SignatureVerified {
+		t.Fatalf("expected verified request signature: %#v", showOutput)
+	}
+
+	for _, approver := range []string{"alice", "bob"} {
+		if code := runApprove([]string{"grant", "--queue", queueDir, "--request-id", requestID, "--approver", approver, "--reason-code", "ticket-1", "--key-mode", "prod", "--private-key", privateKeyPath, "--request-public-key", publicKeyPath, "--json"}); code != exitOK {
+			t.Fatalf("approve grant %s: expected %d got %d", approver, exitOK, code)
+		}
+	}
+	if code := runApprove([]string{"deny", "--queue", queueDir, "--request-id", requestID, "--approver", "carol", "--reason-code", "late", "--request-public-key", publicKeyPath, "--json"}); code != exitInvalidInput {
+		t.Fatalf("deny after grant: expected %d got %d", exitInvalidInput, code)
+	}
+
+	raw = captureStdout(t, func() {
+		if code := runGateEval(append(evalArgs, "--approval-private-key", privateKeyPath)); code != exitOK {
+			t.Fatalf("granted eval: expected %d got %d", exitOK, code)
+		}
+	})
+	gateOutput = gateEvalOutput{}
+	if err := json.Unmarshal([]byte(raw), &gateOutput); err != nil {
+		t.Fatalf("decode granted output: %v", err)
+	}
+	if gateOutput.Verdict != "allow" || gateOutput.ApprovalRequestID != requestID || gateOutput.ValidApprovals != 2 {
+		t.Fatalf("expected queued grants to allow: %#v", gateOutput)
+	}
+
+	otherIntentPath := filepath.Join(workDir, "intent_other.json")
+	writeHighRiskCredentialIntentFixture(t, otherIntentPath, "tool.write")
+	deniedArgs := []string{"--policy", policyPath, "--intent", otherIntentPath, "--approval-queue", queueDir, "--key-mode", "prod", "--private-key", privateKeyPath, "--json"}
+	raw = captureStdout(t, func() {
+		_ = runGateEval(deniedArgs)
+	})
+	gateOutput = gateEvalOutput{}
+	if err := json.Unmarshal([]byte(raw), &gateOutput); err != nil {
+		t.Fatalf("decode second request output: %v", err)
+	}
+	if gateOutput.ApprovalRequestID == "" || gateOutput.ApprovalRequestID == requestID {
+		t.Fatalf("expected a separate request for a different intent: %#v", gateOutput)
+	}
+	if code := runApprove([]string{"deny", "--queue", queueDir, "--request-id", gateOutput.ApprovalRequestID, "--approver", "carol", "--reason-code", "out_of_window", "--json"}); code != exitInvalidInput {
+		t.Fatalf("approve deny without verify key: expected %d got %d", exitInvalidInput, code)
+	}
+	if code := runApprove([]string{"deny", "--queue", queueDir, "--request-id", gateOutput.ApprovalRequestID, "--approver", "carol", "--reason-code", "out_of_window", "--request-public-key", publicKeyPath, "--json"}); code != exitOK {
+		t.Fatalf("approve deny: expected %d got %d", exitOK, code)
+	}
+	if code := runGateEval(append(deniedArgs, "--approval-wait", "50ms")); code != exitPolicyBlocked {
+		t.Fatalf("denied eval: expected %d got %d", exitPolicyBlocked, code)
+	}
+}
+
+func TestMCPServeApprovalEndpoints(t *testing.T) {
+	workDir := t.TempDir()
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, strings.Join([]string{
+		"default_verdict: allow",
+		"rules:",
+		"  - name: approve-writes",
+		"    effect: require_approval",
+		"    match:",
+		"      tool_names: [tool.write]",
+	}, "\n")+"\n")
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	jwksPath := filepath.Join(workDir, "jwks.json")
+	mustWriteFile(t, jwksPath, `{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"k1","x":"`+base64.RawURLEncoding.EncodeToString(publicKey)+`"}]}`)
+	bearer := func(identity string) string {
+		return "Bearer " + signMCPServeTestJWT(t, privateKey, map[string]any{
+			"sub":   identity,
+			"email": identity,
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		})
+	}
+	newHandler := func(approvers []string) http.Handler {
+		handler, err := newMCPServeHandler(mcpServeConfig{
+			PolicyPath:        policyPath,
+			DefaultAdapter:    "mcp",
+			TraceDir:          filepath.Join(workDir, "traces"),
+			KeyMode:           "dev",
+			AuthMode:          "jwt",
+			JWTJWKSPath:       jwksPath,
+			JWTClaims:         authn.ClaimMapping{Identity: "email"},
+			IdentityBinding:   "override",
+			ApprovalQueueDir:  filepath.Join(workDir, "queue"),
+			ApprovalApprovers: approvers,
+		})
+		if err != nil {
+			t.Fatalf("newMCPServeHandler: %v", err)
+		}
+		return handler
+	}
+	handler := newHandler([]string{"bob@example.com", "carol@example.com"})
+	serve := func(target http.Handler, method string, path string, body string, authorization string) *httptest.ResponseRecorder {
+		t.Helper()
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("content-type", "application/json")
+		if authorization != "" {
+			request.Header.Set("authorization", authorization)
+		}
+		recorder := httptest.NewRecorder()
+		target.ServeHTTP(recorder, request)
+		return recorder
+	}
+	evaluate := func() mcpServeEvaluateResponse {
+		t.Helper()
+		body := `{"call":{"name":"tool.write","args":{"path":"/tmp/out.txt"},"targets":[{"kind":"path","value":"/tmp/out.txt","operation":"write"}],"context":{"identity":"alice","workspace":"/repo/gait"}}}`
+		recorder := serve(handler, http.MethodPost, "/v1/evaluate", body, bearer("alice@example.com"))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("evaluate status %d body=%s", recorder.Code, recorder.Body.String())
+		}
+		var response mcpServeEvaluateResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("decode evaluate response: %v", err)
+		}
+		return response
+	}
+
+	first := evaluate()
+	if first.Verdict != "require_approval" || first.ApprovalRequestID == "" || first.ApprovalStatus != "pending" {
+		t.Fatalf("expected pending approval request, got %#v", first)
+	}
+
+	recorder := serve(handler, http.MethodGet, "/v1/approvals?status=pending", "", bearer("alice@example.com"))
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), first.ApprovalRequestID) {
+		t.Fatalf("unexpected list response %d: %s", recorder.Code, recorder.Body.String())
+	}
+	recorder = serve(handler, http.MethodGet, "/v1/approvals/"+strings.Repeat("0", 24), "", bearer("alice@example.com"))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown request, got %d", recorder.Code)
+	}
+
+	grantPath := "/v1/approvals/" + first.ApprovalRequestID + "/grant"
+	grantBody := `{"approver":"bob@example.com","reason_code":"ticket-9","ttl":"10m"}`
+	if recorder := serve(handler, http.MethodPost, grantPath, grantBody, ""); recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without credentials, got %d", recorder.Code)
+	}
+	if recorder := serve(handler, http.MethodPost, grantPath, grantBody, bearer("alice@example.com")); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a caller that is not an approver, got %d body=%s", recorder.Code, recorder.Body.String())
+	}
+	if recorder := serve(newHandler(nil), http.MethodPost, grantPath, grantBody, bearer("bob@example.com")); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 when http decisions are disabled, got %d", recorder.Code)
+	}
+	recorder = serve(handler, http.MethodPost, grantPath, `{"reason_code":"ticket-9","ttl":"10m"}`, bearer("bob@example.com"))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("grant status %d body=%s", recorder.Code, recorder.Body.String())
+	}
+	var granted approveQueueOutput
+	if err := json.Unmarshal(recorder.Body.Bytes(), &granted); err != nil || granted.Request == nil || len(granted.Request.Decisions) != 1 {
+		t.Fatalf("decode grant response: %s (err=%v)", recorder.Body.String(), err)
+	}
+	if approver := granted.Request.Decisions[0].ApproverIdentity; approver != "bob@example.com" {
+		t.Fatalf("expected approver from the authenticated principal, got %q", approver)
+	}
+	recorder = serve(handler, http.MethodPost, "/v1/approvals/"+first.ApprovalRequestID+"/deny", `{"reason_code":"late"}`, bearer("carol@example.com"))
+	if recorder.Code != http.StatusConflict {
+		t.Fatalf("expected conflict for decided request, got %d", recorder.Code)
+	}
+
+	recorder = serve(handler, http.MethodGet, "/v1/approvals/"+first.ApprovalRequestID+"?wait=1s", "", bearer("alice@example.com"))
+	var show approveQueueOutput
+	if err := json.Unmarshal(recorder.Body.Bytes(), &show); err != nil || show.Request == nil || show.Request.Status != "granted" {
+		t.Fatalf("expected granted request, got %s (err=%v)", recorder.Body.String(), err)
+	}
+
+	second := evaluate()
+	if second.Verdict != "allow" || second.ApprovalRequestID != first.ApprovalRequestID || second.ExitCode != exitOK {
+		t.Fatalf("expected granted request to allow the call, got %#v", second)
+	}
+}
+
+func TestValidateMCPServeAuthConfigRequiresPrincipalForApprovers(t *testing.T) {
+	t.Setenv("GAIT_TEST_SERVE_TOKEN", "secret")
+	config := mcpServeConfig{AuthMode: "token", ApprovalApprovers: []string{"bob@example.com"}}
+	if err := validateMCPServeAuthConfig(&config, "GAIT_TEST_SERVE_TOKEN", true); err == nil || !strings.Contains(err.Error(), "--approval-approvers requires") {
+		t.Fatalf("expected token mode to reject approval approvers, got %v", err)
+	}
+	config = mcpServeConfig{AuthMode: "off", ApprovalApprovers: []string{"bob@example.com"}}
+	if err := validateMCPServeAuthConfig(&config, "", true); err == nil {
+		t.Fatalf("expected off mode to reject approval approvers")
+	}
+}
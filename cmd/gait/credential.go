@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/credential"
+	"github.com/Clyra-AI/gait/core/jobruntime"
+)
+
+type credentialLeaseView struct {
+	credential.Lease
+	Outstanding bool   `json:"outstanding"`
+	JobStatus   string `json:"job_status,omitempty"`
+	OutlivedJob bool   `json:"outlived_job,omitempty"`
+}
+
+type credentialOutput struct {
+	OK          bool                  `json:"ok"`
+	Operation   string                `json:"operation,omitempty"`
+	Ledger      string                `json:"ledger,omitempty"`
+	Leases      []credentialLeaseView `json:"leases,omitempty"`
+	Outstanding int                   `json:"outstanding"`
+	OutlivedJob int                   `json:"outlived_job"`
+	Swept       int                   `json:"swept,omitempty"`
+	Error       string                `json:"error,omitempty"`
+}
+
+func runCredential(arguments []string) int {
+	if hasExplainFlag(arguments) {
+		return writeExplain("Inspect the credential lease ledger: every brokered credential ref with its TTL, run and job bindings, and revocation status.")
+	}
+	if len(arguments) == 0 {
+		printCredentialUsage()
+		return exitInvalidInput
+	}
+	switch arguments[0] {
+	case "leases":
+		return runCredentialLeases(arguments[1:])
+	default:
+		printCredentialUsage()
+		return exitInvalidInput
+	}
+}
+
+func runCredentialLeases(arguments []string) int {
+	arguments = reorderInterspersedFlags(arguments, map[string]bool{"ledger": true, "jobs-root": true, "job": true})
+	flagSet := flag.NewFlagSet("credential-leases", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+	var ledgerPath string
+	var jobsRoot string
+	var jobID string
+	var includeAll bool
+	var sweep bool
+	var jsonOutput bool
+	var helpFlag bool
+	flagSet.StringVar(&ledgerPath, "ledger", credential.ResolveLedgerPath(""), "credential lease ledger")
+	flagSet.StringVar(&jobsRoot, "jobs-root", "./gait-out/jobs", "job state root used to detect leases that outlived their job")
+	flagSet.StringVar(&jobID, "job", "", "only list leases bound to this job")
+	flagSet.BoolVar(&includeAll, "all", false, "include revoked and expired leases")
+	flagSet.BoolVar(&sweep, "sweep", false, "record leases whose TTL lapsed as expired before listing")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	flagSet.BoolVar(&helpFlag, "help", false, "show help")
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeCredentialOutput(jsonOutput, credentialOutput{OK: false, Operation: "leases", Error: err.Error()}, exitInvalidInput)
+	}
+	if helpFlag {
+		printCredentialUsage()
+		return exitOK
+	}
+	if len(flagSet.Args()) > 0 {
+		return writeCredentialOutput(jsonOutput, credentialOutput{OK: false, Operation: "leases", Error: "unexpected positional arguments"}, exitInvalidInput)
+	}
+
+	now := time.Now().UTC()
+	output := credentialOutput{OK: true, Operation: "leases", Ledger: strings.TrimSpace(ledgerPath)}
+	if sweep {
+		swept, err := credential.SweepExpiredLeases(ledgerPath, now)
+		if err != nil {
+			return writeCredentialOutput(jsonOutput, credentialOutput{OK: false, Operation: "leases", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+		}
+		output.Swept = len(swept)
+	}
+	leases, err := credential.LoadLeases(ledgerPath)
+	if err != nil {
+		return writeCredentialOutput(jsonOutput, credentialOutput{OK: false, Operation: "leases", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+
+	jobStatuses := map[string]string{}
+	for _, lease := range leases {
+		if trimmedJob := strings.TrimSpace(jobID); trimmedJob != "" && lease.JobBinding != trimmedJob {
+			continue
+		}
+		view := credentialLeaseView{Lease: lease, Outstanding: lease.Outstanding(now)}
+		if lease.JobBinding != "" {
+			status, ok := jobStatuses[lease.JobBinding]
+			if !ok {
+				status = lookupCredentialJobStatus(jobsRoot, lease.JobBinding)
+				jobStatuses[lease.JobBinding] = status
+			}
+			view.JobStatus = status
+			view.OutlivedJob = view.Outstanding && jobStopped(status)
+		}
+		if view.Outstanding {
+			output.Outstanding++
+		}
+		if view.OutlivedJob {
+			output.OutlivedJob++
+		}
+		if !includeAll && !view.Outstanding {
+			continue
+		}
+		output.Leases = append(output.Leases, view)
+	}
+	return writeCredentialOutput(jsonOutput, output, exitOK)
+}
+
+func lookupCredentialJobStatus(root string, jobID string) string {
+	state, err := jobruntime.Status(root, jobID)
+	if err != nil {
+		if errors.Is(err, jobruntime.ErrJobNotFound) {
+			return "not_found"
+		}
+		return "unknown"
+	}
+	return state.Status
+}
+
+func jobStopped(status string) bool {
+	switch status {
+	case jobruntime.StatusCompleted, jobruntime.StatusCancelled, jobruntime.StatusEmergencyStop:
+		return true
+	default:
+		return false
+	}
+}
+
+func writeCredentialOutput(jsonOutput bool, output credentialOutput, exitCode int) int {
+	if jsonOutput {
+		return writeJSONOutput(output, exitCode)
+	}
+	if output.Error != "" {
+		fmt.Fprintf(os.Stderr, "credential %s error: %s\n", output.Operation, output.Error)
+		return exitCode
+	}
+	fmt.Printf("credential leases: outstanding=%d outlived_job=%d ledger=%s\n", output.Outstanding, output.OutlivedJob, output.Ledger)
+	for _, lease := range output.Leases {
+		line := fmt.Sprintf("  %s status=%s broker=%s", lease.CredentialRef, lease.Status, lease.Broker)
+		if !lease.ExpiresAt.IsZero() {
+			line += " expires_at=" + lease.ExpiresAt.Format(time.RFC3339)
+		}
+		if lease.JobBinding != "" {
+			line += fmt.Sprintf(" job=%s(%s)", lease.JobBinding, lease.JobStatus)
+		}
+		if lease.OutlivedJob {
+			line += " OUTLIVED_JOB"
+		}
+		fmt.Println(line)
+	}
+	return exitCode
+}
+
+func printCredentialUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  gait credential leases [--ledger <path>] [--jobs-root ./gait-out/jobs] [--job <job_id>] [--all] [--sweep] [--json] [--explain]")
+	fmt.Println("  default ledger: $" + credential.LedgerPathEnv + " or " + credential.DefaultLedgerPath)
+}
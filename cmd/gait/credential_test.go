@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/credential"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+)
+
+func TestCredentialLeaseLedgerTracksAndRevokesJobCredentials(t *testing.T) {
+	workDir := t.TempDir()
+	withWorkingDir(t, workDir)
+	ledgerPath := filepath.Join(workDir, "leases.jsonl")
+	t.Setenv(credential.LedgerPathEnv, ledgerPath)
+	jobsRoot := filepath.Join(workDir, "jobs")
+
+	privateKeyPath := filepath.Join(workDir, "private.key")
+	writePrivateKey(t, privateKeyPath)
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, strings.Join([]string{
+		"default_verdict: block",
+		"rules:",
+		"  - name: brokered-write",
+		"    effect: allow",
+		"    require_broker_credential: true",
+		"    match:",
+		"      tool_names: [tool.write]",
+	}, "\n")+"\n")
+	intentPath := filepath.Join(workDir, "intent.json")
+	writeIntentFixture(t, intentPath, "tool.write")
+	setIntentJobID(t, intentPath, "job_cancelled")
+
+	if code, out := runJobJSON(t, []string{"submit", "--id", "job_cancelled", "--root", jobsRoot, "--json"}); code != exitOK {
+		t.Fatalf("job submit expected %d got %d output=%#v", exitOK, code, out)
+	}
+	if code := runGateEval([]string{"--policy", policyPath, "--intent", intentPath, "--credential-broker", "stub", "--private-key", privateKeyPath, "--key-mode", "prod", "--json"}); code != exitOK {
+		t.Fatalf("gate eval with stub broker expected %d got %d", exitOK, code)
+	}
+	leases, err := credential.LoadLeases(ledgerPath)
+	if err != nil || len(leases) != 1 || leases[0].JobBinding != "job_cancelled" || leases[0].Status != credential.LeaseStatusActive || leases[0].TTLSeconds != 300 {
+		t.Fatalf("expected one active job-bound lease, leases=%#v err=%v", leases, err)
+	}
+
+	cancelCode, cancelOut := runJobJSON(t, []string{"cancel", "--id", "job_cancelled", "--root", jobsRoot, "--json"})
+	if cancelCode != exitOK || len(cancelOut.Revocations) != 1 || cancelOut.Revocations[0].Status != credential.RevokeStatusRevoked {
+		t.Fatalf("job cancel expected revoked lease got %d output=%#v", cancelCode, cancelOut)
+	}
+	leases, err = credential.LoadLeases(ledgerPath)
+	if err != nil || leases[0].Status != credential.LeaseStatusRevoked || leases[0].StatusReason != credential.RevokeReasonJobCancelled {
+		t.Fatalf("expected revoked lease after cancel, leases=%#v err=%v", leases, err)
+	}
+
+	if code, out := runJobJSON(t, []string{"submit", "--id", "job_done", "--root", jobsRoot, "--json"}); code != exitOK {
+		t.Fatalf("job submit expected %d got %d output=%#v", exitOK, code, out)
+	}
+	if code, out := runJobJSON(t, []string{"checkpoint", "add", "--id", "job_done", "--root", jobsRoot, "--type", "completed", "--summary", "done", "--json"}); code != exitOK {
+		t.Fatalf("job completion expected %d got %d output=%#v", exitOK, code, out)
+	}
+	lateResponse := credential.Response{IssuedBy: "stub", Source: "stub", CredentialRef: "stub:late", JobBinding: "job_done", IssuedAt: time.Now().UTC(), ExpiresAt: time.Now().UTC().Add(time.Hour)}
+	if err := credential.RecordLease(ledgerPath, credential.Request{ToolName: "tool.write", Identity: "alice", JobID: "job_done"}, lateResponse, time.Now().UTC()); err != nil {
+		t.Fatalf("record late lease: %v", err)
+	}
+
+	listCode, listOut := runCredentialJSON(t, []string{"leases", "--jobs-root", jobsRoot, "--json"})
+	if listCode != exitOK || listOut.Outstanding != 1 || listOut.OutlivedJob != 1 || len(listOut.Leases) != 1 || !listOut.Leases[0].OutlivedJob || listOut.Leases[0].JobStatus != "completed" {
+		t.Fatalf("credential leases expected one outlived lease got %d output=%#v", listCode, listOut)
+	}
+	allCode, allOut := runCredentialJSON(t, []string{"leases", "--jobs-root", jobsRoot, "--all", "--json"})
+	if allCode != exitOK || len(allOut.Leases) != 2 {
+		t.Fatalf("credential leases --all expected two leases got %d output=%#v", allCode, allOut)
+	}
+
+	killCode := runKillSwitch([]string{"engage", "--state", filepath.Join(workDir, "kill.json"), "--identity", "alice", "--json"})
+	if killCode != exitOK {
+		t.Fatalf("kill switch engage expected %d got %d", exitOK, killCode)
+	}
+	leases, err = credential.LoadLeases(ledgerPath)
+	if err != nil || leases[1].Status != credential.LeaseStatusRevoked || leases[1].StatusReason != credential.RevokeReasonKillSwitch {
+		t.Fatalf("expected kill switch to revoke the outstanding lease, leases=%#v err=%v", leases, err)
+	}
+}
+
+func TestKillSwitchScopedByEnvironmentLeavesLeasesActive(t *testing.T) {
+	workDir := t.TempDir()
+	withWorkingDir(t, workDir)
+	ledgerPath := filepath.Join(workDir, "leases.jsonl")
+	now := time.Now().UTC()
+	for _, identity := range []string{"alice", "bob"} {
+		response := credential.Response{IssuedBy: "stub", Source: "stub", CredentialRef: "stub:" + identity, IssuedAt: now, ExpiresAt: now.Add(time.Hour)}
+		if err := credential.RecordLease(ledgerPath, credential.Request{ToolName: "tool.write", Identity: identity}, response, now); err != nil {
+			t.Fatalf("record lease: %v", err)
+		}
+	}
+
+	killRaw := captureStdout(t, func() {
+		if code := runKillSwitch([]string{"engage", "--state", filepath.Join(workDir, "kill.json"), "--environment", "prod", "--credential-ledger", ledgerPath, "--json"}); code != exitOK {
+			t.Fatalf("kill switch engage expected %d got %d", exitOK, code)
+		}
+	})
+	var killOut killSwitchOutput
+	if err := json.Unmarshal([]byte(killRaw), &killOut); err != nil {
+		t.Fatalf("decode kill switch output: %v", err)
+	}
+	if len(killOut.Revocations) != 0 {
+		t.Fatalf("expected environment-scoped kill switch not to revoke leases, got %#v", killOut.Revocations)
+	}
+	leases, err := credential.LoadLeases(ledgerPath)
+	if err != nil || len(leases) != 2 {
+		t.Fatalf("load leases: leases=%#v err=%v", leases, err)
+	}
+	for _, lease := range leases {
+		if lease.Status != credential.LeaseStatusActive {
+			t.Fatalf("expected unrelated lease to stay active, got %#v", lease)
+		}
+	}
+}
+
+func setIntentJobID(t *testing.T, path string, jobID string) {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read intent: %v", err)
+	}
+	var intent schemagate.IntentRequest
+	if err := json.Unmarshal(raw, &intent); err != nil {
+		t.Fatalf("parse intent: %v", err)
+	}
+	intent.Context.JobID = jobID
+	encoded, err := json.MarshalIndent(intent, "", "  ")
+	if err != nil {
+		t.Fatalf("encode intent: %v", err)
+	}
+	mustWriteFile(t, path, string(encoded)+"\n")
+}
+
+func runCredentialJSON(t *testing.T, args []string) (int, credentialOutput) {
+	t.Helper()
+	var code int
+	raw := captureStdout(t, func() {
+		code = runCredential(args)
+	})
+	var output credentialOutput
+	if err := json.Unmarshal([]byte(raw), &output); err != nil {
+		t.Fatalf("decode credential output: %v raw=%q", err, raw)
+	}
+	return code, output
+}
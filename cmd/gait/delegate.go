@@ -1,6 +1,7 @@
 package main
 
 import (
+	"crypto/ed25519"
 	"errors"
 	"flag"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/trust"
 	sign "github.com/Clyra-AI/proof/signing"
 )
 
@@ -157,6 +159,7 @@ func runDelegateVerify(arguments []string) int {
 	var requiredScope string
 	var expectedIntentDigest string
 	var expectedPolicyDigest string
+	var trustStorePath string
 	var jsonOutput bool
 	var helpFlag bool
 
@@ -170,6 +173,7 @@ func runDelegateVerify(arguments []string) int {
 	flagSet.StringVar(&requiredScope, "scope", "", "required scope csv")
 	flagSet.StringVar(&expectedIntentDigest, "intent-digest", "", "expected intent digest")
 	flagSet.StringVar(&expectedPolicyDigest, "policy-digest", "", "expected policy digest")
+	flagSet.StringVar(&trustStorePath, "trust-store", "", "trust store selecting the delegator key by key id (default $"+trust.StorePathEnv+")")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
 
@@ -187,15 +191,22 @@ func runDelegateVerify(arguments []string) int {
 		return writeDelegateOutput(jsonOutput, delegateOutput{OK: false, Error: "--token is required"}, exitInvalidInput)
 	}
 
-	verifyKey, err := sign.LoadVerifyKey(sign.KeyConfig{
-		PublicKeyPath:  strings.TrimSpace(publicKeyPath),
-		PublicKeyEnv:   strings.TrimSpace(publicKeyEnv),
-		PrivateKeyPath: strings.TrimSpace(privateKeyPath),
-		PrivateKeyEnv:  strings.TrimSpace(privateKeyEnv),
-	})
+	trustStore, err := loadTrustStore(trustStorePath)
 	if err != nil {
 		return writeDelegateOutput(jsonOutput, delegateOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
+	var verifyKey ed25519.PublicKey
+	if trustStore == nil {
+		verifyKey, err = sign.LoadVerifyKey(sign.KeyConfig{
+			PublicKeyPath:  strings.TrimSpace(publicKeyPath),
+			PublicKeyEnv:   strings.TrimSpace(publicKeyEnv),
+			PrivateKeyPath: strings.TrimSpace(privateKeyPath),
+			PrivateKeyEnv:  strings.TrimSpace(privateKeyEnv),
+		})
+		if err != nil {
+			return writeDelegateOutput(jsonOutput, delegateOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+		}
+	}
 
 	token, err := gate.ReadDelegationToken(tokenPath)
 	if err != nil {
@@ -207,6 +218,7 @@ func runDelegateVerify(arguments []string) int {
 		RequiredScope:        parseCSV(requiredScope),
 		ExpectedIntentDigest: expectedIntentDigest,
 		ExpectedPolicyDigest: expectedPolicyDigest,
+		TrustStore:           trustStore,
 	}); err != nil {
 		errorCode := gate.DelegationCodeSchemaInvalid
 		var tokenErr *gate.DelegationTokenError
@@ -255,7 +267,7 @@ func writeDelegateOutput(jsonOutput bool, output delegateOutput, exitCode int) i
 func printDelegateUsage() {
 	fmt.Println("Usage:")
 	fmt.Println("  gait delegate mint --delegator <identity> --delegate <identity> --scope <csv> --ttl <duration> [--scope-class <value>] [--intent-digest <sha256>] [--policy-digest <sha256>] [--out token.json] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
-	fmt.Println("  gait delegate verify --token <token.json> [--delegator <identity>] [--delegate <identity>] [--scope <csv>] [--intent-digest <sha256>] [--policy-digest <sha256>] [--public-key <path>|--public-key-env <VAR>|--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait delegate verify --token <token.json> [--delegator <identity>] [--delegate <identity>] [--scope <csv>] [--intent-digest <sha256>] [--policy-digest <sha256>] [--public-key <path>|--public-key-env <VAR>|--private-key <path>|--private-key-env <VAR>] [--trust-store <trust_store.json>] [--json] [--explain]")
 }
 
 func printDelegateMintUsage() {
@@ -265,5 +277,5 @@ func printDelegateMintUsage() {
 
 func printDelegateVerifyUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait delegate verify --token <token.json> [--delegator <identity>] [--delegate <identity>] [--scope <csv>] [--intent-digest <sha256>] [--policy-digest <sha256>] [--public-key <path>|--public-key-env <VAR>|--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait delegate verify --token <token.json> [--delegator <identity>] [--delegate <identity>] [--scope <csv>] [--intent-digest <sha256>] [--policy-digest <sha256>] [--public-key <path>|--public-key-env <VAR>|--private-key <path>|--private-key-env <VAR>] [--trust-store <trust_store.json>] [--json] [--explain]")
 }
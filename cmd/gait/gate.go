@@ -1,24 +1,31 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Clyra-AI/gait/core/contextproof"
 	"github.com/Clyra-AI/gait/core/credential"
+	"github.com/Clyra-AI/gait/core/enforce"
 	coreerrors "github.com/Clyra-AI/gait/core/errors"
 	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/notify"
+	"github.com/Clyra-AI/gait/core/otlp"
 	"github.com/Clyra-AI/gait/core/projectconfig"
 	schemacontext "github.com/Clyra-AI/gait/core/schema/v1/context"
 	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	"github.com/Clyra-AI/gait/core/trust"
 	sign "github.com/Clyra-AI/proof/signing"
 )
 
@@ -32,6 +39,9 @@ type gateEvalOutput struct {
 	RequiredApprovals          int                              `json:"required_approvals,omitempty"`
 	ValidApprovals             int                              `json:"valid_approvals,omitempty"`
 	ApprovalAuditPath          string                           `json:"approval_audit_path,omitempty"`
+	ApprovalRequestID          string                           `json:"approval_request_id,omitempty"`
+	ApprovalRequestStatus      string                           `json:"approval_request_status,omitempty"`
+	ApprovalRequestPath        string                           `json:"approval_request_path,omitempty"`
 	DelegationRef              string                           `json:"delegation_ref,omitempty"`
 	DelegationRequired         bool                             `json:"delegation_required,omitempty"`
 	ValidDelegations           int                              `json:"valid_delegations,omitempty"`
@@ -117,6 +127,8 @@ func runGateEval(arguments []string) int {
 	var approvalTokenPath string
 	var approvalTokenChain string
 	var approvalAuditPath string
+	var approvalQueueDir string
+	var approvalWait time.Duration
 	var delegationTokenPath string
 	var delegationTokenChain string
 	var delegationAuditPath string
@@ -128,13 +140,17 @@ func runGateEval(arguments []string) int {
 	var delegationPublicKeyEnv string
 	var delegationPrivateKeyPath string
 	var delegationPrivateKeyEnv string
+	var trustStorePath string
 	var keyMode string
 	var privateKeyPath string
 	var privateKeyEnv string
 	var profile string
 	var evaluationTimeText string
 	var killSwitchStatePath string
+	var killSwitchSource killSwitchSourceFlags
 	var rateLimitState string
+	var rateLimitURL string
+	var rateLimitTokenEnv string
 	var credentialBroker string
 	var credentialEnvPrefix string
 	var credentialRef string
@@ -142,6 +158,8 @@ func runGateEval(arguments []string) int {
 	var credentialCommand string
 	var credentialCommandArgsCSV string
 	var credentialEvidencePath string
+	var credentialLedgerPath string
+	var jobRoot string
 	var wrkrInventoryPath string
 	var approvedScriptRegistryPath string
 	var approvedScriptPublicKeyPath string
@@ -161,6 +179,8 @@ func runGateEval(arguments []string) int {
 	flagSet.StringVar(&approvalTokenPath, "approval-token", "", "path to signed approval token")
 	flagSet.StringVar(&approvalTokenChain, "approval-token-chain", "", "comma-separated paths to additional signed approval tokens")
 	flagSet.StringVar(&approvalAuditPath, "approval-audit-out", "", "path to emitted approval audit JSON (default approval_audit_<trace_id>.json)")
+	flagSet.StringVar(&approvalQueueDir, "approval-queue", "", "approval queue directory; require_approval verdicts enqueue a signed approval request")
+	flagSet.DurationVar(&approvalWait, "approval-wait", 0, "wait up to this long for a queued approval request to be decided")
 	flagSet.StringVar(&delegationTokenPath, "delegation-token", "", "path to signed delegation token")
 	flagSet.StringVar(&delegationTokenChain, "delegation-token-chain", "", "comma-separated paths to additional signed delegation tokens")
 	flagSet.StringVar(&delegationAuditPath, "delegation-audit-out", "", "path to emitted delegation audit JSON (default delegation_audit_<trace_id>.json)")
@@ -172,20 +192,26 @@ func runGateEval(arguments []string) int {
 	flagSet.StringVar(&delegationPublicKeyEnv, "delegation-public-key-env", "", "env var containing base64 delegation verify key")
 	flagSet.StringVar(&delegationPrivateKeyPath, "delegation-private-key", "", "path to base64 delegation private key (derive public)")
 	flagSet.StringVar(&delegationPrivateKeyEnv, "delegation-private-key-env", "", "env var containing base64 delegation private key (derive public)")
+	flagSet.StringVar(&trustStorePath, "trust-store", "", "trust store selecting approver and delegator keys by key id (default $"+trust.StorePathEnv+")")
 	flagSet.StringVar(&keyMode, "key-mode", "", "signing key mode: dev or prod")
 	flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private signing key")
 	flagSet.StringVar(&privateKeyEnv, "private-key-env", "", "env var containing base64 private signing key")
 	flagSet.StringVar(&profile, "profile", "", "runtime profile: standard|oss-prod")
 	flagSet.StringVar(&evaluationTimeText, "evaluation-time", "", "explicit RFC3339 evaluation time for deterministic gate evaluation and replay")
-	flagSet.StringVar(&killSwitchStatePath, "kill-switch-state", "", "path to generalized kill-switch state JSON")
+	flagSet.StringVar(&killSwitchStatePath, "kill-switch-state", "", "path or http(s) URL of generalized kill-switch state JSON")
+	bindKillSwitchSourceFlags(flagSet, &killSwitchSource)
 	flagSet.StringVar(&rateLimitState, "rate-limit-state", "", "path to persisted rate limit state")
-	flagSet.StringVar(&credentialBroker, "credential-broker", "", "credential broker: off|stub|env|command")
+	flagSet.StringVar(&rateLimitURL, "rate-limit-url", "", "shared rate limit service URL hosted by gait mcp serve --rate-limit-service")
+	flagSet.StringVar(&rateLimitTokenEnv, "rate-limit-token-env", "", "env var containing bearer token for --rate-limit-url")
+	flagSet.StringVar(&credentialBroker, "credential-broker", "", "credential broker: off|stub|env|command|vault_dynamic|aws_sts|github_app")
 	flagSet.StringVar(&credentialEnvPrefix, "credential-env-prefix", "", "env broker key prefix")
 	flagSet.StringVar(&credentialRef, "credential-ref", "", "credential broker reference override")
 	flagSet.StringVar(&credentialScopesCSV, "credential-scopes", "", "comma-separated broker scopes override")
 	flagSet.StringVar(&credentialCommand, "credential-command", "", "command to execute when --credential-broker=command")
 	flagSet.StringVar(&credentialCommandArgsCSV, "credential-command-args", "", "comma-separated args for --credential-command")
 	flagSet.StringVar(&credentialEvidencePath, "credential-evidence-out", "", "path to emitted broker credential evidence JSON")
+	flagSet.StringVar(&credentialLedgerPath, "credential-ledger", "", "credential lease ledger that records issued credentials (default $"+credential.LedgerPathEnv+" or "+credential.DefaultLedgerPath+")")
+	flagSet.StringVar(&jobRoot, "job-root", "./gait-out/jobs", "job runtime root used to enforce job constraints when context.job_id is present")
 	flagSet.StringVar(&wrkrInventoryPath, "wrkr-inventory", "", "path to local Wrkr inventory JSON")
 	flagSet.StringVar(&approvedScriptRegistryPath, "approved-script-registry", "", "path to approved script registry JSON")
 	flagSet.StringVar(&approvedScriptPublicKeyPath, "approved-script-public-key", "", "path to base64 approved-script verify key")
@@ -210,12 +236,16 @@ func runGateEval(arguments []string) int {
 	if len(flagSet.Args()) > 0 {
 		return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: "unexpected positional arguments"}, exitInvalidInput)
 	}
+	var notifyConfig notify.Config
+	var telemetryConfig otlp.Config
 	if !disableConfig {
 		allowMissing := isDefaultProjectConfigPath(configPath)
 		configuration, err := projectconfig.Load(configPath, allowMissing)
 		if err != nil {
 			return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitInvalidInput)
 		}
+		notifyConfig = configuration.Notify
+		telemetryConfig = configuration.OTel
 		applyGateConfigDefaults(configuration.Gate, &policyPath, &profile, &keyMode, &privateKeyPath, &privateKeyEnv, &approvalPublicKeyPath, &approvalPublicKeyEnv, &approvalPrivateKeyPath, &approvalPrivateKeyEnv, &killSwitchStatePath, &rateLimitState, &credentialBroker, &credentialEnvPrefix, &credentialRef, &credentialScopesCSV, &credentialCommand, &credentialCommandArgsCSV, &credentialEvidencePath, &tracePath, &wrkrInventoryPath)
 	}
 	if profile == "" {
@@ -236,6 +266,14 @@ func runGateEval(arguments []string) int {
 	if policyPath == "" || intentPath == "" {
 		return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: "both --policy and --intent are required"}, exitInvalidInput)
 	}
+	notifier, err := newConfiguredNotifier(notifyConfig)
+	if err != nil {
+		return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	telemetry, err := newConfiguredTelemetry(telemetryConfig)
+	if err != nil {
+		return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
 	resolvedProfile, err := parseGateEvalProfile(profile)
 	if err != nil {
 		return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitInvalidInput)
@@ -279,20 +317,24 @@ func runGateEval(arguments []string) int {
 	}
 	var killSwitchState *schemagate.KillSwitchState
 	var killSwitchStateErr error
+	killSwitchWarnings := []string{}
+	requireKillSwitchState := strings.TrimSpace(killSwitchStatePath) != "" &&
+		(killSwitchStateRequired(resolvedProfile, intent) || gate.IsRemoteKillSwitchSource(killSwitchStatePath))
 	if strings.TrimSpace(killSwitchStatePath) != "" {
-		state, loadErr := gate.LoadKillSwitchState(killSwitchStatePath)
+		state, warnings, loadErr := loadKillSwitchStateSource(killSwitchStatePath, killSwitchSource)
 		if loadErr != nil {
-			if killSwitchStateRequired(resolvedProfile, intent) {
+			if requireKillSwitchState {
 				killSwitchStateErr = loadErr
 			} else {
 				return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: loadErr.Error()}, exitCodeForError(loadErr, exitInvalidInput))
 			}
 		} else {
 			killSwitchState = &state
+			killSwitchWarnings = warnings
 		}
 	}
 	var verifiedContextEnvelope *schemacontext.Envelope
-	startupWarnings := []string{}
+	startupWarnings := append([]string{}, killSwitchWarnings...)
 	wrkrInventory := map[string]gate.WrkrToolMetadata{}
 	wrkrSource := ""
 	if strings.TrimSpace(contextEnvelopePath) != "" {
@@ -458,7 +500,7 @@ func runGateEval(arguments []string) int {
 			ContextEvidenceNow:      evaluationNow,
 			KillSwitchState:         killSwitchState,
 			KillSwitchStateError:    killSwitchStateErr,
-			RequireKillSwitchState:  strings.TrimSpace(killSwitchStatePath) != "" && killSwitchStateRequired(resolvedProfile, intent),
+			RequireKillSwitchState:  requireKillSwitchState,
 		})
 		if err != nil {
 			return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
@@ -482,31 +524,14 @@ func runGateEval(arguments []string) int {
 		return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
 
-	var rateDecision gate.RateLimitDecision
-	var destructiveBudgetDecision gate.RateLimitDecision
-	if outcome.RateLimit.Requests > 0 {
-		rateDecision, err = gate.EnforceRateLimit(rateLimitState, outcome.RateLimit, preparedIntent, time.Now().UTC())
-		if err != nil {
-			return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
-		}
-		if !rateDecision.Allowed {
-			result.Verdict = "block"
-			result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{"rate_limit_exceeded"})
-			result.Violations = mergeUniqueSorted(result.Violations, []string{"rate_limit_exceeded"})
-		}
+	boundary := enforce.Boundary{Actor: "gate-eval", JournalSource: "gate_eval", ProducerVersion: currentVersion()}
+	rateLimitStore, err := resolveRateLimitStore(rateLimitState, rateLimitURL, rateLimitTokenEnv)
+	if err != nil {
+		return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitInvalidInput)
 	}
-	if outcome.DestructiveBudget.Requests > 0 && gateIntentContainsDestructiveTarget(preparedIntent) {
-		budgetIntent := preparedIntent
-		budgetIntent.ToolName = "destructive_budget|" + strings.TrimSpace(preparedIntent.ToolName)
-		destructiveBudgetDecision, err = gate.EnforceRateLimit(rateLimitState, outcome.DestructiveBudget, budgetIntent, time.Now().UTC())
-		if err != nil {
-			return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
-		}
-		if !destructiveBudgetDecision.Allowed {
-			result.Verdict = "block"
-			result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{"destructive_budget_exceeded"})
-			result.Violations = mergeUniqueSorted(result.Violations, []string{"destructive_budget_exceeded"})
-		}
+	result, rateDecision, destructiveBudgetDecision, err := enforce.RateLimits(rateLimitStore, outcome, preparedIntent, result, time.Now().UTC())
+	if err != nil {
+		return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
 
 	keyPair, signingWarnings, err := sign.LoadSigningKey(sign.KeyConfig{
@@ -524,12 +549,24 @@ func runGateEval(arguments []string) int {
 	validApprovals := 0
 	approvalEntries := make([]schemagate.ApprovalAuditEntry, 0)
 	approvalTokenPaths := gatherApprovalTokenPaths(approvalTokenPath, approvalTokenChain)
+	approvalRequestID := ""
+	approvalRequestStatus := ""
+	approvalRequestPath := ""
+	approvalRequestCreated := false
 	delegationRequired := outcome.RequireDelegation
 	resolvedDelegationRef := ""
 	validDelegations := 0
 	delegationEntries := make([]schemagate.DelegationAuditEntry, 0)
 	delegationTokenPaths := gatherDelegationTokenPaths(delegationTokenPath, delegationTokenChain)
 
+	var trustStore *trust.Store
+	if delegationRequired || result.Verdict == "require_approval" {
+		trustStore, err = loadTrustStore(trustStorePath)
+		if err != nil {
+			return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+		}
+	}
+
 	if delegationRequired {
 		verifyKey := keyPair.Public
 		delegationVerifyConfig := sign.KeyConfig{
@@ -546,7 +583,7 @@ func runGateEval(arguments []string) int {
 				PrivateKeyEnv:  approvalPrivateKeyEnv,
 			}
 		}
-		if resolvedProfile == gateProfileOSSProd && !hasAnyKeySource(delegationVerifyConfig) {
+		if resolvedProfile == gateProfileOSSProd && !hasAnyKeySource(delegationVerifyConfig) && trustStore == nil {
 			return writeGateEvalOutput(jsonOutput, gateEvalOutput{
 				OK:    false,
 				Error: "oss-prod profile requires explicit delegation verify key (--delegation-public-key/--delegation-public-key-env or private key source)",
@@ -577,6 +614,7 @@ func runGateEval(arguments []string) int {
 				RequiredScope:        outcome.RequiredDelegationScopes,
 				ExpectedIntentDigest: intentDigestForContext,
 				ExpectedPolicyDigest: policyDigestForContext,
+				TrustStore:           trustStore,
 			})
 			if validateErr != nil {
 				return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: validateErr.Error()}, exitCodeForError(validateErr, exitInvalidInput))
@@ -614,7 +652,7 @@ func runGateEval(arguments []string) int {
 			PrivateKeyPath: approvalPrivateKeyPath,
 			PrivateKeyEnv:  approvalPrivateKeyEnv,
 		}
-		if resolvedProfile == gateProfileOSSProd && !hasAnyKeySource(verifyConfig) {
+		if resolvedProfile == gateProfileOSSProd && !hasAnyKeySource(verifyConfig) && trustStore == nil {
 			return writeGateEvalOutput(jsonOutput, gateEvalOutput{
 				OK:    false,
 				Error: "oss-prod profile requires explicit approval verify key (--approval-public-key/--approval-public-key-env or approval private key source)",
@@ -627,9 +665,53 @@ func runGateEval(arguments []string) int {
 			}
 		}
 
+		approvalRequestDenied := false
+		if strings.TrimSpace(approvalQueueDir) != "" && !simulate {
+			queueState, created, err := gate.EnqueueApprovalRequest(strings.TrimSpace(approvalQueueDir), gate.EnqueueApprovalRequestOptions{
+				ProducerVersion:          currentVersion(),
+				ToolName:                 preparedIntent.ToolName,
+				Identity:                 preparedIntent.Context.Identity,
+				IntentDigest:             intentDigestForContext,
+				PolicyDigest:             policyDigestForContext,
+				DelegationBindingDigest:  delegationBindingDigest,
+				RequiredScope:            requiredApprovalScope,
+				MinApprovals:             requiredApprovals,
+				RequireDistinctApprovers: outcome.RequireDistinctApprovers,
+				MatchedRule:              outcome.MatchedRule,
+				ReasonCodes:              result.ReasonCodes,
+				SigningPrivateKey:        keyPair.Private,
+			})
+			if err != nil {
+				return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+			}
+			if approvalWait > 0 && queueState.Status == gate.ApprovalRequestStatusPending {
+				waitCtx, cancel := context.WithTimeout(context.Background(), approvalWait)
+				queueState, err = gate.WaitApprovalRequest(waitCtx, strings.TrimSpace(approvalQueueDir), queueState.Request.RequestID, 0)
+				cancel()
+				if err != nil {
+					return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+				}
+			}
+			approvalRequestCreated = created
+			approvalRequestID = queueState.Request.RequestID
+			approvalRequestStatus = queueState.Status
+			approvalRequestPath = queueState.Path
+			switch queueState.Status {
+			case gate.ApprovalRequestStatusGranted:
+				approvalTokenPaths = append(approvalTokenPaths, queueState.TokenPaths...)
+			case gate.ApprovalRequestStatusDenied:
+				approvalRequestDenied = true
+			}
+		}
+
 		validApproverSet := map[string]struct{}{}
 		validTokenRefs := make([]string, 0, len(approvalTokenPaths))
-		if len(approvalTokenPaths) == 0 {
+		if approvalRequestDenied {
+			result.Verdict = "block"
+			result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{gate.ApprovalReasonRequestDenied})
+			result.Violations = mergeUniqueSorted(result.Violations, []string{"approval_not_granted"})
+			exitCode = exitPolicyBlocked
+		} else if len(approvalTokenPaths) == 0 {
 			result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{gate.ApprovalReasonMissingToken})
 			result.Violations = mergeUniqueSorted(result.Violations, []string{"approval_not_granted"})
 			exitCode = exitApprovalRequired
@@ -656,6 +738,7 @@ func runGateEval(arguments []string) int {
 					RequiredScope:                   requiredApprovalScope,
 					TargetCount:                     gateIntentTargetCount(preparedIntent),
 					OperationCount:                  gateIntentOperationCount(preparedIntent),
+					TrustStore:                      trustStore,
 				})
 				if err != nil {
 					reasonCode := gate.ApprovalCodeSchemaInvalid
@@ -701,6 +784,16 @@ func runGateEval(arguments []string) int {
 		}
 	}
 
+	if !simulate {
+		var jobWarnings []string
+		result, jobWarnings = enforce.JobConstraints(jobRoot, boundary, preparedIntent, result, enforce.JobChargeOptions{
+			PolicyDigest: policyDigestForContext,
+			Cost:         outcome.Cost,
+			OnTransition: notifyJobTransition,
+		})
+		startupWarnings = append(startupWarnings, jobWarnings...)
+	}
+
 	credentialBrokerName := ""
 	credentialIssuer := ""
 	credentialSource := ""
@@ -717,80 +810,44 @@ func runGateEval(arguments []string) int {
 	credentialIssuedAt := time.Time{}
 	credentialExpiresAt := time.Time{}
 	credentialTTLSeconds := int64(0)
-	if outcome.RequireBrokerCredential && result.Verdict == "allow" {
-		if resolvedBroker == nil {
-			result.Verdict = "block"
-			result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{"broker_credential_required"})
-			result.Violations = mergeUniqueSorted(result.Violations, []string{"broker_credential_missing"})
-		} else {
-			scope := mergeUniqueSorted(outcome.BrokerScopes, parseCSV(credentialScopesCSV))
-			reference := strings.TrimSpace(credentialRef)
-			if reference == "" {
-				reference = outcome.BrokerReference
-			}
-			targetBinding, bindingErr := gate.CredentialTargetBinding(preparedIntent)
-			if bindingErr != nil {
-				return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: bindingErr.Error()}, exitCodeForError(bindingErr, exitInvalidInput))
-			}
-			credentialReferenceUsed = reference
-			credentialScopesUsed = scope
-			request := credential.Request{
-				ToolName:      preparedIntent.ToolName,
-				Identity:      preparedIntent.Context.Identity,
-				Workspace:     preparedIntent.Context.Workspace,
-				SessionID:     preparedIntent.Context.SessionID,
-				RequestID:     preparedIntent.Context.RequestID,
-				RunID:         preparedIntent.Context.RunID,
-				JobID:         preparedIntent.Context.JobID,
-				Reference:     reference,
-				Scope:         scope,
-				TargetBinding: targetBinding,
-			}
-			issued, issueErr := credential.Issue(resolvedBroker, request)
-			if issueErr != nil {
-				result.Verdict = "block"
-				result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{"broker_credential_missing"})
-				result.Violations = mergeUniqueSorted(result.Violations, []string{"broker_credential_missing"})
-			} else {
-				reasons := []string{}
-				violations := []string{}
-				for _, rule := range policyRulesForMatchedRule(policy, outcome.MatchedRule) {
-					ruleReasons, ruleViolations := gate.ValidateBrokerCredentialReceipt(rule, request, issued, gate.IntentBrokerBinding{
-						ExpectedCredentialRef: strings.TrimSpace(preparedIntent.Context.CredentialRef),
-						TargetBinding:         targetBinding,
-						RunBinding:            strings.TrimSpace(preparedIntent.Context.RunID),
-						JobBinding:            strings.TrimSpace(preparedIntent.Context.JobID),
-					})
-					reasons = mergeUniqueSorted(reasons, ruleReasons)
-					violations = mergeUniqueSorted(violations, ruleViolations)
-				}
-				if len(reasons) > 0 {
-					result.Verdict = "block"
-					result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, reasons)
-					result.Violations = mergeUniqueSorted(result.Violations, violations)
-				}
-				credentialBrokerName = issued.IssuedBy
-				credentialIssuer = issued.Issuer
-				if credentialIssuer == "" {
-					credentialIssuer = issued.IssuedBy
-				}
-				credentialSource = issued.Source
-				credentialAccessType = issued.AccessType
-				credentialSubject = issued.Subject
-				credentialOwner = issued.Owner
-				credentialRefOut = issued.CredentialRef
-				credentialTargetBinding = issued.TargetBinding
-				credentialRunBinding = issued.RunBinding
-				credentialJobBinding = issued.JobBinding
-				credentialRequestDigest = issued.RequestDigest
-				credentialIssuedAt = issued.IssuedAt
-				credentialExpiresAt = issued.ExpiresAt
-				credentialTTLSeconds = issued.TTLSeconds
-				if result.Verdict == "allow" {
-					result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{"broker_credential_present"})
-				}
-			}
-		}
+	if outcome.RequireBrokerCredential && result.Verdict == "allow" && resolvedBroker != nil {
+		credentialReferenceUsed = strings.TrimSpace(credentialRef)
+		if credentialReferenceUsed == "" {
+			credentialReferenceUsed = outcome.BrokerReference
+		}
+		credentialScopesUsed = mergeUniqueSorted(outcome.BrokerScopes, parseCSV(credentialScopesCSV))
+	}
+	var issued *credential.Response
+	var brokerWarnings []string
+	result, issued, brokerWarnings, err = enforce.BrokerCredential(policy, outcome, preparedIntent, result, enforce.BrokerOptions{
+		Broker:     resolvedBroker,
+		Reference:  credentialRef,
+		Scopes:     parseCSV(credentialScopesCSV),
+		LedgerPath: credentialLedgerPath,
+		Now:        time.Now().UTC(),
+	})
+	if err != nil {
+		return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	startupWarnings = append(startupWarnings, brokerWarnings...)
+	if issued != nil {
+		credentialBrokerName = issued.IssuedBy
+		credentialIssuer = issued.Issuer
+		if credentialIssuer == "" {
+			credentialIssuer = issued.IssuedBy
+		}
+		credentialSource = issued.Source
+		credentialAccessType = issued.AccessType
+		credentialSubject = issued.Subject
+		credentialOwner = issued.Owner
+		credentialRefOut = issued.CredentialRef
+		credentialTargetBinding = issued.TargetBinding
+		credentialRunBinding = issued.RunBinding
+		credentialJobBinding = issued.JobBinding
+		credentialRequestDigest = issued.RequestDigest
+		credentialIssuedAt = issued.IssuedAt
+		credentialExpiresAt = issued.ExpiresAt
+		credentialTTLSeconds = issued.TTLSeconds
 	}
 
 	simulatedVerdict := ""
@@ -840,21 +897,38 @@ func runGateEval(arguments []string) int {
 	if err != nil {
 		return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
-	if outcome.KillSwitch != nil && outcome.KillSwitch.Status == "active" && strings.TrimSpace(killSwitchStatePath) != "" {
-		if err := gate.AppendKillSwitchJournal(gate.KillSwitchJournalPath(killSwitchStatePath), gate.KillSwitchJournalRecord{
-			CreatedAt:       result.CreatedAt,
-			ProducerVersion: currentVersion(),
-			Source:          "gate_eval",
-			TraceID:         traceResult.Trace.TraceID,
-			JobID:           preparedIntent.Context.JobID,
-			ToolName:        preparedIntent.ToolName,
-			AgentID:         preparedIntent.Context.AgentID,
-			Identity:        preparedIntent.Context.Identity,
-			ReasonCodes:     outcome.KillSwitch.ReasonCodes,
-			MatchedEntryIDs: outcome.KillSwitch.MatchedEntryIDs,
-		}); err != nil {
-			return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
-		}
+	recordTransparencyArtifact(traceResult.TracePath)
+	journalPath := enforce.KillSwitchJournalPath(killSwitchStatePath, killSwitchSource.CachePath)
+	if err := enforce.AppendKillSwitchJournal(journalPath, boundary, preparedIntent, outcome, result, traceResult.Trace.TraceID); err != nil {
+		return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	notifyWarnings := []string{}
+	if outcome.KillSwitch != nil && outcome.KillSwitch.Status == "active" {
+		notifyWarnings = append(notifyWarnings, emitNotification(notifier, notify.Event{
+			Kind:        notify.EventKillSwitchTriggered,
+			Source:      "gate_eval",
+			Summary:     "kill switch blocked " + preparedIntent.ToolName,
+			TraceID:     traceResult.Trace.TraceID,
+			JobID:       preparedIntent.Context.JobID,
+			ToolName:    preparedIntent.ToolName,
+			Identity:    preparedIntent.Context.Identity,
+			ReasonCodes: outcome.KillSwitch.ReasonCodes,
+			Details:     map[string]string{"matched_entry_ids": strings.Join(outcome.KillSwitch.MatchedEntryIDs, ",")},
+		})...)
+	}
+	// A queued request notifies once when created, not on every re-evaluation.
+	if result.Verdict == "require_approval" && (approvalRequestID == "" || approvalRequestCreated) {
+		notifyWarnings = append(notifyWarnings, emitNotification(notifier, notify.Event{
+			Kind:        notify.EventApprovalRequired,
+			Source:      "gate_eval",
+			Summary:     "approval required for " + preparedIntent.ToolName,
+			TraceID:     traceResult.Trace.TraceID,
+			JobID:       preparedIntent.Context.JobID,
+			ToolName:    preparedIntent.ToolName,
+			Identity:    preparedIntent.Context.Identity,
+			ReasonCodes: result.ReasonCodes,
+			Details:     approvalNotificationDetails(traceResult.IntentDigest, traceResult.PolicyDigest, requiredApprovals, approvalRequestID, approvalRequestStatus),
+		})...)
 	}
 
 	resolvedApprovalAuditPath := ""
@@ -878,6 +952,7 @@ func runGateEval(arguments []string) int {
 		if err := gate.WriteApprovalAuditRecord(resolvedApprovalAuditPath, audit); err != nil {
 			return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 		}
+		recordTransparencyArtifact(resolvedApprovalAuditPath)
 		validApprovals = audit.ValidApprovals
 	}
 	if credentialRefOut != "" {
@@ -931,8 +1006,27 @@ func runGateEval(arguments []string) int {
 		if err := gate.WriteDelegationAuditRecord(resolvedDelegationAuditPath, audit); err != nil {
 			return writeGateEvalOutput(jsonOutput, gateEvalOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 		}
+		recordTransparencyArtifact(resolvedDelegationAuditPath)
 		validDelegations = audit.ValidDelegations
 	}
+	telemetry.Record(otlp.Decision{
+		Source:         "gate_eval",
+		StartedAt:      evalStart,
+		Latency:        time.Duration(evalLatencyMS * float64(time.Millisecond)),
+		ToolName:       preparedIntent.ToolName,
+		Verdict:        result.Verdict,
+		ReasonCodes:    result.ReasonCodes,
+		PolicyDigest:   traceResult.PolicyDigest,
+		PolicyID:       traceResult.Trace.PolicyID,
+		PolicyVersion:  traceResult.Trace.PolicyVersion,
+		MatchedRuleIDs: traceResult.Trace.MatchedRuleIDs,
+		IntentDigest:   traceResult.IntentDigest,
+		TraceID:        traceResult.Trace.TraceID,
+		RunID:          preparedIntent.Context.RunID,
+		SessionID:      preparedIntent.Context.SessionID,
+		JobID:          preparedIntent.Context.JobID,
+	})
+	telemetryWarnings := spoolTelemetry(telemetry)
 
 	output := gateEvalOutput{
 		OK:                         true,
@@ -944,6 +1038,9 @@ func runGateEval(arguments []string) int {
 		RequiredApprovals:          requiredApprovals,
 		ValidApprovals:             validApprovals,
 		ApprovalAuditPath:          resolvedApprovalAuditPath,
+		ApprovalRequestID:          approvalRequestID,
+		ApprovalRequestStatus:      approvalRequestStatus,
+		ApprovalRequestPath:        approvalRequestPath,
 		DelegationRef:              resolvedDelegationRef,
 		DelegationRequired:         delegationRequired,
 		ValidDelegations:           validDelegations,
@@ -987,7 +1084,7 @@ func runGateEval(arguments []string) int {
 		WouldHaveBlocked:           wouldHaveBlocked,
 		SimulatedVerdict:           simulatedVerdict,
 		SimulatedReasonCodes:       simulatedReasonCodes,
-		Warnings:                   mergeUniqueSorted(startupWarnings, signingWarnings),
+		Warnings:                   mergeUniqueSorted(mergeUniqueSorted(mergeUniqueSorted(startupWarnings, signingWarnings), notifyWarnings), telemetryWarnings),
 	}
 	if explainOutput && jsonOutput {
 		explain := gate.BuildPolicyExplain(policy, outcome, gate.BuildPolicyExplainOptions{
@@ -1006,6 +1103,19 @@ func runGateEval(arguments []string) int {
 	return writeGateEvalOutput(jsonOutput, output, exitCode)
 }
 
+func approvalNotificationDetails(intentDigest string, policyDigest string, requiredApprovals int, requestID string, requestStatus string) map[string]string {
+	details := map[string]string{
+		"intent_digest":      intentDigest,
+		"policy_digest":      policyDigest,
+		"required_approvals": strconv.Itoa(requiredApprovals),
+	}
+	if requestID != "" {
+		details["approval_request_id"] = requestID
+		details["approval_request_status"] = requestStatus
+	}
+	return details
+}
+
 func gatherApprovalTokenPaths(primaryPath, chainCSV string) []string {
 	paths := make([]string, 0, 1)
 	if strings.TrimSpace(primaryPath) != "" {
@@ -1047,41 +1157,30 @@ func gateIntentOperationCount(intent schemagate.IntentRequest) int {
 	return len(intent.Targets)
 }
 
-func gateIntentContainsDestructiveTarget(intent schemagate.IntentRequest) bool {
-	if intent.Script != nil && len(intent.Script.Steps) > 0 {
-		sawScriptTargets := false
-		for _, step := range intent.Script.Steps {
-			if len(step.Targets) == 0 {
-				continue
-			}
-			sawScriptTargets = true
-			if gate.IntentContainsDestructiveTarget(step.Targets) {
-				return true
-			}
-		}
-		if sawScriptTargets {
-			return false
+func resolveRateLimitStore(statePath string, serviceURL string, tokenEnv string) (gate.RateLimitStore, error) {
+	serviceURL = strings.TrimSpace(serviceURL)
+	tokenEnv = strings.TrimSpace(tokenEnv)
+	if serviceURL == "" {
+		if tokenEnv != "" {
+			return nil, fmt.Errorf("--rate-limit-token-env requires --rate-limit-url")
 		}
+		return &gate.FileRateLimitStore{Path: strings.TrimSpace(statePath)}, nil
 	}
-	return gate.IntentContainsDestructiveTarget(intent.Targets)
-}
-
-func policyRulesForMatchedRule(policy gate.Policy, matchedRule string) []gate.PolicyRule {
-	names := map[string]struct{}{}
-	for _, name := range strings.Split(matchedRule, ",") {
-		trimmed := strings.TrimSpace(name)
-		if trimmed != "" {
-			names[trimmed] = struct{}{}
-		}
+	parsed, err := url.Parse(serviceURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("--rate-limit-url must be an absolute http(s) URL")
 	}
-	matchedRules := make([]gate.PolicyRule, 0, len(names))
-	for _, rule := range policy.Rules {
-		if _, ok := names[rule.Name]; !ok {
-			continue
+	if parsed.Path == "" || parsed.Path == "/" {
+		parsed.Path = gate.RateLimitServicePath
+	}
+	store := &gate.HTTPRateLimitStore{URL: parsed.String()}
+	if tokenEnv != "" {
+		store.Token = strings.TrimSpace(os.Getenv(tokenEnv))
+		if store.Token == "" {
+			return nil, fmt.Errorf("--rate-limit-token-env did not resolve to a non-empty value")
 		}
-		matchedRules = append(matchedRules, rule)
 	}
-	return matchedRules
+	return store, nil
 }
 
 func buildPreApprovedOutcome(intent schemagate.IntentRequest, producerVersion string, match gate.ApprovedScriptMatch) (gate.EvalOutcome, error) {
@@ -1311,6 +1410,9 @@ func writeGateEvalOutput(jsonOutput bool, output gateEvalOutput, exitCode int) i
 		if output.ApprovalAuditPath != "" {
 			fmt.Printf("approval audit: %s\n", output.ApprovalAuditPath)
 		}
+		if output.ApprovalRequestID != "" {
+			fmt.Printf("approval request: %s (%s)\n", output.ApprovalRequestID, output.ApprovalRequestStatus)
+		}
 		if output.DelegationRequired {
 			fmt.Printf("delegations: %d\n", output.ValidDelegations)
 		}
@@ -1334,7 +1436,7 @@ func writeGateEvalOutput(jsonOutput bool, output gateEvalOutput, exitCode int) i
 
 func printGateUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait gate eval --policy <policy.yaml> --intent <intent.json> [--context-envelope <context_envelope.json>] [--config .gait/config.yaml] [--no-config] [--profile standard|oss-prod] [--simulate] [--approval-token <token.json>] [--approval-token-chain <csv>] [--delegation-token <token.json>] [--delegation-token-chain <csv>] [--approval-audit-out audit.json] [--delegation-audit-out audit.json] [--credential-broker off|stub|env|command] [--credential-command <path>] [--wrkr-inventory <inventory.json>] [--approved-script-registry <registry.json>] [--approved-script-public-key <path>|--approved-script-public-key-env <VAR>] [--evaluation-time <rfc3339>] [--kill-switch-state <state.json>] [--trace-out trace.json] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait gate eval --policy <policy.yaml> --intent <intent.json> [--context-envelope <context_envelope.json>] [--config .gait/config.yaml] [--no-config] [--profile standard|oss-prod] [--simulate] [--approval-token <token.json>] [--approval-token-chain <csv>] [--approval-queue <dir> [--approval-wait <duration>]] [--delegation-token <token.json>] [--delegation-token-chain <csv>] [--approval-audit-out audit.json] [--delegation-audit-out audit.json] [--credential-broker off|stub|env|command|vault_dynamic|aws_sts|github_app] [--credential-command <path>] [--wrkr-inventory <inventory.json>] [--approved-script-registry <registry.json>] [--approved-script-public-key <path>|--approved-script-public-key-env <VAR>] [--evaluation-time <rfc3339>] [--kill-switch-state <state.json|url>] [--kill-switch-public-key <path>|--kill-switch-public-key-env <VAR>] [--kill-switch-cache <path>] [--kill-switch-max-stale <duration>] [--trace-out trace.json] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
 	fmt.Println("Rollout path:")
 	fmt.Println("  observe: gait gate eval ... --simulate --json")
 	fmt.Println("  enforce: gait gate eval ... --json")
@@ -1342,7 +1444,7 @@ func printGateUsage() {
 
 func printGateEvalUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait gate eval --policy <policy.yaml> --intent <intent.json> [--context-envelope <context_envelope.json>] [--config .gait/config.yaml] [--no-config] [--profile standard|oss-prod] [--simulate] [--approval-token <token.json>] [--approval-token-chain <csv>] [--delegation-token <token.json>] [--delegation-token-chain <csv>] [--approval-token-ref token] [--approval-public-key <path>|--approval-public-key-env <VAR>] [--delegation-public-key <path>|--delegation-public-key-env <VAR>] [--approval-audit-out audit.json] [--delegation-audit-out audit.json] [--rate-limit-state state.json] [--credential-broker off|stub|env|command] [--credential-env-prefix GAIT_BROKER_TOKEN_] [--credential-command <path>] [--credential-command-args csv] [--credential-ref ref] [--credential-scopes csv] [--credential-evidence-out path] [--wrkr-inventory <inventory.json>] [--approved-script-registry <registry.json>] [--approved-script-public-key <path>|--approved-script-public-key-env <VAR>] [--evaluation-time <rfc3339>] [--kill-switch-state <state.json>] [--trace-out trace.json] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait gate eval --policy <policy.yaml> --intent <intent.json> [--context-envelope <context_envelope.json>] [--config .gait/config.yaml] [--no-config] [--profile standard|oss-prod] [--simulate] [--approval-token <token.json>] [--approval-token-chain <csv>] [--approval-queue <dir> [--approval-wait <duration>]] [--delegation-token <token.json>] [--delegation-token-chain <csv>] [--approval-token-ref token] [--approval-public-key <path>|--approval-public-key-env <VAR>] [--delegation-public-key <path>|--delegation-public-key-env <VAR>] [--trust-store <trust_store.json>] [--approval-audit-out audit.json] [--delegation-audit-out audit.json] [--rate-limit-state state.json|--rate-limit-url <url> [--rate-limit-token-env <VAR>]] [--credential-broker off|stub|env|command|vault_dynamic|aws_sts|github_app] [--credential-env-prefix GAIT_BROKER_TOKEN_] [--credential-command <path>] [--credential-command-args csv] [--credential-ref ref] [--credential-scopes csv] [--credential-evidence-out path] [--credential-ledger path] [--job-root ./gait-out/jobs] [--wrkr-inventory <inventory.json>] [--approved-script-registry <registry.json>] [--approved-script-public-key <path>|--approved-script-public-key-env <VAR>] [--evaluation-time <rfc3339>] [--kill-switch-state <state.json|url>] [--kill-switch-public-key <path>|--kill-switch-public-key-env <VAR>] [--kill-switch-cache <path>] [--kill-switch-max-stale <duration>] [--trace-out trace.json] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
 	fmt.Println("  observe first: add --simulate while tuning")
 	fmt.Println("  enforce later: remove --simulate once fixtures are stable")
 }
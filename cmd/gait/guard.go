@@ -5,39 +5,45 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/Clyra-AI/gait/core/guard"
+	"github.com/Clyra-AI/gait/core/transparency"
 	sign "github.com/Clyra-AI/proof/signing"
 )
 
 type guardPackOutput struct {
-	OK           bool     `json:"ok"`
-	PackPath     string   `json:"pack_path,omitempty"`
-	PackID       string   `json:"pack_id,omitempty"`
-	RunID        string   `json:"run_id,omitempty"`
-	TemplateID   string   `json:"template_id,omitempty"`
-	Controls     int      `json:"controls,omitempty"`
-	Rendered     int      `json:"rendered_artifacts,omitempty"`
-	ManifestPath string   `json:"manifest_path,omitempty"`
-	Warnings     []string `json:"warnings,omitempty"`
-	Error        string   `json:"error,omitempty"`
+	OK              bool     `json:"ok"`
+	PackPath        string   `json:"pack_path,omitempty"`
+	PackID          string   `json:"pack_id,omitempty"`
+	RunID           string   `json:"run_id,omitempty"`
+	TemplateID      string   `json:"template_id,omitempty"`
+	TemplateVersion string   `json:"template_version,omitempty"`
+	Controls        int      `json:"controls,omitempty"`
+	ControlGaps     []string `json:"control_gaps,omitempty"`
+	RequiredGaps    []string `json:"required_gaps,omitempty"`
+	Rendered        int      `json:"rendered_artifacts,omitempty"`
+	ManifestPath    string   `json:"manifest_path,omitempty"`
+	Warnings        []string `json:"warnings,omitempty"`
+	Error           string   `json:"error,omitempty"`
 }
 
 type guardVerifyOutput struct {
-	OK              bool                 `json:"ok"`
-	Path            string               `json:"path,omitempty"`
-	PackID          string               `json:"pack_id,omitempty"`
-	RunID           string               `json:"run_id,omitempty"`
-	FilesChecked    int                  `json:"files_checked,omitempty"`
-	MissingFiles    []string             `json:"missing_files,omitempty"`
-	HashMismatches  []guard.HashMismatch `json:"hash_mismatches,omitempty"`
-	SignatureStatus string               `json:"signature_status,omitempty"`
-	SignatureErrors []string             `json:"signature_errors,omitempty"`
-	SignaturesTotal int                  `json:"signatures_total,omitempty"`
-	SignaturesValid int                  `json:"signatures_valid,omitempty"`
-	Error           string               `json:"error,omitempty"`
+	OK              bool                          `json:"ok"`
+	Path            string                        `json:"path,omitempty"`
+	PackID          string                        `json:"pack_id,omitempty"`
+	RunID           string                        `json:"run_id,omitempty"`
+	FilesChecked    int                           `json:"files_checked,omitempty"`
+	MissingFiles    []string                      `json:"missing_files,omitempty"`
+	HashMismatches  []guard.HashMismatch          `json:"hash_mismatches,omitempty"`
+	SignatureStatus string                        `json:"signature_status,omitempty"`
+	SignatureErrors []string                      `json:"signature_errors,omitempty"`
+	SignaturesTotal int                           `json:"signatures_total,omitempty"`
+	SignaturesValid int                           `json:"signatures_valid,omitempty"`
+	Inclusion       *transparency.InclusionResult `json:"inclusion,omitempty"`
+	Error           string                        `json:"error,omitempty"`
 }
 
 type guardRetainOutput struct {
@@ -108,6 +114,7 @@ func runGuardPack(arguments []string) int {
 		"approval-audit":      true,
 		"credential-evidence": true,
 		"template":            true,
+		"registry-cache-dir":  true,
 		"key-mode":            true,
 		"private-key":         true,
 		"private-key-env":     true,
@@ -124,6 +131,7 @@ func runGuardPack(arguments []string) int {
 	var approvalAuditCSV string
 	var credentialEvidenceCSV string
 	var templateID string
+	var registryCacheDir string
 	var keyMode string
 	var privateKeyPath string
 	var privateKeyEnv string
@@ -139,7 +147,8 @@ func runGuardPack(arguments []string) int {
 	flagSet.StringVar(&regressCSV, "regress", "", "comma-separated regress result paths")
 	flagSet.StringVar(&approvalAuditCSV, "approval-audit", "", "comma-separated approval audit record paths")
 	flagSet.StringVar(&credentialEvidenceCSV, "credential-evidence", "", "comma-separated broker credential evidence paths")
-	flagSet.StringVar(&templateID, "template", "soc2", "audit template id: soc2|pci|incident_response")
+	flagSet.StringVar(&templateID, "template", "soc2", "control template: soc2|pci|incident_response, a .yaml template path, or registry:<pack>[#artifact]")
+	flagSet.StringVar(&registryCacheDir, "registry-cache-dir", "", "registry cache directory for registry: templates (default ~/.gait/registry)")
 	flagSet.StringVar(&keyMode, "key-mode", string(sign.ModeDev), "signing key mode: dev or prod")
 	flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private signing key")
 	flagSet.StringVar(&privateKeyEnv, "private-key-env", "", "env var containing base64 private signing key")
@@ -185,6 +194,7 @@ func runGuardPack(arguments []string) int {
 		ApprovalAuditPaths:      parseCSVList(approvalAuditCSV),
 		CredentialEvidencePaths: parseCSVList(credentialEvidenceCSV),
 		TemplateID:              templateID,
+		RegistryCacheDir:        registryCacheDir,
 		RenderPDF:               renderPDF,
 		AutoDiscoverV12:         true,
 		ProducerVersion:         currentVersion(),
@@ -193,18 +203,28 @@ func runGuardPack(arguments []string) int {
 	if err != nil {
 		return writeGuardPackOutput(jsonOutput, guardPackOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
+	recordTransparencyArtifact(result.PackPath)
 	manifestPath := result.PackPath + "#pack_manifest.json"
-	return writeGuardPackOutput(jsonOutput, guardPackOutput{
-		OK:           true,
-		PackPath:     result.PackPath,
-		PackID:       result.Manifest.PackID,
-		RunID:        result.Manifest.RunID,
-		TemplateID:   result.Manifest.TemplateID,
-		Controls:     len(result.Manifest.ControlIndex),
-		Rendered:     len(result.Manifest.Rendered),
-		ManifestPath: manifestPath,
-		Warnings:     warnings,
-	}, exitOK)
+	output := guardPackOutput{
+		OK:              true,
+		PackPath:        result.PackPath,
+		PackID:          result.Manifest.PackID,
+		RunID:           result.Manifest.RunID,
+		TemplateID:      result.Manifest.TemplateID,
+		TemplateVersion: result.Manifest.TemplateVersion,
+		Controls:        len(result.Manifest.ControlIndex),
+		ControlGaps:     result.Manifest.ControlGaps,
+		RequiredGaps:    result.RequiredGaps,
+		Rendered:        len(result.Manifest.Rendered),
+		ManifestPath:    manifestPath,
+		Warnings:        warnings,
+	}
+	if len(result.RequiredGaps) > 0 {
+		output.OK = false
+		output.Error = "required controls have no evidence: " + strings.Join(result.RequiredGaps, ", ")
+		return writeGuardPackOutput(jsonOutput, output, exitVerifyFailed)
+	}
+	return writeGuardPackOutput(jsonOutput, output, exitOK)
 }
 
 func runGuardVerify(arguments []string) int {
@@ -225,6 +245,7 @@ func runGuardVerify(arguments []string) int {
 	var pathValue string
 	var profile string
 	var requireSignature bool
+	var inclusionProofPath string
 	var publicKeyPath string
 	var publicKeyEnv string
 	var privateKeyPath string
@@ -235,6 +256,7 @@ func runGuardVerify(arguments []string) int {
 	flagSet.StringVar(&pathValue, "path", "", "path to evidence_pack zip")
 	flagSet.StringVar(&profile, "profile", string(verifyProfileStandard), "verify profile: standard|strict")
 	flagSet.BoolVar(&requireSignature, "require-signature", false, "require valid pack manifest signatures")
+	flagSet.StringVar(&inclusionProofPath, "inclusion-proof", "", "transparency log inclusion proof for the pack manifest")
 	flagSet.StringVar(&publicKeyPath, "public-key", "", "path to base64 public key")
 	flagSet.StringVar(&publicKeyEnv, "public-key-env", "", "env var containing base64 public key")
 	flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private key (derive public)")
@@ -285,15 +307,20 @@ func runGuardVerify(arguments []string) int {
 		}
 		publicKey = loadedKey
 	}
+	inclusionProof, err := loadInclusionProofFlag(inclusionProofPath)
+	if err != nil {
+		return writeGuardVerifyOutput(jsonOutput, guardVerifyOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
 
 	result, err := guard.VerifyPackWithOptions(pathValue, guard.VerifyOptions{
 		PublicKey:        publicKey,
 		RequireSignature: requireSignature,
+		InclusionProof:   inclusionProof,
 	})
 	if err != nil {
 		return writeGuardVerifyOutput(jsonOutput, guardVerifyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
-	ok := len(result.MissingFiles) == 0 && len(result.HashMismatches) == 0
+	ok := len(result.MissingFiles) == 0 && len(result.HashMismatches) == 0 && inclusionOK(result.Inclusion, requireSignature)
 	if requireSignature {
 		ok = ok && result.SignatureStatus == "verified"
 	} else {
@@ -315,6 +342,7 @@ func runGuardVerify(arguments []string) int {
 		SignatureErrors: result.SignatureErrors,
 		SignaturesTotal: result.SignaturesTotal,
 		SignaturesValid: result.SignaturesValid,
+		Inclusion:       result.Inclusion,
 	}, exitCode)
 }
 
@@ -323,10 +351,11 @@ func runGuardRetain(arguments []string) int {
 		return writeExplain("Apply deterministic retention policies to trace and evidence pack artifacts and emit a deletion report.")
 	}
 	arguments = reorderInterspersedFlags(arguments, map[string]bool{
-		"root":       true,
-		"trace-ttl":  true,
-		"pack-ttl":   true,
-		"report-out": true,
+		"root":             true,
+		"trace-ttl":        true,
+		"pack-ttl":         true,
+		"report-out":       true,
+		"transparency-log": true,
 	})
 	flagSet := flag.NewFlagSet("guard-retain", flag.ContinueOnError)
 	flagSet.SetOutput(io.Discard)
@@ -335,6 +364,7 @@ func runGuardRetain(arguments []string) int {
 	var traceTTL string
 	var packTTL string
 	var reportPath string
+	var transparencyLog string
 	var dryRun bool
 	var jsonOutput bool
 	var helpFlag bool
@@ -343,6 +373,7 @@ func runGuardRetain(arguments []string) int {
 	flagSet.StringVar(&traceTTL, "trace-ttl", "168h", "retention window for trace_*.json")
 	flagSet.StringVar(&packTTL, "pack-ttl", "720h", "retention window for evidence_pack_*.zip")
 	flagSet.StringVar(&reportPath, "report-out", "", "optional retention report path")
+	flagSet.StringVar(&transparencyLog, "transparency-log", os.Getenv(transparencyLogEnv), "transparency log that records each deletion")
 	flagSet.BoolVar(&dryRun, "dry-run", false, "calculate retention actions without deleting files")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
@@ -374,6 +405,7 @@ func runGuardRetain(arguments []string) int {
 		DryRun:          dryRun,
 		ReportOutput:    reportPath,
 		ProducerVersion: currentVersion(),
+		TransparencyLog: transparencyLog,
 	})
 	if err != nil {
 		return writeGuardRetainOutput(jsonOutput, guardRetainOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
@@ -526,6 +558,9 @@ func writeGuardPackOutput(jsonOutput bool, output guardPackOutput, exitCode int)
 	}
 	if output.OK {
 		fmt.Printf("guard pack ok: %s\n", output.PackPath)
+		if len(output.ControlGaps) > 0 {
+			fmt.Printf("control gaps: %s\n", strings.Join(output.ControlGaps, ", "))
+		}
 		return exitCode
 	}
 	fmt.Printf("guard pack error: %s\n", output.Error)
@@ -586,26 +621,26 @@ func writeGuardDecryptOutput(jsonOutput bool, output guardDecryptOutput, exitCod
 
 func printGuardUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait guard pack --run <run_id|path> [--inventory <csv>] [--trace <csv>] [--regress <csv>] [--approval-audit <csv>] [--credential-evidence <csv>] [--template soc2|pci|incident_response] [--render-pdf] [--out <evidence_pack.zip>] [--case-id <id>] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
-	fmt.Println("  gait guard verify <evidence_pack.zip> [--profile standard|strict] [--require-signature] [--public-key <path>|--public-key-env <VAR>] [--json] [--explain]")
-	fmt.Println("  gait guard retain [--root <dir>] [--trace-ttl <duration>] [--pack-ttl <duration>] [--dry-run] [--report-out <path>] [--json] [--explain]")
+	fmt.Println("  gait guard pack --run <run_id|path> [--inventory <csv>] [--trace <csv>] [--regress <csv>] [--approval-audit <csv>] [--credential-evidence <csv>] [--template soc2|pci|incident_response|<template.yaml>|registry:<pack>] [--registry-cache-dir <dir>] [--render-pdf] [--out <evidence_pack.zip>] [--case-id <id>] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait guard verify <evidence_pack.zip> [--profile standard|strict] [--require-signature] [--inclusion-proof <proof.json>] [--public-key <path>|--public-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait guard retain [--root <dir>] [--trace-ttl <duration>] [--pack-ttl <duration>] [--dry-run] [--report-out <path>] [--transparency-log <dir>] [--json] [--explain]")
 	fmt.Println("  gait guard encrypt --in <artifact> [--out <artifact.gaitenc>] [--key-env <ENV>|--key-command <cmd> --key-command-args <csv>] [--json] [--explain]")
 	fmt.Println("  gait guard decrypt --in <artifact.gaitenc> [--out <artifact>] [--key-env <ENV>|--key-command <cmd> --key-command-args <csv>] [--json] [--explain]")
 }
 
 func printGuardPackUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait guard pack --run <run_id|path> [--inventory <csv>] [--trace <csv>] [--regress <csv>] [--approval-audit <csv>] [--credential-evidence <csv>] [--template soc2|pci|incident_response] [--render-pdf] [--out <evidence_pack.zip>] [--case-id <id>] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait guard pack --run <run_id|path> [--inventory <csv>] [--trace <csv>] [--regress <csv>] [--approval-audit <csv>] [--credential-evidence <csv>] [--template soc2|pci|incident_response|<template.yaml>|registry:<pack>] [--registry-cache-dir <dir>] [--render-pdf] [--out <evidence_pack.zip>] [--case-id <id>] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
 }
 
 func printGuardVerifyUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait guard verify <evidence_pack.zip> [--profile standard|strict] [--require-signature] [--public-key <path>|--public-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait guard verify <evidence_pack.zip> [--profile standard|strict] [--require-signature] [--inclusion-proof <proof.json>] [--public-key <path>|--public-key-env <VAR>] [--json] [--explain]")
 }
 
 func printGuardRetainUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait guard retain [--root <dir>] [--trace-ttl <duration>] [--pack-ttl <duration>] [--dry-run] [--report-out <path>] [--json] [--explain]")
+	fmt.Println("  gait guard retain [--root <dir>] [--trace-ttl <duration>] [--pack-ttl <duration>] [--dry-run] [--report-out <path>] [--transparency-log <dir>] [--json] [--explain]")
 }
 
 func printGuardEncryptUsage() {
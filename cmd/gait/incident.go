@@ -70,7 +70,7 @@ func runIncidentPack(arguments []string) int {
 	flagSet.StringVar(&window, "window", "24h", "incident selection window duration around run created_at")
 	flagSet.StringVar(&outPath, "out", "", "output incident pack path")
 	flagSet.StringVar(&caseID, "case-id", "", "optional incident case id")
-	flagSet.StringVar(&templateID, "template", "incident_response", "template id: incident_response|soc2|pci")
+	flagSet.StringVar(&templateID, "template", "incident_response", "control template: incident_response|soc2|pci or a .yaml template path")
 	flagSet.BoolVar(&renderPDF, "render-pdf", false, "include summary.pdf convenience artifact")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
@@ -113,6 +113,7 @@ func runIncidentPack(arguments []string) int {
 		return writeIncidentPackOutput(jsonOutput, incidentPackOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
 
+	recordTransparencyArtifact(result.BuildResult.PackPath)
 	return writeIncidentPackOutput(jsonOutput, incidentPackOutput{
 		OK:                      true,
 		PackPath:                result.BuildResult.PackPath,
@@ -143,10 +144,10 @@ func writeIncidentPackOutput(jsonOutput bool, output incidentPackOutput, exitCod
 
 func printIncidentUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait incident pack --from <run_id|path> [--window <duration>] [--template incident_response|soc2|pci] [--render-pdf] [--out <incident_pack.zip>] [--case-id <id>] [--json] [--explain]")
+	fmt.Println("  gait incident pack --from <run_id|path> [--window <duration>] [--template incident_response|soc2|pci|<template.yaml>] [--render-pdf] [--out <incident_pack.zip>] [--case-id <id>] [--json] [--explain]")
 }
 
 func printIncidentPackUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait incident pack --from <run_id|path> [--window <duration>] [--template incident_response|soc2|pci] [--render-pdf] [--out <incident_pack.zip>] [--case-id <id>] [--json] [--explain]")
+	fmt.Println("  gait incident pack --from <run_id|path> [--window <duration>] [--template incident_response|soc2|pci|<template.yaml>] [--render-pdf] [--out <incident_pack.zip>] [--case-id <id>] [--json] [--explain]")
 }
@@ -9,8 +9,10 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/Clyra-AI/gait/core/credential"
 	"github.com/Clyra-AI/gait/core/gate"
 	"github.com/Clyra-AI/gait/core/jobruntime"
+	"github.com/Clyra-AI/gait/core/notify"
 )
 
 const (
@@ -19,21 +21,23 @@ const (
 )
 
 type jobOutput struct {
-	SchemaID      string                  `json:"schema_id"`
-	SchemaVersion string                  `json:"schema_version"`
-	OK            bool                    `json:"ok"`
-	Operation     string                  `json:"operation,omitempty"`
-	JobID         string                  `json:"job_id,omitempty"`
-	Job           *jobruntime.JobState    `json:"job,omitempty"`
-	Checkpoint    *jobruntime.Checkpoint  `json:"checkpoint,omitempty"`
-	Checkpoints   []jobruntime.Checkpoint `json:"checkpoints,omitempty"`
-	Events        []jobruntime.Event      `json:"events,omitempty"`
-	Error         string                  `json:"error,omitempty"`
+	SchemaID      string                       `json:"schema_id"`
+	SchemaVersion string                       `json:"schema_version"`
+	OK            bool                         `json:"ok"`
+	Operation     string                       `json:"operation,omitempty"`
+	JobID         string                       `json:"job_id,omitempty"`
+	Job           *jobruntime.JobState         `json:"job,omitempty"`
+	Checkpoint    *jobruntime.Checkpoint       `json:"checkpoint,omitempty"`
+	Checkpoints   []jobruntime.Checkpoint      `json:"checkpoints,omitempty"`
+	Events        []jobruntime.Event           `json:"events,omitempty"`
+	Supervisor    *jobruntime.SupervisorStatus `json:"supervisor,omitempty"`
+	Revocations   []credential.RevokeResult    `json:"revocations,omitempty"`
+	Error         string                       `json:"error,omitempty"`
 }
 
 func runJob(arguments []string) int {
 	if hasExplainFlag(arguments) {
-		return writeExplain("Manage durable job lifecycle controls with deterministic status transitions, checkpoint interrupts, approvals, and resume gating.")
+		return writeExplain("Manage durable job lifecycle controls with deterministic status transitions, checkpoint interrupts, approvals, resume gating, job-scoped tool-call constraints, and a supervised agent runner.")
 	}
 	if len(arguments) == 0 {
 		printJobUsage()
@@ -58,6 +62,10 @@ func runJob(arguments []string) int {
 		return runJobCancel(arguments[1:])
 	case "inspect":
 		return runJobInspect(arguments[1:])
+	case "run":
+		return runJobRun(arguments[1:])
+	case "constraints":
+		return runJobConstraints(arguments[1:])
 	default:
 		printJobUsage()
 		return exitInvalidInput
@@ -66,14 +74,18 @@ func runJob(arguments []string) int {
 
 func runJobSubmit(arguments []string) int {
 	arguments = reorderInterspersedFlags(arguments, map[string]bool{
-		"id":              true,
-		"root":            true,
-		"actor":           true,
-		"identity":        true,
-		"env-fingerprint": true,
-		"policy":          true,
-		"policy-digest":   true,
-		"policy-ref":      true,
+		"id":                  true,
+		"root":                true,
+		"actor":               true,
+		"identity":            true,
+		"env-fingerprint":     true,
+		"policy":              true,
+		"policy-digest":       true,
+		"policy-ref":          true,
+		"max-tool-calls":      true,
+		"max-destructive-ops": true,
+		"allowed-tools":       true,
+		"spend-ceiling":       true,
 	})
 	flagSet := flag.NewFlagSet("job-submit", flag.ContinueOnError)
 	flagSet.SetOutput(io.Discard)
@@ -86,6 +98,7 @@ func runJobSubmit(arguments []string) int {
 	var policyPath string
 	var policyDigest string
 	var policyRef string
+	var constraints jobruntime.JobConstraints
 	var jsonOutput bool
 	var helpFlag bool
 
@@ -97,6 +110,7 @@ func runJobSubmit(arguments []string) int {
 	flagSet.StringVar(&policyPath, "policy", "", "path to policy yaml used for submit/resume enforcement")
 	flagSet.StringVar(&policyDigest, "policy-digest", "", "policy digest override (sha256:...)")
 	flagSet.StringVar(&policyRef, "policy-ref", "", "policy reference identifier")
+	registerJobConstraintFlags(flagSet, &constraints)
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
 
@@ -122,6 +136,7 @@ func runJobSubmit(arguments []string) int {
 		EnvironmentFingerprint: jobruntime.EnvironmentFingerprint(envFingerprint),
 		PolicyDigest:           resolvedPolicyDigest,
 		PolicyRef:              resolvedPolicyRef,
+		Constraints:            constraints,
 	})
 	if err != nil {
 		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "submit", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
@@ -181,12 +196,15 @@ func runJobCheckpoint(arguments []string) int {
 
 func runJobCheckpointAdd(arguments []string) int {
 	arguments = reorderInterspersedFlags(arguments, map[string]bool{
-		"id":              true,
-		"root":            true,
-		"type":            true,
-		"summary":         true,
-		"required-action": true,
-		"actor":           true,
+		"id":                  true,
+		"root":                true,
+		"type":                true,
+		"summary":             true,
+		"required-action":     true,
+		"actor":               true,
+		"credential-broker":   true,
+		"credential-evidence": true,
+		"credential-ledger":   true,
 	})
 	flagSet := flag.NewFlagSet("job-checkpoint-add", flag.ContinueOnError)
 	flagSet.SetOutput(io.Discard)
@@ -197,6 +215,9 @@ func runJobCheckpointAdd(arguments []string) int {
 	var summary string
 	var requiredAction string
 	var actor string
+	var credentialBroker string
+	var credentialEvidenceCSV string
+	var credentialLedger string
 	var jsonOutput bool
 	var helpFlag bool
 
@@ -206,6 +227,9 @@ func runJobCheckpointAdd(arguments []string) int {
 	flagSet.StringVar(&summary, "summary", "", "checkpoint summary (max 512 chars)")
 	flagSet.StringVar(&requiredAction, "required-action", "", "required action for decision-needed checkpoints")
 	flagSet.StringVar(&actor, "actor", "", "actor identity")
+	flagSet.StringVar(&credentialBroker, "credential-broker", "", "broker used to revoke job credentials on completion: vault_dynamic|aws_sts|github_app")
+	flagSet.StringVar(&credentialEvidenceCSV, "credential-evidence", "", "comma-separated broker credential evidence files to revoke on completion")
+	flagSet.StringVar(&credentialLedger, "credential-ledger", "", "credential lease ledger whose job-bound leases are revoked on completion (default $"+credential.LedgerPathEnv+" or "+credential.DefaultLedgerPath+")")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
 
@@ -219,17 +243,80 @@ func runJobCheckpointAdd(arguments []string) int {
 	if len(flagSet.Args()) > 0 {
 		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "checkpoint add", Error: "unexpected positional arguments"}, exitInvalidInput)
 	}
+	evidencePaths := parseCSV(credentialEvidenceCSV)
+	var revocationBroker credential.Broker
+	if len(evidencePaths) > 0 {
+		broker, err := credential.ResolveBroker(credentialBroker, "", "", nil)
+		if err != nil {
+			return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "checkpoint add", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+		}
+		if broker == nil {
+			return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "checkpoint add", Error: "--credential-evidence requires --credential-broker"}, exitInvalidInput)
+		}
+		revocationBroker = broker
+	}
 
+	leaseRevocation := &jobLeaseRevocation{ledgerPath: credentialLedger}
 	state, checkpoint, err := jobruntime.AddCheckpoint(root, strings.TrimSpace(jobID), jobruntime.CheckpointOptions{
 		Type:           strings.TrimSpace(checkpointType),
 		Summary:        strings.TrimSpace(summary),
 		RequiredAction: strings.TrimSpace(requiredAction),
 		Actor:          strings.TrimSpace(actor),
+		OnStop:         leaseRevocation.onStop,
 	})
 	if err != nil {
 		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "checkpoint add", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
-	return writeJobOutput(jsonOutput, jobOutput{OK: true, Operation: "checkpoint add", JobID: state.JobID, Job: &state, Checkpoint: &checkpoint}, exitOK)
+	output := jobOutput{OK: true, Operation: "checkpoint add", JobID: state.JobID, Job: &state, Checkpoint: &checkpoint, Revocations: leaseRevocation.results}
+	if leaseRevocation.err != nil {
+		output.OK = false
+		output.Error = fmt.Sprintf("checkpoint committed but credential revocation failed: %v", leaseRevocation.err)
+		return writeJobOutput(jsonOutput, output, exitCodeForError(leaseRevocation.err, exitInvalidInput))
+	}
+	if revocationBroker != nil && state.Status == jobruntime.StatusCompleted {
+		revocations, err := revokeJobCredentials(revocationBroker, evidencePaths, state.JobID, output.Revocations)
+		if err != nil {
+			output.OK = false
+			output.Error = err.Error()
+			return writeJobOutput(jsonOutput, output, exitCodeForError(err, exitInvalidInput))
+		}
+		output.Revocations = append(output.Revocations, revocations...)
+	}
+	return writeJobOutput(jsonOutput, output, exitOK)
+}
+
+func revokeJobCredentials(broker credential.Broker, evidencePaths []string, jobID string, alreadyRevoked []credential.RevokeResult) ([]credential.RevokeResult, error) {
+	handled := map[string]struct{}{}
+	for _, result := range alreadyRevoked {
+		handled[result.CredentialRef] = struct{}{}
+	}
+	issued := make([]credential.Response, 0, len(evidencePaths))
+	for _, path := range evidencePaths {
+		record, err := gate.ReadBrokerCredentialRecord(path)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := handled[record.CredentialRef]; ok {
+			continue
+		}
+		issued = append(issued, credential.Response{
+			IssuedBy:      record.Broker,
+			Source:        record.CredentialSource,
+			Issuer:        record.CredentialIssuer,
+			Subject:       record.CredentialSubject,
+			Owner:         record.CredentialOwner,
+			Scope:         record.Scope,
+			CredentialRef: record.CredentialRef,
+			TargetBinding: record.TargetBinding,
+			RunBinding:    record.RunBinding,
+			JobBinding:    record.JobBinding,
+			RequestDigest: record.RequestDigest,
+			IssuedAt:      record.IssuedAt,
+			ExpiresAt:     record.ExpiresAt,
+			TTLSeconds:    record.TTLSeconds,
+		})
+	}
+	return credential.RevokeJob(broker, issued, jobID)
 }
 
 func runJobCheckpointList(arguments []string) int {
@@ -299,37 +386,41 @@ func runJobCheckpointShow(arguments []string) int {
 }
 
 func runJobPause(arguments []string) int {
-	return runSimpleJobTransition(arguments, "pause", func(root, jobID, actor string) (jobruntime.JobState, error) {
-		return jobruntime.Pause(root, jobID, jobruntime.TransitionOptions{Actor: actor})
+	return runSimpleJobTransition(arguments, "pause", func(root, jobID string, opts jobruntime.TransitionOptions) (jobruntime.JobState, error) {
+		return jobruntime.Pause(root, jobID, opts)
 	})
 }
 
 func runJobCancel(arguments []string) int {
-	return runSimpleJobTransition(arguments, "cancel", func(root, jobID, actor string) (jobruntime.JobState, error) {
-		return jobruntime.Cancel(root, jobID, jobruntime.TransitionOptions{Actor: actor})
+	return runSimpleJobTransition(arguments, "cancel", func(root, jobID string, opts jobruntime.TransitionOptions) (jobruntime.JobState, error) {
+		return jobruntime.Cancel(root, jobID, opts)
 	})
 }
 
 func runJobStop(arguments []string) int {
-	return runSimpleJobTransition(arguments, "stop", func(root, jobID, actor string) (jobruntime.JobState, error) {
-		return jobruntime.EmergencyStop(root, jobID, jobruntime.TransitionOptions{Actor: actor})
+	return runSimpleJobTransition(arguments, "stop", func(root, jobID string, opts jobruntime.TransitionOptions) (jobruntime.JobState, error) {
+		return jobruntime.EmergencyStop(root, jobID, opts)
 	})
 }
 
-func runSimpleJobTransition(arguments []string, operation string, action func(root, jobID, actor string) (jobruntime.JobState, error)) int {
-	arguments = reorderInterspersedFlags(arguments, map[string]bool{"id": true, "root": true, "actor": true})
+func runSimpleJobTransition(arguments []string, operation string, action func(root, jobID string, opts jobruntime.TransitionOptions) (jobruntime.JobState, error)) int {
+	arguments = reorderInterspersedFlags(arguments, map[string]bool{"id": true, "root": true, "actor": true, "credential-ledger": true})
 	flagSet := flag.NewFlagSet("job-"+operation, flag.ContinueOnError)
 	flagSet.SetOutput(io.Discard)
 
 	var jobID string
 	var root string
 	var actor string
+	var credentialLedger string
 	var jsonOutput bool
 	var helpFlag bool
 
 	flagSet.StringVar(&jobID, "id", "", "job identifier")
 	flagSet.StringVar(&root, "root", "./gait-out/jobs", "job state root directory")
 	flagSet.StringVar(&actor, "actor", "", "actor identity")
+	if operation != "pause" {
+		flagSet.StringVar(&credentialLedger, "credential-ledger", "", "credential lease ledger whose job-bound leases are revoked (default $"+credential.LedgerPathEnv+" or "+credential.DefaultLedgerPath+")")
+	}
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
 
@@ -350,11 +441,43 @@ func runSimpleJobTransition(arguments []string, operation string, action func(ro
 	if len(flagSet.Args()) > 0 {
 		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: operation, Error: "unexpected positional arguments"}, exitInvalidInput)
 	}
-	state, err := action(root, strings.TrimSpace(jobID), strings.TrimSpace(actor))
+	leaseRevocation := &jobLeaseRevocation{ledgerPath: credentialLedger}
+	state, err := action(root, strings.TrimSpace(jobID), jobruntime.TransitionOptions{
+		Actor:        strings.TrimSpace(actor),
+		OnStop:       leaseRevocation.onStop,
+		OnTransition: notifyJobTransition,
+	})
 	if err != nil {
 		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: operation, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
-	return writeJobOutput(jsonOutput, jobOutput{OK: true, Operation: operation, JobID: state.JobID, Job: &state}, exitOK)
+	output := jobOutput{OK: true, Operation: operation, JobID: state.JobID, Job: &state, Revocations: leaseRevocation.results}
+	if leaseRevocation.err != nil {
+		output.OK = false
+		output.Error = fmt.Sprintf("job %s committed but credential revocation failed: %v", operation, leaseRevocation.err)
+		return writeJobOutput(jsonOutput, output, exitCodeForError(leaseRevocation.err, exitInvalidInput))
+	}
+	return writeJobOutput(jsonOutput, output, exitOK)
+}
+
+// jobLeaseRevocation revokes the ledger leases bound to a job once the job
+// stops, recording the reason that matches the job's final status.
+type jobLeaseRevocation struct {
+	ledgerPath string
+	results    []credential.RevokeResult
+	err        error
+}
+
+func (r *jobLeaseRevocation) onStop(state jobruntime.JobState) {
+	reason := credential.RevokeReasonJobCancelled
+	switch state.Status {
+	case jobruntime.StatusCompleted:
+		reason = credential.RevokeReasonJobCompleted
+	case jobruntime.StatusEmergencyStop:
+		reason = credential.RevokeReasonJobEmergencyStop
+	}
+	results, err := credential.RevokeLeases(r.ledgerPath, credential.LeaseFilter{JobID: state.JobID}, reason, credential.LedgerRevokeOptions{})
+	r.results = append(r.results, results...)
+	r.err = err
 }
 
 func runJobApprove(arguments []string) int {
@@ -386,7 +509,11 @@ func runJobApprove(arguments []string) int {
 	if len(flagSet.Args()) > 0 {
 		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "approve", Error: "unexpected positional arguments"}, exitInvalidInput)
 	}
-	state, err := jobruntime.Approve(root, strings.TrimSpace(jobID), jobruntime.ApprovalOptions{Actor: strings.TrimSpace(actor), Reason: strings.TrimSpace(reason)})
+	state, err := jobruntime.Approve(root, strings.TrimSpace(jobID), jobruntime.ApprovalOptions{
+		Actor:        strings.TrimSpace(actor),
+		Reason:       strings.TrimSpace(reason),
+		OnTransition: notifyJobTransition,
+	})
 	if err != nil {
 		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "approve", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
@@ -542,6 +669,10 @@ func writeJobOutput(jsonOutput bool, output jobOutput, exitCode int) int {
 	}
 	if output.Job != nil {
 		fmt.Printf("job %s: id=%s status=%s stop_reason=%s reason_code=%s\n", output.Operation, output.Job.JobID, output.Job.Status, output.Job.StopReason, output.Job.StatusReasonCode)
+		if output.Job.Constraints != nil && output.Job.Usage != nil {
+			constraints, usage := output.Job.Constraints, output.Job.Usage
+			fmt.Printf("constraints: tool_calls=%d/%d destructive_ops=%d/%d spend=%g/%g allowed_tools=%s\n", usage.ToolCalls, constraints.MaxToolCalls, usage.DestructiveOps, constraints.MaxDestructiveOps, usage.Spend, constraints.SpendCeiling, strings.Join(constraints.AllowedTools, ","))
+		}
 	}
 	if output.Checkpoint != nil {
 		fmt.Printf("checkpoint: %s (%s)\n", output.Checkpoint.CheckpointID, output.Checkpoint.Type)
@@ -552,27 +683,35 @@ func writeJobOutput(jsonOutput bool, output jobOutput, exitCode int) int {
 	if len(output.Events) > 0 {
 		fmt.Printf("events=%d\n", len(output.Events))
 	}
+	if output.Supervisor != nil {
+		fmt.Printf("supervisor: launches=%d restarts=%d steps=%d elapsed_ms=%d\n", output.Supervisor.Launches, output.Supervisor.Restarts, output.Supervisor.Steps, output.Supervisor.ElapsedMS)
+	}
+	for _, revocation := range output.Revocations {
+		fmt.Printf("credential %s: %s\n", revocation.CredentialRef, revocation.Status)
+	}
 	return exitCode
 }
 
 func printJobUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait job submit --id <job_id> [--root ./gait-out/jobs] [--actor <id>] [--identity <id>] [--policy <policy.yaml>|--policy-digest <sha256>] [--policy-ref <ref>] [--env-fingerprint <value>] [--json] [--explain]")
+	fmt.Println("  gait job submit --id <job_id> [--root ./gait-out/jobs] [--actor <id>] [--identity <id>] [--policy <policy.yaml>|--policy-digest <sha256>] [--policy-ref <ref>] [--env-fingerprint <value>] [--max-tool-calls <n>] [--max-destructive-ops <n>] [--allowed-tools <csv>] [--spend-ceiling <amount>] [--json] [--explain]")
 	fmt.Println("  gait job status --id <job_id> [--root ./gait-out/jobs] [--json] [--explain]")
-	fmt.Println("  gait job checkpoint add --id <job_id> --type <plan|progress|decision-needed|blocked|completed> --summary <text> [--required-action <text>] [--actor <id>] [--root ./gait-out/jobs] [--json] [--explain]")
+	fmt.Println("  gait job checkpoint add --id <job_id> --type <plan|progress|decision-needed|blocked|completed> --summary <text> [--required-action <text>] [--actor <id>] [--credential-broker <vault_dynamic|aws_sts|github_app> --credential-evidence <path,...>] [--credential-ledger <path>] [--root ./gait-out/jobs] [--json] [--explain]")
 	fmt.Println("  gait job checkpoint list --id <job_id> [--root ./gait-out/jobs] [--json] [--explain]")
 	fmt.Println("  gait job checkpoint show --id <job_id> --checkpoint <checkpoint_id> [--root ./gait-out/jobs] [--json] [--explain]")
 	fmt.Println("  gait job pause --id <job_id> [--actor <id>] [--root ./gait-out/jobs] [--json] [--explain]")
-	fmt.Println("  gait job stop --id <job_id> [--actor <id>] [--root ./gait-out/jobs] [--json] [--explain]")
+	fmt.Println("  gait job stop --id <job_id> [--actor <id>] [--credential-ledger <path>] [--root ./gait-out/jobs] [--json] [--explain]")
 	fmt.Println("  gait job approve --id <job_id> --actor <id> [--reason <text>] [--root ./gait-out/jobs] [--json] [--explain]")
 	fmt.Println("  gait job resume --id <job_id> [--actor <id>] [--identity <id>] [--reason <text>] [--policy <policy.yaml>|--policy-digest <sha256>] [--policy-ref <ref>] [--identity-revocations <path>|--identity-revoked] [--identity-validation-source <source>] [--env-fingerprint <value>] [--allow-env-mismatch] [--root ./gait-out/jobs] [--json] [--explain]")
-	fmt.Println("  gait job cancel --id <job_id> [--actor <id>] [--root ./gait-out/jobs] [--json] [--explain]")
+	fmt.Println("  gait job cancel --id <job_id> [--actor <id>] [--credential-ledger <path>] [--root ./gait-out/jobs] [--json] [--explain]")
 	fmt.Println("  gait job inspect --id <job_id> [--root ./gait-out/jobs] [--json] [--explain]")
+	fmt.Println("  gait job run --id <job_id> [--max-duration <dur>] [--max-steps <n>] [--max-restarts <n>] [--stop-grace <dur>] [--control-listen 127.0.0.1:<port>|--control-socket <path>] [--cwd <dir>] [--actor <id>] [--credential-ledger <path>] [--root ./gait-out/jobs] [--json] [--explain] -- <agent command...>")
+	fmt.Println("  gait job constraints --id <job_id> [--max-tool-calls <n>] [--max-destructive-ops <n>] [--allowed-tools <csv>] [--spend-ceiling <amount>] [--actor <id>] [--root ./gait-out/jobs] [--json] [--explain]")
 }
 
 func printJobSubmitUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait job submit --id <job_id> [--root ./gait-out/jobs] [--actor <id>] [--identity <id>] [--policy <policy.yaml>|--policy-digest <sha256>] [--policy-ref <ref>] [--env-fingerprint <value>] [--json] [--explain]")
+	fmt.Println("  gait job submit --id <job_id> [--root ./gait-out/jobs] [--actor <id>] [--identity <id>] [--policy <policy.yaml>|--policy-digest <sha256>] [--policy-ref <ref>] [--env-fingerprint <value>] [--max-tool-calls <n>] [--max-destructive-ops <n>] [--allowed-tools <csv>] [--spend-ceiling <amount>] [--json] [--explain]")
 }
 
 func printJobStatusUsage() {
@@ -589,7 +728,7 @@ func printJobCheckpointUsage() {
 
 func printJobCheckpointAddUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait job checkpoint add --id <job_id> --type <plan|progress|decision-needed|blocked|completed> --summary <text> [--required-action <text>] [--actor <id>] [--root ./gait-out/jobs] [--json] [--explain]")
+	fmt.Println("  gait job checkpoint add --id <job_id> --type <plan|progress|decision-needed|blocked|completed> --summary <text> [--required-action <text>] [--actor <id>] [--credential-broker <vault_dynamic|aws_sts|github_app> --credential-evidence <path,...>] [--credential-ledger <path>] [--root ./gait-out/jobs] [--json] [--explain]")
 }
 
 func printJobCheckpointListUsage() {
@@ -609,7 +748,7 @@ func printJobPauseUsage() {
 
 func printJobStopUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait job stop --id <job_id> [--actor <id>] [--root ./gait-out/jobs] [--json] [--explain]")
+	fmt.Println("  gait job stop --id <job_id> [--actor <id>] [--credential-ledger <path>] [--root ./gait-out/jobs] [--json] [--explain]")
 }
 
 func printJobApproveUsage() {
@@ -624,7 +763,7 @@ func printJobResumeUsage() {
 
 func printJobCancelUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait job cancel --id <job_id> [--actor <id>] [--root ./gait-out/jobs] [--json] [--explain]")
+	fmt.Println("  gait job cancel --id <job_id> [--actor <id>] [--credential-ledger <path>] [--root ./gait-out/jobs] [--json] [--explain]")
 }
 
 func printJobInspectUsage() {
@@ -714,3 +853,40 @@ func identityIsRevoked(revoked map[string]struct{}, identity string) bool {
 	_, ok := revoked[value]
 	return ok
 }
+
+// notifyJobTransition is the jobruntime OnTransition hook shared by the job
+// commands, the supervisor and job constraint enforcement, so every pause,
+// stop, cancel and approval is queued for notification wherever it happens.
+func notifyJobTransition(transition jobruntime.Transition) {
+	kind := ""
+	summary := ""
+	switch transition.Kind {
+	case jobruntime.TransitionPaused:
+		kind, summary = notify.EventJobPaused, "job paused"
+	case jobruntime.TransitionDecisionNeeded:
+		kind, summary = notify.EventJobPaused, "job paused for decision"
+	case jobruntime.TransitionEmergencyStopped:
+		kind, summary = notify.EventJobEmergencyStopped, "job emergency stopped"
+	case jobruntime.TransitionCancelled:
+		kind, summary = notify.EventJobCancelled, "job cancelled"
+	case jobruntime.TransitionApproved:
+		kind, summary = notify.EventJobApproved, "job approved"
+	default:
+		return
+	}
+	state := transition.State
+	details := map[string]string{"status": state.Status, "stop_reason": state.StopReason}
+	if transition.Reason != "" {
+		details["reason"] = transition.Reason
+	}
+	notifyProjectEvent(notify.Event{
+		Kind:        kind,
+		Source:      "job_runtime",
+		Summary:     summary + ": " + state.JobID,
+		JobID:       state.JobID,
+		Identity:    state.Identity,
+		Actor:       transition.Actor,
+		ReasonCodes: []string{state.StatusReasonCode},
+		Details:     details,
+	})
+}
@@ -2,6 +2,9 @@ package main
 
 import (
 	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -9,7 +12,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Clyra-AI/gait/core/gate"
 	"github.com/Clyra-AI/gait/core/jobruntime"
+	sign "github.com/Clyra-AI/proof/signing"
 )
 
 func TestRunJobLifecycleCommands(t *testing.T) {
@@ -116,6 +121,73 @@ func TestRunJobLifecycleCommands(t *testing.T) {
 	}
 }
 
+func TestRunJobCheckpointCompletedRevokesCredentials(t *testing.T) {
+	workDir := t.TempDir()
+	withWorkingDir(t, workDir)
+	root := filepath.Join(workDir, "jobs")
+	jobID := "job_cli_revoke"
+
+	revoked := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPut || request.URL.Path != "/v1/sys/leases/revoke" || request.Header.Get("X-Vault-Token") != "vault-token" {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		raw, _ := io.ReadAll(request.Body)
+		revoked = append(revoked, string(raw))
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "vault-token")
+
+	evidencePaths := []string{}
+	for index, binding := range []string{jobID, "job_other"} {
+		path := filepath.Join(workDir, "credential_"+binding+".json")
+		record := gate.BuildBrokerCredentialRecord(gate.BuildBrokerCredentialRecordOptions{
+			ToolName:         "tool.db_query",
+			Identity:         "alice",
+			Broker:           "vault_dynamic",
+			CredentialSource: "vault_dynamic",
+			CredentialRef:    "vault_dynamic:database/creds/readonly/lease-" + string(rune('a'+index)),
+			JobBinding:       binding,
+		})
+		if err := gate.WriteBrokerCredentialRecord(path, record); err != nil {
+			t.Fatalf("write credential evidence: %v", err)
+		}
+		evidencePaths = append(evidencePaths, path)
+	}
+
+	if code, out := runJobJSON(t, []string{"submit", "--id", jobID, "--root", root, "--actor", "alice", "--json"}); code != exitOK {
+		t.Fatalf("job submit expected %d got %d output=%#v", exitOK, code, out)
+	}
+	progressCode, progressOut := runJobJSON(t, []string{
+		"checkpoint", "add", "--id", jobID, "--root", root, "--type", "progress", "--summary", "working",
+		"--credential-broker", "vault_dynamic", "--credential-evidence", strings.Join(evidencePaths, ","), "--json",
+	})
+	if progressCode != exitOK || len(progressOut.Revocations) != 0 || len(revoked) != 0 {
+		t.Fatalf("progress checkpoint must not revoke credentials: code=%d output=%#v revoked=%#v", progressCode, progressOut, revoked)
+	}
+	completedCode, completedOut := runJobJSON(t, []string{
+		"checkpoint", "add", "--id", jobID, "--root", root, "--type", "completed", "--summary", "done",
+		"--credential-broker", "vault_dynamic", "--credential-evidence", strings.Join(evidencePaths, ","), "--json",
+	})
+	if completedCode != exitOK || completedOut.Job == nil || completedOut.Job.Status != jobruntime.StatusCompleted {
+		t.Fatalf("completed checkpoint expected ok got %d output=%#v", completedCode, completedOut)
+	}
+	if len(completedOut.Revocations) != 1 || completedOut.Revocations[0].Status != "revoked" || completedOut.Revocations[0].CredentialRef != "vault_dynamic:database/creds/readonly/lease-a" {
+		t.Fatalf("unexpected revocations: %#v", completedOut.Revocations)
+	}
+	if len(revoked) != 1 || !strings.Contains(revoked[0], "database/creds/readonly/lease-a") {
+		t.Fatalf("unexpected vault revoke calls: %#v", revoked)
+	}
+
+	missingCode, _ := runJobJSON(t, []string{"checkpoint", "add", "--id", jobID, "--root", root, "--summary", "x", "--credential-evidence", evidencePaths[0], "--json"})
+	if missingCode != exitInvalidInput {
+		t.Fatalf("credential evidence without broker expected %d got %d", exitInvalidInput, missingCode)
+	}
+}
+
 func TestRunJobHelpAndErrorPaths(t *testing.T) {
 	workDir := t.TempDir()
 	withWorkingDir(t, workDir)
@@ -596,6 +668,180 @@ rules:
 	}
 }
 
+func TestGateEvalEnforcesJobConstraints(t *testing.T) {
+	workDir := t.TempDir()
+	withWorkingDir(t, workDir)
+	root := filepath.Join(workDir, "jobs")
+	privateKeyPath := filepath.Join(workDir, "private.key")
+	writePrivateKey(t, privateKeyPath)
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, strings.Join([]string{
+		"default_verdict: block",
+		"rules:",
+		"  - name: allow-write",
+		"    effect: allow",
+		"    cost: 1",
+		"    match:",
+		"      tool_names: [tool.write]",
+	}, "\n")+"\n")
+	intentPath := filepath.Join(workDir, "intent.json")
+	writeIntentFixture(t, intentPath, "tool.write")
+	setIntentJobID(t, intentPath, "job_budget")
+
+	evalGate := func() (int, gateEvalOutput) {
+		t.Helper()
+		var code int
+		raw := captureStdout(t, func() {
+			code = runGateEval([]string{"--policy", policyPath, "--intent", intentPath, "--job-root", root, "--private-key", privateKeyPath, "--key-mode", "prod", "--json"})
+		})
+		var output gateEvalOutput
+		if err := json.Unmarshal([]byte(raw), &output); err != nil {
+			t.Fatalf("decode gate output: %v raw=%q", err, raw)
+		}
+		return code, output
+	}
+
+	if code, out := runJobJSON(t, []string{"submit", "--id", "job_budget", "--root", root, "--max-tool-calls", "1", "--allowed-tools", "tool.write,tool.read", "--json"}); code != exitOK || out.Job.Constraints == nil || out.Job.Constraints.MaxToolCalls != 1 {
+		t.Fatalf("job submit with constraints expected %d got %d output=%#v", exitOK, code, out)
+	}
+	if code, out := evalGate(); code != exitOK || out.Verdict != "allow" {
+		t.Fatalf("first charged call expected allow got %d output=%#v", code, out)
+	}
+	code, out := evalGate()
+	if code != exitPolicyBlocked || !reflect.DeepEqual(out.Violations, []string{"job_constraint_violated"}) || !strings.Contains(strings.Join(out.ReasonCodes, ","), jobruntime.ReasonJobToolCallBudgetExhausted) {
+		t.Fatalf("exhausted budget expected block got %d output=%#v", code, out)
+	}
+	statusCode, statusOut := runJobJSON(t, []string{"status", "--id", "job_budget", "--root", root, "--json"})
+	if statusCode != exitOK || statusOut.Job.Status != jobruntime.StatusDecisionNeeded || statusOut.Job.StopReason != jobruntime.StopReasonJobBudgetExhausted || statusOut.Job.Usage.ToolCalls != 1 {
+		t.Fatalf("expected job paused for decision got %d output=%#v", statusCode, statusOut)
+	}
+
+	if code, out := runJobJSON(t, []string{"constraints", "--id", "job_budget", "--root", root, "--max-tool-calls", "2", "--actor", "alice", "--json"}); code != exitOK || out.Job.Constraints.MaxToolCalls != 2 || len(out.Job.Constraints.AllowedTools) != 2 {
+		t.Fatalf("job constraints update expected %d got %d output=%#v", exitOK, code, out)
+	}
+	if code, out := runJobJSON(t, []string{"approve", "--id", "job_budget", "--root", root, "--actor", "alice", "--json"}); code != exitOK {
+		t.Fatalf("job approve expected %d got %d output=%#v", exitOK, code, out)
+	}
+	if code, out := runJobJSON(t, []string{"resume", "--id", "job_budget", "--root", root, "--json"}); code != exitOK {
+		t.Fatalf("job resume expected %d got %d output=%#v", exitOK, code, out)
+	}
+	if code, out := evalGate(); code != exitOK || out.Verdict != "allow" {
+		t.Fatalf("call after raising budget expected allow got %d output=%#v", code, out)
+	}
+
+	setIntentJobID(t, intentPath, "job_spend")
+	if code, out := runJobJSON(t, []string{"submit", "--id", "job_spend", "--root", root, "--spend-ceiling", "1.5", "--json"}); code != exitOK {
+		t.Fatalf("job submit expected %d got %d output=%#v", exitOK, code, out)
+	}
+	if code, out := evalGate(); code != exitOK || out.Verdict != "allow" {
+		t.Fatalf("first costed call expected allow got %d output=%#v", code, out)
+	}
+	code, out = evalGate()
+	if code != exitPolicyBlocked || !strings.Contains(strings.Join(out.ReasonCodes, ","), jobruntime.ReasonJobSpendCeilingExceeded) {
+		t.Fatalf("spend ceiling expected block got %d output=%#v", code, out)
+	}
+
+	setIntentJobID(t, intentPath, "job_pinned")
+	if code, out := runJobJSON(t, []string{"submit", "--id", "job_pinned", "--root", root, "--policy-digest", strings.Repeat("0", 64), "--json"}); code != exitOK {
+		t.Fatalf("job submit expected %d got %d output=%#v", exitOK, code, out)
+	}
+	code, out = evalGate()
+	if code != exitPolicyBlocked || !strings.Contains(strings.Join(out.ReasonCodes, ","), jobruntime.ReasonJobPolicyDigestMismatch) {
+		t.Fatalf("policy digest mismatch expected block got %d output=%#v", code, out)
+	}
+}
+
+func TestMCPProxyChargesJobAfterApprovalQueue(t *testing.T) {
+	workDir := t.TempDir()
+	root := filepath.Join(workDir, "jobs")
+	queueDir := filepath.Join(workDir, "queue")
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, strings.Join([]string{
+		"default_verdict: allow",
+		"rules:",
+		"  - name: approve-writes",
+		"    effect: require_approval",
+		"    match:",
+		"      tool_names: [tool.write]",
+	}, "\n")+"\n")
+	approvalKeyPair, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	proxy := func(toolName string) mcpProxyOutput {
+		t.Helper()
+		payload := `{"name":"` + toolName + `","args":{"path":"/tmp/out.txt"},"targets":[{"kind":"path","value":"/tmp/out.txt","operation":"write"}],` +
+			`"context":{"identity":"alice","workspace":"/repo/gait","risk_class":"high","session_id":"sess-1","job_id":"job_queue"}}`
+		output, _, err := evaluateMCPProxyPayload(policyPath, []byte(payload), mcpProxyEvalOptions{
+			Adapter:          "mcp",
+			JobRoot:          root,
+			TracePath:        filepath.Join(workDir, "trace_"+toolName+".json"),
+			KeyMode:          "dev",
+			ApprovalQueueDir: queueDir,
+			ApprovalKeyPair:  approvalKeyPair,
+		})
+		if err != nil {
+			t.Fatalf("mcp proxy %s: %v", toolName, err)
+		}
+		return output
+	}
+
+	if code, out := runJobJSON(t, []string{"submit", "--id", "job_queue", "--root", root, "--max-tool-calls", "1", "--json"}); code != exitOK {
+		t.Fatalf("job submit expected %d got %d output=%#v", exitOK, code, out)
+	}
+	if out := proxy("tool.read"); out.Verdict != "allow" {
+		t.Fatalf("first call expected allow, got %#v", out)
+	}
+	pending := proxy("tool.write")
+	if pending.Verdict != "require_approval" || pending.ApprovalRequestID == "" {
+		t.Fatalf("expected queued approval request, got %#v", pending)
+	}
+	if _, err := gate.DecideApprovalRequest(queueDir, gate.DecideApprovalRequestOptions{
+		RequestID:         pending.ApprovalRequestID,
+		Decision:          gate.ApprovalDecisionGrant,
+		ApproverIdentity:  "bob",
+		ReasonCode:        "ticket-1",
+		TTL:               time.Hour,
+		SigningPrivateKey: approvalKeyPair.Private,
+	}); err != nil {
+		t.Fatalf("grant approval request: %v", err)
+	}
+	granted := proxy("tool.write")
+	if granted.Verdict != "block" || !strings.Contains(strings.Join(granted.ReasonCodes, ","), jobruntime.ReasonJobToolCallBudgetExhausted) {
+		t.Fatalf("expected granted call on exhausted job to be blocked, got %#v", granted)
+	}
+}
+
+func TestRunJobRunSupervisesAgentCommand(t *testing.T) {
+	workDir := t.TempDir()
+	withWorkingDir(t, workDir)
+	root := filepath.Join(workDir, "jobs")
+	jobID := "job_cli_run"
+	if code, _ := runJobJSON(t, []string{"submit", "--id", jobID, "--root", root, "--json"}); code != exitOK {
+		t.Fatalf("job submit expected %d got %d", exitOK, code)
+	}
+
+	if code, output := runJobJSON(t, []string{"run", "--id", jobID, "--root", root, "--json"}); code != exitInvalidInput || !strings.Contains(output.Error, "agent command") {
+		t.Fatalf("expected missing agent command error, code=%d output=%#v", code, output)
+	}
+	if code, output := runJobJSON(t, []string{"run", "--id", jobID, "--root", root, "--control-listen", "0.0.0.0:0", "--json", "--", os.Args[0]}); code != exitInvalidInput || !strings.Contains(output.Error, "loopback") {
+		t.Fatalf("expected non-loopback control listener rejection, code=%d output=%#v", code, output)
+	}
+
+	code, output := runJobJSON(t, []string{
+		"run", "--id", jobID, "--root", root,
+		"--control-socket", filepath.Join(workDir, "control.sock"),
+		"--json",
+		"--", os.Args[0], "-test.run=TestWrapperChildProcess",
+	})
+	if code != exitOK {
+		t.Fatalf("job run expected %d got %d output=%#v", exitOK, code, output)
+	}
+	if output.Job == nil || output.Job.Status != jobruntime.StatusCompleted || output.Supervisor == nil || output.Supervisor.Launches != 1 {
+		t.Fatalf("expected clean agent exit to complete the job: %#v", output)
+	}
+}
+
 func runJobJSON(t *testing.T, args []string) (int, jobOutput) {
 	t.Helper()
 	var code int
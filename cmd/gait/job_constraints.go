@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Clyra-AI/gait/core/jobruntime"
+)
+
+func registerJobConstraintFlags(flagSet *flag.FlagSet, constraints *jobruntime.JobConstraints) {
+	flagSet.IntVar(&constraints.MaxToolCalls, "max-tool-calls", 0, "maximum gate-allowed tool calls charged to the job; 0 disables")
+	flagSet.IntVar(&constraints.MaxDestructiveOps, "max-destructive-ops", 0, "maximum destructive tool calls charged to the job; 0 disables")
+	flagSet.Func("allowed-tools", "comma-separated tool names the job may call; empty allows all", func(value string) error {
+		constraints.AllowedTools = parseCSV(value)
+		return nil
+	})
+	flagSet.Float64Var(&constraints.SpendCeiling, "spend-ceiling", 0, "maximum summed policy rule cost charged to the job; 0 disables")
+}
+
+func runJobConstraints(arguments []string) int {
+	arguments = reorderInterspersedFlags(arguments, map[string]bool{
+		"id":                  true,
+		"root":                true,
+		"actor":               true,
+		"max-tool-calls":      true,
+		"max-destructive-ops": true,
+		"allowed-tools":       true,
+		"spend-ceiling":       true,
+	})
+	flagSet := flag.NewFlagSet("job-constraints", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+
+	var jobID string
+	var root string
+	var actor string
+	var requested jobruntime.JobConstraints
+	var jsonOutput bool
+	var helpFlag bool
+
+	flagSet.StringVar(&jobID, "id", "", "job identifier")
+	flagSet.StringVar(&root, "root", "./gait-out/jobs", "job state root directory")
+	flagSet.StringVar(&actor, "actor", "", "actor identity")
+	registerJobConstraintFlags(flagSet, &requested)
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	flagSet.BoolVar(&helpFlag, "help", false, "show help")
+
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "constraints", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	if helpFlag {
+		printJobConstraintsUsage()
+		return exitOK
+	}
+	if len(flagSet.Args()) > 0 {
+		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "constraints", Error: "unexpected positional arguments"}, exitInvalidInput)
+	}
+	trimmedJobID := strings.TrimSpace(jobID)
+	state, err := jobruntime.Status(root, trimmedJobID)
+	if err != nil {
+		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "constraints", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+
+	// Only the limits named on the command line change; the rest are kept.
+	updated := jobruntime.JobConstraints{}
+	if state.Constraints != nil {
+		updated = *state.Constraints
+	}
+	changed := false
+	flagSet.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "max-tool-calls":
+			updated.MaxToolCalls = requested.MaxToolCalls
+		case "max-destructive-ops":
+			updated.MaxDestructiveOps = requested.MaxDestructiveOps
+		case "allowed-tools":
+			updated.AllowedTools = requested.AllowedTools
+		case "spend-ceiling":
+			updated.SpendCeiling = requested.SpendCeiling
+		default:
+			return
+		}
+		changed = true
+	})
+	if changed {
+		state, err = jobruntime.UpdateConstraints(root, trimmedJobID, jobruntime.ConstraintOptions{
+			Constraints: updated,
+			Actor:       strings.TrimSpace(actor),
+		})
+		if err != nil {
+			return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "constraints", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+		}
+	}
+	return writeJobOutput(jsonOutput, jobOutput{OK: true, Operation: "constraints", JobID: state.JobID, Job: &state}, exitOK)
+}
+
+func printJobConstraintsUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  gait job constraints --id <job_id> [--max-tool-calls <n>] [--max-destructive-ops <n>] [--allowed-tools <csv>] [--spend-ceiling <amount>] [--actor <id>] [--root ./gait-out/jobs] [--json] [--explain]")
+}
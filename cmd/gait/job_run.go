@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/credential"
+	"github.com/Clyra-AI/gait/core/jobruntime"
+	"github.com/Clyra-AI/gait/core/projectconfig"
+)
+
+func runJobRun(arguments []string) int {
+	agentCommand := []string{}
+	for index, argument := range arguments {
+		if argument == "--" {
+			agentCommand = append(agentCommand, arguments[index+1:]...)
+			arguments = arguments[:index]
+			break
+		}
+	}
+	arguments = reorderInterspersedFlags(arguments, map[string]bool{
+		"id":                true,
+		"root":              true,
+		"actor":             true,
+		"cwd":               true,
+		"max-duration":      true,
+		"max-steps":         true,
+		"max-restarts":      true,
+		"stop-grace":        true,
+		"control-listen":    true,
+		"control-socket":    true,
+		"credential-ledger": true,
+	})
+	flagSet := flag.NewFlagSet("job-run", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+
+	var jobID string
+	var root string
+	var actor string
+	var cwd string
+	var maxDuration string
+	var maxSteps int
+	var maxRestarts int
+	var stopGrace string
+	var controlListen string
+	var controlSocket string
+	var credentialLedger string
+	var jsonOutput bool
+	var helpFlag bool
+
+	flagSet.StringVar(&jobID, "id", "", "job identifier")
+	flagSet.StringVar(&root, "root", "./gait-out/jobs", "job state root directory")
+	flagSet.StringVar(&actor, "actor", "", "actor identity recorded on supervisor transitions")
+	flagSet.StringVar(&cwd, "cwd", "", "working directory for the agent command")
+	flagSet.StringVar(&maxDuration, "max-duration", "", "wall-clock budget for this run (for example 4h); 0 or empty disables")
+	flagSet.IntVar(&maxSteps, "max-steps", 0, "pause once the job has this many checkpoints; 0 disables")
+	flagSet.IntVar(&maxRestarts, "max-restarts", 3, "consecutive agent failures restarted before the job is blocked")
+	flagSet.StringVar(&stopGrace, "stop-grace", "10s", "time between SIGTERM and SIGKILL when stopping the agent")
+	flagSet.StringVar(&controlListen, "control-listen", "", "loopback host:port serving the control API")
+	flagSet.StringVar(&controlSocket, "control-socket", "", "unix socket path serving the control API")
+	flagSet.StringVar(&credentialLedger, "credential-ledger", "", "credential lease ledger whose job-bound leases are revoked (default $"+credential.LedgerPathEnv+" or "+credential.DefaultLedgerPath+")")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	flagSet.BoolVar(&helpFlag, "help", false, "show help")
+
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "run", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	if helpFlag {
+		printJobRunUsage()
+		return exitOK
+	}
+	if len(flagSet.Args()) > 0 {
+		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "run", Error: "unexpected positional arguments (agent command goes after --)"}, exitInvalidInput)
+	}
+	if len(agentCommand) == 0 {
+		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "run", Error: "expected agent command after --"}, exitInvalidInput)
+	}
+	wallClockBudget, err := parseJobRunDuration("--max-duration", maxDuration)
+	if err != nil {
+		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "run", Error: err.Error()}, exitInvalidInput)
+	}
+	stopGracePeriod, err := parseJobRunDuration("--stop-grace", stopGrace)
+	if err != nil {
+		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "run", Error: err.Error()}, exitInvalidInput)
+	}
+	if controlListen != "" && controlSocket != "" {
+		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "run", Error: "--control-listen and --control-socket are mutually exclusive"}, exitInvalidInput)
+	}
+
+	trimmedJobID := strings.TrimSpace(jobID)
+	trimmedActor := strings.TrimSpace(actor)
+	agentStdout := io.Writer(os.Stdout)
+	if jsonOutput {
+		agentStdout = os.Stderr
+	}
+	leaseRevocation := &jobLeaseRevocation{ledgerPath: credentialLedger}
+	supervisor, err := jobruntime.NewSupervisor(jobruntime.SupervisorOptions{
+		Root:            root,
+		JobID:           trimmedJobID,
+		Command:         agentCommand,
+		Dir:             strings.TrimSpace(cwd),
+		Stdout:          agentStdout,
+		Stderr:          os.Stderr,
+		WallClockBudget: wallClockBudget,
+		MaxSteps:        maxSteps,
+		MaxRestarts:     maxRestarts,
+		StopGracePeriod: stopGracePeriod,
+		Actor:           trimmedActor,
+		OnStop:          leaseRevocation.onStop,
+		OnTransition:    notifyJobTransition,
+	})
+	if err != nil {
+		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "run", JobID: trimmedJobID, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	if _, err := jobruntime.Status(root, trimmedJobID); err != nil {
+		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "run", JobID: trimmedJobID, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+
+	listener, controlAddress, err := listenJobControl(controlListen, controlSocket)
+	if err != nil {
+		return writeJobOutput(jsonOutput, jobOutput{OK: false, Operation: "run", JobID: trimmedJobID, Error: err.Error()}, exitInvalidInput)
+	}
+	if listener != nil {
+		server := &http.Server{Handler: supervisor.ControlHandler(), ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			_ = server.Serve(listener)
+		}()
+		defer func() {
+			_ = server.Close()
+			if controlSocket != "" {
+				_ = os.Remove(controlSocket)
+			}
+		}()
+	}
+
+	notifier, err := loadProjectNotifier(projectconfig.DefaultPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "notify warning: %v\n", err)
+	}
+	defer startNotifyFlushLoop(notifier)()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Fprintf(os.Stderr, "job run: id=%s command=%s control=%s\n", trimmedJobID, agentCommand[0], controlAddress)
+	status, err := supervisor.Run(ctx)
+	output := jobOutput{OK: err == nil, Operation: "run", JobID: trimmedJobID, Supervisor: &status, Revocations: leaseRevocation.results}
+	if state, statusErr := jobruntime.Status(root, trimmedJobID); statusErr == nil {
+		output.Job = &state
+	}
+	if err != nil {
+		output.Error = err.Error()
+		return writeJobOutput(jsonOutput, output, exitCodeForError(err, exitInternalFailure))
+	}
+	if leaseRevocation.err != nil {
+		output.OK = false
+		output.Error = fmt.Sprintf("job %s but credential revocation failed: %v", status.Status, leaseRevocation.err)
+		return writeJobOutput(jsonOutput, output, exitCodeForError(leaseRevocation.err, exitInvalidInput))
+	}
+	return writeJobOutput(jsonOutput, output, exitOK)
+}
+
+func parseJobRunDuration(name string, value string) (time.Duration, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" || trimmed == "0" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(trimmed)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s: %w", name, err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("%s must be >= 0", name)
+	}
+	return duration, nil
+}
+
+// listenJobControl opens the control API listener. The API is
+// unauthenticated, so TCP listeners must be loopback and sockets are owner-only.
+func listenJobControl(listenAddr string, socketPath string) (net.Listener, string, error) {
+	switch {
+	case strings.TrimSpace(listenAddr) != "":
+		isLoopback, err := mcpServeIsLoopbackListen(listenAddr)
+		if err != nil || !isLoopback {
+			return nil, "", fmt.Errorf("--control-listen must be a loopback host:port")
+		}
+		listener, err := net.Listen("tcp", strings.TrimSpace(listenAddr))
+		if err != nil {
+			return nil, "", fmt.Errorf("listen control api: %w", err)
+		}
+		return listener, "http://" + listener.Addr().String(), nil
+	case strings.TrimSpace(socketPath) != "":
+		trimmed := strings.TrimSpace(socketPath)
+		if info, err := os.Lstat(trimmed); err == nil {
+			if info.Mode()&os.ModeSocket == 0 {
+				return nil, "", fmt.Errorf("--control-socket %s exists and is not a socket", trimmed)
+			}
+			if err := os.Remove(trimmed); err != nil {
+				return nil, "", fmt.Errorf("remove stale control socket: %w", err)
+			}
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, "", fmt.Errorf("stat control socket: %w", err)
+		}
+		listener, err := net.Listen("unix", trimmed)
+		if err != nil {
+			return nil, "", fmt.Errorf("listen control socket: %w", err)
+		}
+		if err := os.Chmod(trimmed, 0o600); err != nil {
+			_ = listener.Close()
+			return nil, "", fmt.Errorf("restrict control socket: %w", err)
+		}
+		return listener, "unix://" + trimmed, nil
+	default:
+		return nil, "none", nil
+	}
+}
+
+func printJobRunUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  gait job run --id <job_id> [--max-duration <dur>] [--max-steps <n>] [--max-restarts <n>] [--stop-grace <dur>] [--control-listen 127.0.0.1:<port>|--control-socket <path>] [--cwd <dir>] [--actor <id>] [--credential-ledger <path>] [--root ./gait-out/jobs] [--json] [--explain] -- <agent command...>")
+}
@@ -10,16 +10,29 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Clyra-AI/gait/core/trust"
 	sign "github.com/Clyra-AI/proof/signing"
 )
 
 type keysInitOutput struct {
-	OK             bool   `json:"ok"`
-	Prefix         string `json:"prefix,omitempty"`
-	KeyID          string `json:"key_id,omitempty"`
-	PublicKeyPath  string `json:"public_key_path,omitempty"`
-	PrivateKeyPath string `json:"private_key_path,omitempty"`
-	Error          string `json:"error,omitempty"`
+	OK             bool     `json:"ok"`
+	Prefix         string   `json:"prefix,omitempty"`
+	KeyID          string   `json:"key_id,omitempty"`
+	PublicKeyPath  string   `json:"public_key_path,omitempty"`
+	PrivateKeyPath string   `json:"private_key_path,omitempty"`
+	TrustStore     string   `json:"trust_store,omitempty"`
+	Retired        []string `json:"retired,omitempty"`
+	Error          string   `json:"error,omitempty"`
+}
+
+type keysTrustOutput struct {
+	OK         bool        `json:"ok"`
+	Operation  string      `json:"operation,omitempty"`
+	TrustStore string      `json:"trust_store,omitempty"`
+	Key        *trust.Key  `json:"key,omitempty"`
+	Keys       []trust.Key `json:"keys,omitempty"`
+	Retired    []string    `json:"retired,omitempty"`
+	Error      string      `json:"error,omitempty"`
 }
 
 type keysVerifyOutput struct {
@@ -49,6 +62,8 @@ func runKeys(arguments []string) int {
 		return runKeysRotate(arguments[1:])
 	case "verify":
 		return runKeysVerify(arguments[1:])
+	case "trust":
+		return runKeysTrust(arguments[1:])
 	default:
 		printKeysUsage()
 		return exitInvalidInput
@@ -99,11 +114,14 @@ func runKeysInit(arguments []string) int {
 
 func runKeysRotate(arguments []string) int {
 	if hasExplainFlag(arguments) {
-		return writeExplain("Rotate signing keys by generating a timestamped keypair while keeping previous keys intact.")
+		return writeExplain("Rotate signing keys by generating a timestamped keypair while keeping previous keys intact; with --trust-store the new key is trusted and prior keys for the same roles are retired, not revoked.")
 	}
 	arguments = reorderInterspersedFlags(arguments, map[string]bool{
-		"out-dir": true,
-		"prefix":  true,
+		"out-dir":     true,
+		"prefix":      true,
+		"trust-store": true,
+		"roles":       true,
+		"identity":    true,
 	})
 
 	flagSet := flag.NewFlagSet("keys-rotate", flag.ContinueOnError)
@@ -111,11 +129,17 @@ func runKeysRotate(arguments []string) int {
 
 	var outDir string
 	var prefix string
+	var trustStorePath string
+	var rolesCSV string
+	var identity string
 	var jsonOutput bool
 	var helpFlag bool
 
 	flagSet.StringVar(&outDir, "out-dir", filepath.Join("gait-out", "keys"), "directory for rotated key files")
 	flagSet.StringVar(&prefix, "prefix", "gait", "key file prefix")
+	flagSet.StringVar(&trustStorePath, "trust-store", "", "trust store to add the rotated key to (optional)")
+	flagSet.StringVar(&rolesCSV, "roles", trust.RoleTraceSigner, "comma-separated trust store roles for the rotated key")
+	flagSet.StringVar(&identity, "identity", "", "identity the rotated key is bound to (approver, delegator or publisher)")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
 
@@ -130,11 +154,30 @@ func runKeysRotate(arguments []string) int {
 		return writeKeysInitOutput(jsonOutput, keysInitOutput{OK: false, Error: "unexpected positional arguments"}, exitInvalidInput)
 	}
 
-	rotatedPrefix := fmt.Sprintf("%s_%s", strings.TrimSpace(prefix), time.Now().UTC().Format("20060102T150405Z"))
+	now := time.Now().UTC()
+	rotatedPrefix := fmt.Sprintf("%s_%s", strings.TrimSpace(prefix), now.Format("20060102T150405Z"))
 	result, err := createSigningKeypair(outDir, rotatedPrefix, false)
 	if err != nil {
 		return writeKeysInitOutput(jsonOutput, keysInitOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
+	if strings.TrimSpace(trustStorePath) != "" {
+		publicKey, err := sign.LoadVerifyKey(sign.KeyConfig{PublicKeyPath: result.PublicKeyPath})
+		if err != nil {
+			return writeKeysInitOutput(jsonOutput, keysInitOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+		}
+		store, err := trust.LoadOrNew(trustStorePath)
+		if err != nil {
+			return writeKeysInitOutput(jsonOutput, keysInitOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+		}
+		if _, err := store.Add(publicKey, trust.Key{Roles: parseCSV(rolesCSV), Identity: identity}); err != nil {
+			return writeKeysInitOutput(jsonOutput, keysInitOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+		}
+		result.Retired = store.Retire(result.KeyID, now)
+		if err := trust.Write(trustStorePath, store, now); err != nil {
+			return writeKeysInitOutput(jsonOutput, keysInitOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+		}
+		result.TrustStore = strings.TrimSpace(trustStorePath)
+	}
 	return writeKeysInitOutput(jsonOutput, result, exitOK)
 }
 
@@ -199,6 +242,210 @@ func runKeysVerify(arguments []string) int {
 	}, exitOK)
 }
 
+func runKeysTrust(arguments []string) int {
+	if hasExplainFlag(arguments) {
+		return writeExplain("Manage the trust store that maps signing key ids to public keys, roles, validity windows and revocation for artifact verification.")
+	}
+	if len(arguments) == 0 {
+		printKeysTrustUsage()
+		return exitInvalidInput
+	}
+	switch arguments[0] {
+	case "add":
+		return runKeysTrustAdd(arguments[1:])
+	case "revoke":
+		return runKeysTrustRevoke(arguments[1:])
+	case "list":
+		return runKeysTrustList(arguments[1:])
+	default:
+		printKeysTrustUsage()
+		return exitInvalidInput
+	}
+}
+
+func runKeysTrustAdd(arguments []string) int {
+	arguments = reorderInterspersedFlags(arguments, map[string]bool{
+		"store":          true,
+		"public-key":     true,
+		"public-key-env": true,
+		"roles":          true,
+		"identity":       true,
+		"not-before":     true,
+		"not-after":      true,
+	})
+	flagSet := flag.NewFlagSet("keys-trust-add", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+
+	var storePath string
+	var publicKeyPath string
+	var publicKeyEnv string
+	var rolesCSV string
+	var identity string
+	var notBeforeValue string
+	var notAfterValue string
+	var jsonOutput bool
+	var helpFlag bool
+
+	flagSet.StringVar(&storePath, "store", "", "trust store path (default $"+trust.StorePathEnv+")")
+	flagSet.StringVar(&publicKeyPath, "public-key", "", "path to base64 public key")
+	flagSet.StringVar(&publicKeyEnv, "public-key-env", "", "env var containing base64 public key")
+	flagSet.StringVar(&rolesCSV, "roles", "", "comma-separated roles: trace_signer, approver, delegator, registry_publisher")
+	flagSet.StringVar(&identity, "identity", "", "identity the key is bound to (optional)")
+	flagSet.StringVar(&notBeforeValue, "not-before", "", "earliest signing time the key is trusted for (RFC3339)")
+	flagSet.StringVar(&notAfterValue, "not-after", "", "latest signing time the key is trusted for (RFC3339)")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	flagSet.BoolVar(&helpFlag, "help", false, "show help")
+
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "add", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	if helpFlag {
+		printKeysTrustUsage()
+		return exitOK
+	}
+	if len(flagSet.Args()) > 0 {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "add", Error: "unexpected positional arguments"}, exitInvalidInput)
+	}
+	resolvedStore := trust.ResolveStorePath(storePath)
+	if resolvedStore == "" {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "add", Error: "--store is required"}, exitInvalidInput)
+	}
+	if strings.TrimSpace(publicKeyPath) == "" && strings.TrimSpace(publicKeyEnv) == "" {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "add", Error: "public key source is required (--public-key or --public-key-env)"}, exitInvalidInput)
+	}
+	key := trust.Key{Roles: parseCSV(rolesCSV), Identity: identity}
+	var err error
+	if key.NotBefore, err = parseOptionalRFC3339("not-before", notBeforeValue); err != nil {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "add", Error: err.Error()}, exitInvalidInput)
+	}
+	if key.NotAfter, err = parseOptionalRFC3339("not-after", notAfterValue); err != nil {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "add", Error: err.Error()}, exitInvalidInput)
+	}
+	publicKey, err := sign.LoadVerifyKey(sign.KeyConfig{PublicKeyPath: publicKeyPath, PublicKeyEnv: publicKeyEnv})
+	if err != nil {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "add", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	store, err := trust.LoadOrNew(resolvedStore)
+	if err != nil {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "add", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	added, err := store.Add(publicKey, key)
+	if err != nil {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "add", Error: err.Error()}, exitInvalidInput)
+	}
+	if err := trust.Write(resolvedStore, store, time.Now().UTC()); err != nil {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "add", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: true, Operation: "add", TrustStore: resolvedStore, Key: &added}, exitOK)
+}
+
+func runKeysTrustRevoke(arguments []string) int {
+	arguments = reorderInterspersedFlags(arguments, map[string]bool{
+		"store":  true,
+		"key-id": true,
+		"reason": true,
+	})
+	flagSet := flag.NewFlagSet("keys-trust-revoke", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+
+	var storePath string
+	var keyID string
+	var reason string
+	var jsonOutput bool
+	var helpFlag bool
+
+	flagSet.StringVar(&storePath, "store", "", "trust store path (default $"+trust.StorePathEnv+")")
+	flagSet.StringVar(&keyID, "key-id", "", "key id to revoke")
+	flagSet.StringVar(&reason, "reason", "", "revocation reason")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	flagSet.BoolVar(&helpFlag, "help", false, "show help")
+
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "revoke", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	if helpFlag {
+		printKeysTrustUsage()
+		return exitOK
+	}
+	if len(flagSet.Args()) > 0 {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "revoke", Error: "unexpected positional arguments"}, exitInvalidInput)
+	}
+	resolvedStore := trust.ResolveStorePath(storePath)
+	if resolvedStore == "" || strings.TrimSpace(keyID) == "" {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "revoke", Error: "--store and --key-id are required"}, exitInvalidInput)
+	}
+	store, err := trust.Load(resolvedStore)
+	if err != nil {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "revoke", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	now := time.Now().UTC()
+	revoked, err := store.Revoke(keyID, now, reason)
+	if err != nil {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "revoke", Error: err.Error()}, exitInvalidInput)
+	}
+	if err := trust.Write(resolvedStore, store, now); err != nil {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "revoke", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: true, Operation: "revoke", TrustStore: resolvedStore, Key: &revoked}, exitOK)
+}
+
+func runKeysTrustList(arguments []string) int {
+	arguments = reorderInterspersedFlags(arguments, map[string]bool{"store": true})
+	flagSet := flag.NewFlagSet("keys-trust-list", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+
+	var storePath string
+	var jsonOutput bool
+	var helpFlag bool
+
+	flagSet.StringVar(&storePath, "store", "", "trust store path (default $"+trust.StorePathEnv+")")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	flagSet.BoolVar(&helpFlag, "help", false, "show help")
+
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "list", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	if helpFlag {
+		printKeysTrustUsage()
+		return exitOK
+	}
+	if len(flagSet.Args()) > 0 {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "list", Error: "unexpected positional arguments"}, exitInvalidInput)
+	}
+	resolvedStore := trust.ResolveStorePath(storePath)
+	if resolvedStore == "" {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "list", Error: "--store is required"}, exitInvalidInput)
+	}
+	store, err := trust.Load(resolvedStore)
+	if err != nil {
+		return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: false, Operation: "list", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	return writeKeysTrustOutput(jsonOutput, keysTrustOutput{OK: true, Operation: "list", TrustStore: resolvedStore, Keys: store.Keys}, exitOK)
+}
+
+// loadTrustStore loads the trust store named by path or $GAIT_TRUST_STORE.
+// It returns nil when neither is set so callers fall back to a single key.
+func loadTrustStore(path string) (*trust.Store, error) {
+	resolved := trust.ResolveStorePath(path)
+	if resolved == "" {
+		return nil, nil
+	}
+	return trust.Load(resolved)
+}
+
+func parseOptionalRFC3339(name string, value string) (*time.Time, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("--%s must be RFC3339: %w", name, err)
+	}
+	parsed = parsed.UTC()
+	return &parsed, nil
+}
+
 func createSigningKeypair(outDir string, prefix string, force bool) (keysInitOutput, error) {
 	trimmedOutDir := strings.TrimSpace(outDir)
 	if trimmedOutDir == "" {
@@ -265,12 +512,47 @@ func writeKeysInitOutput(jsonOutput bool, output keysInitOutput, exitCode int) i
 	}
 	if output.OK {
 		fmt.Printf("keys init ok: key_id=%s public=%s private=%s\n", output.KeyID, output.PublicKeyPath, output.PrivateKeyPath)
+		if output.TrustStore != "" {
+			fmt.Printf("trust store updated: %s retired=%s\n", output.TrustStore, strings.Join(output.Retired, ","))
+		}
 		return exitCode
 	}
 	fmt.Printf("keys init error: %s\n", output.Error)
 	return exitCode
 }
 
+func writeKeysTrustOutput(jsonOutput bool, output keysTrustOutput, exitCode int) int {
+	if jsonOutput {
+		return writeJSONOutput(output, exitCode)
+	}
+	if !output.OK {
+		fmt.Printf("keys trust %s error: %s\n", output.Operation, output.Error)
+		return exitCode
+	}
+	keys := output.Keys
+	if output.Key != nil {
+		keys = []trust.Key{*output.Key}
+	}
+	fmt.Printf("keys trust %s ok: store=%s\n", output.Operation, output.TrustStore)
+	for _, key := range keys {
+		line := fmt.Sprintf("  %s roles=%s", key.KeyID, strings.Join(key.Roles, ","))
+		if key.Identity != "" {
+			line += " identity=" + key.Identity
+		}
+		if key.NotBefore != nil {
+			line += " not_before=" + key.NotBefore.UTC().Format(time.RFC3339)
+		}
+		if key.NotAfter != nil {
+			line += " not_after=" + key.NotAfter.UTC().Format(time.RFC3339)
+		}
+		if key.RevokedAt != nil {
+			line += " REVOKED " + key.RevokedAt.UTC().Format(time.RFC3339)
+		}
+		fmt.Println(line)
+	}
+	return exitCode
+}
+
 func writeKeysVerifyOutput(jsonOutput bool, output keysVerifyOutput, exitCode int) int {
 	if jsonOutput {
 		return writeJSONOutput(output, exitCode)
@@ -286,8 +568,11 @@ func writeKeysVerifyOutput(jsonOutput bool, output keysVerifyOutput, exitCode in
 func printKeysUsage() {
 	fmt.Println("Usage:")
 	fmt.Println("  gait keys init [--out-dir gait-out/keys] [--prefix gait] [--force] [--json] [--explain]")
-	fmt.Println("  gait keys rotate [--out-dir gait-out/keys] [--prefix gait] [--json] [--explain]")
+	fmt.Println("  gait keys rotate [--out-dir gait-out/keys] [--prefix gait] [--trust-store <trust_store.json> [--roles <csv>] [--identity <id>]] [--json] [--explain]")
 	fmt.Println("  gait keys verify [--private-key <path>|--private-key-env <VAR>] [--public-key <path>|--public-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait keys trust add --store <trust_store.json> --public-key <path>|--public-key-env <VAR> --roles <csv> [--identity <id>] [--not-before <rfc3339>] [--not-after <rfc3339>] [--json]")
+	fmt.Println("  gait keys trust revoke --store <trust_store.json> --key-id <key_id> [--reason <text>] [--json]")
+	fmt.Println("  gait keys trust list --store <trust_store.json> [--json]")
 }
 
 func printKeysInitUsage() {
@@ -297,10 +582,17 @@ func printKeysInitUsage() {
 
 func printKeysRotateUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait keys rotate [--out-dir gait-out/keys] [--prefix gait] [--json] [--explain]")
+	fmt.Println("  gait keys rotate [--out-dir gait-out/keys] [--prefix gait] [--trust-store <trust_store.json> [--roles <csv>] [--identity <id>]] [--json] [--explain]")
 }
 
 func printKeysVerifyUsage() {
 	fmt.Println("Usage:")
 	fmt.Println("  gait keys verify [--private-key <path>|--private-key-env <VAR>] [--public-key <path>|--public-key-env <VAR>] [--json] [--explain]")
 }
+
+func printKeysTrustUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  gait keys trust add --store <trust_store.json> --public-key <path>|--public-key-env <VAR> --roles <csv> [--identity <id>] [--not-before <rfc3339>] [--not-after <rfc3339>] [--json]")
+	fmt.Println("  gait keys trust revoke --store <trust_store.json> --key-id <key_id> [--reason <text>] [--json]")
+	fmt.Println("  gait keys trust list --store <trust_store.json> [--json]")
+}
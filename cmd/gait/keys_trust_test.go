@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKeysRotateWithTrustStoreKeepsHistoricalTracesVerifiable(t *testing.T) {
+	workDir := t.TempDir()
+	withWorkingDir(t, workDir)
+	keysDir := filepath.Join(workDir, "keys")
+	storePath := filepath.Join(workDir, "trust_store.json")
+
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, "default_verdict: allow\n")
+	intentPath := filepath.Join(workDir, "intent.json")
+	writeIntentFixture(t, intentPath, "tool.write")
+
+	initCode, initOut := runKeysJSON(t, []string{"init", "--out-dir", keysDir, "--json"})
+	if initCode != exitOK {
+		t.Fatalf("keys init expected %d got %d output=%#v", exitOK, initCode, initOut)
+	}
+	oldTrace := filepath.Join(workDir, "trace_old.json")
+	if code := runGateEval([]string{"--policy", policyPath, "--intent", intentPath, "--trace-out", oldTrace, "--key-mode", "prod", "--private-key", initOut.PrivateKeyPath, "--json"}); code != exitOK {
+		t.Fatalf("gate eval with original key expected %d got %d", exitOK, code)
+	}
+	if code := runKeys([]string{"trust", "add", "--store", storePath, "--public-key", initOut.PublicKeyPath, "--roles", "trace_signer", "--json"}); code != exitOK {
+		t.Fatalf("keys trust add expected %d got %d", exitOK, code)
+	}
+
+	rotateCode, rotateOut := runKeysJSON(t, []string{"rotate", "--out-dir", keysDir, "--trust-store", storePath, "--roles", "trace_signer", "--json"})
+	if rotateCode != exitOK || len(rotateOut.Retired) != 1 || rotateOut.Retired[0] != initOut.KeyID {
+		t.Fatalf("keys rotate expected original key retired got %d output=%#v", rotateCode, rotateOut)
+	}
+	newTrace := filepath.Join(workDir, "trace_new.json")
+	if code := runGateEval([]string{"--policy", policyPath, "--intent", intentPath, "--trace-out", newTrace, "--key-mode", "prod", "--private-key", rotateOut.PrivateKeyPath, "--json"}); code != exitOK {
+		t.Fatalf("gate eval with rotated key expected %d got %d", exitOK, code)
+	}
+
+	for _, tracePath := range []string{oldTrace, newTrace} {
+		if code := runTraceVerify([]string{"--path", tracePath, "--trust-store", storePath, "--json"}); code != exitOK {
+			t.Fatalf("trace verify %s with trust store expected %d got %d", filepath.Base(tracePath), exitOK, code)
+		}
+	}
+
+	if code := runKeys([]string{"trust", "revoke", "--store", storePath, "--key-id", initOut.KeyID, "--reason", "compromised", "--json"}); code != exitOK {
+		t.Fatalf("keys trust revoke expected %d got %d", exitOK, code)
+	}
+	var verifyCode int
+	raw := captureStdout(t, func() {
+		verifyCode = runTraceVerify([]string{"--path", oldTrace, "--trust-store", storePath, "--json"})
+	})
+	if verifyCode != exitVerifyFailed || !strings.Contains(raw, "revoked") {
+		t.Fatalf("trace verify with revoked key expected %d got %d output=%s", exitVerifyFailed, verifyCode, raw)
+	}
+	if code := runTraceVerify([]string{"--path", newTrace, "--trust-store", storePath, "--json"}); code != exitOK {
+		t.Fatalf("trace verify with rotated key after revocation expected %d got %d", exitOK, code)
+	}
+}
+
+func runKeysJSON(t *testing.T, args []string) (int, keysInitOutput) {
+	t.Helper()
+	var code int
+	raw := captureStdout(t, func() {
+		code = runKeys(args)
+	})
+	var output keysInitOutput
+	if err := json.Unmarshal([]byte(raw), &output); err != nil {
+		t.Fatalf("decode keys output: %v raw=%q", err, raw)
+	}
+	return code, output
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"crypto/ed25519"
 	"errors"
 	"flag"
 	"fmt"
@@ -10,17 +12,33 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Clyra-AI/gait/core/credential"
 	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/notify"
 	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	sign "github.com/Clyra-AI/proof/signing"
 )
 
+const killSwitchSourceTimeout = 10 * time.Second
+
 type killSwitchOutput struct {
-	OK      bool                         `json:"ok"`
-	Action  string                       `json:"action,omitempty"`
-	State   *schemagate.KillSwitchState  `json:"state,omitempty"`
-	Entry   *schemagate.KillSwitchEntry  `json:"entry,omitempty"`
-	Entries []schemagate.KillSwitchEntry `json:"entries,omitempty"`
-	Error   string                       `json:"error,omitempty"`
+	OK                bool                         `json:"ok"`
+	Action            string                       `json:"action,omitempty"`
+	State             *schemagate.KillSwitchState  `json:"state,omitempty"`
+	Entry             *schemagate.KillSwitchEntry  `json:"entry,omitempty"`
+	Entries           []schemagate.KillSwitchEntry `json:"entries,omitempty"`
+	SignatureVerified bool                         `json:"signature_verified,omitempty"`
+	Revocations       []credential.RevokeResult    `json:"revocations,omitempty"`
+	Error             string                       `json:"error,omitempty"`
+}
+
+// killSwitchSourceFlags configure how Gate and MCP surfaces read
+// --kill-switch-state when it is signed or served from a distribution point.
+type killSwitchSourceFlags struct {
+	PublicKeyPath string
+	PublicKeyEnv  string
+	CachePath     string
+	MaxStale      time.Duration
 }
 
 func runKillSwitch(arguments []string) int {
@@ -32,7 +50,7 @@ func runKillSwitch(arguments []string) int {
 		return exitInvalidInput
 	}
 	switch arguments[0] {
-	case "add":
+	case "add", "engage":
 		return runKillSwitchAdd(arguments[1:])
 	case "list":
 		return runKillSwitchList(arguments[1:])
@@ -62,6 +80,9 @@ func runKillSwitchAdd(arguments []string) int {
 	var reason string
 	var actor string
 	var expiresAtText string
+	var privateKeyPath string
+	var privateKeyEnv string
+	var credentialLedger string
 	var jsonOutput bool
 	flagSet.StringVar(&statePath, "state", "./.gait-out/kill_switch_state.json", "path to kill-switch state JSON")
 	flagSet.StringVar(&entryID, "entry-id", "", "entry identifier override")
@@ -76,6 +97,9 @@ func runKillSwitchAdd(arguments []string) int {
 	flagSet.StringVar(&reason, "reason", "", "operator-visible reason")
 	flagSet.StringVar(&actor, "actor", "", "actor recording the entry")
 	flagSet.StringVar(&expiresAtText, "expires-at", "", "optional RFC3339 expiry time")
+	flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private key used to sign the state")
+	flagSet.StringVar(&privateKeyEnv, "private-key-env", "", "env var containing base64 private key used to sign the state")
+	flagSet.StringVar(&credentialLedger, "credential-ledger", "", "credential lease ledger whose matching leases are revoked (default $"+credential.LedgerPathEnv+" or "+credential.DefaultLedgerPath+")")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	if err := flagSet.Parse(arguments); err != nil {
 		return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: false, Error: err.Error()}, exitInvalidInput)
@@ -111,20 +135,58 @@ func runKillSwitchAdd(arguments []string) int {
 	if err != nil {
 		return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: false, Error: err.Error()}, exitInvalidInput)
 	}
+	privateKey, err := loadActionContractPrivateKey(strings.TrimSpace(privateKeyPath), strings.TrimSpace(privateKeyEnv))
+	if err != nil {
+		return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
 	state.Entries = append(state.Entries, normalizedEntry)
-	state.UpdatedAt = now
-	if err := gate.WriteKillSwitchState(statePath, state); err != nil {
+	state, err = writeKillSwitchRevision(statePath, state, now, privateKey)
+	if err != nil {
 		return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: false, Error: err.Error()}, exitInvalidInput)
 	}
-	return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: true, Action: "add", Entry: &normalizedEntry, State: &state}, exitOK)
+	notifyProjectEvent(notify.Event{
+		Kind:     notify.EventKillSwitchEngaged,
+		Source:   "kill_switch",
+		Summary:  "kill switch engaged: " + normalizedEntry.EntryID,
+		ToolName: normalizedEntry.ToolName,
+		Identity: normalizedEntry.Identity,
+		Actor:    normalizedEntry.Actor,
+		Details: map[string]string{
+			"entry_id": normalizedEntry.EntryID,
+			"agent_id": normalizedEntry.AgentID,
+			"reason":   normalizedEntry.Reason,
+		},
+	})
+	// Leases only carry identity and tool name. An entry scoped purely by
+	// agent, environment, target or path cannot be matched against them, so
+	// it revokes nothing rather than every outstanding lease.
+	var revocations []credential.RevokeResult
+	var revokeErr error
+	if normalizedEntry.Identity != "" || normalizedEntry.ToolName != "" {
+		revocations, revokeErr = credential.RevokeLeases(credentialLedger, credential.LeaseFilter{
+			Identity: normalizedEntry.Identity,
+			ToolName: normalizedEntry.ToolName,
+		}, credential.RevokeReasonKillSwitch, credential.LedgerRevokeOptions{Now: now})
+	}
+	output := killSwitchOutput{OK: true, Action: "add", Entry: &normalizedEntry, State: &state, Revocations: revocations}
+	if revokeErr != nil {
+		output.OK = false
+		output.Error = "kill switch engaged but credential revocation failed: " + revokeErr.Error()
+		return writeKillSwitchOutput(jsonOutput, output, exitInvalidInput)
+	}
+	return writeKillSwitchOutput(jsonOutput, output, exitOK)
 }
 
 func runKillSwitchList(arguments []string) int {
 	flagSet := flag.NewFlagSet("kill-switch-list", flag.ContinueOnError)
 	flagSet.SetOutput(io.Discard)
 	var statePath string
+	var publicKeyPath string
+	var publicKeyEnv string
 	var jsonOutput bool
 	flagSet.StringVar(&statePath, "state", "./.gait-out/kill_switch_state.json", "path to kill-switch state JSON")
+	flagSet.StringVar(&publicKeyPath, "public-key", "", "path to base64 public key used to verify the state signature")
+	flagSet.StringVar(&publicKeyEnv, "public-key-env", "", "env var containing base64 public key used to verify the state signature")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	if err := flagSet.Parse(arguments); err != nil {
 		return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: false, Error: err.Error()}, exitInvalidInput)
@@ -133,7 +195,18 @@ func runKillSwitchList(arguments []string) int {
 	if err != nil {
 		return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: false, Error: err.Error()}, exitInvalidInput)
 	}
-	return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: true, Action: "list", State: &state, Entries: append([]schemagate.KillSwitchEntry(nil), state.Entries...)}, exitOK)
+	verified := false
+	if strings.TrimSpace(publicKeyPath) != "" || strings.TrimSpace(publicKeyEnv) != "" {
+		publicKey, err := loadActionContractPublicKey(strings.TrimSpace(publicKeyPath), strings.TrimSpace(publicKeyEnv))
+		if err != nil {
+			return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+		}
+		if err := gate.VerifyKillSwitchState(state, publicKey); err != nil {
+			return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: false, Action: "list", State: &state, Error: err.Error()}, exitVerifyFailed)
+		}
+		verified = true
+	}
+	return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: true, Action: "list", State: &state, Entries: append([]schemagate.KillSwitchEntry(nil), state.Entries...), SignatureVerified: verified}, exitOK)
 }
 
 func runKillSwitchDisable(arguments []string) int {
@@ -148,10 +221,14 @@ func runKillSwitchExpire(arguments []string) int {
 	var statePath string
 	var entryID string
 	var expiresAtText string
+	var privateKeyPath string
+	var privateKeyEnv string
 	var jsonOutput bool
 	flagSet.StringVar(&statePath, "state", "./.gait-out/kill_switch_state.json", "path to kill-switch state JSON")
 	flagSet.StringVar(&entryID, "entry-id", "", "entry id to expire")
 	flagSet.StringVar(&expiresAtText, "expires-at", "", "optional RFC3339 expiry time (default now)")
+	flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private key used to sign the state")
+	flagSet.StringVar(&privateKeyEnv, "private-key-env", "", "env var containing base64 private key used to sign the state")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	if err := flagSet.Parse(arguments); err != nil {
 		return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: false, Error: err.Error()}, exitInvalidInput)
@@ -168,7 +245,11 @@ func runKillSwitchExpire(arguments []string) int {
 		}
 		expiresAt = parsed.UTC()
 	}
-	state, entry, err := mutateKillSwitchState(strings.TrimSpace(statePath), now, strings.TrimSpace(entryID), func(candidate *schemagate.KillSwitchEntry, _ time.Time) {
+	privateKey, err := loadActionContractPrivateKey(strings.TrimSpace(privateKeyPath), strings.TrimSpace(privateKeyEnv))
+	if err != nil {
+		return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
+	state, entry, err := mutateKillSwitchState(strings.TrimSpace(statePath), now, strings.TrimSpace(entryID), privateKey, func(candidate *schemagate.KillSwitchEntry, _ time.Time) {
 		candidate.ExpiresAt = expiresAt
 	})
 	if err != nil {
@@ -182,9 +263,13 @@ func mutateKillSwitchEntry(arguments []string, action string, mutate func(entry
 	flagSet.SetOutput(io.Discard)
 	var statePath string
 	var entryID string
+	var privateKeyPath string
+	var privateKeyEnv string
 	var jsonOutput bool
 	flagSet.StringVar(&statePath, "state", "./.gait-out/kill_switch_state.json", "path to kill-switch state JSON")
 	flagSet.StringVar(&entryID, "entry-id", "", "entry id to mutate")
+	flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private key used to sign the state")
+	flagSet.StringVar(&privateKeyEnv, "private-key-env", "", "env var containing base64 private key used to sign the state")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	if err := flagSet.Parse(arguments); err != nil {
 		return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: false, Error: err.Error()}, exitInvalidInput)
@@ -192,15 +277,19 @@ func mutateKillSwitchEntry(arguments []string, action string, mutate func(entry
 	if strings.TrimSpace(entryID) == "" {
 		return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: false, Error: "--entry-id is required"}, exitInvalidInput)
 	}
+	privateKey, err := loadActionContractPrivateKey(strings.TrimSpace(privateKeyPath), strings.TrimSpace(privateKeyEnv))
+	if err != nil {
+		return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
 	now := time.Now().UTC()
-	state, entry, err := mutateKillSwitchState(strings.TrimSpace(statePath), now, strings.TrimSpace(entryID), mutate)
+	state, entry, err := mutateKillSwitchState(strings.TrimSpace(statePath), now, strings.TrimSpace(entryID), privateKey, mutate)
 	if err != nil {
 		return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: false, Error: err.Error()}, exitInvalidInput)
 	}
 	return writeKillSwitchOutput(jsonOutput, killSwitchOutput{OK: true, Action: action, State: &state, Entry: entry}, exitOK)
 }
 
-func mutateKillSwitchState(statePath string, now time.Time, entryID string, mutate func(entry *schemagate.KillSwitchEntry, now time.Time)) (schemagate.KillSwitchState, *schemagate.KillSwitchEntry, error) {
+func mutateKillSwitchState(statePath string, now time.Time, entryID string, privateKey ed25519.PrivateKey, mutate func(entry *schemagate.KillSwitchEntry, now time.Time)) (schemagate.KillSwitchState, *schemagate.KillSwitchEntry, error) {
 	state, err := gate.LoadKillSwitchState(statePath)
 	if err != nil {
 		return schemagate.KillSwitchState{}, nil, err
@@ -210,16 +299,73 @@ func mutateKillSwitchState(statePath string, now time.Time, entryID string, muta
 			continue
 		}
 		mutate(&state.Entries[index], now)
-		state.UpdatedAt = now
-		if err := gate.WriteKillSwitchState(statePath, state); err != nil {
+		entry := state.Entries[index]
+		state, err = writeKillSwitchRevision(statePath, state, now, privateKey)
+		if err != nil {
 			return schemagate.KillSwitchState{}, nil, err
 		}
-		entry := state.Entries[index]
 		return state, &entry, nil
 	}
 	return schemagate.KillSwitchState{}, nil, fmt.Errorf("kill switch entry not found: %s", entryID)
 }
 
+// writeKillSwitchRevision bumps the state revision and writes it, signing when
+// a key is supplied. A signed state is never replaced by an unsigned one.
+func writeKillSwitchRevision(statePath string, state schemagate.KillSwitchState, now time.Time, privateKey ed25519.PrivateKey) (schemagate.KillSwitchState, error) {
+	if state.Signature != nil && len(privateKey) == 0 {
+		return schemagate.KillSwitchState{}, fmt.Errorf("kill switch state is signed; --private-key or --private-key-env is required")
+	}
+	state.Revision++
+	state.UpdatedAt = now
+	state.Signature = nil
+	if len(privateKey) > 0 {
+		signed, err := gate.SignKillSwitchState(state, privateKey)
+		if err != nil {
+			return schemagate.KillSwitchState{}, err
+		}
+		state = signed
+	}
+	if err := gate.WriteKillSwitchState(statePath, state); err != nil {
+		return schemagate.KillSwitchState{}, err
+	}
+	return gate.LoadKillSwitchState(statePath)
+}
+
+// loadKillSwitchStateSource reads --kill-switch-state from a local path or
+// distribution URL, verifying its signature when a public key is configured.
+func loadKillSwitchStateSource(source string, flags killSwitchSourceFlags) (schemagate.KillSwitchState, []string, error) {
+	var publicKey ed25519.PublicKey
+	if strings.TrimSpace(flags.PublicKeyPath) != "" || strings.TrimSpace(flags.PublicKeyEnv) != "" {
+		loaded, err := sign.LoadVerifyKey(sign.KeyConfig{
+			PublicKeyPath: strings.TrimSpace(flags.PublicKeyPath),
+			PublicKeyEnv:  strings.TrimSpace(flags.PublicKeyEnv),
+		})
+		if err != nil {
+			return schemagate.KillSwitchState{}, nil, fmt.Errorf("load kill switch verify key: %w", err)
+		}
+		publicKey = loaded
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), killSwitchSourceTimeout)
+	defer cancel()
+	result, err := gate.FetchKillSwitchState(ctx, gate.KillSwitchFetchOptions{
+		Source:       strings.TrimSpace(source),
+		PublicKey:    publicKey,
+		CachePath:    strings.TrimSpace(flags.CachePath),
+		MaxStaleness: flags.MaxStale,
+	})
+	if err != nil {
+		return schemagate.KillSwitchState{}, nil, err
+	}
+	return result.State, result.Warnings, nil
+}
+
+func bindKillSwitchSourceFlags(flagSet *flag.FlagSet, flags *killSwitchSourceFlags) {
+	flagSet.StringVar(&flags.PublicKeyPath, "kill-switch-public-key", "", "path to base64 public key that verifies signed kill-switch state")
+	flagSet.StringVar(&flags.PublicKeyEnv, "kill-switch-public-key-env", "", "env var containing base64 public key that verifies signed kill-switch state")
+	flagSet.StringVar(&flags.CachePath, "kill-switch-cache", "", "path to cache the last verified kill-switch state; required for http(s) sources")
+	flagSet.DurationVar(&flags.MaxStale, "kill-switch-max-stale", 15*time.Minute, "how long the cached kill-switch state may be used while the source is unavailable")
+}
+
 func loadOrCreateKillSwitchState(statePath string, now time.Time) (schemagate.KillSwitchState, error) {
 	state, err := gate.LoadKillSwitchState(statePath)
 	if err == nil {
@@ -251,8 +397,8 @@ func writeKillSwitchOutput(jsonOutput bool, output killSwitchOutput, exitCode in
 
 func printKillSwitchUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait kill-switch add --state <path> [--entry-id <id>] [--agent-id <id>] [--identity <id>] [--tool-name <name>] [--target-kind <kind>] [--target-value <value>] [--environment <env>] [--path-prefixes <csv>] [--workspace-prefixes <csv>] [--reason <text>] [--actor <id>] [--expires-at <rfc3339>] [--json]")
-	fmt.Println("  gait kill-switch list --state <path> [--json]")
-	fmt.Println("  gait kill-switch disable --state <path> --entry-id <id> [--json]")
-	fmt.Println("  gait kill-switch expire --state <path> --entry-id <id> [--expires-at <rfc3339>] [--json]")
+	fmt.Println("  gait kill-switch add|engage --state <path> [--entry-id <id>] [--agent-id <id>] [--identity <id>] [--tool-name <name>] [--target-kind <kind>] [--target-value <value>] [--environment <env>] [--path-prefixes <csv>] [--workspace-prefixes <csv>] [--reason <text>] [--actor <id>] [--expires-at <rfc3339>] [--private-key <path>|--private-key-env <VAR>] [--credential-ledger <path>] [--json]")
+	fmt.Println("  gait kill-switch list --state <path> [--public-key <path>|--public-key-env <VAR>] [--json]")
+	fmt.Println("  gait kill-switch disable --state <path> --entry-id <id> [--private-key <path>|--private-key-env <VAR>] [--json]")
+	fmt.Println("  gait kill-switch expire --state <path> --entry-id <id> [--expires-at <rfc3339>] [--private-key <path>|--private-key-env <VAR>] [--json]")
 }
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,6 +13,7 @@ import (
 
 	"github.com/Clyra-AI/gait/core/gate"
 	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	sign "github.com/Clyra-AI/proof/signing"
 )
 
 func TestRunKillSwitchAddListDisableExpire(t *testing.T) {
@@ -229,3 +233,84 @@ func TestRunKillSwitchUsageAndTextOutput(t *testing.T) {
 		t.Fatalf("unexpected text kill-switch output: %q", text)
 	}
 }
+
+func TestRunKillSwitchSignedStateDistributedOverHTTP(t *testing.T) {
+	workDir := t.TempDir()
+	withWorkingDir(t, workDir)
+	keyPair, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	privateKeyPath := filepath.Join(workDir, "kill_switch_private.key")
+	publicKeyPath := filepath.Join(workDir, "kill_switch_public.key")
+	mustWriteFile(t, privateKeyPath, base64.StdEncoding.EncodeToString(keyPair.Private)+"\n")
+	mustWriteFile(t, publicKeyPath, base64.StdEncoding.EncodeToString(keyPair.Public)+"\n")
+	statePath := filepath.Join(workDir, "kill_switch_state.json")
+
+	var engageOut killSwitchOutput
+	engageRaw := captureStdout(t, func() {
+		if code := runKillSwitch([]string{"engage", "--state", statePath, "--tool-name", "tool.exec", "--private-key", privateKeyPath, "--json"}); code != exitOK {
+			t.Fatalf("engage expected %d got %d", exitOK, code)
+		}
+	})
+	if err := json.Unmarshal([]byte(engageRaw), &engageOut); err != nil || engageOut.State == nil || engageOut.State.Revision != 1 || engageOut.State.Signature == nil {
+		t.Fatalf("unexpected engage output %s: %v", engageRaw, err)
+	}
+	if code := runKillSwitch([]string{"disable", "--state", statePath, "--entry-id", engageOut.Entry.EntryID, "--json"}); code != exitInvalidInput {
+		t.Fatalf("expected unsigned rewrite of signed state to fail with %d got %d", exitInvalidInput, code)
+	}
+	var listOut killSwitchOutput
+	listRaw := captureStdout(t, func() {
+		if code := runKillSwitch([]string{"list", "--state", statePath, "--public-key", publicKeyPath, "--json"}); code != exitOK {
+			t.Fatalf("list expected %d got %d", exitOK, code)
+		}
+	})
+	if err := json.Unmarshal([]byte(listRaw), &listOut); err != nil || !listOut.SignatureVerified {
+		t.Fatalf("expected verified list output %s: %v", listRaw, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		payload, err := os.ReadFile(statePath)
+		if err != nil {
+			writer.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = writer.Write(payload)
+	}))
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, "default_verdict: allow\n")
+	intentPath := filepath.Join(workDir, "intent.json")
+	writeIntentFixture(t, intentPath, "tool.exec")
+	cachePath := filepath.Join(workDir, "replica", "kill_switch_cache.json")
+	evalArgs := []string{
+		"--policy", policyPath,
+		"--intent", intentPath,
+		"--kill-switch-state", server.URL + "/kill_switch_state.json",
+		"--kill-switch-public-key", publicKeyPath,
+		"--kill-switch-cache", cachePath,
+		"--json",
+	}
+	var output gateEvalOutput
+	raw := captureStdout(t, func() {
+		if code := runGateEval(evalArgs); code != exitPolicyBlocked {
+			t.Fatalf("gate eval with remote kill switch expected %d got %d", exitPolicyBlocked, code)
+		}
+	})
+	if err := json.Unmarshal([]byte(raw), &output); err != nil || output.KillSwitch == nil || output.KillSwitch.Status != "active" {
+		t.Fatalf("expected active remote kill switch, got %s: %v", raw, err)
+	}
+	if _, err := os.Stat(gate.KillSwitchJournalPath(cachePath)); err != nil {
+		t.Fatalf("expected journal next to cache: %v", err)
+	}
+
+	server.Close()
+	raw = captureStdout(t, func() {
+		if code := runGateEval(append(evalArgs, "--kill-switch-max-stale", "0s")); code != exitPolicyBlocked {
+			t.Fatalf("gate eval with unreachable stale source expected %d got %d", exitPolicyBlocked, code)
+		}
+	})
+	output = gateEvalOutput{}
+	if err := json.Unmarshal([]byte(raw), &output); err != nil || output.KillSwitch == nil || output.KillSwitch.Status != "unavailable" {
+		t.Fatalf("expected fail-closed unavailable kill switch, got %s: %v", raw, err)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunLogRecordsPacksAndVerifiesInclusionOffline(t *testing.T) {
+	workDir := t.TempDir()
+	withWorkingDir(t, workDir)
+	t.Setenv(transparencyLogEnv, filepath.Join(workDir, "transparency"))
+	keyPath := filepath.Join(workDir, "log.key")
+	writePrivateKey(t, keyPath)
+
+	if code := runDemo(nil); code != exitOK {
+		t.Fatalf("demo expected %d got %d", exitOK, code)
+	}
+	buildCode, buildOut := runPackJSON(t, []string{"build", "--type", "run", "--from", "run_demo", "--private-key", keyPath, "--json"})
+	if buildCode != exitOK {
+		t.Fatalf("pack build expected %d got %d output=%#v", exitOK, buildCode, buildOut)
+	}
+
+	proofPath := filepath.Join(workDir, "proof.json")
+	proveCode, proveOut := runLogJSON(t, []string{"prove", "--artifact", buildOut.Path, "--private-key", keyPath, "--out", proofPath, "--json"})
+	if proveCode != exitOK || proveOut.InclusionProof == nil || proveOut.InclusionProof.TreeHead.Signature == nil || proveOut.InclusionProof.Entry.Kind != "pack" {
+		t.Fatalf("log prove expected signed proof for the built pack got %d output=%#v", proveCode, proveOut)
+	}
+
+	verifyCode, verifyOut := runPackJSON(t, []string{"verify", buildOut.Path, "--inclusion-proof", proofPath, "--private-key", keyPath, "--require-signature", "--json"})
+	if verifyCode != exitOK || verifyOut.Verify == nil || verifyOut.Verify.Inclusion == nil || verifyOut.Verify.Inclusion.Status != "verified" {
+		t.Fatalf("pack verify with inclusion proof expected ok got %d output=%#v", verifyCode, verifyOut)
+	}
+
+	logVerifyCode, logVerifyOut := runLogJSON(t, []string{"verify", "--json"})
+	if logVerifyCode != exitOK || logVerifyOut.Verify == nil || logVerifyOut.Verify.TreeSize < 1 || logVerifyOut.Verify.HeadsChecked != 1 {
+		t.Fatalf("log verify expected ok got %d output=%#v", logVerifyCode, logVerifyOut)
+	}
+	consistencyCode, consistencyOut := runLogJSON(t, []string{"consistency", "--old-size", "1", "--json"})
+	if consistencyCode != exitOK || consistencyOut.ConsistencyProof == nil {
+		t.Fatalf("log consistency expected ok got %d output=%#v", consistencyCode, consistencyOut)
+	}
+
+	mismatchCode, mismatchOut := runLogJSON(t, []string{"verify", "--proof", proofPath, "--digest", strings.Repeat("0", 64), "--json"})
+	if mismatchCode != exitVerifyFailed || mismatchOut.Inclusion == nil || mismatchOut.Inclusion.Status != "failed" {
+		t.Fatalf("log verify with mismatched digest expected %d got %d output=%#v", exitVerifyFailed, mismatchCode, mismatchOut)
+	}
+}
+
+func runLogJSON(t *testing.T, args []string) (int, logOutput) {
+	t.Helper()
+	var code int
+	raw := captureStdout(t, func() {
+		code = runLog(args)
+	})
+	var output logOutput
+	if err := json.Unmarshal([]byte(raw), &output); err != nil {
+		t.Fatalf("decode log output: %v raw=%q", err, raw)
+	}
+	return code, output
+}
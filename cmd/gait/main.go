@@ -82,6 +82,8 @@ func runDispatch(arguments []string) int {
 		return runCheck(arguments[2:])
 	case "contract":
 		return runActionContract(arguments[2:])
+	case "credential":
+		return runCredential(arguments[2:])
 	case "delegate":
 		return runDelegate(arguments[2:])
 	case "demo":
@@ -96,8 +98,14 @@ func runDispatch(arguments []string) int {
 		return runGate(arguments[2:])
 	case "kill-switch":
 		return runKillSwitch(arguments[2:])
+	case "log":
+		return runLog(arguments[2:])
 	case "init":
 		return runInit(arguments[2:])
+	case "notify":
+		return runNotify(arguments[2:])
+	case "otel":
+		return runOTel(arguments[2:])
 	case "policy":
 		return runPolicy(arguments[2:])
 	case "keys":
@@ -185,7 +193,7 @@ func normalizeAdoptionCommand(arguments []string) string {
 		return "version"
 	case "--explain":
 		return "explain"
-	case "approve-script", "capture", "check", "contract", "enforce", "gate", "init", "keys", "list-scripts", "policy", "regress", "run", "job", "pack", "report", "scout", "guard", "incident", "registry", "gateway", "mcp", "voice", "doctor", "delegate", "test", "ui":
+	case "approve-script", "capture", "check", "contract", "credential", "enforce", "gate", "init", "keys", "list-scripts", "notify", "otel", "policy", "regress", "run", "job", "pack", "report", "scout", "guard", "incident", "log", "registry", "gateway", "mcp", "voice", "doctor", "delegate", "test", "ui":
 		if len(arguments) > 2 {
 			subcommand := strings.TrimSpace(arguments[2])
 			if subcommand != "" && !strings.HasPrefix(subcommand, "-") {
@@ -1884,6 +1884,14 @@ func TestScoutGuardRegistryAndReduceFlow(t *testing.T) {
 	if code := runGuardVerify([]string{packPath, "--json"}); code != exitOK {
 		t.Fatalf("runGuardVerify: expected %d got %d", exitOK, code)
 	}
+	templatePath := filepath.Join(workDir, "controls.yaml")
+	mustWriteFile(t, templatePath, "schema_id: gait.guard.control_template\nschema_version: 1.0.0\ntemplate_id: nist-800-53\nversion: r5\ncontrols:\n  - id: AU-2\n    title: Event Logging\n    required: true\n    path_matches: [credential_evidence_]\n")
+	if code := runGuardPack([]string{"--run", runpackPath, "--template", templatePath, "--out", filepath.Join(workDir, "gap_pack.zip"), "--json"}); code != exitVerifyFailed {
+		t.Fatalf("runGuardPack required gap: expected %d got %d", exitVerifyFailed, code)
+	}
+	if code := runGuardPack([]string{"--run", runpackPath, "--template", "iso-unknown", "--json"}); code != exitInvalidInput {
+		t.Fatalf("runGuardPack unknown template: expected %d got %d", exitInvalidInput, code)
+	}
 
 	keyPair, err := sign.GenerateKeyPair()
 	if err != nil {
@@ -2916,6 +2924,71 @@ func TestPolicyValidateFmtAndMatchedRuleOutput(t *testing.T) {
 	}
 }
 
+func TestPolicyValidateAndFmtWithExtends(t *testing.T) {
+	workDir := t.TempDir()
+	withWorkingDir(t, workDir)
+
+	mustWriteFile(t, filepath.Join(workDir, "baseline.yaml"), strings.Join([]string{
+		"default_verdict: block",
+		"rules:",
+		"  - name: block-secrets",
+		"    priority: 10",
+		"    effect: block",
+		"    locked: true",
+		"    match:",
+		"      tool_names: [tool.secrets]",
+	}, "\n")+"\n")
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, strings.Join([]string{
+		"extends: [baseline.yaml]",
+		"rules:",
+		"  - name: allow-read",
+		"    priority: 20",
+		"    effect: allow",
+		"    match:",
+		"      tool_names: [tool.read]",
+	}, "\n")+"\n")
+
+	var validateCode int
+	validateRaw := captureStdout(t, func() {
+		validateCode = runPolicyValidate([]string{"--json", policyPath})
+	})
+	if validateCode != exitOK {
+		t.Fatalf("runPolicyValidate: expected %d got %d raw=%s", exitOK, validateCode, validateRaw)
+	}
+	var validateOutput policyValidateOutput
+	if err := json.Unmarshal([]byte(validateRaw), &validateOutput); err != nil {
+		t.Fatalf("decode policy validate output: %v", err)
+	}
+	if validateOutput.RuleCount != 2 || len(validateOutput.Sources) != 2 {
+		t.Fatalf("expected resolved bundle in validate output, got %#v", validateOutput)
+	}
+	if validateOutput.Rules[0].Name != "block-secrets" || validateOutput.Rules[0].Source != "baseline.yaml" || !validateOutput.Rules[0].Locked {
+		t.Fatalf("expected rule provenance in validate output, got %#v", validateOutput.Rules)
+	}
+	validateText := captureStdout(t, func() {
+		validateCode = runPolicyValidate([]string{policyPath})
+	})
+	if validateCode != exitOK || !strings.Contains(validateText, "rule block-secrets priority=10 effect=block source=baseline.yaml locked") {
+		t.Fatalf("unexpected policy validate text output: %s", validateText)
+	}
+
+	var fmtCode int
+	fmtRaw := captureStdout(t, func() {
+		fmtCode = runPolicyFmt([]string{"--json", policyPath})
+	})
+	if fmtCode != exitOK {
+		t.Fatalf("runPolicyFmt: expected %d got %d raw=%s", exitOK, fmtCode, fmtRaw)
+	}
+	var fmtOutput policyFmtOutput
+	if err := json.Unmarshal([]byte(fmtRaw), &fmtOutput); err != nil {
+		t.Fatalf("decode policy fmt output: %v", err)
+	}
+	if fmtOutput.PolicyDigest != validateOutput.PolicyDigest || !strings.Contains(fmtOutput.Formatted, "extends:") || strings.Contains(fmtOutput.Formatted, "block-secrets") {
+		t.Fatalf("expected fmt to keep extends unresolved and report bundle digest, got %#v", fmtOutput)
+	}
+}
+
 func TestPolicyTestEqualPriorityRenamesDoNotChangeVerdict(t *testing.T) {
 	workDir := t.TempDir()
 	withWorkingDir(t, workDir)
@@ -11,11 +11,15 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Clyra-AI/gait/core/authn"
 	"github.com/Clyra-AI/gait/core/contextproof"
+	"github.com/Clyra-AI/gait/core/enforce"
 	"github.com/Clyra-AI/gait/core/gate"
-	"github.com/Clyra-AI/gait/core/jobruntime"
 	"github.com/Clyra-AI/gait/core/mcp"
+	"github.com/Clyra-AI/gait/core/notify"
+	"github.com/Clyra-AI/gait/core/otlp"
 	"github.com/Clyra-AI/gait/core/pack"
+	"github.com/Clyra-AI/gait/core/projectconfig"
 	"github.com/Clyra-AI/gait/core/runpack"
 	schemacommon "github.com/Clyra-AI/gait/core/schema/v1/common"
 	schemacontext "github.com/Clyra-AI/gait/core/schema/v1/context"
@@ -51,6 +55,8 @@ type mcpProxyOutput struct {
 	OTelExport        string                             `json:"otel_export,omitempty"`
 	KillSwitch        *schemagate.KillSwitchDecision     `json:"kill_switch,omitempty"`
 	MCPTrust          *schemagate.MCPTrustDecision       `json:"mcp_trust,omitempty"`
+	ApprovalRequestID string                             `json:"approval_request_id,omitempty"`
+	ApprovalStatus    string                             `json:"approval_request_status,omitempty"`
 	Warnings          []string                           `json:"warnings,omitempty"`
 	Relationship      *schemacommon.RelationshipEnvelope `json:"relationship,omitempty"`
 	Error             string                             `json:"error,omitempty"`
@@ -74,6 +80,7 @@ type mcpProxyEvalOptions struct {
 	Profile                     string
 	JobRoot                     string
 	KillSwitchStatePath         string
+	KillSwitchSource            killSwitchSourceFlags
 	RunID                       string
 	ContextEnvelopePath         string
 	VerifiedContextEnvelope     *schemacontext.Envelope
@@ -88,6 +95,14 @@ type mcpProxyEvalOptions struct {
 	PrivateKeyEnv               string
 	AllowLocalContextArtifacts  bool
 	AllowPayloadContextEnvelope bool
+	ToolAnnotations             *mcp.ToolAnnotationSnapshot
+	RateLimitStore              gate.RateLimitStore
+	ApprovalQueueDir            string
+	ApprovalKeyPair             sign.KeyPair
+	Notifier                    *notify.Notifier
+	Telemetry                   *otlp.Exporter
+	Principal                   authn.Principal
+	IdentityBinding             string
 }
 
 func runMCP(arguments []string) int {
@@ -107,6 +122,10 @@ func runMCP(arguments []string) int {
 		return runMCPVerify(arguments[1:])
 	case "serve":
 		return runMCPServe(arguments[1:])
+	case "relay":
+		return runMCPRelay(arguments[1:])
+	case "annotations":
+		return runMCPAnnotations(arguments[1:])
 	default:
 		printMCPUsage()
 		return exitInvalidInput
@@ -118,22 +137,32 @@ func runMCPProxy(arguments []string) int {
 		return writeExplain("Decode an MCP or adapter-formatted tool call, evaluate policy deterministically, and emit a signed gate-compatible trace.")
 	}
 	arguments = reorderInterspersedFlags(arguments, map[string]bool{
-		"policy":            true,
-		"call":              true,
-		"context-envelope":  true,
-		"adapter":           true,
-		"profile":           true,
-		"job-root":          true,
-		"kill-switch-state": true,
-		"trace-out":         true,
-		"run-id":            true,
-		"runpack-out":       true,
-		"pack-out":          true,
-		"export-log-out":    true,
-		"export-otel-out":   true,
-		"key-mode":          true,
-		"private-key":       true,
-		"private-key-env":   true,
+		"policy":                          true,
+		"call":                            true,
+		"context-envelope":                true,
+		"adapter":                         true,
+		"profile":                         true,
+		"job-root":                        true,
+		"kill-switch-state":               true,
+		"kill-switch-public-key":          true,
+		"kill-switch-public-key-env":      true,
+		"kill-switch-cache":               true,
+		"kill-switch-max-stale":           true,
+		"trace-out":                       true,
+		"run-id":                          true,
+		"runpack-out":                     true,
+		"pack-out":                        true,
+		"export-log-out":                  true,
+		"export-otel-out":                 true,
+		"key-mode":                        true,
+		"private-key":                     true,
+		"private-key-env":                 true,
+		"tool-annotations":                true,
+		"tool-annotations-public-key":     true,
+		"tool-annotations-public-key-env": true,
+		"rate-limit-state":                true,
+		"rate-limit-url":                  true,
+		"rate-limit-token-env":            true,
 	})
 	flagSet := flag.NewFlagSet("mcp-proxy", flag.ContinueOnError)
 	flagSet.SetOutput(io.Discard)
@@ -145,6 +174,7 @@ func runMCPProxy(arguments []string) int {
 	var profile string
 	var jobRoot string
 	var killSwitchStatePath string
+	var killSwitchSource killSwitchSourceFlags
 	var tracePath string
 	var runID string
 	var runpackOut string
@@ -154,6 +184,12 @@ func runMCPProxy(arguments []string) int {
 	var keyMode string
 	var privateKeyPath string
 	var privateKeyEnv string
+	var toolAnnotationsPath string
+	var toolAnnotationsPublicKey string
+	var toolAnnotationsPublicKeyEnv string
+	var rateLimitState string
+	var rateLimitURL string
+	var rateLimitTokenEnv string
 	var jsonOutput bool
 	var helpFlag bool
 
@@ -162,8 +198,9 @@ func runMCPProxy(arguments []string) int {
 	flagSet.StringVar(&contextEnvelopePath, "context-envelope", "", "path to verified context evidence envelope JSON")
 	flagSet.StringVar(&adapter, "adapter", "mcp", "adapter payload format: mcp|openai|anthropic|langchain|claude_code")
 	flagSet.StringVar(&profile, "profile", string(gateProfileStandard), "runtime profile: standard|oss-prod")
-	flagSet.StringVar(&jobRoot, "job-root", "./gait-out/jobs", "job runtime root for emergency stop preemption checks when context.job_id is present")
-	flagSet.StringVar(&killSwitchStatePath, "kill-switch-state", "", "path to generalized kill-switch state JSON")
+	flagSet.StringVar(&jobRoot, "job-root", "./gait-out/jobs", "job runtime root for emergency stop preemption and job constraint checks when context.job_id is present")
+	flagSet.StringVar(&killSwitchStatePath, "kill-switch-state", "", "path or http(s) URL of generalized kill-switch state JSON")
+	bindKillSwitchSourceFlags(flagSet, &killSwitchSource)
 	flagSet.StringVar(&tracePath, "trace-out", "", "path to emitted trace JSON (default trace_<trace_id>.json)")
 	flagSet.StringVar(&runID, "run-id", "", "optional run_id override for proxy artifacts")
 	flagSet.StringVar(&runpackOut, "runpack-out", "", "optional path to emit a runpack zip for this proxy decision")
@@ -173,6 +210,12 @@ func runMCPProxy(arguments []string) int {
 	flagSet.StringVar(&keyMode, "key-mode", string(sign.ModeDev), "signing key mode: dev or prod")
 	flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private signing key")
 	flagSet.StringVar(&privateKeyEnv, "private-key-env", "", "env var containing base64 private signing key")
+	flagSet.StringVar(&toolAnnotationsPath, "tool-annotations", "", "signed tool annotation snapshot; target hints come from the snapshot instead of the call payload")
+	flagSet.StringVar(&toolAnnotationsPublicKey, "tool-annotations-public-key", "", "path to base64 tool annotation snapshot verify key")
+	flagSet.StringVar(&toolAnnotationsPublicKeyEnv, "tool-annotations-public-key-env", "", "env var containing base64 tool annotation snapshot verify key")
+	flagSet.StringVar(&rateLimitState, "rate-limit-state", "", "optional path to persisted rate limit state; enables rate_limit and destructive_budget enforcement")
+	flagSet.StringVar(&rateLimitURL, "rate-limit-url", "", "optional shared rate limit service URL hosted by gait mcp serve --rate-limit-service")
+	flagSet.StringVar(&rateLimitTokenEnv, "rate-limit-token-env", "", "env var containing bearer token for --rate-limit-url")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
 
@@ -200,11 +243,33 @@ func runMCPProxy(arguments []string) int {
 	if err != nil {
 		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
+	toolAnnotations, err := loadMCPToolAnnotations(toolAnnotationsPath, sign.KeyConfig{
+		PublicKeyPath:  strings.TrimSpace(toolAnnotationsPublicKey),
+		PublicKeyEnv:   strings.TrimSpace(toolAnnotationsPublicKeyEnv),
+		PrivateKeyPath: strings.TrimSpace(privateKeyPath),
+		PrivateKeyEnv:  strings.TrimSpace(privateKeyEnv),
+	})
+	if err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	rateLimitStore, err := resolveMCPRateLimitStore(rateLimitState, rateLimitURL, rateLimitTokenEnv)
+	if err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
+	notifier, err := loadProjectNotifier(projectconfig.DefaultPath)
+	if err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	telemetry, err := loadProjectTelemetry(projectconfig.DefaultPath)
+	if err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
 	output, exitCode, err := evaluateMCPProxyPayload(policyPath, payload, mcpProxyEvalOptions{
 		Adapter:                    adapter,
 		Profile:                    profile,
 		JobRoot:                    jobRoot,
 		KillSwitchStatePath:        killSwitchStatePath,
+		KillSwitchSource:           killSwitchSource,
 		RunID:                      runID,
 		ContextEnvelopePath:        contextEnvelopePath,
 		TracePath:                  tracePath,
@@ -216,10 +281,15 @@ func runMCPProxy(arguments []string) int {
 		PrivateKey:                 privateKeyPath,
 		PrivateKeyEnv:              privateKeyEnv,
 		AllowLocalContextArtifacts: true,
+		ToolAnnotations:            toolAnnotations,
+		RateLimitStore:             rateLimitStore,
+		Notifier:                   notifier,
+		Telemetry:                  telemetry,
 	})
 	if err != nil {
 		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
+	output.Warnings = mergeUniqueSorted(output.Warnings, spoolTelemetry(telemetry))
 	return writeMCPProxyOutput(jsonOutput, output, exitCode)
 }
 
@@ -381,6 +451,9 @@ func evaluateMCPProxyPayload(policyPath string, payload []byte, options mcpProxy
 	if err != nil {
 		return mcpProxyOutput{}, exitInvalidInput, err
 	}
+	if err := bindMCPServePrincipal(&call, options.Principal, options.IdentityBinding); err != nil {
+		return mcpProxyOutput{}, exitInvalidInput, err
+	}
 	evalOptions := gate.EvalOptions{ProducerVersion: currentVersion()}
 	envelopePath := strings.TrimSpace(options.ContextEnvelopePath)
 	if options.VerifiedContextEnvelope != nil {
@@ -410,10 +483,12 @@ func evaluateMCPProxyPayload(policyPath string, payload []byte, options mcpProxy
 	if err != nil {
 		return mcpProxyOutput{}, exitInvalidInput, err
 	}
+	var killSwitchWarnings []string
 	if trimmedStatePath := strings.TrimSpace(options.KillSwitchStatePath); trimmedStatePath != "" {
-		state, loadErr := gate.LoadKillSwitchState(trimmedStatePath)
+		required := mcpKillSwitchStateRequired(resolvedProfile, call.Context.RiskClass) || gate.IsRemoteKillSwitchSource(trimmedStatePath)
+		state, stateWarnings, loadErr := loadKillSwitchStateSource(trimmedStatePath, options.KillSwitchSource)
 		if loadErr != nil {
-			if mcpKillSwitchStateRequired(resolvedProfile, call.Context.RiskClass) {
+			if required {
 				evalOptions.RequireKillSwitchState = true
 				evalOptions.KillSwitchStateError = loadErr
 			} else {
@@ -421,7 +496,8 @@ func evaluateMCPProxyPayload(policyPath string, payload []byte, options mcpProxy
 			}
 		} else {
 			evalOptions.KillSwitchState = &state
-			evalOptions.RequireKillSwitchState = mcpKillSwitchStateRequired(resolvedProfile, call.Context.RiskClass)
+			evalOptions.RequireKillSwitchState = required
+			killSwitchWarnings = stateWarnings
 		}
 	}
 	if err := validateMCPBoundaryOAuthEvidence(call, resolvedProfile); err != nil {
@@ -441,18 +517,25 @@ func evaluateMCPProxyPayload(policyPath string, payload []byte, options mcpProxy
 
 	evalResult, err := mcp.EvaluateToolCallWithIntentOptions(policy, call, evalOptions, mcp.IntentOptions{
 		RequireExplicitContext: resolvedProfile == gateProfileOSSProd,
+		ToolAnnotations:        options.ToolAnnotations,
 	})
 	if err != nil {
 		return mcpProxyOutput{}, exitInvalidInput, err
 	}
-	emergencyBlockedReason, emergencyWarnings := evaluateMCPEmergencyStop(call, strings.TrimSpace(options.JobRoot))
-	if emergencyBlockedReason != "" {
-		result := evalResult.Outcome.Result
-		result.Verdict = "block"
-		result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{emergencyBlockedReason})
-		result.Violations = mergeUniqueSorted(result.Violations, []string{"emergency_stop_active"})
+	boundary := enforce.Boundary{Actor: "mcp-proxy", DispatchPath: "mcp.proxy", JournalSource: "mcp_proxy", ProducerVersion: currentVersion()}
+	var emergencyWarnings []string
+	evalResult.Outcome.Result, emergencyWarnings = enforce.EmergencyStop(strings.TrimSpace(options.JobRoot), boundary, evalResult.Intent, evalResult.Outcome.Result)
+	if options.RateLimitStore != nil {
+		result, _, _, rateLimitErr := enforce.RateLimits(options.RateLimitStore, evalResult.Outcome, evalResult.Intent, evalResult.Outcome.Result, time.Now().UTC())
+		if rateLimitErr != nil {
+			return mcpProxyOutput{}, exitCodeForError(rateLimitErr, exitInvalidInput), rateLimitErr
+		}
 		evalResult.Outcome.Result = result
 	}
+	policyDigest, err := gate.PolicyDigest(policy)
+	if err != nil {
+		return mcpProxyOutput{}, exitInvalidInput, err
+	}
 
 	keyPair, warnings, err := sign.LoadSigningKey(sign.KeyConfig{
 		Mode:           sign.KeyMode(strings.ToLower(strings.TrimSpace(options.KeyMode))),
@@ -462,7 +545,31 @@ func evaluateMCPProxyPayload(policyPath string, payload []byte, options mcpProxy
 	if err != nil {
 		return mcpProxyOutput{}, exitInvalidInput, err
 	}
-	warnings = mergeUniqueSorted(warnings, emergencyWarnings)
+	warnings = mergeUniqueSorted(mergeUniqueSorted(warnings, killSwitchWarnings), emergencyWarnings)
+	var approvalRequest gate.ApprovalRequestState
+	approvalRequestCreated := false
+	if evalResult.Outcome.Result.Verdict == "require_approval" && strings.TrimSpace(options.ApprovalQueueDir) != "" {
+		approvalKeyPair := options.ApprovalKeyPair
+		if len(approvalKeyPair.Private) == 0 {
+			approvalKeyPair = keyPair
+		}
+		result, state, created, approvalErr := applyMCPApprovalQueue(strings.TrimSpace(options.ApprovalQueueDir), approvalKeyPair, policy, evalResult)
+		if approvalErr != nil {
+			return mcpProxyOutput{}, exitCodeForError(approvalErr, exitInvalidInput), approvalErr
+		}
+		evalResult.Outcome.Result = result
+		approvalRequest = state
+		approvalRequestCreated = created
+	}
+	// Jobs are charged after the approval queue so a granted request cannot
+	// bypass an exhausted budget.
+	var jobConstraintWarnings []string
+	evalResult.Outcome.Result, jobConstraintWarnings = enforce.JobConstraints(strings.TrimSpace(options.JobRoot), boundary, evalResult.Intent, evalResult.Outcome.Result, enforce.JobChargeOptions{
+		PolicyDigest: policyDigest,
+		Cost:         evalResult.Outcome.Cost,
+		OnTransition: notifyJobTransition,
+	})
+	warnings = mergeUniqueSorted(warnings, jobConstraintWarnings)
 	resolvedTracePath := strings.TrimSpace(options.TracePath)
 	if resolvedTracePath == "" {
 		resolvedTracePath = fmt.Sprintf("trace_%s_%s.json", normalizeRunID(options.RunID), time.Now().UTC().Format("20060102T150405.000000000"))
@@ -483,21 +590,36 @@ func evaluateMCPProxyPayload(policyPath string, payload []byte, options mcpProxy
 	if err != nil {
 		return mcpProxyOutput{}, exitInvalidInput, err
 	}
-	if evalResult.Outcome.KillSwitch != nil && evalResult.Outcome.KillSwitch.Status == "active" && strings.TrimSpace(options.KillSwitchStatePath) != "" {
-		if err := gate.AppendKillSwitchJournal(gate.KillSwitchJournalPath(options.KillSwitchStatePath), gate.KillSwitchJournalRecord{
-			CreatedAt:       evalResult.Outcome.Result.CreatedAt,
-			ProducerVersion: currentVersion(),
-			Source:          "mcp_proxy",
-			TraceID:         traceResult.Trace.TraceID,
-			JobID:           evalResult.Intent.Context.JobID,
-			ToolName:        evalResult.Intent.ToolName,
-			AgentID:         evalResult.Intent.Context.AgentID,
-			Identity:        evalResult.Intent.Context.Identity,
-			ReasonCodes:     evalResult.Outcome.KillSwitch.ReasonCodes,
-			MatchedEntryIDs: evalResult.Outcome.KillSwitch.MatchedEntryIDs,
-		}); err != nil {
-			return mcpProxyOutput{}, exitInvalidInput, err
-		}
+	recordTransparencyArtifact(traceResult.TracePath)
+	journalPath := enforce.KillSwitchJournalPath(options.KillSwitchStatePath, options.KillSwitchSource.CachePath)
+	if err := enforce.AppendKillSwitchJournal(journalPath, boundary, evalResult.Intent, evalResult.Outcome, evalResult.Outcome.Result, traceResult.Trace.TraceID); err != nil {
+		return mcpProxyOutput{}, exitInvalidInput, err
+	}
+	if evalResult.Outcome.KillSwitch != nil && evalResult.Outcome.KillSwitch.Status == "active" {
+		warnings = mergeUniqueSorted(warnings, emitNotification(options.Notifier, notify.Event{
+			Kind:        notify.EventKillSwitchTriggered,
+			Source:      "mcp_proxy",
+			Summary:     "kill switch blocked " + evalResult.Intent.ToolName,
+			TraceID:     traceResult.Trace.TraceID,
+			JobID:       evalResult.Intent.Context.JobID,
+			ToolName:    evalResult.Intent.ToolName,
+			Identity:    evalResult.Intent.Context.Identity,
+			ReasonCodes: evalResult.Outcome.KillSwitch.ReasonCodes,
+			Details:     map[string]string{"matched_entry_ids": strings.Join(evalResult.Outcome.KillSwitch.MatchedEntryIDs, ",")},
+		}))
+	}
+	if evalResult.Outcome.Result.Verdict == "require_approval" && (approvalRequest.Request.RequestID == "" || approvalRequestCreated) {
+		warnings = mergeUniqueSorted(warnings, emitNotification(options.Notifier, notify.Event{
+			Kind:        notify.EventApprovalRequired,
+			Source:      "mcp_proxy",
+			Summary:     "approval required for " + evalResult.Intent.ToolName,
+			TraceID:     traceResult.Trace.TraceID,
+			JobID:       evalResult.Intent.Context.JobID,
+			ToolName:    evalResult.Intent.ToolName,
+			Identity:    evalResult.Intent.Context.Identity,
+			ReasonCodes: evalResult.Outcome.Result.ReasonCodes,
+			Details:     approvalNotificationDetails(traceResult.IntentDigest, traceResult.PolicyDigest, evalResult.Outcome.MinApprovals, approvalRequest.Request.RequestID, approvalRequest.Status),
+		}))
 	}
 	if resolvedProfile == gateProfileStandard && (strings.TrimSpace(call.Context.Identity) == "" || strings.TrimSpace(call.Context.Workspace) == "" || strings.TrimSpace(call.Context.SessionID) == "") {
 		warnings = append(warnings, "standard profile applied fallback intent context; use --profile oss-prod for strict context enforcement")
@@ -553,6 +675,7 @@ func evaluateMCPProxyPayload(policyPath string, payload []byte, options mcpProxy
 		}
 		resolvedPackPath = buildResult.Path
 		resolvedPackID = buildResult.Manifest.PackID
+		recordTransparencyArtifact(buildResult.Path)
 	}
 
 	exportEvent := mcp.ExportEvent{
@@ -594,6 +717,23 @@ func evaluateMCPProxyPayload(policyPath string, payload []byte, options mcpProxy
 			return mcpProxyOutput{}, exitInvalidInput, err
 		}
 	}
+	options.Telemetry.Record(otlp.Decision{
+		Source:         "mcp_proxy",
+		StartedAt:      decisionStarted,
+		Latency:        time.Since(decisionStarted),
+		ToolName:       evalResult.Intent.ToolName,
+		Verdict:        evalResult.Outcome.Result.Verdict,
+		ReasonCodes:    evalResult.Outcome.Result.ReasonCodes,
+		PolicyDigest:   traceResult.PolicyDigest,
+		PolicyID:       traceResult.Trace.PolicyID,
+		PolicyVersion:  traceResult.Trace.PolicyVersion,
+		MatchedRuleIDs: traceResult.Trace.MatchedRuleIDs,
+		IntentDigest:   traceResult.IntentDigest,
+		TraceID:        traceResult.Trace.TraceID,
+		RunID:          resolvedRunID,
+		SessionID:      evalResult.Intent.Context.SessionID,
+		JobID:          evalResult.Intent.Context.JobID,
+	})
 
 	exitCode := exitOK
 	switch evalResult.Outcome.Result.Verdict {
@@ -629,31 +769,26 @@ func evaluateMCPProxyPayload(policyPath string, payload []byte, options mcpProxy
 		OTelExport:        resolvedOTelExport,
 		KillSwitch:        evalResult.Outcome.KillSwitch,
 		MCPTrust:          evalResult.Trust,
+		ApprovalRequestID: approvalRequest.Request.RequestID,
+		ApprovalStatus:    approvalRequest.Status,
 		Warnings:          warnings,
 		Relationship:      traceResult.Trace.Relationship,
 	}, exitCode, nil
 }
 
-func evaluateMCPEmergencyStop(call mcp.ToolCall, jobRoot string) (string, []string) {
-	jobID := strings.TrimSpace(call.Context.JobID)
-	if jobID == "" {
-		return "", nil
-	}
-	state, err := jobruntime.Status(jobRoot, jobID)
-	if err != nil {
-		return "emergency_stop_state_unavailable", []string{fmt.Sprintf("job_status_unavailable=%v", err)}
-	}
-	if !jobruntime.IsEmergencyStopped(state) {
-		return "", nil
+// resolveMCPRateLimitStore returns nil when no rate limit backend is configured
+// so MCP boundaries keep skipping budget enforcement by default.
+func resolveMCPRateLimitStore(statePath string, serviceURL string, tokenEnv string) (gate.RateLimitStore, error) {
+	if strings.TrimSpace(statePath) == "" && strings.TrimSpace(serviceURL) == "" {
+		if strings.TrimSpace(tokenEnv) != "" {
+			return nil, fmt.Errorf("--rate-limit-token-env requires --rate-limit-url")
+		}
+		return nil, nil
 	}
-	if _, recordErr := jobruntime.RecordBlockedDispatch(jobRoot, jobID, jobruntime.DispatchRecordOptions{
-		Actor:        "mcp-proxy",
-		DispatchPath: "mcp.proxy",
-		ReasonCode:   "emergency_stop_preempted",
-	}); recordErr != nil {
-		return "emergency_stop_preempted", []string{fmt.Sprintf("blocked_dispatch_record_failed=%v", recordErr)}
+	if strings.TrimSpace(statePath) != "" && strings.TrimSpace(serviceURL) != "" {
+		return nil, fmt.Errorf("--rate-limit-state and --rate-limit-url are mutually exclusive")
 	}
-	return "emergency_stop_preempted", nil
+	return resolveRateLimitStore(statePath, serviceURL, tokenEnv)
 }
 
 func mcpKillSwitchStateRequired(profile gateEvalProfile, riskClass string) bool {
@@ -1067,16 +1202,18 @@ func writeMCPVerifyOutput(jsonOutput bool, output mcpVerifyOutput, exitCode int)
 
 func printMCPUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait mcp proxy --policy <policy.yaml> --call <tool_call.json|-> [--context-envelope <context_envelope.json>] [--adapter mcp|openai|anthropic|langchain|claude_code] [--profile standard|oss-prod] [--job-root ./gait-out/jobs] [--kill-switch-state <state.json>] [--trace-out trace.json] [--run-id run_...] [--runpack-out runpack.zip] [--pack-out pack_run.zip] [--export-log-out events.jsonl] [--export-otel-out otel.jsonl] [--json] [--explain]")
-	fmt.Println("  gait mcp bridge --policy <policy.yaml> --call <tool_call.json|-> [--context-envelope <context_envelope.json>] [--adapter mcp|openai|anthropic|langchain|claude_code] [--profile standard|oss-prod] [--job-root ./gait-out/jobs] [--kill-switch-state <state.json>] [--trace-out trace.json] [--run-id run_...] [--runpack-out runpack.zip] [--pack-out pack_run.zip] [--export-log-out events.jsonl] [--export-otel-out otel.jsonl] [--json] [--explain]")
+	fmt.Println("  gait mcp proxy --policy <policy.yaml> --call <tool_call.json|-> [--context-envelope <context_envelope.json>] [--adapter mcp|openai|anthropic|langchain|claude_code] [--profile standard|oss-prod] [--job-root ./gait-out/jobs] [--kill-switch-state <state.json|url>] [--kill-switch-public-key <path>|--kill-switch-public-key-env <VAR>] [--kill-switch-cache <path>] [--kill-switch-max-stale <duration>] [--trace-out trace.json] [--run-id run_...] [--runpack-out runpack.zip] [--pack-out pack_run.zip] [--export-log-out events.jsonl] [--export-otel-out otel.jsonl] [--json] [--explain]")
+	fmt.Println("  gait mcp bridge --policy <policy.yaml> --call <tool_call.json|-> [--context-envelope <context_envelope.json>] [--adapter mcp|openai|anthropic|langchain|claude_code] [--profile standard|oss-prod] [--job-root ./gait-out/jobs] [--kill-switch-state <state.json|url>] [--kill-switch-public-key <path>|--kill-switch-public-key-env <VAR>] [--kill-switch-cache <path>] [--kill-switch-max-stale <duration>] [--trace-out trace.json] [--run-id run_...] [--runpack-out runpack.zip] [--pack-out pack_run.zip] [--export-log-out events.jsonl] [--export-otel-out otel.jsonl] [--json] [--explain]")
 	fmt.Println("  gait mcp verify --policy <policy.yaml> --server <server.json> [--risk-class <class>] [--json] [--explain]")
-	fmt.Println("  gait mcp serve --policy <policy.yaml> [--context-envelope <context_envelope.json>] [--listen 127.0.0.1:8787] [--adapter mcp|openai|anthropic|langchain|claude_code] [--profile standard|oss-prod] [--job-root ./gait-out/jobs] [--kill-switch-state <state.json>] [--auth-mode off|token] [--auth-token-env <VAR>] [--max-request-bytes <bytes>] [--http-verdict-status compat|strict] [--allow-client-artifact-paths] [--trace-dir <dir>] [--runpack-dir <dir>] [--pack-dir <dir>] [--session-dir <dir>] [--trace-max-age <dur>] [--trace-max-count <n>] [--runpack-max-age <dur>] [--runpack-max-count <n>] [--pack-max-age <dur>] [--pack-max-count <n>] [--session-max-age <dur>] [--session-max-count <n>] [--json] [--explain]")
-	fmt.Println("    serve endpoints: POST /v1/evaluate, POST /v1/evaluate/sse, POST /v1/evaluate/stream")
+	fmt.Println("  gait mcp serve --policy <policy.yaml> [--context-envelope <context_envelope.json>] [--listen 127.0.0.1:8787] [--adapter mcp|openai|anthropic|langchain|claude_code] [--profile standard|oss-prod] [--job-root ./gait-out/jobs] [--kill-switch-state <state.json|url>] [--kill-switch-public-key <path>|--kill-switch-public-key-env <VAR>] [--kill-switch-cache <path>] [--kill-switch-max-stale <duration>] [--auth-mode off|token|jwt|mtls] [--auth-token-env <VAR>] [--tls-cert <cert.pem> --tls-key <key.pem>] [--tls-client-ca <ca.pem>] [--jwt-jwks <jwks.json>|--jwt-issuer <url>] [--identity-binding override|strict] [--max-request-bytes <bytes>] [--http-verdict-status compat|strict] [--allow-client-artifact-paths] [--trace-dir <dir>] [--runpack-dir <dir>] [--pack-dir <dir>] [--session-dir <dir>] [--trace-max-age <dur>] [--trace-max-count <n>] [--runpack-max-age <dur>] [--runpack-max-count <n>] [--pack-max-age <dur>] [--pack-max-count <n>] [--session-max-age <dur>] [--session-max-count <n>] [--upstream-url <url>|--upstream-command <command>] [--tool-annotations <tool_annotations.json>] [--json] [--explain]")
+	fmt.Println("    serve endpoints: POST /v1/evaluate, POST /v1/evaluate/sse, POST /v1/evaluate/stream, POST /mcp (with an upstream)")
+	fmt.Println("  gait mcp relay --policy <policy.yaml> [--upstream-url <url>] [--profile standard|oss-prod] [--trace-dir <dir>] [--server-id <id>] [--identity <id>] [--workspace <path>] [--tool-annotations <tool_annotations.json>] [--json] [--explain] [-- <upstream command> [args...]]")
+	fmt.Println("  gait mcp annotations capture|verify ... (signed tool annotation snapshot from upstream tools/list)")
 }
 
 func printMCPProxyUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait mcp proxy --policy <policy.yaml> --call <tool_call.json|-> [--context-envelope <context_envelope.json>] [--adapter mcp|openai|anthropic|langchain|claude_code] [--profile standard|oss-prod] [--job-root ./gait-out/jobs] [--kill-switch-state <state.json>] [--trace-out trace.json] [--run-id run_...] [--runpack-out runpack.zip] [--pack-out pack_run.zip] [--export-log-out events.jsonl] [--export-otel-out otel.jsonl] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait mcp proxy --policy <policy.yaml> --call <tool_call.json|-> [--context-envelope <context_envelope.json>] [--adapter mcp|openai|anthropic|langchain|claude_code] [--profile standard|oss-prod] [--job-root ./gait-out/jobs] [--kill-switch-state <state.json|url>] [--kill-switch-public-key <path>|--kill-switch-public-key-env <VAR>] [--kill-switch-cache <path>] [--kill-switch-max-stale <duration>] [--trace-out trace.json] [--run-id run_...] [--runpack-out runpack.zip] [--pack-out pack_run.zip] [--export-log-out events.jsonl] [--export-otel-out otel.jsonl] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--tool-annotations <tool_annotations.json>] [--tool-annotations-public-key <path>|--tool-annotations-public-key-env <VAR>] [--rate-limit-state <state.json>|--rate-limit-url <url> [--rate-limit-token-env <VAR>]] [--json] [--explain]")
 }
 
 func printMCPVerifyUsage() {
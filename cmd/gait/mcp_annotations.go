@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/mcp"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+type mcpAnnotationsOutput struct {
+	OK                bool     `json:"ok"`
+	Operation         string   `json:"operation,omitempty"`
+	Path              string   `json:"path,omitempty"`
+	ServerID          string   `json:"server_id,omitempty"`
+	ServerName        string   `json:"server_name,omitempty"`
+	ToolCount         int      `json:"tool_count,omitempty"`
+	ToolsDigest       string   `json:"tools_digest,omitempty"`
+	ServerCount       int      `json:"server_count,omitempty"`
+	TrustSnapshotPath string   `json:"trust_snapshot_path,omitempty"`
+	KeyID             string   `json:"key_id,omitempty"`
+	Warnings          []string `json:"warnings,omitempty"`
+	Error             string   `json:"error,omitempty"`
+}
+
+func runMCPAnnotations(arguments []string) int {
+	if hasExplainFlag(arguments) {
+		return writeExplain("Capture an upstream MCP server's tools/list annotations into a signed snapshot so Gate takes tool hints from the server instead of the caller payload.")
+	}
+	if len(arguments) == 0 {
+		printMCPAnnotationsUsage()
+		return exitInvalidInput
+	}
+	switch arguments[0] {
+	case "capture":
+		return runMCPAnnotationsCapture(arguments[1:])
+	case "verify":
+		return runMCPAnnotationsVerify(arguments[1:])
+	default:
+		printMCPAnnotationsUsage()
+		return exitInvalidInput
+	}
+}
+
+func runMCPAnnotationsCapture(arguments []string) int {
+	upstreamCommand := []string{}
+	for index, argument := range arguments {
+		if argument == "--" {
+			upstreamCommand = append(upstreamCommand, arguments[index+1:]...)
+			arguments = arguments[:index]
+			break
+		}
+	}
+	arguments = reorderInterspersedFlags(arguments, map[string]bool{
+		"upstream-url":    true,
+		"server-id":       true,
+		"server-name":     true,
+		"out":             true,
+		"trust-snapshot":  true,
+		"timeout":         true,
+		"key-mode":        true,
+		"private-key":     true,
+		"private-key-env": true,
+	})
+	flagSet := flag.NewFlagSet("mcp-annotations-capture", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+
+	var upstreamURL string
+	var serverID string
+	var serverName string
+	var outPath string
+	var trustSnapshotPath string
+	var timeout time.Duration
+	var keyMode string
+	var privateKeyPath string
+	var privateKeyEnv string
+	var jsonOutput bool
+	var helpFlag bool
+
+	flagSet.StringVar(&upstreamURL, "upstream-url", "", "streamable HTTP endpoint of the upstream MCP server (alternative to -- <command>)")
+	flagSet.StringVar(&serverID, "server-id", "", "server identity recorded in the snapshot")
+	flagSet.StringVar(&serverName, "server-name", "", "server name recorded in the snapshot (defaults to initialize serverInfo.name)")
+	flagSet.StringVar(&outPath, "out", "./gait-out/mcp/tool_annotations.json", "tool annotation snapshot path (updated in place when it exists)")
+	flagSet.StringVar(&trustSnapshotPath, "trust-snapshot", "", "optional mcp_trust snapshot to record the captured server in")
+	flagSet.DurationVar(&timeout, "timeout", 30*time.Second, "upstream capture timeout")
+	flagSet.StringVar(&keyMode, "key-mode", string(sign.ModeDev), "signing key mode: dev or prod")
+	flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private signing key")
+	flagSet.StringVar(&privateKeyEnv, "private-key-env", "", "env var containing base64 private signing key")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	flagSet.BoolVar(&helpFlag, "help", false, "show help")
+
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "capture", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	if helpFlag {
+		printMCPAnnotationsUsage()
+		return exitOK
+	}
+	if len(flagSet.Args()) > 0 {
+		return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "capture", Error: "unexpected positional arguments"}, exitInvalidInput)
+	}
+	if (strings.TrimSpace(upstreamURL) == "") == (len(upstreamCommand) == 0) {
+		return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "capture", Error: "exactly one upstream is required: --upstream-url <url> or -- <command> [args...]"}, exitInvalidInput)
+	}
+	if strings.TrimSpace(privateKeyPath) == "" && strings.TrimSpace(privateKeyEnv) == "" {
+		return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "capture", Error: "tool annotation snapshots must be signed with a persistent key: set --private-key or --private-key-env"}, exitInvalidInput)
+	}
+	if timeout <= 0 {
+		return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "capture", Error: "--timeout must be > 0"}, exitInvalidInput)
+	}
+	keyPair, warnings, err := sign.LoadSigningKey(sign.KeyConfig{
+		Mode:           sign.KeyMode(strings.ToLower(strings.TrimSpace(keyMode))),
+		PrivateKeyPath: strings.TrimSpace(privateKeyPath),
+		PrivateKeyEnv:  strings.TrimSpace(privateKeyEnv),
+	})
+	if err != nil {
+		return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "capture", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+
+	relayConfig := mcpRelayConfig{UpstreamURL: strings.TrimSpace(upstreamURL), UpstreamCommand: upstreamCommand}
+	upstream, err := startMCPRelayUpstream(relayConfig, nil)
+	if err != nil {
+		return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "capture", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	defer func() {
+		_ = upstream.Close()
+	}()
+	endpoint := relayConfig.UpstreamURL
+	if endpoint == "" {
+		endpoint = strings.Join(upstreamCommand, " ")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	captured, err := mcp.CaptureToolAnnotations(ctx, upstream, mcp.ServerInfo{
+		ServerID:   strings.TrimSpace(serverID),
+		ServerName: strings.TrimSpace(serverName),
+		Endpoint:   endpoint,
+	}, time.Now().UTC())
+	if err != nil {
+		return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "capture", Error: err.Error()}, exitInternalFailure)
+	}
+
+	snapshot := mcp.ToolAnnotationSnapshot{}
+	if _, statErr := os.Stat(outPath); statErr == nil {
+		snapshot, err = mcp.LoadToolAnnotationSnapshot(outPath)
+		if err != nil {
+			return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "capture", Error: err.Error()}, exitInvalidInput)
+		}
+		if snapshot.Signature != nil {
+			if verifyErr := mcp.VerifyToolAnnotationSnapshot(snapshot, keyPair.Public); verifyErr != nil {
+				return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "capture", Error: fmt.Sprintf("existing snapshot %s: %v", outPath, verifyErr)}, exitVerifyFailed)
+			}
+		}
+	} else if !errors.Is(statErr, os.ErrNotExist) {
+		return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "capture", Error: statErr.Error()}, exitInvalidInput)
+	}
+	snapshot = mcp.UpsertToolAnnotationServer(snapshot, captured)
+	snapshot.CreatedAt = captured.CapturedAt
+	snapshot.ProducerVersion = currentVersion()
+	signed, err := mcp.SignToolAnnotationSnapshot(snapshot, keyPair.Private)
+	if err != nil {
+		return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "capture", Error: err.Error()}, exitInvalidInput)
+	}
+	if err := mcp.WriteToolAnnotationSnapshot(outPath, signed); err != nil {
+		return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "capture", Error: err.Error()}, exitInternalFailure)
+	}
+
+	if trimmedTrustPath := strings.TrimSpace(trustSnapshotPath); trimmedTrustPath != "" {
+		trustSnapshot := mcp.TrustSnapshot{}
+		if _, statErr := os.Stat(trimmedTrustPath); statErr == nil {
+			trustSnapshot, err = mcp.LoadTrustSnapshot(trimmedTrustPath)
+			if err != nil {
+				return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "capture", Error: err.Error()}, exitInvalidInput)
+			}
+		}
+		trustSnapshot = mcp.ObserveTrustSnapshotEntry(trustSnapshot, captured, outPath)
+		trustSnapshot.CreatedAt = captured.CapturedAt
+		trustSnapshot.ProducerVersion = currentVersion()
+		if err := mcp.WriteTrustSnapshot(trimmedTrustPath, trustSnapshot); err != nil {
+			return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "capture", Error: err.Error()}, exitInternalFailure)
+		}
+	}
+
+	return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{
+		OK:                true,
+		Operation:         "capture",
+		Path:              outPath,
+		ServerID:          captured.ServerID,
+		ServerName:        captured.ServerName,
+		ToolCount:         len(captured.Tools),
+		ToolsDigest:       captured.ToolsDigest,
+		ServerCount:       len(signed.Servers),
+		TrustSnapshotPath: strings.TrimSpace(trustSnapshotPath),
+		KeyID:             signed.Signature.KeyID,
+		Warnings:          warnings,
+	}, exitOK)
+}
+
+func runMCPAnnotationsVerify(arguments []string) int {
+	arguments = reorderInterspersedFlags(arguments, map[string]bool{
+		"snapshot":        true,
+		"public-key":      true,
+		"public-key-env":  true,
+		"private-key":     true,
+		"private-key-env": true,
+	})
+	flagSet := flag.NewFlagSet("mcp-annotations-verify", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+
+	var snapshotPath string
+	var publicKeyPath string
+	var publicKeyEnv string
+	var privateKeyPath string
+	var privateKeyEnv string
+	var jsonOutput bool
+	var helpFlag bool
+
+	flagSet.StringVar(&snapshotPath, "snapshot", "", "path to tool annotation snapshot JSON")
+	flagSet.StringVar(&publicKeyPath, "public-key", "", "path to base64 verify key")
+	flagSet.StringVar(&publicKeyEnv, "public-key-env", "", "env var containing base64 verify key")
+	flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private signing key")
+	flagSet.StringVar(&privateKeyEnv, "private-key-env", "", "env var containing base64 private signing key")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	flagSet.BoolVar(&helpFlag, "help", false, "show help")
+
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "verify", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	if helpFlag {
+		printMCPAnnotationsUsage()
+		return exitOK
+	}
+	remaining := flagSet.Args()
+	if strings.TrimSpace(snapshotPath) == "" && len(remaining) > 0 {
+		snapshotPath = remaining[0]
+		remaining = remaining[1:]
+	}
+	if strings.TrimSpace(snapshotPath) == "" || len(remaining) > 0 {
+		return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "verify", Error: "expected --snapshot <tool_annotations.json>"}, exitInvalidInput)
+	}
+	snapshot, err := loadMCPToolAnnotations(snapshotPath, sign.KeyConfig{
+		PublicKeyPath:  strings.TrimSpace(publicKeyPath),
+		PublicKeyEnv:   strings.TrimSpace(publicKeyEnv),
+		PrivateKeyPath: strings.TrimSpace(privateKeyPath),
+		PrivateKeyEnv:  strings.TrimSpace(privateKeyEnv),
+	})
+	if err != nil {
+		return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{OK: false, Operation: "verify", Path: snapshotPath, Error: err.Error()}, exitVerifyFailed)
+	}
+	toolCount := 0
+	for _, server := range snapshot.Servers {
+		toolCount += len(server.Tools)
+	}
+	return writeMCPAnnotationsOutput(jsonOutput, mcpAnnotationsOutput{
+		OK:          true,
+		Operation:   "verify",
+		Path:        snapshotPath,
+		ServerCount: len(snapshot.Servers),
+		ToolCount:   toolCount,
+		KeyID:       snapshot.Signature.KeyID,
+	}, exitOK)
+}
+
+// loadMCPToolAnnotations loads and verifies a tool annotation snapshot for use at
+// an evaluation boundary. An empty path disables snapshot annotations.
+func loadMCPToolAnnotations(path string, keyConfig sign.KeyConfig) (*mcp.ToolAnnotationSnapshot, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+	if !hasAnyKeySource(keyConfig) {
+		return nil, fmt.Errorf("tool annotation snapshot requires a verify key (--tool-annotations-public-key/--tool-annotations-public-key-env or private key source)")
+	}
+	verifyKey, err := sign.LoadVerifyKey(keyConfig)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := mcp.LoadToolAnnotationSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := mcp.VerifyToolAnnotationSnapshot(snapshot, verifyKey); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func writeMCPAnnotationsOutput(jsonOutput bool, output mcpAnnotationsOutput, exitCode int) int {
+	if jsonOutput {
+		return writeJSONOutput(output, exitCode)
+	}
+	if !output.OK {
+		fmt.Printf("mcp annotations %s error: %s\n", output.Operation, output.Error)
+		return exitCode
+	}
+	switch output.Operation {
+	case "capture":
+		fmt.Printf("mcp annotations capture: server=%s tools=%d path=%s\n", firstNonEmptyMCPString(output.ServerID, output.ServerName), output.ToolCount, output.Path)
+		if output.TrustSnapshotPath != "" {
+			fmt.Printf("trust snapshot: %s\n", output.TrustSnapshotPath)
+		}
+	default:
+		fmt.Printf("mcp annotations verify: ok servers=%d tools=%d key_id=%s\n", output.ServerCount, output.ToolCount, output.KeyID)
+	}
+	for _, warning := range output.Warnings {
+		fmt.Printf("warning: %s\n", warning)
+	}
+	return exitCode
+}
+
+func firstNonEmptyMCPString(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+func printMCPAnnotationsUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  gait mcp annotations capture [--upstream-url <url>] [--server-id <id>] [--server-name <name>] [--out ./gait-out/mcp/tool_annotations.json] [--trust-snapshot <trust_snapshot.json>] [--timeout 30s] [--key-mode dev|prod] (--private-key <path>|--private-key-env <VAR>) [--json] [--explain] [-- <upstream command> [args...]]")
+	fmt.Println("  gait mcp annotations verify --snapshot <tool_annotations.json> [--public-key <path>|--public-key-env <VAR>|--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  note: evaluation boundaries use the snapshot via --tool-annotations on mcp proxy, serve, and relay")
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Clyra-AI/gait/core/mcp"
+)
+
+func newFakeMCPToolsServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		raw, _ := io.ReadAll(request.Body)
+		message, err := mcp.DecodeRPCMessage(raw)
+		if err != nil {
+			http.Error(writer, "bad request", http.StatusBadRequest)
+			return
+		}
+		if message.IsNotification() {
+			writer.WriteHeader(http.StatusAccepted)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		switch message.Method {
+		case "initialize":
+			_, _ = fmt.Fprintf(writer, `{"jsonrpc":"2.0","id":%s,"result":{"protocolVersion":"2025-06-18","serverInfo":{"name":"files","version":"2.1.0"}}}`, string(message.ID))
+		case "tools/list":
+			_, _ = fmt.Fprintf(writer, `{"jsonrpc":"2.0","id":%s,"result":{"tools":[{"name":"fs.read","annotations":{"readOnlyHint":true}},{"name":"http.post","annotations":{"readOnlyHint":false,"openWorldHint":true}}]}}`, string(message.ID))
+		default:
+			_, _ = fmt.Fprintf(writer, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32601,"message":"method not found"}}`, string(message.ID))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMCPAnnotationsCaptureVerifyAndProxyEnforcement(t *testing.T) {
+	workDir := t.TempDir()
+	keyPath := filepath.Join(workDir, "snapshot.key")
+	writePrivateKey(t, keyPath)
+	snapshotPath := filepath.Join(workDir, "tool_annotations.json")
+	trustPath := filepath.Join(workDir, "trust_snapshot.json")
+	upstream := newFakeMCPToolsServer(t)
+
+	var code int
+	raw := captureStdout(t, func() {
+		code = runMCPAnnotations([]string{"capture", "--upstream-url", upstream.URL, "--server-id", "files", "--out", snapshotPath, "--trust-snapshot", trustPath, "--private-key", keyPath, "--json"})
+	})
+	if code != exitOK {
+		t.Fatalf("capture: expected %d got %d output=%s", exitOK, code, raw)
+	}
+	var captured mcpAnnotationsOutput
+	if err := json.Unmarshal([]byte(raw), &captured); err != nil {
+		t.Fatalf("decode capture output: %v raw=%s", err, raw)
+	}
+	if captured.ToolCount != 2 || captured.ServerName != "files" || captured.KeyID == "" {
+		t.Fatalf("unexpected capture output: %#v", captured)
+	}
+	trustSnapshot, err := mcp.LoadTrustSnapshot(trustPath)
+	if err != nil {
+		t.Fatalf("load trust snapshot: %v", err)
+	}
+	if len(trustSnapshot.Entries) != 1 || trustSnapshot.Entries[0].Status != "observed" {
+		t.Fatalf("expected observed trust entry, got %#v", trustSnapshot.Entries)
+	}
+
+	raw = captureStdout(t, func() {
+		code = runMCPAnnotations([]string{"verify", "--snapshot", snapshotPath, "--private-key", keyPath, "--json"})
+	})
+	if code != exitOK {
+		t.Fatalf("verify: expected %d got %d output=%s", exitOK, code, raw)
+	}
+
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, strings.Join([]string{
+		"default_verdict: block",
+		"rules:",
+		"  - name: allow-read-only",
+		"    effect: allow",
+		"    match:",
+		"      tool_annotations:",
+		"        readOnlyHint: true",
+	}, "\n")+"\n")
+	callPath := filepath.Join(workDir, "call.json")
+	mustWriteFile(t, callPath, `{"name":"http.post","server":{"server_id":"files"},"targets":[{"kind":"url","value":"https://example.com/hook","operation":"get","readOnlyHint":true}]}`)
+
+	raw = captureStdout(t, func() {
+		code = runMCPProxy([]string{"--policy", policyPath, "--call", callPath, "--trace-out", filepath.Join(workDir, "trace_plain.json"), "--json"})
+	})
+	if code != exitOK {
+		t.Fatalf("proxy without snapshot: expected claimed hint to be trusted, got %d output=%s", code, raw)
+	}
+
+	raw = captureStdout(t, func() {
+		code = runMCPProxy([]string{"--policy", policyPath, "--call", callPath, "--trace-out", filepath.Join(workDir, "trace_snapshot.json"), "--tool-annotations", snapshotPath, "--private-key", keyPath, "--json"})
+	})
+	if code != exitPolicyBlocked {
+		t.Fatalf("proxy with snapshot: expected %d got %d output=%s", exitPolicyBlocked, code, raw)
+	}
+	var output mcpProxyOutput
+	if err := json.Unmarshal([]byte(raw), &output); err != nil {
+		t.Fatalf("decode proxy output: %v raw=%s", err, raw)
+	}
+	if !strings.Contains(strings.Join(output.ReasonCodes, ","), "mcp_tool_annotation_mismatch") {
+		t.Fatalf("expected mismatch reason code, got %v", output.ReasonCodes)
+	}
+
+	otherKey := filepath.Join(workDir, "other.key")
+	writePrivateKey(t, otherKey)
+	raw = captureStdout(t, func() {
+		code = runMCPProxy([]string{"--policy", policyPath, "--call", callPath, "--tool-annotations", snapshotPath, "--private-key", otherKey, "--json"})
+	})
+	if code != exitInvalidInput {
+		t.Fatalf("proxy with wrong verify key: expected %d got %d output=%s", exitInvalidInput, code, raw)
+	}
+}
+
+func TestMCPAnnotationsCaptureValidation(t *testing.T) {
+	if code := runMCPAnnotations([]string{"capture", "--json"}); code != exitInvalidInput {
+		t.Fatalf("expected missing upstream to fail, got %d", code)
+	}
+	if code := runMCPAnnotations([]string{"capture", "--upstream-url", "http://127.0.0.1:1/mcp", "--json"}); code != exitInvalidInput {
+		t.Fatalf("expected missing signing key to fail, got %d", code)
+	}
+	if code := runMCPAnnotations([]string{"verify", "--json"}); code != exitInvalidInput {
+		t.Fatalf("expected missing snapshot to fail, got %d", code)
+	}
+	if code := runMCPAnnotations([]string{"unknown"}); code != exitInvalidInput {
+		t.Fatalf("expected unknown subcommand to fail, got %d", code)
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/authn"
+	coreerrors "github.com/Clyra-AI/gait/core/errors"
+	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/mcp"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+const (
+	mcpServeApprovalsPath   = "/v1/approvals"
+	mcpServeMaxApprovalWait = 5 * time.Minute
+)
+
+// mcpServeApprovalDecisionRequest carries no approver: the decision is
+// recorded under the authenticated principal's identity.
+type mcpServeApprovalDecisionRequest struct {
+	ReasonCode string `json:"reason_code"`
+	TTL        string `json:"ttl,omitempty"`
+}
+
+// applyMCPApprovalQueue enqueues a signed approval request for a
+// require_approval verdict. A granted request whose tokens validate against
+// the approval key turns the verdict into allow; a denied request blocks.
+func applyMCPApprovalQueue(queueDir string, keyPair sign.KeyPair, policy gate.Policy, evalResult mcp.EvalResult) (schemagate.GateResult, gate.ApprovalRequestState, bool, error) {
+	result := evalResult.Outcome.Result
+	policyDigest, intentDigest, scope, err := gate.ApprovalContext(policy, evalResult.Intent)
+	if err != nil {
+		return result, gate.ApprovalRequestState{}, false, err
+	}
+	delegationBindingDigest, err := gate.DelegationBindingDigest(evalResult.Intent)
+	if err != nil {
+		return result, gate.ApprovalRequestState{}, false, err
+	}
+	state, created, err := gate.EnqueueApprovalRequest(queueDir, gate.EnqueueApprovalRequestOptions{
+		ProducerVersion:          currentVersion(),
+		ToolName:                 evalResult.Intent.ToolName,
+		Identity:                 evalResult.Intent.Context.Identity,
+		IntentDigest:             intentDigest,
+		PolicyDigest:             policyDigest,
+		DelegationBindingDigest:  delegationBindingDigest,
+		RequiredScope:            scope,
+		MinApprovals:             evalResult.Outcome.MinApprovals,
+		RequireDistinctApprovers: evalResult.Outcome.RequireDistinctApprovers,
+		MatchedRule:              evalResult.Outcome.MatchedRule,
+		ReasonCodes:              result.ReasonCodes,
+		SigningPrivateKey:        keyPair.Private,
+	})
+	if err != nil {
+		return result, gate.ApprovalRequestState{}, false, err
+	}
+	switch state.Status {
+	case gate.ApprovalRequestStatusDenied:
+		result.Verdict = "block"
+		result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{gate.ApprovalReasonRequestDenied})
+		result.Violations = mergeUniqueSorted(result.Violations, []string{"approval_not_granted"})
+	case gate.ApprovalRequestStatusGranted:
+		validApprovals := 0
+		approvers := map[string]struct{}{}
+		for _, tokenPath := range state.TokenPaths {
+			token, err := gate.ReadApprovalToken(tokenPath)
+			if err != nil {
+				return result, gate.ApprovalRequestState{}, false, err
+			}
+			if err := gate.ValidateApprovalToken(token, keyPair.Public, gate.ApprovalValidationOptions{
+				Now:                             time.Now().UTC(),
+				ExpectedIntentDigest:            intentDigest,
+				ExpectedPolicyDigest:            policyDigest,
+				ExpectedDelegationBindingDigest: delegationBindingDigest,
+				RequiredScope:                   scope,
+			}); err != nil {
+				var tokenErr *gate.ApprovalTokenError
+				if errors.As(err, &tokenErr) && tokenErr.Code != "" {
+					result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{tokenErr.Code})
+				}
+				continue
+			}
+			validApprovals++
+			approvers[token.ApproverIdentity] = struct{}{}
+		}
+		granted := validApprovals >= state.Request.MinApprovals
+		if state.Request.RequireDistinctApprovers && len(approvers) < state.Request.MinApprovals {
+			granted = false
+		}
+		if granted {
+			result.Verdict = "allow"
+			result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{gate.ApprovalReasonGranted})
+		}
+	}
+	return result, state, created, nil
+}
+
+func registerMCPServeApprovalRoutes(mux *http.ServeMux, config mcpServeConfig) {
+	mux.HandleFunc(mcpServeApprovalsPath, func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet {
+			writeMCPServeError(writer, http.StatusMethodNotAllowed, "expected GET")
+			return
+		}
+		if _, err := authorizeMCPServeRequest(config, request); err != nil {
+			writeMCPServeError(writer, http.StatusUnauthorized, err.Error())
+			return
+		}
+		states, err := gate.ListApprovalRequests(config.ApprovalQueueDir, time.Now().UTC())
+		if err != nil {
+			writeMCPServeError(writer, http.StatusInternalServerError, err.Error())
+			return
+		}
+		status := strings.ToLower(strings.TrimSpace(request.URL.Query().Get("status")))
+		filtered := make([]gate.ApprovalRequestState, 0, len(states))
+		for _, state := range states {
+			if status == "" || state.Status == status {
+				filtered = append(filtered, state)
+			}
+		}
+		writeMCPServeJSON(writer, http.StatusOK, approveQueueOutput{OK: true, Action: "list", Requests: filtered})
+	})
+	mux.HandleFunc(mcpServeApprovalsPath+"/", func(writer http.ResponseWriter, request *http.Request) {
+		principal, err := authorizeMCPServeRequest(config, request)
+		if err != nil {
+			writeMCPServeError(writer, http.StatusUnauthorized, err.Error())
+			return
+		}
+		requestID, action, _ := strings.Cut(strings.TrimPrefix(request.URL.Path, mcpServeApprovalsPath+"/"), "/")
+		switch {
+		case action == "" && request.Method == http.MethodGet:
+			handleMCPServeApprovalShow(writer, request, config, requestID)
+		case (action == gate.ApprovalDecisionGrant || action == gate.ApprovalDecisionDeny) && request.Method == http.MethodPost:
+			if err := authorizeMCPServeApprover(config, principal); err != nil {
+				writeMCPServeError(writer, http.StatusForbidden, err.Error())
+				return
+			}
+			handleMCPServeApprovalDecision(writer, request, config, principal, requestID, action)
+		case action == "" || action == gate.ApprovalDecisionGrant || action == gate.ApprovalDecisionDeny:
+			writeMCPServeError(writer, http.StatusMethodNotAllowed, "unsupported method")
+		default:
+			writeMCPServeError(writer, http.StatusNotFound, "unknown approval action")
+		}
+	})
+}
+
+func handleMCPServeApprovalShow(writer http.ResponseWriter, request *http.Request, config mcpServeConfig, requestID string) {
+	var state gate.ApprovalRequestState
+	var err error
+	if rawWait := strings.TrimSpace(request.URL.Query().Get("wait")); rawWait != "" {
+		wait, parseErr := time.ParseDuration(rawWait)
+		if parseErr != nil || wait < 0 {
+			writeMCPServeError(writer, http.StatusBadRequest, "wait must be a non-negative duration")
+			return
+		}
+		if wait > mcpServeMaxApprovalWait {
+			wait = mcpServeMaxApprovalWait
+		}
+		ctx, cancel := context.WithTimeout(request.Context(), wait)
+		state, err = gate.WaitApprovalRequest(ctx, config.ApprovalQueueDir, requestID, 0)
+		cancel()
+	} else {
+		state, err = gate.LoadApprovalRequest(config.ApprovalQueueDir, requestID, time.Now().UTC())
+	}
+	if err != nil {
+		writeMCPServeError(writer, mcpServeApprovalErrorStatus(err), err.Error())
+		return
+	}
+	writeMCPServeJSON(writer, http.StatusOK, approveQueueOutput{OK: true, Action: "show", Request: &state})
+}
+
+// authorizeMCPServeApprover allows grant and deny only for a jwt or mtls
+// principal listed in --approval-approvers, so credentials that can evaluate
+// cannot approve their own calls.
+func authorizeMCPServeApprover(config mcpServeConfig, principal authn.Principal) error {
+	if len(config.ApprovalApprovers) == 0 {
+		return fmt.Errorf("approval decisions over http are disabled; configure --approval-approvers or use gait approve")
+	}
+	if principal.Method == "" || strings.TrimSpace(principal.Identity) == "" {
+		return fmt.Errorf("approval decisions require an authenticated jwt or mtls principal")
+	}
+	for _, approver := range config.ApprovalApprovers {
+		if approver == principal.Identity {
+			return nil
+		}
+	}
+	return fmt.Errorf("principal %s is not an approval approver", principal.Identity)
+}
+
+func handleMCPServeApprovalDecision(writer http.ResponseWriter, request *http.Request, config mcpServeConfig, principal authn.Principal, requestID string, decision string) {
+	request.Body = http.MaxBytesReader(writer, request.Body, config.MaxRequestBytes)
+	payload, err := io.ReadAll(request.Body)
+	_ = request.Body.Close()
+	if err != nil {
+		writeMCPServeError(writer, http.StatusRequestEntityTooLarge, "request body exceeds max-request-bytes")
+		return
+	}
+	var input mcpServeApprovalDecisionRequest
+	if err := json.Unmarshal(payload, &input); err != nil {
+		writeMCPServeError(writer, http.StatusBadRequest, fmt.Sprintf("decode approval decision: %v", err))
+		return
+	}
+	var ttl time.Duration
+	if strings.TrimSpace(input.TTL) != "" {
+		ttl, err = time.ParseDuration(strings.TrimSpace(input.TTL))
+		if err != nil || ttl <= 0 {
+			writeMCPServeError(writer, http.StatusBadRequest, "ttl must be a positive duration")
+			return
+		}
+	}
+	state, err := gate.DecideApprovalRequest(config.ApprovalQueueDir, gate.DecideApprovalRequestOptions{
+		ProducerVersion:   currentVersion(),
+		RequestID:         requestID,
+		Decision:          decision,
+		ApproverIdentity:  principal.Identity,
+		ReasonCode:        input.ReasonCode,
+		TTL:               ttl,
+		SigningPrivateKey: config.ApprovalKeyPair.Private,
+		VerifyPublicKey:   config.ApprovalKeyPair.Public,
+	})
+	if err != nil {
+		writeMCPServeError(writer, mcpServeApprovalErrorStatus(err), err.Error())
+		return
+	}
+	writeMCPServeJSON(writer, http.StatusOK, approveQueueOutput{OK: true, Action: decision, Request: &state})
+}
+
+func mcpServeApprovalErrorStatus(err error) int {
+	switch coreerrors.CodeOf(err) {
+	case "approval_request_not_found":
+		return http.StatusNotFound
+	case "approval_request_not_pending", gate.ApprovalReasonDistinctApprovers:
+		return http.StatusConflict
+	case "approval_queue_lock_timeout":
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadRequest
+	}
+}
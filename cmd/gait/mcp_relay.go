@@ -0,0 +1,355 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/mcp"
+	"github.com/Clyra-AI/gait/core/notify"
+	"github.com/Clyra-AI/gait/core/otlp"
+	"github.com/Clyra-AI/gait/core/projectconfig"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+type mcpRelayConfig struct {
+	PolicyPath          string
+	Profile             string
+	JobRoot             string
+	KillSwitchStatePath string
+	KillSwitchSource    killSwitchSourceFlags
+	TraceDir            string
+	LogExportPath       string
+	OTelExport          string
+	KeyMode             string
+	PrivateKey          string // #nosec G117 -- field name is explicit config surface, not a hardcoded secret.
+	PrivateKeyEnv       string
+	UpstreamURL         string
+	UpstreamCommand     []string
+	ServerID            string
+	ServerName          string
+	Context             mcp.CallContext
+	ToolAnnotations     *mcp.ToolAnnotationSnapshot
+	RateLimitStore      gate.RateLimitStore
+	IdentityBinding     string
+	ApprovalQueueDir    string
+	ApprovalKeyPair     sign.KeyPair
+	Notifier            *notify.Notifier
+	Telemetry           *otlp.Exporter
+}
+
+func runMCPRelay(arguments []string) int {
+	if hasExplainFlag(arguments) {
+		return writeExplain("Run a transparent stdio MCP proxy that forwards JSON-RPC traffic to an upstream MCP server and only lets tools/call requests through when Gate allows them.")
+	}
+	upstreamCommand := []string{}
+	for index, argument := range arguments {
+		if argument == "--" {
+			upstreamCommand = append(upstreamCommand, arguments[index+1:]...)
+			arguments = arguments[:index]
+			break
+		}
+	}
+	arguments = reorderInterspersedFlags(arguments, map[string]bool{
+		"policy":                          true,
+		"upstream-url":                    true,
+		"profile":                         true,
+		"job-root":                        true,
+		"kill-switch-state":               true,
+		"kill-switch-public-key":          true,
+		"kill-switch-public-key-env":      true,
+		"kill-switch-cache":               true,
+		"kill-switch-max-stale":           true,
+		"trace-dir":                       true,
+		"server-id":                       true,
+		"server-name":                     true,
+		"identity":                        true,
+		"workspace":                       true,
+		"risk-class":                      true,
+		"session-id":                      true,
+		"export-log-out":                  true,
+		"export-otel-out":                 true,
+		"key-mode":                        true,
+		"private-key":                     true,
+		"private-key-env":                 true,
+		"tool-annotations":                true,
+		"tool-annotations-public-key":     true,
+		"tool-annotations-public-key-env": true,
+		"rate-limit-state":                true,
+		"rate-limit-url":                  true,
+		"rate-limit-token-env":            true,
+		"approval-queue":                  true,
+	})
+	flagSet := flag.NewFlagSet("mcp-relay", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+
+	var config mcpRelayConfig
+	var toolAnnotationsPath string
+	var toolAnnotationsPublicKey string
+	var toolAnnotationsPublicKeyEnv string
+	var rateLimitState string
+	var rateLimitURL string
+	var rateLimitTokenEnv string
+	var jsonOutput bool
+	var helpFlag bool
+
+	flagSet.StringVar(&config.PolicyPath, "policy", "", "path to policy YAML")
+	flagSet.StringVar(&config.UpstreamURL, "upstream-url", "", "streamable HTTP endpoint of the upstream MCP server (alternative to -- <command>)")
+	flagSet.StringVar(&config.Profile, "profile", string(gateProfileStandard), "runtime profile: standard|oss-prod")
+	flagSet.StringVar(&config.JobRoot, "job-root", "./gait-out/jobs", "job runtime root for emergency stop preemption checks when context.job_id is present")
+	flagSet.StringVar(&config.KillSwitchStatePath, "kill-switch-state", "", "path or http(s) URL of generalized kill-switch state JSON")
+	bindKillSwitchSourceFlags(flagSet, &config.KillSwitchSource)
+	flagSet.StringVar(&config.TraceDir, "trace-dir", "./gait-out/mcp-relay/traces", "directory for emitted traces")
+	flagSet.StringVar(&config.ServerID, "server-id", "", "upstream server identity used for mcp_trust evaluation")
+	flagSet.StringVar(&config.ServerName, "server-name", "", "upstream server name (defaults to initialize serverInfo.name)")
+	flagSet.StringVar(&config.Context.Identity, "identity", "", "intent context identity applied to relayed tool calls")
+	flagSet.StringVar(&config.Context.Workspace, "workspace", "", "intent context workspace applied to relayed tool calls")
+	flagSet.StringVar(&config.Context.RiskClass, "risk-class", "", "intent context risk class applied to relayed tool calls")
+	flagSet.StringVar(&config.Context.SessionID, "session-id", "", "intent context session id applied to relayed tool calls")
+	flagSet.StringVar(&config.LogExportPath, "export-log-out", "", "optional JSONL log export path")
+	flagSet.StringVar(&config.OTelExport, "export-otel-out", "", "optional OTEL-style JSONL export path")
+	flagSet.StringVar(&config.KeyMode, "key-mode", "dev", "signing key mode: dev or prod")
+	flagSet.StringVar(&config.PrivateKey, "private-key", "", "path to base64 private signing key")
+	flagSet.StringVar(&config.PrivateKeyEnv, "private-key-env", "", "env var containing base64 private signing key")
+	flagSet.StringVar(&toolAnnotationsPath, "tool-annotations", "", "signed tool annotation snapshot; target hints come from the snapshot instead of the call payload")
+	flagSet.StringVar(&toolAnnotationsPublicKey, "tool-annotations-public-key", "", "path to base64 tool annotation snapshot verify key")
+	flagSet.StringVar(&toolAnnotationsPublicKeyEnv, "tool-annotations-public-key-env", "", "env var containing base64 tool annotation snapshot verify key")
+	flagSet.StringVar(&rateLimitState, "rate-limit-state", "", "optional path to persisted rate limit state; enables rate_limit and destructive_budget enforcement")
+	flagSet.StringVar(&rateLimitURL, "rate-limit-url", "", "optional shared rate limit service URL hosted by gait mcp serve --rate-limit-service")
+	flagSet.StringVar(&rateLimitTokenEnv, "rate-limit-token-env", "", "env var containing bearer token for --rate-limit-url")
+	flagSet.StringVar(&config.ApprovalQueueDir, "approval-queue", "", "approval queue directory; require_approval verdicts enqueue signed requests decided via gait approve")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON errors")
+	flagSet.BoolVar(&helpFlag, "help", false, "show help")
+
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	if helpFlag {
+		printMCPRelayUsage()
+		return exitOK
+	}
+	remaining := flagSet.Args()
+	if strings.TrimSpace(config.PolicyPath) == "" && len(remaining) > 0 {
+		config.PolicyPath = remaining[0]
+		remaining = remaining[1:]
+	}
+	if strings.TrimSpace(config.PolicyPath) == "" || len(remaining) > 0 {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: "expected --policy <policy.yaml> and an upstream (--upstream-url <url> or -- <command> [args...])"}, exitInvalidInput)
+	}
+	config.UpstreamCommand = upstreamCommand
+	config.ApprovalQueueDir = strings.TrimSpace(config.ApprovalQueueDir)
+	if (strings.TrimSpace(config.UpstreamURL) == "") == (len(config.UpstreamCommand) == 0) {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: "exactly one upstream is required: --upstream-url <url> or -- <command> [args...]"}, exitInvalidInput)
+	}
+	if _, err := parseGateEvalProfile(config.Profile); err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
+	toolAnnotations, err := loadMCPToolAnnotations(toolAnnotationsPath, sign.KeyConfig{
+		PublicKeyPath:  strings.TrimSpace(toolAnnotationsPublicKey),
+		PublicKeyEnv:   strings.TrimSpace(toolAnnotationsPublicKeyEnv),
+		PrivateKeyPath: strings.TrimSpace(config.PrivateKey),
+		PrivateKeyEnv:  strings.TrimSpace(config.PrivateKeyEnv),
+	})
+	if err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	config.ToolAnnotations = toolAnnotations
+	rateLimitStore, err := resolveMCPRateLimitStore(rateLimitState, rateLimitURL, rateLimitTokenEnv)
+	if err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
+	config.RateLimitStore = rateLimitStore
+	notifier, err := loadProjectNotifier(projectconfig.DefaultPath)
+	if err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	config.Notifier = notifier
+	defer startNotifyFlushLoop(notifier)()
+	if strings.TrimSpace(config.TraceDir) != "" {
+		if err := os.MkdirAll(config.TraceDir, 0o750); err != nil {
+			return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: fmt.Sprintf("create trace directory: %v", err)}, exitInvalidInput)
+		}
+	}
+
+	output := &mcpRelayWriter{writer: os.Stdout}
+	upstream, err := startMCPRelayUpstream(config, func(message mcp.RPCMessage) {
+		output.writeMessage(message)
+	})
+	if err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	defer func() {
+		_ = upstream.Close()
+	}()
+	relay, err := newMCPRelay(config, upstream)
+	if err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Fprintf(os.Stderr, "mcp relay: transport=stdio policy=%s\n", config.PolicyPath)
+	if err := serveMCPRelayStdio(ctx, relay, config.Context.SessionID, os.Stdin, output); err != nil {
+		fmt.Fprintf(os.Stderr, "mcp relay error: %v\n", err)
+		return exitInternalFailure
+	}
+	return exitOK
+}
+
+func startMCPRelayUpstream(config mcpRelayConfig, onMessage func(mcp.RPCMessage)) (mcp.Upstream, error) {
+	if endpoint := strings.TrimSpace(config.UpstreamURL); endpoint != "" {
+		return mcp.NewHTTPUpstream(mcp.HTTPUpstreamOptions{
+			URL:       endpoint,
+			OnMessage: onMessage,
+		})
+	}
+	if len(config.UpstreamCommand) == 0 {
+		return nil, fmt.Errorf("upstream command is required")
+	}
+	return mcp.StartStdioUpstream(mcp.StdioUpstreamOptions{
+		Command:   config.UpstreamCommand[0],
+		Args:      config.UpstreamCommand[1:],
+		Stderr:    os.Stderr,
+		OnMessage: onMessage,
+	})
+}
+
+func newMCPRelay(config mcpRelayConfig, upstream mcp.Upstream) (*mcp.Relay, error) {
+	var server *mcp.ServerInfo
+	if strings.TrimSpace(config.ServerID) != "" || strings.TrimSpace(config.ServerName) != "" {
+		endpoint := strings.TrimSpace(config.UpstreamURL)
+		if endpoint == "" {
+			endpoint = strings.Join(config.UpstreamCommand, " ")
+		}
+		server = &mcp.ServerInfo{
+			ServerID:   strings.TrimSpace(config.ServerID),
+			ServerName: strings.TrimSpace(config.ServerName),
+			Endpoint:   endpoint,
+		}
+	}
+	return mcp.NewRelay(mcp.RelayOptions{
+		Upstream: upstream,
+		Evaluate: newMCPRelayEvaluator(config),
+		Server:   server,
+		Context:  config.Context,
+	})
+}
+
+func newMCPRelayEvaluator(config mcpRelayConfig) mcp.CallEvaluator {
+	return func(ctx context.Context, call mcp.ToolCall) (mcp.CallDecision, error) {
+		payload, err := json.Marshal(call)
+		if err != nil {
+			return mcp.CallDecision{}, fmt.Errorf("encode relayed tool call: %w", err)
+		}
+		tracePath := ""
+		if strings.TrimSpace(config.TraceDir) != "" {
+			tracePath = filepath.Join(config.TraceDir, fmt.Sprintf("trace_%s_%s.json", normalizeRunID(call.Context.RunID), time.Now().UTC().Format("20060102T150405.000000000")))
+		}
+		output, _, err := evaluateMCPProxyPayload(config.PolicyPath, payload, mcpProxyEvalOptions{
+			Adapter:             "mcp",
+			Profile:             config.Profile,
+			JobRoot:             config.JobRoot,
+			KillSwitchStatePath: config.KillSwitchStatePath,
+			KillSwitchSource:    config.KillSwitchSource,
+			RunID:               call.Context.RunID,
+			TracePath:           tracePath,
+			LogExportPath:       config.LogExportPath,
+			OTelExport:          config.OTelExport,
+			KeyMode:             config.KeyMode,
+			PrivateKey:          config.PrivateKey,
+			PrivateKeyEnv:       config.PrivateKeyEnv,
+			ToolAnnotations:     config.ToolAnnotations,
+			RateLimitStore:      config.RateLimitStore,
+			Principal:           mcpServePrincipalFromContext(ctx),
+			IdentityBinding:     config.IdentityBinding,
+			ApprovalQueueDir:    config.ApprovalQueueDir,
+			ApprovalKeyPair:     config.ApprovalKeyPair,
+			Notifier:            config.Notifier,
+			Telemetry:           config.Telemetry,
+		})
+		if err != nil {
+			return mcp.CallDecision{}, err
+		}
+		return mcp.CallDecision{
+			Verdict:           output.Verdict,
+			ReasonCodes:       output.ReasonCodes,
+			Violations:        output.Violations,
+			TraceID:           output.TraceID,
+			PolicyDigest:      output.PolicyDigest,
+			IntentDigest:      output.IntentDigest,
+			ApprovalRequestID: output.ApprovalRequestID,
+			ApprovalStatus:    output.ApprovalStatus,
+		}, nil
+	}
+}
+
+type mcpRelayWriter struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+func (relayWriter *mcpRelayWriter) writeMessage(message mcp.RPCMessage) {
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+	relayWriter.writeRaw(encoded)
+}
+
+func (relayWriter *mcpRelayWriter) writeRaw(encoded []byte) {
+	relayWriter.mu.Lock()
+	defer relayWriter.mu.Unlock()
+	_, _ = relayWriter.writer.Write(append(bytes.TrimSpace(encoded), '\n'))
+}
+
+func serveMCPRelayStdio(ctx context.Context, relay *mcp.Relay, sessionID string, input io.Reader, output *mcpRelayWriter) error {
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10<<20)
+	var inflight sync.WaitGroup
+	defer inflight.Wait()
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		payload := append([]byte(nil), line...)
+		// Only tool calls are evaluated concurrently. Everything else reaches
+		// the upstream in arrival order, so notifications/initialized cannot
+		// overtake initialize.
+		if message, err := mcp.DecodeRPCMessage(payload); err != nil || message.Method != "tools/call" {
+			if reply := relay.Handle(ctx, sessionID, payload); reply != nil {
+				output.writeRaw(reply)
+			}
+			continue
+		}
+		inflight.Add(1)
+		go func() {
+			defer inflight.Done()
+			if reply := relay.Handle(ctx, sessionID, payload); reply != nil {
+				output.writeRaw(reply)
+			}
+		}()
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read stdio input: %w", err)
+	}
+	return nil
+}
+
+func printMCPRelayUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  gait mcp relay --policy <policy.yaml> [--upstream-url <url>] [--profile standard|oss-prod] [--job-root ./gait-out/jobs] [--kill-switch-state <state.json|url>] [--kill-switch-public-key <path>|--kill-switch-public-key-env <VAR>] [--kill-switch-cache <path>] [--kill-switch-max-stale <duration>] [--trace-dir <dir>] [--server-id <id>] [--server-name <name>] [--identity <id>] [--workspace <path>] [--risk-class <class>] [--session-id <id>] [--export-log-out events.jsonl] [--export-otel-out otel.jsonl] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--tool-annotations <tool_annotations.json>] [--tool-annotations-public-key <path>|--tool-annotations-public-key-env <VAR>] [--rate-limit-state <state.json>|--rate-limit-url <url> [--rate-limit-token-env <VAR>]] [--approval-queue <dir>] [--json] [--explain] [-- <upstream command> [args...]]")
+	fmt.Println("  transport: newline-delimited JSON-RPC on stdin/stdout; tools/call is forwarded upstream only when Gate returns allow")
+}
@@ -0,0 +1,515 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/authn"
+	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/mcp"
+	"github.com/Clyra-AI/gait/core/notify"
+)
+
+func newFakeMCPUpstreamServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		raw, _ := io.ReadAll(request.Body)
+		message, err := mcp.DecodeRPCMessage(raw)
+		if err != nil {
+			http.Error(writer, "bad request", http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		methods = append(methods, message.Method)
+		mu.Unlock()
+		if message.IsNotification() {
+			writer.WriteHeader(http.StatusAccepted)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(writer, `{"jsonrpc":"2.0","id":%s,"result":{"content":[{"type":"text","text":"upstream-ok"}]}}`, string(message.ID))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), methods...)
+	}
+}
+
+func TestMCPServeHandlerRelaysToolCallsToUpstream(t *testing.T) {
+	workDir := t.TempDir()
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, strings.Join([]string{
+		"default_verdict: allow",
+		"rules:",
+		"  - name: block-delete",
+		"    effect: block",
+		"    match:",
+		"      tool_names: [fs.delete]",
+	}, "\n")+"\n")
+	upstream, upstreamMethods := newFakeMCPUpstreamServer(t)
+
+	handler, err := newMCPServeHandler(mcpServeConfig{
+		PolicyPath:     policyPath,
+		DefaultAdapter: "mcp",
+		TraceDir:       filepath.Join(workDir, "traces"),
+		KeyMode:        "dev",
+		UpstreamURL:    upstream.URL,
+	})
+	if err != nil {
+		t.Fatalf("newMCPServeHandler: %v", err)
+	}
+
+	post := func(sessionID string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		if sessionID != "" {
+			request.Header.Set("Mcp-Session-Id", sessionID)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	if missing := post("", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"fs.read"}}`); missing.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without a session, got %d %s", missing.Code, missing.Body.String())
+	}
+	if unknown := post("sess-client-chosen", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"fs.read"}}`); unknown.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a client-chosen session, got %d", unknown.Code)
+	}
+	initialized := post("", `{"jsonrpc":"2.0","id":0,"method":"initialize","params":{}}`)
+	sessionID := initialized.Header().Get("Mcp-Session-Id")
+	if initialized.Code != http.StatusOK || !strings.HasPrefix(sessionID, "mcps_") {
+		t.Fatalf("expected initialize to open a session, got %d session=%q", initialized.Code, sessionID)
+	}
+
+	allowed := post(sessionID, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"fs.read","arguments":{"path":"/tmp/a"}}}`)
+	if allowed.Code != http.StatusOK || !strings.Contains(allowed.Body.String(), "upstream-ok") {
+		t.Fatalf("expected allowed call forwarded, got %d %s", allowed.Code, allowed.Body.String())
+	}
+
+	blocked := post(sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"fs.delete","arguments":{"path":"/tmp/a"}}}`)
+	var reply mcp.RPCMessage
+	if err := json.Unmarshal(blocked.Body.Bytes(), &reply); err != nil {
+		t.Fatalf("decode blocked reply: %v", err)
+	}
+	if reply.Error == nil || reply.Error.Code != mcp.RPCErrorPolicyBlocked {
+		t.Fatalf("expected policy blocked error, got %s", blocked.Body.String())
+	}
+
+	notification := post(sessionID, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	if notification.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 for notification, got %d", notification.Code)
+	}
+	if methods := upstreamMethods(); strings.Join(methods, ",") != "initialize,tools/call,notifications/initialized" {
+		t.Fatalf("unexpected upstream methods: %v", methods)
+	}
+
+	traces, err := os.ReadDir(filepath.Join(workDir, "traces"))
+	if err != nil {
+		t.Fatalf("read trace dir: %v", err)
+	}
+	if len(traces) != 2 {
+		t.Fatalf("expected signed traces for both evaluated calls, got %d", len(traces))
+	}
+
+	deleteRequest := httptest.NewRequest(http.MethodDelete, "/mcp", nil)
+	deleteRequest.Header.Set("Mcp-Session-Id", sessionID)
+	deleteRecorder := httptest.NewRecorder()
+	handler.ServeHTTP(deleteRecorder, deleteRequest)
+	if deleteRecorder.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 for DELETE /mcp, got %d", deleteRecorder.Code)
+	}
+	if closed := post(sessionID, `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"fs.read"}}`); closed.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 after session delete, got %d", closed.Code)
+	}
+}
+
+func TestMCPServeHandlerRelayEnqueuesApprovalRequests(t *testing.T) {
+	workDir := t.TempDir()
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, strings.Join([]string{
+		"default_verdict: allow",
+		"rules:",
+		"  - name: approve-write",
+		"    effect: require_approval",
+		"    match:",
+		"      tool_names: [fs.write]",
+	}, "\n")+"\n")
+	upstream, upstreamMethods := newFakeMCPUpstreamServer(t)
+	queueDir := filepath.Join(workDir, "queue")
+
+	handler, err := newMCPServeHandler(mcpServeConfig{
+		PolicyPath:       policyPath,
+		DefaultAdapter:   "mcp",
+		TraceDir:         filepath.Join(workDir, "traces"),
+		KeyMode:          "dev",
+		UpstreamURL:      upstream.URL,
+		ApprovalQueueDir: queueDir,
+	})
+	if err != nil {
+		t.Fatalf("newMCPServeHandler: %v", err)
+	}
+	post := func(sessionID string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		if sessionID != "" {
+			request.Header.Set("Mcp-Session-Id", sessionID)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	sessionID := post("", `{"jsonrpc":"2.0","id":0,"method":"initialize","params":{}}`).Header().Get("Mcp-Session-Id")
+	if sessionID == "" {
+		t.Fatalf("expected initialize to open a session")
+	}
+	pending := post(sessionID, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"fs.write","arguments":{"path":"/tmp/a"}}}`)
+	var reply mcp.RPCMessage
+	if err := json.Unmarshal(pending.Body.Bytes(), &reply); err != nil {
+		t.Fatalf("decode approval reply: %v", err)
+	}
+	if reply.Error == nil || reply.Error.Code != mcp.RPCErrorApprovalRequired {
+		t.Fatalf("expected approval required error, got %s", pending.Body.String())
+	}
+	data, _ := reply.Error.Data.(map[string]any)
+	requestID, _ := data["approval_request_id"].(string)
+	if requestID == "" || data["approval_request_status"] != "pending" {
+		t.Fatalf("expected pending approval request in error data, got %#v", reply.Error.Data)
+	}
+	states, err := gate.ListApprovalRequests(queueDir, time.Now().UTC())
+	if err != nil || len(states) != 1 || states[0].Request.RequestID != requestID {
+		t.Fatalf("expected relayed call to enqueue one approval request, states=%#v err=%v", states, err)
+	}
+	if methods := upstreamMethods(); strings.Join(methods, ",") != "initialize" {
+		t.Fatalf("expected pending call not to reach upstream, got %v", methods)
+	}
+}
+
+func TestMCPServeRelaySessionsStreamServerMessages(t *testing.T) {
+	workDir := t.TempDir()
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, "default_verdict: allow\n")
+	upstream, _ := newFakeMCPUpstreamServer(t)
+	sessions, err := newMCPServeRelaySessions(mcpServeConfig{PolicyPath: policyPath, KeyMode: "dev", UpstreamURL: upstream.URL})
+	if err != nil {
+		t.Fatalf("new relay sessions: %v", err)
+	}
+	handler, err := newMCPServeHandler(mcpServeConfig{PolicyPath: policyPath, KeyMode: "dev", UpstreamURL: upstream.URL, RelaySessions: sessions})
+	if err != nil {
+		t.Fatalf("newMCPServeHandler: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	first, err := sessions.open(authn.Principal{Method: "jwt", Subject: "agent-a"})
+	if err != nil {
+		t.Fatalf("open session: %v", err)
+	}
+	second, err := sessions.open(authn.Principal{Method: "jwt", Subject: "agent-b"})
+	if err != nil {
+		t.Fatalf("open session: %v", err)
+	}
+	if first.id == second.id || first.upstream == second.upstream {
+		t.Fatalf("expected separate sessions with separate upstreams")
+	}
+	if _, ok := sessions.get(first.id, authn.Principal{Method: "jwt", Subject: "agent-b"}); ok {
+		t.Fatalf("expected session to be bound to the principal that opened it")
+	}
+
+	anonymous, err := sessions.open(authn.Principal{})
+	if err != nil {
+		t.Fatalf("open session: %v", err)
+	}
+	anonymous.deliver(mcp.RPCMessage{JSONRPC: "2.0", Method: "notifications/tools/list_changed"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/mcp", nil)
+	request.Header.Set("Mcp-Session-Id", anonymous.id)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("GET /mcp: %v", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	reader := bufio.NewReader(response.Body)
+	line, err := reader.ReadString('\n')
+	for err == nil && !strings.HasPrefix(line, "data: ") {
+		line, err = reader.ReadString('\n')
+	}
+	if response.Header.Get("Content-Type") != "text/event-stream" || !strings.Contains(line, "notifications/tools/list_changed") {
+		t.Fatalf("expected server message on the event stream, got %q err=%v", line, err)
+	}
+
+	sessions.Close()
+	if _, err := sessions.open(authn.Principal{}); err == nil {
+		t.Fatalf("expected closed relay sessions to reject new sessions")
+	}
+	if _, ok := sessions.get(first.id, authn.Principal{Method: "jwt", Subject: "agent-a"}); ok {
+		t.Fatalf("expected Close to drop open sessions")
+	}
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	argv, err := splitCommandLine(`node "my server.js" --name 'a b' c\ d "x\"y" "p\q"`)
+	if err != nil {
+		t.Fatalf("split: %v", err)
+	}
+	expected := []string{"node", "my server.js", "--name", "a b", "c d", `x"y`, `p\q`}
+	if strings.Join(argv, "|") != strings.Join(expected, "|") {
+		t.Fatalf("unexpected argv %q", argv)
+	}
+	if _, err := splitCommandLine(`node "unterminated`); err == nil {
+		t.Fatalf("expected unterminated quote to fail")
+	}
+}
+
+func TestMCPServeRelayUpstreamFlagsAreExclusive(t *testing.T) {
+	workDir := t.TempDir()
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, "default_verdict: allow\n")
+	code := runMCPServe([]string{"--policy", policyPath, "--upstream-url", "http://127.0.0.1:1/mcp", "--upstream-command", "server", "--json"})
+	if code != exitInvalidInput {
+		t.Fatalf("expected invalid input for conflicting upstreams, got %d", code)
+	}
+}
+
+func TestServeMCPRelayStdio(t *testing.T) {
+	workDir := t.TempDir()
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, strings.Join([]string{
+		"default_verdict: allow",
+		"rules:",
+		"  - name: approve-write",
+		"    effect: require_approval",
+		"    match:",
+		"      tool_names: [fs.write]",
+	}, "\n")+"\n")
+	upstream, upstreamMethods := newFakeMCPUpstreamServer(t)
+	config := mcpRelayConfig{
+		PolicyPath:  policyPath,
+		TraceDir:    filepath.Join(workDir, "traces"),
+		KeyMode:     "dev",
+		UpstreamURL: upstream.URL,
+	}
+	client, err := startMCPRelayUpstream(config, nil)
+	if err != nil {
+		t.Fatalf("start upstream: %v", err)
+	}
+	relay, err := newMCPRelay(config, client)
+	if err != nil {
+		t.Fatalf("new relay: %v", err)
+	}
+
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"fs.read"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"fs.write"}}`,
+		"",
+	}, "\n")
+	var output bytes.Buffer
+	if err := serveMCPRelayStdio(context.Background(), relay, "sess-stdio", strings.NewReader(input), &mcpRelayWriter{writer: &output}); err != nil {
+		t.Fatalf("serve stdio: %v", err)
+	}
+	replies := map[string]mcp.RPCMessage{}
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		var reply mcp.RPCMessage
+		if err := json.Unmarshal([]byte(line), &reply); err != nil {
+			t.Fatalf("decode reply %q: %v", line, err)
+		}
+		replies[string(reply.ID)] = reply
+	}
+	if reply := replies["1"]; reply.Error != nil || !strings.Contains(string(reply.Result), "upstream-ok") {
+		t.Fatalf("expected allowed reply, got %#v", reply)
+	}
+	if reply := replies["2"]; reply.Error == nil || reply.Error.Code != mcp.RPCErrorApprovalRequired {
+		t.Fatalf("expected approval required reply, got %#v", reply)
+	}
+	if methods := upstreamMethods(); len(methods) != 1 {
+		t.Fatalf("expected only the allowed call upstream, got %v", methods)
+	}
+}
+
+func TestMCPRelayNotifiesApprovalRequired(t *testing.T) {
+	workDir := t.TempDir()
+	t.Setenv("GAIT_TEST_WEBHOOK_SECRET", "hook-secret")
+	var mu sync.Mutex
+	received := []notify.Event{}
+	webhook := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		if err := notify.VerifySignature([]byte("hook-secret"), request.Header.Get(notify.HeaderTimestamp), request.Header.Get(notify.HeaderSignature), body, time.Now(), time.Minute); err != nil {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var event notify.Event
+		_ = json.Unmarshal(body, &event)
+		mu.Lock()
+		received = append(received, event)
+		mu.Unlock()
+	}))
+	defer webhook.Close()
+	notifier, err := notify.New(notify.Config{
+		Outbox: filepath.Join(workDir, "outbox"),
+		Sinks:  []notify.SinkConfig{{Name: "ops", Type: "webhook", URL: webhook.URL, SecretEnv: "GAIT_TEST_WEBHOOK_SECRET"}},
+	})
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, strings.Join([]string{
+		"default_verdict: allow",
+		"rules:",
+		"  - name: approve-write",
+		"    effect: require_approval",
+		"    match:",
+		"      tool_names: [fs.write]",
+	}, "\n")+"\n")
+	upstream, _ := newFakeMCPUpstreamServer(t)
+
+	config := mcpRelayConfig{
+		PolicyPath:  policyPath,
+		TraceDir:    filepath.Join(workDir, "traces"),
+		KeyMode:     "dev",
+		UpstreamURL: upstream.URL,
+		Notifier:    notifier,
+	}
+	client, err := startMCPRelayUpstream(config, nil)
+	if err != nil {
+		t.Fatalf("start upstream: %v", err)
+	}
+	relay, err := newMCPRelay(config, client)
+	if err != nil {
+		t.Fatalf("new relay: %v", err)
+	}
+	var output bytes.Buffer
+	input := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"fs.write"}}` + "\n"
+	if err := serveMCPRelayStdio(context.Background(), relay, "sess-stdio", strings.NewReader(input), &mcpRelayWriter{writer: &output}); err != nil {
+		t.Fatalf("serve stdio: %v", err)
+	}
+
+	handler, err := newMCPServeHandler(mcpServeConfig{
+		PolicyPath:     policyPath,
+		DefaultAdapter: "mcp",
+		TraceDir:       filepath.Join(workDir, "traces"),
+		KeyMode:        "dev",
+		UpstreamURL:    upstream.URL,
+		Notifier:       notifier,
+	})
+	if err != nil {
+		t.Fatalf("newMCPServeHandler: %v", err)
+	}
+	post := func(sessionID string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		if sessionID != "" {
+			request.Header.Set("Mcp-Session-Id", sessionID)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+	sessionID := post("", `{"jsonrpc":"2.0","id":0,"method":"initialize","params":{}}`).Header().Get("Mcp-Session-Id")
+	if sessionID == "" {
+		t.Fatalf("expected initialize to open a session")
+	}
+	post(sessionID, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"fs.write"}}`)
+
+	result, err := notifier.Flush(context.Background())
+	if err != nil || result.Delivered != 2 {
+		t.Fatalf("expected two relayed notifications delivered, result=%#v err=%v", result, err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, event := range received {
+		if event.Kind != notify.EventApprovalRequired || event.ToolName != "fs.write" {
+			t.Fatalf("unexpected relayed notification %#v", event)
+		}
+	}
+}
+
+type orderedMCPUpstream struct {
+	mu      sync.Mutex
+	methods []string
+}
+
+func (upstream *orderedMCPUpstream) record(method string) {
+	upstream.mu.Lock()
+	defer upstream.mu.Unlock()
+	upstream.methods = append(upstream.methods, method)
+}
+
+func (upstream *orderedMCPUpstream) Call(_ context.Context, request mcp.RPCMessage) (mcp.RPCMessage, error) {
+	if request.Method == "initialize" {
+		// A slow initialize gives later lines the chance to overtake it.
+		time.Sleep(20 * time.Millisecond)
+	}
+	upstream.record(request.Method)
+	return mcp.RPCMessage{JSONRPC: "2.0", ID: request.ID, Result: json.RawMessage(`{}`)}, nil
+}
+
+func (upstream *orderedMCPUpstream) Notify(_ context.Context, message mcp.RPCMessage) error {
+	upstream.record(message.Method)
+	return nil
+}
+
+func (upstream *orderedMCPUpstream) Close() error {
+	return nil
+}
+
+func TestServeMCPRelayStdioKeepsUpstreamOrder(t *testing.T) {
+	upstream := &orderedMCPUpstream{}
+	relay, err := mcp.NewRelay(mcp.RelayOptions{
+		Upstream: upstream,
+		Evaluate: func(context.Context, mcp.ToolCall) (mcp.CallDecision, error) {
+			return mcp.CallDecision{Verdict: "allow"}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("new relay: %v", err)
+	}
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":2}}`,
+		"",
+	}, "\n")
+	var output bytes.Buffer
+	if err := serveMCPRelayStdio(context.Background(), relay, "sess-order", strings.NewReader(input), &mcpRelayWriter{writer: &output}); err != nil {
+		t.Fatalf("serve stdio: %v", err)
+	}
+	expected := "initialize,notifications/initialized,tools/list,notifications/cancelled"
+	if got := strings.Join(upstream.methods, ","); got != expected {
+		t.Fatalf("expected upstream order %s, got %s", expected, got)
+	}
+}
+
+func TestRunMCPRelayValidationErrors(t *testing.T) {
+	if code := runMCPRelay([]string{"--json"}); code != exitInvalidInput {
+		t.Fatalf("expected missing policy to be invalid input, got %d", code)
+	}
+	workDir := t.TempDir()
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, "default_verdict: allow\n")
+	if code := runMCPRelay([]string{"--policy", policyPath, "--json"}); code != exitInvalidInput {
+		t.Fatalf("expected missing upstream to be invalid input, got %d", code)
+	}
+	if code := runMCPRelay([]string{"--policy", policyPath, "--upstream-url", "http://127.0.0.1:1/mcp", "--json", "--", "server"}); code != exitInvalidInput {
+		t.Fatalf("expected conflicting upstreams to be invalid input, got %d", code)
+	}
+}
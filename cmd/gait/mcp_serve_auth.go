@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Clyra-AI/gait/core/authn"
+	"github.com/Clyra-AI/gait/core/mcp"
+	"github.com/Clyra-AI/gait/core/projectconfig"
+)
+
+const (
+	mcpServeAuthOff   = "off"
+	mcpServeAuthToken = "token"
+	mcpServeAuthJWT   = "jwt"
+	mcpServeAuthMTLS  = "mtls"
+
+	mcpServeIdentityBindingOverride = "override"
+	mcpServeIdentityBindingStrict   = "strict"
+)
+
+type mcpServePrincipalContextKey struct{}
+
+// applyMCPServeConfigDefaults fills serve settings from mcp_serve in the
+// project config for every flag the caller did not set explicitly.
+func applyMCPServeConfigDefaults(defaults projectconfig.MCPServeDefaults, flagSet *flag.FlagSet, config *mcpServeConfig, authTokenEnv *string) {
+	explicit := map[string]bool{}
+	flagSet.Visit(func(set *flag.Flag) {
+		explicit[set.Name] = true
+	})
+	applyString := func(name string, target *string, value string) {
+		if !explicit[name] && value != "" {
+			*target = value
+		}
+	}
+	applyString("listen", &config.ListenAddr, defaults.Listen)
+	applyString("auth-mode", &config.AuthMode, defaults.AuthMode)
+	applyString("auth-token-env", authTokenEnv, defaults.AuthTokenEnv)
+	applyString("tls-cert", &config.TLSCertPath, defaults.TLSCert)
+	applyString("tls-key", &config.TLSKeyPath, defaults.TLSKey)
+	applyString("tls-client-ca", &config.TLSClientCAPath, defaults.TLSClientCA)
+	applyString("jwt-jwks", &config.JWTJWKSPath, defaults.JWT.JWKS)
+	applyString("jwt-issuer", &config.JWTIssuer, defaults.JWT.Issuer)
+	applyString("jwt-audience", &config.JWTAudience, defaults.JWT.Audience)
+	applyString("jwt-identity-claim", &config.JWTClaims.Identity, defaults.JWT.IdentityClaim)
+	applyString("jwt-workspace-claim", &config.JWTClaims.Workspace, defaults.JWT.WorkspaceClaim)
+	applyString("jwt-agent-claim", &config.JWTClaims.Agent, defaults.JWT.AgentClaim)
+	applyString("identity-binding", &config.IdentityBinding, defaults.IdentityBinding)
+	applyString("http-verdict-status", &config.HTTPVerdictStatus, defaults.HTTPVerdictStatus)
+	if !explicit["max-request-bytes"] && defaults.MaxRequestBytes > 0 {
+		config.MaxRequestBytes = defaults.MaxRequestBytes
+	}
+	if !explicit["allow-client-artifact-paths"] && defaults.AllowClientArtifactPaths {
+		config.AllowClientArtifactPaths = true
+	}
+}
+
+func validateMCPServeAuthConfig(config *mcpServeConfig, authTokenEnv string, isLoopback bool) error {
+	switch config.AuthMode {
+	case mcpServeAuthOff, mcpServeAuthToken, mcpServeAuthJWT, mcpServeAuthMTLS:
+	default:
+		return fmt.Errorf("unsupported --auth-mode value (expected off, token, jwt or mtls)")
+	}
+	if !isLoopback && config.AuthMode == mcpServeAuthOff {
+		return fmt.Errorf("non-loopback --listen requires --auth-mode token, jwt or mtls")
+	}
+	if (config.TLSCertPath == "") != (config.TLSKeyPath == "") {
+		return fmt.Errorf("--tls-cert and --tls-key must be set together")
+	}
+	switch config.AuthMode {
+	case mcpServeAuthToken:
+		if strings.TrimSpace(authTokenEnv) == "" {
+			return fmt.Errorf("--auth-mode token requires --auth-token-env")
+		}
+		tokenValue := strings.TrimSpace(os.Getenv(authTokenEnv))
+		if tokenValue == "" {
+			return fmt.Errorf("--auth-token-env did not resolve to a non-empty value")
+		}
+		config.AuthToken = tokenValue
+	case mcpServeAuthJWT:
+		if config.JWTJWKSPath == "" && config.JWTIssuer == "" {
+			return fmt.Errorf("--auth-mode jwt requires --jwt-jwks or --jwt-issuer")
+		}
+	case mcpServeAuthMTLS:
+		if config.TLSCertPath == "" || config.TLSClientCAPath == "" {
+			return fmt.Errorf("--auth-mode mtls requires --tls-cert, --tls-key and --tls-client-ca")
+		}
+	}
+	if config.TLSClientCAPath != "" && config.AuthMode != mcpServeAuthMTLS {
+		return fmt.Errorf("--tls-client-ca requires --auth-mode mtls")
+	}
+	if config.IdentityBinding == "" {
+		config.IdentityBinding = mcpServeIdentityBindingOverride
+	}
+	if config.IdentityBinding != mcpServeIdentityBindingOverride && config.IdentityBinding != mcpServeIdentityBindingStrict {
+		return fmt.Errorf("unsupported --identity-binding value (expected override or strict)")
+	}
+	if config.IdentityBinding == mcpServeIdentityBindingStrict && config.AuthMode != mcpServeAuthJWT && config.AuthMode != mcpServeAuthMTLS {
+		return fmt.Errorf("--identity-binding strict requires --auth-mode jwt or mtls")
+	}
+	if len(config.ApprovalApprovers) > 0 && config.AuthMode != mcpServeAuthJWT && config.AuthMode != mcpServeAuthMTLS {
+		return fmt.Errorf("--approval-approvers requires --auth-mode jwt or mtls")
+	}
+	return nil
+}
+
+func newMCPServeJWTVerifier(config mcpServeConfig) (*authn.JWTVerifier, error) {
+	var keys authn.KeySource
+	if config.JWTJWKSPath != "" {
+		keySet, err := authn.LoadKeySetFile(config.JWTJWKSPath)
+		if err != nil {
+			return nil, err
+		}
+		keys = keySet
+	} else {
+		keys = authn.NewIssuerKeySet(config.JWTIssuer, nil)
+	}
+	return &authn.JWTVerifier{
+		Keys:     keys,
+		Issuer:   config.JWTIssuer,
+		Audience: config.JWTAudience,
+		Claims:   config.JWTClaims,
+	}, nil
+}
+
+// newMCPServeTLSConfig returns nil when serve runs over plain HTTP. In mtls
+// mode the handshake rejects clients without a certificate chaining to the
+// configured client CA.
+func newMCPServeTLSConfig(config mcpServeConfig) (*tls.Config, error) {
+	if config.TLSCertPath == "" {
+		return nil, nil
+	}
+	certificate, err := tls.LoadX509KeyPair(config.TLSCertPath, config.TLSKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("load tls certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+	}
+	if config.AuthMode == mcpServeAuthMTLS {
+		// #nosec G304 -- client CA path is explicit local operator configuration.
+		rawCA, err := os.ReadFile(config.TLSClientCAPath)
+		if err != nil {
+			return nil, fmt.Errorf("read tls client ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(rawCA) {
+			return nil, fmt.Errorf("tls client ca contains no PEM certificates")
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// authorizeMCPServeRequest returns the authenticated principal. Token mode
+// authenticates the shared secret only, so it yields an empty principal.
+func authorizeMCPServeRequest(config mcpServeConfig, request *http.Request) (authn.Principal, error) {
+	switch strings.TrimSpace(config.AuthMode) {
+	case mcpServeAuthToken:
+		if strings.TrimSpace(config.AuthToken) == "" {
+			return authn.Principal{}, fmt.Errorf("auth token is not configured")
+		}
+		provided, err := mcpServeBearerToken(request)
+		if err != nil {
+			return authn.Principal{}, err
+		}
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(strings.TrimSpace(config.AuthToken))) != 1 {
+			return authn.Principal{}, fmt.Errorf("invalid bearer authorization")
+		}
+		return authn.Principal{}, nil
+	case mcpServeAuthJWT:
+		if config.JWTVerifier == nil {
+			return authn.Principal{}, fmt.Errorf("jwt verifier is not configured")
+		}
+		provided, err := mcpServeBearerToken(request)
+		if err != nil {
+			return authn.Principal{}, err
+		}
+		return config.JWTVerifier.Verify(request.Context(), provided)
+	case mcpServeAuthMTLS:
+		if request.TLS == nil || len(request.TLS.VerifiedChains) == 0 || len(request.TLS.VerifiedChains[0]) == 0 {
+			return authn.Principal{}, fmt.Errorf("verified client certificate is required")
+		}
+		return authn.PrincipalFromCertificate(request.TLS.VerifiedChains[0][0])
+	default:
+		return authn.Principal{}, nil
+	}
+}
+
+func mcpServeBearerToken(request *http.Request) (string, error) {
+	rawHeader := strings.TrimSpace(request.Header.Get("Authorization"))
+	if !strings.HasPrefix(rawHeader, "Bearer ") {
+		return "", fmt.Errorf("missing bearer authorization")
+	}
+	return strings.TrimSpace(strings.TrimPrefix(rawHeader, "Bearer ")), nil
+}
+
+func requireMCPServeAuth(config mcpServeConfig, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if _, err := authorizeMCPServeRequest(config, request); err != nil {
+			writeMCPServeError(writer, http.StatusUnauthorized, err.Error())
+			return
+		}
+		next.ServeHTTP(writer, request)
+	})
+}
+
+func withMCPServePrincipal(ctx context.Context, principal authn.Principal) context.Context {
+	if principal.Method == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, mcpServePrincipalContextKey{}, principal)
+}
+
+func mcpServePrincipalFromContext(ctx context.Context) authn.Principal {
+	principal, _ := ctx.Value(mcpServePrincipalContextKey{}).(authn.Principal)
+	return principal
+}
+
+// bindMCPServePrincipal replaces the self-asserted identity, workspace and
+// agent id in the call context with the authenticated principal. In strict
+// binding a payload value that disagrees with the principal is rejected.
+func bindMCPServePrincipal(call *mcp.ToolCall, principal authn.Principal, binding string) error {
+	if principal.Method == "" {
+		return nil
+	}
+	fields := []struct {
+		name   string
+		target *string
+		value  string
+	}{
+		{name: "identity", target: &call.Context.Identity, value: principal.Identity},
+		{name: "workspace", target: &call.Context.Workspace, value: principal.Workspace},
+		{name: "agent_id", target: &call.Context.AgentID, value: principal.AgentID},
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		asserted := strings.TrimSpace(*field.target)
+		if binding == mcpServeIdentityBindingStrict && asserted != "" && asserted != field.value {
+			return mcpServeRequestError{
+				Status:  http.StatusForbidden,
+				Message: fmt.Sprintf("call.context.%s %q does not match authenticated principal %q", field.name, asserted, field.value),
+			}
+		}
+		*field.target = field.value
+	}
+	if call.Context.AuthContext == nil {
+		call.Context.AuthContext = map[string]any{}
+	}
+	authenticated := map[string]any{"method": principal.Method, "identity": principal.Identity}
+	if principal.Subject != "" {
+		authenticated["subject"] = principal.Subject
+	}
+	if principal.Issuer != "" {
+		authenticated["issuer"] = principal.Issuer
+	}
+	call.Context.AuthContext["authenticated_principal"] = authenticated
+	return nil
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/authn"
+)
+
+const mcpServeAuthPolicy = `default_verdict: block
+rules:
+  - name: allow-alice-search
+    effect: allow
+    match:
+      tool_names: [tool.search]
+      identities: [alice@example.com]
+`
+
+const mcpServeAuthRequestBody = `{"call":{"name":"tool.search","args":{"query":"gait"},"context":{"identity":"mallory@example.com","workspace":"/repo/gait","session_id":"sess-1"}}}`
+
+func TestMCPServeHandlerJWTBindsAuthenticatedIdentity(t *testing.T) {
+	workDir := t.TempDir()
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, mcpServeAuthPolicy)
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	jwksPath := filepath.Join(workDir, "jwks.json")
+	mustWriteFile(t, jwksPath, `{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"k1","x":"`+base64.RawURLEncoding.EncodeToString(publicKey)+`"}]}`)
+	token := signMCPServeTestJWT(t, privateKey, map[string]any{
+		"iss":   "https://idp.example.com",
+		"sub":   "user-1",
+		"email": "alice@example.com",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	})
+
+	newHandler := func(binding string) http.Handler {
+		handler, err := newMCPServeHandler(mcpServeConfig{
+			PolicyPath:        policyPath,
+			DefaultAdapter:    "mcp",
+			TraceDir:          filepath.Join(workDir, "traces"),
+			AuthMode:          "jwt",
+			JWTJWKSPath:       jwksPath,
+			JWTIssuer:         "https://idp.example.com",
+			JWTClaims:         authn.ClaimMapping{Identity: "email"},
+			IdentityBinding:   binding,
+			MaxRequestBytes:   1 << 20,
+			HTTPVerdictStatus: "compat",
+			KeyMode:           "dev",
+		})
+		if err != nil {
+			t.Fatalf("newMCPServeHandler: %v", err)
+		}
+		return handler
+	}
+	evaluate := func(handler http.Handler, authorization string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/v1/evaluate", bytes.NewReader([]byte(mcpServeAuthRequestBody)))
+		request.Header.Set("content-type", "application/json")
+		if authorization != "" {
+			request.Header.Set("authorization", authorization)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	overrideHandler := newHandler("override")
+	recorder := evaluate(overrideHandler, "Bearer "+token)
+	var response mcpServeEvaluateResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v body=%s", err, recorder.Body.String())
+	}
+	if recorder.Code != http.StatusOK || response.Verdict != "allow" {
+		t.Fatalf("expected principal identity to replace payload identity, code=%d body=%s", recorder.Code, recorder.Body.String())
+	}
+	if recorder := evaluate(overrideHandler, ""); recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d without token got %d", http.StatusUnauthorized, recorder.Code)
+	}
+	if recorder := evaluate(overrideHandler, "Bearer "+token+"x"); recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d with tampered token got %d", http.StatusUnauthorized, recorder.Code)
+	}
+	if recorder := evaluate(newHandler("strict"), "Bearer "+token); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected %d for mismatched payload identity in strict binding got %d body=%s", http.StatusForbidden, recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestMCPServeMTLSMapsClientCertificateIdentity(t *testing.T) {
+	workDir := t.TempDir()
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, mcpServeAuthPolicy)
+
+	caKey, caCert := newMCPServeTestCertificate(t, nil, nil, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "gait test ca"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	serverKey, serverCert := newMCPServeTestCertificate(t, caKey, caCert, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	clientKey, clientCert := newMCPServeTestCertificate(t, caKey, caCert, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "alice@example.com", OrganizationalUnit: []string{"/repo/gait"}},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	caPath := filepath.Join(workDir, "ca.pem")
+	certPath := filepath.Join(workDir, "server.pem")
+	keyPath := filepath.Join(workDir, "server.key")
+	mustWriteFile(t, caPath, string(pemEncode("CERTIFICATE", caCert.Raw)))
+	mustWriteFile(t, certPath, string(pemEncode("CERTIFICATE", serverCert.Raw)))
+	mustWriteFile(t, keyPath, string(pemEncode("PRIVATE KEY", mustMarshalPKCS8(t, serverKey))))
+
+	config := mcpServeConfig{
+		PolicyPath:        policyPath,
+		DefaultAdapter:    "mcp",
+		TraceDir:          filepath.Join(workDir, "traces"),
+		AuthMode:          "mtls",
+		TLSCertPath:       certPath,
+		TLSKeyPath:        keyPath,
+		TLSClientCAPath:   caPath,
+		IdentityBinding:   "override",
+		MaxRequestBytes:   1 << 20,
+		HTTPVerdictStatus: "compat",
+		KeyMode:           "dev",
+	}
+	tlsConfig, err := newMCPServeTLSConfig(config)
+	if err != nil {
+		t.Fatalf("newMCPServeTLSConfig: %v", err)
+	}
+	handler, err := newMCPServeHandler(config)
+	if err != nil {
+		t.Fatalf("newMCPServeHandler: %v", err)
+	}
+	server := httptest.NewUnstartedServer(handler)
+	server.TLS = tlsConfig
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{{Certificate: [][]byte{clientCert.Raw}, PrivateKey: clientKey}},
+	}}}
+	response, err := client.Post(server.URL+"/v1/evaluate", "application/json", bytes.NewReader([]byte(mcpServeAuthRequestBody)))
+	if err != nil {
+		t.Fatalf("post with client certificate: %v", err)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	var output mcpServeEvaluateResponse
+	if err := json.NewDecoder(response.Body).Decode(&output); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if response.StatusCode != http.StatusOK || output.Verdict != "allow" {
+		t.Fatalf("expected certificate identity to be evaluated, status=%d output=%#v", response.StatusCode, output)
+	}
+
+	anonymous := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: roots}}}
+	if anonymousResponse, err := anonymous.Post(server.URL+"/v1/evaluate", "application/json", bytes.NewReader([]byte(mcpServeAuthRequestBody))); err == nil {
+		_ = anonymousResponse.Body.Close()
+		t.Fatalf("expected handshake failure without client certificate, got status %d", anonymousResponse.StatusCode)
+	}
+}
+
+func signMCPServeTestJWT(t *testing.T, privateKey ed25519.PrivateKey, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "EdDSA", "kid": "k1", "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(privateKey, []byte(signed)))
+}
+
+func newMCPServeTestCertificate(t *testing.T, parentKey *ecdsa.PrivateKey, parent *x509.Certificate, template *x509.Certificate) (*ecdsa.PrivateKey, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate certificate key: %v", err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatalf("generate serial: %v", err)
+	}
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	certificate, err := x509.ParseCertificate(raw)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	return key, certificate
+}
+
+func mustMarshalPKCS8(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	t.Helper()
+	raw, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	return raw
+}
+
+func pemEncode(blockType string, raw []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: raw})
+}
@@ -0,0 +1,400 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/authn"
+	"github.com/Clyra-AI/gait/core/mcp"
+)
+
+const (
+	mcpServeRelaySessionHeader  = "Mcp-Session-Id"
+	mcpServeMaxRelaySessions    = 64
+	mcpServeRelayIdleTimeout    = 30 * time.Minute
+	mcpServeRelayMessageBacklog = 64
+)
+
+var (
+	errMCPServeRelayClosed   = errors.New("mcp relay is shutting down")
+	errMCPServeRelaySessions = fmt.Errorf("mcp relay session limit (%d) reached", mcpServeMaxRelaySessions)
+)
+
+// mcpServeRelaySession is one /mcp client session. Each session owns its own
+// upstream connection so one client's upstream state and server-initiated
+// messages never reach another client.
+type mcpServeRelaySession struct {
+	id        string
+	principal string
+	relay     *mcp.Relay
+	upstream  mcp.Upstream
+	messages  chan mcp.RPCMessage
+
+	mu       sync.Mutex
+	lastUsed time.Time
+}
+
+type mcpServeRelaySessions struct {
+	config mcpRelayConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*mcpServeRelaySession
+	closed   bool
+}
+
+func newMCPServeRelaySessions(config mcpServeConfig) (*mcpServeRelaySessions, error) {
+	upstreamCommand, err := splitCommandLine(config.UpstreamCommand)
+	if err != nil {
+		return nil, fmt.Errorf("parse --upstream-command: %w", err)
+	}
+	return &mcpServeRelaySessions{
+		config: mcpRelayConfig{
+			PolicyPath:          config.PolicyPath,
+			Profile:             config.Profile,
+			JobRoot:             config.JobRoot,
+			KillSwitchStatePath: config.KillSwitchStatePath,
+			KillSwitchSource:    config.KillSwitchSource,
+			TraceDir:            config.TraceDir,
+			LogExportPath:       config.LogExportPath,
+			OTelExport:          config.OTelExport,
+			KeyMode:             config.KeyMode,
+			PrivateKey:          config.PrivateKey,
+			PrivateKeyEnv:       config.PrivateKeyEnv,
+			UpstreamURL:         config.UpstreamURL,
+			UpstreamCommand:     upstreamCommand,
+			ServerID:            config.UpstreamServerID,
+			ToolAnnotations:     config.ToolAnnotations,
+			RateLimitStore:      config.RateLimitStore,
+			IdentityBinding:     config.IdentityBinding,
+			ApprovalQueueDir:    config.ApprovalQueueDir,
+			ApprovalKeyPair:     config.ApprovalKeyPair,
+			Notifier:            config.Notifier,
+			Telemetry:           config.Telemetry,
+		},
+		now:      time.Now,
+		sessions: map[string]*mcpServeRelaySession{},
+	}, nil
+}
+
+// open starts a new session with its own upstream. Sessions are bound to the
+// authenticated principal that created them.
+func (sessions *mcpServeRelaySessions) open(principal authn.Principal) (*mcpServeRelaySession, error) {
+	sessions.mu.Lock()
+	if sessions.closed {
+		sessions.mu.Unlock()
+		return nil, errMCPServeRelayClosed
+	}
+	expired := sessions.expireLocked()
+	full := len(sessions.sessions) >= mcpServeMaxRelaySessions
+	sessions.mu.Unlock()
+	closeMCPServeRelaySessions(expired)
+	if full {
+		return nil, errMCPServeRelaySessions
+	}
+
+	id, err := newMCPServeRelaySessionID()
+	if err != nil {
+		return nil, err
+	}
+	session := &mcpServeRelaySession{
+		id:        id,
+		principal: mcpServeRelayPrincipalKey(principal),
+		messages:  make(chan mcp.RPCMessage, mcpServeRelayMessageBacklog),
+		lastUsed:  sessions.now(),
+	}
+	upstream, err := startMCPRelayUpstream(sessions.config, session.deliver)
+	if err != nil {
+		return nil, err
+	}
+	relay, err := newMCPRelay(sessions.config, upstream)
+	if err != nil {
+		_ = upstream.Close()
+		return nil, err
+	}
+	session.upstream = upstream
+	session.relay = relay
+
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+	if sessions.closed || len(sessions.sessions) >= mcpServeMaxRelaySessions {
+		_ = upstream.Close()
+		if sessions.closed {
+			return nil, errMCPServeRelayClosed
+		}
+		return nil, errMCPServeRelaySessions
+	}
+	sessions.sessions[id] = session
+	return session, nil
+}
+
+func (sessions *mcpServeRelaySessions) get(id string, principal authn.Principal) (*mcpServeRelaySession, bool) {
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+	session, ok := sessions.sessions[id]
+	if !ok || session.principal != mcpServeRelayPrincipalKey(principal) {
+		return nil, false
+	}
+	session.touch(sessions.now())
+	return session, true
+}
+
+func (sessions *mcpServeRelaySessions) remove(id string, principal authn.Principal) bool {
+	sessions.mu.Lock()
+	session, ok := sessions.sessions[id]
+	if ok && session.principal == mcpServeRelayPrincipalKey(principal) {
+		delete(sessions.sessions, id)
+	} else {
+		ok = false
+	}
+	sessions.mu.Unlock()
+	if ok {
+		closeMCPServeRelaySessions([]*mcpServeRelaySession{session})
+	}
+	return ok
+}
+
+// Close shuts down every session upstream; later requests are rejected.
+func (sessions *mcpServeRelaySessions) Close() {
+	if sessions == nil {
+		return
+	}
+	sessions.mu.Lock()
+	sessions.closed = true
+	open := make([]*mcpServeRelaySession, 0, len(sessions.sessions))
+	for id, session := range sessions.sessions {
+		open = append(open, session)
+		delete(sessions.sessions, id)
+	}
+	sessions.mu.Unlock()
+	closeMCPServeRelaySessions(open)
+}
+
+func (sessions *mcpServeRelaySessions) expireLocked() []*mcpServeRelaySession {
+	now := sessions.now()
+	expired := []*mcpServeRelaySession{}
+	for id, session := range sessions.sessions {
+		if now.Sub(session.idleSince()) >= mcpServeRelayIdleTimeout {
+			expired = append(expired, session)
+			delete(sessions.sessions, id)
+		}
+	}
+	return expired
+}
+
+func closeMCPServeRelaySessions(sessions []*mcpServeRelaySession) {
+	for _, session := range sessions {
+		_ = session.upstream.Close()
+	}
+}
+
+// deliver queues a server-initiated message for GET /mcp. When the client is
+// not reading, the oldest queued message is dropped.
+func (session *mcpServeRelaySession) deliver(message mcp.RPCMessage) {
+	for {
+		select {
+		case session.messages <- message:
+			return
+		default:
+		}
+		select {
+		case <-session.messages:
+		default:
+		}
+	}
+}
+
+func (session *mcpServeRelaySession) touch(now time.Time) {
+	session.mu.Lock()
+	session.lastUsed = now
+	session.mu.Unlock()
+}
+
+func (session *mcpServeRelaySession) idleSince() time.Time {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.lastUsed
+}
+
+func registerMCPServeRelayRoutes(mux *http.ServeMux, config mcpServeConfig, sessions *mcpServeRelaySessions) {
+	mux.HandleFunc("/mcp", func(writer http.ResponseWriter, request *http.Request) {
+		principal, err := authorizeMCPServeRequest(config, request)
+		if err != nil {
+			writeMCPServeError(writer, http.StatusUnauthorized, err.Error())
+			return
+		}
+		sessionID := strings.TrimSpace(request.Header.Get(mcpServeRelaySessionHeader))
+		switch request.Method {
+		case http.MethodPost:
+			handleMCPServeRelayPost(writer, request, config, sessions, principal, sessionID)
+		case http.MethodGet:
+			session, ok := sessions.get(sessionID, principal)
+			if !ok {
+				writeMCPServeError(writer, http.StatusNotFound, "unknown mcp session")
+				return
+			}
+			streamMCPServeRelayMessages(writer, request, session)
+		case http.MethodDelete:
+			if !sessions.remove(sessionID, principal) {
+				writeMCPServeError(writer, http.StatusNotFound, "unknown mcp session")
+				return
+			}
+			writer.WriteHeader(http.StatusNoContent)
+		default:
+			writer.Header().Set("Allow", strings.Join([]string{http.MethodPost, http.MethodGet, http.MethodDelete}, ", "))
+			writeMCPServeError(writer, http.StatusMethodNotAllowed, "expected POST, GET or DELETE")
+		}
+	})
+}
+
+func handleMCPServeRelayPost(writer http.ResponseWriter, request *http.Request, config mcpServeConfig, sessions *mcpServeRelaySessions, principal authn.Principal, sessionID string) {
+	if err := ensureMCPServeContentType(request); err != nil {
+		writeMCPServeError(writer, mcpServeErrorStatus(err), err.Error())
+		return
+	}
+	request.Body = http.MaxBytesReader(writer, request.Body, config.MaxRequestBytes)
+	payload, err := io.ReadAll(request.Body)
+	_ = request.Body.Close()
+	if err != nil {
+		writeMCPServeError(writer, http.StatusRequestEntityTooLarge, "request body exceeds max-request-bytes")
+		return
+	}
+	var session *mcpServeRelaySession
+	if sessionID == "" {
+		message, decodeErr := mcp.DecodeRPCMessage(payload)
+		if decodeErr != nil || message.Method != "initialize" {
+			writeMCPServeError(writer, http.StatusBadRequest, "missing "+mcpServeRelaySessionHeader+"; start a session with initialize")
+			return
+		}
+		session, err = sessions.open(principal)
+		if err != nil {
+			writeMCPServeError(writer, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+	} else {
+		var ok bool
+		session, ok = sessions.get(sessionID, principal)
+		if !ok {
+			writeMCPServeError(writer, http.StatusNotFound, "unknown mcp session")
+			return
+		}
+	}
+	writer.Header().Set(mcpServeRelaySessionHeader, session.id)
+	reply := session.relay.Handle(withMCPServePrincipal(request.Context(), principal), session.id, payload)
+	if reply == nil {
+		writer.WriteHeader(http.StatusAccepted)
+		return
+	}
+	writer.Header().Set("content-type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	_, _ = writer.Write(reply)
+}
+
+// streamMCPServeRelayMessages serves the session's server-initiated messages
+// as a text/event-stream until the client disconnects.
+func streamMCPServeRelayMessages(writer http.ResponseWriter, request *http.Request, session *mcpServeRelaySession) {
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		writeMCPServeError(writer, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+	writer.Header().Set("content-type", "text/event-stream")
+	writer.Header().Set("cache-control", "no-cache")
+	writer.Header().Set(mcpServeRelaySessionHeader, session.id)
+	writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case <-request.Context().Done():
+			return
+		case message := <-session.messages:
+			encoded, err := json.Marshal(message)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(writer, "event: message\ndata: %s\n\n", encoded); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func mcpServeRelayPrincipalKey(principal authn.Principal) string {
+	return strings.Join([]string{principal.Method, principal.Issuer, principal.Subject, principal.Identity}, "\x00")
+}
+
+func newMCPServeRelaySessionID() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate mcp session id: %w", err)
+	}
+	return "mcps_" + hex.EncodeToString(raw), nil
+}
+
+// splitCommandLine splits a command line into argv the way a POSIX shell
+// would for plain words: single quotes are literal, double quotes allow
+// backslash escapes, and nothing is expanded.
+func splitCommandLine(commandLine string) ([]string, error) {
+	argv := []string{}
+	var current strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, char := range commandLine {
+		switch {
+		case escaped:
+			// Inside double quotes a backslash only escapes $ ` " \ and newline.
+			if quote == '"' && !strings.ContainsRune("$`\"\\\n", char) {
+				current.WriteRune('\\')
+			}
+			current.WriteRune(char)
+			escaped = false
+		case quote == '\'':
+			if char == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(char)
+			}
+		case quote == '"':
+			switch char {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				current.WriteRune(char)
+			}
+		case char == '\\':
+			escaped = true
+			inWord = true
+		case char == '\'' || char == '"':
+			quote = char
+			inWord = true
+		case char == ' ' || char == '\t' || char == '\n':
+			if inWord {
+				argv = append(argv, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(char)
+			inWord = true
+		}
+	}
+	if escaped || quote != 0 {
+		return nil, fmt.Errorf("unterminated quote or escape in %q", commandLine)
+	}
+	if inWord {
+		argv = append(argv, current.String())
+	}
+	return argv, nil
+}
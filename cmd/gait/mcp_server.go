@@ -1,7 +1,7 @@
 package main
 
 import (
-	"crypto/subtle"
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -11,15 +11,24 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"sort"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/Clyra-AI/gait/core/authn"
+	"github.com/Clyra-AI/gait/core/gate"
 	"github.com/Clyra-AI/gait/core/jobruntime"
+	"github.com/Clyra-AI/gait/core/mcp"
+	"github.com/Clyra-AI/gait/core/notify"
+	"github.com/Clyra-AI/gait/core/otlp"
+	"github.com/Clyra-AI/gait/core/projectconfig"
 	"github.com/Clyra-AI/gait/core/runpack"
 	schemacommon "github.com/Clyra-AI/gait/core/schema/v1/common"
 	schemacontext "github.com/Clyra-AI/gait/core/schema/v1/context"
+	sign "github.com/Clyra-AI/proof/signing"
 )
 
 type mcpServeConfig struct {
@@ -31,8 +40,18 @@ type mcpServeConfig struct {
 	Profile                  string
 	JobRoot                  string
 	KillSwitchStatePath      string
+	KillSwitchSource         killSwitchSourceFlags
 	AuthMode                 string
 	AuthToken                string // #nosec G117 -- field name is explicit config surface, not a hardcoded secret.
+	TLSCertPath              string
+	TLSKeyPath               string
+	TLSClientCAPath          string
+	JWTJWKSPath              string
+	JWTIssuer                string
+	JWTAudience              string
+	JWTClaims                authn.ClaimMapping
+	JWTVerifier              *authn.JWTVerifier
+	IdentityBinding          string
 	TraceDir                 string
 	RunpackDir               string
 	PackDir                  string
@@ -53,6 +72,24 @@ type mcpServeConfig struct {
 	KeyMode                  string
 	PrivateKey               string // #nosec G117 -- field name is explicit config surface, not a hardcoded secret.
 	PrivateKeyEnv            string
+	UpstreamURL              string
+	UpstreamCommand          string
+	UpstreamServerID         string
+	ToolAnnotationsPath      string
+	ToolAnnotationsPublicKey string
+	ToolAnnotationsKeyEnv    string
+	ToolAnnotations          *mcp.ToolAnnotationSnapshot
+	RateLimitStatePath       string
+	RateLimitURL             string
+	RateLimitTokenEnv        string
+	RateLimitService         bool
+	RateLimitStore           gate.RateLimitStore
+	ApprovalQueueDir         string
+	ApprovalApprovers        []string
+	ApprovalKeyPair          sign.KeyPair
+	Notifier                 *notify.Notifier
+	Telemetry                *otlp.Exporter
+	RelaySessions            *mcpServeRelaySessions
 }
 
 type mcpServeEvaluateRequest struct {
@@ -88,34 +125,62 @@ func runMCPServe(arguments []string) int {
 		return writeExplain("Run a local interception service that evaluates tool-call payloads through Gate and emits signed traces across JSON, SSE, and streamable HTTP endpoints.")
 	}
 	arguments = reorderInterspersedFlags(arguments, map[string]bool{
-		"policy":                      true,
-		"context-envelope":            true,
-		"listen":                      true,
-		"adapter":                     true,
-		"profile":                     true,
-		"job-root":                    true,
-		"auth-mode":                   true,
-		"auth-token-env":              true,
-		"trace-dir":                   true,
-		"runpack-dir":                 true,
-		"pack-dir":                    true,
-		"session-dir":                 true,
-		"max-request-bytes":           true,
-		"http-verdict-status":         true,
-		"allow-client-artifact-paths": false,
-		"trace-max-age":               true,
-		"trace-max-count":             true,
-		"runpack-max-age":             true,
-		"runpack-max-count":           true,
-		"pack-max-age":                true,
-		"pack-max-count":              true,
-		"session-max-age":             true,
-		"session-max-count":           true,
-		"export-log-out":              true,
-		"export-otel-out":             true,
-		"key-mode":                    true,
-		"private-key":                 true,
-		"private-key-env":             true,
+		"policy":                          true,
+		"context-envelope":                true,
+		"listen":                          true,
+		"adapter":                         true,
+		"profile":                         true,
+		"job-root":                        true,
+		"kill-switch-state":               true,
+		"kill-switch-public-key":          true,
+		"kill-switch-public-key-env":      true,
+		"kill-switch-cache":               true,
+		"kill-switch-max-stale":           true,
+		"auth-mode":                       true,
+		"auth-token-env":                  true,
+		"tls-cert":                        true,
+		"tls-key":                         true,
+		"tls-client-ca":                   true,
+		"jwt-jwks":                        true,
+		"jwt-issuer":                      true,
+		"jwt-audience":                    true,
+		"jwt-identity-claim":              true,
+		"jwt-workspace-claim":             true,
+		"jwt-agent-claim":                 true,
+		"identity-binding":                true,
+		"config":                          true,
+		"trace-dir":                       true,
+		"runpack-dir":                     true,
+		"pack-dir":                        true,
+		"session-dir":                     true,
+		"max-request-bytes":               true,
+		"http-verdict-status":             true,
+		"allow-client-artifact-paths":     false,
+		"trace-max-age":                   true,
+		"trace-max-count":                 true,
+		"runpack-max-age":                 true,
+		"runpack-max-count":               true,
+		"pack-max-age":                    true,
+		"pack-max-count":                  true,
+		"session-max-age":                 true,
+		"session-max-count":               true,
+		"export-log-out":                  true,
+		"export-otel-out":                 true,
+		"key-mode":                        true,
+		"private-key":                     true,
+		"private-key-env":                 true,
+		"upstream-url":                    true,
+		"upstream-command":                true,
+		"upstream-server-id":              true,
+		"tool-annotations":                true,
+		"tool-annotations-public-key":     true,
+		"tool-annotations-public-key-env": true,
+		"rate-limit-state":                true,
+		"rate-limit-url":                  true,
+		"rate-limit-token-env":            true,
+		"rate-limit-service":              false,
+		"approval-queue":                  true,
+		"approval-approvers":              true,
 	})
 	flagSet := flag.NewFlagSet("mcp-serve", flag.ContinueOnError)
 	flagSet.SetOutput(io.Discard)
@@ -127,8 +192,20 @@ func runMCPServe(arguments []string) int {
 	var profile string
 	var jobRoot string
 	var killSwitchStatePath string
+	var killSwitchSource killSwitchSourceFlags
 	var authMode string
 	var authTokenEnv string
+	var tlsCertPath string
+	var tlsKeyPath string
+	var tlsClientCAPath string
+	var jwtJWKSPath string
+	var jwtIssuer string
+	var jwtAudience string
+	var jwtIdentityClaim string
+	var jwtWorkspaceClaim string
+	var jwtAgentClaim string
+	var identityBinding string
+	var configPath string
 	var traceDir string
 	var runpackDir string
 	var packDir string
@@ -149,6 +226,18 @@ func runMCPServe(arguments []string) int {
 	var keyMode string
 	var privateKeyPath string
 	var privateKeyEnv string
+	var upstreamURL string
+	var upstreamCommand string
+	var upstreamServerID string
+	var toolAnnotationsPath string
+	var toolAnnotationsPublicKey string
+	var toolAnnotationsPublicKeyEnv string
+	var rateLimitState string
+	var rateLimitURL string
+	var rateLimitTokenEnv string
+	var rateLimitService bool
+	var approvalQueueDir string
+	var approvalApprovers string
 	var jsonOutput bool
 	var helpFlag bool
 
@@ -157,10 +246,22 @@ func runMCPServe(arguments []string) int {
 	flagSet.StringVar(&listenAddr, "listen", "127.0.0.1:8787", "listen address")
 	flagSet.StringVar(&adapter, "adapter", "mcp", "default adapter: mcp|openai|anthropic|langchain|claude_code")
 	flagSet.StringVar(&profile, "profile", "standard", "runtime profile: standard|oss-prod")
-	flagSet.StringVar(&jobRoot, "job-root", "./gait-out/jobs", "job runtime root for emergency stop preemption checks when context.job_id is present")
-	flagSet.StringVar(&killSwitchStatePath, "kill-switch-state", "", "path to generalized kill-switch state JSON")
-	flagSet.StringVar(&authMode, "auth-mode", "off", "serve auth mode: off|token")
+	flagSet.StringVar(&jobRoot, "job-root", "./gait-out/jobs", "job runtime root for emergency stop preemption and job constraint checks when context.job_id is present")
+	flagSet.StringVar(&killSwitchStatePath, "kill-switch-state", "", "path or http(s) URL of generalized kill-switch state JSON")
+	bindKillSwitchSourceFlags(flagSet, &killSwitchSource)
+	flagSet.StringVar(&authMode, "auth-mode", "off", "serve auth mode: off|token|jwt|mtls")
 	flagSet.StringVar(&authTokenEnv, "auth-token-env", "", "env var containing bearer token for --auth-mode token")
+	flagSet.StringVar(&tlsCertPath, "tls-cert", "", "PEM server certificate; serves HTTPS when set")
+	flagSet.StringVar(&tlsKeyPath, "tls-key", "", "PEM server private key for --tls-cert")
+	flagSet.StringVar(&tlsClientCAPath, "tls-client-ca", "", "PEM CA bundle that client certificates must chain to in --auth-mode mtls")
+	flagSet.StringVar(&jwtJWKSPath, "jwt-jwks", "", "local JWKS file used to verify bearer JWTs in --auth-mode jwt")
+	flagSet.StringVar(&jwtIssuer, "jwt-issuer", "", "required JWT issuer; keys are discovered from the issuer when --jwt-jwks is unset")
+	flagSet.StringVar(&jwtAudience, "jwt-audience", "", "required JWT audience")
+	flagSet.StringVar(&jwtIdentityClaim, "jwt-identity-claim", authn.DefaultIdentityClaim, "JWT claim mapped onto context.identity")
+	flagSet.StringVar(&jwtWorkspaceClaim, "jwt-workspace-claim", "", "optional JWT claim mapped onto context.workspace")
+	flagSet.StringVar(&jwtAgentClaim, "jwt-agent-claim", "", "optional JWT claim mapped onto context.agent_id")
+	flagSet.StringVar(&identityBinding, "identity-binding", "", "authenticated principal binding: override|strict (strict rejects payload identity that disagrees)")
+	flagSet.StringVar(&configPath, "config", projectconfig.DefaultPath, "path to project defaults yaml")
 	flagSet.StringVar(&traceDir, "trace-dir", "./gait-out/mcp-serve/traces", "directory for emitted traces")
 	flagSet.StringVar(&runpackDir, "runpack-dir", "", "optional directory for emitted runpacks")
 	flagSet.StringVar(&packDir, "pack-dir", "", "optional directory for emitted PackSpec artifacts")
@@ -181,6 +282,18 @@ func runMCPServe(arguments []string) int {
 	flagSet.StringVar(&keyMode, "key-mode", "dev", "signing key mode: dev or prod")
 	flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private signing key")
 	flagSet.StringVar(&privateKeyEnv, "private-key-env", "", "env var containing base64 private signing key")
+	flagSet.StringVar(&upstreamURL, "upstream-url", "", "optional streamable HTTP endpoint of an upstream MCP server exposed through POST /mcp")
+	flagSet.StringVar(&upstreamCommand, "upstream-command", "", "optional stdio upstream MCP server command exposed through POST /mcp")
+	flagSet.StringVar(&upstreamServerID, "upstream-server-id", "", "upstream server identity used for mcp_trust evaluation on /mcp")
+	flagSet.StringVar(&toolAnnotationsPath, "tool-annotations", "", "signed tool annotation snapshot; target hints come from the snapshot instead of the call payload")
+	flagSet.StringVar(&toolAnnotationsPublicKey, "tool-annotations-public-key", "", "path to base64 tool annotation snapshot verify key")
+	flagSet.StringVar(&toolAnnotationsPublicKeyEnv, "tool-annotations-public-key-env", "", "env var containing base64 tool annotation snapshot verify key")
+	flagSet.StringVar(&rateLimitState, "rate-limit-state", "", "optional path to persisted rate limit state; enables rate_limit and destructive_budget enforcement")
+	flagSet.StringVar(&rateLimitURL, "rate-limit-url", "", "optional shared rate limit service URL hosted by another replica")
+	flagSet.StringVar(&rateLimitTokenEnv, "rate-limit-token-env", "", "env var containing bearer token for --rate-limit-url")
+	flagSet.BoolVar(&rateLimitService, "rate-limit-service", false, "host the shared rate limit service on POST /v1/rate-limit/consume backed by this replica's store")
+	flagSet.StringVar(&approvalQueueDir, "approval-queue", "", "approval queue directory; require_approval verdicts enqueue signed requests decided via /v1/approvals")
+	flagSet.StringVar(&approvalApprovers, "approval-approvers", "", "comma-separated jwt/mtls principal identities allowed to grant or deny over /v1/approvals; decisions over HTTP are disabled when unset")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit startup JSON")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
 
@@ -208,7 +321,15 @@ func runMCPServe(arguments []string) int {
 		Profile:                  strings.ToLower(strings.TrimSpace(profile)),
 		JobRoot:                  strings.TrimSpace(jobRoot),
 		KillSwitchStatePath:      strings.TrimSpace(killSwitchStatePath),
+		KillSwitchSource:         killSwitchSource,
 		AuthMode:                 strings.ToLower(strings.TrimSpace(authMode)),
+		TLSCertPath:              strings.TrimSpace(tlsCertPath),
+		TLSKeyPath:               strings.TrimSpace(tlsKeyPath),
+		TLSClientCAPath:          strings.TrimSpace(tlsClientCAPath),
+		JWTJWKSPath:              strings.TrimSpace(jwtJWKSPath),
+		JWTIssuer:                strings.TrimSpace(jwtIssuer),
+		JWTAudience:              strings.TrimSpace(jwtAudience),
+		IdentityBinding:          strings.ToLower(strings.TrimSpace(identityBinding)),
 		TraceDir:                 strings.TrimSpace(traceDir),
 		RunpackDir:               strings.TrimSpace(runpackDir),
 		PackDir:                  strings.TrimSpace(packDir),
@@ -225,6 +346,31 @@ func runMCPServe(arguments []string) int {
 		KeyMode:                  strings.TrimSpace(keyMode),
 		PrivateKey:               strings.TrimSpace(privateKeyPath),
 		PrivateKeyEnv:            strings.TrimSpace(privateKeyEnv),
+		UpstreamURL:              strings.TrimSpace(upstreamURL),
+		UpstreamCommand:          strings.TrimSpace(upstreamCommand),
+		UpstreamServerID:         strings.TrimSpace(upstreamServerID),
+		ToolAnnotationsPath:      strings.TrimSpace(toolAnnotationsPath),
+		ToolAnnotationsPublicKey: strings.TrimSpace(toolAnnotationsPublicKey),
+		ToolAnnotationsKeyEnv:    strings.TrimSpace(toolAnnotationsPublicKeyEnv),
+		RateLimitStatePath:       strings.TrimSpace(rateLimitState),
+		RateLimitURL:             strings.TrimSpace(rateLimitURL),
+		RateLimitTokenEnv:        strings.TrimSpace(rateLimitTokenEnv),
+		RateLimitService:         rateLimitService,
+		ApprovalQueueDir:         strings.TrimSpace(approvalQueueDir),
+		ApprovalApprovers:        parseCSV(approvalApprovers),
+	}
+	config.JWTClaims = authn.ClaimMapping{
+		Identity:  strings.TrimSpace(jwtIdentityClaim),
+		Workspace: strings.TrimSpace(jwtWorkspaceClaim),
+		Agent:     strings.TrimSpace(jwtAgentClaim),
+	}
+	projectConfig, err := projectconfig.Load(configPath, isDefaultProjectConfigPath(configPath))
+	if err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
+	applyMCPServeConfigDefaults(projectConfig.MCPServe, flagSet, &config, &authTokenEnv)
+	if config.UpstreamURL != "" && config.UpstreamCommand != "" {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: "--upstream-url and --upstream-command are mutually exclusive"}, exitInvalidInput)
 	}
 	traceMaxAge, parseTraceErr := parseOptionalDuration(traceMaxAgeRaw)
 	if parseTraceErr != nil {
@@ -246,25 +392,12 @@ func runMCPServe(arguments []string) int {
 	config.RunpackMaxAge = runpackMaxAge
 	config.PackMaxAge = packMaxAge
 	config.SessionMaxAge = sessionMaxAge
-	if config.AuthMode != "off" && config.AuthMode != "token" {
-		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: "unsupported --auth-mode value (expected off or token)"}, exitInvalidInput)
-	}
 	isLoopback, loopbackErr := mcpServeIsLoopbackListen(config.ListenAddr)
 	if loopbackErr != nil {
 		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: loopbackErr.Error()}, exitInvalidInput)
 	}
-	if !isLoopback && config.AuthMode != "token" {
-		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: "non-loopback --listen requires --auth-mode token"}, exitInvalidInput)
-	}
-	if config.AuthMode == "token" {
-		if strings.TrimSpace(authTokenEnv) == "" {
-			return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: "--auth-mode token requires --auth-token-env"}, exitInvalidInput)
-		}
-		tokenValue := strings.TrimSpace(os.Getenv(authTokenEnv))
-		if tokenValue == "" {
-			return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: "--auth-token-env did not resolve to a non-empty value"}, exitInvalidInput)
-		}
-		config.AuthToken = tokenValue
+	if err := validateMCPServeAuthConfig(&config, authTokenEnv, isLoopback); err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitInvalidInput)
 	}
 	if config.MaxRequestBytes <= 0 {
 		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: "--max-request-bytes must be > 0"}, exitInvalidInput)
@@ -275,23 +408,52 @@ func runMCPServe(arguments []string) int {
 	if config.TraceMaxCount < 0 || config.RunpackMaxCount < 0 || config.PackMaxCount < 0 || config.SessionMaxCount < 0 {
 		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: "retention max-count values must be >= 0"}, exitInvalidInput)
 	}
+	notifier, err := newConfiguredNotifier(projectConfig.Notify)
+	if err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	config.Notifier = notifier
+	telemetry, err := newConfiguredTelemetry(projectConfig.OTel)
+	if err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	config.Telemetry = telemetry
+	tlsConfig, err := newMCPServeTLSConfig(config)
+	if err != nil {
+		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
+	if config.UpstreamURL != "" || config.UpstreamCommand != "" {
+		sessions, err := newMCPServeRelaySessions(config)
+		if err != nil {
+			return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+		}
+		defer sessions.Close()
+		config.RelaySessions = sessions
+	}
 	handler, err := newMCPServeHandler(config)
 	if err != nil {
 		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
+	defer startNotifyFlushLoop(notifier)()
+	flushCtx, stopFlush := context.WithCancel(context.Background())
+	defer stopFlush()
+	go telemetry.Run(flushCtx)
+	defer closeTelemetry(telemetry)
 
 	if jsonOutput {
 		if code := writeJSONOutput(map[string]any{
-			"ok":      true,
-			"listen":  config.ListenAddr,
-			"policy":  config.PolicyPath,
-			"adapter": config.DefaultAdapter,
-			"profile": config.Profile,
+			"ok":        true,
+			"listen":    config.ListenAddr,
+			"policy":    config.PolicyPath,
+			"adapter":   config.DefaultAdapter,
+			"profile":   config.Profile,
+			"auth_mode": config.AuthMode,
+			"tls":       tlsConfig != nil,
 		}, exitOK); code != exitOK {
 			return code
 		}
 	} else {
-		fmt.Printf("mcp serve: listening=%s adapter=%s\n", config.ListenAddr, config.DefaultAdapter)
+		fmt.Printf("mcp serve: listening=%s adapter=%s auth=%s\n", config.ListenAddr, config.DefaultAdapter, config.AuthMode)
 	}
 
 	server := &http.Server{
@@ -301,8 +463,21 @@ func runMCPServe(arguments []string) int {
 		ReadTimeout:       30 * time.Second,
 		WriteTimeout:      30 * time.Second,
 		IdleTimeout:       60 * time.Second,
+		TLSConfig:         tlsConfig,
+	}
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	go func() {
+		<-signalCtx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	serve := server.ListenAndServe
+	if tlsConfig != nil {
+		serve = func() error { return server.ListenAndServeTLS("", "") }
 	}
-	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+	if err := serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return writeMCPProxyOutput(jsonOutput, mcpProxyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
 	return exitOK
@@ -313,7 +488,14 @@ func newMCPServeHandler(config mcpServeConfig) (http.Handler, error) {
 		return nil, fmt.Errorf("mcp serve requires policy path")
 	}
 	if strings.TrimSpace(config.AuthMode) == "" {
-		config.AuthMode = "off"
+		config.AuthMode = mcpServeAuthOff
+	}
+	if config.AuthMode == mcpServeAuthJWT && config.JWTVerifier == nil {
+		verifier, err := newMCPServeJWTVerifier(config)
+		if err != nil {
+			return nil, err
+		}
+		config.JWTVerifier = verifier
 	}
 	if strings.TrimSpace(config.DefaultAdapter) == "" {
 		config.DefaultAdapter = "mcp"
@@ -339,6 +521,42 @@ func newMCPServeHandler(config mcpServeConfig) (http.Handler, error) {
 		}
 		config.VerifiedContextEnvelope = &envelope
 	}
+	if config.ToolAnnotations == nil && config.ToolAnnotationsPath != "" {
+		toolAnnotations, err := loadMCPToolAnnotations(config.ToolAnnotationsPath, sign.KeyConfig{
+			PublicKeyPath:  config.ToolAnnotationsPublicKey,
+			PublicKeyEnv:   config.ToolAnnotationsKeyEnv,
+			PrivateKeyPath: config.PrivateKey,
+			PrivateKeyEnv:  config.PrivateKeyEnv,
+		})
+		if err != nil {
+			return nil, err
+		}
+		config.ToolAnnotations = toolAnnotations
+	}
+	if config.RateLimitStore == nil {
+		if config.RateLimitService && config.RateLimitURL != "" {
+			return nil, fmt.Errorf("--rate-limit-service and --rate-limit-url are mutually exclusive")
+		}
+		rateLimitStore, err := resolveMCPRateLimitStore(config.RateLimitStatePath, config.RateLimitURL, config.RateLimitTokenEnv)
+		if err != nil {
+			return nil, err
+		}
+		if rateLimitStore == nil && config.RateLimitService {
+			rateLimitStore = gate.NewMemoryRateLimitStore()
+		}
+		config.RateLimitStore = rateLimitStore
+	}
+	if config.ApprovalQueueDir != "" && len(config.ApprovalKeyPair.Private) == 0 {
+		keyPair, _, err := sign.LoadSigningKey(sign.KeyConfig{
+			Mode:           sign.KeyMode(strings.ToLower(strings.TrimSpace(config.KeyMode))),
+			PrivateKeyPath: config.PrivateKey,
+			PrivateKeyEnv:  config.PrivateKeyEnv,
+		})
+		if err != nil {
+			return nil, err
+		}
+		config.ApprovalKeyPair = keyPair
+	}
 	if config.RunpackDir != "" {
 		if err := os.MkdirAll(config.RunpackDir, 0o750); err != nil {
 			return nil, fmt.Errorf("create runpack directory: %w", err)
@@ -371,11 +589,12 @@ func newMCPServeHandler(config mcpServeConfig) (http.Handler, error) {
 			writeMCPServeError(writer, http.StatusMethodNotAllowed, "expected POST")
 			return
 		}
-		if err := authorizeMCPServeRequest(config, request); err != nil {
+		principal, err := authorizeMCPServeRequest(config, request)
+		if err != nil {
 			writeMCPServeError(writer, http.StatusUnauthorized, err.Error())
 			return
 		}
-		response, err := evaluateMCPServeRequest(config, writer, request)
+		response, err := evaluateMCPServeRequest(config, principal, writer, request)
 		if err != nil {
 			writeMCPServeError(writer, mcpServeErrorStatus(err), err.Error())
 			return
@@ -387,11 +606,12 @@ func newMCPServeHandler(config mcpServeConfig) (http.Handler, error) {
 			writeMCPServeError(writer, http.StatusMethodNotAllowed, "expected POST")
 			return
 		}
-		if err := authorizeMCPServeRequest(config, request); err != nil {
+		principal, err := authorizeMCPServeRequest(config, request)
+		if err != nil {
 			writeMCPServeError(writer, http.StatusUnauthorized, err.Error())
 			return
 		}
-		response, err := evaluateMCPServeRequest(config, writer, request)
+		response, err := evaluateMCPServeRequest(config, principal, writer, request)
 		if err != nil {
 			writeMCPServeError(writer, mcpServeErrorStatus(err), err.Error())
 			return
@@ -403,21 +623,47 @@ func newMCPServeHandler(config mcpServeConfig) (http.Handler, error) {
 			writeMCPServeError(writer, http.StatusMethodNotAllowed, "expected POST")
 			return
 		}
-		if err := authorizeMCPServeRequest(config, request); err != nil {
+		principal, err := authorizeMCPServeRequest(config, request)
+		if err != nil {
 			writeMCPServeError(writer, http.StatusUnauthorized, err.Error())
 			return
 		}
-		response, err := evaluateMCPServeRequest(config, writer, request)
+		response, err := evaluateMCPServeRequest(config, principal, writer, request)
 		if err != nil {
 			writeMCPServeError(writer, mcpServeErrorStatus(err), err.Error())
 			return
 		}
 		writeMCPServeStream(writer, mcpServeVerdictHTTPStatus(config, response), response)
 	})
+	if config.RateLimitService {
+		serviceToken := ""
+		if config.AuthMode == "token" {
+			serviceToken = config.AuthToken
+		}
+		rateLimitHandler := gate.NewRateLimitServiceHandler(config.RateLimitStore, serviceToken)
+		if config.AuthMode == mcpServeAuthJWT || config.AuthMode == mcpServeAuthMTLS {
+			rateLimitHandler = requireMCPServeAuth(config, rateLimitHandler)
+		}
+		mux.Handle(gate.RateLimitServicePath, rateLimitHandler)
+	}
+	if config.ApprovalQueueDir != "" {
+		registerMCPServeApprovalRoutes(mux, config)
+	}
+	if config.UpstreamURL != "" || config.UpstreamCommand != "" {
+		sessions := config.RelaySessions
+		if sessions == nil {
+			created, err := newMCPServeRelaySessions(config)
+			if err != nil {
+				return nil, err
+			}
+			sessions = created
+		}
+		registerMCPServeRelayRoutes(mux, config, sessions)
+	}
 	return mux, nil
 }
 
-func evaluateMCPServeRequest(config mcpServeConfig, writer http.ResponseWriter, request *http.Request) (mcpServeEvaluateResponse, error) {
+func evaluateMCPServeRequest(config mcpServeConfig, principal authn.Principal, writer http.ResponseWriter, request *http.Request) (mcpServeEvaluateResponse, error) {
 	if err := ensureMCPServeContentType(request); err != nil {
 		return mcpServeEvaluateResponse{}, err
 	}
@@ -467,6 +713,7 @@ func evaluateMCPServeRequest(config mcpServeConfig, writer http.ResponseWriter,
 		Profile:                     config.Profile,
 		JobRoot:                     config.JobRoot,
 		KillSwitchStatePath:         config.KillSwitchStatePath,
+		KillSwitchSource:            config.KillSwitchSource,
 		RunID:                       input.RunID,
 		VerifiedContextEnvelope:     config.VerifiedContextEnvelope,
 		TracePath:                   tracePath,
@@ -480,6 +727,14 @@ func evaluateMCPServeRequest(config mcpServeConfig, writer http.ResponseWriter,
 		PrivateKeyEnv:               config.PrivateKeyEnv,
 		AllowLocalContextArtifacts:  config.AllowClientArtifactPaths,
 		AllowPayloadContextEnvelope: config.AllowClientArtifactPaths,
+		ToolAnnotations:             config.ToolAnnotations,
+		RateLimitStore:              config.RateLimitStore,
+		ApprovalQueueDir:            config.ApprovalQueueDir,
+		ApprovalKeyPair:             config.ApprovalKeyPair,
+		Notifier:                    config.Notifier,
+		Telemetry:                   config.Telemetry,
+		Principal:                   principal,
+		IdentityBinding:             config.IdentityBinding,
 	})
 	if evalErr != nil {
 		return mcpServeEvaluateResponse{}, evalErr
@@ -525,6 +780,10 @@ func evaluateMCPServeRequest(config mcpServeConfig, writer http.ResponseWriter,
 				safetyInvariantHash = strings.TrimSpace(state.SafetyInvariantHash)
 			}
 		}
+		redactor, err := loadPolicyRedactor(config.PolicyPath)
+		if err != nil {
+			return mcpServeEvaluateResponse{}, err
+		}
 		var agentChain []schemacommon.AgentLink
 		if output.Relationship != nil {
 			agentChain = append(agentChain, output.Relationship.AgentChain...)
@@ -546,6 +805,7 @@ func evaluateMCPServeRequest(config mcpServeConfig, writer http.ResponseWriter,
 			Violations:             output.Violations,
 			SafetyInvariantVersion: safetyInvariantVersion,
 			SafetyInvariantHash:    safetyInvariantHash,
+			Redactor:               redactor,
 		})
 		if err != nil {
 			return mcpServeEvaluateResponse{}, err
@@ -740,8 +1000,8 @@ func mcpRetentionMatches(class string, fileName string) bool {
 
 func printMCPServeUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait mcp serve --policy <policy.yaml> [--context-envelope <context_envelope.json>] [--listen 127.0.0.1:8787] [--adapter mcp|openai|anthropic|langchain|claude_code] [--profile standard|oss-prod] [--job-root ./gait-out/jobs] [--auth-mode off|token] [--auth-token-env <VAR>] [--max-request-bytes <bytes>] [--http-verdict-status compat|strict] [--allow-client-artifact-paths] [--trace-dir <dir>] [--runpack-dir <dir>] [--pack-dir <dir>] [--session-dir <dir>] [--trace-max-age <dur>] [--trace-max-count <n>] [--runpack-max-age <dur>] [--runpack-max-count <n>] [--pack-max-age <dur>] [--pack-max-count <n>] [--session-max-age <dur>] [--session-max-count <n>] [--export-log-out events.jsonl] [--export-otel-out otel.jsonl] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
-	fmt.Println("  endpoints: POST /v1/evaluate (json), POST /v1/evaluate/sse (text/event-stream), POST /v1/evaluate/stream (application/x-ndjson)")
+	fmt.Println("  gait mcp serve --policy <policy.yaml> [--context-envelope <context_envelope.json>] [--listen 127.0.0.1:8787] [--adapter mcp|openai|anthropic|langchain|claude_code] [--profile standard|oss-prod] [--job-root ./gait-out/jobs] [--config .gait/config.yaml] [--auth-mode off|token|jwt|mtls] [--auth-token-env <VAR>] [--tls-cert <cert.pem> --tls-key <key.pem>] [--tls-client-ca <ca.pem>] [--jwt-jwks <jwks.json>|--jwt-issuer <url>] [--jwt-audience <aud>] [--jwt-identity-claim <claim>] [--jwt-workspace-claim <claim>] [--jwt-agent-claim <claim>] [--identity-binding override|strict] [--max-request-bytes <bytes>] [--http-verdict-status compat|strict] [--allow-client-artifact-paths] [--trace-dir <dir>] [--runpack-dir <dir>] [--pack-dir <dir>] [--session-dir <dir>] [--trace-max-age <dur>] [--trace-max-count <n>] [--runpack-max-age <dur>] [--runpack-max-count <n>] [--pack-max-age <dur>] [--pack-max-count <n>] [--session-max-age <dur>] [--session-max-count <n>] [--export-log-out events.jsonl] [--export-otel-out otel.jsonl] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--upstream-url <url>|--upstream-command <command>] [--upstream-server-id <id>] [--tool-annotations <tool_annotations.json>] [--tool-annotations-public-key <path>|--tool-annotations-public-key-env <VAR>] [--rate-limit-state <state.json>|--rate-limit-url <url> [--rate-limit-token-env <VAR>]] [--rate-limit-service] [--approval-queue <dir> [--approval-approvers <identity,...>]] [--json] [--explain]")
+	fmt.Println("  endpoints: POST /v1/evaluate (json), POST /v1/evaluate/sse (text/event-stream), POST /v1/evaluate/stream (application/x-ndjson), POST|GET|DELETE /mcp (JSON-RPC relay when an upstream is configured; initialize opens a session with its own upstream and returns Mcp-Session-Id), POST /v1/rate-limit/consume (with --rate-limit-service), GET /v1/approvals, GET /v1/approvals/<id>[?wait=<dur>], POST /v1/approvals/<id>/grant|deny (with --approval-queue; decisions require --approval-approvers and a jwt or mtls principal)")
 }
 
 func sanitizeSessionFileBase(value string) string {
@@ -797,24 +1057,6 @@ func mcpServeVerdictHTTPStatus(config mcpServeConfig, response mcpServeEvaluateR
 	}
 }
 
-func authorizeMCPServeRequest(config mcpServeConfig, request *http.Request) error {
-	if strings.TrimSpace(config.AuthMode) != "token" {
-		return nil
-	}
-	if strings.TrimSpace(config.AuthToken) == "" {
-		return fmt.Errorf("auth token is not configured")
-	}
-	rawHeader := strings.TrimSpace(request.Header.Get("Authorization"))
-	if !strings.HasPrefix(rawHeader, "Bearer ") {
-		return fmt.Errorf("missing bearer authorization")
-	}
-	provided := strings.TrimSpace(strings.TrimPrefix(rawHeader, "Bearer "))
-	if subtle.ConstantTimeCompare([]byte(provided), []byte(strings.TrimSpace(config.AuthToken))) != 1 {
-		return fmt.Errorf("invalid bearer authorization")
-	}
-	return nil
-}
-
 func ensureMCPServeContentType(request *http.Request) error {
 	contentType := strings.TrimSpace(request.Header.Get("Content-Type"))
 	if contentType == "" {
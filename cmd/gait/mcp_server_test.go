@@ -1419,6 +1419,18 @@ func TestRunMCPServeValidationErrors(t *testing.T) {
 			name:      "invalid auth mode",
 			arguments: []string{"--json", "--policy", policyPath, "--auth-mode", "bad"},
 		},
+		{
+			name:      "jwt mode requires key source",
+			arguments: []string{"--json", "--policy", policyPath, "--auth-mode", "jwt"},
+		},
+		{
+			name:      "mtls mode requires tls files",
+			arguments: []string{"--json", "--policy", policyPath, "--auth-mode", "mtls", "--tls-client-ca", "ca.pem"},
+		},
+		{
+			name:      "strict identity binding requires principal auth",
+			arguments: []string{"--json", "--policy", policyPath, "--identity-binding", "strict"},
+		},
 	}
 
 	t.Setenv("GAIT_EMPTY_TOKEN", "")
@@ -1445,3 +1457,72 @@ func containsWarningPrefix(warnings []string, prefix string) bool {
 	}
 	return false
 }
+
+func TestMCPServeHandlerSharesRateLimitAcrossReplicas(t *testing.T) {
+	workDir := t.TempDir()
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, strings.Join([]string{
+		"default_verdict: allow",
+		"rules:",
+		"  - name: search-budget",
+		"    effect: allow",
+		"    match:",
+		"      tool_names: [tool.search]",
+		"    rate_limit:",
+		"      requests: 1",
+		"      window: day",
+		"      scope: tool",
+	}, "\n")+"\n")
+
+	host, err := newMCPServeHandler(mcpServeConfig{
+		PolicyPath:         policyPath,
+		DefaultAdapter:     "mcp",
+		TraceDir:           filepath.Join(workDir, "traces-a"),
+		KeyMode:            "dev",
+		RateLimitStatePath: filepath.Join(workDir, "rate_limits.json"),
+		RateLimitService:   true,
+	})
+	if err != nil {
+		t.Fatalf("newMCPServeHandler host: %v", err)
+	}
+	hostServer := httptest.NewServer(host)
+	t.Cleanup(hostServer.Close)
+
+	replica, err := newMCPServeHandler(mcpServeConfig{
+		PolicyPath:     policyPath,
+		DefaultAdapter: "mcp",
+		TraceDir:       filepath.Join(workDir, "traces-b"),
+		KeyMode:        "dev",
+		RateLimitURL:   hostServer.URL,
+	})
+	if err != nil {
+		t.Fatalf("newMCPServeHandler replica: %v", err)
+	}
+
+	evaluate := func(handler http.Handler) mcpServeEvaluateResponse {
+		request := httptest.NewRequest(http.MethodPost, "/v1/evaluate", strings.NewReader(`{"call":{"name":"tool.search","arguments":{"query":"gait"}}}`))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		var response mcpServeEvaluateResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("decode evaluate response: %v body=%s", err, recorder.Body.String())
+		}
+		return response
+	}
+
+	first := evaluate(host)
+	if first.Verdict != "allow" {
+		t.Fatalf("expected first call on host to allow, got %#v", first)
+	}
+	second := evaluate(replica)
+	if second.Verdict != "block" || second.ExitCode != exitPolicyBlocked || !strings.Contains(strings.Join(second.ReasonCodes, ","), "rate_limit_exceeded") {
+		t.Fatalf("expected replica to share the exhausted budget, got %#v", second)
+	}
+
+	if _, err := newMCPServeHandler(mcpServeConfig{PolicyPath: policyPath, RateLimitURL: hostServer.URL, RateLimitService: true}); err == nil {
+		t.Fatalf("expected --rate-limit-service with --rate-limit-url to fail")
+	}
+	if _, err := newMCPServeHandler(mcpServeConfig{PolicyPath: policyPath, RateLimitTokenEnv: "GAIT_RATE_LIMIT_TOKEN"}); err == nil {
+		t.Fatalf("expected --rate-limit-token-env without --rate-limit-url to fail")
+	}
+}
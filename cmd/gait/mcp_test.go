@@ -177,58 +177,25 @@ func TestRunMCPProxyKillSwitchPreemption(t *testing.T) {
 	if out.Verdict != "block" || !strings.Contains(strings.Join(out.ReasonCodes, ","), "kill_switch_identity_active") {
 		t.Fatalf("expected kill switch reason code, got %#v", out)
 	}
-}
-
-func TestEvaluateMCPEmergencyStopWithoutJobID(t *testing.T) {
-	reason, warnings := evaluateMCPEmergencyStop(mcp.ToolCall{
-		Name: "tool.write",
-		Context: mcp.CallContext{
-			SessionID: "sess-1",
-		},
-	}, "")
-	if reason != "" {
-		t.Fatalf("expected empty reason when job_id is not set, got %q", reason)
-	}
-	if len(warnings) != 0 {
-		t.Fatalf("expected no warnings without job_id, got %#v", warnings)
-	}
-}
 
-func TestEvaluateMCPEmergencyStopStateUnavailable(t *testing.T) {
-	workDir := t.TempDir()
-	reason, warnings := evaluateMCPEmergencyStop(mcp.ToolCall{
-		Name: "tool.write",
-		Context: mcp.CallContext{
-			JobID: "job_missing",
-		},
-	}, filepath.Join(workDir, "jobs"))
-	if reason != "emergency_stop_state_unavailable" {
-		t.Fatalf("expected emergency_stop_state_unavailable, got %q", reason)
-	}
-	if len(warnings) != 1 || !strings.Contains(warnings[0], "job_status_unavailable=") {
-		t.Fatalf("expected job status warning, got %#v", warnings)
-	}
-}
-
-func TestEvaluateMCPEmergencyStopJobNotStopped(t *testing.T) {
-	workDir := t.TempDir()
-	jobsRoot := filepath.Join(workDir, "jobs")
-	if _, err := jobruntime.Submit(jobsRoot, jobruntime.SubmitOptions{
-		JobID: "job_running",
-	}); err != nil {
-		t.Fatalf("submit job: %v", err)
+	raw = captureStdout(t, func() {
+		code = runMCPProxy([]string{
+			"--policy", policyPath,
+			"--call", callPath,
+			"--kill-switch-state", statePath,
+			"--kill-switch-cache", filepath.Join(statePath, "cache.json"),
+			"--json",
+		})
+	})
+	if code != exitPolicyBlocked {
+		t.Fatalf("expected kill switch preemption with unwritable cache to block with %d, got %d (%s)", exitPolicyBlocked, code, raw)
 	}
-	reason, warnings := evaluateMCPEmergencyStop(mcp.ToolCall{
-		Name: "tool.write",
-		Context: mcp.CallContext{
-			JobID: "job_running",
-		},
-	}, jobsRoot)
-	if reason != "" {
-		t.Fatalf("expected empty reason for non-stopped job, got %q", reason)
+	out = mcpProxyOutput{}
+	if err := json.Unmarshal([]byte(raw), &out); err != nil {
+		t.Fatalf("decode proxy output: %v (%s)", err, raw)
 	}
-	if len(warnings) != 0 {
-		t.Fatalf("expected no warnings for non-stopped job, got %#v", warnings)
+	if !strings.Contains(strings.Join(out.Warnings, ","), "kill switch cache not updated") {
+		t.Fatalf("expected kill switch source warning in proxy output, got %#v", out.Warnings)
 	}
 }
 
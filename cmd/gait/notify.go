@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/notify"
+	"github.com/Clyra-AI/gait/core/projectconfig"
+)
+
+const (
+	notifyFlushInterval = 30 * time.Second
+	notifyCloseTimeout  = 10 * time.Second
+)
+
+type notifyOutput struct {
+	OK      bool                `json:"ok"`
+	Action  string              `json:"action,omitempty"`
+	Outbox  string              `json:"outbox,omitempty"`
+	Flush   *notify.FlushResult `json:"flush,omitempty"`
+	Pending []notify.Delivery   `json:"pending,omitempty"`
+	Error   string              `json:"error,omitempty"`
+}
+
+func runNotify(arguments []string) int {
+	if hasExplainFlag(arguments) {
+		return writeExplain("Inspect and flush the notification outbox that delivers approval, kill-switch, and job events to webhook, Slack, and email sinks.")
+	}
+	if len(arguments) == 0 {
+		printNotifyUsage()
+		return exitInvalidInput
+	}
+	switch arguments[0] {
+	case "flush":
+		return runNotifyFlush(arguments[1:])
+	case "list":
+		return runNotifyList(arguments[1:])
+	case "test":
+		return runNotifyTest(arguments[1:])
+	default:
+		printNotifyUsage()
+		return exitInvalidInput
+	}
+}
+
+func runNotifyFlush(arguments []string) int {
+	flagSet := flag.NewFlagSet("notify-flush", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+	var configPath string
+	var jsonOutput bool
+	flagSet.StringVar(&configPath, "config", projectconfig.DefaultPath, "path to project defaults yaml")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeNotifyOutput(jsonOutput, notifyOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
+	notifier, code, output := requireProjectNotifier(configPath)
+	if notifier == nil {
+		return writeNotifyOutput(jsonOutput, output, code)
+	}
+	result, err := notifier.Flush(context.Background())
+	if err != nil {
+		return writeNotifyOutput(jsonOutput, notifyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	return writeNotifyOutput(jsonOutput, notifyOutput{OK: true, Action: "flush", Outbox: notifier.OutboxDir(), Flush: &result}, exitOK)
+}
+
+func runNotifyList(arguments []string) int {
+	flagSet := flag.NewFlagSet("notify-list", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+	var configPath string
+	var jsonOutput bool
+	flagSet.StringVar(&configPath, "config", projectconfig.DefaultPath, "path to project defaults yaml")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeNotifyOutput(jsonOutput, notifyOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
+	notifier, code, output := requireProjectNotifier(configPath)
+	if notifier == nil {
+		return writeNotifyOutput(jsonOutput, output, code)
+	}
+	pending, err := notifier.Pending()
+	if err != nil {
+		return writeNotifyOutput(jsonOutput, notifyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	return writeNotifyOutput(jsonOutput, notifyOutput{OK: true, Action: "list", Outbox: notifier.OutboxDir(), Pending: pending}, exitOK)
+}
+
+func runNotifyTest(arguments []string) int {
+	flagSet := flag.NewFlagSet("notify-test", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+	var configPath string
+	var kind string
+	var jsonOutput bool
+	flagSet.StringVar(&configPath, "config", projectconfig.DefaultPath, "path to project defaults yaml")
+	flagSet.StringVar(&kind, "kind", notify.EventApprovalRequired, "event kind to send")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeNotifyOutput(jsonOutput, notifyOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
+	notifier, code, output := requireProjectNotifier(configPath)
+	if notifier == nil {
+		return writeNotifyOutput(jsonOutput, output, code)
+	}
+	if err := notifier.Notify(context.Background(), notify.Event{
+		ProducerVersion: currentVersion(),
+		Kind:            kind,
+		Source:          "notify_test",
+		Summary:         "gait notification test",
+	}); err != nil {
+		return writeNotifyOutput(jsonOutput, notifyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	pending, err := notifier.Pending()
+	if err != nil {
+		return writeNotifyOutput(jsonOutput, notifyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	return writeNotifyOutput(jsonOutput, notifyOutput{OK: true, Action: "test", Outbox: notifier.OutboxDir(), Pending: pending}, exitOK)
+}
+
+func requireProjectNotifier(configPath string) (*notify.Notifier, int, notifyOutput) {
+	notifier, err := loadProjectNotifier(configPath)
+	if err != nil {
+		return nil, exitCodeForError(err, exitInvalidInput), notifyOutput{OK: false, Error: err.Error()}
+	}
+	if notifier == nil {
+		return nil, exitInvalidInput, notifyOutput{OK: false, Error: "no notify sinks configured in " + strings.TrimSpace(configPath)}
+	}
+	return notifier, exitOK, notifyOutput{}
+}
+
+func loadProjectNotifier(configPath string) (*notify.Notifier, error) {
+	configuration, err := projectconfig.Load(configPath, isDefaultProjectConfigPath(configPath))
+	if err != nil {
+		return nil, err
+	}
+	return newConfiguredNotifier(configuration.Notify)
+}
+
+func newConfiguredNotifier(configuration notify.Config) (*notify.Notifier, error) {
+	if !configuration.Enabled() {
+		return nil, nil
+	}
+	return notify.New(configuration)
+}
+
+// emitNotification only queues the event in the outbox so a decision never
+// waits on a sink; gait notify flush or the serve and job run flush loops
+// deliver it. Failures to queue are surfaced as warnings.
+func emitNotification(notifier *notify.Notifier, event notify.Event) []string {
+	if notifier == nil {
+		return nil
+	}
+	event.ProducerVersion = currentVersion()
+	if err := notifier.Enqueue(event); err != nil {
+		return []string{"notification not queued: " + err.Error()}
+	}
+	return nil
+}
+
+// notifyProjectEvent serves operator commands that have no --config flag of
+// their own and read notify settings from the default project config.
+func notifyProjectEvent(event notify.Event) {
+	notifier, err := loadProjectNotifier(projectconfig.DefaultPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "notify warning: %v\n", err)
+		return
+	}
+	for _, warning := range emitNotification(notifier, event) {
+		fmt.Fprintf(os.Stderr, "notify warning: %s\n", warning)
+	}
+}
+
+// startNotifyFlushLoop delivers queued notifications in the background for a
+// long-running command. The returned stop ends the loop and waits for its last
+// bounded flush.
+func startNotifyFlushLoop(notifier *notify.Notifier) func() {
+	if notifier == nil {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runNotifyFlushLoop(ctx, notifier)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func runNotifyFlushLoop(ctx context.Context, notifier *notify.Notifier) {
+	if notifier == nil {
+		return
+	}
+	ticker := time.NewTicker(notifyFlushInterval)
+	defer ticker.Stop()
+	for {
+		_, _ = notifier.Flush(ctx)
+		select {
+		case <-ctx.Done():
+			closeCtx, cancel := context.WithTimeout(context.Background(), notifyCloseTimeout)
+			_, _ = notifier.Flush(closeCtx)
+			cancel()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func writeNotifyOutput(jsonOutput bool, output notifyOutput, exitCode int) int {
+	if jsonOutput {
+		return writeJSONOutput(output, exitCode)
+	}
+	if output.Error != "" {
+		fmt.Fprintf(os.Stderr, "notify error: %s\n", output.Error)
+		return exitCode
+	}
+	if output.Flush != nil {
+		fmt.Printf("notify flush: delivered=%d retrying=%d failed=%d pending=%d\n", output.Flush.Delivered, output.Flush.Retrying, output.Flush.Failed, output.Flush.Pending)
+		for _, flushErr := range output.Flush.Errors {
+			fmt.Printf("  %s\n", flushErr)
+		}
+		return exitCode
+	}
+	fmt.Printf("notify %s: %d pending in %s\n", output.Action, len(output.Pending), output.Outbox)
+	for _, delivery := range output.Pending {
+		fmt.Printf("  %s sink=%s kind=%s attempts=%d next=%s\n", delivery.DeliveryID, delivery.Sink, delivery.Event.Kind, delivery.Attempts, delivery.NextAttemptAt.UTC().Format(time.RFC3339))
+	}
+	return exitCode
+}
+
+func printNotifyUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  gait notify flush [--config .gait/config.yaml] [--json] [--explain]")
+	fmt.Println("  gait notify list [--config .gait/config.yaml] [--json] [--explain]")
+	fmt.Println("  gait notify test [--config .gait/config.yaml] [--kind approval_required] [--json] [--explain]")
+}
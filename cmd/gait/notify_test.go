@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/notify"
+)
+
+func TestNotificationsFromGateKillSwitchAndJobs(t *testing.T) {
+	workDir := t.TempDir()
+	withWorkingDir(t, workDir)
+	t.Setenv("GAIT_TEST_WEBHOOK_SECRET", "hook-secret")
+
+	var mu sync.Mutex
+	received := []notify.Event{}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		if err := notify.VerifySignature([]byte("hook-secret"), request.Header.Get(notify.HeaderTimestamp), request.Header.Get(notify.HeaderSignature), body, time.Now(), time.Minute); err != nil {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var event notify.Event
+		_ = json.Unmarshal(body, &event)
+		mu.Lock()
+		received = append(received, event)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	if err := os.MkdirAll(filepath.Join(workDir, ".gait"), 0o750); err != nil {
+		t.Fatalf("mkdir .gait: %v", err)
+	}
+	mustWriteFile(t, filepath.Join(workDir, ".gait", "config.yaml"), strings.Join([]string{
+		"notify:",
+		"  sinks:",
+		"    - name: ops",
+		"      type: webhook",
+		"      url: " + server.URL,
+		"      secret_env: GAIT_TEST_WEBHOOK_SECRET",
+	}, "\n")+"\n")
+
+	intentPath := filepath.Join(workDir, "intent.json")
+	writeIntentFixture(t, intentPath, "tool.write")
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, strings.Join([]string{
+		"default_verdict: allow",
+		"rules:",
+		"  - name: approve-writes",
+		"    effect: require_approval",
+		"    match:",
+		"      tool_names: [tool.write]",
+	}, "\n")+"\n")
+	if code := runGateEval([]string{"--policy", policyPath, "--intent", intentPath, "--json"}); code != exitApprovalRequired {
+		t.Fatalf("gate eval: expected %d got %d", exitApprovalRequired, code)
+	}
+	if code := runKillSwitch([]string{"add", "--state", filepath.Join(workDir, "kill_switch.json"), "--tool-name", "tool.write", "--actor", "oncall", "--json"}); code != exitOK {
+		t.Fatalf("kill-switch add: expected %d got %d", exitOK, code)
+	}
+	jobRoot := filepath.Join(workDir, "jobs")
+	if code := runJob([]string{"submit", "--id", "job_notify", "--root", jobRoot, "--json"}); code != exitOK {
+		t.Fatalf("job submit: expected %d got %d", exitOK, code)
+	}
+	if code := runJob([]string{"stop", "--id", "job_notify", "--root", jobRoot, "--actor", "oncall", "--json"}); code != exitOK {
+		t.Fatalf("job stop: expected %d got %d", exitOK, code)
+	}
+	if code := runJob([]string{"submit", "--id", "job_cancel", "--root", jobRoot, "--json"}); code != exitOK {
+		t.Fatalf("job submit: expected %d got %d", exitOK, code)
+	}
+	if code := runJob([]string{"cancel", "--id", "job_cancel", "--root", jobRoot, "--actor", "oncall", "--json"}); code != exitOK {
+		t.Fatalf("job cancel: expected %d got %d", exitOK, code)
+	}
+
+	receivedKinds := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		kinds := make([]string, 0, len(received))
+		for _, event := range received {
+			kinds = append(kinds, event.Kind)
+		}
+		sort.Strings(kinds)
+		return kinds
+	}
+	if kinds := receivedKinds(); len(kinds) != 0 {
+		t.Fatalf("expected commands to only queue notifications, got deliveries %v", kinds)
+	}
+	var output notifyOutput
+	raw := captureStdout(t, func() {
+		if code := runNotify([]string{"list", "--json"}); code != exitOK {
+			t.Fatalf("notify list: expected %d got %d", exitOK, code)
+		}
+	})
+	if err := json.Unmarshal([]byte(raw), &output); err != nil || len(output.Pending) != 4 {
+		t.Fatalf("expected four queued deliveries, got %s err=%v", raw, err)
+	}
+
+	raw = captureStdout(t, func() {
+		if code := runNotify([]string{"flush", "--json"}); code != exitOK {
+			t.Fatalf("notify flush: expected %d got %d", exitOK, code)
+		}
+	})
+	output = notifyOutput{}
+	if err := json.Unmarshal([]byte(raw), &output); err != nil || output.Flush == nil || output.Flush.Delivered != 4 {
+		t.Fatalf("expected flush to deliver four notifications, got %s err=%v", raw, err)
+	}
+	expected := []string{notify.EventApprovalRequired, notify.EventJobCancelled, notify.EventJobEmergencyStopped, notify.EventKillSwitchEngaged}
+	if kinds := receivedKinds(); strings.Join(kinds, ",") != strings.Join(expected, ",") {
+		t.Fatalf("unexpected notifications: %v", kinds)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/otlp"
+	"github.com/Clyra-AI/gait/core/projectconfig"
+)
+
+const telemetryCloseTimeout = 10 * time.Second
+
+type otelOutput struct {
+	OK    bool              `json:"ok"`
+	Spool string            `json:"spool,omitempty"`
+	Flush *otlp.FlushResult `json:"flush,omitempty"`
+	Error string            `json:"error,omitempty"`
+}
+
+func runOTel(arguments []string) int {
+	if hasExplainFlag(arguments) {
+		return writeExplain("Deliver gate decisions that one-shot commands spooled for the OpenTelemetry collector configured in the otel project config section.")
+	}
+	if len(arguments) == 0 {
+		printOTelUsage()
+		return exitInvalidInput
+	}
+	switch arguments[0] {
+	case "flush":
+		return runOTelFlush(arguments[1:])
+	default:
+		printOTelUsage()
+		return exitInvalidInput
+	}
+}
+
+func runOTelFlush(arguments []string) int {
+	flagSet := flag.NewFlagSet("otel-flush", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+	var configPath string
+	var jsonOutput bool
+	flagSet.StringVar(&configPath, "config", projectconfig.DefaultPath, "path to project defaults yaml")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	if err := flagSet.Parse(arguments); err != nil {
+		return writeOTelOutput(jsonOutput, otelOutput{OK: false, Error: err.Error()}, exitInvalidInput)
+	}
+	exporter, err := loadProjectTelemetry(configPath)
+	if err != nil {
+		return writeOTelOutput(jsonOutput, otelOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	if exporter == nil {
+		return writeOTelOutput(jsonOutput, otelOutput{OK: false, Error: "no otel endpoint configured in " + strings.TrimSpace(configPath)}, exitInvalidInput)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryCloseTimeout)
+	defer cancel()
+	result, err := exporter.Flush(ctx)
+	if err != nil {
+		return writeOTelOutput(jsonOutput, otelOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	return writeOTelOutput(jsonOutput, otelOutput{OK: true, Spool: exporter.SpoolDir(), Flush: &result}, exitOK)
+}
+
+func loadProjectTelemetry(configPath string) (*otlp.Exporter, error) {
+	configuration, err := projectconfig.Load(configPath, isDefaultProjectConfigPath(configPath))
+	if err != nil {
+		return nil, err
+	}
+	return newConfiguredTelemetry(configuration.OTel)
+}
+
+func newConfiguredTelemetry(configuration otlp.Config) (*otlp.Exporter, error) {
+	if !configuration.Enabled() {
+		return nil, nil
+	}
+	return otlp.New(configuration, currentVersion())
+}
+
+// spoolTelemetry writes queued decisions to the spool so a one-shot command
+// never waits on the collector; gait otel flush or the gait mcp serve flush
+// loop delivers them.
+func spoolTelemetry(exporter *otlp.Exporter) []string {
+	if exporter == nil {
+		return nil
+	}
+	result, err := exporter.Spool()
+	if err != nil {
+		return []string{"otel spool failed: " + err.Error()}
+	}
+	return telemetryDropWarnings(result)
+}
+
+// closeTelemetry flushes queued decisions for a long-running command and
+// reports anything that was not delivered as warnings.
+func closeTelemetry(exporter *otlp.Exporter) []string {
+	if exporter == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryCloseTimeout)
+	defer cancel()
+	result, err := exporter.Close(ctx)
+	if err != nil {
+		return []string{"otel export failed: " + err.Error()}
+	}
+	warnings := []string{}
+	if result.Spooled > 0 {
+		warnings = append(warnings, fmt.Sprintf("otel collector unavailable; spooled %d decision(s) to %s", result.Spooled, exporter.SpoolDir()))
+	}
+	return append(warnings, telemetryDropWarnings(result)...)
+}
+
+func telemetryDropWarnings(result otlp.FlushResult) []string {
+	if result.Dropped == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("otel export dropped %d decision(s)", result.Dropped)}
+}
+
+func writeOTelOutput(jsonOutput bool, output otelOutput, exitCode int) int {
+	if jsonOutput {
+		return writeJSONOutput(output, exitCode)
+	}
+	if output.Error != "" {
+		fmt.Fprintf(os.Stderr, "otel error: %s\n", output.Error)
+		return exitCode
+	}
+	fmt.Printf("otel flush: exported=%d replayed=%d spooled=%d dropped=%d\n", output.Flush.Exported, output.Flush.Replayed, output.Flush.Spooled, output.Flush.Dropped)
+	for _, flushErr := range output.Flush.Errors {
+		fmt.Printf("  %s\n", flushErr)
+	}
+	return exitCode
+}
+
+func printOTelUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  gait otel flush [--config .gait/config.yaml] [--json] [--explain]")
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGateEvalSpoolsOTLPDecisionForOTelFlush(t *testing.T) {
+	workDir := t.TempDir()
+	withWorkingDir(t, workDir)
+
+	var mu sync.Mutex
+	received := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		mu.Lock()
+		received[request.URL.Path] = string(body)
+		mu.Unlock()
+		writer.Header().Set("content-type", "application/json")
+		_, _ = writer.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	writeConfig := func(endpoint string) {
+		mustWriteFile(t, filepath.Join(workDir, ".gait", "config.yaml"), strings.Join([]string{
+			"otel:",
+			"  endpoint: " + endpoint,
+			"  service_name: gait-test",
+			"  max_attempts: 1",
+			"  spool: " + filepath.Join(workDir, "otlp_spool"),
+		}, "\n")+"\n")
+	}
+	if err := os.MkdirAll(filepath.Join(workDir, ".gait"), 0o750); err != nil {
+		t.Fatalf("mkdir .gait: %v", err)
+	}
+	writeConfig(server.URL)
+
+	intentPath := filepath.Join(workDir, "intent.json")
+	writeIntentFixture(t, intentPath, "tool.write")
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, strings.Join([]string{
+		"default_verdict: allow",
+		"rules:",
+		"  - name: block-writes",
+		"    effect: block",
+		"    match:",
+		"      tool_names: [tool.write]",
+	}, "\n")+"\n")
+
+	var output gateEvalOutput
+	raw := captureStdout(t, func() {
+		if code := runGateEval([]string{"--policy", policyPath, "--intent", intentPath, "--json"}); code != exitPolicyBlocked {
+			t.Fatalf("gate eval: expected %d got %d", exitPolicyBlocked, code)
+		}
+	})
+	if err := json.Unmarshal([]byte(raw), &output); err != nil {
+		t.Fatalf("decode gate eval output: %v (%s)", err, raw)
+	}
+	if strings.Contains(strings.Join(output.Warnings, "\n"), "otel") {
+		t.Fatalf("expected no otel warnings when spooling, got %v", output.Warnings)
+	}
+	mu.Lock()
+	sentDuringEval := len(received)
+	mu.Unlock()
+	if sentDuringEval != 0 {
+		t.Fatalf("expected gate eval not to contact the collector, got %d requests", sentDuringEval)
+	}
+	entries, err := os.ReadDir(filepath.Join(workDir, "otlp_spool", "pending"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one spooled batch, entries=%v err=%v", entries, err)
+	}
+
+	var flushOutput otelOutput
+	raw = captureStdout(t, func() {
+		if code := runOTel([]string{"flush", "--json"}); code != exitOK {
+			t.Fatalf("otel flush: expected %d got %d", exitOK, code)
+		}
+	})
+	if err := json.Unmarshal([]byte(raw), &flushOutput); err != nil {
+		t.Fatalf("decode otel flush output: %v (%s)", err, raw)
+	}
+	if flushOutput.Flush == nil || flushOutput.Flush.Replayed != 1 {
+		t.Fatalf("expected otel flush to replay the spooled decision, got %#v", flushOutput)
+	}
+
+	mu.Lock()
+	traces := received["/v1/traces"]
+	metrics := received["/v1/metrics"]
+	logs := received["/v1/logs"]
+	mu.Unlock()
+	for _, expected := range []string{`"gait.gate.evaluate"`, `"gait-test"`, `"stringValue":"block"`, `"stringValue":"` + output.PolicyDigest + `"`, `"stringValue":"` + output.TraceID + `"`, `"gait.decision_latency_ms"`} {
+		if !strings.Contains(traces, expected) {
+			t.Fatalf("expected %s in exported span: %s", expected, traces)
+		}
+	}
+	if !strings.Contains(metrics, `"gait.gate.decisions"`) || !strings.Contains(logs, `"gait.gate.decision"`) {
+		t.Fatalf("expected metrics and logs export, metrics=%s logs=%s", metrics, logs)
+	}
+
+	server.Close()
+	raw = captureStdout(t, func() {
+		if code := runGateEval([]string{"--policy", policyPath, "--intent", intentPath, "--json"}); code != exitPolicyBlocked {
+			t.Fatalf("gate eval offline: expected %d got %d", exitPolicyBlocked, code)
+		}
+	})
+	raw = captureStdout(t, func() {
+		if code := runOTel([]string{"flush", "--json"}); code != exitOK {
+			t.Fatalf("otel flush offline: expected %d got %d", exitOK, code)
+		}
+	})
+	flushOutput = otelOutput{}
+	if err := json.Unmarshal([]byte(raw), &flushOutput); err != nil {
+		t.Fatalf("decode otel flush output: %v (%s)", err, raw)
+	}
+	if flushOutput.Flush == nil || flushOutput.Flush.Replayed != 0 || len(flushOutput.Flush.Errors) == 0 {
+		t.Fatalf("expected offline flush to keep the batch spooled, got %#v", flushOutput)
+	}
+	entries, err = os.ReadDir(filepath.Join(workDir, "otlp_spool", "pending"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one spooled batch, entries=%v err=%v", entries, err)
+	}
+}
@@ -170,6 +170,7 @@ func runPackBuild(arguments []string) int {
 		if buildErr != nil {
 			return writePackOutput(jsonOutput, packOutput{OK: false, Operation: "build", Error: buildErr.Error()}, exitCodeForError(buildErr, exitInvalidInput))
 		}
+		recordTransparencyArtifact(result.Path)
 		return writePackOutput(jsonOutput, packOutput{OK: true, Operation: "build", Path: result.Path, PackID: result.Manifest.PackID, PackType: result.Manifest.PackType, SourceRef: result.Manifest.SourceRef, Warnings: warnings}, exitOK)
 	case string(pack.BuildTypeJob):
 		root, jobID, resolveErr := resolveJobSource(strings.TrimSpace(from), strings.TrimSpace(jobRoot))
@@ -180,6 +181,7 @@ func runPackBuild(arguments []string) int {
 		if buildErr != nil {
 			return writePackOutput(jsonOutput, packOutput{OK: false, Operation: "build", Error: buildErr.Error()}, exitCodeForError(buildErr, exitInvalidInput))
 		}
+		recordTransparencyArtifact(result.Path)
 		return writePackOutput(jsonOutput, packOutput{OK: true, Operation: "build", Path: result.Path, PackID: result.Manifest.PackID, PackType: result.Manifest.PackType, SourceRef: result.Manifest.SourceRef, Warnings: warnings}, exitOK)
 	case string(pack.BuildTypeCall):
 		result, buildErr := pack.BuildCallPack(pack.BuildCallOptions{
@@ -191,6 +193,7 @@ func runPackBuild(arguments []string) int {
 		if buildErr != nil {
 			return writePackOutput(jsonOutput, packOutput{OK: false, Operation: "build", Error: buildErr.Error()}, exitCodeForError(buildErr, exitInvalidInput))
 		}
+		recordTransparencyArtifact(result.Path)
 		return writePackOutput(jsonOutput, packOutput{OK: true, Operation: "build", Path: result.Path, PackID: result.Manifest.PackID, PackType: result.Manifest.PackType, SourceRef: result.Manifest.SourceRef, Warnings: warnings}, exitOK)
 	case string(pack.BuildTypeAuthorization):
 		result, buildErr := pack.BuildAuthorizationPack(pack.BuildAuthorizationOptions{
@@ -202,6 +205,7 @@ func runPackBuild(arguments []string) int {
 		if buildErr != nil {
 			return writePackOutput(jsonOutput, packOutput{OK: false, Operation: "build", Error: buildErr.Error()}, exitCodeForError(buildErr, exitInvalidInput))
 		}
+		recordTransparencyArtifact(result.Path)
 		return writePackOutput(jsonOutput, packOutput{OK: true, Operation: "build", Path: result.Path, PackID: result.Manifest.PackID, PackType: result.Manifest.PackType, SourceRef: result.Manifest.SourceRef, Warnings: warnings}, exitOK)
 	default:
 		return writePackOutput(jsonOutput, packOutput{OK: false, Operation: "build", Error: "--type must be run, job, call, or authorization"}, exitInvalidInput)
@@ -212,6 +216,7 @@ func runPackVerify(arguments []string) int {
 	arguments = reorderInterspersedFlags(arguments, map[string]bool{
 		"path":            true,
 		"profile":         true,
+		"inclusion-proof": true,
 		"public-key":      true,
 		"public-key-env":  true,
 		"private-key":     true,
@@ -223,6 +228,7 @@ func runPackVerify(arguments []string) int {
 	var pathValue string
 	var profile string
 	var requireSignature bool
+	var inclusionProofPath string
 	var publicKeyPath string
 	var publicKeyEnv string
 	var privateKeyPath string
@@ -233,6 +239,7 @@ func runPackVerify(arguments []string) int {
 	flagSet.StringVar(&pathValue, "path", "", "path to pack artifact zip")
 	flagSet.StringVar(&profile, "profile", string(verifyProfileStandard), "verify profile: standard|strict")
 	flagSet.BoolVar(&requireSignature, "require-signature", false, "require valid signatures")
+	flagSet.StringVar(&inclusionProofPath, "inclusion-proof", "", "transparency log inclusion proof for the pack manifest")
 	flagSet.StringVar(&publicKeyPath, "public-key", "", "path to base64 public key")
 	flagSet.StringVar(&publicKeyEnv, "public-key-env", "", "env var containing base64 public key")
 	flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private key (derive public)")
@@ -274,11 +281,16 @@ func runPackVerify(arguments []string) int {
 		return writePackOutput(jsonOutput, packOutput{OK: false, Operation: "verify", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
 
-	result, err := pack.Verify(strings.TrimSpace(pathValue), pack.VerifyOptions{PublicKey: publicKey, RequireSignature: requireSignature})
+	inclusionProof, err := loadInclusionProofFlag(inclusionProofPath)
+	if err != nil {
+		return writePackOutput(jsonOutput, packOutput{OK: false, Operation: "verify", Error: err.Error()}, exitInvalidInput)
+	}
+
+	result, err := pack.Verify(strings.TrimSpace(pathValue), pack.VerifyOptions{PublicKey: publicKey, RequireSignature: requireSignature, InclusionProof: inclusionProof})
 	if err != nil {
 		return writePackOutput(jsonOutput, packOutput{OK: false, Operation: "verify", Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
-	ok := len(result.MissingFiles) == 0 && len(result.HashMismatches) == 0 && len(result.UndeclaredFiles) == 0
+	ok := len(result.MissingFiles) == 0 && len(result.HashMismatches) == 0 && len(result.UndeclaredFiles) == 0 && inclusionOK(result.Inclusion, requireSignature)
 	if requireSignature {
 		ok = ok && result.SignatureStatus == "verified"
 	} else {
@@ -516,7 +528,7 @@ func resolveJobSource(from string, defaultRoot string) (string, string, error) {
 func printPackUsage() {
 	fmt.Println("Usage:")
 	fmt.Println("  gait pack build --type <run|job|call|authorization> --from <run_id|path|job_id|job_path|call_record.json|authorization_bundle.json> [--out <pack.zip>] [--job-root ./gait-out/jobs] [--key-mode none|dev|prod] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
-	fmt.Println("  gait pack verify <pack.zip> [--profile standard|strict] [--require-signature] [--public-key <path>|--public-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait pack verify <pack.zip> [--profile standard|strict] [--require-signature] [--inclusion-proof <proof.json>] [--public-key <path>|--public-key-env <VAR>] [--json] [--explain]")
 	fmt.Println("  gait pack inspect <pack.zip> [--json] [--explain]")
 	fmt.Println("  gait pack diff <left.zip> <right.zip> [--output <diff.json>] [--json] [--explain]")
 	fmt.Println("  gait pack export <pack.zip> [--otel-out <otel.jsonl>] [--postgres-sql-out <pack_index.sql>] [--postgres-table <table|schema.table>] [--postgres-include-ddl=true|false] [--json] [--explain]")
@@ -529,7 +541,7 @@ func printPackBuildUsage() {
 
 func printPackVerifyUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait pack verify <pack.zip> [--profile standard|strict] [--require-signature] [--public-key <path>|--public-key-env <VAR>] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait pack verify <pack.zip> [--profile standard|strict] [--require-signature] [--inclusion-proof <proof.json>] [--public-key <path>|--public-key-env <VAR>] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
 }
 
 func printPackInspectUsage() {
@@ -40,14 +40,24 @@ type policyInitOutput struct {
 }
 
 type policyValidateOutput struct {
-	OK             bool   `json:"ok"`
-	SchemaID       string `json:"schema_id,omitempty"`
-	SchemaVersion  string `json:"schema_version,omitempty"`
-	PolicyDigest   string `json:"policy_digest,omitempty"`
-	DefaultVerdict string `json:"default_verdict,omitempty"`
-	RuleCount      int    `json:"rule_count,omitempty"`
-	Summary        string `json:"summary,omitempty"`
-	Error          string `json:"error,omitempty"`
+	OK             bool                 `json:"ok"`
+	SchemaID       string               `json:"schema_id,omitempty"`
+	SchemaVersion  string               `json:"schema_version,omitempty"`
+	PolicyDigest   string               `json:"policy_digest,omitempty"`
+	DefaultVerdict string               `json:"default_verdict,omitempty"`
+	RuleCount      int                  `json:"rule_count,omitempty"`
+	Sources        []gate.PolicySource  `json:"sources,omitempty"`
+	Rules          []policyValidateRule `json:"rules,omitempty"`
+	Summary        string               `json:"summary,omitempty"`
+	Error          string               `json:"error,omitempty"`
+}
+
+type policyValidateRule struct {
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+	Effect   string `json:"effect"`
+	Source   string `json:"source,omitempty"`
+	Locked   bool   `json:"locked,omitempty"`
 }
 
 type policyFmtOutput struct {
@@ -124,7 +134,7 @@ var policyTemplateAliases = map[string]string{
 
 func runPolicy(arguments []string) int {
 	if hasExplainFlag(arguments) {
-		return writeExplain("Initialize, validate, format, and test Gate policies deterministically before rollout.")
+		return writeExplain("Initialize, validate, format, test, and measure trace coverage of Gate policies deterministically before rollout.")
 	}
 	if len(arguments) == 0 {
 		printPolicyUsage()
@@ -141,6 +151,10 @@ func runPolicy(arguments []string) int {
 		return runPolicySimulate(arguments[1:])
 	case "test":
 		return runPolicyTest(arguments[1:])
+	case "coverage":
+		return runPolicyCoverage(arguments[1:])
+	case "diff":
+		return runPolicyDiff(arguments[1:])
 	default:
 		printPolicyUsage()
 		return exitInvalidInput
@@ -243,14 +257,18 @@ func runPolicyValidate(arguments []string) int {
 	if hasExplainFlag(arguments) {
 		return writeExplain("Parse and normalize one policy file with strict YAML checks and return deterministic metadata.")
 	}
-	arguments = reorderInterspersedFlags(arguments, nil)
+	arguments = reorderInterspersedFlags(arguments, map[string]bool{
+		"registry-cache-dir": true,
+	})
 
 	flagSet := flag.NewFlagSet("policy-validate", flag.ContinueOnError)
 	flagSet.SetOutput(io.Discard)
 
+	var registryCacheDir string
 	var jsonOutput bool
 	var helpFlag bool
 
+	flagSet.StringVar(&registryCacheDir, "registry-cache-dir", "", "registry cache directory for registry: extends (default ~/.gait/registry)")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
 
@@ -269,7 +287,7 @@ func runPolicyValidate(arguments []string) int {
 	}
 
 	policyPath := flagSet.Args()[0]
-	policy, err := gate.LoadPolicyFile(policyPath)
+	policy, err := gate.LoadPolicyFileWithOptions(policyPath, gate.PolicyLoadOptions{RegistryCacheDir: registryCacheDir})
 	if err != nil {
 		return writePolicyValidateOutput(jsonOutput, policyValidateOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
@@ -277,7 +295,20 @@ func runPolicyValidate(arguments []string) int {
 	if err != nil {
 		return writePolicyValidateOutput(jsonOutput, policyValidateOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
+	rules := make([]policyValidateRule, 0, len(policy.Rules))
+	for _, rule := range policy.Rules {
+		rules = append(rules, policyValidateRule{
+			Name:     rule.Name,
+			Priority: rule.Priority,
+			Effect:   rule.Effect,
+			Source:   rule.Source,
+			Locked:   rule.Locked,
+		})
+	}
 	summary := fmt.Sprintf("policy validate ok: default=%s rules=%d digest=%s", policy.DefaultVerdict, len(policy.Rules), policyDigest)
+	if len(policy.Sources) > 1 {
+		summary += fmt.Sprintf(" sources=%d", len(policy.Sources))
+	}
 	return writePolicyValidateOutput(jsonOutput, policyValidateOutput{
 		OK:             true,
 		SchemaID:       policy.SchemaID,
@@ -285,6 +316,8 @@ func runPolicyValidate(arguments []string) int {
 		PolicyDigest:   policyDigest,
 		DefaultVerdict: policy.DefaultVerdict,
 		RuleCount:      len(policy.Rules),
+		Sources:        policy.Sources,
+		Rules:          rules,
 		Summary:        summary,
 	}, exitOK)
 }
@@ -331,7 +364,7 @@ func runPolicyFmt(arguments []string) int {
 	if err != nil {
 		return writePolicyFmtOutput(jsonOutput, policyFmtOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
-	policy, err := gate.ParsePolicyYAML(content)
+	policy, err := gate.ParsePolicyLayerYAML(content)
 	if err != nil {
 		return writePolicyFmtOutput(jsonOutput, policyFmtOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
@@ -340,7 +373,14 @@ func runPolicyFmt(arguments []string) int {
 		return writePolicyFmtOutput(jsonOutput, policyFmtOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
 	changed := string(content) != string(formatted)
-	policyDigest, err := gate.PolicyDigest(policy)
+	digestPolicy := policy
+	if len(policy.Extends) > 0 {
+		digestPolicy, err = gate.LoadPolicyFile(policyPath)
+		if err != nil {
+			return writePolicyFmtOutput(jsonOutput, policyFmtOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+		}
+	}
+	policyDigest, err := gate.PolicyDigest(digestPolicy)
 	if err != nil {
 		return writePolicyFmtOutput(jsonOutput, policyFmtOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
@@ -624,6 +664,15 @@ func writePolicyValidateOutput(jsonOutput bool, output policyValidateOutput, exi
 	}
 	if output.OK {
 		fmt.Println(output.Summary)
+		if len(output.Sources) > 1 {
+			for _, rule := range output.Rules {
+				locked := ""
+				if rule.Locked {
+					locked = " locked"
+				}
+				fmt.Printf("  rule %s priority=%d effect=%s source=%s%s\n", rule.Name, rule.Priority, rule.Effect, rule.Source, locked)
+			}
+		}
 		return exitCode
 	}
 	fmt.Printf("policy validate error: %s\n", output.Error)
@@ -668,10 +717,12 @@ func formatPolicyYAML(policy gate.Policy) ([]byte, error) {
 func printPolicyUsage() {
 	fmt.Println("Usage:")
 	fmt.Println("  gait policy init <baseline-lowrisk|baseline-mediumrisk|baseline-highrisk> [--out gait.policy.yaml] [--force] [--json] [--explain]")
-	fmt.Println("  gait policy validate <policy.yaml> [--json] [--explain]")
+	fmt.Println("  gait policy validate <policy.yaml> [--registry-cache-dir <dir>] [--json] [--explain]")
 	fmt.Println("  gait policy fmt <policy.yaml> [--write] [--json] [--explain]")
 	fmt.Println("  gait policy simulate --policy <candidate.yaml> --baseline <baseline.yaml> --fixtures <csv files/dirs> [--json] [--explain]")
 	fmt.Println("  gait policy test <policy.yaml> <intent_fixture.json> [--json] [--explain]")
+	fmt.Println("  gait policy coverage --policy <policy.yaml> --traces <csv files/dirs> [--runpacks <csv files/dirs>] [--junit <path>] [--json] [--explain]")
+	fmt.Println("  gait policy diff --base <old.yaml> --head <new.yaml> [--corpus <csv files/dirs>] [--json] [--explain]")
 	fmt.Println("Rollout path:")
 	fmt.Println("  observe: gait gate eval --policy <policy.yaml> --intent <intent.json> --simulate --json")
 	fmt.Println("  enforce: gait gate eval --policy <policy.yaml> --intent <intent.json> --json")
@@ -691,7 +742,7 @@ func printPolicyTestUsage() {
 
 func printPolicyValidateUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait policy validate <policy.yaml> [--json] [--explain]")
+	fmt.Println("  gait policy validate <policy.yaml> [--registry-cache-dir <dir>] [--json] [--explain]")
 }
 
 func printPolicyFmtUsage() {
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/policytest"
+	schemapolicytest "github.com/Clyra-AI/gait/core/schema/v1/policytest"
+)
+
+type policyCoverageOutput struct {
+	OK        bool   `json:"ok"`
+	JUnitPath string `json:"junit_path,omitempty"`
+	Summary   string `json:"summary,omitempty"`
+	Error     string `json:"error,omitempty"`
+	*schemapolicytest.PolicyCoverageReport
+}
+
+func runPolicyCoverage(arguments []string) int {
+	if hasExplainFlag(arguments) {
+		return writeExplain("Replay recorded traces and runpack intents through a policy and report rule hit counts, unreachable and shadowed rules, and tools that only reach default_verdict.")
+	}
+	arguments = reorderInterspersedFlags(arguments, map[string]bool{
+		"policy":   true,
+		"traces":   true,
+		"runpacks": true,
+		"junit":    true,
+	})
+
+	flagSet := flag.NewFlagSet("policy-coverage", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+
+	var policyPath string
+	var tracesCSV string
+	var runpacksCSV string
+	var junitPath string
+	var jsonOutput bool
+	var helpFlag bool
+
+	flagSet.StringVar(&policyPath, "policy", "", "policy path")
+	flagSet.StringVar(&tracesCSV, "traces", "", "trace record and intent path list (csv of files/directories)")
+	flagSet.StringVar(&runpacksCSV, "runpacks", "", "runpack path list (csv of files/directories)")
+	flagSet.StringVar(&junitPath, "junit", "", "write a JUnit coverage summary to this path")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	flagSet.BoolVar(&helpFlag, "help", false, "show help")
+
+	if err := flagSet.Parse(arguments); err != nil {
+		return writePolicyCoverageOutput(jsonOutput, policyCoverageOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	if helpFlag {
+		printPolicyCoverageUsage()
+		return exitOK
+	}
+	if len(flagSet.Args()) > 0 {
+		return writePolicyCoverageOutput(jsonOutput, policyCoverageOutput{OK: false, Error: "unexpected positional arguments"}, exitInvalidInput)
+	}
+	if strings.TrimSpace(policyPath) == "" {
+		return writePolicyCoverageOutput(jsonOutput, policyCoverageOutput{OK: false, Error: "missing required --policy <policy.yaml>"}, exitInvalidInput)
+	}
+	inputs := append(parseCSV(tracesCSV), parseCSV(runpacksCSV)...)
+	if len(inputs) == 0 {
+		return writePolicyCoverageOutput(jsonOutput, policyCoverageOutput{OK: false, Error: "missing required --traces <csv files/dirs> or --runpacks <csv files/dirs>"}, exitInvalidInput)
+	}
+
+	policy, err := gate.LoadPolicyFile(policyPath)
+	if err != nil {
+		return writePolicyCoverageOutput(jsonOutput, policyCoverageOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	samples, loadErrors, err := policytest.LoadReplaySamples(inputs)
+	if err != nil {
+		return writePolicyCoverageOutput(jsonOutput, policyCoverageOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	if len(samples) == 0 {
+		return writePolicyCoverageOutput(jsonOutput, policyCoverageOutput{OK: false, Error: "no trace records or intents discovered"}, exitInvalidInput)
+	}
+
+	report, err := policytest.Coverage(policytest.CoverageOptions{
+		Policy:          policy,
+		Samples:         samples,
+		LoadErrors:      loadErrors,
+		ProducerVersion: currentVersion(),
+	})
+	if err != nil {
+		return writePolicyCoverageOutput(jsonOutput, policyCoverageOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	if strings.TrimSpace(junitPath) != "" {
+		if err := policytest.WriteCoverageJUnit(junitPath, report); err != nil {
+			return writePolicyCoverageOutput(jsonOutput, policyCoverageOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+		}
+	}
+
+	hitRules := len(report.Rules) - len(report.UnreachableRules) - len(report.ShadowedRules)
+	summary := fmt.Sprintf(
+		"policy coverage samples=%d rules_hit=%d/%d unreachable=%d shadowed=%d default_only_tools=%d/%d",
+		report.SamplesEvaluated,
+		hitRules,
+		len(report.Rules),
+		len(report.UnreachableRules),
+		len(report.ShadowedRules),
+		len(report.DefaultOnlyTools),
+		report.ToolsObserved,
+	)
+	return writePolicyCoverageOutput(jsonOutput, policyCoverageOutput{
+		OK:                   true,
+		JUnitPath:            strings.TrimSpace(junitPath),
+		Summary:              summary,
+		PolicyCoverageReport: &report,
+	}, exitOK)
+}
+
+func writePolicyCoverageOutput(jsonOutput bool, output policyCoverageOutput, exitCode int) int {
+	if jsonOutput {
+		return writeJSONOutput(output, exitCode)
+	}
+	if !output.OK {
+		fmt.Printf("policy coverage error: %s\n", output.Error)
+		return exitCode
+	}
+	fmt.Println(output.Summary)
+	for _, rule := range output.Rules {
+		line := fmt.Sprintf("  %s: %s hits=%d", rule.Name, rule.Status, rule.Hits)
+		if len(rule.ShadowedBy) > 0 {
+			line += " shadowed_by=" + strings.Join(rule.ShadowedBy, ",")
+		}
+		fmt.Println(line)
+	}
+	if len(output.DefaultOnlyTools) > 0 {
+		fmt.Printf("  default_verdict only: %s\n", strings.Join(output.DefaultOnlyTools, ","))
+	}
+	for _, sampleErr := range output.Errors {
+		fmt.Printf("  skipped %s: %s\n", sampleErr.Source, sampleErr.Error)
+	}
+	if output.JUnitPath != "" {
+		fmt.Printf("junit: %s\n", output.JUnitPath)
+	}
+	return exitCode
+}
+
+func printPolicyCoverageUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  gait policy coverage --policy <policy.yaml> --traces <csv files/dirs> [--runpacks <csv files/dirs>] [--junit <path>] [--json] [--explain]")
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunPolicyCoverage(t *testing.T) {
+	workDir := t.TempDir()
+	withWorkingDir(t, workDir)
+
+	policyPath := filepath.Join(workDir, "policy.yaml")
+	mustWriteFile(t, policyPath, strings.Join([]string{
+		"default_verdict: allow",
+		"rules:",
+		"  - name: approve-writes",
+		"    effect: require_approval",
+		"    match:",
+		"      tool_names: [tool.write]",
+		"  - name: block-delete",
+		"    effect: block",
+		"    match:",
+		"      tool_names: [tool.delete]",
+	}, "\n")+"\n")
+	tracesDir := filepath.Join(workDir, "traces")
+	if err := os.MkdirAll(tracesDir, 0o750); err != nil {
+		t.Fatalf("mkdir traces: %v", err)
+	}
+	writeIntentFixture(t, filepath.Join(tracesDir, "write.json"), "tool.write")
+	writeIntentFixture(t, filepath.Join(tracesDir, "read.json"), "tool.read")
+	junitPath := filepath.Join(workDir, "coverage.xml")
+
+	var output policyCoverageOutput
+	raw := captureStdout(t, func() {
+		if code := runPolicy([]string{"coverage", "--policy", policyPath, "--traces", tracesDir, "--junit", junitPath, "--json"}); code != exitOK {
+			t.Fatalf("policy coverage: expected %d got %d", exitOK, code)
+		}
+	})
+	if err := json.Unmarshal([]byte(raw), &output); err != nil || output.PolicyCoverageReport == nil {
+		t.Fatalf("decode output %s: %v", raw, err)
+	}
+	if !output.OK || output.SamplesEvaluated != 2 || strings.Join(output.UnreachableRules, ",") != "block-delete" || strings.Join(output.DefaultOnlyTools, ",") != "tool.read" {
+		t.Fatalf("unexpected coverage output: %s", raw)
+	}
+	if _, err := os.Stat(junitPath); err != nil {
+		t.Fatalf("expected junit report: %v", err)
+	}
+
+	if code := runPolicy([]string{"coverage", "--policy", policyPath, "--json"}); code != exitInvalidInput {
+		t.Fatalf("expected missing inputs to fail with %d got %d", exitInvalidInput, code)
+	}
+}
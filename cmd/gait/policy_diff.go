@@ -0,0 +1,147 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/policytest"
+	schemapolicytest "github.com/Clyra-AI/gait/core/schema/v1/policytest"
+)
+
+type policyDiffOutput struct {
+	OK      bool   `json:"ok"`
+	Summary string `json:"summary,omitempty"`
+	Error   string `json:"error,omitempty"`
+	*schemapolicytest.PolicyDiffReport
+}
+
+func runPolicyDiff(arguments []string) int {
+	if hasExplainFlag(arguments) {
+		return writeExplain("Diff two policies rule by rule and replay recorded traces, runpacks, and session journals under both to list intents whose verdict, reason codes, or approval requirements change. Exits non-zero when any decision becomes more permissive or an intent that base evaluates fails under head.")
+	}
+	arguments = reorderInterspersedFlags(arguments, map[string]bool{
+		"base":   true,
+		"head":   true,
+		"corpus": true,
+	})
+
+	flagSet := flag.NewFlagSet("policy-diff", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+
+	var basePath string
+	var headPath string
+	var corpusCSV string
+	var jsonOutput bool
+	var helpFlag bool
+
+	flagSet.StringVar(&basePath, "base", "", "base policy path")
+	flagSet.StringVar(&headPath, "head", "", "head policy path")
+	flagSet.StringVar(&corpusCSV, "corpus", "", "traces, intents, runpacks, and session journals (csv of files/directories)")
+	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
+	flagSet.BoolVar(&helpFlag, "help", false, "show help")
+
+	if err := flagSet.Parse(arguments); err != nil {
+		return writePolicyDiffOutput(jsonOutput, policyDiffOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	if helpFlag {
+		printPolicyDiffUsage()
+		return exitOK
+	}
+	if len(flagSet.Args()) > 0 {
+		return writePolicyDiffOutput(jsonOutput, policyDiffOutput{OK: false, Error: "unexpected positional arguments"}, exitInvalidInput)
+	}
+	if strings.TrimSpace(basePath) == "" || strings.TrimSpace(headPath) == "" {
+		return writePolicyDiffOutput(jsonOutput, policyDiffOutput{OK: false, Error: "missing required --base <policy.yaml> and --head <policy.yaml>"}, exitInvalidInput)
+	}
+
+	basePolicy, err := gate.LoadPolicyFile(basePath)
+	if err != nil {
+		return writePolicyDiffOutput(jsonOutput, policyDiffOutput{OK: false, Error: "base: " + err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	headPolicy, err := gate.LoadPolicyFile(headPath)
+	if err != nil {
+		return writePolicyDiffOutput(jsonOutput, policyDiffOutput{OK: false, Error: "head: " + err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+	var samples []policytest.ReplaySample
+	var loadErrors []schemapolicytest.PolicyReplayError
+	if corpus := parseCSV(corpusCSV); len(corpus) > 0 {
+		samples, loadErrors, err = policytest.LoadReplaySamples(corpus)
+		if err != nil {
+			return writePolicyDiffOutput(jsonOutput, policyDiffOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+		}
+	}
+
+	report, err := policytest.Diff(policytest.DiffOptions{
+		Base:            basePolicy,
+		Head:            headPolicy,
+		Samples:         samples,
+		LoadErrors:      loadErrors,
+		ProducerVersion: currentVersion(),
+	})
+	if err != nil {
+		return writePolicyDiffOutput(jsonOutput, policyDiffOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
+
+	summary := fmt.Sprintf(
+		"policy diff rules added=%d removed=%d changed=%d intents=%d changed=%d more_permissive=%d head_errors=%d",
+		len(report.AddedRules),
+		len(report.RemovedRules),
+		len(report.ChangedRules),
+		report.SamplesEvaluated,
+		report.ChangedIntents,
+		report.MorePermissive,
+		report.HeadErrors,
+	)
+	regressed := report.MorePermissive > 0 || report.HeadErrors > 0
+	exitCode := exitOK
+	if regressed {
+		exitCode = exitRegressFailed
+	}
+	return writePolicyDiffOutput(jsonOutput, policyDiffOutput{
+		OK:               !regressed,
+		Summary:          summary,
+		PolicyDiffReport: &report,
+	}, exitCode)
+}
+
+func writePolicyDiffOutput(jsonOutput bool, output policyDiffOutput, exitCode int) int {
+	if jsonOutput {
+		return writeJSONOutput(output, exitCode)
+	}
+	if output.PolicyDiffReport == nil {
+		fmt.Printf("policy diff error: %s\n", output.Error)
+		return exitCode
+	}
+	fmt.Println(output.Summary)
+	for _, rule := range output.AddedRules {
+		fmt.Printf("  + %s\n", rule)
+	}
+	for _, rule := range output.RemovedRules {
+		fmt.Printf("  - %s\n", rule)
+	}
+	for _, rule := range output.ChangedRules {
+		fmt.Printf("  ~ %s (%s)\n", rule.Name, strings.Join(rule.Fields, ","))
+	}
+	for _, group := range output.Groups {
+		fmt.Printf("rule %s: %d changed, %d more permissive\n", group.Rule, group.Intents, group.MorePermissive)
+	}
+	for _, change := range output.Changes {
+		marker := ""
+		if change.MorePermissive {
+			marker = " [more permissive]"
+		}
+		fmt.Printf("  %s %s: %s -> %s (%s)%s\n", change.Source, change.ToolName, change.BaseVerdict, change.HeadVerdict, strings.Join(change.Changed, ","), marker)
+	}
+	for _, replayErr := range output.Errors {
+		fmt.Printf("  skipped %s: %s\n", replayErr.Source, replayErr.Error)
+	}
+	return exitCode
+}
+
+func printPolicyDiffUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  gait policy diff --base <old.yaml> --head <new.yaml> [--corpus <csv files/dirs>] [--json] [--explain]")
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunPolicyDiff(t *testing.T) {
+	workDir := t.TempDir()
+	withWorkingDir(t, workDir)
+
+	basePath := filepath.Join(workDir, "base.yaml")
+	mustWriteFile(t, basePath, strings.Join([]string{
+		"default_verdict: allow",
+		"rules:",
+		"  - name: approve-writes",
+		"    effect: require_approval",
+		"    match:",
+		"      tool_names: [tool.write]",
+	}, "\n")+"\n")
+	headPath := filepath.Join(workDir, "head.yaml")
+	mustWriteFile(t, headPath, "default_verdict: allow\n")
+	corpusDir := filepath.Join(workDir, "corpus")
+	if err := os.MkdirAll(corpusDir, 0o750); err != nil {
+		t.Fatalf("mkdir corpus: %v", err)
+	}
+	writeIntentFixture(t, filepath.Join(corpusDir, "write.json"), "tool.write")
+
+	var output policyDiffOutput
+	raw := captureStdout(t, func() {
+		if code := runPolicy([]string{"diff", "--base", basePath, "--head", headPath, "--corpus", corpusDir, "--json"}); code != exitRegressFailed {
+			t.Fatalf("policy diff: expected %d got %d", exitRegressFailed, code)
+		}
+	})
+	if err := json.Unmarshal([]byte(raw), &output); err != nil || output.PolicyDiffReport == nil {
+		t.Fatalf("decode output %s: %v", raw, err)
+	}
+	if output.OK || output.MorePermissive != 1 || strings.Join(output.RemovedRules, ",") != "approve-writes" || len(output.Changes) != 1 || output.Changes[0].HeadVerdict != "allow" {
+		t.Fatalf("unexpected diff output: %s", raw)
+	}
+
+	raw = captureStdout(t, func() {
+		if code := runPolicy([]string{"diff", "--base", headPath, "--head", basePath, "--corpus", corpusDir, "--json"}); code != exitOK {
+			t.Fatalf("stricter policy diff: expected %d got %d", exitOK, code)
+		}
+	})
+	if !strings.Contains(raw, `"ok":true`) {
+		t.Fatalf("unexpected stricter diff output: %s", raw)
+	}
+
+	failClosedPath := filepath.Join(workDir, "fail_closed.yaml")
+	mustWriteFile(t, failClosedPath, "default_verdict: allow\nfail_closed:\n  enabled: true\n  risk_classes: [high]\n")
+	invalidDir := filepath.Join(workDir, "invalid")
+	if err := os.MkdirAll(invalidDir, 0o750); err != nil {
+		t.Fatalf("mkdir invalid corpus: %v", err)
+	}
+	writeIntentFixture(t, filepath.Join(invalidDir, "unnamed.json"), "")
+	output = policyDiffOutput{}
+	raw = captureStdout(t, func() {
+		if code := runPolicy([]string{"diff", "--base", failClosedPath, "--head", headPath, "--corpus", invalidDir, "--json"}); code != exitRegressFailed {
+			t.Fatalf("head error diff: expected %d got %d", exitRegressFailed, code)
+		}
+	})
+	if err := json.Unmarshal([]byte(raw), &output); err != nil || output.PolicyDiffReport == nil {
+		t.Fatalf("decode head error output %s: %v", raw, err)
+	}
+	if output.OK || output.HeadErrors != 1 || output.MorePermissive != 0 {
+		t.Fatalf("expected head evaluation error to fail the diff: %s", raw)
+	}
+}
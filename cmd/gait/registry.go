@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	"github.com/Clyra-AI/gait/core/registry"
+	"github.com/Clyra-AI/gait/core/trust"
 	sign "github.com/Clyra-AI/proof/signing"
 )
 
@@ -112,6 +114,7 @@ func runRegistryInstall(arguments []string) int {
 		"public-key-env":        true,
 		"private-key":           true,
 		"private-key-env":       true,
+		"trust-store":           true,
 		"retry-max-attempts":    true,
 		"retry-base-delay":      true,
 		"allow-insecure-http":   true,
@@ -129,6 +132,7 @@ func runRegistryInstall(arguments []string) int {
 	var publicKeyEnv string
 	var privateKeyPath string
 	var privateKeyEnv string
+	var trustStorePath string
 	var retryMaxAttempts int
 	var retryBaseDelay time.Duration
 	var allowInsecureHTTP bool
@@ -145,6 +149,7 @@ func runRegistryInstall(arguments []string) int {
 	flagSet.StringVar(&publicKeyEnv, "public-key-env", "", "env var containing base64 public key")
 	flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private key (derive public)")
 	flagSet.StringVar(&privateKeyEnv, "private-key-env", "", "env var containing base64 private key (derive public)")
+	flagSet.StringVar(&trustStorePath, "trust-store", "", "trust store selecting registry publisher keys by key id (default $"+trust.StorePathEnv+")")
 	flagSet.IntVar(&retryMaxAttempts, "retry-max-attempts", 3, "max remote fetch retry attempts for transient failures")
 	flagSet.DurationVar(&retryBaseDelay, "retry-base-delay", 200*time.Millisecond, "base remote fetch retry delay (for example 200ms, 1s)")
 	flagSet.BoolVar(&allowInsecureHTTP, "allow-insecure-http", false, "allow insecure http sources (unsafe)")
@@ -168,20 +173,28 @@ func runRegistryInstall(arguments []string) int {
 		return writeRegistryInstallOutput(jsonOutput, registryInstallOutput{OK: false, Error: "expected --source <path|url>"}, exitInvalidInput)
 	}
 
-	publicKey, err := sign.LoadVerifyKey(sign.KeyConfig{
-		PublicKeyPath:  publicKeyPath,
-		PublicKeyEnv:   publicKeyEnv,
-		PrivateKeyPath: privateKeyPath,
-		PrivateKeyEnv:  privateKeyEnv,
-	})
+	trustStore, err := loadTrustStore(trustStorePath)
 	if err != nil {
 		return writeRegistryInstallOutput(jsonOutput, registryInstallOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
+	var publicKey ed25519.PublicKey
+	if trustStore == nil {
+		publicKey, err = sign.LoadVerifyKey(sign.KeyConfig{
+			PublicKeyPath:  publicKeyPath,
+			PublicKeyEnv:   publicKeyEnv,
+			PrivateKeyPath: privateKeyPath,
+			PrivateKeyEnv:  privateKeyEnv,
+		})
+		if err != nil {
+			return writeRegistryInstallOutput(jsonOutput, registryInstallOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+		}
+	}
 
 	result, err := registry.Install(context.Background(), registry.InstallOptions{
 		Source:              source,
 		CacheDir:            cacheDir,
 		PublicKey:           publicKey,
+		TrustStore:          trustStore,
 		AllowHosts:          parseCSVList(allowHostsCSV),
 		PublisherAllowlist:  parseCSVList(publisherAllowlistCSV),
 		PinDigest:           pinDigest,
@@ -260,6 +273,7 @@ func runRegistryVerify(arguments []string) int {
 		"public-key-env":      true,
 		"private-key":         true,
 		"private-key-env":     true,
+		"trust-store":         true,
 	})
 	flagSet := flag.NewFlagSet("registry-verify", flag.ContinueOnError)
 	flagSet.SetOutput(io.Discard)
@@ -272,6 +286,7 @@ func runRegistryVerify(arguments []string) int {
 	var publicKeyEnv string
 	var privateKeyPath string
 	var privateKeyEnv string
+	var trustStorePath string
 	var jsonOutput bool
 	var helpFlag bool
 
@@ -283,6 +298,7 @@ func runRegistryVerify(arguments []string) int {
 	flagSet.StringVar(&publicKeyEnv, "public-key-env", "", "env var containing base64 public key")
 	flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private key (derive public)")
 	flagSet.StringVar(&privateKeyEnv, "private-key-env", "", "env var containing base64 private key (derive public)")
+	flagSet.StringVar(&trustStorePath, "trust-store", "", "trust store selecting registry publisher keys by key id (default $"+trust.StorePathEnv+")")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
 
@@ -303,20 +319,28 @@ func runRegistryVerify(arguments []string) int {
 	}
 	publisherAllowlist := parseCSVList(publisherAllowlistCSV)
 
-	publicKey, err := sign.LoadVerifyKey(sign.KeyConfig{
-		PublicKeyPath:  publicKeyPath,
-		PublicKeyEnv:   publicKeyEnv,
-		PrivateKeyPath: privateKeyPath,
-		PrivateKeyEnv:  privateKeyEnv,
-	})
+	trustStore, err := loadTrustStore(trustStorePath)
 	if err != nil {
 		return writeRegistryVerifyOutput(jsonOutput, registryVerifyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
+	var publicKey ed25519.PublicKey
+	if trustStore == nil {
+		publicKey, err = sign.LoadVerifyKey(sign.KeyConfig{
+			PublicKeyPath:  publicKeyPath,
+			PublicKeyEnv:   publicKeyEnv,
+			PrivateKeyPath: privateKeyPath,
+			PrivateKeyEnv:  privateKeyEnv,
+		})
+		if err != nil {
+			return writeRegistryVerifyOutput(jsonOutput, registryVerifyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+		}
+	}
 
 	result, err := registry.Verify(registry.VerifyOptions{
 		MetadataPath:       metadataPath,
 		CacheDir:           cacheDir,
 		PublicKey:          publicKey,
+		TrustStore:         trustStore,
 		PublisherAllowlist: publisherAllowlist,
 	})
 	if err != nil {
@@ -470,14 +494,14 @@ func writeRegistryVerifyOutput(jsonOutput bool, output registryVerifyOutput, exi
 
 func printRegistryUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait registry install --source <path|url> --allow-host <csv> [--publisher-allowlist <csv>] [--pin sha256:<hex>] [--cache-dir <path>] [--public-key <path>|--public-key-env <VAR>] [--retry-max-attempts <n>] [--retry-base-delay <duration>] [--allow-cached-fallback] [--allow-insecure-http] [--json] [--explain]")
+	fmt.Println("  gait registry install --source <path|url> --allow-host <csv> [--publisher-allowlist <csv>] [--pin sha256:<hex>] [--cache-dir <path>] [--public-key <path>|--public-key-env <VAR>|--trust-store <trust_store.json>] [--retry-max-attempts <n>] [--retry-base-delay <duration>] [--allow-cached-fallback] [--allow-insecure-http] [--json] [--explain]")
 	fmt.Println("  gait registry list [--cache-dir <path>] [--json] [--explain]")
-	fmt.Println("  gait registry verify --path <registry_pack.json> [--cache-dir <path>] [--publisher-allowlist <csv>] [--report-out <path>] [--public-key <path>|--public-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait registry verify --path <registry_pack.json> [--cache-dir <path>] [--publisher-allowlist <csv>] [--report-out <path>] [--public-key <path>|--public-key-env <VAR>|--trust-store <trust_store.json>] [--json] [--explain]")
 }
 
 func printRegistryInstallUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait registry install --source <path|url> --allow-host <csv> [--publisher-allowlist <csv>] [--pin sha256:<hex>] [--cache-dir <path>] [--public-key <path>|--public-key-env <VAR>] [--retry-max-attempts <n>] [--retry-base-delay <duration>] [--allow-cached-fallback] [--allow-insecure-http] [--json] [--explain]")
+	fmt.Println("  gait registry install --source <path|url> --allow-host <csv> [--publisher-allowlist <csv>] [--pin sha256:<hex>] [--cache-dir <path>] [--public-key <path>|--public-key-env <VAR>|--trust-store <trust_store.json>] [--retry-max-attempts <n>] [--retry-base-delay <duration>] [--allow-cached-fallback] [--allow-insecure-http] [--json] [--explain]")
 }
 
 func printRegistryListUsage() {
@@ -487,7 +511,7 @@ func printRegistryListUsage() {
 
 func printRegistryVerifyUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait registry verify --path <registry_pack.json> [--cache-dir <path>] [--publisher-allowlist <csv>] [--report-out <path>] [--public-key <path>|--public-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait registry verify --path <registry_pack.json> [--cache-dir <path>] [--publisher-allowlist <csv>] [--report-out <path>] [--public-key <path>|--public-key-env <VAR>|--trust-store <trust_store.json>] [--json] [--explain]")
 }
 
 func uniqueSortedStrings(values []string) []string {
@@ -296,10 +296,10 @@ func writeReplayOutput(jsonOutput bool, output replayOutput, exitCode int) int {
 
 func printRunUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait run record --input <run_record.json> [--out-dir gait-out] [--run-id <run_id>] [--capture-mode reference|raw] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait run record --input <run_record.json> [--out-dir gait-out] [--run-id <run_id>] [--capture-mode reference|raw] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--redaction-policy <policy.yaml>] [--json] [--explain]")
 	fmt.Println("  gait run inspect --from <run_id|path> [--json] [--explain]")
 	fmt.Println("  gait run session start --journal <path> --session-id <id> --run-id <run_id> [--json]")
-	fmt.Println("  gait run session append --journal <path> --tool <name> --verdict <allow|block|dry_run|require_approval> [--intent-id <id>] [--trace-id <id>] [--trace-path <path>] [--intent-digest <sha256>] [--policy-digest <sha256>] [--reason-codes <csv>] [--violations <csv>] [--json]")
+	fmt.Println("  gait run session append --journal <path> --tool <name> --verdict <allow|block|dry_run|require_approval> [--intent-id <id>] [--trace-id <id>] [--trace-path <path>] [--intent-digest <sha256>] [--policy-digest <sha256>] [--reason-codes <csv>] [--violations <csv>] [--redaction-policy <policy.yaml>] [--json]")
 	fmt.Println("  gait run session status --journal <path> [--json]")
 	fmt.Println("  gait run session checkpoint --journal <path> --out <runpack.zip> [--json]")
 	fmt.Println("  gait run session compact --journal <path> [--out <journal.jsonl>] [--dry-run] [--json]")
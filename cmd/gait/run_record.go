@@ -10,6 +10,8 @@ import (
 	"strings"
 
 	"github.com/Clyra-AI/gait/core/contextproof"
+	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/redact"
 	"github.com/Clyra-AI/gait/core/runpack"
 	schemacontext "github.com/Clyra-AI/gait/core/schema/v1/context"
 	schemarunpack "github.com/Clyra-AI/gait/core/schema/v1/runpack"
@@ -37,6 +39,7 @@ type runRecordOutput struct {
 	ManifestDigest  string   `json:"manifest_digest,omitempty"`
 	SignatureStatus string   `json:"signature_status,omitempty"`
 	SignatureKeyID  string   `json:"signature_key_id,omitempty"`
+	Redactions      int      `json:"redactions,omitempty"`
 	Warnings        []string `json:"warnings,omitempty"`
 	TicketFooter    string   `json:"ticket_footer,omitempty"`
 	Error           string   `json:"error,omitempty"`
@@ -62,6 +65,7 @@ func runRecord(arguments []string) int {
 		"key-mode":              true,
 		"private-key":           true,
 		"private-key-env":       true,
+		"redaction-policy":      true,
 	})
 
 	flagSet := flag.NewFlagSet("record", flag.ContinueOnError)
@@ -78,6 +82,7 @@ func runRecord(arguments []string) int {
 	var contextEnvelopePath string
 	var contextEvidenceMode string
 	var unsafeContextRaw bool
+	var redactionPolicyPath string
 	var helpFlag bool
 
 	flagSet.StringVar(&inputPath, "input", "", "path to run record JSON input")
@@ -90,6 +95,7 @@ func runRecord(arguments []string) int {
 	flagSet.StringVar(&contextEnvelopePath, "context-envelope", "", "path to context evidence envelope JSON (optional)")
 	flagSet.StringVar(&contextEvidenceMode, "context-evidence-mode", "", "context evidence mode: best_effort|required (optional)")
 	flagSet.BoolVar(&unsafeContextRaw, "unsafe-context-raw", false, "allow context receipts with redaction_mode=raw (unsafe)")
+	flagSet.StringVar(&redactionPolicyPath, "redaction-policy", "", "gate policy whose redaction section is applied to recorded args and results (optional)")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
 
@@ -227,6 +233,10 @@ func runRecord(arguments []string) int {
 		}, exitInvalidInput)
 	}
 	captureWarnings := captureModeWarnings(recordInput, resolvedCaptureMode)
+	redactor, err := loadPolicyRedactor(redactionPolicyPath)
+	if err != nil {
+		return writeRunRecordOutput(jsonOutput, runRecordOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
 	if signingRequested {
 		resolvedMode := strings.ToLower(strings.TrimSpace(keyMode))
 		if resolvedMode == "" {
@@ -255,6 +265,7 @@ func runRecord(arguments []string) int {
 			IntentArgs:     recordInput.Normalization.IntentArgs,
 			ResultPayloads: recordInput.Normalization.ResultPayloads,
 		},
+		Redactor: redactor,
 	})
 	if err != nil {
 		return writeRunRecordOutput(jsonOutput, runRecordOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
@@ -285,11 +296,29 @@ func runRecord(arguments []string) int {
 		ManifestDigest:  result.Manifest.ManifestDigest,
 		SignatureStatus: signatureStatus,
 		SignatureKeyID:  signatureKeyID,
+		Redactions:      result.RedactionCount,
 		Warnings:        append(captureWarnings, signingWarnings...),
 		TicketFooter:    ticketFooter,
 	}, exitOK)
 }
 
+// loadPolicyRedactor builds the redactor configured by a gate policy's
+// redaction section. An empty path or a policy without one returns nil.
+func loadPolicyRedactor(policyPath string) (*redact.Redactor, error) {
+	if strings.TrimSpace(policyPath) == "" {
+		return nil, nil
+	}
+	policy, err := gate.LoadPolicyFile(strings.TrimSpace(policyPath))
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := redact.New(policy.Redaction)
+	if err != nil {
+		return nil, fmt.Errorf("policy redaction: %w", err)
+	}
+	return redactor, nil
+}
+
 func readRunRecordInput(path string) (runRecordInput, error) {
 	// #nosec G304 -- explicit user-supplied local file path.
 	content, err := os.ReadFile(path)
@@ -333,8 +362,8 @@ func writeRunRecordOutput(jsonOutput bool, output runRecordOutput, exitCode int)
 
 func printRecordUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait run record --input <run_record.json> [--out-dir gait-out] [--run-id <run_id>] [--capture-mode reference|raw] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--context-envelope <path>] [--context-evidence-mode best_effort|required] [--unsafe-context-raw] [--json] [--explain]")
-	fmt.Println("  gait run record <run_record.json> [--out-dir gait-out] [--run-id <run_id>] [--capture-mode reference|raw] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--context-envelope <path>] [--context-evidence-mode best_effort|required] [--unsafe-context-raw] [--json] [--explain]")
+	fmt.Println("  gait run record --input <run_record.json> [--out-dir gait-out] [--run-id <run_id>] [--capture-mode reference|raw] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--context-envelope <path>] [--context-evidence-mode best_effort|required] [--unsafe-context-raw] [--redaction-policy <policy.yaml>] [--json] [--explain]")
+	fmt.Println("  gait run record <run_record.json> [--out-dir gait-out] [--run-id <run_id>] [--capture-mode reference|raw] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--context-envelope <path>] [--context-evidence-mode best_effort|required] [--unsafe-context-raw] [--redaction-policy <policy.yaml>] [--json] [--explain]")
 }
 
 func hasRawContextRecord(records []schemacontext.ReferenceRecord) bool {
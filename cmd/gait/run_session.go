@@ -119,16 +119,17 @@ func runSessionStart(arguments []string) int {
 
 func runSessionAppend(arguments []string) int {
 	arguments = reorderInterspersedFlags(arguments, map[string]bool{
-		"journal":       true,
-		"tool":          true,
-		"verdict":       true,
-		"intent-id":     true,
-		"trace-id":      true,
-		"trace-path":    true,
-		"intent-digest": true,
-		"policy-digest": true,
-		"reason-codes":  true,
-		"violations":    true,
+		"journal":          true,
+		"tool":             true,
+		"verdict":          true,
+		"intent-id":        true,
+		"trace-id":         true,
+		"trace-path":       true,
+		"intent-digest":    true,
+		"policy-digest":    true,
+		"reason-codes":     true,
+		"violations":       true,
+		"redaction-policy": true,
 	})
 	flagSet := flag.NewFlagSet("run-session-append", flag.ContinueOnError)
 	flagSet.SetOutput(io.Discard)
@@ -143,6 +144,7 @@ func runSessionAppend(arguments []string) int {
 	var policyDigest string
 	var reasonCodesCSV string
 	var violationsCSV string
+	var redactionPolicyPath string
 	var jsonOutput bool
 	var helpFlag bool
 
@@ -156,6 +158,7 @@ func runSessionAppend(arguments []string) int {
 	flagSet.StringVar(&policyDigest, "policy-digest", "", "optional policy digest (sha256)")
 	flagSet.StringVar(&reasonCodesCSV, "reason-codes", "", "comma-separated reason codes")
 	flagSet.StringVar(&violationsCSV, "violations", "", "comma-separated violations")
+	flagSet.StringVar(&redactionPolicyPath, "redaction-policy", "", "gate policy whose redaction section is applied to the event (optional)")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
 
@@ -192,6 +195,14 @@ func runSessionAppend(arguments []string) int {
 		}, exitInvalidInput)
 	}
 
+	redactor, err := loadPolicyRedactor(redactionPolicyPath)
+	if err != nil {
+		return writeRunSessionOutput(jsonOutput, runSessionOutput{
+			OK:        false,
+			Operation: "append",
+			Error:     err.Error(),
+		}, exitCodeForError(err, exitInvalidInput))
+	}
 	event, err := runpack.AppendSessionEvent(journal, runpack.SessionAppendOptions{
 		ProducerVersion: currentVersion(),
 		IntentID:        intentID,
@@ -203,6 +214,7 @@ func runSessionAppend(arguments []string) int {
 		Verdict:         strings.ToLower(strings.TrimSpace(verdict)),
 		ReasonCodes:     parseCSV(reasonCodesCSV),
 		Violations:      parseCSV(violationsCSV),
+		Redactor:        redactor,
 	})
 	if err != nil {
 		return writeRunSessionOutput(jsonOutput, runSessionOutput{
@@ -547,7 +559,7 @@ func isSessionVerdictSupported(value string) bool {
 func printRunSessionUsage() {
 	fmt.Println("Usage:")
 	fmt.Println("  gait run session start --journal <path> --session-id <id> --run-id <run_id> [--json] [--explain]")
-	fmt.Println("  gait run session append --journal <path> --tool <name> --verdict <allow|block|dry_run|require_approval> [--intent-id <id>] [--trace-id <id>] [--trace-path <path>] [--intent-digest <sha256>] [--policy-digest <sha256>] [--reason-codes <csv>] [--violations <csv>] [--json] [--explain]")
+	fmt.Println("  gait run session append --journal <path> --tool <name> --verdict <allow|block|dry_run|require_approval> [--intent-id <id>] [--trace-id <id>] [--trace-path <path>] [--intent-digest <sha256>] [--policy-digest <sha256>] [--reason-codes <csv>] [--violations <csv>] [--redaction-policy <policy.yaml>] [--json] [--explain]")
 	fmt.Println("  gait run session status --journal <path> [--json] [--explain]")
 	fmt.Println("  gait run session checkpoint --journal <path> --out <runpack.zip> [--chain-out <session_chain.json>] [--json] [--explain]")
 	fmt.Println("  gait run session compact --journal <path> [--out <journal.jsonl>] [--dry-run] [--json] [--explain]")
@@ -560,7 +572,7 @@ func printRunSessionStartUsage() {
 
 func printRunSessionAppendUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait run session append --journal <path> --tool <name> --verdict <allow|block|dry_run|require_approval> [--intent-id <id>] [--trace-id <id>] [--trace-path <path>] [--intent-digest <sha256>] [--policy-digest <sha256>] [--reason-codes <csv>] [--violations <csv>] [--json] [--explain]")
+	fmt.Println("  gait run session append --journal <path> --tool <name> --verdict <allow|block|dry_run|require_approval> [--intent-id <id>] [--trace-id <id>] [--trace-path <path>] [--intent-digest <sha256>] [--policy-digest <sha256>] [--reason-codes <csv>] [--violations <csv>] [--redaction-policy <policy.yaml>] [--json] [--explain]")
 }
 
 func printRunSessionStatusUsage() {
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	schemascout "github.com/Clyra-AI/gait/core/schema/v1/scout"
 	"github.com/Clyra-AI/gait/core/scout"
@@ -75,6 +76,7 @@ func runScoutSnapshot(arguments []string) int {
 		"policy":       true,
 		"out":          true,
 		"coverage-out": true,
+		"live-timeout": true,
 	})
 	flagSet := flag.NewFlagSet("scout-snapshot", flag.ContinueOnError)
 	flagSet.SetOutput(io.Discard)
@@ -85,6 +87,8 @@ func runScoutSnapshot(arguments []string) int {
 	var policyCSV string
 	var outPath string
 	var coverageOutPath string
+	var liveMCP bool
+	var liveTimeout time.Duration
 	var jsonOutput bool
 	var helpFlag bool
 
@@ -94,6 +98,8 @@ func runScoutSnapshot(arguments []string) int {
 	flagSet.StringVar(&policyCSV, "policy", "", "comma-separated policy files for coverage")
 	flagSet.StringVar(&outPath, "out", "", "path to write snapshot JSON")
 	flagSet.StringVar(&coverageOutPath, "coverage-out", "", "path to write coverage JSON")
+	flagSet.BoolVar(&liveMCP, "live-mcp", false, "launch or connect to declared MCP servers and record the tools they list")
+	flagSet.DurationVar(&liveTimeout, "live-timeout", 10*time.Second, "per-server timeout for --live-mcp")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
 
@@ -108,7 +114,14 @@ func runScoutSnapshot(arguments []string) int {
 		return writeScoutSnapshotOutput(jsonOutput, scoutSnapshotOutput{OK: false, Error: "unexpected positional arguments"}, exitInvalidInput)
 	}
 
-	provider := scout.DefaultProvider{Options: scout.SnapshotOptions{ProducerVersion: currentVersion()}}
+	options := scout.SnapshotOptions{ProducerVersion: currentVersion()}
+	var provider scout.InventoryProvider = scout.DefaultProvider{Options: options}
+	if liveMCP {
+		provider = scout.CompositeProvider{Options: options, Providers: []scout.InventoryProvider{
+			scout.DefaultProvider{Options: options},
+			scout.LiveMCPProvider{Options: options, Timeout: liveTimeout},
+		}}
+	}
 	snapshot, err := provider.Snapshot(context.Background(), scout.SnapshotRequest{
 		Roots:   parseCSVList(rootsCSV),
 		Include: parseCSVList(includeCSV),
@@ -412,7 +425,7 @@ func printScoutUsage() {
 
 func printScoutSnapshotUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait scout snapshot [--roots <csv>] [--include <csv>] [--exclude <csv>] [--policy <csv>] [--out <snapshot.json>] [--coverage-out <coverage.json>] [--json] [--explain]")
+	fmt.Println("  gait scout snapshot [--roots <csv>] [--include <csv>] [--exclude <csv>] [--policy <csv>] [--live-mcp] [--live-timeout <duration>] [--out <snapshot.json>] [--coverage-out <coverage.json>] [--json] [--explain]")
 }
 
 func printScoutDiffUsage() {
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/trust"
 	sign "github.com/Clyra-AI/proof/signing"
 )
 
@@ -63,6 +64,7 @@ func runTraceVerify(arguments []string) int {
 		"public-key-env":  true,
 		"private-key":     true,
 		"private-key-env": true,
+		"trust-store":     true,
 	})
 	if len(arguments) > 0 && !strings.HasPrefix(arguments[0], "-") {
 		arguments = append([]string{"--path", arguments[0]}, arguments[1:]...)
@@ -77,6 +79,7 @@ func runTraceVerify(arguments []string) int {
 	var publicKeyEnv string
 	var privateKeyPath string
 	var privateKeyEnv string
+	var trustStorePath string
 	var helpFlag bool
 
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
@@ -85,6 +88,7 @@ func runTraceVerify(arguments []string) int {
 	flagSet.StringVar(&publicKeyEnv, "public-key-env", "", "env var containing base64 public key")
 	flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private key (derive public)")
 	flagSet.StringVar(&privateKeyEnv, "private-key-env", "", "env var containing base64 private key (derive public)")
+	flagSet.StringVar(&trustStorePath, "trust-store", "", "trust store selecting the verify key by key id (default $"+trust.StorePathEnv+")")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
 
 	if err := flagSet.Parse(arguments); err != nil {
@@ -108,17 +112,25 @@ func runTraceVerify(arguments []string) int {
 	if err != nil {
 		return writeTraceVerifyOutput(jsonOutput, traceVerifyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
-	publicKey, err := sign.LoadVerifyKey(sign.KeyConfig{
-		PublicKeyPath:  publicKeyPath,
-		PublicKeyEnv:   publicKeyEnv,
-		PrivateKeyPath: privateKeyPath,
-		PrivateKeyEnv:  privateKeyEnv,
-	})
+	trustStore, err := loadTrustStore(trustStorePath)
 	if err != nil {
 		return writeTraceVerifyOutput(jsonOutput, traceVerifyOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
-
-	ok, err := gate.VerifyTraceRecordSignature(record, publicKey)
+	var ok bool
+	if trustStore != nil {
+		ok, err = gate.VerifyTraceRecordSignatureWithTrustStore(record, trustStore)
+	} else {
+		publicKey, keyErr := sign.LoadVerifyKey(sign.KeyConfig{
+			PublicKeyPath:  publicKeyPath,
+			PublicKeyEnv:   publicKeyEnv,
+			PrivateKeyPath: privateKeyPath,
+			PrivateKeyEnv:  privateKeyEnv,
+		})
+		if keyErr != nil {
+			return writeTraceVerifyOutput(jsonOutput, traceVerifyOutput{OK: false, Error: keyErr.Error()}, exitCodeForError(keyErr, exitInvalidInput))
+		}
+		ok, err = gate.VerifyTraceRecordSignature(record, publicKey)
+	}
 	if err != nil {
 		return writeTraceVerifyOutput(jsonOutput, traceVerifyOutput{
 			OK:              false,
@@ -172,10 +184,10 @@ func writeTraceVerifyOutput(jsonOutput bool, output traceVerifyOutput, exitCode
 func printTraceUsage() {
 	fmt.Println("Usage:")
 	fmt.Println("  gait trace [--cwd .] [--timeout 30s] [--json] -- <child command...>")
-	fmt.Println("  gait trace verify <path> [--json] [--public-key <path>] [--public-key-env <VAR>] [--private-key <path>] [--private-key-env <VAR>] [--explain]")
+	fmt.Println("  gait trace verify <path> [--json] [--public-key <path>] [--public-key-env <VAR>] [--private-key <path>] [--private-key-env <VAR>] [--trust-store <trust_store.json>] [--explain]")
 }
 
 func printTraceVerifyUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait trace verify <path> [--json] [--public-key <path>] [--public-key-env <VAR>] [--private-key <path>] [--private-key-env <VAR>] [--explain]")
+	fmt.Println("  gait trace verify <path> [--json] [--public-key <path>] [--public-key-env <VAR>] [--private-key <path>] [--private-key-env <VAR>] [--trust-store <trust_store.json>] [--explain]")
 }
@@ -25,11 +25,12 @@ type uiOutput struct {
 
 func runUI(arguments []string) int {
 	if hasExplainFlag(arguments) {
-		return writeExplain("Run an optional localhost UI that orchestrates existing Gait commands for first-run, artifact, and regression workflows.")
+		return writeExplain("Run an optional localhost UI that orchestrates existing Gait commands and browses local traces, runpacks, sessions, and pending approvals.")
 	}
 	arguments = reorderInterspersedFlags(arguments, map[string]bool{
 		"listen":             true,
 		"open-browser":       true,
+		"approval-queue":     true,
 		"allow-non-loopback": false,
 	})
 
@@ -38,12 +39,14 @@ func runUI(arguments []string) int {
 
 	var listenAddr string
 	var openBrowser bool
+	var approvalQueueDir string
 	var allowNonLoopback bool
 	var jsonOutput bool
 	var helpFlag bool
 
 	flagSet.StringVar(&listenAddr, "listen", "127.0.0.1:7980", "listen address for localhost UI server")
 	flagSet.BoolVar(&openBrowser, "open-browser", true, "open UI URL in default browser after startup")
+	flagSet.StringVar(&approvalQueueDir, "approval-queue", defaultApprovalQueueDir, "approval queue directory shown in the approvals view")
 	flagSet.BoolVar(&allowNonLoopback, "allow-non-loopback", false, "allow non-loopback listen addresses")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit startup JSON")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
@@ -82,9 +85,10 @@ func runUI(arguments []string) int {
 		return writeUIOutput(jsonOutput, uiOutput{OK: false, Error: staticErr.Error()}, exitCodeForError(staticErr, exitInternalFailure))
 	}
 	apiHandler, apiErr := coreui.NewHandler(coreui.Config{
-		ExecutablePath: executablePath,
-		WorkDir:        ".",
-		CommandTimeout: 2 * time.Minute,
+		ExecutablePath:   executablePath,
+		WorkDir:          ".",
+		CommandTimeout:   2 * time.Minute,
+		ApprovalQueueDir: approvalQueueDir,
 	}, staticHandler)
 	if apiErr != nil {
 		return writeUIOutput(jsonOutput, uiOutput{OK: false, Error: apiErr.Error()}, exitCodeForError(apiErr, exitInternalFailure))
@@ -139,7 +143,7 @@ func writeUIOutput(jsonOutput bool, output uiOutput, exitCode int) int {
 
 func printUIUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait ui [--listen 127.0.0.1:7980] [--open-browser=true|false] [--allow-non-loopback] [--json] [--explain]")
+	fmt.Println("  gait ui [--listen 127.0.0.1:7980] [--open-browser=true|false] [--approval-queue <dir>] [--allow-non-loopback] [--json] [--explain]")
 }
 
 func openInBrowser(url string) error {
@@ -10,6 +10,7 @@ import (
 	"github.com/Clyra-AI/gait/core/gate"
 	"github.com/Clyra-AI/gait/core/guard"
 	"github.com/Clyra-AI/gait/core/runpack"
+	"github.com/Clyra-AI/gait/core/trust"
 	exitcode "github.com/Clyra-AI/proof/exitcode"
 	sign "github.com/Clyra-AI/proof/signing"
 )
@@ -171,6 +172,7 @@ func runVerifyChain(arguments []string) int {
 		"public-key-env":  true,
 		"private-key":     true,
 		"private-key-env": true,
+		"trust-store":     true,
 	})
 	flagSet := flag.NewFlagSet("verify-chain", flag.ContinueOnError)
 	flagSet.SetOutput(io.Discard)
@@ -184,6 +186,7 @@ func runVerifyChain(arguments []string) int {
 	var publicKeyEnv string
 	var privateKeyPath string
 	var privateKeyEnv string
+	var trustStorePath string
 	var jsonOutput bool
 	var helpFlag bool
 
@@ -196,6 +199,7 @@ func runVerifyChain(arguments []string) int {
 	flagSet.StringVar(&publicKeyEnv, "public-key-env", "", "env var containing base64 public key")
 	flagSet.StringVar(&privateKeyPath, "private-key", "", "path to base64 private key (derive public)")
 	flagSet.StringVar(&privateKeyEnv, "private-key-env", "", "env var containing base64 private key (derive public)")
+	flagSet.StringVar(&trustStorePath, "trust-store", "", "trust store selecting the trace verify key by key id (default $"+trust.StorePathEnv+")")
 	flagSet.BoolVar(&jsonOutput, "json", false, "emit JSON output")
 	flagSet.BoolVar(&helpFlag, "help", false, "show help")
 
@@ -236,6 +240,10 @@ func runVerifyChain(arguments []string) int {
 	if err != nil {
 		return writeVerifyChainOutput(jsonOutput, verifyChainOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
 	}
+	trustStore, err := loadTrustStore(trustStorePath)
+	if err != nil {
+		return writeVerifyChainOutput(jsonOutput, verifyChainOutput{OK: false, Error: err.Error()}, exitCodeForError(err, exitInvalidInput))
+	}
 
 	runOutput, err := verifyRunpackArtifact(runPath, requireSignature, publicKey)
 	if err != nil {
@@ -247,7 +255,7 @@ func runVerifyChain(arguments []string) int {
 	}
 
 	if strings.TrimSpace(tracePath) != "" {
-		traceOut, traceErr := verifyTraceArtifact(tracePath, publicKey)
+		traceOut, traceErr := verifyTraceArtifact(tracePath, publicKey, trustStore)
 		if traceErr != nil {
 			return writeVerifyChainOutput(jsonOutput, verifyChainOutput{OK: false, Run: runOutput, Error: traceErr.Error()}, exitCodeForError(traceErr, exitInvalidInput))
 		}
@@ -446,19 +454,24 @@ func verifyNextCommands(runID string) []string {
 	}
 }
 
-func verifyTraceArtifact(path string, publicKey ed25519.PublicKey) (traceVerifyOutput, error) {
+func verifyTraceArtifact(path string, publicKey ed25519.PublicKey, trustStore *trust.Store) (traceVerifyOutput, error) {
 	tracePath := strings.TrimSpace(path)
 	if tracePath == "" {
 		return traceVerifyOutput{}, fmt.Errorf("trace path is required")
 	}
-	if len(publicKey) == 0 {
-		return traceVerifyOutput{}, fmt.Errorf("trace verification requires --public-key/--public-key-env or private key source")
+	if len(publicKey) == 0 && trustStore == nil {
+		return traceVerifyOutput{}, fmt.Errorf("trace verification requires --public-key/--public-key-env, private key source or --trust-store")
 	}
 	record, err := gate.ReadTraceRecord(tracePath)
 	if err != nil {
 		return traceVerifyOutput{}, err
 	}
-	ok, err := gate.VerifyTraceRecordSignature(record, publicKey)
+	var ok bool
+	if trustStore != nil {
+		ok, err = gate.VerifyTraceRecordSignatureWithTrustStore(record, trustStore)
+	} else {
+		ok, err = gate.VerifyTraceRecordSignature(record, publicKey)
+	}
 	if err != nil {
 		return traceVerifyOutput{
 			OK:              false,
@@ -638,6 +651,7 @@ func writeVerifySessionChainOutput(jsonOutput bool, output verifySessionChainOut
 func printUsage() {
 	fmt.Println("Usage:")
 	fmt.Println("  gait approve --intent-digest <sha256> --policy-digest <sha256> --ttl <duration> --scope <csv> --approver <identity> --reason-code <code> [--json] [--explain]")
+	fmt.Println("  gait approve list|show|grant|deny [--queue <dir>] [--request-id <id>] [--json]")
 	fmt.Println("  gait approve-script --policy <policy.yaml> --intent <script_intent.json> --registry <registry.json> --approver <identity> [--pattern-id <id>] [--ttl <duration>] [--key-mode dev|prod] [--private-key <path>|--private-key-env <VAR>] [--json] [--explain]")
 	fmt.Println("  gait init [--template baseline-lowrisk|baseline-mediumrisk|baseline-highrisk] [--out .gait.yaml] [--force] [--json] [--explain]")
 	fmt.Println("  gait check [--policy .gait.yaml] [--json] [--explain]")
@@ -648,17 +662,18 @@ func printUsage() {
 	fmt.Println("  gait doctor [--production-readiness] [--json] [--explain]")
 	fmt.Println("  gait doctor adoption --from <events.jsonl> [--json] [--explain]")
 	fmt.Println("  gait list-scripts --registry <registry.json> [--json] [--explain]")
-	fmt.Println("  gait gate eval --policy <policy.yaml> --intent <intent.json> [--context-envelope <context_envelope.json>] [--profile standard|oss-prod] [--simulate] [--approval-token <token.json>] [--approval-token-chain <csv>] [--credential-broker off|stub|env|command] [--json] [--explain]")
+	fmt.Println("  gait gate eval --policy <policy.yaml> --intent <intent.json> [--context-envelope <context_envelope.json>] [--profile standard|oss-prod] [--simulate] [--approval-token <token.json>] [--approval-token-chain <csv>] [--credential-broker off|stub|env|command|vault_dynamic|aws_sts|github_app] [--json] [--explain]")
 	fmt.Println("  gait policy init <baseline-lowrisk|baseline-mediumrisk|baseline-highrisk> [--out gait.policy.yaml] [--force] [--json] [--explain]")
-	fmt.Println("  gait policy validate <policy.yaml> [--json] [--explain]")
+	fmt.Println("  gait policy validate <policy.yaml> [--registry-cache-dir <dir>] [--json] [--explain]")
 	fmt.Println("  gait policy fmt <policy.yaml> [--write] [--json] [--explain]")
 	fmt.Println("  gait policy simulate --policy <candidate.yaml> --baseline <baseline.yaml> --fixtures <csv files/dirs> [--json] [--explain]")
 	fmt.Println("  gait policy test <policy.yaml> <intent_fixture.json> [--json] [--explain]")
 	fmt.Println("  gait keys init [--out-dir gait-out/keys] [--prefix gait] [--force] [--json] [--explain]")
-	fmt.Println("  gait keys rotate [--out-dir gait-out/keys] [--prefix gait] [--json] [--explain]")
+	fmt.Println("  gait keys rotate [--out-dir gait-out/keys] [--prefix gait] [--trust-store <trust_store.json> [--roles <csv>] [--identity <id>]] [--json] [--explain]")
 	fmt.Println("  gait keys verify [--private-key <path>|--private-key-env <VAR>] [--public-key <path>|--public-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait keys trust add|revoke|list --store <trust_store.json> [--json]")
 	fmt.Println("  gait trace [--cwd .] [--timeout 30s] [--json] -- <child command...>")
-	fmt.Println("  gait trace verify <path> [--json] [--public-key <path>] [--public-key-env <VAR>] [--explain]")
+	fmt.Println("  gait trace verify <path> [--json] [--public-key <path>] [--public-key-env <VAR>] [--trust-store <trust_store.json>] [--explain]")
 	fmt.Println("  gait test [--cwd .] [--timeout 30s] [--json] -- <child command...>")
 	fmt.Println("  gait enforce [--cwd .] [--timeout 30s] [--json] -- <child command...>")
 	fmt.Println("  gait capture --from <run_id|runpack.zip|trace.json|session_chain.json> [--checkpoint latest|<index>] [--out ./gait-out/capture.json] [--json] [--explain]")
@@ -671,12 +686,16 @@ func printUsage() {
 	fmt.Println("  gait run diff <left> <right> [--json] [--explain]")
 	fmt.Println("  gait run reduce --from <run_id|path> [--predicate missing_result|non_ok_status] [--json] [--explain]")
 	fmt.Println("  gait run session start --journal <path> --session-id <id> --run-id <run_id> [--json] [--explain]")
-	fmt.Println("  gait run session append --journal <path> --tool <name> --verdict <allow|block|dry_run|require_approval> [--intent-id <id>] [--trace-id <id>] [--trace-path <path>] [--intent-digest <sha256>] [--policy-digest <sha256>] [--reason-codes <csv>] [--violations <csv>] [--json] [--explain]")
+	fmt.Println("  gait run session append --journal <path> --tool <name> --verdict <allow|block|dry_run|require_approval> [--intent-id <id>] [--trace-id <id>] [--trace-path <path>] [--intent-digest <sha256>] [--policy-digest <sha256>] [--reason-codes <csv>] [--violations <csv>] [--redaction-policy <policy.yaml>] [--json] [--explain]")
 	fmt.Println("  gait run session status --journal <path> [--json] [--explain]")
 	fmt.Println("  gait run session checkpoint --journal <path> --out <runpack.zip> [--chain-out <session_chain.json>] [--json] [--explain]")
 	fmt.Println("  gait run session compact --journal <path> [--out <journal.jsonl>] [--dry-run] [--json] [--explain]")
-	fmt.Println("  gait job submit --id <job_id> [--policy <policy.yaml>|--policy-digest <sha256>] [--identity <id>] [--json] [--explain]")
+	fmt.Println("  gait job submit --id <job_id> [--policy <policy.yaml>|--policy-digest <sha256>] [--identity <id>] [--max-tool-calls <n>] [--spend-ceiling <amount>] [--json] [--explain]")
 	fmt.Println("  gait job status --id <job_id> [--json] [--explain]")
+	fmt.Println("  gait job run --id <job_id> [--max-duration <dur>] [--max-steps <n>] [--control-listen <addr>|--control-socket <path>] [--json] [--explain] -- <agent command...>")
+	fmt.Println("  gait job constraints --id <job_id> [--max-tool-calls <n>] [--max-destructive-ops <n>] [--allowed-tools <csv>] [--spend-ceiling <amount>] [--json] [--explain]")
+	fmt.Println("  gait notify flush|list|test [--config .gait/config.yaml] [--json] [--explain]")
+	fmt.Println("  gait otel flush [--config .gait/config.yaml] [--json] [--explain]")
 	fmt.Println("  gait pack build --type <run|job|call> --from <id|path> [--json] [--explain]")
 	fmt.Println("  gait pack verify <pack.zip> [--profile standard|strict] [--json] [--explain]")
 	fmt.Println("  gait pack inspect <pack.zip> [--json] [--explain]")
@@ -689,10 +708,12 @@ func printUsage() {
 	fmt.Println("  gait scout diff <left_snapshot.json> <right_snapshot.json> [--json] [--explain]")
 	fmt.Println("  gait guard pack --run <run_id|path> [--json] [--explain]")
 	fmt.Println("  gait guard verify <evidence_pack.zip> [--profile standard|strict] [--json] [--explain]")
-	fmt.Println("  gait guard retain [--root <dir>] [--trace-ttl <duration>] [--pack-ttl <duration>] [--dry-run] [--json] [--explain]")
+	fmt.Println("  gait guard retain [--root <dir>] [--trace-ttl <duration>] [--pack-ttl <duration>] [--dry-run] [--transparency-log <dir>] [--json] [--explain]")
 	fmt.Println("  gait guard encrypt --in <artifact> [--out <artifact.gaitenc>] [--json] [--explain]")
 	fmt.Println("  gait guard decrypt --in <artifact.gaitenc> [--out <artifact>] [--json] [--explain]")
 	fmt.Println("  gait incident pack --from <run_id|path> [--window <duration>] [--json] [--explain]")
+	fmt.Println("  gait log append|prove|verify|consistency [--log <dir>] [--json] [--explain]")
+	fmt.Println("  gait credential leases [--ledger <path>] [--job <job_id>] [--all] [--sweep] [--json] [--explain]")
 	fmt.Println("  gait registry install --source <path|url> --allow-host <csv> [--json] [--explain]")
 	fmt.Println("  gait registry list [--cache-dir <path>] [--json] [--explain]")
 	fmt.Println("  gait registry verify --path <registry_pack.json> [--cache-dir <path>] [--json] [--explain]")
@@ -705,11 +726,13 @@ func printUsage() {
 	fmt.Println("  gait mcp verify --policy <policy.yaml> --server <server.json> [--risk-class <class>] [--json] [--explain]")
 	fmt.Println("  gait mcp proxy --policy <policy.yaml> --call <tool_call.json|-> [--adapter mcp|openai|anthropic|langchain|claude_code] [--json] [--explain]")
 	fmt.Println("  gait mcp bridge --policy <policy.yaml> --call <tool_call.json|-> [--adapter mcp|openai|anthropic|langchain|claude_code] [--json] [--explain]")
-	fmt.Println("  gait mcp serve --policy <policy.yaml> [--context-envelope <context_envelope.json>] [--listen 127.0.0.1:8787] [--adapter mcp|openai|anthropic|langchain|claude_code] [--profile standard|oss-prod] [--auth-mode off|token] [--auth-token-env <VAR>] [--max-request-bytes <bytes>] [--http-verdict-status compat|strict] [--allow-client-artifact-paths] [--trace-dir <dir>] [--runpack-dir <dir>] [--pack-dir <dir>] [--session-dir <dir>] [--trace-max-age <dur>] [--trace-max-count <n>] [--runpack-max-age <dur>] [--runpack-max-count <n>] [--pack-max-age <dur>] [--pack-max-count <n>] [--session-max-age <dur>] [--session-max-count <n>] [--json] [--explain]")
+	fmt.Println("  gait mcp serve --policy <policy.yaml> [--context-envelope <context_envelope.json>] [--listen 127.0.0.1:8787] [--adapter mcp|openai|anthropic|langchain|claude_code] [--profile standard|oss-prod] [--auth-mode off|token|jwt|mtls] [--auth-token-env <VAR>] [--tls-cert <cert.pem> --tls-key <key.pem>] [--tls-client-ca <ca.pem>] [--jwt-jwks <jwks.json>|--jwt-issuer <url>] [--identity-binding override|strict] [--max-request-bytes <bytes>] [--http-verdict-status compat|strict] [--allow-client-artifact-paths] [--trace-dir <dir>] [--runpack-dir <dir>] [--pack-dir <dir>] [--session-dir <dir>] [--trace-max-age <dur>] [--trace-max-count <n>] [--runpack-max-age <dur>] [--runpack-max-count <n>] [--pack-max-age <dur>] [--pack-max-count <n>] [--session-max-age <dur>] [--session-max-count <n>] [--json] [--explain]")
+	fmt.Println("  gait mcp relay --policy <policy.yaml> [--upstream-url <url>] [--trace-dir <dir>] [--json] [--explain] [-- <upstream command> [args...]]")
+	fmt.Println("  gait mcp annotations capture|verify [--upstream-url <url>] [--out <tool_annotations.json>] [--private-key <path>] [--json] [--explain] [-- <upstream command> [args...]]")
 	fmt.Println("  gait verify <run_id|path> [--json] [--public-key <path>] [--public-key-env <VAR>] [--explain]")
-	fmt.Println("  gait verify chain --run <run_id|path> [--trace <trace.json>] [--pack <evidence_pack.zip>] [--profile standard|strict] [--require-signature] [--public-key <path>|--public-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait verify chain --run <run_id|path> [--trace <trace.json>] [--pack <evidence_pack.zip>] [--profile standard|strict] [--require-signature] [--public-key <path>|--public-key-env <VAR>] [--trust-store <trust_store.json>] [--json] [--explain]")
 	fmt.Println("  gait verify session-chain --chain <session_chain.json> [--profile standard|strict] [--require-signature] [--public-key <path>|--public-key-env <VAR>] [--json] [--explain]")
-	fmt.Println("  gait ui [--listen 127.0.0.1:7980] [--open-browser=true|false] [--allow-non-loopback] [--json] [--explain]")
+	fmt.Println("  gait ui [--listen 127.0.0.1:7980] [--open-browser=true|false] [--approval-queue <dir>] [--allow-non-loopback] [--json] [--explain]")
 	fmt.Println("  gait version [--json] [--explain]")
 }
 
@@ -725,7 +748,7 @@ func printVerifyUsage() {
 
 func printVerifyChainUsage() {
 	fmt.Println("Usage:")
-	fmt.Println("  gait verify chain --run <run_id|path> [--trace <trace.json>] [--pack <evidence_pack.zip>] [--profile standard|strict] [--require-signature] [--public-key <path>] [--public-key-env <VAR>] [--json] [--explain]")
+	fmt.Println("  gait verify chain --run <run_id|path> [--trace <trace.json>] [--pack <evidence_pack.zip>] [--profile standard|strict] [--require-signature] [--public-key <path>] [--public-key-env <VAR>] [--trust-store <trust_store.json>] [--json] [--explain]")
 	fmt.Println("  gait verify chain --run <run_id|path> [--trace <trace.json>] [--pack <evidence_pack.zip>] [--profile standard|strict] [--require-signature] [--private-key <path>] [--private-key-env <VAR>] [--json] [--explain]")
 }
 
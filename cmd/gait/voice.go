@@ -189,6 +189,7 @@ func runVoiceTokenMint(arguments []string) int {
 	if traceErr != nil {
 		return writeVoiceTokenOutput(jsonOutput, voiceTokenOutput{OK: false, Operation: "mint", Error: traceErr.Error()}, exitCodeForError(traceErr, exitInvalidInput))
 	}
+	recordTransparencyArtifact(traceResult.TracePath)
 	if outcome.Result.Verdict != "allow" {
 		exitCode := exitPolicyBlocked
 		if outcome.Result.Verdict == "require_approval" {
@@ -0,0 +1,377 @@
+package authn
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	discoveryPath          = "/.well-known/openid-configuration"
+	defaultRefreshInterval = 5 * time.Minute
+	defaultFailureBackoff  = 30 * time.Second
+	keySetFetchTimeout     = 30 * time.Second
+	maxKeySetBytes         = 1 << 20
+)
+
+var ErrUnknownKey = errors.New("jwt signing key not found")
+
+// KeySource resolves the verification key named by a token header.
+type KeySource interface {
+	Key(ctx context.Context, keyID string) (PublicKey, error)
+}
+
+type PublicKey struct {
+	KeyID     string
+	Algorithm string
+	Key       crypto.PublicKey
+}
+
+// KeySet is a parsed JSON Web Key Set. Keys with a "use" other than "sig"
+// are skipped.
+type KeySet struct {
+	Keys []PublicKey
+}
+
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+}
+
+func ParseKeySet(raw []byte) (*KeySet, error) {
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &document); err != nil {
+		return nil, fmt.Errorf("parse jwks: %w", err)
+	}
+	set := &KeySet{}
+	for index, entry := range document.Keys {
+		if use := strings.TrimSpace(entry.Use); use != "" && use != "sig" {
+			continue
+		}
+		key, err := parseJSONWebKey(entry)
+		if err != nil {
+			return nil, fmt.Errorf("jwks key %d: %w", index, err)
+		}
+		set.Keys = append(set.Keys, PublicKey{
+			KeyID:     strings.TrimSpace(entry.KeyID),
+			Algorithm: strings.TrimSpace(entry.Algorithm),
+			Key:       key,
+		})
+	}
+	if len(set.Keys) == 0 {
+		return nil, fmt.Errorf("jwks has no signing keys")
+	}
+	return set, nil
+}
+
+func LoadKeySetFile(path string) (*KeySet, error) {
+	// #nosec G304 -- jwks path is explicit local operator configuration.
+	raw, err := os.ReadFile(strings.TrimSpace(path))
+	if err != nil {
+		return nil, fmt.Errorf("read jwks: %w", err)
+	}
+	return ParseKeySet(raw)
+}
+
+// Key returns the key with keyID. An empty keyID resolves only when the set
+// holds exactly one key.
+func (set *KeySet) Key(_ context.Context, keyID string) (PublicKey, error) {
+	trimmed := strings.TrimSpace(keyID)
+	if trimmed == "" {
+		if len(set.Keys) == 1 {
+			return set.Keys[0], nil
+		}
+		return PublicKey{}, fmt.Errorf("%w: token has no kid and jwks holds %d keys", ErrUnknownKey, len(set.Keys))
+	}
+	for _, key := range set.Keys {
+		if key.KeyID == trimmed {
+			return key, nil
+		}
+	}
+	return PublicKey{}, fmt.Errorf("%w: kid %s", ErrUnknownKey, trimmed)
+}
+
+// IssuerKeySet fetches signing keys through OpenID Connect discovery on the
+// issuer and refetches them when a token names an unknown kid, at most once
+// per RefreshInterval. A failed fetch is not retried for FailureBackoff.
+// Concurrent lookups share one fetch, which runs without holding the lock so
+// tokens with known kids keep verifying while the issuer is slow.
+type IssuerKeySet struct {
+	Issuer          string
+	Client          *http.Client
+	RefreshInterval time.Duration
+	FailureBackoff  time.Duration
+	Now             func() time.Time
+
+	mu        sync.Mutex
+	keys      *KeySet
+	fetchedAt time.Time
+	failedAt  time.Time
+	fetchErr  error
+	inflight  *keySetFetch
+}
+
+type keySetFetch struct {
+	done chan struct{}
+	keys *KeySet
+	err  error
+}
+
+func NewIssuerKeySet(issuer string, client *http.Client) *IssuerKeySet {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &IssuerKeySet{Issuer: strings.TrimRight(strings.TrimSpace(issuer), "/"), Client: client}
+}
+
+func (set *IssuerKeySet) Key(ctx context.Context, keyID string) (PublicKey, error) {
+	now := time.Now
+	if set.Now != nil {
+		now = set.Now
+	}
+	set.mu.Lock()
+	var unknownErr error
+	if set.keys != nil {
+		key, err := set.keys.Key(ctx, keyID)
+		if err == nil || !errors.Is(err, ErrUnknownKey) {
+			set.mu.Unlock()
+			return key, err
+		}
+		unknownErr = err
+	}
+	fetch := set.inflight
+	if fetch == nil {
+		if err := set.refetchBlocked(now(), unknownErr); err != nil {
+			set.mu.Unlock()
+			return PublicKey{}, err
+		}
+		fetch = &keySetFetch{done: make(chan struct{})}
+		set.inflight = fetch
+		go set.runFetch(context.WithoutCancel(ctx), fetch, now)
+	}
+	set.mu.Unlock()
+
+	select {
+	case <-fetch.done:
+	case <-ctx.Done():
+		return PublicKey{}, ctx.Err()
+	}
+	if fetch.err != nil {
+		if unknownErr != nil {
+			return PublicKey{}, fmt.Errorf("%w (jwks refresh failed: %v)", unknownErr, fetch.err)
+		}
+		return PublicKey{}, fetch.err
+	}
+	return fetch.keys.Key(ctx, keyID)
+}
+
+// refetchBlocked reports why a new fetch may not start yet. The caller holds
+// set.mu.
+func (set *IssuerKeySet) refetchBlocked(now time.Time, unknownErr error) error {
+	if set.fetchErr != nil {
+		backoff := set.FailureBackoff
+		if backoff <= 0 {
+			backoff = defaultFailureBackoff
+		}
+		if now.Sub(set.failedAt) < backoff {
+			if unknownErr != nil {
+				return unknownErr
+			}
+			return fmt.Errorf("%w (retrying after %s)", set.fetchErr, set.failedAt.Add(backoff).Format(time.RFC3339))
+		}
+	}
+	if unknownErr != nil {
+		interval := set.RefreshInterval
+		if interval <= 0 {
+			interval = defaultRefreshInterval
+		}
+		if now.Sub(set.fetchedAt) < interval {
+			return unknownErr
+		}
+	}
+	return nil
+}
+
+func (set *IssuerKeySet) runFetch(ctx context.Context, fetch *keySetFetch, now func() time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, keySetFetchTimeout)
+	defer cancel()
+	fetch.keys, fetch.err = set.fetch(ctx)
+
+	set.mu.Lock()
+	if fetch.err != nil {
+		set.fetchErr = fetch.err
+		set.failedAt = now()
+	} else {
+		set.keys = fetch.keys
+		set.fetchedAt = now()
+		set.fetchErr = nil
+	}
+	set.inflight = nil
+	set.mu.Unlock()
+	close(fetch.done)
+}
+
+func (set *IssuerKeySet) fetch(ctx context.Context) (*KeySet, error) {
+	if set.Issuer == "" {
+		return nil, fmt.Errorf("jwt issuer is required")
+	}
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	raw, err := set.get(ctx, set.Issuer+discoveryPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &discovery); err != nil {
+		return nil, fmt.Errorf("parse openid configuration: %w", err)
+	}
+	if strings.TrimRight(strings.TrimSpace(discovery.Issuer), "/") != set.Issuer {
+		return nil, fmt.Errorf("openid configuration issuer %q does not match %q", discovery.Issuer, set.Issuer)
+	}
+	if strings.TrimSpace(discovery.JWKSURI) == "" {
+		return nil, fmt.Errorf("openid configuration has no jwks_uri")
+	}
+	raw, err = set.get(ctx, strings.TrimSpace(discovery.JWKSURI))
+	if err != nil {
+		return nil, err
+	}
+	return ParseKeySet(raw)
+}
+
+func (set *IssuerKeySet) get(ctx context.Context, endpoint string) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build jwks request: %w", err)
+	}
+	request.Header.Set("Accept", "application/json")
+	// #nosec G107 -- endpoint comes from operator-configured issuer discovery.
+	response, err := set.Client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s: %w", endpoint, err)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: status %d", endpoint, response.StatusCode)
+	}
+	raw, err := io.ReadAll(io.LimitReader(response.Body, maxKeySetBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", endpoint, err)
+	}
+	return raw, nil
+}
+
+func parseJSONWebKey(entry jsonWebKey) (crypto.PublicKey, error) {
+	switch strings.TrimSpace(entry.KeyType) {
+	case "RSA":
+		modulus, err := decodeBigInt(entry.N)
+		if err != nil {
+			return nil, fmt.Errorf("decode n: %w", err)
+		}
+		exponent, err := decodeBigInt(entry.E)
+		if err != nil {
+			return nil, fmt.Errorf("decode e: %w", err)
+		}
+		if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("unsupported rsa exponent")
+		}
+		if modulus.BitLen() < 2048 {
+			return nil, fmt.Errorf("rsa key must be at least 2048 bits")
+		}
+		return &rsa.PublicKey{N: modulus, E: int(exponent.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch strings.TrimSpace(entry.Curve) {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported ec curve %q", entry.Curve)
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		x, err := decodeCoordinate(entry.X, size)
+		if err != nil {
+			return nil, fmt.Errorf("decode x: %w", err)
+		}
+		y, err := decodeCoordinate(entry.Y, size)
+		if err != nil {
+			return nil, fmt.Errorf("decode y: %w", err)
+		}
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		x.FillBytes(point[1 : 1+size])
+		y.FillBytes(point[1+size:])
+		key, err := ecdsa.ParseUncompressedPublicKey(curve, point)
+		if err != nil {
+			return nil, fmt.Errorf("ec point: %w", err)
+		}
+		return key, nil
+	case "OKP":
+		if strings.TrimSpace(entry.Curve) != "Ed25519" {
+			return nil, fmt.Errorf("unsupported okp curve %q", entry.Curve)
+		}
+		decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(entry.X))
+		if err != nil {
+			return nil, fmt.Errorf("decode x: %w", err)
+		}
+		if len(decoded) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("ed25519 key must be %d bytes", ed25519.PublicKeySize)
+		}
+		return ed25519.PublicKey(decoded), nil
+	default:
+		return nil, fmt.Errorf("unsupported kty %q", entry.KeyType)
+	}
+}
+
+// decodeCoordinate rejects coordinates longer than the curve size, which
+// would otherwise make FillBytes panic.
+func decodeCoordinate(value string, size int) (*big.Int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) == 0 {
+		return nil, fmt.Errorf("empty value")
+	}
+	if len(decoded) > size {
+		return nil, fmt.Errorf("coordinate is %d bytes, curve allows %d", len(decoded), size)
+	}
+	return new(big.Int).SetBytes(decoded), nil
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) == 0 {
+		return nil, fmt.Errorf("empty value")
+	}
+	return new(big.Int).SetBytes(decoded), nil
+}
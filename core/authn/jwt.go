@@ -0,0 +1,250 @@
+package authn
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultIdentityClaim = "sub"
+	defaultLeeway        = time.Minute
+)
+
+var ErrInvalidToken = errors.New("invalid bearer token")
+
+// ClaimMapping names the token claims copied onto the principal. Identity
+// defaults to the subject; workspace and agent are mapped only when named.
+type ClaimMapping struct {
+	Identity  string
+	Workspace string
+	Agent     string
+}
+
+// JWTVerifier validates compact JWS bearer tokens. Tokens must carry exp;
+// iss and aud are checked when Issuer and Audience are set.
+type JWTVerifier struct {
+	Keys     KeySource
+	Issuer   string
+	Audience string
+	Claims   ClaimMapping
+	Leeway   time.Duration
+	Now      func() time.Time
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Critical  []any  `json:"crit"`
+}
+
+func (verifier *JWTVerifier) Verify(ctx context.Context, token string) (Principal, error) {
+	if verifier == nil || verifier.Keys == nil {
+		return Principal{}, fmt.Errorf("jwt verifier is not configured")
+	}
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return Principal{}, fmt.Errorf("%w: malformed jwt", ErrInvalidToken)
+	}
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Principal{}, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	if len(header.Critical) > 0 {
+		return Principal{}, fmt.Errorf("%w: unsupported crit header", ErrInvalidToken)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: signature encoding", ErrInvalidToken)
+	}
+	key, err := verifier.Keys.Key(ctx, header.KeyID)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if key.Algorithm != "" && key.Algorithm != header.Algorithm {
+		return Principal{}, fmt.Errorf("%w: alg %s does not match key alg %s", ErrInvalidToken, header.Algorithm, key.Algorithm)
+	}
+	if err := verifySignature(header.Algorithm, key.Key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Principal{}, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	if err := verifier.checkClaims(claims); err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	identityClaim := strings.TrimSpace(verifier.Claims.Identity)
+	if identityClaim == "" {
+		identityClaim = DefaultIdentityClaim
+	}
+	principal := Principal{
+		Method:    MethodJWT,
+		Subject:   stringClaim(claims, "sub"),
+		Issuer:    stringClaim(claims, "iss"),
+		Identity:  stringClaim(claims, identityClaim),
+		Workspace: stringClaim(claims, verifier.Claims.Workspace),
+		AgentID:   stringClaim(claims, verifier.Claims.Agent),
+	}
+	if principal.Identity == "" {
+		return Principal{}, fmt.Errorf("%w: identity claim %s is missing", ErrInvalidToken, identityClaim)
+	}
+	return principal, nil
+}
+
+func (verifier *JWTVerifier) checkClaims(claims map[string]any) error {
+	now := time.Now().UTC()
+	if verifier.Now != nil {
+		now = verifier.Now().UTC()
+	}
+	leeway := verifier.Leeway
+	if leeway <= 0 {
+		leeway = defaultLeeway
+	}
+	expiresAt, ok := numericDate(claims["exp"])
+	if !ok {
+		return fmt.Errorf("exp claim is required")
+	}
+	if !now.Before(expiresAt.Add(leeway)) {
+		return fmt.Errorf("token expired at %s", expiresAt.Format(time.RFC3339))
+	}
+	if notBefore, ok := numericDate(claims["nbf"]); ok && now.Add(leeway).Before(notBefore) {
+		return fmt.Errorf("token not valid before %s", notBefore.Format(time.RFC3339))
+	}
+	if issuer := strings.TrimRight(strings.TrimSpace(verifier.Issuer), "/"); issuer != "" {
+		if strings.TrimRight(stringClaim(claims, "iss"), "/") != issuer {
+			return fmt.Errorf("iss does not match %s", issuer)
+		}
+	}
+	if audience := strings.TrimSpace(verifier.Audience); audience != "" && !audienceContains(claims["aud"], audience) {
+		return fmt.Errorf("aud does not include %s", audience)
+	}
+	return nil
+}
+
+func verifySignature(algorithm string, key crypto.PublicKey, signed []byte, signature []byte) error {
+	switch algorithm {
+	case "RS256", "RS384", "RS512", "PS256", "PS384", "PS512":
+		publicKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("alg %s requires an RSA key", algorithm)
+		}
+		hash := hashForAlgorithm(algorithm)
+		digest := digestWith(hash, signed)
+		if strings.HasPrefix(algorithm, "PS") {
+			return rsa.VerifyPSS(publicKey, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		return rsa.VerifyPKCS1v15(publicKey, hash, digest, signature)
+	case "ES256", "ES384":
+		publicKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("alg %s requires an EC key", algorithm)
+		}
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		if (algorithm == "ES256" && size != 32) || (algorithm == "ES384" && size != 48) {
+			return fmt.Errorf("alg %s does not match key curve", algorithm)
+		}
+		if len(signature) != 2*size {
+			return fmt.Errorf("signature has invalid length")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(publicKey, digestWith(hashForAlgorithm(algorithm), signed), r, s) {
+			return fmt.Errorf("signature verification failed")
+		}
+		return nil
+	case "EdDSA":
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("alg EdDSA requires an Ed25519 key")
+		}
+		if !ed25519.Verify(publicKey, signed, signature) {
+			return fmt.Errorf("signature verification failed")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported alg %q", algorithm)
+	}
+}
+
+func hashForAlgorithm(algorithm string) crypto.Hash {
+	switch algorithm[len(algorithm)-3:] {
+	case "384":
+		return crypto.SHA384
+	case "512":
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
+
+func digestWith(hash crypto.Hash, payload []byte) []byte {
+	switch hash {
+	case crypto.SHA384:
+		sum := sha512.Sum384(payload)
+		return sum[:]
+	case crypto.SHA512:
+		sum := sha512.Sum512(payload)
+		return sum[:]
+	default:
+		sum := sha256.Sum256(payload)
+		return sum[:]
+	}
+}
+
+func decodeSegment(segment string, target any) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(decoded)))
+	decoder.UseNumber()
+	return decoder.Decode(target)
+}
+
+func numericDate(value any) (time.Time, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0).UTC(), true
+}
+
+func stringClaim(claims map[string]any, name string) string {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return ""
+	}
+	value, _ := claims[trimmed].(string)
+	return strings.TrimSpace(value)
+}
+
+func audienceContains(value any, audience string) bool {
+	switch typed := value.(type) {
+	case string:
+		return strings.TrimSpace(typed) == audience
+	case []any:
+		for _, entry := range typed {
+			if text, ok := entry.(string); ok && strings.TrimSpace(text) == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
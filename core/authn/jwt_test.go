@@ -0,0 +1,306 @@
+package authn
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJWTVerifierMapsClaimsAcrossAlgorithms(t *testing.T) {
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ed25519 key: %v", err)
+	}
+	ecPrivate, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ec key: %v", err)
+	}
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	ecPoint, err := ecPrivate.PublicKey.Bytes()
+	if err != nil {
+		t.Fatalf("encode ec point: %v", err)
+	}
+	jwks := map[string]any{"keys": []map[string]any{
+		{"kty": "OKP", "crv": "Ed25519", "kid": "ed", "x": b64(edPublic)},
+		{"kty": "EC", "crv": "P-256", "kid": "ec", "alg": "ES256", "x": b64(ecPoint[1:33]), "y": b64(ecPoint[33:])},
+		{"kty": "RSA", "kid": "rsa", "n": b64(rsaPrivate.N.Bytes()), "e": b64(big.NewInt(int64(rsaPrivate.E)).Bytes())},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": b64(rsaPrivate.N.Bytes()), "e": "AQAB"},
+	}}
+	rawJWKS, _ := json.Marshal(jwks)
+	keySet, err := ParseKeySet(rawJWKS)
+	if err != nil {
+		t.Fatalf("parse jwks: %v", err)
+	}
+	if len(keySet.Keys) != 3 {
+		t.Fatalf("expected encryption key to be skipped, got %d keys", len(keySet.Keys))
+	}
+	verifier := &JWTVerifier{
+		Keys:     keySet,
+		Issuer:   "https://idp.example.com/",
+		Audience: "gait",
+		Claims:   ClaimMapping{Identity: "email", Workspace: "workspace", Agent: "agent_id"},
+		Now:      func() time.Time { return now },
+	}
+	claims := map[string]any{
+		"iss":       "https://idp.example.com",
+		"sub":       "user-123",
+		"aud":       []string{"other", "gait"},
+		"exp":       now.Add(time.Hour).Unix(),
+		"email":     "alice@example.com",
+		"workspace": "/repo/payments",
+		"agent_id":  "billing-agent",
+	}
+
+	signers := map[string]func([]byte) []byte{
+		"EdDSA": func(payload []byte) []byte { return ed25519.Sign(edPrivate, payload) },
+		"ES256": func(payload []byte) []byte {
+			digest := sha256.Sum256(payload)
+			r, s, signErr := ecdsa.Sign(rand.Reader, ecPrivate, digest[:])
+			if signErr != nil {
+				t.Fatalf("sign es256: %v", signErr)
+			}
+			signature := make([]byte, 64)
+			r.FillBytes(signature[:32])
+			s.FillBytes(signature[32:])
+			return signature
+		},
+		"RS256": func(payload []byte) []byte {
+			digest := sha256.Sum256(payload)
+			signature, signErr := rsa.SignPKCS1v15(rand.Reader, rsaPrivate, crypto.SHA256, digest[:])
+			if signErr != nil {
+				t.Fatalf("sign rs256: %v", signErr)
+			}
+			return signature
+		},
+	}
+	kids := map[string]string{"EdDSA": "ed", "ES256": "ec", "RS256": "rsa"}
+	for algorithm, signer := range signers {
+		principal, err := verifier.Verify(context.Background(), signToken(t, algorithm, kids[algorithm], claims, signer))
+		if err != nil {
+			t.Fatalf("%s verify: %v", algorithm, err)
+		}
+		if principal.Method != MethodJWT || principal.Identity != "alice@example.com" || principal.Workspace != "/repo/payments" || principal.AgentID != "billing-agent" || principal.Subject != "user-123" {
+			t.Fatalf("%s unexpected principal: %#v", algorithm, principal)
+		}
+	}
+
+	edSigner := signers["EdDSA"]
+	failures := map[string]string{
+		"expired":        signToken(t, "EdDSA", "ed", withClaim(claims, "exp", now.Add(-2*time.Minute).Unix()), edSigner),
+		"missing exp":    signToken(t, "EdDSA", "ed", withClaim(claims, "exp", nil), edSigner),
+		"not yet valid":  signToken(t, "EdDSA", "ed", withClaim(claims, "nbf", now.Add(time.Hour).Unix()), edSigner),
+		"wrong audience": signToken(t, "EdDSA", "ed", withClaim(claims, "aud", "other"), edSigner),
+		"wrong issuer":   signToken(t, "EdDSA", "ed", withClaim(claims, "iss", "https://evil.example.com"), edSigner),
+		"no identity":    signToken(t, "EdDSA", "ed", withClaim(claims, "email", nil), edSigner),
+		"unknown kid":    signToken(t, "EdDSA", "missing", claims, edSigner),
+		"alg mismatch":   signToken(t, "EdDSA", "ec", claims, edSigner),
+		"none":           signToken(t, "none", "ed", claims, func([]byte) []byte { return nil }),
+	}
+	tampered := strings.Split(signToken(t, "EdDSA", "ed", claims, edSigner), ".")
+	tampered[1] = encodeSegment(t, withClaim(claims, "email", "mallory@example.com"))
+	failures["tampered"] = strings.Join(tampered, ".")
+	for name, token := range failures {
+		if _, err := verifier.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+}
+
+func TestParseKeySetRejectsOversizedECCoordinates(t *testing.T) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	x := private.X.FillBytes(make([]byte, 32))
+	y := private.Y.FillBytes(make([]byte, 32))
+	for name, key := range map[string]map[string]any{
+		"x": {"kty": "EC", "crv": "P-256", "x": b64(append([]byte{1}, x...)), "y": b64(y)},
+		"y": {"kty": "EC", "crv": "P-256", "x": b64(x), "y": b64(append([]byte{1}, y...))},
+	} {
+		raw, _ := json.Marshal(map[string]any{"keys": []map[string]any{key}})
+		if _, err := ParseKeySet(raw); err == nil || !strings.Contains(err.Error(), "curve allows 32") {
+			t.Fatalf("expected oversized %s coordinate to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestIssuerKeySetDiscoversAndRefreshesKeys(t *testing.T) {
+	firstPublic, firstPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	secondPublic, secondPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	keys := []map[string]any{{"kty": "OKP", "crv": "Ed25519", "kid": "one", "x": b64(firstPublic)}}
+	fetches := 0
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc(discoveryPath, func(writer http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(writer).Encode(map[string]string{"issuer": server.URL, "jwks_uri": server.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(writer http.ResponseWriter, _ *http.Request) {
+		fetches++
+		_ = json.NewEncoder(writer).Encode(map[string]any{"keys": keys})
+	})
+
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	keySet := NewIssuerKeySet(server.URL, server.Client())
+	keySet.Now = func() time.Time { return now }
+	verifier := &JWTVerifier{Keys: keySet, Issuer: server.URL, Now: keySet.Now}
+	claims := map[string]any{"iss": server.URL, "sub": "svc-a", "exp": now.Add(time.Hour).Unix()}
+	if _, err := verifier.Verify(context.Background(), signToken(t, "EdDSA", "one", claims, func(payload []byte) []byte { return ed25519.Sign(firstPrivate, payload) })); err != nil {
+		t.Fatalf("verify with discovered key: %v", err)
+	}
+
+	keys = append(keys, map[string]any{"kty": "OKP", "crv": "Ed25519", "kid": "two", "x": b64(secondPublic)})
+	rotated := signToken(t, "EdDSA", "two", claims, func(payload []byte) []byte { return ed25519.Sign(secondPrivate, payload) })
+	if _, err := verifier.Verify(context.Background(), rotated); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected unknown kid inside refresh interval, got %v", err)
+	}
+	now = now.Add(defaultRefreshInterval)
+	if principal, err := verifier.Verify(context.Background(), rotated); err != nil || principal.Identity != "svc-a" {
+		t.Fatalf("expected rotated key after refresh, principal=%#v err=%v", principal, err)
+	}
+	if fetches != 2 {
+		t.Fatalf("expected 2 jwks fetches, got %d", fetches)
+	}
+}
+
+func TestIssuerKeySetSharesFetchesAndBacksOffAfterFailures(t *testing.T) {
+	firstPublic, _, _ := ed25519.GenerateKey(rand.Reader)
+	secondPublic, _, _ := ed25519.GenerateKey(rand.Reader)
+	keys := []map[string]any{{"kty": "OKP", "crv": "Ed25519", "kid": "one", "x": b64(firstPublic)}}
+	var fetches atomic.Int32
+	failing := atomic.Bool{}
+	failing.Store(true)
+	release := make(chan struct{})
+	blocking := atomic.Bool{}
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc(discoveryPath, func(writer http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(writer).Encode(map[string]string{"issuer": server.URL, "jwks_uri": server.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(writer http.ResponseWriter, _ *http.Request) {
+		fetches.Add(1)
+		if failing.Load() {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if blocking.Load() {
+			<-release
+		}
+		_ = json.NewEncoder(writer).Encode(map[string]any{"keys": keys})
+	})
+
+	now := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	keySet := NewIssuerKeySet(server.URL, server.Client())
+	keySet.Now = func() time.Time { return now }
+	ctx := context.Background()
+	if _, err := keySet.Key(ctx, "one"); err == nil {
+		t.Fatalf("expected fetch failure")
+	}
+	if _, err := keySet.Key(ctx, "one"); err == nil || fetches.Load() != 1 {
+		t.Fatalf("expected failure backoff without refetch, fetches=%d err=%v", fetches.Load(), err)
+	}
+	failing.Store(false)
+	now = now.Add(defaultFailureBackoff)
+	if _, err := keySet.Key(ctx, "one"); err != nil || fetches.Load() != 2 {
+		t.Fatalf("expected refetch after backoff, fetches=%d err=%v", fetches.Load(), err)
+	}
+
+	keys = append(keys, map[string]any{"kty": "OKP", "crv": "Ed25519", "kid": "two", "x": b64(secondPublic)})
+	now = now.Add(defaultRefreshInterval)
+	blocking.Store(true)
+	results := make(chan error, 4)
+	for range 4 {
+		go func() {
+			_, err := keySet.Key(ctx, "two")
+			results <- err
+		}()
+	}
+	for fetches.Load() != 3 {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := keySet.Key(ctx, "one"); err != nil {
+		t.Fatalf("expected known kid to resolve while a refresh is in flight: %v", err)
+	}
+	close(release)
+	for range 4 {
+		if err := <-results; err != nil {
+			t.Fatalf("expected rotated key from shared fetch: %v", err)
+		}
+	}
+	if fetches.Load() != 3 {
+		t.Fatalf("expected concurrent lookups to share one fetch, got %d fetches", fetches.Load())
+	}
+}
+
+func TestPrincipalFromCertificate(t *testing.T) {
+	spiffeID, _ := url.Parse("spiffe://example.org/agent/deployer")
+	principal, err := PrincipalFromCertificate(&x509.Certificate{
+		Subject: pkix.Name{CommonName: "deployer", OrganizationalUnit: []string{"payments"}},
+		URIs:    []*url.URL{spiffeID},
+	})
+	if err != nil || principal.Identity != spiffeID.String() || principal.Workspace != "payments" || principal.Method != MethodMTLS {
+		t.Fatalf("unexpected principal=%#v err=%v", principal, err)
+	}
+	principal, err = PrincipalFromCertificate(&x509.Certificate{Subject: pkix.Name{CommonName: "deployer"}})
+	if err != nil || principal.Identity != "deployer" {
+		t.Fatalf("expected common name fallback, principal=%#v err=%v", principal, err)
+	}
+	if _, err := PrincipalFromCertificate(&x509.Certificate{}); err == nil {
+		t.Fatalf("expected error for certificate without identity")
+	}
+}
+
+func signToken(t *testing.T, algorithm string, keyID string, claims map[string]any, signer func([]byte) []byte) string {
+	t.Helper()
+	signed := encodeSegment(t, map[string]any{"alg": algorithm, "kid": keyID, "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	return signed + "." + b64(signer([]byte(signed)))
+}
+
+func encodeSegment(t *testing.T, value any) string {
+	t.Helper()
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("marshal segment: %v", err)
+	}
+	return b64(encoded)
+}
+
+func withClaim(claims map[string]any, name string, value any) map[string]any {
+	copied := make(map[string]any, len(claims))
+	for key, existing := range claims {
+		copied[key] = existing
+	}
+	if value == nil {
+		delete(copied, name)
+	} else {
+		copied[name] = value
+	}
+	return copied
+}
+
+func b64(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
@@ -0,0 +1,55 @@
+// Package authn authenticates callers of gait services and maps them onto
+// the identity fields Gate evaluates.
+package authn
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strings"
+)
+
+const (
+	MethodJWT  = "jwt"
+	MethodMTLS = "mtls"
+)
+
+// Principal is an authenticated caller. Identity, Workspace and AgentID are
+// the values bound onto the intent context.
+type Principal struct {
+	Method    string `json:"method"`
+	Subject   string `json:"subject,omitempty"`
+	Issuer    string `json:"issuer,omitempty"`
+	Identity  string `json:"identity"`
+	Workspace string `json:"workspace,omitempty"`
+	AgentID   string `json:"agent_id,omitempty"`
+}
+
+// PrincipalFromCertificate maps a verified client certificate. The identity
+// is the first URI SAN (for example a SPIFFE ID) or else the subject common
+// name; the workspace is the first subject organizational unit.
+func PrincipalFromCertificate(certificate *x509.Certificate) (Principal, error) {
+	if certificate == nil {
+		return Principal{}, fmt.Errorf("client certificate is required")
+	}
+	identity := ""
+	if len(certificate.URIs) > 0 {
+		identity = strings.TrimSpace(certificate.URIs[0].String())
+	}
+	if identity == "" {
+		identity = strings.TrimSpace(certificate.Subject.CommonName)
+	}
+	if identity == "" {
+		return Principal{}, fmt.Errorf("client certificate has no uri san or common name")
+	}
+	workspace := ""
+	if len(certificate.Subject.OrganizationalUnit) > 0 {
+		workspace = strings.TrimSpace(certificate.Subject.OrganizationalUnit[0])
+	}
+	return Principal{
+		Method:    MethodMTLS,
+		Subject:   certificate.Subject.String(),
+		Issuer:    certificate.Issuer.String(),
+		Identity:  identity,
+		Workspace: workspace,
+	}, nil
+}
@@ -0,0 +1,247 @@
+package credential
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	awsAccessKeyIDEnv       = "AWS_ACCESS_KEY_ID"
+	awsSecretAccessKeyEnv   = "AWS_SECRET_ACCESS_KEY"
+	awsSessionTokenEnv      = "AWS_SESSION_TOKEN"
+	awsRegionEnv            = "AWS_REGION"
+	awsDefaultRegionEnv     = "AWS_DEFAULT_REGION"
+	awsSTSRoleARNEnv        = "GAIT_AWS_STS_ROLE_ARN"
+	awsSTSEndpointEnv       = "GAIT_AWS_STS_ENDPOINT"
+	awsSTSDurationEnv       = "GAIT_AWS_STS_DURATION_SECONDS"
+	awsSTSMinDuration       = 900
+	awsSTSMaxDuration       = 43200
+	awsSTSAPIVersion        = "2011-06-15"
+	awsSTSService           = "sts"
+	awsSigV4Algorithm       = "AWS4-HMAC-SHA256"
+	awsSTSRoleSessionMaxLen = 64
+)
+
+var awsRoleSessionSanitizer = regexp.MustCompile(`[^A-Za-z0-9_=,.@-]+`)
+
+// AWSSTSBroker calls STS AssumeRole with SigV4-signed requests. The request
+// reference overrides RoleARN when it is a role ARN. Only the assumed role
+// ARN is returned; the temporary keys never leave the broker.
+type AWSSTSBroker struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Region          string
+	RoleARN         string
+	Endpoint        string
+	DurationSeconds int64
+	Client          *http.Client
+	Now             func() time.Time
+}
+
+type awsAssumeRoleResponse struct {
+	Result struct {
+		Credentials struct {
+			Expiration string `xml:"Expiration"`
+		} `xml:"Credentials"`
+		AssumedRoleUser struct {
+			AssumedRoleID string `xml:"AssumedRoleId"`
+			Arn           string `xml:"Arn"`
+		} `xml:"AssumedRoleUser"`
+	} `xml:"AssumeRoleResult"`
+}
+
+func (b AWSSTSBroker) Name() string {
+	return "aws_sts"
+}
+
+func (b AWSSTSBroker) Issue(request Request) (Response, error) {
+	roleARN := strings.TrimSpace(b.RoleARN)
+	if reference := strings.TrimSpace(request.Reference); strings.HasPrefix(reference, "arn:") {
+		roleARN = reference
+	}
+	if !strings.HasPrefix(roleARN, "arn:") || !strings.Contains(roleARN, ":role/") {
+		return Response{}, fmt.Errorf("aws_sts broker requires a role arn reference or %s", awsSTSRoleARNEnv)
+	}
+	if strings.TrimSpace(b.AccessKeyID) == "" || strings.TrimSpace(b.SecretAccessKey) == "" {
+		return Response{}, fmt.Errorf("aws_sts broker requires %s and %s", awsAccessKeyIDEnv, awsSecretAccessKeyEnv)
+	}
+	region := strings.TrimSpace(b.Region)
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint, err := normalizeBaseURL(b.Endpoint, "https://sts."+region+".amazonaws.com")
+	if err != nil {
+		return Response{}, fmt.Errorf("aws_sts broker endpoint: %w", err)
+	}
+	duration := b.DurationSeconds
+	if duration == 0 {
+		duration = awsSTSMinDuration
+	}
+	if duration < awsSTSMinDuration || duration > awsSTSMaxDuration {
+		return Response{}, fmt.Errorf("aws_sts duration must be between %d and %d seconds", awsSTSMinDuration, awsSTSMaxDuration)
+	}
+
+	issuedAt := brokerNow(b.Now)
+	form := url.Values{}
+	form.Set("Action", "AssumeRole")
+	form.Set("Version", awsSTSAPIVersion)
+	form.Set("RoleArn", roleARN)
+	form.Set("RoleSessionName", awsRoleSessionName(request))
+	form.Set("DurationSeconds", strconv.FormatInt(duration, 10))
+	if identity := awsRoleSessionSanitizer.ReplaceAllString(strings.TrimSpace(request.Identity), "_"); identity != "" {
+		form.Set("SourceIdentity", truncateString(identity, awsSTSRoleSessionMaxLen))
+	}
+	body := form.Encode()
+	httpRequest, err := http.NewRequest(http.MethodPost, endpoint+"/", strings.NewReader(body))
+	if err != nil {
+		return Response{}, fmt.Errorf("build aws_sts request: %w", err)
+	}
+	httpRequest.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signAWSRequestV4(httpRequest, []byte(body), awsSigningCredentials{
+		AccessKeyID:     strings.TrimSpace(b.AccessKeyID),
+		SecretAccessKey: strings.TrimSpace(b.SecretAccessKey),
+		SessionToken:    strings.TrimSpace(b.SessionToken),
+	}, region, awsSTSService, issuedAt)
+	payload, err := doBrokerRequest(b.Client, httpRequest, "aws_sts")
+	if err != nil {
+		return Response{}, err
+	}
+	var assumed awsAssumeRoleResponse
+	if err := xml.Unmarshal(payload, &assumed); err != nil {
+		return Response{}, fmt.Errorf("%w: parse aws_sts response", ErrCredentialUnavailable)
+	}
+	assumedARN := strings.TrimSpace(assumed.Result.AssumedRoleUser.Arn)
+	if assumedARN == "" {
+		return Response{}, fmt.Errorf("%w: aws_sts response missing assumed role arn", ErrCredentialUnavailable)
+	}
+	expiresAt := issuedAt.Add(time.Duration(duration) * time.Second)
+	if raw := strings.TrimSpace(assumed.Result.Credentials.Expiration); raw != "" {
+		parsed, parseErr := time.Parse(time.RFC3339, raw)
+		if parseErr != nil {
+			return Response{}, fmt.Errorf("%w: invalid aws_sts expiration", ErrCredentialUnavailable)
+		}
+		expiresAt = parsed.UTC()
+	}
+	if !expiresAt.After(issuedAt) {
+		return Response{}, fmt.Errorf("%w: aws_sts credentials already expired", ErrCredentialUnavailable)
+	}
+	return Response{
+		IssuedBy:      "aws_sts",
+		Source:        "aws_sts",
+		AccessType:    "jit",
+		Issuer:        "sts.amazonaws.com",
+		Subject:       assumedARN,
+		Owner:         request.Identity,
+		Scope:         normalizeScope(request.Scope),
+		CredentialRef: "aws_sts:" + assumedARN,
+		TargetBinding: request.TargetBinding,
+		RunBinding:    request.RunID,
+		JobBinding:    request.JobID,
+		IssuedAt:      issuedAt,
+		ExpiresAt:     expiresAt,
+		TTLSeconds:    int64(expiresAt.Sub(issuedAt).Seconds()),
+	}, nil
+}
+
+// Revoke always reports ErrRevocationUnsupported: STS sessions cannot be
+// ended individually and stay valid until they expire.
+func (b AWSSTSBroker) Revoke(response Response) error {
+	return fmt.Errorf("%w: aws_sts sessions expire at %s", ErrRevocationUnsupported, response.ExpiresAt.UTC().Format(time.RFC3339))
+}
+
+func awsRoleSessionName(request Request) string {
+	parts := []string{"gait"}
+	for _, value := range []string{request.JobID, request.RunID, request.RequestID} {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			parts = append(parts, trimmed)
+			break
+		}
+	}
+	digest, err := RequestDigest(request)
+	if err == nil && len(digest) >= 12 {
+		parts = append(parts, digest[:12])
+	}
+	name := awsRoleSessionSanitizer.ReplaceAllString(strings.Join(parts, "-"), "_")
+	return truncateString(name, awsSTSRoleSessionMaxLen)
+}
+
+func truncateString(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+	return value[:maxLength]
+}
+
+type awsSigningCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// signAWSRequestV4 adds SigV4 headers for a request whose body is payload.
+func signAWSRequestV4(request *http.Request, payload []byte, credentials awsSigningCredentials, region string, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	dateStamp := now.UTC().Format("20060102")
+	payloadHash := sha256Hex(payload)
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if credentials.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	headerNames := []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+	if credentials.SessionToken != "" {
+		headerNames = append(headerNames, "x-amz-security-token")
+	}
+	canonicalHeaders := strings.Builder{}
+	for _, name := range headerNames {
+		value := request.Header.Get(name)
+		if name == "host" {
+			value = request.URL.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+	canonicalPath := request.URL.EscapedPath()
+	if canonicalPath == "" {
+		canonicalPath = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		canonicalPath,
+		request.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := strings.Join([]string{dateStamp, region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{awsSigV4Algorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), dateStamp)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, service)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	request.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", awsSigV4Algorithm, credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, value string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
@@ -43,9 +43,12 @@ type Response struct {
 	TTLSeconds    int64     `json:"ttl_seconds,omitempty"`
 }
 
+// Broker issues credential refs. Revoke ends a credential before its TTL and
+// returns ErrRevocationUnsupported when the provider cannot do that.
 type Broker interface {
 	Name() string
 	Issue(Request) (Response, error)
+	Revoke(Response) error
 }
 
 func Issue(broker Broker, request Request) (Response, error) {
@@ -0,0 +1,273 @@
+package credential
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	githubAppIDEnv             = "GAIT_GITHUB_APP_ID"
+	githubAppInstallationIDEnv = "GAIT_GITHUB_APP_INSTALLATION_ID"
+	githubAppPrivateKeyPathEnv = "GAIT_GITHUB_APP_PRIVATE_KEY_PATH"
+	githubAPIURLEnv            = "GAIT_GITHUB_API_URL"
+	defaultGitHubAPIURL        = "https://api.github.com"
+	githubAppJWTLifetime       = 9 * time.Minute
+)
+
+// GitHubAppBroker mints installation access tokens for a GitHub App. Scope
+// entries of the form permission:level (for example contents:read) narrow the
+// token permissions and the request reference, when set, is a comma-separated
+// repository list. The token itself is held in memory only so Revoke can end
+// it; the response carries a digest-derived reference.
+type GitHubAppBroker struct {
+	AppID          string
+	InstallationID string
+	PrivateKey     *rsa.PrivateKey
+	BaseURL        string
+	Client         *http.Client
+	Now            func() time.Time
+
+	tokens *githubTokenStore
+}
+
+type githubTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+type githubInstallationTokenResponse struct {
+	Token        string            `json:"token"`
+	ExpiresAt    string            `json:"expires_at"`
+	Permissions  map[string]string `json:"permissions"`
+	Repositories []struct {
+		FullName string `json:"full_name"`
+	} `json:"repositories"`
+}
+
+func NewGitHubAppBroker(appID string, installationID string, privateKey *rsa.PrivateKey, baseURL string) GitHubAppBroker {
+	return GitHubAppBroker{
+		AppID:          strings.TrimSpace(appID),
+		InstallationID: strings.TrimSpace(installationID),
+		PrivateKey:     privateKey,
+		BaseURL:        strings.TrimSpace(baseURL),
+		tokens:         &githubTokenStore{tokens: map[string]string{}},
+	}
+}
+
+// LoadGitHubAppPrivateKey reads a PKCS#1 or PKCS#8 RSA key in PEM form.
+func LoadGitHubAppPrivateKey(path string) (*rsa.PrivateKey, error) {
+	// #nosec G304 -- GitHub App key path is explicit operator configuration.
+	raw, err := os.ReadFile(strings.TrimSpace(path))
+	if err != nil {
+		return nil, fmt.Errorf("read github app private key: %w", err)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("github app private key must be PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse github app private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("github app private key must be RSA")
+	}
+	return key, nil
+}
+
+func (b GitHubAppBroker) Name() string {
+	return "github_app"
+}
+
+func (b GitHubAppBroker) Issue(request Request) (Response, error) {
+	if strings.TrimSpace(b.AppID) == "" || strings.TrimSpace(b.InstallationID) == "" || b.PrivateKey == nil {
+		return Response{}, fmt.Errorf("github_app broker requires app id, installation id and private key")
+	}
+	if _, err := strconv.ParseInt(strings.TrimSpace(b.InstallationID), 10, 64); err != nil {
+		return Response{}, fmt.Errorf("github_app installation id must be numeric")
+	}
+	baseURL, err := normalizeBaseURL(b.BaseURL, defaultGitHubAPIURL)
+	if err != nil {
+		return Response{}, fmt.Errorf("github_app broker api url: %w", err)
+	}
+	issuedAt := brokerNow(b.Now)
+	appJWT, err := b.appJWT(issuedAt)
+	if err != nil {
+		return Response{}, err
+	}
+
+	tokenRequest := map[string]any{}
+	if permissions := githubPermissionsFromScope(request.Scope); len(permissions) > 0 {
+		tokenRequest["permissions"] = permissions
+	}
+	if repositories := githubRepositoriesFromReference(request.Reference); len(repositories) > 0 {
+		tokenRequest["repositories"] = repositories
+	}
+	payload, err := json.Marshal(tokenRequest)
+	if err != nil {
+		return Response{}, fmt.Errorf("marshal github_app token request: %w", err)
+	}
+	endpoint := baseURL + "/app/installations/" + strings.TrimSpace(b.InstallationID) + "/access_tokens"
+	httpRequest, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return Response{}, fmt.Errorf("build github_app request: %w", err)
+	}
+	httpRequest.Header.Set("Accept", "application/vnd.github+json")
+	httpRequest.Header.Set("Authorization", "Bearer "+appJWT)
+	httpRequest.Header.Set("Content-Type", "application/json")
+	body, err := doBrokerRequest(b.Client, httpRequest, "github_app")
+	if err != nil {
+		return Response{}, err
+	}
+	var token githubInstallationTokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return Response{}, fmt.Errorf("%w: parse github_app response", ErrCredentialUnavailable)
+	}
+	if strings.TrimSpace(token.Token) == "" {
+		return Response{}, fmt.Errorf("%w: github_app response missing token", ErrCredentialUnavailable)
+	}
+	expiresAt, err := time.Parse(time.RFC3339, strings.TrimSpace(token.ExpiresAt))
+	if err != nil {
+		return Response{}, fmt.Errorf("%w: invalid github_app expires_at", ErrCredentialUnavailable)
+	}
+	expiresAt = expiresAt.UTC()
+	if !expiresAt.After(issuedAt) {
+		return Response{}, fmt.Errorf("%w: github_app token already expired", ErrCredentialUnavailable)
+	}
+
+	sum := sha256.Sum256([]byte(token.Token))
+	credentialRef := "github_app:installation/" + strings.TrimSpace(b.InstallationID) + ":" + hex.EncodeToString(sum[:12])
+	if b.tokens != nil {
+		b.tokens.mu.Lock()
+		b.tokens.tokens[credentialRef] = token.Token
+		b.tokens.mu.Unlock()
+	}
+	scope := make([]string, 0, len(token.Permissions))
+	for name, level := range token.Permissions {
+		scope = append(scope, name+":"+level)
+	}
+	sort.Strings(scope)
+	if len(scope) == 0 {
+		scope = normalizeScope(request.Scope)
+	}
+	return Response{
+		IssuedBy:      "github_app",
+		Source:        "github_app",
+		AccessType:    "jit",
+		Issuer:        urlHost(baseURL),
+		Subject:       "app/" + strings.TrimSpace(b.AppID) + "/installation/" + strings.TrimSpace(b.InstallationID),
+		Owner:         request.Identity,
+		Scope:         normalizeScope(scope),
+		CredentialRef: credentialRef,
+		TargetBinding: request.TargetBinding,
+		RunBinding:    request.RunID,
+		JobBinding:    request.JobID,
+		IssuedAt:      issuedAt,
+		ExpiresAt:     expiresAt,
+		TTLSeconds:    int64(expiresAt.Sub(issuedAt).Seconds()),
+	}, nil
+}
+
+// Revoke deletes an installation token minted by this broker instance. Tokens
+// issued by another process are not held here and expire on their own.
+func (b GitHubAppBroker) Revoke(response Response) error {
+	credentialRef := strings.TrimSpace(response.CredentialRef)
+	token := ""
+	if b.tokens != nil {
+		b.tokens.mu.Lock()
+		token = b.tokens.tokens[credentialRef]
+		b.tokens.mu.Unlock()
+	}
+	if token == "" {
+		return fmt.Errorf("%w: github_app token %s is not held by this broker", ErrRevocationUnsupported, credentialRef)
+	}
+	baseURL, err := normalizeBaseURL(b.BaseURL, defaultGitHubAPIURL)
+	if err != nil {
+		return fmt.Errorf("github_app broker api url: %w", err)
+	}
+	httpRequest, err := http.NewRequest(http.MethodDelete, baseURL+"/installation/token", nil)
+	if err != nil {
+		return fmt.Errorf("build github_app revoke request: %w", err)
+	}
+	httpRequest.Header.Set("Accept", "application/vnd.github+json")
+	httpRequest.Header.Set("Authorization", "Bearer "+token)
+	if _, err := doBrokerRequest(b.Client, httpRequest, "github_app"); err != nil {
+		return err
+	}
+	b.tokens.mu.Lock()
+	delete(b.tokens.tokens, credentialRef)
+	b.tokens.mu.Unlock()
+	return nil
+}
+
+func (b GitHubAppBroker) appJWT(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("marshal github_app jwt header: %w", err)
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(githubAppJWTLifetime).Unix(),
+		"iss": strings.TrimSpace(b.AppID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal github_app jwt claims: %w", err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, b.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign github_app jwt: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func githubPermissionsFromScope(scope []string) map[string]string {
+	permissions := map[string]string{}
+	for _, entry := range normalizeScope(scope) {
+		name, level, ok := strings.Cut(entry, ":")
+		if !ok || name == "" {
+			continue
+		}
+		switch level {
+		case "read", "write", "admin":
+			permissions[name] = level
+		}
+	}
+	return permissions
+}
+
+func githubRepositoriesFromReference(reference string) []string {
+	repositories := []string{}
+	for _, entry := range strings.Split(reference, ",") {
+		trimmed := strings.TrimSpace(entry)
+		if trimmed == "" {
+			continue
+		}
+		if _, name, ok := strings.Cut(trimmed, "/"); ok {
+			trimmed = name
+		}
+		repositories = append(repositories, trimmed)
+	}
+	return repositories
+}
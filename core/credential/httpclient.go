@@ -0,0 +1,85 @@
+package credential
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBrokerHTTPTimeout      = 10 * time.Second
+	defaultBrokerResponseMaxBytes = 1 << 20
+)
+
+func brokerHTTPClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	return &http.Client{Timeout: defaultBrokerHTTPTimeout}
+}
+
+func brokerNow(now func() time.Time) time.Time {
+	if now != nil {
+		return now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// doBrokerRequest sends request and returns the bounded response body. Non-2xx
+// statuses are reported as ErrCredentialUnavailable without echoing the body,
+// which may carry provider secrets.
+func doBrokerRequest(client *http.Client, request *http.Request, provider string) ([]byte, error) {
+	response, err := brokerHTTPClient(client).Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s request failed: %v", ErrCredentialUnavailable, provider, redactURLError(err))
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	body, err := io.ReadAll(io.LimitReader(response.Body, defaultBrokerResponseMaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: read %s response: %v", ErrCredentialUnavailable, provider, err)
+	}
+	if len(body) > defaultBrokerResponseMaxBytes {
+		return nil, fmt.Errorf("%w: %s response exceeded %d bytes", ErrCredentialUnavailable, provider, defaultBrokerResponseMaxBytes)
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return nil, fmt.Errorf("%w: %s returned status %d", ErrCredentialUnavailable, provider, response.StatusCode)
+	}
+	return body, nil
+}
+
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+func normalizeBaseURL(raw string, fallback string) (string, error) {
+	trimmed := strings.TrimRight(strings.TrimSpace(raw), "/")
+	if trimmed == "" {
+		trimmed = fallback
+	}
+	if trimmed == "" {
+		return "", fmt.Errorf("base url is required")
+	}
+	parsed, err := url.Parse(trimmed)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return "", fmt.Errorf("invalid base url %q", trimmed)
+	}
+	return trimmed, nil
+}
+
+func urlHost(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
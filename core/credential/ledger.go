@@ -0,0 +1,391 @@
+package credential
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/fsx"
+)
+
+const (
+	ledgerEventSchemaID      = "gait.credential.lease_event"
+	ledgerEventSchemaVersion = "1.0.0"
+	DefaultLedgerPath        = "./gait-out/credentials/leases.jsonl"
+	LedgerPathEnv            = "GAIT_CREDENTIAL_LEDGER"
+	maxLedgerLineBytes       = 1 << 20
+)
+
+const (
+	LeaseStatusActive       = "active"
+	LeaseStatusRevoked      = "revoked"
+	LeaseStatusExpired      = "expired"
+	LeaseStatusRevokeFailed = "revoke_failed"
+)
+
+const (
+	RevokeReasonJobCompleted     = "job_completed"
+	RevokeReasonJobCancelled     = "job_cancelled"
+	RevokeReasonJobEmergencyStop = "job_emergency_stopped"
+	RevokeReasonKillSwitch       = "kill_switch"
+	RevokeReasonTTLExpired       = "ttl_expired"
+)
+
+const (
+	ledgerEventIssued       = "issued"
+	ledgerEventRevoked      = "revoked"
+	ledgerEventExpired      = "expired"
+	ledgerEventRevokeFailed = "revoke_failed"
+)
+
+// Lease is the ledger view of one issued credential ref. Status starts active
+// and moves to revoked, expired or revoke_failed as ledger events arrive; a
+// revoke_failed lease stays eligible for later revocation attempts.
+type Lease struct {
+	CredentialRef string    `json:"credential_ref"`
+	Broker        string    `json:"broker"`
+	Source        string    `json:"source,omitempty"`
+	Issuer        string    `json:"issuer,omitempty"`
+	Subject       string    `json:"subject,omitempty"`
+	Owner         string    `json:"owner,omitempty"`
+	ToolName      string    `json:"tool_name,omitempty"`
+	Identity      string    `json:"identity,omitempty"`
+	Scope         []string  `json:"scope,omitempty"`
+	TargetBinding string    `json:"target_binding,omitempty"`
+	RunBinding    string    `json:"run_binding,omitempty"`
+	JobBinding    string    `json:"job_binding,omitempty"`
+	RequestDigest string    `json:"request_digest,omitempty"`
+	IssuedAt      time.Time `json:"issued_at,omitempty"`
+	ExpiresAt     time.Time `json:"expires_at,omitempty"`
+	TTLSeconds    int64     `json:"ttl_seconds,omitempty"`
+	Status        string    `json:"status"`
+	StatusReason  string    `json:"status_reason,omitempty"`
+	StatusAt      time.Time `json:"status_at,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+type LedgerEvent struct {
+	SchemaID      string    `json:"schema_id"`
+	SchemaVersion string    `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Type          string    `json:"type"`
+	CredentialRef string    `json:"credential_ref"`
+	Reason        string    `json:"reason,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	Lease         *Lease    `json:"lease,omitempty"`
+}
+
+// LeaseFilter selects active leases. Empty fields match every lease.
+type LeaseFilter struct {
+	JobID    string
+	Identity string
+	ToolName string
+}
+
+type LedgerRevokeOptions struct {
+	Now time.Time
+	// Resolve returns the broker that revokes leases issued by the named
+	// broker. It defaults to ResolveBroker with environment configuration.
+	Resolve func(name string) (Broker, error)
+}
+
+func ResolveLedgerPath(path string) string {
+	if trimmed := strings.TrimSpace(path); trimmed != "" {
+		return trimmed
+	}
+	if fromEnv := strings.TrimSpace(os.Getenv(LedgerPathEnv)); fromEnv != "" {
+		return fromEnv
+	}
+	return DefaultLedgerPath
+}
+
+func LeaseFromResponse(request Request, response Response) Lease {
+	return Lease{
+		CredentialRef: strings.TrimSpace(response.CredentialRef),
+		Broker:        strings.TrimSpace(response.IssuedBy),
+		Source:        response.Source,
+		Issuer:        response.Issuer,
+		Subject:       response.Subject,
+		Owner:         response.Owner,
+		ToolName:      strings.ToLower(strings.TrimSpace(request.ToolName)),
+		Identity:      strings.TrimSpace(request.Identity),
+		Scope:         normalizeScope(response.Scope),
+		TargetBinding: response.TargetBinding,
+		RunBinding:    response.RunBinding,
+		JobBinding:    response.JobBinding,
+		RequestDigest: response.RequestDigest,
+		IssuedAt:      response.IssuedAt.UTC(),
+		ExpiresAt:     response.ExpiresAt.UTC(),
+		TTLSeconds:    response.TTLSeconds,
+		Status:        LeaseStatusActive,
+	}
+}
+
+func (lease Lease) Response() Response {
+	return Response{
+		IssuedBy:      lease.Broker,
+		Source:        lease.Source,
+		Issuer:        lease.Issuer,
+		Subject:       lease.Subject,
+		Owner:         lease.Owner,
+		Scope:         lease.Scope,
+		CredentialRef: lease.CredentialRef,
+		TargetBinding: lease.TargetBinding,
+		RunBinding:    lease.RunBinding,
+		JobBinding:    lease.JobBinding,
+		RequestDigest: lease.RequestDigest,
+		IssuedAt:      lease.IssuedAt,
+		ExpiresAt:     lease.ExpiresAt,
+		TTLSeconds:    lease.TTLSeconds,
+	}
+}
+
+// Outstanding reports whether the lease may still be usable at now.
+func (lease Lease) Outstanding(now time.Time) bool {
+	if lease.Status != LeaseStatusActive && lease.Status != LeaseStatusRevokeFailed {
+		return false
+	}
+	return lease.ExpiresAt.IsZero() || lease.ExpiresAt.After(now)
+}
+
+// RecordLease appends an issued event for response to the ledger at path.
+func RecordLease(path string, request Request, response Response, now time.Time) error {
+	lease := LeaseFromResponse(request, response)
+	if lease.CredentialRef == "" {
+		return fmt.Errorf("lease requires credential_ref")
+	}
+	return appendLedgerEvent(path, LedgerEvent{
+		CreatedAt:     normalizeLedgerNow(now),
+		Type:          ledgerEventIssued,
+		CredentialRef: lease.CredentialRef,
+		Lease:         &lease,
+	})
+}
+
+// LoadLeases folds the ledger at path into one lease per credential ref,
+// ordered by issue time. A missing ledger has no leases.
+func LoadLeases(path string) ([]Lease, error) {
+	// #nosec G304 -- ledger path is explicit local operator configuration.
+	file, err := os.Open(ResolveLedgerPath(path))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []Lease{}, nil
+		}
+		return nil, fmt.Errorf("open credential ledger: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	leases := map[string]*Lease{}
+	order := []string{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLedgerLineBytes)
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+		var event LedgerEvent
+		if err := json.Unmarshal([]byte(raw), &event); err != nil {
+			return nil, fmt.Errorf("parse credential ledger line %d: %w", line, err)
+		}
+		if event.SchemaID != ledgerEventSchemaID {
+			return nil, fmt.Errorf("credential ledger line %d has unsupported schema %q", line, event.SchemaID)
+		}
+		if event.Type == ledgerEventIssued {
+			if event.Lease == nil {
+				return nil, fmt.Errorf("credential ledger line %d missing lease", line)
+			}
+			if _, exists := leases[event.CredentialRef]; !exists {
+				order = append(order, event.CredentialRef)
+			}
+			lease := *event.Lease
+			lease.Status = LeaseStatusActive
+			leases[event.CredentialRef] = &lease
+			continue
+		}
+		lease, ok := leases[event.CredentialRef]
+		if !ok {
+			continue
+		}
+		switch event.Type {
+		case ledgerEventRevoked:
+			lease.Status = LeaseStatusRevoked
+		case ledgerEventExpired:
+			lease.Status = LeaseStatusExpired
+		case ledgerEventRevokeFailed:
+			if lease.Status != LeaseStatusActive && lease.Status != LeaseStatusRevokeFailed {
+				continue
+			}
+			lease.Status = LeaseStatusRevokeFailed
+		default:
+			return nil, fmt.Errorf("credential ledger line %d has unsupported event type %q", line, event.Type)
+		}
+		lease.StatusReason = event.Reason
+		lease.StatusAt = event.CreatedAt
+		lease.Error = event.Error
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read credential ledger: %w", err)
+	}
+	result := make([]Lease, 0, len(order))
+	for _, ref := range order {
+		result = append(result, *leases[ref])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].IssuedAt.Before(result[j].IssuedAt)
+	})
+	return result, nil
+}
+
+// RevokeLeases revokes every outstanding lease matching filter and records the
+// outcome in the ledger. Leases whose provider cannot revoke early are marked
+// expired with their TTL as the end of life.
+func RevokeLeases(path string, filter LeaseFilter, reason string, opts LedgerRevokeOptions) ([]RevokeResult, error) {
+	leases, err := LoadLeases(path)
+	if err != nil {
+		return nil, err
+	}
+	now := normalizeLedgerNow(opts.Now)
+	resolve := opts.Resolve
+	if resolve == nil {
+		resolve = resolveRevocationBroker
+	}
+	brokers := map[string]Broker{}
+	brokerErrors := map[string]error{}
+	results := []RevokeResult{}
+	for _, lease := range leases {
+		if !lease.Outstanding(now) || !filter.matches(lease) {
+			continue
+		}
+		broker, resolved := brokers[lease.Broker]
+		resolveErr := brokerErrors[lease.Broker]
+		if !resolved && resolveErr == nil {
+			broker, resolveErr = resolve(lease.Broker)
+			if resolveErr == nil && broker == nil {
+				resolveErr = fmt.Errorf("%w: no broker for %s", ErrRevocationUnsupported, lease.Broker)
+			}
+			if resolveErr != nil {
+				brokerErrors[lease.Broker] = resolveErr
+			} else {
+				brokers[lease.Broker] = broker
+			}
+		}
+		var result RevokeResult
+		if resolveErr != nil {
+			result = RevokeResult{
+				CredentialRef: lease.CredentialRef,
+				Source:        lease.Source,
+				JobBinding:    lease.JobBinding,
+				Status:        RevokeStatusFailed,
+				Error:         resolveErr.Error(),
+			}
+			if errors.Is(resolveErr, ErrRevocationUnsupported) {
+				result.Status = RevokeStatusExpires
+			}
+		} else {
+			result = revokeResponse(broker, lease.Response())
+		}
+		eventType := ledgerEventRevoked
+		switch result.Status {
+		case RevokeStatusExpires:
+			eventType = ledgerEventExpired
+		case RevokeStatusFailed:
+			eventType = ledgerEventRevokeFailed
+		}
+		if err := appendLedgerEvent(path, LedgerEvent{
+			CreatedAt:     now,
+			Type:          eventType,
+			CredentialRef: lease.CredentialRef,
+			Reason:        reason,
+			Error:         result.Error,
+		}); err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// SweepExpiredLeases marks leases whose TTL lapsed before now as expired. The
+// provider already ended them, so no revoke call is made.
+func SweepExpiredLeases(path string, now time.Time) ([]Lease, error) {
+	leases, err := LoadLeases(path)
+	if err != nil {
+		return nil, err
+	}
+	now = normalizeLedgerNow(now)
+	swept := []Lease{}
+	for _, lease := range leases {
+		if lease.Status != LeaseStatusActive && lease.Status != LeaseStatusRevokeFailed {
+			continue
+		}
+		if lease.ExpiresAt.IsZero() || lease.ExpiresAt.After(now) {
+			continue
+		}
+		if err := appendLedgerEvent(path, LedgerEvent{
+			CreatedAt:     now,
+			Type:          ledgerEventExpired,
+			CredentialRef: lease.CredentialRef,
+			Reason:        RevokeReasonTTLExpired,
+		}); err != nil {
+			return swept, err
+		}
+		lease.Status = LeaseStatusExpired
+		lease.StatusReason = RevokeReasonTTLExpired
+		lease.StatusAt = now
+		swept = append(swept, lease)
+	}
+	return swept, nil
+}
+
+// resolveRevocationBroker rebuilds the broker that issued a lease from the
+// environment. The command broker needs no command to report that it cannot
+// revoke.
+func resolveRevocationBroker(name string) (Broker, error) {
+	if strings.ToLower(strings.TrimSpace(name)) == "command" {
+		return CommandBroker{}, nil
+	}
+	return ResolveBroker(name, "", "", nil)
+}
+
+func (filter LeaseFilter) matches(lease Lease) bool {
+	if jobID := strings.TrimSpace(filter.JobID); jobID != "" && lease.JobBinding != jobID {
+		return false
+	}
+	if identity := strings.TrimSpace(filter.Identity); identity != "" && lease.Identity != identity {
+		return false
+	}
+	if toolName := strings.ToLower(strings.TrimSpace(filter.ToolName)); toolName != "" && lease.ToolName != toolName {
+		return false
+	}
+	return true
+}
+
+func appendLedgerEvent(path string, event LedgerEvent) error {
+	event.SchemaID = ledgerEventSchemaID
+	event.SchemaVersion = ledgerEventSchemaVersion
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal credential ledger event: %w", err)
+	}
+	if err := fsx.AppendLineLocked(ResolveLedgerPath(path), encoded, 0o600); err != nil {
+		return fmt.Errorf("append credential ledger: %w", err)
+	}
+	return nil
+}
+
+func normalizeLedgerNow(now time.Time) time.Time {
+	if now.IsZero() {
+		return time.Now().UTC()
+	}
+	return now.UTC()
+}
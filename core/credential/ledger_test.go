@@ -0,0 +1,97 @@
+package credential
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type failingRevokeBroker struct{}
+
+func (failingRevokeBroker) Name() string { return "failing" }
+
+func (failingRevokeBroker) Issue(Request) (Response, error) { return Response{}, errors.New("unused") }
+
+func (failingRevokeBroker) Revoke(Response) error { return errors.New("provider unreachable") }
+
+func TestLeaseLedgerRevokeAndSweep(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leases.jsonl")
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	record := func(ref string, broker string, jobID string, ttl time.Duration) {
+		t.Helper()
+		response := Response{IssuedBy: broker, Source: broker, CredentialRef: ref, JobBinding: jobID, IssuedAt: now, ExpiresAt: now.Add(ttl), TTLSeconds: int64(ttl.Seconds())}
+		if err := RecordLease(path, Request{ToolName: "tool.write", Identity: "alice", JobID: jobID}, response, now); err != nil {
+			t.Fatalf("record lease %s: %v", ref, err)
+		}
+	}
+	record("stub:a", "stub", "job-1", time.Hour)
+	record("env:b", "env", "job-1", time.Hour)
+	record("failing:c", "failing", "job-1", time.Hour)
+	record("stub:d", "stub", "job-2", time.Hour)
+	record("stub:e", "stub", "job-3", time.Minute)
+
+	results, err := RevokeLeases(path, LeaseFilter{JobID: "job-1"}, RevokeReasonJobCompleted, LedgerRevokeOptions{
+		Now: now.Add(time.Second),
+		Resolve: func(name string) (Broker, error) {
+			switch name {
+			case "stub":
+				return StubBroker{}, nil
+			case "env":
+				return EnvBroker{}, nil
+			default:
+				return failingRevokeBroker{}, nil
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("revoke leases: %v", err)
+	}
+	statuses := map[string]string{}
+	for _, result := range results {
+		statuses[result.CredentialRef] = result.Status
+	}
+	if len(results) != 3 || statuses["stub:a"] != RevokeStatusRevoked || statuses["env:b"] != RevokeStatusExpires || statuses["failing:c"] != RevokeStatusFailed {
+		t.Fatalf("unexpected revoke results: %#v", results)
+	}
+
+	swept, err := SweepExpiredLeases(path, now.Add(2*time.Minute))
+	if err != nil {
+		t.Fatalf("sweep leases: %v", err)
+	}
+	if len(swept) != 1 || swept[0].CredentialRef != "stub:e" {
+		t.Fatalf("expected only stub:e to be swept, got %#v", swept)
+	}
+
+	leases, err := LoadLeases(path)
+	if err != nil {
+		t.Fatalf("load leases: %v", err)
+	}
+	byRef := map[string]Lease{}
+	for _, lease := range leases {
+		byRef[lease.CredentialRef] = lease
+	}
+	checkNow := now.Add(2 * time.Minute)
+	if byRef["stub:a"].Status != LeaseStatusRevoked || byRef["stub:a"].StatusReason != RevokeReasonJobCompleted || byRef["stub:a"].Outstanding(checkNow) {
+		t.Fatalf("expected stub:a revoked, got %#v", byRef["stub:a"])
+	}
+	if byRef["env:b"].Status != LeaseStatusExpired || byRef["env:b"].Outstanding(checkNow) {
+		t.Fatalf("expected env:b left to expire, got %#v", byRef["env:b"])
+	}
+	if byRef["failing:c"].Status != LeaseStatusRevokeFailed || byRef["failing:c"].Error == "" || !byRef["failing:c"].Outstanding(checkNow) {
+		t.Fatalf("expected failing:c to stay outstanding after failed revoke, got %#v", byRef["failing:c"])
+	}
+	if byRef["stub:d"].Status != LeaseStatusActive || !byRef["stub:d"].Outstanding(checkNow) {
+		t.Fatalf("expected job-2 lease untouched, got %#v", byRef["stub:d"])
+	}
+	if byRef["stub:e"].Status != LeaseStatusExpired || byRef["stub:e"].StatusReason != RevokeReasonTTLExpired {
+		t.Fatalf("expected stub:e swept as ttl_expired, got %#v", byRef["stub:e"])
+	}
+}
+
+func TestLoadLeasesMissingLedgerIsEmpty(t *testing.T) {
+	leases, err := LoadLeases(filepath.Join(t.TempDir(), "missing.jsonl"))
+	if err != nil || len(leases) != 0 {
+		t.Fatalf("expected empty ledger, got %#v err=%v", leases, err)
+	}
+}
@@ -282,7 +282,7 @@ func looksLikeSecretReceiptValue(value string) bool {
 		return false
 	}
 	lower := strings.ToLower(trimmed)
-	for _, prefix := range []string{"ref:", "stub:", "cmd:", "env:", "aws_sts:", "github_oidc:", "vault_dynamic:", "gcp_sts:", "azure_federated:", "okta_cyberark:", "file:"} {
+	for _, prefix := range []string{"ref:", "stub:", "cmd:", "env:", "aws_sts:", "github_oidc:", "vault_dynamic:", "gcp_sts:", "azure_federated:", "okta_cyberark:", "github_app:", "file:"} {
 		if strings.HasPrefix(lower, prefix) {
 			return false
 		}
@@ -64,6 +64,11 @@ func (StubBroker) Issue(request Request) (Response, error) {
 	}, nil
 }
 
+// Revoke is a no-op: stub refs are synthetic and carry no provider state.
+func (StubBroker) Revoke(Response) error {
+	return nil
+}
+
 type EnvBroker struct {
 	Prefix string
 }
@@ -159,6 +164,10 @@ func (b EnvBroker) Issue(request Request) (Response, error) {
 	}, nil
 }
 
+func (b EnvBroker) Revoke(Response) error {
+	return fmt.Errorf("%w: env credentials are managed outside gait", ErrRevocationUnsupported)
+}
+
 type CommandBroker struct {
 	Command string
 	Args    []string
@@ -247,6 +256,10 @@ func (b CommandBroker) Issue(request Request) (Response, error) {
 	return response, nil
 }
 
+func (b CommandBroker) Revoke(Response) error {
+	return fmt.Errorf("%w: command broker has no revoke protocol", ErrRevocationUnsupported)
+}
+
 func ResolveBroker(name string, envPrefix string, command string, commandArgs []string) (Broker, error) {
 	switch strings.ToLower(strings.TrimSpace(name)) {
 	case "", "off", "none":
@@ -268,6 +281,56 @@ func ResolveBroker(name string, envPrefix string, command string, commandArgs []
 			Command: commandPath,
 			Args:    normalizeCommandArgs(commandArgs),
 		}, nil
+	case "vault", "vault_dynamic":
+		address := strings.TrimSpace(os.Getenv(vaultAddrEnv))
+		token := strings.TrimSpace(os.Getenv(vaultTokenEnv))
+		if address == "" || token == "" {
+			return nil, fmt.Errorf("vault_dynamic broker requires %s and %s", vaultAddrEnv, vaultTokenEnv)
+		}
+		return VaultBroker{
+			Address:   address,
+			Token:     token,
+			Namespace: strings.TrimSpace(os.Getenv(vaultNamespaceEnv)),
+		}, nil
+	case "aws_sts":
+		accessKeyID := strings.TrimSpace(os.Getenv(awsAccessKeyIDEnv))
+		secretAccessKey := strings.TrimSpace(os.Getenv(awsSecretAccessKeyEnv))
+		if accessKeyID == "" || secretAccessKey == "" {
+			return nil, fmt.Errorf("aws_sts broker requires %s and %s", awsAccessKeyIDEnv, awsSecretAccessKeyEnv)
+		}
+		region := strings.TrimSpace(os.Getenv(awsRegionEnv))
+		if region == "" {
+			region = strings.TrimSpace(os.Getenv(awsDefaultRegionEnv))
+		}
+		duration := int64(0)
+		if raw := strings.TrimSpace(os.Getenv(awsSTSDurationEnv)); raw != "" {
+			parsed, err := parseInt64(raw)
+			if err != nil {
+				return nil, fmt.Errorf("parse %s: %w", awsSTSDurationEnv, err)
+			}
+			duration = parsed
+		}
+		return AWSSTSBroker{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: secretAccessKey,
+			SessionToken:    strings.TrimSpace(os.Getenv(awsSessionTokenEnv)),
+			Region:          region,
+			RoleARN:         strings.TrimSpace(os.Getenv(awsSTSRoleARNEnv)),
+			Endpoint:        strings.TrimSpace(os.Getenv(awsSTSEndpointEnv)),
+			DurationSeconds: duration,
+		}, nil
+	case "github_app":
+		appID := strings.TrimSpace(os.Getenv(githubAppIDEnv))
+		installationID := strings.TrimSpace(os.Getenv(githubAppInstallationIDEnv))
+		keyPath := strings.TrimSpace(os.Getenv(githubAppPrivateKeyPathEnv))
+		if appID == "" || installationID == "" || keyPath == "" {
+			return nil, fmt.Errorf("github_app broker requires %s, %s and %s", githubAppIDEnv, githubAppInstallationIDEnv, githubAppPrivateKeyPathEnv)
+		}
+		privateKey, err := LoadGitHubAppPrivateKey(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		return NewGitHubAppBroker(appID, installationID, privateKey, os.Getenv(githubAPIURLEnv)), nil
 	default:
 		return nil, fmt.Errorf("unsupported credential broker: %s", name)
 	}
@@ -0,0 +1,292 @@
+package credential
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVaultBrokerIssueAndRevoke(t *testing.T) {
+	var mu sync.Mutex
+	revoked := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Header.Get("X-Vault-Token") != "root-token" || request.Header.Get("X-Vault-Namespace") != "team" {
+			writer.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case request.Method == http.MethodGet && request.URL.Path == "/v1/database/creds/readonly":
+			_, _ = io.WriteString(writer, `{"lease_id":"database/creds/readonly/abc123","lease_duration":600,"renewable":true,"data":{"username":"v-user","password":"s3cret"}}`)
+		case request.Method == http.MethodPut && request.URL.Path == "/v1/sys/leases/revoke":
+			var body map[string]string
+			_ = json.NewDecoder(request.Body).Decode(&body)
+			mu.Lock()
+			revoked = append(revoked, body["lease_id"])
+			mu.Unlock()
+			writer.WriteHeader(http.StatusNoContent)
+		default:
+			writer.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	broker := VaultBroker{Address: server.URL, Token: "root-token", Namespace: "team", Now: func() time.Time { return now }}
+	response, err := Issue(broker, Request{
+		ToolName:  "tool.db_query",
+		Identity:  "alice",
+		JobID:     "job-1",
+		Reference: "database/creds/readonly",
+		Scope:     []string{"db:read"},
+	})
+	if err != nil {
+		t.Fatalf("issue vault credential: %v", err)
+	}
+	if response.CredentialRef != "vault_dynamic:database/creds/readonly/abc123" || response.Source != "vault_dynamic" || response.AccessType != "jit" {
+		t.Fatalf("unexpected vault response: %#v", response)
+	}
+	if response.TTLSeconds != 600 || !response.ExpiresAt.Equal(now.Add(10*time.Minute)) || response.JobBinding != "job-1" || response.RequestDigest == "" {
+		t.Fatalf("unexpected vault lease fields: %#v", response)
+	}
+	if strings.Contains(fmt.Sprintf("%#v", response), "s3cret") {
+		t.Fatalf("vault response leaked secret data: %#v", response)
+	}
+	if err := Revoke(broker, response); err != nil {
+		t.Fatalf("revoke vault lease: %v", err)
+	}
+	if len(revoked) != 1 || revoked[0] != "database/creds/readonly/abc123" {
+		t.Fatalf("unexpected revoked leases: %#v", revoked)
+	}
+
+	if _, err := Issue(broker, Request{ToolName: "tool.db_query", Identity: "alice", Reference: "secret/data/static"}); !errors.Is(err, ErrCredentialUnavailable) {
+		t.Fatalf("expected unavailable error for non-leased secret, got %v", err)
+	}
+}
+
+func TestAWSSTSBrokerAssumeRoleSigned(t *testing.T) {
+	var captured *http.Request
+	var capturedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		raw, _ := io.ReadAll(request.Body)
+		captured = request
+		capturedBody = string(raw)
+		writer.Header().Set("Content-Type", "text/xml")
+		_, _ = io.WriteString(writer, `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>ASIAEXAMPLE</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>session</SessionToken>
+      <Expiration>2026-03-01T12:15:00Z</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <AssumedRoleId>AROAEXAMPLE:gait-job-1</AssumedRoleId>
+      <Arn>arn:aws:sts::123456789012:assumed-role/deploy/gait-job-1</Arn>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`)
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	broker := AWSSTSBroker{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		SessionToken:    "base-session",
+		Region:          "us-west-2",
+		RoleARN:         "arn:aws:iam::123456789012:role/deploy",
+		Endpoint:        server.URL,
+		Now:             func() time.Time { return now },
+	}
+	response, err := Issue(broker, Request{ToolName: "tool.deploy", Identity: "alice", JobID: "job-1", Scope: []string{"deploy:prod"}})
+	if err != nil {
+		t.Fatalf("issue aws_sts credential: %v", err)
+	}
+	if response.CredentialRef != "aws_sts:arn:aws:sts::123456789012:assumed-role/deploy/gait-job-1" || response.Issuer != "sts.amazonaws.com" {
+		t.Fatalf("unexpected aws_sts response: %#v", response)
+	}
+	if response.TTLSeconds != 900 || response.Scope[0] != "deploy:prod" {
+		t.Fatalf("unexpected aws_sts ttl/scope: %#v", response)
+	}
+	authorization := captured.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20260301/us-west-2/sts/aws4_request, SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date;x-amz-security-token, Signature=") {
+		t.Fatalf("unexpected sigv4 authorization header: %q", authorization)
+	}
+	if captured.Header.Get("X-Amz-Date") != "20260301T120000Z" || captured.Header.Get("X-Amz-Security-Token") != "base-session" {
+		t.Fatalf("unexpected sigv4 headers: %#v", captured.Header)
+	}
+	for _, expected := range []string{"Action=AssumeRole", "RoleArn=arn%3Aaws%3Aiam%3A%3A123456789012%3Arole%2Fdeploy", "DurationSeconds=900", "RoleSessionName=gait-job-1-"} {
+		if !strings.Contains(capturedBody, expected) {
+			t.Fatalf("expected %q in assume role body %q", expected, capturedBody)
+		}
+	}
+	if err := Revoke(broker, response); !errors.Is(err, ErrRevocationUnsupported) {
+		t.Fatalf("expected aws_sts revoke to be unsupported, got %v", err)
+	}
+}
+
+func TestGitHubAppBrokerIssueAndRevoke(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	deleted := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch {
+		case request.Method == http.MethodPost && request.URL.Path == "/app/installations/42/access_tokens":
+			if err := verifyTestAppJWT(strings.TrimPrefix(request.Header.Get("Authorization"), "Bearer "), &privateKey.PublicKey, "1001"); err != nil {
+				t.Errorf("app jwt: %v", err)
+				writer.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			var body struct {
+				Permissions  map[string]string `json:"permissions"`
+				Repositories []string          `json:"repositories"`
+			}
+			_ = json.NewDecoder(request.Body).Decode(&body)
+			if body.Permissions["contents"] != "read" || len(body.Repositories) != 1 || body.Repositories[0] != "gait" {
+				t.Errorf("unexpected token request: %#v", body)
+			}
+			writer.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(writer).Encode(map[string]any{
+				"token":       "ghs_installationtoken",
+				"expires_at":  "2026-03-01T13:00:00Z",
+				"permissions": body.Permissions,
+			})
+		case request.Method == http.MethodDelete && request.URL.Path == "/installation/token":
+			if request.Header.Get("Authorization") != "Bearer ghs_installationtoken" {
+				writer.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			deleted++
+			writer.WriteHeader(http.StatusNoContent)
+		default:
+			writer.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	broker := NewGitHubAppBroker("1001", "42", privateKey, server.URL)
+	broker.Now = func() time.Time { return now }
+	response, err := Issue(broker, Request{
+		ToolName:  "tool.git_push",
+		Identity:  "alice",
+		JobID:     "job-1",
+		Reference: "Clyra-AI/gait",
+		Scope:     []string{"contents:read", "ticket:open"},
+	})
+	if err != nil {
+		t.Fatalf("issue github_app credential: %v", err)
+	}
+	if !strings.HasPrefix(response.CredentialRef, "github_app:installation/42:") || strings.Contains(response.CredentialRef, "ghs_") {
+		t.Fatalf("unexpected github_app credential ref: %#v", response)
+	}
+	if response.TTLSeconds != 3600 || len(response.Scope) != 1 || response.Scope[0] != "contents:read" || response.Subject != "app/1001/installation/42" {
+		t.Fatalf("unexpected github_app response: %#v", response)
+	}
+
+	results, err := RevokeJob(broker, []Response{response, {CredentialRef: "github_app:installation/42:other", JobBinding: "job-2"}}, "job-1")
+	if err != nil {
+		t.Fatalf("revoke job credentials: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != RevokeStatusRevoked || deleted != 1 {
+		t.Fatalf("unexpected revoke results=%#v deleted=%d", results, deleted)
+	}
+	results, err = RevokeJob(broker, []Response{response}, "job-1")
+	if err != nil {
+		t.Fatalf("revoke job credentials again: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != RevokeStatusExpires {
+		t.Fatalf("expected already revoked token to be reported as expiring, got %#v", results)
+	}
+}
+
+func TestResolveRemoteBrokersFromEnv(t *testing.T) {
+	t.Setenv(vaultAddrEnv, "")
+	t.Setenv(vaultTokenEnv, "")
+	if _, err := ResolveBroker("vault_dynamic", "", "", nil); err == nil {
+		t.Fatalf("expected vault broker without address to fail")
+	}
+	t.Setenv(vaultAddrEnv, "http://127.0.0.1:8200")
+	t.Setenv(vaultTokenEnv, "token")
+	broker, err := ResolveBroker("vault", "", "", nil)
+	if err != nil || broker.Name() != "vault_dynamic" {
+		t.Fatalf("resolve vault broker: broker=%#v err=%v", broker, err)
+	}
+
+	t.Setenv(awsAccessKeyIDEnv, "AKIDEXAMPLE")
+	t.Setenv(awsSecretAccessKeyEnv, "secret")
+	t.Setenv(awsSTSDurationEnv, "soon")
+	if _, err := ResolveBroker("aws_sts", "", "", nil); err == nil {
+		t.Fatalf("expected invalid aws_sts duration to fail")
+	}
+	t.Setenv(awsSTSDurationEnv, "1800")
+	broker, err = ResolveBroker("aws_sts", "", "", nil)
+	if err != nil || broker.(AWSSTSBroker).DurationSeconds != 1800 {
+		t.Fatalf("resolve aws_sts broker: broker=%#v err=%v", broker, err)
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "app.pem")
+	encoded := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	if err := os.WriteFile(keyPath, encoded, 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	t.Setenv(githubAppIDEnv, "1001")
+	t.Setenv(githubAppInstallationIDEnv, "42")
+	t.Setenv(githubAppPrivateKeyPathEnv, keyPath)
+	broker, err = ResolveBroker("github_app", "", "", nil)
+	if err != nil || broker.Name() != "github_app" {
+		t.Fatalf("resolve github_app broker: broker=%#v err=%v", broker, err)
+	}
+}
+
+func verifyTestAppJWT(token string, publicKey *rsa.PublicKey, expectedIssuer string) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("expected three jwt segments")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature); err != nil {
+		return err
+	}
+	rawClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return err
+	}
+	var claims struct {
+		Issuer string `json:"iss"`
+		Expiry int64  `json:"exp"`
+	}
+	if err := json.Unmarshal(rawClaims, &claims); err != nil {
+		return err
+	}
+	if claims.Issuer != expectedIssuer || claims.Expiry == 0 {
+		return fmt.Errorf("unexpected claims %#v", claims)
+	}
+	return nil
+}
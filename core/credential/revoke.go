@@ -0,0 +1,80 @@
+package credential
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrRevocationUnsupported reports that a credential cannot be revoked early
+// and stays valid until its TTL lapses.
+var ErrRevocationUnsupported = errors.New("credential revocation unsupported")
+
+const (
+	RevokeStatusRevoked = "revoked"
+	RevokeStatusExpires = "expires"
+	RevokeStatusFailed  = "failed"
+)
+
+type RevokeResult struct {
+	CredentialRef string `json:"credential_ref"`
+	Source        string `json:"source,omitempty"`
+	JobBinding    string `json:"job_binding,omitempty"`
+	Status        string `json:"status"`
+	Error         string `json:"error,omitempty"`
+}
+
+func Revoke(broker Broker, response Response) error {
+	if broker == nil {
+		return fmt.Errorf("broker is required")
+	}
+	if strings.TrimSpace(response.CredentialRef) == "" {
+		return fmt.Errorf("credential_ref is required")
+	}
+	return broker.Revoke(response)
+}
+
+// RevokeJob revokes every credential bound to jobID. Credentials that cannot
+// be revoked early are reported with status expires rather than failing the
+// batch.
+func RevokeJob(broker Broker, issued []Response, jobID string) ([]RevokeResult, error) {
+	trimmedJobID := strings.TrimSpace(jobID)
+	if trimmedJobID == "" {
+		return nil, fmt.Errorf("job_id is required")
+	}
+	seen := map[string]struct{}{}
+	results := []RevokeResult{}
+	for _, response := range issued {
+		ref := strings.TrimSpace(response.CredentialRef)
+		if strings.TrimSpace(response.JobBinding) != trimmedJobID || ref == "" {
+			continue
+		}
+		if _, ok := seen[ref]; ok {
+			continue
+		}
+		seen[ref] = struct{}{}
+		results = append(results, revokeResponse(broker, response))
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].CredentialRef < results[j].CredentialRef
+	})
+	return results, nil
+}
+
+func revokeResponse(broker Broker, response Response) RevokeResult {
+	result := RevokeResult{
+		CredentialRef: strings.TrimSpace(response.CredentialRef),
+		Source:        response.Source,
+		JobBinding:    strings.TrimSpace(response.JobBinding),
+		Status:        RevokeStatusRevoked,
+	}
+	if err := Revoke(broker, response); err != nil {
+		result.Status = RevokeStatusFailed
+		if errors.Is(err, ErrRevocationUnsupported) {
+			result.Status = RevokeStatusExpires
+		}
+		result.Error = err.Error()
+	}
+	return result
+}
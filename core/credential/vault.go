@@ -0,0 +1,131 @@
+package credential
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	vaultAddrEnv      = "VAULT_ADDR"
+	vaultTokenEnv     = "VAULT_TOKEN"
+	vaultNamespaceEnv = "VAULT_NAMESPACE"
+)
+
+// VaultBroker reads dynamic secrets from the Vault HTTP API. The request
+// reference (or Path) names the secret endpoint, for example
+// database/creds/readonly; only the lease id leaves the broker.
+type VaultBroker struct {
+	Address   string
+	Token     string
+	Namespace string
+	Path      string
+	Client    *http.Client
+	Now       func() time.Time
+}
+
+type vaultSecretResponse struct {
+	LeaseID       string `json:"lease_id"`
+	LeaseDuration int64  `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+func (b VaultBroker) Name() string {
+	return "vault_dynamic"
+}
+
+func (b VaultBroker) Issue(request Request) (Response, error) {
+	secretPath := strings.Trim(strings.TrimSpace(request.Reference), "/")
+	if secretPath == "" {
+		secretPath = strings.Trim(strings.TrimSpace(b.Path), "/")
+	}
+	if secretPath == "" {
+		return Response{}, fmt.Errorf("vault broker requires a secret path reference")
+	}
+	if strings.Contains(secretPath, "..") {
+		return Response{}, fmt.Errorf("vault secret path must not contain '..'")
+	}
+	address, err := b.address()
+	if err != nil {
+		return Response{}, err
+	}
+	issuedAt := brokerNow(b.Now)
+	httpRequest, err := http.NewRequest(http.MethodGet, address+"/v1/"+secretPath, nil)
+	if err != nil {
+		return Response{}, fmt.Errorf("build vault request: %w", err)
+	}
+	b.authorize(httpRequest)
+	body, err := doBrokerRequest(b.Client, httpRequest, "vault")
+	if err != nil {
+		return Response{}, err
+	}
+	var secret vaultSecretResponse
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return Response{}, fmt.Errorf("%w: parse vault response", ErrCredentialUnavailable)
+	}
+	leaseID := strings.TrimSpace(secret.LeaseID)
+	if leaseID == "" || secret.LeaseDuration <= 0 {
+		return Response{}, fmt.Errorf("%w: vault secret %s is not a leased dynamic secret", ErrCredentialUnavailable, secretPath)
+	}
+	ttl := time.Duration(secret.LeaseDuration) * time.Second
+	return Response{
+		IssuedBy:      "vault_dynamic",
+		Source:        "vault_dynamic",
+		AccessType:    "jit",
+		Issuer:        urlHost(address),
+		Subject:       request.Identity,
+		Owner:         request.Identity,
+		Scope:         normalizeScope(request.Scope),
+		CredentialRef: "vault_dynamic:" + leaseID,
+		TargetBinding: request.TargetBinding,
+		RunBinding:    request.RunID,
+		JobBinding:    request.JobID,
+		IssuedAt:      issuedAt,
+		ExpiresAt:     issuedAt.Add(ttl),
+		TTLSeconds:    secret.LeaseDuration,
+	}, nil
+}
+
+// Revoke ends the lease behind response through sys/leases/revoke.
+func (b VaultBroker) Revoke(response Response) error {
+	leaseID, ok := strings.CutPrefix(strings.TrimSpace(response.CredentialRef), "vault_dynamic:")
+	if !ok || strings.TrimSpace(leaseID) == "" {
+		return fmt.Errorf("%w: %s is not a vault lease", ErrRevocationUnsupported, response.CredentialRef)
+	}
+	address, err := b.address()
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(map[string]string{"lease_id": leaseID})
+	if err != nil {
+		return fmt.Errorf("marshal vault revoke request: %w", err)
+	}
+	httpRequest, err := http.NewRequest(http.MethodPut, address+"/v1/sys/leases/revoke", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("build vault revoke request: %w", err)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	b.authorize(httpRequest)
+	if _, err := doBrokerRequest(b.Client, httpRequest, "vault"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (b VaultBroker) address() (string, error) {
+	address, err := normalizeBaseURL(b.Address, "")
+	if err != nil {
+		return "", fmt.Errorf("vault broker requires %s: %w", vaultAddrEnv, err)
+	}
+	return address, nil
+}
+
+func (b VaultBroker) authorize(request *http.Request) {
+	request.Header.Set("X-Vault-Token", strings.TrimSpace(b.Token))
+	if namespace := strings.TrimSpace(b.Namespace); namespace != "" {
+		request.Header.Set("X-Vault-Namespace", namespace)
+	}
+}
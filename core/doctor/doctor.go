@@ -910,15 +910,16 @@ func checkProductionServiceBoundary(defaults projectconfig.MCPServeDefaults) Che
 			Message: fmt.Sprintf("invalid mcp_serve.listen: %v", loopbackErr),
 		}
 	}
-	if !isLoopback && strings.ToLower(strings.TrimSpace(defaults.AuthMode)) != "token" {
+	authMode := strings.ToLower(strings.TrimSpace(defaults.AuthMode))
+	if !isLoopback && authMode != "token" && authMode != "jwt" && authMode != "mtls" {
 		return Check{
 			Name:       "production_service_boundary",
 			Status:     statusFail,
-			Message:    "non-loopback mcp_serve.listen requires mcp_serve.auth_mode=token",
+			Message:    "non-loopback mcp_serve.listen requires mcp_serve.auth_mode=token, jwt or mtls",
 			FixCommand: fmt.Sprintf("set mcp_serve.auth_mode=token in %s", shellQuote(projectconfig.DefaultPath)),
 		}
 	}
-	if strings.ToLower(strings.TrimSpace(defaults.AuthMode)) == "token" && strings.TrimSpace(defaults.AuthTokenEnv) == "" {
+	if authMode == "token" && strings.TrimSpace(defaults.AuthTokenEnv) == "" {
 		return Check{
 			Name:       "production_service_boundary",
 			Status:     statusFail,
@@ -926,6 +927,22 @@ func checkProductionServiceBoundary(defaults projectconfig.MCPServeDefaults) Che
 			FixCommand: fmt.Sprintf("set mcp_serve.auth_token_env in %s", shellQuote(projectconfig.DefaultPath)),
 		}
 	}
+	if authMode == "jwt" && strings.TrimSpace(defaults.JWT.JWKS) == "" && strings.TrimSpace(defaults.JWT.Issuer) == "" {
+		return Check{
+			Name:       "production_service_boundary",
+			Status:     statusFail,
+			Message:    "mcp_serve.auth_mode=jwt requires mcp_serve.jwt.jwks or mcp_serve.jwt.issuer",
+			FixCommand: fmt.Sprintf("set mcp_serve.jwt.jwks in %s", shellQuote(projectconfig.DefaultPath)),
+		}
+	}
+	if authMode == "mtls" && (strings.TrimSpace(defaults.TLSCert) == "" || strings.TrimSpace(defaults.TLSKey) == "" || strings.TrimSpace(defaults.TLSClientCA) == "") {
+		return Check{
+			Name:       "production_service_boundary",
+			Status:     statusFail,
+			Message:    "mcp_serve.auth_mode=mtls requires mcp_serve.tls_cert, mcp_serve.tls_key and mcp_serve.tls_client_ca",
+			FixCommand: fmt.Sprintf("set mcp_serve.tls_cert, mcp_serve.tls_key and mcp_serve.tls_client_ca in %s", shellQuote(projectconfig.DefaultPath)),
+		}
+	}
 	if defaults.AllowClientArtifactPaths {
 		return Check{
 			Name:       "production_service_boundary",
@@ -550,6 +550,30 @@ func TestProductionReadinessChecks(t *testing.T) {
 		t.Fatalf("expected production service boundary pass, got %#v", goodService)
 	}
 
+	mtlsService := checkProductionServiceBoundary(projectconfig.MCPServeDefaults{
+		Enabled:           true,
+		Listen:            "0.0.0.0:8787",
+		AuthMode:          "mtls",
+		TLSCert:           "server.pem",
+		TLSKey:            "server.key",
+		TLSClientCA:       "clients.pem",
+		MaxRequestBytes:   1 << 20,
+		HTTPVerdictStatus: "strict",
+	})
+	if mtlsService.Status != statusPass {
+		t.Fatalf("expected mtls service boundary pass, got %#v", mtlsService)
+	}
+	jwtService := checkProductionServiceBoundary(projectconfig.MCPServeDefaults{
+		Enabled:           true,
+		Listen:            "0.0.0.0:8787",
+		AuthMode:          "jwt",
+		MaxRequestBytes:   1 << 20,
+		HTTPVerdictStatus: "strict",
+	})
+	if jwtService.Status != statusFail || !strings.Contains(jwtService.Message, "jwt.jwks") {
+		t.Fatalf("expected jwt service boundary fail without key source, got %#v", jwtService)
+	}
+
 	badService := checkProductionServiceBoundary(projectconfig.MCPServeDefaults{
 		Enabled:         true,
 		Listen:          "0.0.0.0:8787",
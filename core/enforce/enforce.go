@@ -0,0 +1,318 @@
+// Package enforce applies the steps every enforcement boundary runs after
+// policy evaluation: job emergency stop, rate limits, job constraints, broker
+// credentials, and the kill-switch journal. `gait gate eval`, `gait mcp` and
+// pkg/gaitclient share it so a call gets the same verdict on every path.
+package enforce
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/credential"
+	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/jobruntime"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+)
+
+const (
+	verdictAllow = "allow"
+	verdictBlock = "block"
+
+	ReasonEmergencyStopPreempted        = "emergency_stop_preempted"
+	ReasonEmergencyStopStateUnavailable = "emergency_stop_state_unavailable"
+	ReasonJobConstraintsUnavailable     = "job_constraints_unavailable"
+)
+
+// Boundary names the caller in job events, blocked-dispatch records and
+// kill-switch journal records.
+type Boundary struct {
+	Actor           string
+	DispatchPath    string
+	JournalSource   string
+	ProducerVersion string
+}
+
+// EmergencyStop blocks calls for a job that is emergency stopped and records
+// the blocked dispatch. A job whose state cannot be read, including an unknown
+// job, is treated as stopped.
+func EmergencyStop(jobRoot string, boundary Boundary, intent schemagate.IntentRequest, result schemagate.GateResult) (schemagate.GateResult, []string) {
+	jobID := strings.TrimSpace(intent.Context.JobID)
+	if jobID == "" {
+		return result, nil
+	}
+	reason := ""
+	var warnings []string
+	state, err := jobruntime.Status(jobRoot, jobID)
+	switch {
+	case err != nil:
+		reason = ReasonEmergencyStopStateUnavailable
+		warnings = []string{fmt.Sprintf("job_status_unavailable=%v", err)}
+	case jobruntime.IsEmergencyStopped(state):
+		reason = ReasonEmergencyStopPreempted
+		if _, recordErr := jobruntime.RecordBlockedDispatch(jobRoot, jobID, jobruntime.DispatchRecordOptions{
+			Actor:        boundary.Actor,
+			DispatchPath: boundary.DispatchPath,
+			ReasonCode:   reason,
+		}); recordErr != nil {
+			warnings = []string{fmt.Sprintf("blocked_dispatch_record_failed=%v", recordErr)}
+		}
+	default:
+		return result, nil
+	}
+	result.Verdict = verdictBlock
+	result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{reason})
+	result.Violations = mergeUniqueSorted(result.Violations, []string{"emergency_stop_active"})
+	return result, warnings
+}
+
+// RateLimits charges the outcome's rate_limit and, for destructive intents,
+// its destructive_budget against store.
+func RateLimits(store gate.RateLimitStore, outcome gate.EvalOutcome, intent schemagate.IntentRequest, result schemagate.GateResult, now time.Time) (schemagate.GateResult, gate.RateLimitDecision, gate.RateLimitDecision, error) {
+	var rateDecision gate.RateLimitDecision
+	var destructiveBudgetDecision gate.RateLimitDecision
+	var err error
+	if outcome.RateLimit.Requests > 0 {
+		rateDecision, err = gate.EnforceRateLimitWithStore(store, outcome.RateLimit, intent, now)
+		if err != nil {
+			return result, rateDecision, destructiveBudgetDecision, err
+		}
+		if !rateDecision.Allowed {
+			result.Verdict = verdictBlock
+			result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{"rate_limit_exceeded"})
+			result.Violations = mergeUniqueSorted(result.Violations, []string{"rate_limit_exceeded"})
+		}
+	}
+	if outcome.DestructiveBudget.Requests > 0 && IntentContainsDestructiveTarget(intent) {
+		budgetIntent := intent
+		budgetIntent.ToolName = "destructive_budget|" + strings.TrimSpace(intent.ToolName)
+		destructiveBudgetDecision, err = gate.EnforceRateLimitWithStore(store, outcome.DestructiveBudget, budgetIntent, now)
+		if err != nil {
+			return result, rateDecision, destructiveBudgetDecision, err
+		}
+		if !destructiveBudgetDecision.Allowed {
+			result.Verdict = verdictBlock
+			result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{"destructive_budget_exceeded"})
+			result.Violations = mergeUniqueSorted(result.Violations, []string{"destructive_budget_exceeded"})
+		}
+	}
+	return result, rateDecision, destructiveBudgetDecision, nil
+}
+
+type JobChargeOptions struct {
+	PolicyDigest string
+	// Cost is the policy cost of the call, see gate.EvalOutcome.Cost.
+	Cost         float64
+	Now          time.Time
+	OnTransition func(jobruntime.Transition)
+}
+
+// JobConstraints charges an allowed call to the job named in context.job_id.
+// Intents that do not name a known job are left unchanged: there are no
+// constraints to enforce, and boundaries that require the job to exist run
+// EmergencyStop first. Any other failure to read or charge the job blocks the
+// call.
+func JobConstraints(jobRoot string, boundary Boundary, intent schemagate.IntentRequest, result schemagate.GateResult, opts JobChargeOptions) (schemagate.GateResult, []string) {
+	jobID := strings.TrimSpace(intent.Context.JobID)
+	if jobID == "" || result.Verdict != verdictAllow {
+		return result, nil
+	}
+	decision, err := jobruntime.ChargeToolCall(jobRoot, jobID, jobruntime.ToolCallCharge{
+		ToolName:     intent.ToolName,
+		PolicyDigest: opts.PolicyDigest,
+		Destructive:  IntentContainsDestructiveTarget(intent),
+		Cost:         opts.Cost,
+		Actor:        boundary.Actor,
+		Now:          opts.Now,
+		OnTransition: opts.OnTransition,
+	})
+	if errors.Is(err, jobruntime.ErrJobNotFound) {
+		return result, nil
+	}
+	if err != nil {
+		result.Verdict = verdictBlock
+		result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{ReasonJobConstraintsUnavailable})
+		result.Violations = mergeUniqueSorted(result.Violations, []string{ReasonJobConstraintsUnavailable})
+		return result, []string{fmt.Sprintf("job_constraints_unavailable=%v", err)}
+	}
+	if decision.Allowed {
+		return result, nil
+	}
+	result.Verdict = verdictBlock
+	result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, decision.ReasonCodes)
+	result.Violations = mergeUniqueSorted(result.Violations, []string{"job_constraint_violated"})
+	return result, nil
+}
+
+type BrokerOptions struct {
+	Broker credential.Broker
+	// Reference and Scopes default to the outcome's broker_reference and
+	// broker_scopes.
+	Reference string
+	Scopes    []string
+	// LedgerPath is resolved with credential.ResolveLedgerPath.
+	LedgerPath string
+	Now        time.Time
+}
+
+// BrokerCredential issues the broker credential required by the matched rules
+// of an allowed call and validates its receipt. The issued credential is
+// returned whenever the broker issued one, even if its receipt blocks the
+// call, so the trace can record it.
+func BrokerCredential(policy gate.Policy, outcome gate.EvalOutcome, intent schemagate.IntentRequest, result schemagate.GateResult, opts BrokerOptions) (schemagate.GateResult, *credential.Response, []string, error) {
+	if !outcome.RequireBrokerCredential || result.Verdict != verdictAllow {
+		return result, nil, nil, nil
+	}
+	if opts.Broker == nil {
+		result.Verdict = verdictBlock
+		result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{"broker_credential_required"})
+		result.Violations = mergeUniqueSorted(result.Violations, []string{"broker_credential_missing"})
+		return result, nil, nil, nil
+	}
+	targetBinding, err := gate.CredentialTargetBinding(intent)
+	if err != nil {
+		return result, nil, nil, err
+	}
+	reference := strings.TrimSpace(opts.Reference)
+	if reference == "" {
+		reference = outcome.BrokerReference
+	}
+	request := credential.Request{
+		ToolName:      intent.ToolName,
+		Identity:      intent.Context.Identity,
+		Workspace:     intent.Context.Workspace,
+		SessionID:     intent.Context.SessionID,
+		RequestID:     intent.Context.RequestID,
+		RunID:         intent.Context.RunID,
+		JobID:         intent.Context.JobID,
+		Reference:     reference,
+		Scope:         mergeUniqueSorted(outcome.BrokerScopes, opts.Scopes),
+		TargetBinding: targetBinding,
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	var warnings []string
+	if _, sweepErr := credential.SweepExpiredLeases(opts.LedgerPath, now); sweepErr != nil {
+		warnings = append(warnings, "credential lease ledger sweep failed: "+sweepErr.Error())
+	}
+	issued, err := credential.Issue(opts.Broker, request)
+	if err != nil {
+		result.Verdict = verdictBlock
+		result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{"broker_credential_missing"})
+		result.Violations = mergeUniqueSorted(result.Violations, []string{"broker_credential_missing"})
+		return result, nil, warnings, nil
+	}
+	if recordErr := credential.RecordLease(opts.LedgerPath, request, issued, now); recordErr != nil {
+		warnings = append(warnings, "credential lease ledger record failed: "+recordErr.Error())
+	}
+	reasons := []string{}
+	violations := []string{}
+	for _, rule := range MatchedPolicyRules(policy, outcome.MatchedRule) {
+		ruleReasons, ruleViolations := gate.ValidateBrokerCredentialReceipt(rule, request, issued, gate.IntentBrokerBinding{
+			ExpectedCredentialRef: strings.TrimSpace(intent.Context.CredentialRef),
+			TargetBinding:         targetBinding,
+			RunBinding:            strings.TrimSpace(intent.Context.RunID),
+			JobBinding:            strings.TrimSpace(intent.Context.JobID),
+		})
+		reasons = mergeUniqueSorted(reasons, ruleReasons)
+		violations = mergeUniqueSorted(violations, ruleViolations)
+	}
+	if len(reasons) > 0 {
+		result.Verdict = verdictBlock
+		result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, reasons)
+		result.Violations = mergeUniqueSorted(result.Violations, violations)
+		return result, &issued, warnings, nil
+	}
+	result.ReasonCodes = mergeUniqueSorted(result.ReasonCodes, []string{"broker_credential_present"})
+	return result, &issued, warnings, nil
+}
+
+// KillSwitchJournalPath keeps the journal next to local state, or next to the
+// cache for remote sources; remote sources without a cache do not journal.
+func KillSwitchJournalPath(source string, cachePath string) string {
+	if gate.IsRemoteKillSwitchSource(source) {
+		return gate.KillSwitchJournalPath(cachePath)
+	}
+	return gate.KillSwitchJournalPath(source)
+}
+
+// AppendKillSwitchJournal records a call blocked by an active kill switch.
+// It does nothing when the kill switch did not fire or journalPath is empty.
+func AppendKillSwitchJournal(journalPath string, boundary Boundary, intent schemagate.IntentRequest, outcome gate.EvalOutcome, result schemagate.GateResult, traceID string) error {
+	if outcome.KillSwitch == nil || outcome.KillSwitch.Status != "active" || strings.TrimSpace(journalPath) == "" {
+		return nil
+	}
+	return gate.AppendKillSwitchJournal(journalPath, gate.KillSwitchJournalRecord{
+		CreatedAt:       result.CreatedAt,
+		ProducerVersion: boundary.ProducerVersion,
+		Source:          boundary.JournalSource,
+		TraceID:         traceID,
+		JobID:           intent.Context.JobID,
+		ToolName:        intent.ToolName,
+		AgentID:         intent.Context.AgentID,
+		Identity:        intent.Context.Identity,
+		ReasonCodes:     outcome.KillSwitch.ReasonCodes,
+		MatchedEntryIDs: outcome.KillSwitch.MatchedEntryIDs,
+	})
+}
+
+// IntentContainsDestructiveTarget checks script step targets when any step
+// declares targets, and the top-level targets otherwise.
+func IntentContainsDestructiveTarget(intent schemagate.IntentRequest) bool {
+	if intent.Script != nil && len(intent.Script.Steps) > 0 {
+		sawScriptTargets := false
+		for _, step := range intent.Script.Steps {
+			if len(step.Targets) == 0 {
+				continue
+			}
+			sawScriptTargets = true
+			if gate.IntentContainsDestructiveTarget(step.Targets) {
+				return true
+			}
+		}
+		if sawScriptTargets {
+			return false
+		}
+	}
+	return gate.IntentContainsDestructiveTarget(intent.Targets)
+}
+
+// MatchedPolicyRules returns the policy rules named in a comma-separated
+// EvalOutcome.MatchedRule, in policy order.
+func MatchedPolicyRules(policy gate.Policy, matchedRule string) []gate.PolicyRule {
+	names := map[string]struct{}{}
+	for _, name := range strings.Split(matchedRule, ",") {
+		if trimmed := strings.TrimSpace(name); trimmed != "" {
+			names[trimmed] = struct{}{}
+		}
+	}
+	rules := make([]gate.PolicyRule, 0, len(names))
+	for _, rule := range policy.Rules {
+		if _, ok := names[rule.Name]; ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func mergeUniqueSorted(values []string, extra []string) []string {
+	seen := map[string]struct{}{}
+	merged := make([]string, 0, len(values)+len(extra))
+	for _, value := range append(append([]string{}, values...), extra...) {
+		trimmed := strings.TrimSpace(value)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		merged = append(merged, trimmed)
+	}
+	sort.Strings(merged)
+	return merged
+}
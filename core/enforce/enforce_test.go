@@ -0,0 +1,155 @@
+package enforce
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Clyra-AI/gait/core/jobruntime"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+)
+
+var testBoundary = Boundary{Actor: "test", DispatchPath: "test.dispatch", JournalSource: "test"}
+
+func allowResult() schemagate.GateResult {
+	return schemagate.GateResult{Verdict: verdictAllow, ReasonCodes: []string{"matched_rule_allow"}}
+}
+
+func intentForJob(jobID string) schemagate.IntentRequest {
+	return schemagate.IntentRequest{
+		ToolName: "tool.write",
+		Context:  schemagate.IntentContext{JobID: jobID},
+	}
+}
+
+func TestEmergencyStopWithoutJobID(t *testing.T) {
+	result, warnings := EmergencyStop("", testBoundary, intentForJob(""), allowResult())
+	if result.Verdict != verdictAllow {
+		t.Fatalf("expected allow without job_id, got %#v", result)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("expected no warnings without job_id, got %#v", warnings)
+	}
+}
+
+func TestEmergencyStopStateUnavailable(t *testing.T) {
+	result, warnings := EmergencyStop(filepath.Join(t.TempDir(), "jobs"), testBoundary, intentForJob("job_missing"), allowResult())
+	if result.Verdict != verdictBlock || !reflect.DeepEqual(result.ReasonCodes, []string{ReasonEmergencyStopStateUnavailable, "matched_rule_allow"}) {
+		t.Fatalf("expected emergency_stop_state_unavailable block, got %#v", result)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "job_status_unavailable=") {
+		t.Fatalf("expected job status warning, got %#v", warnings)
+	}
+}
+
+func TestEmergencyStopJobNotStopped(t *testing.T) {
+	jobsRoot := filepath.Join(t.TempDir(), "jobs")
+	if _, err := jobruntime.Submit(jobsRoot, jobruntime.SubmitOptions{JobID: "job_running"}); err != nil {
+		t.Fatalf("submit job: %v", err)
+	}
+	result, warnings := EmergencyStop(jobsRoot, testBoundary, intentForJob("job_running"), allowResult())
+	if result.Verdict != verdictAllow {
+		t.Fatalf("expected allow for non-stopped job, got %#v", result)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("expected no warnings for non-stopped job, got %#v", warnings)
+	}
+}
+
+func TestEmergencyStopBlocksAndRecordsDispatch(t *testing.T) {
+	jobsRoot := filepath.Join(t.TempDir(), "jobs")
+	if _, err := jobruntime.Submit(jobsRoot, jobruntime.SubmitOptions{JobID: "job_stopped"}); err != nil {
+		t.Fatalf("submit job: %v", err)
+	}
+	if _, err := jobruntime.EmergencyStop(jobsRoot, "job_stopped", jobruntime.TransitionOptions{Actor: "test"}); err != nil {
+		t.Fatalf("emergency stop: %v", err)
+	}
+	result, warnings := EmergencyStop(jobsRoot, testBoundary, intentForJob("job_stopped"), allowResult())
+	if result.Verdict != verdictBlock || !reflect.DeepEqual(result.Violations, []string{"emergency_stop_active"}) {
+		t.Fatalf("expected emergency stop block, got %#v", result)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("expected blocked dispatch to be recorded, got %#v", warnings)
+	}
+	_, events, err := jobruntime.Inspect(jobsRoot, "job_stopped")
+	if err != nil {
+		t.Fatalf("inspect job: %v", err)
+	}
+	last := events[len(events)-1]
+	if last.Type != "dispatch_blocked" || last.Actor != "test" || last.ReasonCode != ReasonEmergencyStopPreempted {
+		t.Fatalf("unexpected last event %#v", last)
+	}
+}
+
+func TestJobConstraintsSkipsUnknownJobs(t *testing.T) {
+	result, warnings := JobConstraints(filepath.Join(t.TempDir(), "jobs"), testBoundary, intentForJob("job_missing"), allowResult(), JobChargeOptions{})
+	if result.Verdict != verdictAllow || len(warnings) != 0 {
+		t.Fatalf("expected unknown job to pass through, got %#v %#v", result, warnings)
+	}
+}
+
+func TestJobConstraintsBlocksExhaustedBudget(t *testing.T) {
+	jobsRoot := filepath.Join(t.TempDir(), "jobs")
+	if _, err := jobruntime.Submit(jobsRoot, jobruntime.SubmitOptions{
+		JobID:       "job_budget",
+		Constraints: jobruntime.JobConstraints{MaxToolCalls: 1},
+	}); err != nil {
+		t.Fatalf("submit job: %v", err)
+	}
+	first, _ := JobConstraints(jobsRoot, testBoundary, intentForJob("job_budget"), allowResult(), JobChargeOptions{})
+	if first.Verdict != verdictAllow {
+		t.Fatalf("expected first call to be charged, got %#v", first)
+	}
+	second, _ := JobConstraints(jobsRoot, testBoundary, intentForJob("job_budget"), allowResult(), JobChargeOptions{})
+	if second.Verdict != verdictBlock || !reflect.DeepEqual(second.Violations, []string{"job_constraint_violated"}) {
+		t.Fatalf("expected exhausted budget to block, got %#v", second)
+	}
+}
+
+func TestIntentContainsDestructiveTargetUsesScriptTargets(t *testing.T) {
+	intent := schemagate.IntentRequest{
+		Script: &schemagate.IntentScript{
+			Steps: []schemagate.IntentScriptStep{
+				{
+					ToolName: "tool.delete",
+					Targets: []schemagate.IntentTarget{
+						{Kind: "path", Value: "/tmp/a", EndpointClass: "fs.delete", Destructive: true},
+					},
+				},
+			},
+		},
+	}
+
+	if !IntentContainsDestructiveTarget(intent) {
+		t.Fatalf("expected script targets to be considered for destructive budget enforcement")
+	}
+}
+
+func TestIntentContainsDestructiveTargetFallsBackToTopLevel(t *testing.T) {
+	intent := schemagate.IntentRequest{
+		Targets: []schemagate.IntentTarget{
+			{Kind: "path", Value: "/tmp/a", EndpointClass: "fs.delete", Destructive: true},
+		},
+		Script: &schemagate.IntentScript{
+			Steps: []schemagate.IntentScriptStep{
+				{ToolName: "tool.script"},
+			},
+		},
+	}
+
+	if !IntentContainsDestructiveTarget(intent) {
+		t.Fatalf("expected fallback to top-level targets when script targets are empty")
+	}
+}
+
+func TestKillSwitchJournalPathUsesCacheForRemoteSources(t *testing.T) {
+	if got := KillSwitchJournalPath("https://example.test/kill-switch.json", ""); got != "" {
+		t.Fatalf("expected remote source without cache not to journal, got %q", got)
+	}
+	remote := KillSwitchJournalPath("https://example.test/kill-switch.json", "/tmp/cache.json")
+	local := KillSwitchJournalPath("/tmp/cache.json", "")
+	if remote == "" || remote != local {
+		t.Fatalf("expected remote journal next to cache, got %q want %q", remote, local)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	"github.com/Clyra-AI/gait/core/trust"
 	sign "github.com/Clyra-AI/proof/signing"
 )
 
@@ -34,6 +35,7 @@ const (
 	ApprovalCodeScopeMismatch       = "approval_token_scope_mismatch"
 	ApprovalCodeTargetsExceeded     = "approval_token_max_targets_exceeded"
 	ApprovalCodeOpsExceeded         = "approval_token_max_ops_exceeded"
+	ApprovalCodeKeyUntrusted        = "approval_token_key_untrusted"
 )
 
 type MintApprovalTokenOptions struct {
@@ -65,6 +67,9 @@ type ApprovalValidationOptions struct {
 	RequiredScope                   []string
 	TargetCount                     int
 	OperationCount                  int
+	// TrustStore, when set, selects the verify key by signature key id
+	// instead of the publicKey argument.
+	TrustStore *trust.Store
 }
 
 type ApprovalTokenError struct {
@@ -90,41 +95,59 @@ func (e *ApprovalTokenError) Unwrap() error {
 }
 
 func MintApprovalToken(opts MintApprovalTokenOptions) (MintApprovalTokenResult, error) {
+	token, err := signApprovalToken(opts)
+	if err != nil {
+		return MintApprovalTokenResult{}, err
+	}
+	tokenPath := strings.TrimSpace(opts.TokenPath)
+	if tokenPath == "" {
+		tokenPath = fmt.Sprintf("approval_%s.json", token.TokenID)
+	}
+	if err := WriteApprovalToken(tokenPath, token); err != nil {
+		return MintApprovalTokenResult{}, err
+	}
+	return MintApprovalTokenResult{
+		Token:     token,
+		TokenPath: tokenPath,
+	}, nil
+}
+
+func signApprovalToken(opts MintApprovalTokenOptions) (schemagate.ApprovalToken, error) {
 	if len(opts.SigningPrivateKey) == 0 {
-		return MintApprovalTokenResult{}, fmt.Errorf("signing private key is required")
+		return schemagate.ApprovalToken{}, fmt.Errorf("signing private key is required")
 	}
 	if opts.TTL <= 0 {
-		return MintApprovalTokenResult{}, fmt.Errorf("ttl must be greater than 0")
+		return schemagate.ApprovalToken{}, fmt.Errorf("ttl must be greater than 0")
 	}
 	intentDigest := strings.ToLower(strings.TrimSpace(opts.IntentDigest))
 	if !isDigestHex(intentDigest) {
-		return MintApprovalTokenResult{}, fmt.Errorf("intent_digest must be sha256 hex")
+		return schemagate.ApprovalToken{}, fmt.Errorf("intent_digest must be sha256 hex")
 	}
 	policyDigest := strings.ToLower(strings.TrimSpace(opts.PolicyDigest))
 	if !isDigestHex(policyDigest) {
-		return MintApprovalTokenResult{}, fmt.Errorf("policy_digest must be sha256 hex")
+		return schemagate.ApprovalToken{}, fmt.Errorf("policy_digest must be sha256 hex")
 	}
 	delegationBindingDigest := strings.ToLower(strings.TrimSpace(opts.DelegationBindingDigest))
 	if delegationBindingDigest != "" && !isDigestHex(delegationBindingDigest) {
-		return MintApprovalTokenResult{}, fmt.Errorf("delegation_binding_digest must be sha256 hex when set")
+		return schemagate.ApprovalToken{}, fmt.Errorf("delegation_binding_digest must be sha256 hex when set")
 	}
 	approver := strings.TrimSpace(opts.ApproverIdentity)
 	if approver == "" {
-		return MintApprovalTokenResult{}, fmt.Errorf("approver identity is required")
+		return schemagate.ApprovalToken{}, fmt.Errorf("approver identity is required")
 	}
 	reasonCode := strings.TrimSpace(opts.ReasonCode)
 	if reasonCode == "" {
-		return MintApprovalTokenResult{}, fmt.Errorf("reason code is required")
+		return schemagate.ApprovalToken{}, fmt.Errorf("reason code is required")
 	}
 	scope := normalizeStringListLower(opts.Scope)
 	if len(scope) == 0 {
-		return MintApprovalTokenResult{}, fmt.Errorf("scope must include at least one value")
+		return schemagate.ApprovalToken{}, fmt.Errorf("scope must include at least one value")
 	}
 	if opts.MaxTargets < 0 {
-		return MintApprovalTokenResult{}, fmt.Errorf("max_targets must be >= 0")
+		return schemagate.ApprovalToken{}, fmt.Errorf("max_targets must be >= 0")
 	}
 	if opts.MaxOps < 0 {
-		return MintApprovalTokenResult{}, fmt.Errorf("max_ops must be >= 0")
+		return schemagate.ApprovalToken{}, fmt.Errorf("max_ops must be >= 0")
 	}
 
 	createdAt := opts.Now.UTC()
@@ -157,11 +180,11 @@ func MintApprovalToken(opts MintApprovalTokenOptions) (MintApprovalTokenResult,
 	signable.Signature = nil
 	signableRaw, err := json.Marshal(signable)
 	if err != nil {
-		return MintApprovalTokenResult{}, fmt.Errorf("marshal signable approval token: %w", err)
+		return schemagate.ApprovalToken{}, fmt.Errorf("marshal signable approval token: %w", err)
 	}
 	signature, err := sign.SignJSON(opts.SigningPrivateKey, signableRaw)
 	if err != nil {
-		return MintApprovalTokenResult{}, fmt.Errorf("sign approval token: %w", err)
+		return schemagate.ApprovalToken{}, fmt.Errorf("sign approval token: %w", err)
 	}
 	token.Signature = &schemagate.Signature{
 		Alg:          signature.Alg,
@@ -169,18 +192,7 @@ func MintApprovalToken(opts MintApprovalTokenOptions) (MintApprovalTokenResult,
 		Sig:          signature.Sig,
 		SignedDigest: signature.SignedDigest,
 	}
-
-	tokenPath := strings.TrimSpace(opts.TokenPath)
-	if tokenPath == "" {
-		tokenPath = fmt.Sprintf("approval_%s.json", token.TokenID)
-	}
-	if err := WriteApprovalToken(tokenPath, token); err != nil {
-		return MintApprovalTokenResult{}, err
-	}
-	return MintApprovalTokenResult{
-		Token:     token,
-		TokenPath: tokenPath,
-	}, nil
+	return token, nil
 }
 
 func WriteApprovalToken(path string, token schemagate.ApprovalToken) error {
@@ -219,12 +231,23 @@ func ValidateApprovalToken(token schemagate.ApprovalToken, publicKey ed25519.Pub
 	if err != nil {
 		return &ApprovalTokenError{Code: ApprovalCodeSchemaInvalid, Err: err}
 	}
-	if len(publicKey) == 0 {
+	if len(publicKey) == 0 && opts.TrustStore == nil {
 		return &ApprovalTokenError{Code: ApprovalCodeSignatureFailed, Err: fmt.Errorf("verification public key is required")}
 	}
 	if normalized.Signature == nil {
 		return &ApprovalTokenError{Code: ApprovalCodeSignatureMiss, Err: fmt.Errorf("signature missing")}
 	}
+	if opts.TrustStore != nil {
+		publicKey, err = opts.TrustStore.Resolve(trust.Lookup{
+			Role:     trust.RoleApprover,
+			KeyID:    normalized.Signature.KeyID,
+			Identity: normalized.ApproverIdentity,
+			SignedAt: normalized.CreatedAt,
+		})
+		if err != nil {
+			return &ApprovalTokenError{Code: ApprovalCodeKeyUntrusted, Err: err}
+		}
+	}
 
 	signable := normalized
 	signable.Signature = nil
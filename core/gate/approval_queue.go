@@ -0,0 +1,673 @@
+package gate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	coreerrors "github.com/Clyra-AI/gait/core/errors"
+	"github.com/Clyra-AI/gait/core/fsx"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+const (
+	approvalRequestSchemaID  = "gait.gate.approval_request"
+	approvalRequestSchemaV1  = "1.0.0"
+	approvalDecisionSchemaID = "gait.gate.approval_decision"
+	approvalDecisionSchemaV1 = "1.0.0"
+
+	ApprovalRequestStatusPending = "pending"
+	ApprovalRequestStatusGranted = "granted"
+	ApprovalRequestStatusDenied  = "denied"
+	ApprovalRequestStatusExpired = "expired"
+
+	ApprovalDecisionGrant = "grant"
+	ApprovalDecisionDeny  = "deny"
+
+	ApprovalReasonRequestDenied = "approval_request_denied"
+
+	approvalRequestFile       = "request.json"
+	approvalDecisionsFile     = "decisions.jsonl"
+	approvalQueueAuditFile    = "approval_audit.json"
+	approvalQueueTokensDir    = "tokens"
+	approvalDecideLockFile    = "decide.lock"
+	defaultApprovalRequestTTL = 24 * time.Hour
+	defaultApprovalPollPeriod = 500 * time.Millisecond
+	approvalDecideLockTimeout = 5 * time.Second
+	approvalDecideLockRetry   = 10 * time.Millisecond
+	approvalDecideLockStale   = time.Minute
+)
+
+type EnqueueApprovalRequestOptions struct {
+	ProducerVersion          string
+	Now                      time.Time
+	TTL                      time.Duration
+	TraceID                  string
+	ToolName                 string
+	Identity                 string
+	IntentDigest             string
+	PolicyDigest             string
+	DelegationBindingDigest  string
+	RequiredScope            []string
+	MinApprovals             int
+	RequireDistinctApprovers bool
+	MatchedRule              string
+	ReasonCodes              []string
+	SigningPrivateKey        ed25519.PrivateKey
+}
+
+type DecideApprovalRequestOptions struct {
+	ProducerVersion   string
+	Now               time.Time
+	RequestID         string
+	Decision          string
+	ApproverIdentity  string
+	ReasonCode        string
+	TTL               time.Duration
+	SigningPrivateKey ed25519.PrivateKey
+	// VerifyPublicKey checks the request signature before a decision is
+	// recorded. It defaults to the public half of SigningPrivateKey.
+	VerifyPublicKey ed25519.PublicKey
+}
+
+// ApprovalRequestState is a queued approval request together with the
+// decisions recorded against it and the status derived from them.
+type ApprovalRequestState struct {
+	Request    schemagate.ApprovalRequest    `json:"request"`
+	Status     string                        `json:"status"`
+	Decisions  []schemagate.ApprovalDecision `json:"decisions,omitempty"`
+	TokenPaths []string                      `json:"token_paths,omitempty"`
+	Path       string                        `json:"path"`
+	AuditPath  string                        `json:"audit_path,omitempty"`
+}
+
+// EnqueueApprovalRequest writes a signed approval request to the queue. An
+// unexpired request for the same digests and scope is returned instead of a new
+// one; the boolean reports whether a request was created.
+func EnqueueApprovalRequest(queueDir string, opts EnqueueApprovalRequestOptions) (ApprovalRequestState, bool, error) {
+	queueDir = strings.TrimSpace(queueDir)
+	if queueDir == "" {
+		return ApprovalRequestState{}, false, fmt.Errorf("approval queue directory is required")
+	}
+	if len(opts.SigningPrivateKey) == 0 {
+		return ApprovalRequestState{}, false, fmt.Errorf("signing private key is required")
+	}
+	intentDigest := strings.ToLower(strings.TrimSpace(opts.IntentDigest))
+	if !isDigestHex(intentDigest) {
+		return ApprovalRequestState{}, false, fmt.Errorf("intent_digest must be sha256 hex")
+	}
+	policyDigest := strings.ToLower(strings.TrimSpace(opts.PolicyDigest))
+	if !isDigestHex(policyDigest) {
+		return ApprovalRequestState{}, false, fmt.Errorf("policy_digest must be sha256 hex")
+	}
+	delegationBindingDigest := strings.ToLower(strings.TrimSpace(opts.DelegationBindingDigest))
+	if delegationBindingDigest != "" && !isDigestHex(delegationBindingDigest) {
+		return ApprovalRequestState{}, false, fmt.Errorf("delegation_binding_digest must be sha256 hex when set")
+	}
+	toolName := strings.TrimSpace(opts.ToolName)
+	if toolName == "" {
+		return ApprovalRequestState{}, false, fmt.Errorf("tool name is required")
+	}
+	scope := normalizeStringListLower(opts.RequiredScope)
+	if len(scope) == 0 {
+		return ApprovalRequestState{}, false, fmt.Errorf("required scope must include at least one value")
+	}
+	minApprovals := opts.MinApprovals
+	if minApprovals <= 0 {
+		minApprovals = 1
+	}
+	ttl := opts.TTL
+	if ttl <= 0 {
+		ttl = defaultApprovalRequestTTL
+	}
+	now := opts.Now.UTC()
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+
+	existing, err := ListApprovalRequests(queueDir, now)
+	if err != nil {
+		return ApprovalRequestState{}, false, err
+	}
+	for _, state := range existing {
+		request := state.Request
+		if state.Status == ApprovalRequestStatusExpired ||
+			request.IntentDigest != intentDigest ||
+			request.PolicyDigest != policyDigest ||
+			request.DelegationBindingDigest != delegationBindingDigest ||
+			strings.Join(request.RequiredScope, ",") != strings.Join(scope, ",") {
+			continue
+		}
+		return state, false, nil
+	}
+
+	producerVersion := strings.TrimSpace(opts.ProducerVersion)
+	if producerVersion == "" {
+		producerVersion = "0.0.0-dev"
+	}
+	request := schemagate.ApprovalRequest{
+		SchemaID:                 approvalRequestSchemaID,
+		SchemaVersion:            approvalRequestSchemaV1,
+		CreatedAt:                now,
+		ProducerVersion:          producerVersion,
+		TraceID:                  strings.TrimSpace(opts.TraceID),
+		ToolName:                 toolName,
+		Identity:                 strings.TrimSpace(opts.Identity),
+		IntentDigest:             intentDigest,
+		PolicyDigest:             policyDigest,
+		DelegationBindingDigest:  delegationBindingDigest,
+		RequiredScope:            scope,
+		MinApprovals:             minApprovals,
+		RequireDistinctApprovers: opts.RequireDistinctApprovers,
+		MatchedRule:              strings.TrimSpace(opts.MatchedRule),
+		ReasonCodes:              uniqueSorted(opts.ReasonCodes),
+		ExpiresAt:                now.Add(ttl),
+	}
+	request.RequestID = computeApprovalRequestID(request)
+	signableRaw, err := approvalRequestSignable(request)
+	if err != nil {
+		return ApprovalRequestState{}, false, err
+	}
+	signature, err := sign.SignJSON(opts.SigningPrivateKey, signableRaw)
+	if err != nil {
+		return ApprovalRequestState{}, false, fmt.Errorf("sign approval request: %w", err)
+	}
+	request.Signature = &schemagate.Signature{
+		Alg:          signature.Alg,
+		KeyID:        signature.KeyID,
+		Sig:          signature.Sig,
+		SignedDigest: signature.SignedDigest,
+	}
+
+	requestDir := filepath.Join(queueDir, request.RequestID)
+	if err := os.MkdirAll(requestDir, 0o750); err != nil {
+		return ApprovalRequestState{}, false, coreerrors.Wrap(
+			fmt.Errorf("create approval request directory: %w", err),
+			coreerrors.CategoryIOFailure,
+			"approval_queue_write_failed",
+			"check write permissions for the approval queue directory",
+			false,
+		)
+	}
+	encoded, err := json.MarshalIndent(request, "", "  ")
+	if err != nil {
+		return ApprovalRequestState{}, false, fmt.Errorf("marshal approval request: %w", err)
+	}
+	encoded = append(encoded, '\n')
+	if err := fsx.WriteFileAtomic(filepath.Join(requestDir, approvalRequestFile), encoded, 0o600); err != nil {
+		return ApprovalRequestState{}, false, coreerrors.Wrap(
+			fmt.Errorf("write approval request: %w", err),
+			coreerrors.CategoryIOFailure,
+			"approval_queue_write_failed",
+			"check write permissions for the approval queue directory",
+			false,
+		)
+	}
+	state, err := LoadApprovalRequest(queueDir, request.RequestID, now)
+	if err != nil {
+		return ApprovalRequestState{}, false, err
+	}
+	if err := writeApprovalQueueAudit(&state, now); err != nil {
+		return ApprovalRequestState{}, false, err
+	}
+	return state, true, nil
+}
+
+func ListApprovalRequests(queueDir string, now time.Time) ([]ApprovalRequestState, error) {
+	entries, err := os.ReadDir(strings.TrimSpace(queueDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []ApprovalRequestState{}, nil
+		}
+		return nil, fmt.Errorf("read approval queue: %w", err)
+	}
+	states := make([]ApprovalRequestState, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || !isApprovalRequestID(entry.Name()) {
+			continue
+		}
+		state, err := LoadApprovalRequest(queueDir, entry.Name(), now)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if !states[i].Request.CreatedAt.Equal(states[j].Request.CreatedAt) {
+			return states[i].Request.CreatedAt.Before(states[j].Request.CreatedAt)
+		}
+		return states[i].Request.RequestID < states[j].Request.RequestID
+	})
+	return states, nil
+}
+
+func LoadApprovalRequest(queueDir string, requestID string, now time.Time) (ApprovalRequestState, error) {
+	requestID = strings.ToLower(strings.TrimSpace(requestID))
+	if !isApprovalRequestID(requestID) {
+		return ApprovalRequestState{}, coreerrors.Wrap(
+			fmt.Errorf("invalid approval request id %q", requestID),
+			coreerrors.CategoryInvalidInput,
+			"approval_request_id_invalid",
+			"use the request_id reported by gait approve list",
+			false,
+		)
+	}
+	requestDir := filepath.Join(strings.TrimSpace(queueDir), requestID)
+	requestPath := filepath.Join(requestDir, approvalRequestFile)
+	// #nosec G304 -- request path is derived from the configured queue directory and a validated hex id.
+	content, err := os.ReadFile(requestPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ApprovalRequestState{}, coreerrors.Wrap(
+				fmt.Errorf("approval request %s not found", requestID),
+				coreerrors.CategoryInvalidInput,
+				"approval_request_not_found",
+				"use the request_id reported by gait approve list",
+				false,
+			)
+		}
+		return ApprovalRequestState{}, fmt.Errorf("read approval request: %w", err)
+	}
+	var request schemagate.ApprovalRequest
+	if err := json.Unmarshal(content, &request); err != nil {
+		return ApprovalRequestState{}, fmt.Errorf("parse approval request: %w", err)
+	}
+	if request.SchemaID != approvalRequestSchemaID || request.RequestID != requestID {
+		return ApprovalRequestState{}, fmt.Errorf("approval request %s is malformed", requestID)
+	}
+	decisions, err := readApprovalDecisions(filepath.Join(requestDir, approvalDecisionsFile))
+	if err != nil {
+		return ApprovalRequestState{}, err
+	}
+	state := ApprovalRequestState{
+		Request:   request,
+		Decisions: decisions,
+		Path:      requestPath,
+	}
+	auditPath := filepath.Join(requestDir, approvalQueueAuditFile)
+	if _, err := os.Stat(auditPath); err == nil {
+		state.AuditPath = auditPath
+	}
+	resolveApprovalRequestStatus(&state, now)
+	return state, nil
+}
+
+// DecideApprovalRequest records a grant or deny decision on a request whose
+// signature verifies against the approval key. A grant mints an approval token
+// bound to the request digests and scope into the request's token directory.
+func DecideApprovalRequest(queueDir string, opts DecideApprovalRequestOptions) (ApprovalRequestState, error) {
+	now := opts.Now.UTC()
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	decision := strings.ToLower(strings.TrimSpace(opts.Decision))
+	if decision != ApprovalDecisionGrant && decision != ApprovalDecisionDeny {
+		return ApprovalRequestState{}, fmt.Errorf("decision must be grant or deny")
+	}
+	approver := strings.TrimSpace(opts.ApproverIdentity)
+	if approver == "" {
+		return ApprovalRequestState{}, fmt.Errorf("approver identity is required")
+	}
+	reasonCode := strings.TrimSpace(opts.ReasonCode)
+	if reasonCode == "" {
+		return ApprovalRequestState{}, fmt.Errorf("reason code is required")
+	}
+	verifyKey := opts.VerifyPublicKey
+	if len(verifyKey) == 0 && len(opts.SigningPrivateKey) == ed25519.PrivateKeySize {
+		verifyKey = opts.SigningPrivateKey.Public().(ed25519.PublicKey)
+	}
+	if len(verifyKey) == 0 {
+		return ApprovalRequestState{}, fmt.Errorf("request verify key is required")
+	}
+	state, err := LoadApprovalRequest(queueDir, opts.RequestID, now)
+	if err != nil {
+		return ApprovalRequestState{}, err
+	}
+	var decided ApprovalRequestState
+	err = withApprovalDecideLock(filepath.Dir(state.Path), func() error {
+		var decideErr error
+		decided, decideErr = decideApprovalRequestLocked(queueDir, state.Request.RequestID, opts, now, decision, approver, reasonCode, verifyKey)
+		return decideErr
+	})
+	if err != nil {
+		return ApprovalRequestState{}, err
+	}
+	return decided, nil
+}
+
+// decideApprovalRequestLocked runs with the request's decide lock held so the
+// pending and distinct-approver checks cannot race the decision append.
+func decideApprovalRequestLocked(queueDir string, requestID string, opts DecideApprovalRequestOptions, now time.Time, decision string, approver string, reasonCode string, verifyKey ed25519.PublicKey) (ApprovalRequestState, error) {
+	state, err := LoadApprovalRequest(queueDir, requestID, now)
+	if err != nil {
+		return ApprovalRequestState{}, err
+	}
+	if err := VerifyApprovalRequest(state.Request, verifyKey); err != nil {
+		return ApprovalRequestState{}, coreerrors.Wrap(
+			err,
+			coreerrors.CategoryVerification,
+			"approval_request_signature_invalid",
+			"decide only requests signed by the configured approval key",
+			false,
+		)
+	}
+	if state.Status != ApprovalRequestStatusPending {
+		return ApprovalRequestState{}, coreerrors.Wrap(
+			fmt.Errorf("approval request %s is %s", state.Request.RequestID, state.Status),
+			coreerrors.CategoryInvalidInput,
+			"approval_request_not_pending",
+			"only pending approval requests can be granted or denied",
+			false,
+		)
+	}
+	producerVersion := strings.TrimSpace(opts.ProducerVersion)
+	if producerVersion == "" {
+		producerVersion = "0.0.0-dev"
+	}
+	record := schemagate.ApprovalDecision{
+		SchemaID:         approvalDecisionSchemaID,
+		SchemaVersion:    approvalDecisionSchemaV1,
+		CreatedAt:        now,
+		ProducerVersion:  producerVersion,
+		RequestID:        state.Request.RequestID,
+		Decision:         decision,
+		ApproverIdentity: approver,
+		ReasonCode:       reasonCode,
+	}
+	requestDir := filepath.Dir(state.Path)
+	if decision == ApprovalDecisionGrant {
+		if state.Request.RequireDistinctApprovers {
+			for _, existing := range countedApprovalGrants(state, now) {
+				if existing.ApproverIdentity == approver {
+					return ApprovalRequestState{}, coreerrors.Wrap(
+						fmt.Errorf("approver %s already granted request %s", approver, state.Request.RequestID),
+						coreerrors.CategoryInvalidInput,
+						ApprovalReasonDistinctApprovers,
+						"this request requires grants from distinct approvers",
+						false,
+					)
+				}
+			}
+		}
+		ttl := opts.TTL
+		if ttl <= 0 || now.Add(ttl).After(state.Request.ExpiresAt) {
+			ttl = state.Request.ExpiresAt.Sub(now)
+		}
+		token, err := signApprovalToken(MintApprovalTokenOptions{
+			ProducerVersion:         producerVersion,
+			ApproverIdentity:        approver,
+			ReasonCode:              reasonCode,
+			IntentDigest:            state.Request.IntentDigest,
+			PolicyDigest:            state.Request.PolicyDigest,
+			DelegationBindingDigest: state.Request.DelegationBindingDigest,
+			Scope:                   state.Request.RequiredScope,
+			TTL:                     ttl,
+			Now:                     now,
+			SigningPrivateKey:       opts.SigningPrivateKey,
+		})
+		if err != nil {
+			return ApprovalRequestState{}, err
+		}
+		if err := WriteApprovalToken(approvalQueueTokenPath(requestDir, token.TokenID), token); err != nil {
+			return ApprovalRequestState{}, err
+		}
+		record.TokenID = token.TokenID
+		record.TokenExpiresAt = token.ExpiresAt
+	}
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return ApprovalRequestState{}, fmt.Errorf("marshal approval decision: %w", err)
+	}
+	if err := fsx.AppendLineLocked(filepath.Join(requestDir, approvalDecisionsFile), encoded, 0o600); err != nil {
+		return ApprovalRequestState{}, coreerrors.Wrap(
+			fmt.Errorf("append approval decision: %w", err),
+			coreerrors.CategoryIOFailure,
+			"approval_queue_write_failed",
+			"check write permissions for the approval queue directory",
+			false,
+		)
+	}
+	state, err = LoadApprovalRequest(queueDir, state.Request.RequestID, now)
+	if err != nil {
+		return ApprovalRequestState{}, err
+	}
+	if err := writeApprovalQueueAudit(&state, now); err != nil {
+		return ApprovalRequestState{}, err
+	}
+	return state, nil
+}
+
+// withApprovalDecideLock serializes decisions on one request across
+// processes. A lock left behind by a crashed process is reclaimed once stale.
+func withApprovalDecideLock(requestDir string, fn func() error) error {
+	lockPath := filepath.Join(requestDir, approvalDecideLockFile)
+	deadline := time.Now().Add(approvalDecideLockTimeout)
+	for {
+		// #nosec G304 -- lock path is derived from the configured queue directory and a validated request id.
+		lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = lockFile.Close()
+			defer func() {
+				_ = os.Remove(lockPath)
+			}()
+			return fn()
+		}
+		if !os.IsExist(err) && !isWindowsAccessDeniedLockError(err) {
+			return coreerrors.Wrap(
+				fmt.Errorf("acquire approval decision lock: %w", err),
+				coreerrors.CategoryIOFailure,
+				"approval_queue_lock_failed",
+				"check write permissions for the approval queue directory",
+				false,
+			)
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > approvalDecideLockStale {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return coreerrors.Wrap(
+				fmt.Errorf("acquire approval decision lock: timeout"),
+				coreerrors.CategoryStateContention,
+				"approval_queue_lock_timeout",
+				"retry after the concurrent decision completes",
+				true,
+			)
+		}
+		time.Sleep(approvalDecideLockRetry)
+	}
+}
+
+// WaitApprovalRequest polls a request until it leaves the pending status or
+// the context is done. On context expiry the last observed state is returned
+// without an error so callers can report that the request is still pending.
+func WaitApprovalRequest(ctx context.Context, queueDir string, requestID string, pollInterval time.Duration) (ApprovalRequestState, error) {
+	if pollInterval <= 0 {
+		pollInterval = defaultApprovalPollPeriod
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		state, err := LoadApprovalRequest(queueDir, requestID, time.Now().UTC())
+		if err != nil || state.Status != ApprovalRequestStatusPending {
+			return state, err
+		}
+		select {
+		case <-ctx.Done():
+			return state, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func VerifyApprovalRequest(request schemagate.ApprovalRequest, publicKey ed25519.PublicKey) error {
+	if len(publicKey) == 0 {
+		return fmt.Errorf("verification public key is required")
+	}
+	if request.Signature == nil {
+		return fmt.Errorf("approval request signature missing")
+	}
+	signableRaw, err := approvalRequestSignable(request)
+	if err != nil {
+		return err
+	}
+	ok, err := sign.VerifyJSON(publicKey, sign.Signature{
+		Alg:          request.Signature.Alg,
+		KeyID:        request.Signature.KeyID,
+		Sig:          request.Signature.Sig,
+		SignedDigest: request.Signature.SignedDigest,
+	}, signableRaw)
+	if err != nil {
+		return fmt.Errorf("verify approval request: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("approval request signature verification failed")
+	}
+	return nil
+}
+
+func approvalRequestSignable(request schemagate.ApprovalRequest) ([]byte, error) {
+	request.Signature = nil
+	raw, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("marshal signable approval request: %w", err)
+	}
+	return raw, nil
+}
+
+func resolveApprovalRequestStatus(state *ApprovalRequestState, now time.Time) {
+	state.Status = ApprovalRequestStatusPending
+	state.TokenPaths = nil
+	requestDir := filepath.Dir(state.Path)
+	for _, decision := range state.Decisions {
+		if decision.Decision == ApprovalDecisionDeny {
+			state.Status = ApprovalRequestStatusDenied
+			return
+		}
+	}
+	grants := countedApprovalGrants(*state, now)
+	approvers := map[string]struct{}{}
+	for _, grant := range grants {
+		approvers[grant.ApproverIdentity] = struct{}{}
+		state.TokenPaths = append(state.TokenPaths, approvalQueueTokenPath(requestDir, grant.TokenID))
+	}
+	satisfied := len(grants) >= state.Request.MinApprovals
+	if state.Request.RequireDistinctApprovers && len(approvers) < state.Request.MinApprovals {
+		satisfied = false
+	}
+	switch {
+	case satisfied:
+		state.Status = ApprovalRequestStatusGranted
+	case !now.Before(state.Request.ExpiresAt):
+		state.Status = ApprovalRequestStatusExpired
+	}
+}
+
+func countedApprovalGrants(state ApprovalRequestState, now time.Time) []schemagate.ApprovalDecision {
+	grants := make([]schemagate.ApprovalDecision, 0, len(state.Decisions))
+	for _, decision := range state.Decisions {
+		if decision.Decision != ApprovalDecisionGrant || decision.TokenID == "" || !now.Before(decision.TokenExpiresAt) {
+			continue
+		}
+		grants = append(grants, decision)
+	}
+	return grants
+}
+
+func readApprovalDecisions(path string) ([]schemagate.ApprovalDecision, error) {
+	// #nosec G304 -- decisions path is derived from the configured queue directory and a validated hex id.
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read approval decisions: %w", err)
+	}
+	decisions := []schemagate.ApprovalDecision{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var decision schemagate.ApprovalDecision
+		if err := json.Unmarshal(line, &decision); err != nil {
+			return nil, fmt.Errorf("parse approval decision: %w", err)
+		}
+		decisions = append(decisions, decision)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read approval decisions: %w", err)
+	}
+	return decisions, nil
+}
+
+func writeApprovalQueueAudit(state *ApprovalRequestState, now time.Time) error {
+	entries := make([]schemagate.ApprovalAuditEntry, 0, len(state.Decisions))
+	for _, decision := range state.Decisions {
+		entry := schemagate.ApprovalAuditEntry{
+			TokenID:          decision.TokenID,
+			ApproverIdentity: decision.ApproverIdentity,
+			ReasonCode:       decision.ReasonCode,
+			Scope:            state.Request.RequiredScope,
+			ExpiresAt:        decision.TokenExpiresAt,
+		}
+		switch {
+		case decision.Decision == ApprovalDecisionDeny:
+			entry.ErrorCode = ApprovalReasonRequestDenied
+		case !now.Before(decision.TokenExpiresAt):
+			entry.ErrorCode = ApprovalCodeExpired
+		default:
+			entry.Valid = true
+		}
+		entries = append(entries, entry)
+	}
+	record := BuildApprovalAuditRecord(BuildApprovalAuditOptions{
+		CreatedAt:         now,
+		ProducerVersion:   state.Request.ProducerVersion,
+		TraceID:           state.Request.TraceID,
+		ToolName:          state.Request.ToolName,
+		IntentDigest:      state.Request.IntentDigest,
+		PolicyDigest:      state.Request.PolicyDigest,
+		RequiredApprovals: state.Request.MinApprovals,
+		Entries:           entries,
+	})
+	auditPath := filepath.Join(filepath.Dir(state.Path), approvalQueueAuditFile)
+	if err := WriteApprovalAuditRecord(auditPath, record); err != nil {
+		return err
+	}
+	state.AuditPath = auditPath
+	return nil
+}
+
+func approvalQueueTokenPath(requestDir string, tokenID string) string {
+	return filepath.Join(requestDir, approvalQueueTokensDir, fmt.Sprintf("approval_%s.json", tokenID))
+}
+
+func computeApprovalRequestID(request schemagate.ApprovalRequest) string {
+	raw := request.IntentDigest + ":" + request.PolicyDigest + ":" + request.DelegationBindingDigest + ":" +
+		strings.Join(request.RequiredScope, ",") + fmt.Sprintf(":%d:", request.MinApprovals) + request.CreatedAt.UTC().Format(time.RFC3339Nano)
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:12])
+}
+
+func isApprovalRequestID(value string) bool {
+	if len(value) != 24 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil && strings.ToLower(value) == value
+}
@@ -0,0 +1,297 @@
+package gate
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+func TestApprovalQueueGrantFlow(t *testing.T) {
+	keyPair, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	queueDir := filepath.Join(t.TempDir(), "approvals")
+	now := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	opts := EnqueueApprovalRequestOptions{
+		ProducerVersion:          "test",
+		Now:                      now,
+		TTL:                      time.Hour,
+		TraceID:                  "trace_1",
+		ToolName:                 "tool.write",
+		Identity:                 "agent-a",
+		IntentDigest:             strings.Repeat("1", 64),
+		PolicyDigest:             strings.Repeat("2", 64),
+		RequiredScope:            []string{"tool:tool.write"},
+		MinApprovals:             2,
+		RequireDistinctApprovers: true,
+		MatchedRule:              "approve-writes",
+		SigningPrivateKey:        keyPair.Private,
+	}
+	state, created, err := EnqueueApprovalRequest(queueDir, opts)
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if !created || state.Status != ApprovalRequestStatusPending || state.AuditPath == "" {
+		t.Fatalf("unexpected enqueue state: created=%v %#v", created, state)
+	}
+	if err := VerifyApprovalRequest(state.Request, keyPair.Public); err != nil {
+		t.Fatalf("verify request: %v", err)
+	}
+	tampered := state.Request
+	tampered.MinApprovals = 1
+	if err := VerifyApprovalRequest(tampered, keyPair.Public); err == nil {
+		t.Fatalf("expected tampered request to fail verification")
+	}
+
+	opts.Now = now.Add(time.Minute)
+	again, created, err := EnqueueApprovalRequest(queueDir, opts)
+	if err != nil {
+		t.Fatalf("re-enqueue: %v", err)
+	}
+	if created || again.Request.RequestID != state.Request.RequestID {
+		t.Fatalf("expected pending request to be reused, got %s created=%v", again.Request.RequestID, created)
+	}
+
+	requestID := state.Request.RequestID
+	grant := DecideApprovalRequestOptions{
+		ProducerVersion:   "test",
+		Now:               now.Add(2 * time.Minute),
+		RequestID:         requestID,
+		Decision:          ApprovalDecisionGrant,
+		ApproverIdentity:  "alice",
+		ReasonCode:        "change_window",
+		TTL:               10 * time.Minute,
+		SigningPrivateKey: keyPair.Private,
+	}
+	state, err = DecideApprovalRequest(queueDir, grant)
+	if err != nil {
+		t.Fatalf("first grant: %v", err)
+	}
+	if state.Status != ApprovalRequestStatusPending || len(state.TokenPaths) != 1 {
+		t.Fatalf("expected pending after one of two grants: %#v", state)
+	}
+	if _, err := DecideApprovalRequest(queueDir, grant); err == nil || !strings.Contains(err.Error(), "already granted") {
+		t.Fatalf("expected distinct approver error, got %v", err)
+	}
+	grant.ApproverIdentity = "bob"
+	state, err = DecideApprovalRequest(queueDir, grant)
+	if err != nil {
+		t.Fatalf("second grant: %v", err)
+	}
+	if state.Status != ApprovalRequestStatusGranted || len(state.TokenPaths) != 2 {
+		t.Fatalf("expected granted request with two tokens: %#v", state)
+	}
+	for _, tokenPath := range state.TokenPaths {
+		token, err := ReadApprovalToken(tokenPath)
+		if err != nil {
+			t.Fatalf("read token: %v", err)
+		}
+		if err := ValidateApprovalToken(token, keyPair.Public, ApprovalValidationOptions{
+			Now:                  now.Add(3 * time.Minute),
+			ExpectedIntentDigest: opts.IntentDigest,
+			ExpectedPolicyDigest: opts.PolicyDigest,
+			RequiredScope:        opts.RequiredScope,
+		}); err != nil {
+			t.Fatalf("validate queued token: %v", err)
+		}
+	}
+	grant.ApproverIdentity = "carol"
+	if _, err := DecideApprovalRequest(queueDir, grant); err == nil || !strings.Contains(err.Error(), "is granted") {
+		t.Fatalf("expected not pending error, got %v", err)
+	}
+
+	auditRaw, err := os.ReadFile(state.AuditPath)
+	if err != nil {
+		t.Fatalf("read audit: %v", err)
+	}
+	var audit schemagate.ApprovalAuditRecord
+	if err := json.Unmarshal(auditRaw, &audit); err != nil {
+		t.Fatalf("parse audit: %v", err)
+	}
+	if !audit.Approved || audit.ValidApprovals != 2 || audit.RequiredApprovals != 2 || audit.TraceID != "trace_1" {
+		t.Fatalf("unexpected audit record: %#v", audit)
+	}
+
+	expired, err := LoadApprovalRequest(queueDir, requestID, now.Add(20*time.Minute))
+	if err != nil {
+		t.Fatalf("load after token expiry: %v", err)
+	}
+	if expired.Status != ApprovalRequestStatusPending || len(expired.TokenPaths) != 0 {
+		t.Fatalf("expected expired grants to stop counting: %#v", expired)
+	}
+	expired, err = LoadApprovalRequest(queueDir, requestID, now.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("load after request expiry: %v", err)
+	}
+	if expired.Status != ApprovalRequestStatusExpired {
+		t.Fatalf("expected expired status, got %s", expired.Status)
+	}
+}
+
+func TestApprovalQueueDenyListAndWait(t *testing.T) {
+	keyPair, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	queueDir := t.TempDir()
+	states, err := ListApprovalRequests(filepath.Join(queueDir, "missing"), time.Now())
+	if err != nil || len(states) != 0 {
+		t.Fatalf("expected empty list for missing queue, got %#v err=%v", states, err)
+	}
+	state, _, err := EnqueueApprovalRequest(queueDir, EnqueueApprovalRequestOptions{
+		ToolName:          "tool.delete",
+		IntentDigest:      strings.Repeat("a", 64),
+		PolicyDigest:      strings.Repeat("b", 64),
+		RequiredScope:     []string{"tool:tool.delete", "destructive:apply"},
+		SigningPrivateKey: keyPair.Private,
+	})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	waited, err := WaitApprovalRequest(ctx, queueDir, state.Request.RequestID, 10*time.Millisecond)
+	if err != nil || waited.Status != ApprovalRequestStatusPending {
+		t.Fatalf("expected wait timeout to report pending, got %s err=%v", waited.Status, err)
+	}
+
+	decided := make(chan struct{})
+	go func() {
+		defer close(decided)
+		time.Sleep(30 * time.Millisecond)
+		_, _ = DecideApprovalRequest(queueDir, DecideApprovalRequestOptions{
+			RequestID:        state.Request.RequestID,
+			Decision:         ApprovalDecisionDeny,
+			ApproverIdentity: "alice",
+			ReasonCode:       "not_in_window",
+			VerifyPublicKey:  keyPair.Public,
+		})
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	waited, err = WaitApprovalRequest(ctx, queueDir, state.Request.RequestID, 10*time.Millisecond)
+	<-decided
+	if err != nil || waited.Status != ApprovalRequestStatusDenied {
+		t.Fatalf("expected denied after wait, got %s err=%v", waited.Status, err)
+	}
+
+	states, err = ListApprovalRequests(queueDir, time.Now())
+	if err != nil || len(states) != 1 || states[0].Status != ApprovalRequestStatusDenied {
+		t.Fatalf("unexpected list: %#v err=%v", states, err)
+	}
+	if _, err := LoadApprovalRequest(queueDir, "../escape", time.Now()); err == nil || !strings.Contains(err.Error(), "invalid approval request id") {
+		t.Fatalf("expected invalid id error, got %v", err)
+	}
+	if _, err := DecideApprovalRequest(queueDir, DecideApprovalRequestOptions{RequestID: state.Request.RequestID, Decision: "maybe", ApproverIdentity: "a", ReasonCode: "r"}); err == nil {
+		t.Fatalf("expected invalid decision error")
+	}
+}
+
+func TestDecideApprovalRequestVerifiesSignatureAndSerializesGrants(t *testing.T) {
+	keyPair, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	otherKeyPair, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate other key pair: %v", err)
+	}
+	queueDir := t.TempDir()
+	state, _, err := EnqueueApprovalRequest(queueDir, EnqueueApprovalRequestOptions{
+		ToolName:                 "tool.write",
+		IntentDigest:             strings.Repeat("3", 64),
+		PolicyDigest:             strings.Repeat("4", 64),
+		RequiredScope:            []string{"tool:tool.write"},
+		MinApprovals:             2,
+		RequireDistinctApprovers: true,
+		SigningPrivateKey:        keyPair.Private,
+	})
+	if err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	requestID := state.Request.RequestID
+
+	if _, err := DecideApprovalRequest(queueDir, DecideApprovalRequestOptions{
+		RequestID:        requestID,
+		Decision:         ApprovalDecisionDeny,
+		ApproverIdentity: "alice",
+		ReasonCode:       "late",
+	}); err == nil || !strings.Contains(err.Error(), "verify key is required") {
+		t.Fatalf("expected missing verify key error, got %v", err)
+	}
+	if _, err := DecideApprovalRequest(queueDir, DecideApprovalRequestOptions{
+		RequestID:         requestID,
+		Decision:          ApprovalDecisionGrant,
+		ApproverIdentity:  "alice",
+		ReasonCode:        "ticket",
+		SigningPrivateKey: otherKeyPair.Private,
+	}); err == nil || !strings.Contains(err.Error(), "signature verification failed") {
+		t.Fatalf("expected request signed by another key to be rejected, got %v", err)
+	}
+
+	tampered := state.Request
+	tampered.MinApprovals = 1
+	tamperedRaw, err := json.Marshal(tampered)
+	if err != nil {
+		t.Fatalf("marshal tampered request: %v", err)
+	}
+	if err := os.WriteFile(state.Path, tamperedRaw, 0o600); err != nil {
+		t.Fatalf("write tampered request: %v", err)
+	}
+	if _, err := DecideApprovalRequest(queueDir, DecideApprovalRequestOptions{
+		RequestID:         requestID,
+		Decision:          ApprovalDecisionGrant,
+		ApproverIdentity:  "alice",
+		ReasonCode:        "ticket",
+		SigningPrivateKey: keyPair.Private,
+	}); err == nil || !strings.Contains(err.Error(), "signature verification failed") {
+		t.Fatalf("expected tampered request to be rejected, got %v", err)
+	}
+	originalRaw, err := json.Marshal(state.Request)
+	if err != nil {
+		t.Fatalf("marshal request: %v", err)
+	}
+	if err := os.WriteFile(state.Path, originalRaw, 0o600); err != nil {
+		t.Fatalf("restore request: %v", err)
+	}
+
+	const attempts = 8
+	results := make(chan error, attempts)
+	for range attempts {
+		go func() {
+			_, err := DecideApprovalRequest(queueDir, DecideApprovalRequestOptions{
+				RequestID:         requestID,
+				Decision:          ApprovalDecisionGrant,
+				ApproverIdentity:  "alice",
+				ReasonCode:        "ticket",
+				SigningPrivateKey: keyPair.Private,
+			})
+			results <- err
+		}()
+	}
+	succeeded := 0
+	for range attempts {
+		if err := <-results; err == nil {
+			succeeded++
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("expected exactly one grant from the same approver, got %d", succeeded)
+	}
+	state, err = LoadApprovalRequest(queueDir, requestID, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("load request: %v", err)
+	}
+	if state.Status != ApprovalRequestStatusPending || len(state.Decisions) != 1 || len(state.TokenPaths) != 1 {
+		t.Fatalf("expected one recorded grant, got %#v", state)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	"github.com/Clyra-AI/gait/core/trust"
 	sign "github.com/Clyra-AI/proof/signing"
 )
 
@@ -508,3 +509,53 @@ func TestValidateApprovalTokenMissingVerifyKeyAndInvalidContext(t *testing.T) {
 		t.Fatalf("expected approval context to fail for invalid policy")
 	}
 }
+
+func TestValidateApprovalTokenWithTrustStore(t *testing.T) {
+	aliceKey, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	otherKey, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	store := trust.NewStore()
+	if _, err := store.Add(aliceKey.Public, trust.Key{Roles: []string{trust.RoleApprover}, Identity: "alice"}); err != nil {
+		t.Fatalf("add trusted key: %v", err)
+	}
+
+	now := time.Date(2026, time.February, 5, 12, 0, 0, 0, time.UTC)
+	mint := func(approver string, privateKey []byte) schemagate.ApprovalToken {
+		t.Helper()
+		token, err := signApprovalToken(MintApprovalTokenOptions{
+			ProducerVersion:   "test",
+			ApproverIdentity:  approver,
+			ReasonCode:        "incident_hotfix",
+			IntentDigest:      "1111111111111111111111111111111111111111111111111111111111111111",
+			PolicyDigest:      "2222222222222222222222222222222222222222222222222222222222222222",
+			Scope:             []string{"tool:tool.write"},
+			TTL:               30 * time.Minute,
+			Now:               now,
+			SigningPrivateKey: privateKey,
+		})
+		if err != nil {
+			t.Fatalf("sign approval token: %v", err)
+		}
+		return token
+	}
+	opts := ApprovalValidationOptions{Now: now.Add(time.Minute), TrustStore: store}
+
+	if err := ValidateApprovalToken(mint("alice", aliceKey.Private), nil, opts); err != nil {
+		t.Fatalf("expected trusted approver token to validate: %v", err)
+	}
+	for name, token := range map[string]schemagate.ApprovalToken{
+		"identity_mismatch": mint("bob", aliceKey.Private),
+		"unknown_key":       mint("alice", otherKey.Private),
+	} {
+		err := ValidateApprovalToken(token, otherKey.Public, opts)
+		var tokenErr *ApprovalTokenError
+		if !errors.As(err, &tokenErr) || tokenErr.Code != ApprovalCodeKeyUntrusted {
+			t.Fatalf("%s: expected %s, got %v", name, ApprovalCodeKeyUntrusted, err)
+		}
+	}
+}
@@ -0,0 +1,593 @@
+package gate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const maxArgPredicateDepth = 4
+
+var argPatternCache sync.Map
+
+// ArgPredicate tests the value at a JSON pointer into IntentRequest.Args.
+// Every operator that is set must hold. Any and All apply a nested predicate
+// to the elements of an array, with the nested path relative to each element.
+type ArgPredicate struct {
+	Path      string        `yaml:"path"`
+	Exists    *bool         `yaml:"exists"`
+	Equals    any           `yaml:"equals"`
+	NotEquals any           `yaml:"not_equals"`
+	In        []any         `yaml:"in"`
+	NotIn     []any         `yaml:"not_in"`
+	Regex     string        `yaml:"regex"`
+	Glob      string        `yaml:"glob"`
+	GT        *float64      `yaml:"gt"`
+	GTE       *float64      `yaml:"gte"`
+	LT        *float64      `yaml:"lt"`
+	LTE       *float64      `yaml:"lte"`
+	MinLength *int          `yaml:"min_length"`
+	MaxLength *int          `yaml:"max_length"`
+	Any       *ArgPredicate `yaml:"any"`
+	All       *ArgPredicate `yaml:"all"`
+}
+
+func normalizeArgPredicates(predicates []ArgPredicate, ruleName string) ([]ArgPredicate, error) {
+	if len(predicates) == 0 {
+		return nil, nil
+	}
+	out := make([]ArgPredicate, 0, len(predicates))
+	for index, predicate := range predicates {
+		normalized, err := normalizeArgPredicate(predicate, 0)
+		if err != nil {
+			return nil, fmt.Errorf("match.args[%d] for %s: %w", index, ruleName, err)
+		}
+		out = append(out, normalized)
+	}
+	return out, nil
+}
+
+func normalizeArgPredicate(predicate ArgPredicate, depth int) (ArgPredicate, error) {
+	if depth > maxArgPredicateDepth {
+		return ArgPredicate{}, fmt.Errorf("any/all nesting exceeds %d levels", maxArgPredicateDepth)
+	}
+	output := predicate
+	output.Path = strings.TrimSpace(output.Path)
+	if output.Path != "" && !strings.HasPrefix(output.Path, "/") {
+		return ArgPredicate{}, fmt.Errorf("path %q must be a JSON pointer starting with /", output.Path)
+	}
+	if _, err := splitJSONPointer(output.Path); err != nil {
+		return ArgPredicate{}, err
+	}
+	if output.Equals != nil {
+		output.Equals = normalizeArgValue(output.Equals)
+	}
+	if output.NotEquals != nil {
+		output.NotEquals = normalizeArgValue(output.NotEquals)
+	}
+	if output.In != nil {
+		if len(output.In) == 0 {
+			return ArgPredicate{}, fmt.Errorf("in must not be empty")
+		}
+		output.In = normalizeArgValues(output.In)
+	}
+	if output.NotIn != nil {
+		if len(output.NotIn) == 0 {
+			return ArgPredicate{}, fmt.Errorf("not_in must not be empty")
+		}
+		output.NotIn = normalizeArgValues(output.NotIn)
+	}
+	if output.Regex != "" {
+		if _, err := argPattern("regex", output.Regex); err != nil {
+			return ArgPredicate{}, err
+		}
+	}
+	if output.Glob != "" {
+		if _, err := argPattern("glob", output.Glob); err != nil {
+			return ArgPredicate{}, err
+		}
+	}
+	for _, bound := range []*float64{output.GT, output.GTE, output.LT, output.LTE} {
+		if bound != nil && (math.IsNaN(*bound) || math.IsInf(*bound, 0)) {
+			return ArgPredicate{}, fmt.Errorf("numeric bounds must be finite")
+		}
+	}
+	if output.MinLength != nil && *output.MinLength < 0 {
+		return ArgPredicate{}, fmt.Errorf("min_length must be >= 0")
+	}
+	if output.MaxLength != nil && *output.MaxLength < 0 {
+		return ArgPredicate{}, fmt.Errorf("max_length must be >= 0")
+	}
+	if output.MinLength != nil && output.MaxLength != nil && *output.MinLength > *output.MaxLength {
+		return ArgPredicate{}, fmt.Errorf("min_length must be <= max_length")
+	}
+	if output.Exists != nil && !*output.Exists && len(argPredicateOperators(output)) > 1 {
+		return ArgPredicate{}, fmt.Errorf("exists: false cannot be combined with other operators")
+	}
+	for _, nested := range []**ArgPredicate{&output.Any, &output.All} {
+		if *nested == nil {
+			continue
+		}
+		normalized, err := normalizeArgPredicate(**nested, depth+1)
+		if err != nil {
+			return ArgPredicate{}, err
+		}
+		*nested = &normalized
+	}
+	if len(argPredicateOperators(output)) == 0 {
+		return ArgPredicate{}, fmt.Errorf("predicate on %q needs at least one operator", output.Path)
+	}
+	return output, nil
+}
+
+func argPredicateOperators(predicate ArgPredicate) []string {
+	operators := []string{}
+	if predicate.Exists != nil {
+		operators = append(operators, "exists")
+	}
+	if predicate.Equals != nil {
+		operators = append(operators, "equals")
+	}
+	if predicate.NotEquals != nil {
+		operators = append(operators, "not_equals")
+	}
+	if predicate.In != nil {
+		operators = append(operators, "in")
+	}
+	if predicate.NotIn != nil {
+		operators = append(operators, "not_in")
+	}
+	if predicate.Regex != "" {
+		operators = append(operators, "regex")
+	}
+	if predicate.Glob != "" {
+		operators = append(operators, "glob")
+	}
+	if predicate.GT != nil {
+		operators = append(operators, "gt")
+	}
+	if predicate.GTE != nil {
+		operators = append(operators, "gte")
+	}
+	if predicate.LT != nil {
+		operators = append(operators, "lt")
+	}
+	if predicate.LTE != nil {
+		operators = append(operators, "lte")
+	}
+	if predicate.MinLength != nil {
+		operators = append(operators, "min_length")
+	}
+	if predicate.MaxLength != nil {
+		operators = append(operators, "max_length")
+	}
+	if predicate.Any != nil {
+		operators = append(operators, "any")
+	}
+	if predicate.All != nil {
+		operators = append(operators, "all")
+	}
+	return operators
+}
+
+func argPredicatesMatch(predicates []ArgPredicate, args map[string]any) bool {
+	for _, predicate := range predicates {
+		if !argPredicateHolds(predicate, args) {
+			return false
+		}
+	}
+	return true
+}
+
+func argPredicateHolds(predicate ArgPredicate, root any) bool {
+	value, found := lookupJSONPointer(root, predicate.Path)
+	if predicate.Exists != nil && *predicate.Exists != found {
+		return false
+	}
+	if !found {
+		return predicate.Exists != nil && !*predicate.Exists
+	}
+	if predicate.Equals != nil && !argValuesEqual(value, predicate.Equals) {
+		return false
+	}
+	if predicate.NotEquals != nil && argValuesEqual(value, predicate.NotEquals) {
+		return false
+	}
+	if predicate.In != nil && !argValueIn(value, predicate.In) {
+		return false
+	}
+	if predicate.NotIn != nil && argValueIn(value, predicate.NotIn) {
+		return false
+	}
+	if predicate.Regex != "" && !argPatternMatches("regex", predicate.Regex, value) {
+		return false
+	}
+	if predicate.Glob != "" && !argPatternMatches("glob", predicate.Glob, value) {
+		return false
+	}
+	if predicate.GT != nil || predicate.GTE != nil || predicate.LT != nil || predicate.LTE != nil {
+		number, ok := argNumber(value)
+		if !ok ||
+			(predicate.GT != nil && !(number > *predicate.GT)) ||
+			(predicate.GTE != nil && !(number >= *predicate.GTE)) ||
+			(predicate.LT != nil && !(number < *predicate.LT)) ||
+			(predicate.LTE != nil && !(number <= *predicate.LTE)) {
+			return false
+		}
+	}
+	if predicate.MinLength != nil || predicate.MaxLength != nil {
+		length, ok := argLength(value)
+		if !ok ||
+			(predicate.MinLength != nil && length < *predicate.MinLength) ||
+			(predicate.MaxLength != nil && length > *predicate.MaxLength) {
+			return false
+		}
+	}
+	if predicate.Any != nil || predicate.All != nil {
+		items, ok := value.([]any)
+		if !ok {
+			return false
+		}
+		if predicate.Any != nil {
+			matched := false
+			for _, item := range items {
+				if argPredicateHolds(*predicate.Any, item) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		}
+		if predicate.All != nil {
+			if len(items) == 0 {
+				return false
+			}
+			for _, item := range items {
+				if !argPredicateHolds(*predicate.All, item) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// describeArgPredicate renders a predicate for explain output, for example
+// `/amount gt 1000` or `/recipients any(glob *@example.com)`.
+func describeArgPredicate(predicate ArgPredicate) string {
+	path := predicate.Path
+	if path == "" {
+		path = "/"
+	}
+	parts := []string{}
+	for _, operator := range argPredicateOperators(predicate) {
+		var operand any
+		switch operator {
+		case "exists":
+			operand = *predicate.Exists
+		case "equals":
+			operand = predicate.Equals
+		case "not_equals":
+			operand = predicate.NotEquals
+		case "in":
+			operand = predicate.In
+		case "not_in":
+			operand = predicate.NotIn
+		case "regex":
+			operand = predicate.Regex
+		case "glob":
+			operand = predicate.Glob
+		case "gt":
+			operand = *predicate.GT
+		case "gte":
+			operand = *predicate.GTE
+		case "lt":
+			operand = *predicate.LT
+		case "lte":
+			operand = *predicate.LTE
+		case "min_length":
+			operand = *predicate.MinLength
+		case "max_length":
+			operand = *predicate.MaxLength
+		case "any":
+			parts = append(parts, "any("+strings.TrimPrefix(describeArgPredicate(*predicate.Any), "/ ")+")")
+			continue
+		case "all":
+			parts = append(parts, "all("+strings.TrimPrefix(describeArgPredicate(*predicate.All), "/ ")+")")
+			continue
+		}
+		parts = append(parts, operator+" "+formatArgOperand(operand))
+	}
+	return path + " " + strings.Join(parts, " ")
+}
+
+// FiredArgPredicates lists the argument predicates of rule that hold for args.
+func FiredArgPredicates(rule PolicyRule, args map[string]any) []string {
+	fired := []string{}
+	for _, predicate := range rule.Match.Args {
+		if argPredicateHolds(predicate, args) {
+			fired = append(fired, describeArgPredicate(predicate))
+		}
+	}
+	return fired
+}
+
+func argPredicateDigestPayload(predicates []ArgPredicate) []any {
+	payload := make([]any, 0, len(predicates))
+	for _, predicate := range predicates {
+		payload = append(payload, argPredicatePayload(predicate))
+	}
+	return payload
+}
+
+func argPredicatePayload(predicate ArgPredicate) map[string]any {
+	payload := map[string]any{"Path": predicate.Path}
+	if predicate.Exists != nil {
+		payload["Exists"] = *predicate.Exists
+	}
+	if predicate.Equals != nil {
+		payload["Equals"] = predicate.Equals
+	}
+	if predicate.NotEquals != nil {
+		payload["NotEquals"] = predicate.NotEquals
+	}
+	if predicate.In != nil {
+		payload["In"] = predicate.In
+	}
+	if predicate.NotIn != nil {
+		payload["NotIn"] = predicate.NotIn
+	}
+	if predicate.Regex != "" {
+		payload["Regex"] = predicate.Regex
+	}
+	if predicate.Glob != "" {
+		payload["Glob"] = predicate.Glob
+	}
+	if predicate.GT != nil {
+		payload["GT"] = *predicate.GT
+	}
+	if predicate.GTE != nil {
+		payload["GTE"] = *predicate.GTE
+	}
+	if predicate.LT != nil {
+		payload["LT"] = *predicate.LT
+	}
+	if predicate.LTE != nil {
+		payload["LTE"] = *predicate.LTE
+	}
+	if predicate.MinLength != nil {
+		payload["MinLength"] = *predicate.MinLength
+	}
+	if predicate.MaxLength != nil {
+		payload["MaxLength"] = *predicate.MaxLength
+	}
+	if predicate.Any != nil {
+		payload["Any"] = argPredicatePayload(*predicate.Any)
+	}
+	if predicate.All != nil {
+		payload["All"] = argPredicatePayload(*predicate.All)
+	}
+	return payload
+}
+
+func splitJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	segments := strings.Split(pointer[1:], "/")
+	for index, segment := range segments {
+		for position := 0; position < len(segment); position++ {
+			if segment[position] != '~' {
+				continue
+			}
+			if position+1 >= len(segment) || (segment[position+1] != '0' && segment[position+1] != '1') {
+				return nil, fmt.Errorf("path %q has an invalid ~ escape", pointer)
+			}
+		}
+		segments[index] = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+	}
+	return segments, nil
+}
+
+func lookupJSONPointer(root any, pointer string) (any, bool) {
+	segments, err := splitJSONPointer(pointer)
+	if err != nil {
+		return nil, false
+	}
+	current := root
+	for _, segment := range segments {
+		switch typed := current.(type) {
+		case map[string]any:
+			next, ok := typed[segment]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(typed) || strconv.Itoa(index) != segment {
+				return nil, false
+			}
+			current = typed[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func normalizeArgValues(values []any) []any {
+	out := make([]any, 0, len(values))
+	for _, value := range values {
+		out = append(out, normalizeArgValue(value))
+	}
+	return out
+}
+
+func normalizeArgValue(value any) any {
+	if number, ok := argNumber(value); ok {
+		return number
+	}
+	switch typed := value.(type) {
+	case []any:
+		return normalizeArgValues(typed)
+	case map[string]any:
+		out := make(map[string]any, len(typed))
+		for key, nested := range typed {
+			out[key] = normalizeArgValue(nested)
+		}
+		return out
+	case map[any]any:
+		out := make(map[string]any, len(typed))
+		for key, nested := range typed {
+			out[fmt.Sprint(key)] = normalizeArgValue(nested)
+		}
+		return out
+	}
+	return value
+}
+
+func argNumber(value any) (float64, bool) {
+	switch typed := value.(type) {
+	case float64:
+		return typed, true
+	case float32:
+		return float64(typed), true
+	case int:
+		return float64(typed), true
+	case int8:
+		return float64(typed), true
+	case int16:
+		return float64(typed), true
+	case int32:
+		return float64(typed), true
+	case int64:
+		return float64(typed), true
+	case uint:
+		return float64(typed), true
+	case uint8:
+		return float64(typed), true
+	case uint16:
+		return float64(typed), true
+	case uint32:
+		return float64(typed), true
+	case uint64:
+		return float64(typed), true
+	case json.Number:
+		number, err := typed.Float64()
+		return number, err == nil
+	}
+	return 0, false
+}
+
+func argLength(value any) (int, bool) {
+	switch typed := value.(type) {
+	case string:
+		return len([]rune(typed)), true
+	case []any:
+		return len(typed), true
+	case map[string]any:
+		return len(typed), true
+	}
+	return 0, false
+}
+
+func argValuesEqual(left any, right any) bool {
+	leftNumber, leftIsNumber := argNumber(left)
+	rightNumber, rightIsNumber := argNumber(right)
+	if leftIsNumber || rightIsNumber {
+		return leftIsNumber && rightIsNumber && leftNumber == rightNumber
+	}
+	switch typed := left.(type) {
+	case string, bool, nil:
+		return typed == right
+	}
+	leftRaw, leftErr := json.Marshal(normalizeArgValue(left))
+	rightRaw, rightErr := json.Marshal(normalizeArgValue(right))
+	return leftErr == nil && rightErr == nil && string(leftRaw) == string(rightRaw)
+}
+
+func argValueIn(value any, candidates []any) bool {
+	for _, candidate := range candidates {
+		if argValuesEqual(value, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func argPatternMatches(kind string, pattern string, value any) bool {
+	text, ok := value.(string)
+	if !ok {
+		return false
+	}
+	compiled, err := argPattern(kind, pattern)
+	if err != nil {
+		return false
+	}
+	return compiled.MatchString(text)
+}
+
+func argPattern(kind string, pattern string) (*regexp.Regexp, error) {
+	key := kind + "\x00" + pattern
+	if cached, ok := argPatternCache.Load(key); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	expression := pattern
+	if kind == "glob" {
+		expression = globToRegex(pattern)
+	}
+	compiled, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", kind, pattern, err)
+	}
+	argPatternCache.Store(key, compiled)
+	return compiled, nil
+}
+
+// globToRegex anchors a glob where * matches any run of characters and ?
+// matches one character, including path separators.
+func globToRegex(pattern string) string {
+	var builder strings.Builder
+	builder.WriteString("^")
+	for _, character := range pattern {
+		switch character {
+		case '*':
+			builder.WriteString("(?s:.*)")
+		case '?':
+			builder.WriteString("(?s:.)")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(character)))
+		}
+	}
+	builder.WriteString("$")
+	return builder.String()
+}
+
+func formatArgOperand(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case []any:
+		items := make([]string, 0, len(typed))
+		for _, item := range typed {
+			items = append(items, formatArgOperand(item))
+		}
+		sort.Strings(items)
+		return "[" + strings.Join(items, ",") + "]"
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
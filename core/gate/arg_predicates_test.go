@@ -0,0 +1,174 @@
+package gate
+
+import (
+	"strings"
+	"testing"
+)
+
+const argPredicatePolicy = `default_verdict: allow
+rules:
+  - name: block-rm-rf
+    priority: 10
+    effect: block
+    match:
+      tool_names: [shell.exec]
+      args:
+        - path: /command
+          regex: 'rm\s+-rf'
+  - name: approve-large-payment
+    priority: 20
+    effect: require_approval
+    match:
+      tool_names: [payments.send]
+      args:
+        - path: /amount
+          gt: 1000
+        - path: /currency
+          in: [USD, EUR]
+  - name: block-external-recipients
+    priority: 30
+    effect: block
+    match:
+      tool_names: [mail.send]
+      args:
+        - path: /recipients
+          any:
+            glob: "*@external.example"
+        - path: /recipients
+          max_length: 50
+  - name: block-untagged-batch
+    priority: 40
+    effect: block
+    match:
+      tool_names: [batch.run]
+      args:
+        - path: /items
+          all:
+            path: /tag
+            exists: false
+  - name: approve-escaped-key
+    priority: 50
+    effect: require_approval
+    match:
+      tool_names: [kv.put]
+      args:
+        - path: /labels/a~1b
+          equals: {tier: prod}
+`
+
+func TestArgPredicatesMatchIntentArgs(t *testing.T) {
+	policy, err := ParsePolicyYAML([]byte(argPredicatePolicy))
+	if err != nil {
+		t.Fatalf("parse policy: %v", err)
+	}
+	cases := []struct {
+		name    string
+		tool    string
+		args    map[string]any
+		verdict string
+	}{
+		{name: "rm_rf_blocked", tool: "shell.exec", args: map[string]any{"command": "sudo rm  -rf /"}, verdict: "block"},
+		{name: "ls_allowed", tool: "shell.exec", args: map[string]any{"command": "ls -la"}, verdict: "allow"},
+		{name: "missing_command_allowed", tool: "shell.exec", args: map[string]any{}, verdict: "allow"},
+		{name: "large_payment", tool: "payments.send", args: map[string]any{"amount": 1500.5, "currency": "USD"}, verdict: "require_approval"},
+		{name: "large_payment_int", tool: "payments.send", args: map[string]any{"amount": int64(2000), "currency": "EUR"}, verdict: "require_approval"},
+		{name: "small_payment", tool: "payments.send", args: map[string]any{"amount": 1000, "currency": "USD"}, verdict: "allow"},
+		{name: "other_currency", tool: "payments.send", args: map[string]any{"amount": 5000, "currency": "GBP"}, verdict: "allow"},
+		{name: "string_amount_not_numeric", tool: "payments.send", args: map[string]any{"amount": "5000", "currency": "USD"}, verdict: "allow"},
+		{name: "external_recipient", tool: "mail.send", args: map[string]any{"recipients": []any{"a@corp.example", "b@external.example"}}, verdict: "block"},
+		{name: "internal_recipients", tool: "mail.send", args: map[string]any{"recipients": []any{"a@corp.example"}}, verdict: "allow"},
+		{name: "untagged_batch", tool: "batch.run", args: map[string]any{"items": []any{map[string]any{"id": 1}, map[string]any{"id": 2}}}, verdict: "block"},
+		{name: "partially_tagged_batch", tool: "batch.run", args: map[string]any{"items": []any{map[string]any{"id": 1, "tag": "x"}, map[string]any{"id": 2}}}, verdict: "allow"},
+		{name: "escaped_pointer_object", tool: "kv.put", args: map[string]any{"labels": map[string]any{"a/b": map[string]any{"tier": "prod"}}}, verdict: "require_approval"},
+		{name: "escaped_pointer_mismatch", tool: "kv.put", args: map[string]any{"labels": map[string]any{"a/b": map[string]any{"tier": "dev"}}}, verdict: "allow"},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			intent := baseIntent()
+			intent.ToolName = testCase.tool
+			intent.Args = testCase.args
+			outcome, err := EvaluatePolicyDetailed(policy, intent, EvalOptions{ProducerVersion: "test"})
+			if err != nil {
+				t.Fatalf("evaluate: %v", err)
+			}
+			if outcome.Result.Verdict != testCase.verdict {
+				t.Fatalf("expected %s, got %s (matched=%s reasons=%v)", testCase.verdict, outcome.Result.Verdict, outcome.MatchedRule, outcome.Result.ReasonCodes)
+			}
+		})
+	}
+}
+
+func TestArgPredicatesExplainAndDigest(t *testing.T) {
+	policy, err := ParsePolicyYAML([]byte(argPredicatePolicy))
+	if err != nil {
+		t.Fatalf("parse policy: %v", err)
+	}
+	intent := baseIntent()
+	intent.ToolName = "payments.send"
+	intent.Args = map[string]any{"amount": 2500, "currency": "USD"}
+	outcome, err := EvaluatePolicyDetailed(policy, intent, EvalOptions{ProducerVersion: "test"})
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	explain := BuildPolicyExplain(policy, outcome, BuildPolicyExplainOptions{ProducerVersion: "test"})
+	if len(explain.MatchedRules) != 1 {
+		t.Fatalf("expected one matched rule, got %#v", explain.MatchedRules)
+	}
+	fired := explain.MatchedRules[0].ArgPredicates
+	if len(fired) != 2 || fired[0] != "/amount gt 1000" || fired[1] != "/currency in [EUR,USD]" {
+		t.Fatalf("unexpected fired predicates: %#v", fired)
+	}
+
+	digest, err := PolicyDigest(policy)
+	if err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+	changed, err := ParsePolicyYAML([]byte(strings.Replace(argPredicatePolicy, "gt: 1000", "gt: 2000", 1)))
+	if err != nil {
+		t.Fatalf("parse changed policy: %v", err)
+	}
+	changedDigest, err := PolicyDigest(changed)
+	if err != nil {
+		t.Fatalf("digest changed policy: %v", err)
+	}
+	if digest == changedDigest {
+		t.Fatalf("expected arg predicates to be covered by policy digest")
+	}
+	reparsed, err := ParsePolicyYAML([]byte(argPredicatePolicy))
+	if err != nil {
+		t.Fatalf("reparse policy: %v", err)
+	}
+	if reparsedDigest, err := PolicyDigest(reparsed); err != nil || reparsedDigest != digest {
+		t.Fatalf("expected stable digest, got %s vs %s err=%v", reparsedDigest, digest, err)
+	}
+}
+
+func TestArgPredicateValidation(t *testing.T) {
+	cases := []struct {
+		name      string
+		predicate string
+		wantErr   string
+	}{
+		{name: "relative_path", predicate: "path: command\n          equals: x", wantErr: "JSON pointer"},
+		{name: "bad_escape", predicate: "path: /a~2b\n          equals: x", wantErr: "invalid ~ escape"},
+		{name: "bad_regex", predicate: "path: /command\n          regex: '('", wantErr: "invalid regex"},
+		{name: "no_operator", predicate: "path: /command", wantErr: "at least one operator"},
+		{name: "empty_in", predicate: "path: /command\n          in: []", wantErr: "in must not be empty"},
+		{name: "length_range", predicate: "path: /items\n          min_length: 5\n          max_length: 2", wantErr: "min_length must be <= max_length"},
+		{name: "not_exists_combined", predicate: "path: /items\n          exists: false\n          equals: 1", wantErr: "cannot be combined"},
+		{name: "unknown_operator", predicate: "path: /items\n          matches: 1", wantErr: "matches"},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := ParsePolicyYAML([]byte(`rules:
+  - name: rule
+    effect: block
+    match:
+      args:
+        - ` + testCase.predicate + "\n"))
+			if err == nil || !strings.Contains(err.Error(), testCase.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", testCase.wantErr, err)
+			}
+		})
+	}
+}
@@ -95,3 +95,22 @@ func WriteBrokerCredentialRecord(path string, record schemagate.BrokerCredential
 	}
 	return nil
 }
+
+func ReadBrokerCredentialRecord(path string) (schemagate.BrokerCredentialRecord, error) {
+	// #nosec G304 -- credential evidence path is explicit local user input.
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return schemagate.BrokerCredentialRecord{}, fmt.Errorf("read credential evidence: %w", err)
+	}
+	var record schemagate.BrokerCredentialRecord
+	if err := json.Unmarshal(content, &record); err != nil {
+		return schemagate.BrokerCredentialRecord{}, fmt.Errorf("parse credential evidence: %w", err)
+	}
+	if record.SchemaID != brokerCredentialSchemaID {
+		return schemagate.BrokerCredentialRecord{}, fmt.Errorf("unsupported credential evidence schema %q", record.SchemaID)
+	}
+	if strings.TrimSpace(record.CredentialRef) == "" {
+		return schemagate.BrokerCredentialRecord{}, fmt.Errorf("credential evidence missing credential_ref")
+	}
+	return record, nil
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	"github.com/Clyra-AI/gait/core/trust"
 	jcs "github.com/Clyra-AI/proof/canon"
 	sign "github.com/Clyra-AI/proof/signing"
 )
@@ -32,6 +33,7 @@ const (
 	DelegationCodeIntentMismatch  = "delegation_token_intent_mismatch"
 	DelegationCodePolicyMismatch  = "delegation_token_policy_mismatch"
 	DelegationCodeChainMismatch   = "delegation_token_chain_mismatch"
+	DelegationCodeKeyUntrusted    = "delegation_token_key_untrusted"
 )
 
 type MintDelegationTokenOptions struct {
@@ -60,6 +62,9 @@ type DelegationValidationOptions struct {
 	RequiredScope        []string
 	ExpectedIntentDigest string
 	ExpectedPolicyDigest string
+	// TrustStore, when set, selects the verify key by signature key id
+	// instead of the publicKey argument.
+	TrustStore *trust.Store
 }
 
 type DelegationTokenError struct {
@@ -72,6 +77,7 @@ type DelegationChainValidationOptions struct {
 	RequiredScope        []string
 	ExpectedIntentDigest string
 	ExpectedPolicyDigest string
+	TrustStore           *trust.Store
 }
 
 type DelegationChainValidationResult struct {
@@ -216,12 +222,23 @@ func ValidateDelegationToken(token schemagate.DelegationToken, publicKey ed25519
 	if err != nil {
 		return &DelegationTokenError{Code: DelegationCodeSchemaInvalid, Err: err}
 	}
-	if len(publicKey) == 0 {
+	if len(publicKey) == 0 && opts.TrustStore == nil {
 		return &DelegationTokenError{Code: DelegationCodeSignatureFailed, Err: fmt.Errorf("verification public key is required")}
 	}
 	if normalized.Signature == nil {
 		return &DelegationTokenError{Code: DelegationCodeSignatureMiss, Err: fmt.Errorf("signature missing")}
 	}
+	if opts.TrustStore != nil {
+		publicKey, err = opts.TrustStore.Resolve(trust.Lookup{
+			Role:     trust.RoleDelegator,
+			KeyID:    normalized.Signature.KeyID,
+			Identity: normalized.DelegatorIdentity,
+			SignedAt: normalized.CreatedAt,
+		})
+		if err != nil {
+			return &DelegationTokenError{Code: DelegationCodeKeyUntrusted, Err: err}
+		}
+	}
 
 	signable := normalized
 	signable.Signature = nil
@@ -312,6 +329,7 @@ func ValidateDelegationChain(delegation *schemagate.IntentDelegation, tokens []s
 				RequiredScope:        requiredScope,
 				ExpectedIntentDigest: opts.ExpectedIntentDigest,
 				ExpectedPolicyDigest: opts.ExpectedPolicyDigest,
+				TrustStore:           opts.TrustStore,
 			})
 			if validateErr != nil {
 				continue
@@ -360,6 +378,7 @@ func ValidateDelegationChain(delegation *schemagate.IntentDelegation, tokens []s
 				RequiredScope:        requiredScope,
 				ExpectedIntentDigest: opts.ExpectedIntentDigest,
 				ExpectedPolicyDigest: opts.ExpectedPolicyDigest,
+				TrustStore:           opts.TrustStore,
 			})
 			if validateErr == nil {
 				errorCode = ""
@@ -0,0 +1,264 @@
+package gate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+)
+
+var execCommandArgKeys = []string{"command", "cmd", "script"}
+
+const reasonExecCommandUnparseable = "exec_command_unparseable"
+
+// ExecMatch matches the commands parsed from the shell command of a proc.exec
+// intent. A rule matches when a single parsed command satisfies every field
+// that is set.
+type ExecMatch struct {
+	Executables []string   `yaml:"executables"`
+	Operands    []string   `yaml:"operands"`
+	FlagSets    [][]string `yaml:"flag_sets"`
+	Piped       bool       `yaml:"piped"`
+	Privileged  bool       `yaml:"privileged"`
+}
+
+func (match ExecMatch) empty() bool {
+	return len(match.Executables) == 0 && len(match.Operands) == 0 && len(match.FlagSets) == 0 && !match.Piped && !match.Privileged
+}
+
+func normalizeExecMatch(match ExecMatch, ruleName string) (ExecMatch, error) {
+	executables := make([]string, 0, len(match.Executables))
+	for _, executable := range match.Executables {
+		if trimmed := strings.TrimSpace(executable); trimmed != "" {
+			executables = append(executables, shellExecutableName(trimmed))
+		}
+	}
+	output := ExecMatch{
+		Executables: uniqueSorted(executables),
+		Operands:    normalizeStringList(match.Operands),
+		Piped:       match.Piped,
+		Privileged:  match.Privileged,
+	}
+	seenSets := map[string]struct{}{}
+	for index, flagSet := range match.FlagSets {
+		flags := make([]string, 0, len(flagSet))
+		for _, flag := range flagSet {
+			flag, _, _ = strings.Cut(strings.TrimSpace(flag), "=")
+			if len(flag) < 2 || !strings.HasPrefix(flag, "-") {
+				return ExecMatch{}, fmt.Errorf("match.exec.flag_sets[%d] for %s: flag %q must start with -", index, ruleName, flag)
+			}
+			flags = append(flags, flag)
+		}
+		flags = uniqueSorted(flags)
+		if len(flags) == 0 {
+			return ExecMatch{}, fmt.Errorf("match.exec.flag_sets[%d] for %s must not be empty", index, ruleName)
+		}
+		key := strings.Join(flags, "\x00")
+		if _, ok := seenSets[key]; ok {
+			continue
+		}
+		seenSets[key] = struct{}{}
+		output.FlagSets = append(output.FlagSets, flags)
+	}
+	sort.Slice(output.FlagSets, func(i, j int) bool {
+		return strings.Join(output.FlagSets[i], "\x00") < strings.Join(output.FlagSets[j], "\x00")
+	})
+	if output.empty() {
+		return ExecMatch{}, nil
+	}
+	return output, nil
+}
+
+func execMatchDigestPayload(match ExecMatch) (map[string]any, bool) {
+	if match.empty() {
+		return nil, false
+	}
+	payload := map[string]any{}
+	if len(match.Executables) > 0 {
+		payload["Executables"] = match.Executables
+	}
+	if len(match.Operands) > 0 {
+		payload["Operands"] = match.Operands
+	}
+	if len(match.FlagSets) > 0 {
+		payload["FlagSets"] = match.FlagSets
+	}
+	if match.Piped {
+		payload["Piped"] = true
+	}
+	if match.Privileged {
+		payload["Privileged"] = true
+	}
+	return payload, true
+}
+
+func execMatches(match ExecMatch, intent schemagate.IntentRequest) bool {
+	if match.empty() {
+		return true
+	}
+	parsed, ok, err := parseExecIntentCommand(intent.Args, intent.Targets)
+	if err != nil || !ok {
+		return false
+	}
+	for _, command := range parsed.Commands {
+		if execCommandMatches(match, command) {
+			return true
+		}
+	}
+	return false
+}
+
+func execCommandMatches(match ExecMatch, command ShellCommand) bool {
+	if len(match.Executables) > 0 && !contains(match.Executables, command.Executable) {
+		return false
+	}
+	if match.Piped && !command.Piped {
+		return false
+	}
+	if match.Privileged && !command.Privileged {
+		return false
+	}
+	if len(match.Operands) > 0 {
+		operandMatched := false
+		for _, operand := range command.Operands {
+			if contains(match.Operands, operand) {
+				operandMatched = true
+				break
+			}
+		}
+		if !operandMatched {
+			return false
+		}
+	}
+	if len(match.FlagSets) == 0 {
+		return true
+	}
+	for _, flagSet := range match.FlagSets {
+		setMatched := true
+		for _, flag := range flagSet {
+			if !contains(command.Flags, flag) {
+				setMatched = false
+				break
+			}
+		}
+		if setMatched {
+			return true
+		}
+	}
+	return false
+}
+
+// parseExecIntentCommand parses the command of an intent that declares a
+// proc.exec target. The command is read from args.command, args.cmd or
+// args.script, or from an args.argv string array.
+func parseExecIntentCommand(args map[string]any, targets []schemagate.IntentTarget) (ParsedShellCommand, bool, error) {
+	execTarget := false
+	for _, target := range targets {
+		if target.EndpointClass == "proc.exec" {
+			execTarget = true
+			break
+		}
+	}
+	if !execTarget {
+		return ParsedShellCommand{}, false, nil
+	}
+	for _, key := range execCommandArgKeys {
+		value, ok := args[key].(string)
+		if !ok || strings.TrimSpace(value) == "" {
+			continue
+		}
+		parsed, err := ParseShellCommand(value)
+		if err != nil {
+			return ParsedShellCommand{}, false, fmt.Errorf("parse args.%s: %w", key, err)
+		}
+		return parsed, true, nil
+	}
+	rawArgv, ok := args["argv"].([]any)
+	if !ok || len(rawArgv) == 0 {
+		return ParsedShellCommand{}, false, nil
+	}
+	argv := make([]string, 0, len(rawArgv))
+	for _, value := range rawArgv {
+		word, ok := value.(string)
+		if !ok {
+			return ParsedShellCommand{}, false, fmt.Errorf("args.argv must contain only strings")
+		}
+		argv = append(argv, word)
+	}
+	parsed, err := parseShellArgv(argv)
+	if err != nil {
+		return ParsedShellCommand{}, false, fmt.Errorf("parse args.argv: %w", err)
+	}
+	return parsed, true, nil
+}
+
+// synthesizeExecTargets adds one proc.exec target per parsed executable and
+// one target per file or URL the command touches. A command that cannot be
+// parsed keeps its declared targets; evaluation reports it with
+// exec_command_unparseable.
+func synthesizeExecTargets(toolName string, args map[string]any, targets []schemagate.IntentTarget) ([]schemagate.IntentTarget, error) {
+	parsed, ok, err := parseExecIntentCommand(args, targets)
+	if err != nil || !ok {
+		return targets, nil
+	}
+	synthesized := append([]schemagate.IntentTarget{}, targets...)
+	for _, command := range parsed.Commands {
+		synthesized = append(synthesized, schemagate.IntentTarget{
+			Kind:          "other",
+			Value:         command.Executable,
+			Operation:     "exec",
+			EndpointClass: "proc.exec",
+		})
+	}
+	for _, target := range parsed.Targets {
+		synthesized = append(synthesized, schemagate.IntentTarget{
+			Kind:          target.Kind,
+			Value:         target.Value,
+			Operation:     target.Operation,
+			EndpointClass: inferEndpointClass(target.Kind, target.Operation, ""),
+		})
+	}
+	return normalizeTargets(toolName, synthesized)
+}
+
+// execCommandUnparseable reports whether the intent, or any script step, has a
+// proc.exec command that cannot be parsed.
+func execCommandUnparseable(intent schemagate.IntentRequest) bool {
+	if _, _, err := parseExecIntentCommand(intent.Args, intent.Targets); err != nil {
+		return true
+	}
+	if intent.Script == nil {
+		return false
+	}
+	for _, step := range intent.Script.Steps {
+		if _, _, err := parseExecIntentCommand(step.Args, step.Targets); err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func policyUsesExecMatch(policy Policy) bool {
+	for _, rule := range policy.Rules {
+		if !rule.Match.Exec.empty() {
+			return true
+		}
+	}
+	return false
+}
+
+// applyExecCommandOutcome records an unparseable exec command on the result.
+// Policies with match.exec rules, or fail_closed for the risk class, block the
+// call because those rules cannot be checked.
+func applyExecCommandOutcome(policy Policy, intent schemagate.IntentRequest, outcome EvalOutcome) EvalOutcome {
+	if !execCommandUnparseable(intent) {
+		return outcome
+	}
+	outcome.Result.ReasonCodes = mergeUniqueSorted(outcome.Result.ReasonCodes, []string{reasonExecCommandUnparseable})
+	if policyUsesExecMatch(policy) || shouldFailClosed(policy.FailClosed, intent.Context.RiskClass) {
+		outcome.Result.Verdict = "block"
+		outcome.Result.Violations = mergeUniqueSorted(outcome.Result.Violations, []string{reasonExecCommandUnparseable})
+	}
+	return outcome
+}
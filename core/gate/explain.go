@@ -43,7 +43,7 @@ func BuildPolicyExplain(policy Policy, outcome EvalOutcome, opts BuildPolicyExpl
 		ProducerVersion:          producerVersion,
 		Verdict:                  outcome.Result.Verdict,
 		MatchedRule:              outcome.MatchedRule,
-		MatchedRules:             toExplainRules(matchedRules),
+		MatchedRules:             toExplainRules(matchedRules, outcome.PreparedIntent.Args),
 		ReasonCodes:              mergeUniqueSorted(nil, outcome.Result.ReasonCodes),
 		Violations:               mergeUniqueSorted(nil, outcome.Result.Violations),
 		MissingFields:            explainMissingFields(outcome.Result.ReasonCodes),
@@ -87,14 +87,20 @@ func matchedRulesForOutcome(policy Policy, matchedRule string) []PolicyRule {
 	return rules
 }
 
-func toExplainRules(rules []PolicyRule) []schemagate.PolicyExplainRule {
+func toExplainRules(rules []PolicyRule, args map[string]any) []schemagate.PolicyExplainRule {
 	out := make([]schemagate.PolicyExplainRule, 0, len(rules))
 	for _, rule := range rules {
-		out = append(out, schemagate.PolicyExplainRule{
+		explainRule := schemagate.PolicyExplainRule{
 			Name:     rule.Name,
 			Priority: rule.Priority,
 			Effect:   rule.Effect,
-		})
+			Source:   rule.Source,
+			Locked:   rule.Locked,
+		}
+		if len(rule.Match.Args) > 0 {
+			explainRule.ArgPredicates = FiredArgPredicates(rule, args)
+		}
+		out = append(out, explainRule)
 	}
 	return out
 }
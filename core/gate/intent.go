@@ -66,6 +66,7 @@ var (
 		"command":                    {},
 		"env":                        {},
 		"gcp_sts":                    {},
+		"github_app":                 {},
 		"github_oidc":                {},
 		"github_pat":                 {},
 		"kubernetes_service_account": {},
@@ -250,6 +251,12 @@ func normalizeIntent(input schemagate.IntentRequest) (normalizedIntent, error) {
 	if err != nil {
 		return normalizedIntent{}, err
 	}
+	if script == nil {
+		targets, err = synthesizeExecTargets(toolName, args, targets)
+		if err != nil {
+			return normalizedIntent{}, err
+		}
+	}
 	provenanceInput := input.ArgProvenance
 	if script != nil && len(provenanceInput) == 0 {
 		for _, step := range script.Steps {
@@ -322,6 +329,10 @@ func normalizeScript(input *schemagate.IntentScript) (*normalizedScript, error)
 		if err != nil {
 			return nil, fmt.Errorf("normalize script.steps[%d].targets: %w", index, err)
 		}
+		targets, err = synthesizeExecTargets(toolName, args, targets)
+		if err != nil {
+			return nil, fmt.Errorf("normalize script.steps[%d].targets: %w", index, err)
+		}
 		provenance, err := normalizeArgProvenance(step.ArgProvenance)
 		if err != nil {
 			return nil, fmt.Errorf("normalize script.steps[%d].arg_provenance: %w", index, err)
@@ -1,10 +1,13 @@
 package gate
 
 import (
+	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
@@ -13,6 +16,7 @@ import (
 
 	"github.com/Clyra-AI/gait/core/fsx"
 	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	sign "github.com/Clyra-AI/proof/signing"
 )
 
 const (
@@ -46,11 +50,24 @@ func LoadKillSwitchState(path string) (schemagate.KillSwitchState, error) {
 	if err := json.Unmarshal(payload, &state); err != nil {
 		return schemagate.KillSwitchState{}, fmt.Errorf("parse kill switch state: %w", err)
 	}
-	return normalizeKillSwitchState(state)
+	return normalizeKillSwitchState(state, 0)
 }
 
+// WriteKillSwitchState refuses to replace an on-disk state unless the new
+// revision advances it, so a stale copy cannot silently clear newer entries.
 func WriteKillSwitchState(path string, state schemagate.KillSwitchState) error {
-	normalized, err := normalizeKillSwitchState(state)
+	minRevision := int64(0)
+	previous, err := LoadKillSwitchState(path)
+	switch {
+	case err == nil:
+		minRevision = previous.Revision
+		if minRevision > 0 {
+			minRevision++
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+	normalized, err := normalizeKillSwitchState(state, minRevision)
 	if err != nil {
 		return err
 	}
@@ -195,7 +212,75 @@ func computeKillSwitchEntryID(entry schemagate.KillSwitchEntry) string {
 	return hex.EncodeToString(sum[:12])
 }
 
-func normalizeKillSwitchState(state schemagate.KillSwitchState) (schemagate.KillSwitchState, error) {
+// SignKillSwitchState normalizes state and signs it with privateKey. The
+// signature covers the revision, so replaying an older signed state is
+// detectable by any holder of a newer one.
+func SignKillSwitchState(state schemagate.KillSwitchState, privateKey ed25519.PrivateKey) (schemagate.KillSwitchState, error) {
+	if len(privateKey) == 0 {
+		return schemagate.KillSwitchState{}, fmt.Errorf("signing private key is required")
+	}
+	normalized, err := normalizeKillSwitchState(state, 0)
+	if err != nil {
+		return schemagate.KillSwitchState{}, err
+	}
+	raw, err := signableKillSwitchState(normalized)
+	if err != nil {
+		return schemagate.KillSwitchState{}, err
+	}
+	signature, err := sign.SignTraceRecordJSON(privateKey, raw)
+	if err != nil {
+		return schemagate.KillSwitchState{}, fmt.Errorf("sign kill switch state: %w", err)
+	}
+	normalized.Signature = &schemagate.Signature{
+		Alg:          signature.Alg,
+		KeyID:        signature.KeyID,
+		Sig:          signature.Sig,
+		SignedDigest: signature.SignedDigest,
+	}
+	return normalized, nil
+}
+
+func VerifyKillSwitchState(state schemagate.KillSwitchState, publicKey ed25519.PublicKey) error {
+	normalized, err := normalizeKillSwitchState(state, 0)
+	if err != nil {
+		return err
+	}
+	if normalized.Signature == nil {
+		return fmt.Errorf("kill switch state signature is required")
+	}
+	if len(publicKey) == 0 {
+		return fmt.Errorf("verify key is required")
+	}
+	raw, err := signableKillSwitchState(normalized)
+	if err != nil {
+		return err
+	}
+	ok, err := sign.VerifyTraceRecordJSON(publicKey, sign.Signature{
+		Alg:          normalized.Signature.Alg,
+		KeyID:        normalized.Signature.KeyID,
+		Sig:          normalized.Signature.Sig,
+		SignedDigest: normalized.Signature.SignedDigest,
+	}, raw)
+	if err != nil {
+		return fmt.Errorf("verify kill switch state signature: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("kill switch state signature did not verify")
+	}
+	return nil
+}
+
+func signableKillSwitchState(state schemagate.KillSwitchState) ([]byte, error) {
+	signable := state
+	signable.Signature = nil
+	raw, err := json.Marshal(signable)
+	if err != nil {
+		return nil, fmt.Errorf("marshal signable kill switch state: %w", err)
+	}
+	return raw, nil
+}
+
+func normalizeKillSwitchState(state schemagate.KillSwitchState, minRevision int64) (schemagate.KillSwitchState, error) {
 	if strings.TrimSpace(state.SchemaID) == "" {
 		state.SchemaID = killSwitchStateSchemaID
 	}
@@ -214,6 +299,12 @@ func normalizeKillSwitchState(state schemagate.KillSwitchState) (schemagate.Kill
 	if state.UpdatedAt.IsZero() {
 		return schemagate.KillSwitchState{}, fmt.Errorf("kill switch state updated_at is required")
 	}
+	if state.Revision < 0 {
+		return schemagate.KillSwitchState{}, fmt.Errorf("kill switch state revision must be >= 0")
+	}
+	if state.Revision < minRevision {
+		return schemagate.KillSwitchState{}, fmt.Errorf("kill switch state revision %d would roll back; revision must be at least %d", state.Revision, minRevision)
+	}
 	state.CreatedAt = state.CreatedAt.UTC()
 	state.UpdatedAt = state.UpdatedAt.UTC()
 	state.ProducerVersion = strings.TrimSpace(state.ProducerVersion)
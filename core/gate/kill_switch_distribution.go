@@ -0,0 +1,207 @@
+package gate
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/fsx"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+)
+
+const (
+	KillSwitchOriginSource = "source"
+	KillSwitchOriginCache  = "cache"
+
+	killSwitchCacheSchemaID      = "gait.gate.kill_switch_cache"
+	killSwitchCacheSchemaVersion = "1.0.0"
+	killSwitchFetchTimeout       = 5 * time.Second
+	killSwitchFetchMaxBody       = 4 << 20
+)
+
+// KillSwitchFetchOptions describes a kill-switch distribution point. Source is
+// a local path or an http(s) URL; remote sources must be signed and verified
+// with PublicKey and need CachePath. When CachePath is set, the last verified
+// state is kept there, bounds the revisions later fetches may return, and is
+// served while the source is unreachable for up to MaxStaleness.
+type KillSwitchFetchOptions struct {
+	Source       string
+	PublicKey    ed25519.PublicKey
+	CachePath    string
+	MaxStaleness time.Duration
+	Now          time.Time
+	Client       *http.Client
+}
+
+type KillSwitchFetchResult struct {
+	State     schemagate.KillSwitchState
+	Origin    string
+	FetchedAt time.Time
+	Warnings  []string
+}
+
+type killSwitchCacheRecord struct {
+	SchemaID      string                     `json:"schema_id"`
+	SchemaVersion string                     `json:"schema_version"`
+	FetchedAt     time.Time                  `json:"fetched_at"`
+	Source        string                     `json:"source"`
+	State         schemagate.KillSwitchState `json:"state"`
+}
+
+func IsRemoteKillSwitchSource(source string) bool {
+	lowered := strings.ToLower(strings.TrimSpace(source))
+	return strings.HasPrefix(lowered, "http://") || strings.HasPrefix(lowered, "https://")
+}
+
+// FetchKillSwitchState loads kill-switch state from its distribution point.
+// A fetched state whose revision is older than the cached one is treated as a
+// rollback and rejected. When the source cannot be used, the cached
+// last-known-good state is returned if it is fresh enough; otherwise an error
+// is returned so callers can fail closed.
+func FetchKillSwitchState(ctx context.Context, opts KillSwitchFetchOptions) (KillSwitchFetchResult, error) {
+	source := strings.TrimSpace(opts.Source)
+	if source == "" {
+		return KillSwitchFetchResult{}, fmt.Errorf("kill switch state source is required")
+	}
+	if IsRemoteKillSwitchSource(source) && len(opts.PublicKey) == 0 {
+		return KillSwitchFetchResult{}, fmt.Errorf("remote kill switch state requires a verify key")
+	}
+	cachePath := strings.TrimSpace(opts.CachePath)
+	// The cache holds the revision floor. Without it, whoever serves the
+	// remote source could replay an older, validly signed state.
+	if IsRemoteKillSwitchSource(source) && cachePath == "" {
+		return KillSwitchFetchResult{}, fmt.Errorf("remote kill switch state requires a cache path")
+	}
+	now := opts.Now.UTC()
+	if opts.Now.IsZero() {
+		now = time.Now().UTC()
+	}
+	cached, cacheErr := readKillSwitchCache(cachePath, opts.PublicKey)
+
+	state, fetchErr := readKillSwitchSource(ctx, source, opts.Client)
+	if fetchErr == nil && len(opts.PublicKey) > 0 {
+		fetchErr = VerifyKillSwitchState(state, opts.PublicKey)
+	}
+	if fetchErr == nil && cached != nil && state.Revision < cached.State.Revision {
+		fetchErr = fmt.Errorf("kill switch state revision %d rolls back cached revision %d", state.Revision, cached.State.Revision)
+	}
+	if fetchErr == nil {
+		result := KillSwitchFetchResult{State: state, Origin: KillSwitchOriginSource, FetchedAt: now}
+		if cachePath != "" {
+			if err := writeKillSwitchCache(cachePath, killSwitchCacheRecord{FetchedAt: now, Source: source, State: state}); err != nil {
+				result.Warnings = append(result.Warnings, "kill switch cache not updated: "+err.Error())
+			}
+		}
+		return result, nil
+	}
+
+	if cached == nil {
+		if cacheErr != nil {
+			return KillSwitchFetchResult{}, fmt.Errorf("%w (cache unavailable: %v)", fetchErr, cacheErr)
+		}
+		return KillSwitchFetchResult{}, fetchErr
+	}
+	age := now.Sub(cached.FetchedAt)
+	if opts.MaxStaleness <= 0 || age > opts.MaxStaleness {
+		return KillSwitchFetchResult{}, fmt.Errorf("%w (cached revision %d is stale: fetched %s ago)", fetchErr, cached.State.Revision, age.Round(time.Second))
+	}
+	return KillSwitchFetchResult{
+		State:     cached.State,
+		Origin:    KillSwitchOriginCache,
+		FetchedAt: cached.FetchedAt,
+		Warnings:  []string{fmt.Sprintf("using cached kill switch state revision %d: %v", cached.State.Revision, fetchErr)},
+	}, nil
+}
+
+func readKillSwitchSource(ctx context.Context, source string, client *http.Client) (schemagate.KillSwitchState, error) {
+	if !IsRemoteKillSwitchSource(source) {
+		return LoadKillSwitchState(source)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return schemagate.KillSwitchState{}, fmt.Errorf("build kill switch state request: %w", err)
+	}
+	request.Header.Set("Accept", "application/json")
+	if client == nil {
+		client = &http.Client{Timeout: killSwitchFetchTimeout}
+	}
+	// #nosec G107 -- kill switch distribution url is explicit operator configuration.
+	response, err := client.Do(request)
+	if err != nil {
+		return schemagate.KillSwitchState{}, fmt.Errorf("fetch kill switch state: %w", err)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	if response.StatusCode != http.StatusOK {
+		return schemagate.KillSwitchState{}, fmt.Errorf("fetch kill switch state: %s returned %d", source, response.StatusCode)
+	}
+	payload, err := io.ReadAll(io.LimitReader(response.Body, killSwitchFetchMaxBody))
+	if err != nil {
+		return schemagate.KillSwitchState{}, fmt.Errorf("read kill switch state: %w", err)
+	}
+	var state schemagate.KillSwitchState
+	if err := json.Unmarshal(payload, &state); err != nil {
+		return schemagate.KillSwitchState{}, fmt.Errorf("parse kill switch state: %w", err)
+	}
+	return normalizeKillSwitchState(state, 0)
+}
+
+func readKillSwitchCache(path string, publicKey ed25519.PublicKey) (*killSwitchCacheRecord, error) {
+	if path == "" {
+		return nil, nil
+	}
+	// #nosec G304 -- explicit local cache path input.
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read kill switch cache: %w", err)
+	}
+	var record killSwitchCacheRecord
+	if err := json.Unmarshal(payload, &record); err != nil {
+		return nil, fmt.Errorf("parse kill switch cache: %w", err)
+	}
+	if record.SchemaID != killSwitchCacheSchemaID {
+		return nil, fmt.Errorf("kill switch cache schema_id must be %s", killSwitchCacheSchemaID)
+	}
+	if record.FetchedAt.IsZero() {
+		return nil, fmt.Errorf("kill switch cache fetched_at is required")
+	}
+	state, err := normalizeKillSwitchState(record.State, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(publicKey) > 0 {
+		if err := VerifyKillSwitchState(state, publicKey); err != nil {
+			return nil, fmt.Errorf("kill switch cache: %w", err)
+		}
+	}
+	record.FetchedAt = record.FetchedAt.UTC()
+	record.State = state
+	return &record, nil
+}
+
+func writeKillSwitchCache(path string, record killSwitchCacheRecord) error {
+	record.SchemaID = killSwitchCacheSchemaID
+	record.SchemaVersion = killSwitchCacheSchemaVersion
+	encoded, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal kill switch cache: %w", err)
+	}
+	encoded = append(encoded, '\n')
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("create kill switch cache directory: %w", err)
+	}
+	return fsx.WriteFileAtomic(path, encoded, 0o600)
+}
@@ -0,0 +1,100 @@
+package gate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+func TestFetchKillSwitchStateRemoteCacheAndStaleness(t *testing.T) {
+	keyPair, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	now := time.Date(2026, time.May, 9, 12, 0, 0, 0, time.UTC)
+	signedRevision := func(revision int64) schemagate.KillSwitchState {
+		state := NewKillSwitchState(now, "test")
+		state.Revision = revision
+		state.Entries = []schemagate.KillSwitchEntry{{EntryID: "tool", Enabled: true, ToolName: "tool.exec", CreatedAt: now}}
+		signed, err := SignKillSwitchState(state, keyPair.Private)
+		if err != nil {
+			t.Fatalf("sign revision %d: %v", revision, err)
+		}
+		return signed
+	}
+
+	var mu sync.Mutex
+	served := signedRevision(2)
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if status != http.StatusOK {
+			writer.WriteHeader(status)
+			return
+		}
+		_ = json.NewEncoder(writer).Encode(served)
+	}))
+	defer server.Close()
+	serve := func(state schemagate.KillSwitchState, code int) {
+		mu.Lock()
+		defer mu.Unlock()
+		served = state
+		status = code
+	}
+
+	opts := KillSwitchFetchOptions{
+		Source:       server.URL,
+		PublicKey:    keyPair.Public,
+		CachePath:    filepath.Join(t.TempDir(), "cache", "kill_switch_cache.json"),
+		MaxStaleness: 10 * time.Minute,
+		Now:          now,
+	}
+	result, err := FetchKillSwitchState(context.Background(), opts)
+	if err != nil || result.Origin != KillSwitchOriginSource || result.State.Revision != 2 {
+		t.Fatalf("unexpected initial fetch: %#v err=%v", result, err)
+	}
+
+	serve(signedRevision(1), http.StatusOK)
+	result, err = FetchKillSwitchState(context.Background(), opts)
+	if err != nil || result.Origin != KillSwitchOriginCache || result.State.Revision != 2 || !strings.Contains(strings.Join(result.Warnings, " "), "rolls back") {
+		t.Fatalf("expected rollback to fall back to cached revision: %#v err=%v", result, err)
+	}
+
+	unsigned := NewKillSwitchState(now, "test")
+	unsigned.Revision = 9
+	serve(unsigned, http.StatusOK)
+	if result, err = FetchKillSwitchState(context.Background(), opts); err != nil || result.Origin != KillSwitchOriginCache {
+		t.Fatalf("expected unsigned remote state to be rejected in favour of cache: %#v err=%v", result, err)
+	}
+
+	serve(schemagate.KillSwitchState{}, http.StatusServiceUnavailable)
+	opts.Now = now.Add(5 * time.Minute)
+	if result, err = FetchKillSwitchState(context.Background(), opts); err != nil || result.Origin != KillSwitchOriginCache {
+		t.Fatalf("expected fresh cache while source is down: %#v err=%v", result, err)
+	}
+	opts.Now = now.Add(11 * time.Minute)
+	if _, err = FetchKillSwitchState(context.Background(), opts); err == nil || !strings.Contains(err.Error(), "stale") {
+		t.Fatalf("expected stale cache to fail closed, got %v", err)
+	}
+
+	if _, err := FetchKillSwitchState(context.Background(), KillSwitchFetchOptions{Source: server.URL}); err == nil {
+		t.Fatalf("expected remote source without verify key to be rejected")
+	}
+
+	// Without a cache there is no revision floor, so a replayed older state
+	// would be accepted; the fetch must refuse instead.
+	serve(signedRevision(1), http.StatusOK)
+	if _, err := FetchKillSwitchState(context.Background(), KillSwitchFetchOptions{Source: server.URL, PublicKey: keyPair.Public, Now: now}); err == nil || !strings.Contains(err.Error(), "requires a cache path") {
+		t.Fatalf("expected remote source without cache to be rejected, got %v", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	sign "github.com/Clyra-AI/proof/signing"
 )
 
 func TestMatchKillSwitchSelectors(t *testing.T) {
@@ -209,3 +210,47 @@ func TestKillSwitchPrefixMatchesDriveLikeScopes(t *testing.T) {
 		t.Fatalf("expected drive-like workspace prefix to match absolute workspace")
 	}
 }
+
+func TestSignedKillSwitchStateRejectsTamperingAndRollback(t *testing.T) {
+	keyPair, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	now := time.Date(2026, time.May, 9, 12, 0, 0, 0, time.UTC)
+	state := NewKillSwitchState(now, "test")
+	state.Revision = 2
+	state.Entries = []schemagate.KillSwitchEntry{{EntryID: "tool", Enabled: true, ToolName: "tool.exec", CreatedAt: now}}
+	signed, err := SignKillSwitchState(state, keyPair.Private)
+	if err != nil {
+		t.Fatalf("sign kill switch state: %v", err)
+	}
+	if err := VerifyKillSwitchState(signed, keyPair.Public); err != nil {
+		t.Fatalf("verify kill switch state: %v", err)
+	}
+	tampered := signed
+	tampered.Entries = []schemagate.KillSwitchEntry{}
+	if err := VerifyKillSwitchState(tampered, keyPair.Public); err == nil {
+		t.Fatalf("expected cleared entries to fail verification")
+	}
+
+	statePath := filepath.Join(t.TempDir(), "kill_switch_state.json")
+	if err := WriteKillSwitchState(statePath, signed); err != nil {
+		t.Fatalf("write signed state: %v", err)
+	}
+	loaded, err := LoadKillSwitchState(statePath)
+	if err != nil || VerifyKillSwitchState(loaded, keyPair.Public) != nil {
+		t.Fatalf("expected written state to round-trip verification: %v", err)
+	}
+	for _, revision := range []int64{1, 2} {
+		older := state
+		older.Revision = revision
+		if err := WriteKillSwitchState(statePath, older); err == nil || !strings.Contains(err.Error(), "roll back") {
+			t.Fatalf("expected revision %d to be refused, got %v", revision, err)
+		}
+	}
+	newer := state
+	newer.Revision = 3
+	if err := WriteKillSwitchState(statePath, newer); err != nil {
+		t.Fatalf("write newer revision: %v", err)
+	}
+}
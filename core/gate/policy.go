@@ -3,7 +3,7 @@ package gate
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"math"
 	"path"
 	"path/filepath"
 	"sort"
@@ -11,8 +11,8 @@ import (
 	"time"
 
 	gaitjcs "github.com/Clyra-AI/proof/canon"
-	"github.com/goccy/go-yaml"
 
+	"github.com/Clyra-AI/gait/core/redact"
 	schemacontext "github.com/Clyra-AI/gait/core/schema/v1/context"
 	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
 )
@@ -65,10 +65,14 @@ var (
 		"tool":          {},
 		"identity":      {},
 		"tool_identity": {},
+		"workspace":     {},
+		"session":       {},
+		"target":        {},
 	}
-	allowedRateLimitWindows = map[string]struct{}{
-		"minute": {},
-		"hour":   {},
+	allowedRateLimitAlgorithms = map[string]struct{}{
+		"fixed_window":   {},
+		"sliding_window": {},
+		"token_bucket":   {},
 	}
 	allowedDataflowActions = map[string]struct{}{
 		"block":            {},
@@ -83,12 +87,15 @@ var (
 type Policy struct {
 	SchemaID       string           `yaml:"schema_id"`
 	SchemaVersion  string           `yaml:"schema_version"`
+	Extends        []string         `yaml:"extends,omitempty"`
 	DefaultVerdict string           `yaml:"default_verdict"`
 	DefaultAction  string           `yaml:"default_action"`
 	Scripts        ScriptPolicy     `yaml:"scripts"`
 	FailClosed     FailClosedPolicy `yaml:"fail_closed"`
 	MCPTrust       MCPTrustPolicy   `yaml:"mcp_trust"`
+	Redaction      redact.Config    `yaml:"redaction"`
 	Rules          []PolicyRule     `yaml:"rules"`
+	Sources        []PolicySource   `yaml:"-" json:"-"`
 	normalized     bool             `yaml:"-" json:"-"`
 }
 
@@ -121,6 +128,9 @@ type PolicyRule struct {
 	Priority                       int                `yaml:"priority"`
 	Effect                         string             `yaml:"effect"`
 	Action                         string             `yaml:"action"`
+	Locked                         bool               `yaml:"locked,omitempty"`
+	Override                       bool               `yaml:"override,omitempty"`
+	Source                         string             `yaml:"-"`
 	Match                          PolicyMatch        `yaml:"match"`
 	Endpoint                       EndpointPolicy     `yaml:"endpoint"`
 	ReasonCodes                    []string           `yaml:"reason_codes"`
@@ -152,6 +162,7 @@ type PolicyRule struct {
 	Sandbox                        SandboxPolicy      `yaml:"sandbox"`
 	RateLimit                      RateLimitPolicy    `yaml:"rate_limit"`
 	DestructiveBudget              RateLimitPolicy    `yaml:"destructive_budget"`
+	Cost                           float64            `yaml:"cost"`
 	Dataflow                       DataflowPolicy     `yaml:"dataflow"`
 }
 
@@ -183,9 +194,10 @@ type SandboxPolicy struct {
 }
 
 type RateLimitPolicy struct {
-	Requests int    `yaml:"requests"`
-	Window   string `yaml:"window"`
-	Scope    string `yaml:"scope"`
+	Requests  int    `yaml:"requests"`
+	Window    string `yaml:"window"`
+	Scope     string `yaml:"scope"`
+	Algorithm string `yaml:"algorithm"`
 }
 
 type DataflowPolicy struct {
@@ -240,6 +252,8 @@ type PolicyMatch struct {
 	AllowedDelegateIdentities  []string            `yaml:"allowed_delegate_identities"`
 	DelegationScopes           []string            `yaml:"delegation_scopes"`
 	MaxDelegationDepth         *int                `yaml:"max_delegation_depth"`
+	Args                       []ArgPredicate      `yaml:"args"`
+	Exec                       ExecMatch           `yaml:"exec"`
 }
 
 type ToolAnnotationMatch struct {
@@ -274,6 +288,7 @@ type EvalOutcome struct {
 	BrokerScopes             []string
 	RateLimit                RateLimitPolicy
 	DestructiveBudget        RateLimitPolicy
+	Cost                     float64
 	DataflowTriggered        bool
 	Script                   bool
 	StepCount                int
@@ -304,40 +319,12 @@ type matchedRuleEvaluation struct {
 	BrokerScopes             []string
 	RateLimit                RateLimitPolicy
 	DestructiveBudget        RateLimitPolicy
+	Cost                     float64
 	DataflowTriggered        bool
 	FreezeWindow             *schemagate.FreezeWindowDecision
 	Sandbox                  *schemagate.SandboxDecision
 }
 
-func LoadPolicyFile(path string) (Policy, error) {
-	// #nosec G304 -- policy path is explicit local user input.
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return Policy{}, fmt.Errorf("read policy: %w", err)
-	}
-	return ParsePolicyYAML(content)
-}
-
-func ParsePolicyYAML(data []byte) (Policy, error) {
-	legacyFields, err := detectLegacyPolicyFieldMigrations(data)
-	if err != nil {
-		return Policy{}, fmt.Errorf("parse policy yaml: %w", err)
-	}
-	if len(legacyFields) > 0 {
-		return Policy{}, fmt.Errorf("parse policy yaml: %w", LegacyPolicyContractError{Fields: legacyFields})
-	}
-
-	var policy Policy
-	if err := yaml.UnmarshalWithOptions(data, &policy, yaml.Strict(), yaml.DisallowUnknownField()); err != nil {
-		formatted := strings.TrimSpace(yaml.FormatError(err, false, false))
-		if formatted != "" {
-			return Policy{}, fmt.Errorf("parse policy yaml: %s", formatted)
-		}
-		return Policy{}, fmt.Errorf("parse policy yaml: %w", err)
-	}
-	return normalizePolicy(policy)
-}
-
 func normalizedPolicy(input Policy) (Policy, error) {
 	if input.normalized {
 		return input, nil
@@ -436,6 +423,7 @@ func EvaluatePolicyDetailed(policy Policy, intent schemagate.IntentRequest, opts
 		if err != nil {
 			return EvalOutcome{}, err
 		}
+		outcome = applyExecCommandOutcome(normalizedPolicy, normalizedIntent, outcome)
 		return applyKillSwitchOutcome(outcome, opts), nil
 	}
 	enrichedIntent := normalizedIntent
@@ -451,6 +439,7 @@ func EvaluatePolicyDetailed(policy Policy, intent schemagate.IntentRequest, opts
 	if contextApplied {
 		outcome.ContextSource = mergeContextSource(outcome.ContextSource, resolveWrkrSource(opts.WrkrSource))
 	}
+	outcome = applyExecCommandOutcome(normalizedPolicy, enrichedIntent, outcome)
 	return applyKillSwitchOutcome(outcome, opts), nil
 }
 
@@ -458,15 +447,17 @@ func evaluateSingleIntent(policy Policy, intent schemagate.IntentRequest, opts E
 	matchedRules := make([]PolicyRule, 0, 1)
 	matchedPriority := 0
 	for _, rule := range policy.Rules {
+		// Locked rules always take part so a higher-priority rule from an
+		// extending policy cannot shadow them.
+		if len(matchedRules) > 0 && rule.Priority != matchedPriority && !rule.Locked {
+			continue
+		}
 		if !ruleMatches(rule.Match, intent) {
 			continue
 		}
 		if len(matchedRules) == 0 {
 			matchedPriority = rule.Priority
 		}
-		if rule.Priority != matchedPriority {
-			break
-		}
 		matchedRules = append(matchedRules, rule)
 	}
 
@@ -492,6 +483,7 @@ func evaluateSingleIntent(policy Policy, intent schemagate.IntentRequest, opts E
 		brokerScopes := []string{}
 		rateLimit := RateLimitPolicy{}
 		destructiveBudget := RateLimitPolicy{}
+		cost := 0.0
 		dataflowTriggered := false
 		var freezeWindow *schemagate.FreezeWindowDecision
 		var sandbox *schemagate.SandboxDecision
@@ -512,6 +504,7 @@ func evaluateSingleIntent(policy Policy, intent schemagate.IntentRequest, opts E
 			brokerScopes = mergeUniqueSorted(brokerScopes, evaluation.BrokerScopes)
 			rateLimit = mostRestrictiveRateLimitPolicy(rateLimit, evaluation.RateLimit)
 			destructiveBudget = mostRestrictiveRateLimitPolicy(destructiveBudget, evaluation.DestructiveBudget)
+			cost = math.Max(cost, evaluation.Cost)
 			dataflowTriggered = dataflowTriggered || evaluation.DataflowTriggered
 			freezeWindow = pickFreezeWindowDecision(freezeWindow, evaluation.FreezeWindow)
 			sandbox = pickSandboxDecision(sandbox, evaluation.Sandbox)
@@ -533,6 +526,7 @@ func evaluateSingleIntent(policy Policy, intent schemagate.IntentRequest, opts E
 			BrokerScopes:             uniqueSorted(brokerScopes),
 			RateLimit:                rateLimit,
 			DestructiveBudget:        destructiveBudget,
+			Cost:                     cost,
 			DataflowTriggered:        dataflowTriggered,
 			FreezeWindow:             freezeWindow,
 			Sandbox:                  sandbox,
@@ -615,7 +609,7 @@ func evaluateMatchedRule(rule PolicyRule, intent schemagate.IntentRequest, now t
 		violations = mergeUniqueSorted(violations, sandboxViolations)
 	}
 	destructiveTarget := intentContainsDestructiveTarget(intent.Targets)
-	if sandbox != nil && sandbox.Status == "valid" && targetsOnlyUseProcExec(intent.Targets) {
+	if sandbox != nil && sandbox.Status == "valid" && destructiveTargetsOnlyUseProcExec(intent.Targets) {
 		destructiveTarget = false
 	}
 	switch strings.ToLower(strings.TrimSpace(intent.Context.Phase)) {
@@ -649,6 +643,7 @@ func evaluateMatchedRule(rule PolicyRule, intent schemagate.IntentRequest, now t
 		BrokerScopes:             uniqueSorted(rule.BrokerScopes),
 		RateLimit:                rule.RateLimit,
 		DestructiveBudget:        rule.DestructiveBudget,
+		Cost:                     rule.Cost,
 		DataflowTriggered:        dataflowTriggered,
 		FreezeWindow:             freezeWindow,
 		Sandbox:                  sandbox,
@@ -677,6 +672,7 @@ func evaluateScriptPolicyDetailed(policy Policy, intent schemagate.IntentRequest
 	riskClasses := []string{}
 	aggregatedRateLimit := RateLimitPolicy{}
 	aggregatedDestructiveBudget := RateLimitPolicy{}
+	totalCost := 0.0
 	contextSource := ""
 	if opts.VerifiedContextEnvelope != nil {
 		contextSource = mergeContextSource(contextSource, verifiedContextSource)
@@ -732,6 +728,7 @@ func evaluateScriptPolicyDetailed(policy Policy, intent schemagate.IntentRequest
 		brokerScopes = mergeUniqueSorted(brokerScopes, stepOutcome.BrokerScopes)
 		aggregatedRateLimit = mergeRateLimitPolicy(aggregatedRateLimit, stepOutcome.RateLimit)
 		aggregatedDestructiveBudget = mergeRateLimitPolicy(aggregatedDestructiveBudget, stepOutcome.DestructiveBudget)
+		totalCost += stepOutcome.Cost
 		if stepOutcome.DataflowTriggered {
 			dataflowTriggered = true
 		}
@@ -784,6 +781,7 @@ func evaluateScriptPolicyDetailed(policy Policy, intent schemagate.IntentRequest
 		BrokerScopes:             uniqueSorted(brokerScopes),
 		RateLimit:                aggregatedRateLimit,
 		DestructiveBudget:        aggregatedDestructiveBudget,
+		Cost:                     totalCost,
 		DataflowTriggered:        dataflowTriggered,
 		PreparedIntent:           intent,
 		Script:                   true,
@@ -954,6 +952,12 @@ func policyDigestPayload(policy Policy) map[string]any {
 		if rule.Match.MaxDelegationDepth != nil {
 			matchPayload["MaxDelegationDepth"] = *rule.Match.MaxDelegationDepth
 		}
+		if len(rule.Match.Args) > 0 {
+			matchPayload["Args"] = argPredicateDigestPayload(rule.Match.Args)
+		}
+		if execPayload, ok := execMatchDigestPayload(rule.Match.Exec); ok {
+			matchPayload["Exec"] = execPayload
+		}
 
 		rulePayload := map[string]any{
 			"Name":        rule.Name,
@@ -966,6 +970,9 @@ func policyDigestPayload(policy Policy) map[string]any {
 		if rule.MinApprovals > 0 {
 			rulePayload["MinApprovals"] = rule.MinApprovals
 		}
+		if rule.Locked {
+			rulePayload["Locked"] = true
+		}
 		if rule.RequireDeclaredAgent {
 			rulePayload["RequireDeclaredAgent"] = rule.RequireDeclaredAgent
 		}
@@ -1066,18 +1073,29 @@ func policyDigestPayload(policy Policy) map[string]any {
 			rulePayload["Sandbox"] = sandboxPayload
 		}
 		if rule.RateLimit.Requests > 0 {
-			rulePayload["RateLimit"] = map[string]any{
+			rateLimitPayload := map[string]any{
 				"Requests": rule.RateLimit.Requests,
 				"Window":   rule.RateLimit.Window,
 				"Scope":    rule.RateLimit.Scope,
 			}
+			if algorithm := rule.RateLimit.Algorithm; algorithm != "" && algorithm != "fixed_window" {
+				rateLimitPayload["Algorithm"] = algorithm
+			}
+			rulePayload["RateLimit"] = rateLimitPayload
 		}
 		if rule.DestructiveBudget.Requests > 0 {
-			rulePayload["DestructiveBudget"] = map[string]any{
+			destructiveBudgetPayload := map[string]any{
 				"Requests": rule.DestructiveBudget.Requests,
 				"Window":   rule.DestructiveBudget.Window,
 				"Scope":    rule.DestructiveBudget.Scope,
 			}
+			if algorithm := rule.DestructiveBudget.Algorithm; algorithm != "" && algorithm != "fixed_window" {
+				destructiveBudgetPayload["Algorithm"] = algorithm
+			}
+			rulePayload["DestructiveBudget"] = destructiveBudgetPayload
+		}
+		if rule.Cost > 0 {
+			rulePayload["Cost"] = rule.Cost
 		}
 		if rule.Dataflow.Enabled {
 			dataflowPayload := map[string]any{
@@ -1151,6 +1169,14 @@ func policyDigestPayload(policy Policy) map[string]any {
 		}
 		payload["MCPTrust"] = mcpTrustPayload
 	}
+	if policy.Redaction.Enabled() {
+		payload["Redaction"] = map[string]any{
+			"Paths":     policy.Redaction.Paths,
+			"Detectors": policy.Redaction.Detectors,
+			"PII":       policy.Redaction.PII,
+			"SaltEnv":   policy.Redaction.SaltEnv,
+		}
+	}
 	return payload
 }
 
@@ -1232,6 +1258,11 @@ func normalizePolicy(input Policy) (Policy, error) {
 			return Policy{}, fmt.Errorf("unsupported fail_closed required_high_risk_field: %s", field)
 		}
 	}
+	redaction, err := output.Redaction.Normalize()
+	if err != nil {
+		return Policy{}, fmt.Errorf("redaction: %w", err)
+	}
+	output.Redaction = redaction
 	output.MCPTrust.SnapshotPath = strings.TrimSpace(output.MCPTrust.SnapshotPath)
 	output.MCPTrust.Action = strings.ToLower(strings.TrimSpace(output.MCPTrust.Action))
 	output.MCPTrust.RequiredRiskClasses = normalizeStringListLower(output.MCPTrust.RequiredRiskClasses)
@@ -1342,6 +1373,16 @@ func normalizePolicy(input Policy) (Policy, error) {
 		if rule.Match.MaxDelegationDepth != nil && *rule.Match.MaxDelegationDepth < 0 {
 			return Policy{}, fmt.Errorf("max_delegation_depth must be >= 0 for %s", rule.Name)
 		}
+		argPredicates, err := normalizeArgPredicates(rule.Match.Args, rule.Name)
+		if err != nil {
+			return Policy{}, err
+		}
+		rule.Match.Args = argPredicates
+		execMatch, err := normalizeExecMatch(rule.Match.Exec, rule.Name)
+		if err != nil {
+			return Policy{}, err
+		}
+		rule.Match.Exec = execMatch
 		rule.AllowedAgentIDs = normalizeStringList(rule.AllowedAgentIDs)
 		rule.DeniedAgentIDs = normalizeStringList(rule.DeniedAgentIDs)
 		rule.RequiredAgentManifestDigest = strings.ToLower(strings.TrimSpace(rule.RequiredAgentManifestDigest))
@@ -1481,13 +1522,20 @@ func normalizePolicy(input Policy) (Policy, error) {
 		}
 		rule.RateLimit.Window = strings.ToLower(strings.TrimSpace(rule.RateLimit.Window))
 		rule.RateLimit.Scope = strings.ToLower(strings.TrimSpace(rule.RateLimit.Scope))
+		rule.RateLimit.Algorithm = strings.ToLower(strings.TrimSpace(rule.RateLimit.Algorithm))
 		if rule.RateLimit.Requests > 0 {
 			if rule.RateLimit.Window == "" {
 				rule.RateLimit.Window = "minute"
 			}
-			if _, ok := allowedRateLimitWindows[rule.RateLimit.Window]; !ok {
+			if _, err := rateLimitWindowDuration(rule.RateLimit.Window); err != nil {
 				return Policy{}, fmt.Errorf("unsupported rate_limit.window %q for %s", rule.RateLimit.Window, rule.Name)
 			}
+			if rule.RateLimit.Algorithm == "" {
+				rule.RateLimit.Algorithm = "fixed_window"
+			}
+			if _, ok := allowedRateLimitAlgorithms[rule.RateLimit.Algorithm]; !ok {
+				return Policy{}, fmt.Errorf("unsupported rate_limit.algorithm %q for %s", rule.RateLimit.Algorithm, rule.Name)
+			}
 			if rule.RateLimit.Scope == "" {
 				rule.RateLimit.Scope = "tool_identity"
 			}
@@ -1498,11 +1546,16 @@ func normalizePolicy(input Policy) (Policy, error) {
 		if rule.DestructiveBudget.Requests < 0 {
 			return Policy{}, fmt.Errorf("destructive_budget.requests must be >= 0 for %s", rule.Name)
 		}
+		if math.IsNaN(rule.Cost) || math.IsInf(rule.Cost, 0) || rule.Cost < 0 {
+			return Policy{}, fmt.Errorf("cost must be a finite value >= 0 for %s", rule.Name)
+		}
 		rule.DestructiveBudget.Window = strings.ToLower(strings.TrimSpace(rule.DestructiveBudget.Window))
 		rule.DestructiveBudget.Scope = strings.ToLower(strings.TrimSpace(rule.DestructiveBudget.Scope))
+		rule.DestructiveBudget.Algorithm = strings.ToLower(strings.TrimSpace(rule.DestructiveBudget.Algorithm))
 		destructiveBudgetConfigured := rule.DestructiveBudget.Requests > 0 ||
 			rule.DestructiveBudget.Window != "" ||
-			rule.DestructiveBudget.Scope != ""
+			rule.DestructiveBudget.Scope != "" ||
+			rule.DestructiveBudget.Algorithm != ""
 		if destructiveBudgetConfigured {
 			if rule.DestructiveBudget.Requests <= 0 {
 				return Policy{}, fmt.Errorf("destructive_budget.requests must be >= 1 for %s", rule.Name)
@@ -1510,9 +1563,15 @@ func normalizePolicy(input Policy) (Policy, error) {
 			if rule.DestructiveBudget.Window == "" {
 				rule.DestructiveBudget.Window = "minute"
 			}
-			if _, ok := allowedRateLimitWindows[rule.DestructiveBudget.Window]; !ok {
+			if _, err := rateLimitWindowDuration(rule.DestructiveBudget.Window); err != nil {
 				return Policy{}, fmt.Errorf("unsupported destructive_budget.window %q for %s", rule.DestructiveBudget.Window, rule.Name)
 			}
+			if rule.DestructiveBudget.Algorithm == "" {
+				rule.DestructiveBudget.Algorithm = "fixed_window"
+			}
+			if _, ok := allowedRateLimitAlgorithms[rule.DestructiveBudget.Algorithm]; !ok {
+				return Policy{}, fmt.Errorf("unsupported destructive_budget.algorithm %q for %s", rule.DestructiveBudget.Algorithm, rule.Name)
+			}
 			if rule.DestructiveBudget.Scope == "" {
 				rule.DestructiveBudget.Scope = "tool_identity"
 			}
@@ -1604,10 +1663,49 @@ func normalizePolicy(input Policy) (Policy, error) {
 	return output, nil
 }
 
+// RulesMatchingIntent returns every rule whose match clause accepts the
+// prepared intent, ignoring priority. Script intents are checked step by step.
+func RulesMatchingIntent(policy Policy, intent schemagate.IntentRequest) ([]string, error) {
+	normalizedPolicy, err := normalizedPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	candidates := []schemagate.IntentRequest{intent}
+	if intent.Script != nil {
+		candidates = make([]schemagate.IntentRequest, 0, len(intent.Script.Steps))
+		for _, step := range intent.Script.Steps {
+			stepIntent := intent
+			stepIntent.Script = nil
+			stepIntent.ScriptHash = ""
+			stepIntent.ToolName = step.ToolName
+			stepIntent.Args = step.Args
+			stepIntent.Targets = step.Targets
+			stepIntent.ArgProvenance = step.ArgProvenance
+			candidates = append(candidates, stepIntent)
+		}
+	}
+	names := []string{}
+	for _, rule := range normalizedPolicy.Rules {
+		for _, candidate := range candidates {
+			if ruleMatches(rule.Match, candidate) {
+				names = append(names, rule.Name)
+				break
+			}
+		}
+	}
+	return names, nil
+}
+
 func ruleMatches(match PolicyMatch, intent schemagate.IntentRequest) bool {
 	if len(match.ToolNames) > 0 && !contains(match.ToolNames, intent.ToolName) {
 		return false
 	}
+	if len(match.Args) > 0 && !argPredicatesMatch(match.Args, intent.Args) {
+		return false
+	}
+	if !execMatches(match.Exec, intent) {
+		return false
+	}
 	if len(match.RiskClasses) > 0 && !contains(match.RiskClasses, intent.Context.RiskClass) {
 		return false
 	}
@@ -2280,16 +2378,21 @@ func IntentContainsDestructiveTarget(targets []schemagate.IntentTarget) bool {
 	return intentContainsDestructiveTarget(targets)
 }
 
-func targetsOnlyUseProcExec(targets []schemagate.IntentTarget) bool {
-	if len(targets) == 0 {
-		return false
-	}
+// destructiveTargetsOnlyUseProcExec reports whether every destructive target is
+// a proc.exec target, so files read or written by a sandboxed command keep the
+// sandbox exemption while files it deletes cancel it.
+func destructiveTargetsOnlyUseProcExec(targets []schemagate.IntentTarget) bool {
+	destructive := false
 	for _, target := range targets {
+		if !target.Destructive {
+			continue
+		}
+		destructive = true
 		if strings.ToLower(strings.TrimSpace(target.EndpointClass)) != "proc.exec" {
 			return false
 		}
 	}
-	return true
+	return destructive
 }
 
 func matchesAnyPattern(value string, patterns []string) bool {
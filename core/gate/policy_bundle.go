@@ -0,0 +1,453 @@
+package gate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/Clyra-AI/gait/core/registry"
+)
+
+const (
+	maxPolicyExtendsDepth   = 8
+	registryPolicyRefPrefix = "registry:"
+)
+
+type PolicyLoadOptions struct {
+	RegistryCacheDir string
+}
+
+// PolicySource records one file that contributed to a resolved policy.
+type PolicySource struct {
+	Ref    string `json:"ref"`
+	Path   string `json:"path,omitempty"`
+	SHA256 string `json:"sha256"`
+}
+
+type policyBundleLoader struct {
+	options PolicyLoadOptions
+	rootDir string
+	chain   []string
+	sources []PolicySource
+	seen    map[string]struct{}
+}
+
+type policyBundleFile struct {
+	key     string
+	label   string
+	path    string
+	dir     string
+	content []byte
+}
+
+func LoadPolicyFile(path string) (Policy, error) {
+	return LoadPolicyFileWithOptions(path, PolicyLoadOptions{})
+}
+
+// LoadPolicyFileWithOptions loads a policy and everything it extends, merges
+// the layers parent-first and normalizes the resolved bundle.
+func LoadPolicyFileWithOptions(path string, options PolicyLoadOptions) (Policy, error) {
+	// #nosec G304 -- policy path is explicit local user input.
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Policy{}, fmt.Errorf("read policy: %w", err)
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return Policy{}, fmt.Errorf("resolve policy path: %w", err)
+	}
+	loader := &policyBundleLoader{
+		options: options,
+		rootDir: filepath.Dir(absPath),
+		seen:    map[string]struct{}{},
+	}
+	resolved, err := loader.resolve(policyBundleFile{
+		key:     absPath,
+		label:   filepath.Base(absPath),
+		path:    path,
+		dir:     filepath.Dir(absPath),
+		content: content,
+	})
+	if err != nil {
+		return Policy{}, err
+	}
+	policy, err := normalizePolicy(resolved)
+	if err != nil {
+		return Policy{}, err
+	}
+	policy.Sources = loader.sources
+	return policy, nil
+}
+
+func ParsePolicyYAML(data []byte) (Policy, error) {
+	layer, err := decodePolicyLayer(data)
+	if err != nil {
+		return Policy{}, err
+	}
+	if len(layer.Extends) > 0 {
+		return Policy{}, fmt.Errorf("parse policy yaml: extends requires loading the policy from a file")
+	}
+	merged, err := mergePolicyLayers(Policy{}, layer, "policy")
+	if err != nil {
+		return Policy{}, err
+	}
+	return normalizePolicy(merged)
+}
+
+// ParsePolicyLayerYAML parses and normalizes one policy file on its own,
+// keeping extends references unresolved. It is meant for formatting, not
+// evaluation.
+func ParsePolicyLayerYAML(data []byte) (Policy, error) {
+	layer, err := decodePolicyLayer(data)
+	if err != nil {
+		return Policy{}, err
+	}
+	return normalizePolicy(layer)
+}
+
+func decodePolicyLayer(data []byte) (Policy, error) {
+	legacyFields, err := detectLegacyPolicyFieldMigrations(data)
+	if err != nil {
+		return Policy{}, fmt.Errorf("parse policy yaml: %w", err)
+	}
+	if len(legacyFields) > 0 {
+		return Policy{}, fmt.Errorf("parse policy yaml: %w", LegacyPolicyContractError{Fields: legacyFields})
+	}
+
+	var policy Policy
+	if err := yaml.UnmarshalWithOptions(data, &policy, yaml.Strict(), yaml.DisallowUnknownField()); err != nil {
+		formatted := strings.TrimSpace(yaml.FormatError(err, false, false))
+		if formatted != "" {
+			return Policy{}, fmt.Errorf("parse policy yaml: %s", formatted)
+		}
+		return Policy{}, fmt.Errorf("parse policy yaml: %w", err)
+	}
+	policy.Extends = normalizeStringList(policy.Extends)
+	return policy, nil
+}
+
+func (loader *policyBundleLoader) resolve(file policyBundleFile) (Policy, error) {
+	for _, key := range loader.chain {
+		if key == file.key {
+			return Policy{}, fmt.Errorf("policy extends cycle at %s", file.label)
+		}
+	}
+	if len(loader.chain) > maxPolicyExtendsDepth {
+		return Policy{}, fmt.Errorf("policy extends depth exceeds %d at %s", maxPolicyExtendsDepth, file.label)
+	}
+	loader.chain = append(loader.chain, file.key)
+	defer func() {
+		loader.chain = loader.chain[:len(loader.chain)-1]
+	}()
+
+	layer, err := decodePolicyLayer(file.content)
+	if err != nil {
+		if len(loader.chain) > 1 {
+			return Policy{}, fmt.Errorf("extends %s: %w", file.label, err)
+		}
+		return Policy{}, err
+	}
+	for index := range layer.Rules {
+		layer.Rules[index].Source = file.label
+	}
+
+	merged := Policy{}
+	for _, ref := range layer.Extends {
+		parentFile, err := loader.fetch(ref, file)
+		if err != nil {
+			return Policy{}, fmt.Errorf("extends %s from %s: %w", ref, file.label, err)
+		}
+		parent, err := loader.resolve(parentFile)
+		if err != nil {
+			return Policy{}, err
+		}
+		merged, err = mergePolicyLayers(merged, parent, parentFile.label)
+		if err != nil {
+			return Policy{}, err
+		}
+	}
+	merged, err = mergePolicyLayers(merged, layer, file.label)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	if _, ok := loader.seen[file.key]; !ok {
+		loader.seen[file.key] = struct{}{}
+		sum := sha256.Sum256(file.content)
+		loader.sources = append(loader.sources, PolicySource{
+			Ref:    file.label,
+			Path:   file.path,
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+	}
+	return merged, nil
+}
+
+func (loader *policyBundleLoader) fetch(ref string, parent policyBundleFile) (policyBundleFile, error) {
+	ref = strings.TrimSpace(ref)
+	if strings.HasPrefix(ref, registryPolicyRefPrefix) {
+		packName, artifactPath, _ := strings.Cut(strings.TrimPrefix(ref, registryPolicyRefPrefix), "#")
+		artifact, err := registry.ResolveArtifact(registry.ArtifactOptions{
+			CacheDir:     loader.options.RegistryCacheDir,
+			PackName:     packName,
+			ArtifactPath: artifactPath,
+			Extensions:   []string{".yaml", ".yml"},
+		})
+		if err != nil {
+			return policyBundleFile{}, err
+		}
+		label := registryPolicyRefPrefix + artifact.PackName + "@" + artifact.PackVersion
+		if strings.TrimSpace(artifactPath) != "" {
+			label += "#" + artifact.ArtifactPath
+		}
+		return policyBundleFile{
+			key:     registryPolicyRefPrefix + artifact.Digest + "#" + artifact.ArtifactPath,
+			label:   label,
+			path:    artifact.Path,
+			content: artifact.Content,
+		}, nil
+	}
+	if parent.dir == "" {
+		return policyBundleFile{}, fmt.Errorf("registry policies may only extend other registry policies")
+	}
+	path := filepath.FromSlash(ref)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(parent.dir, path)
+	}
+	// #nosec G304 -- extends path is explicit local policy input.
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return policyBundleFile{}, fmt.Errorf("read policy: %w", err)
+	}
+	label := path
+	if relative, err := filepath.Rel(loader.rootDir, path); err == nil {
+		label = filepath.ToSlash(relative)
+	}
+	return policyBundleFile{
+		key:     filepath.Clean(path),
+		label:   label,
+		path:    path,
+		dir:     filepath.Dir(path),
+		content: content,
+	}, nil
+}
+
+// mergePolicyLayers applies overlay, read from label, on top of base.
+// Top-level sections set in overlay replace the inherited ones, but
+// default_verdict, fail_closed, mcp_trust and redaction may only be
+// tightened. Rules are merged by name and replacing an inherited rule requires
+// override, which locked rules only allow when the replacement is at least as
+// restrictive.
+func mergePolicyLayers(base Policy, overlay Policy, label string) (Policy, error) {
+	if err := checkPolicyDefaultsOverride(base, overlay, label); err != nil {
+		return Policy{}, err
+	}
+	merged := base
+	merged.Extends = overlay.Extends
+	if strings.TrimSpace(overlay.SchemaID) != "" {
+		merged.SchemaID = overlay.SchemaID
+	}
+	if strings.TrimSpace(overlay.SchemaVersion) != "" {
+		merged.SchemaVersion = overlay.SchemaVersion
+	}
+	if strings.TrimSpace(overlay.DefaultVerdict) != "" || strings.TrimSpace(overlay.DefaultAction) != "" {
+		merged.DefaultVerdict = overlay.DefaultVerdict
+		merged.DefaultAction = overlay.DefaultAction
+	}
+	if !reflect.ValueOf(overlay.Scripts).IsZero() {
+		merged.Scripts = overlay.Scripts
+	}
+	if !reflect.ValueOf(overlay.FailClosed).IsZero() {
+		merged.FailClosed = overlay.FailClosed
+	}
+	if !reflect.ValueOf(overlay.MCPTrust).IsZero() {
+		merged.MCPTrust = overlay.MCPTrust
+	}
+	if !reflect.ValueOf(overlay.Redaction).IsZero() {
+		merged.Redaction = overlay.Redaction
+	}
+
+	inherited := make(map[string]int, len(base.Rules))
+	for index, rule := range base.Rules {
+		inherited[strings.TrimSpace(rule.Name)] = index
+	}
+	rules := append([]PolicyRule(nil), base.Rules...)
+	for _, rule := range overlay.Rules {
+		name := strings.TrimSpace(rule.Name)
+		position, exists := inherited[name]
+		if name == "" || !exists {
+			if rule.Override {
+				return Policy{}, fmt.Errorf("rule %s in %s sets override but no inherited rule has that name", name, ruleSourceLabel(rule))
+			}
+			rules = append(rules, rule)
+			continue
+		}
+		current := rules[position]
+		if current.Source == rule.Source && reflect.DeepEqual(current, rule) {
+			continue
+		}
+		if !rule.Override {
+			return Policy{}, fmt.Errorf("rule %s in %s redefines the rule from %s; set override: true to replace it", name, ruleSourceLabel(rule), ruleSourceLabel(current))
+		}
+		if current.Locked {
+			if err := checkLockedRuleOverride(current, rule); err != nil {
+				return Policy{}, err
+			}
+			rule.Locked = true
+		}
+		rules[position] = rule
+	}
+	merged.Rules = rules
+	return merged, nil
+}
+
+func checkLockedRuleOverride(locked PolicyRule, candidate PolicyRule) error {
+	lockedPolicy, err := normalizePolicy(Policy{Rules: []PolicyRule{locked}})
+	if err != nil {
+		return err
+	}
+	candidatePolicy, err := normalizePolicy(Policy{Rules: []PolicyRule{candidate}})
+	if err != nil {
+		return err
+	}
+	before := lockedPolicy.Rules[0]
+	after := candidatePolicy.Rules[0]
+	if mostRestrictiveVerdict(before.Effect, after.Effect) != after.Effect || after.MinApprovals < before.MinApprovals {
+		return fmt.Errorf(
+			"rule %s is locked by %s and cannot be relaxed by %s (effect %s -> %s)",
+			before.Name, ruleSourceLabel(locked), ruleSourceLabel(candidate), before.Effect, after.Effect,
+		)
+	}
+	for _, rule := range []*PolicyRule{&before, &after} {
+		rule.Effect = ""
+		rule.Action = ""
+		rule.MinApprovals = 0
+		rule.ReasonCodes = nil
+		rule.Violations = nil
+		rule.Locked = false
+		rule.Override = false
+		rule.Source = ""
+	}
+	if !reflect.DeepEqual(before, after) {
+		return fmt.Errorf(
+			"rule %s is locked by %s; %s may only tighten effect, min_approvals, reason_codes or violations",
+			locked.Name, ruleSourceLabel(locked), ruleSourceLabel(candidate),
+		)
+	}
+	return nil
+}
+
+// checkPolicyDefaultsOverride rejects overlay sections that would relax what
+// base already enforces. Sections base leaves unset may be set to anything.
+func checkPolicyDefaultsOverride(base Policy, overlay Policy, label string) error {
+	relaxed := func(section string, detail string) error {
+		return fmt.Errorf("%s in %s cannot relax the inherited %s (%s)", section, label, section, detail)
+	}
+
+	if beforeVerdict, afterVerdict := layerDefaultVerdict(base), layerDefaultVerdict(overlay); beforeVerdict != "" && afterVerdict != "" {
+		if mostRestrictiveVerdict(beforeVerdict, afterVerdict) != afterVerdict {
+			return relaxed("default_verdict", beforeVerdict+" -> "+afterVerdict)
+		}
+	}
+
+	if !reflect.ValueOf(base.FailClosed).IsZero() && !reflect.ValueOf(overlay.FailClosed).IsZero() {
+		beforePolicy, err := normalizePolicy(Policy{FailClosed: base.FailClosed})
+		if err != nil {
+			return err
+		}
+		afterPolicy, err := normalizePolicy(Policy{FailClosed: overlay.FailClosed})
+		if err != nil {
+			return err
+		}
+		before, after := beforePolicy.FailClosed, afterPolicy.FailClosed
+		switch {
+		case before.Enabled && !after.Enabled:
+			return relaxed("fail_closed", "enabled true -> false")
+		case !containsAll(after.RiskClasses, before.RiskClasses):
+			return relaxed("fail_closed", "risk_classes must include "+strings.Join(before.RiskClasses, ","))
+		case !containsAll(after.RequiredFields, before.RequiredFields):
+			return relaxed("fail_closed", "required_fields must include "+strings.Join(before.RequiredFields, ","))
+		case !containsAll(after.RequiredHighRiskFields, before.RequiredHighRiskFields):
+			return relaxed("fail_closed", "required_high_risk_fields must include "+strings.Join(before.RequiredHighRiskFields, ","))
+		}
+	}
+
+	if !reflect.ValueOf(base.MCPTrust).IsZero() && !reflect.ValueOf(overlay.MCPTrust).IsZero() {
+		beforePolicy, err := normalizePolicy(Policy{MCPTrust: base.MCPTrust})
+		if err != nil {
+			return err
+		}
+		afterPolicy, err := normalizePolicy(Policy{MCPTrust: overlay.MCPTrust})
+		if err != nil {
+			return err
+		}
+		before, after := beforePolicy.MCPTrust, afterPolicy.MCPTrust
+		switch {
+		case before.Enabled && !after.Enabled:
+			return relaxed("mcp_trust", "enabled true -> false")
+		case before.SnapshotPath != after.SnapshotPath:
+			return relaxed("mcp_trust", "snapshot "+before.SnapshotPath+" -> "+after.SnapshotPath)
+		case mostRestrictiveVerdict(before.Action, after.Action) != after.Action:
+			return relaxed("mcp_trust", "action "+before.Action+" -> "+after.Action)
+		case !containsAll(after.RequiredRiskClasses, before.RequiredRiskClasses):
+			return relaxed("mcp_trust", "required_risk_classes must include "+strings.Join(before.RequiredRiskClasses, ","))
+		case after.MinScore < before.MinScore:
+			return relaxed("mcp_trust", fmt.Sprintf("min_score %g -> %g", before.MinScore, after.MinScore))
+		case before.RequireRegistry && !after.RequireRegistry:
+			return relaxed("mcp_trust", "require_registry true -> false")
+		case len(before.PublisherAllowlist) > 0 && (len(after.PublisherAllowlist) == 0 || !containsAll(before.PublisherAllowlist, after.PublisherAllowlist)):
+			return relaxed("mcp_trust", "publisher_allowlist must stay within "+strings.Join(before.PublisherAllowlist, ","))
+		}
+		if before.MaxAge != "" {
+			beforeAge, _ := time.ParseDuration(before.MaxAge)
+			afterAge, err := time.ParseDuration(after.MaxAge)
+			if after.MaxAge == "" || err != nil || afterAge > beforeAge {
+				return relaxed("mcp_trust", "max_age "+before.MaxAge+" -> "+after.MaxAge)
+			}
+		}
+	}
+
+	if !reflect.ValueOf(base.Redaction).IsZero() && !reflect.ValueOf(overlay.Redaction).IsZero() {
+		before, err := base.Redaction.Normalize()
+		if err != nil {
+			return fmt.Errorf("redaction: %w", err)
+		}
+		after, err := overlay.Redaction.Normalize()
+		if err != nil {
+			return fmt.Errorf("redaction: %w", err)
+		}
+		switch {
+		case !containsAll(after.Paths, before.Paths):
+			return relaxed("redaction", "paths must include "+strings.Join(before.Paths, ","))
+		case !containsAll(after.Detectors, before.Detectors):
+			return relaxed("redaction", "detectors must include "+strings.Join(before.Detectors, ","))
+		case !containsAll(after.PII, before.PII):
+			return relaxed("redaction", "pii must include "+strings.Join(before.PII, ","))
+		case before.SaltEnv != "" && after.SaltEnv != before.SaltEnv:
+			return relaxed("redaction", "salt_env "+before.SaltEnv+" -> "+after.SaltEnv)
+		}
+	}
+	return nil
+}
+
+func layerDefaultVerdict(policy Policy) string {
+	verdict := strings.ToLower(strings.TrimSpace(policy.DefaultVerdict))
+	if verdict == "" {
+		verdict = strings.ToLower(strings.TrimSpace(policy.DefaultAction))
+	}
+	return verdict
+}
+
+func ruleSourceLabel(rule PolicyRule) string {
+	if strings.TrimSpace(rule.Source) == "" {
+		return "policy"
+	}
+	return rule.Source
+}
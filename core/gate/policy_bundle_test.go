@@ -0,0 +1,375 @@
+package gate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/registry"
+	schemaregistry "github.com/Clyra-AI/gait/core/schema/v1/registry"
+	jcs "github.com/Clyra-AI/proof/canon"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+const bundleBaselinePolicy = `default_verdict: block
+fail_closed:
+  enabled: true
+rules:
+  - name: block-secrets
+    priority: 10
+    effect: block
+    locked: true
+    match:
+      tool_names: [tool.secrets]
+    reason_codes: [org_secrets_blocked]
+  - name: approve-write
+    priority: 20
+    effect: require_approval
+    match:
+      tool_names: [tool.write]
+`
+
+func TestLoadPolicyFileResolvesExtendsWithProvenance(t *testing.T) {
+	workDir := t.TempDir()
+	mustWriteBundleFile(t, filepath.Join(workDir, "org", "baseline.yaml"), bundleBaselinePolicy)
+	repoPolicyPath := filepath.Join(workDir, "repo", "gait.policy.yaml")
+	mustWriteBundleFile(t, repoPolicyPath, `extends:
+  - ../org/baseline.yaml
+rules:
+  - name: allow-all-repo-tools
+    priority: 1
+    effect: allow
+    match:
+      tool_names: [tool.secrets, tool.read]
+  - name: approve-write
+    priority: 20
+    effect: allow
+    override: true
+    match:
+      tool_names: [tool.write]
+`)
+
+	policy, err := LoadPolicyFile(repoPolicyPath)
+	if err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+	if policy.DefaultVerdict != "block" || !policy.FailClosed.Enabled {
+		t.Fatalf("expected inherited default verdict and fail_closed, got %#v", policy)
+	}
+	if len(policy.Sources) != 2 || policy.Sources[0].Ref != "../org/baseline.yaml" || policy.Sources[1].Ref != "gait.policy.yaml" {
+		t.Fatalf("unexpected policy sources: %#v", policy.Sources)
+	}
+	sources := map[string]string{}
+	for _, rule := range policy.Rules {
+		sources[rule.Name] = rule.Source
+	}
+	if sources["block-secrets"] != "../org/baseline.yaml" || sources["approve-write"] != "gait.policy.yaml" || sources["allow-all-repo-tools"] != "gait.policy.yaml" {
+		t.Fatalf("unexpected rule provenance: %#v", sources)
+	}
+
+	intent := baseIntent()
+	intent.ToolName = "tool.secrets"
+	outcome, err := EvaluatePolicyDetailed(policy, intent, EvalOptions{ProducerVersion: "test"})
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if outcome.Result.Verdict != "block" || outcome.MatchedRule != "allow-all-repo-tools,block-secrets" {
+		t.Fatalf("expected locked baseline rule to apply despite higher-priority allow, got %#v matched=%s", outcome.Result, outcome.MatchedRule)
+	}
+	explain := BuildPolicyExplain(policy, outcome, BuildPolicyExplainOptions{ProducerVersion: "test"})
+	if len(explain.MatchedRules) != 2 || explain.MatchedRules[1].Source != "../org/baseline.yaml" || !explain.MatchedRules[1].Locked {
+		t.Fatalf("expected explain provenance for locked rule, got %#v", explain.MatchedRules)
+	}
+
+	intent.ToolName = "tool.write"
+	outcome, err = EvaluatePolicyDetailed(policy, intent, EvalOptions{ProducerVersion: "test"})
+	if err != nil {
+		t.Fatalf("evaluate write: %v", err)
+	}
+	if outcome.Result.Verdict != "allow" {
+		t.Fatalf("expected unlocked rule override to apply, got %s", outcome.Result.Verdict)
+	}
+
+	digest, err := PolicyDigest(policy)
+	if err != nil {
+		t.Fatalf("digest: %v", err)
+	}
+	mustWriteBundleFile(t, filepath.Join(workDir, "org", "baseline.yaml"), strings.Replace(bundleBaselinePolicy, "org_secrets_blocked", "org_secrets_denied", 1))
+	changed, err := LoadPolicyFile(repoPolicyPath)
+	if err != nil {
+		t.Fatalf("reload policy: %v", err)
+	}
+	changedDigest, err := PolicyDigest(changed)
+	if err != nil {
+		t.Fatalf("digest changed policy: %v", err)
+	}
+	if digest == changedDigest {
+		t.Fatalf("expected policy digest to cover extended rules")
+	}
+}
+
+func TestLoadPolicyFileExtendsMergeErrors(t *testing.T) {
+	workDir := t.TempDir()
+	mustWriteBundleFile(t, filepath.Join(workDir, "baseline.yaml"), bundleBaselinePolicy)
+
+	cases := []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{
+			name: "redefine_without_override",
+			policy: `extends: [baseline.yaml]
+rules:
+  - name: approve-write
+    effect: allow
+    match:
+      tool_names: [tool.write]
+`,
+			wantErr: "set override: true",
+		},
+		{
+			name: "relax_locked_effect",
+			policy: `extends: [baseline.yaml]
+rules:
+  - name: block-secrets
+    priority: 10
+    effect: allow
+    override: true
+    match:
+      tool_names: [tool.secrets]
+`,
+			wantErr: "locked by baseline.yaml and cannot be relaxed",
+		},
+		{
+			name: "narrow_locked_match",
+			policy: `extends: [baseline.yaml]
+rules:
+  - name: block-secrets
+    priority: 10
+    effect: block
+    override: true
+    match:
+      tool_names: [tool.none]
+`,
+			wantErr: "may only tighten",
+		},
+		{
+			name: "override_without_parent",
+			policy: `extends: [baseline.yaml]
+rules:
+  - name: new-rule
+    effect: block
+    override: true
+`,
+			wantErr: "no inherited rule",
+		},
+		{
+			name:    "relax_default_verdict",
+			policy:  "extends: [baseline.yaml]\ndefault_verdict: allow\n",
+			wantErr: "default_verdict in policy.yaml cannot relax the inherited default_verdict (block -> allow)",
+		},
+		{
+			name:    "disable_fail_closed",
+			policy:  "extends: [baseline.yaml]\nfail_closed:\n  enabled: false\n  required_fields: [targets]\n",
+			wantErr: "fail_closed in policy.yaml cannot relax the inherited fail_closed (enabled true -> false)",
+		},
+		{
+			name:    "narrow_fail_closed_risk_classes",
+			policy:  "extends: [baseline.yaml]\nfail_closed:\n  enabled: true\n  risk_classes: [critical]\n",
+			wantErr: "risk_classes must include critical,high",
+		},
+		{
+			name:    "missing_parent",
+			policy:  "extends: [missing.yaml]\n",
+			wantErr: "extends missing.yaml",
+		},
+		{
+			name:    "cycle",
+			policy:  "extends: [policy.yaml]\n",
+			wantErr: "cycle",
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			policyPath := filepath.Join(workDir, "policy.yaml")
+			mustWriteBundleFile(t, policyPath, testCase.policy)
+			if _, err := LoadPolicyFile(policyPath); err == nil || !strings.Contains(err.Error(), testCase.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", testCase.wantErr, err)
+			}
+		})
+	}
+
+	tightenPath := filepath.Join(workDir, "tighten.yaml")
+	mustWriteBundleFile(t, tightenPath, `extends: [baseline.yaml]
+rules:
+  - name: block-secrets
+    priority: 10
+    effect: block
+    override: true
+    match:
+      tool_names: [tool.secrets]
+    reason_codes: [repo_secrets_blocked]
+`)
+	policy, err := LoadPolicyFile(tightenPath)
+	if err != nil {
+		t.Fatalf("expected equal-strength override of locked rule: %v", err)
+	}
+	for _, rule := range policy.Rules {
+		if rule.Name == "block-secrets" && (!rule.Locked || rule.Source != "tighten.yaml") {
+			t.Fatalf("expected override to stay locked with child provenance, got %#v", rule)
+		}
+	}
+
+	if _, err := ParsePolicyYAML([]byte("extends: [baseline.yaml]\n")); err == nil || !strings.Contains(err.Error(), "requires loading the policy from a file") {
+		t.Fatalf("expected ParsePolicyYAML to reject extends, got %v", err)
+	}
+	layer, err := ParsePolicyLayerYAML([]byte("extends: [baseline.yaml]\n"))
+	if err != nil || len(layer.Extends) != 1 {
+		t.Fatalf("expected layer parse to keep extends, got %#v err=%v", layer.Extends, err)
+	}
+}
+
+func TestLoadPolicyFileExtendsOnlyTightensDefaults(t *testing.T) {
+	workDir := t.TempDir()
+	mustWriteBundleFile(t, filepath.Join(workDir, "baseline.yaml"), `default_verdict: require_approval
+mcp_trust:
+  snapshot: trust.json
+  action: require_approval
+  min_score: 0.5
+  max_age: 24h
+redaction:
+  paths: ["$.args.password"]
+  detectors: [jwt]
+`)
+
+	cases := []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{
+			name:    "lower_mcp_trust_min_score",
+			policy:  "extends: [baseline.yaml]\nmcp_trust:\n  snapshot: trust.json\n  action: require_approval\n  min_score: 0.1\n  max_age: 24h\n",
+			wantErr: "mcp_trust in policy.yaml cannot relax the inherited mcp_trust (min_score 0.5 -> 0.1)",
+		},
+		{
+			name:    "swap_mcp_trust_snapshot",
+			policy:  "extends: [baseline.yaml]\nmcp_trust:\n  snapshot: other.json\n  action: block\n  min_score: 0.5\n  max_age: 24h\n",
+			wantErr: "snapshot trust.json -> other.json",
+		},
+		{
+			name:    "extend_mcp_trust_max_age",
+			policy:  "extends: [baseline.yaml]\nmcp_trust:\n  snapshot: trust.json\n  action: block\n  min_score: 0.5\n  max_age: 72h\n",
+			wantErr: "max_age 24h0m0s -> 72h0m0s",
+		},
+		{
+			name:    "drop_redaction_path",
+			policy:  "extends: [baseline.yaml]\nredaction:\n  detectors: [jwt]\n",
+			wantErr: "redaction in policy.yaml cannot relax the inherited redaction (paths must include $.args.password)",
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			policyPath := filepath.Join(workDir, "policy.yaml")
+			mustWriteBundleFile(t, policyPath, testCase.policy)
+			if _, err := LoadPolicyFile(policyPath); err == nil || !strings.Contains(err.Error(), testCase.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", testCase.wantErr, err)
+			}
+		})
+	}
+
+	tightenPath := filepath.Join(workDir, "tighten.yaml")
+	mustWriteBundleFile(t, tightenPath, `extends: [baseline.yaml]
+default_verdict: block
+mcp_trust:
+  snapshot: trust.json
+  action: block
+  min_score: 0.8
+  max_age: 1h
+  require_registry: true
+redaction:
+  paths: ["$.args.password", "$.args.token"]
+  detectors: [jwt, bearer_token]
+`)
+	policy, err := LoadPolicyFile(tightenPath)
+	if err != nil {
+		t.Fatalf("expected tightened defaults to load: %v", err)
+	}
+	if policy.DefaultVerdict != "block" || policy.MCPTrust.Action != "block" || policy.MCPTrust.MinScore != 0.8 || len(policy.Redaction.Paths) != 2 {
+		t.Fatalf("expected tightened defaults to replace inherited ones, got %#v", policy)
+	}
+}
+
+func TestLoadPolicyFileExtendsRegistryPack(t *testing.T) {
+	workDir := t.TempDir()
+	cacheDir := filepath.Join(workDir, "cache")
+	packDir := filepath.Join(workDir, "pack")
+	mustWriteBundleFile(t, filepath.Join(packDir, "policy.yaml"), bundleBaselinePolicy)
+	sum := sha256.Sum256([]byte(bundleBaselinePolicy))
+	keyPair, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	manifest := schemaregistry.RegistryPack{
+		SchemaID:        "gait.registry.pack",
+		SchemaVersion:   "1.0.0",
+		CreatedAt:       time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		ProducerVersion: "0.0.0-test",
+		PackName:        "org-baseline",
+		PackVersion:     "2.0.0",
+		Artifacts:       []schemaregistry.PackArtifact{{Path: "policy.yaml", SHA256: hex.EncodeToString(sum[:])}},
+	}
+	raw, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("marshal manifest: %v", err)
+	}
+	digest, err := jcs.DigestJCS(raw)
+	if err != nil {
+		t.Fatalf("digest manifest: %v", err)
+	}
+	signature, err := sign.SignDigestHex(keyPair.Private, digest)
+	if err != nil {
+		t.Fatalf("sign manifest: %v", err)
+	}
+	manifest.Signatures = []schemaregistry.SignatureRef{{Alg: signature.Alg, KeyID: signature.KeyID, Sig: signature.Sig, SignedDigest: signature.SignedDigest}}
+	raw, err = json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("marshal signed manifest: %v", err)
+	}
+	manifestPath := filepath.Join(packDir, "registry_pack.json")
+	mustWriteBundleFile(t, manifestPath, string(raw))
+	if _, err := registry.Install(context.Background(), registry.InstallOptions{Source: manifestPath, CacheDir: cacheDir, PublicKey: keyPair.Public}); err != nil {
+		t.Fatalf("install registry pack: %v", err)
+	}
+
+	policyPath := filepath.Join(workDir, "gait.policy.yaml")
+	mustWriteBundleFile(t, policyPath, "extends: [\"registry:org-baseline\"]\n")
+	policy, err := LoadPolicyFileWithOptions(policyPath, PolicyLoadOptions{RegistryCacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("load registry extends: %v", err)
+	}
+	if len(policy.Rules) != 2 || policy.Rules[0].Source != "registry:org-baseline@2.0.0" {
+		t.Fatalf("unexpected registry provenance: %#v", policy.Rules)
+	}
+
+	if _, err := LoadPolicyFileWithOptions(policyPath, PolicyLoadOptions{RegistryCacheDir: filepath.Join(workDir, "empty")}); err == nil || !strings.Contains(err.Error(), "not installed") {
+		t.Fatalf("expected missing registry pack error, got %v", err)
+	}
+}
+
+func mustWriteBundleFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatalf("mkdir %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
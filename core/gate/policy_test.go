@@ -227,7 +227,7 @@ rules:
     effect: allow
     rate_limit:
       requests: 1
-      window: day
+      window: fortnight
 `,
 		},
 		{
@@ -1855,6 +1855,55 @@ rules:
 	}
 }
 
+func TestEvaluatePolicyRuleCost(t *testing.T) {
+	policy, err := ParsePolicyYAML([]byte(`
+default_verdict: allow
+rules:
+  - name: read-cheap
+    effect: allow
+    cost: 0.5
+    match:
+      tool_names: [tool.read]
+  - name: read-metered
+    effect: allow
+    cost: 2
+    match:
+      tool_names: [tool.read]
+  - name: write
+    effect: allow
+    cost: 1
+    match:
+      tool_names: [tool.write]
+`))
+	if err != nil {
+		t.Fatalf("parse policy: %v", err)
+	}
+	intent := baseIntent()
+	intent.ToolName = "tool.read"
+	intent.Targets = []schemagate.IntentTarget{{Kind: "path", Value: "/tmp/in.txt", Operation: "read"}}
+	outcome, err := EvaluatePolicyDetailed(policy, intent, EvalOptions{ProducerVersion: "test"})
+	if err != nil || outcome.Cost != 2 {
+		t.Fatalf("expected highest matched rule cost, got %g err=%v", outcome.Cost, err)
+	}
+
+	intent.ToolName = "script"
+	intent.Script = &schemagate.IntentScript{
+		Steps: []schemagate.IntentScriptStep{
+			{ToolName: "tool.read", Targets: []schemagate.IntentTarget{{Kind: "path", Value: "/tmp/in.txt", Operation: "read"}}},
+			{ToolName: "tool.write", Targets: []schemagate.IntentTarget{{Kind: "path", Value: "/tmp/out.txt", Operation: "write"}}},
+			{ToolName: "tool.other", Targets: []schemagate.IntentTarget{{Kind: "path", Value: "/tmp/in.txt", Operation: "read"}}},
+		},
+	}
+	outcome, err = EvaluatePolicyDetailed(policy, intent, EvalOptions{ProducerVersion: "test"})
+	if err != nil || outcome.Cost != 3 {
+		t.Fatalf("expected summed script step cost, got %g err=%v", outcome.Cost, err)
+	}
+
+	if _, err := ParsePolicyYAML([]byte("rules:\n  - name: bad\n    effect: allow\n    cost: -1\n")); err == nil || !strings.Contains(err.Error(), "cost must be") {
+		t.Fatalf("expected negative cost to be rejected, got %v", err)
+	}
+}
+
 func TestEvaluateScriptIntentWrkrContextDoesNotLeakAcrossSteps(t *testing.T) {
 	policy, err := ParsePolicyYAML([]byte(`
 default_verdict: allow
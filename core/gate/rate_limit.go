@@ -3,11 +3,14 @@ package gate
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	coreerrors "github.com/Clyra-AI/gait/core/errors"
@@ -21,8 +24,12 @@ const (
 	rateLimitLockTimeout    = 3 * time.Second
 	rateLimitLockRetry      = 15 * time.Millisecond
 	rateLimitLockStaleAfter = 15 * time.Second
+
+	rateLimitTokenBucketMarker = "token_bucket"
 )
 
+var rateLimitCustomWindowPattern = regexp.MustCompile(`^([0-9]+(s|m|h))+$`)
+
 type RateLimitDecision struct {
 	Allowed   bool   `json:"allowed"`
 	Limit     int    `json:"limit"`
@@ -30,6 +37,49 @@ type RateLimitDecision struct {
 	Remaining int    `json:"remaining"`
 	Scope     string `json:"scope"`
 	Key       string `json:"key"`
+	Window    string `json:"window,omitempty"`
+	Algorithm string `json:"algorithm,omitempty"`
+}
+
+// RateLimitStore consumes one request from a shared counter. Implementations
+// must apply the request atomically so replicas sharing a store cannot
+// overspend a budget.
+type RateLimitStore interface {
+	Consume(request RateLimitCounterRequest) (RateLimitCounterResult, error)
+}
+
+type RateLimitCounterRequest struct {
+	Algorithm string    `json:"algorithm"`
+	Window    string    `json:"window"`
+	Scope     string    `json:"scope"`
+	ScopeKey  string    `json:"scope_key"`
+	Limit     int       `json:"limit"`
+	Now       time.Time `json:"now"`
+}
+
+type RateLimitCounterResult struct {
+	Allowed   bool `json:"allowed"`
+	Used      int  `json:"used"`
+	Remaining int  `json:"remaining"`
+}
+
+// FileRateLimitStore persists counters in a local JSON file guarded by a lock
+// file. An empty path keeps counters for the duration of a single call only.
+type FileRateLimitStore struct {
+	Path string
+}
+
+// MemoryRateLimitStore keeps counters in process memory. It backs the shared
+// counter service when no state file is configured.
+type MemoryRateLimitStore struct {
+	mu       sync.Mutex
+	counters map[string]rateLimitCounter
+}
+
+type rateLimitCounter struct {
+	Count     int
+	Tokens    float64
+	UpdatedAt time.Time
 }
 
 type persistedRateLimitState struct {
@@ -39,8 +89,10 @@ type persistedRateLimitState struct {
 }
 
 type persistedRateLimitBucket struct {
-	Key   string `json:"key"`
-	Count int    `json:"count"`
+	Key       string  `json:"key"`
+	Count     int     `json:"count,omitempty"`
+	Tokens    float64 `json:"tokens,omitempty"`
+	UpdatedAt string  `json:"updated_at,omitempty"`
 }
 
 type rateLimitLockMetadata struct {
@@ -51,9 +103,16 @@ type rateLimitLockMetadata struct {
 }
 
 func EnforceRateLimit(statePath string, limit RateLimitPolicy, intent schemagate.IntentRequest, now time.Time) (RateLimitDecision, error) {
+	return EnforceRateLimitWithStore(&FileRateLimitStore{Path: statePath}, limit, intent, now)
+}
+
+func EnforceRateLimitWithStore(store RateLimitStore, limit RateLimitPolicy, intent schemagate.IntentRequest, now time.Time) (RateLimitDecision, error) {
 	if limit.Requests <= 0 {
 		return RateLimitDecision{Allowed: true}, nil
 	}
+	if store == nil {
+		return RateLimitDecision{}, fmt.Errorf("rate limit store is required")
+	}
 
 	normalizedIntent, err := NormalizeIntent(intent)
 	if err != nil {
@@ -68,56 +127,152 @@ func EnforceRateLimit(statePath string, limit RateLimitPolicy, intent schemagate
 	if window == "" {
 		window = "minute"
 	}
-	if _, ok := allowedRateLimitWindows[window]; !ok {
-		return RateLimitDecision{}, fmt.Errorf("unsupported rate_limit window: %s", window)
+	if _, err := rateLimitWindowDuration(window); err != nil {
+		return RateLimitDecision{}, err
+	}
+	algorithm := strings.ToLower(strings.TrimSpace(limit.Algorithm))
+	if algorithm == "" {
+		algorithm = "fixed_window"
+	}
+	if _, ok := allowedRateLimitAlgorithms[algorithm]; !ok {
+		return RateLimitDecision{}, fmt.Errorf("unsupported rate_limit algorithm: %s", algorithm)
 	}
 
 	nowUTC := now.UTC()
 	if nowUTC.IsZero() {
 		nowUTC = time.Now().UTC()
 	}
-	bucketStart := rateLimitBucketStart(window, nowUTC)
-	bucket := bucketStart.Format(time.RFC3339)
 	scopeKey, err := rateLimitScopeKey(scope, normalizedIntent)
 	if err != nil {
 		return RateLimitDecision{}, err
 	}
-	counterKey := window + "|" + scope + "|" + bucket + "|" + scopeKey
 
-	return withRateLimitLock(statePath, func() (RateLimitDecision, error) {
-		counters, err := loadRateLimitCounters(statePath)
+	result, err := store.Consume(RateLimitCounterRequest{
+		Algorithm: algorithm,
+		Window:    window,
+		Scope:     scope,
+		ScopeKey:  scopeKey,
+		Limit:     limit.Requests,
+		Now:       nowUTC,
+	})
+	if err != nil {
+		return RateLimitDecision{}, err
+	}
+	return RateLimitDecision{
+		Allowed:   result.Allowed,
+		Limit:     limit.Requests,
+		Used:      result.Used,
+		Remaining: result.Remaining,
+		Scope:     scope,
+		Key:       scopeKey,
+		Window:    window,
+		Algorithm: algorithm,
+	}, nil
+}
+
+func (store *FileRateLimitStore) Consume(request RateLimitCounterRequest) (RateLimitCounterResult, error) {
+	if err := validateRateLimitCounterRequest(request); err != nil {
+		return RateLimitCounterResult{}, err
+	}
+	decision, err := withRateLimitLock(store.Path, func() (RateLimitDecision, error) {
+		counters, err := loadRateLimitCounters(store.Path)
 		if err != nil {
 			return RateLimitDecision{}, err
 		}
-		pruneRateLimitCounters(counters, window, nowUTC)
-
-		used := counters[counterKey]
-		if used >= limit.Requests {
-			return RateLimitDecision{
-				Allowed:   false,
-				Limit:     limit.Requests,
-				Used:      used,
-				Remaining: 0,
-				Scope:     scope,
-				Key:       scopeKey,
-			}, nil
+		pruneRateLimitCounters(counters, request.Now.UTC())
+		result := consumeRateLimitCounter(counters, request)
+		if result.Allowed {
+			if err := writeRateLimitCounters(store.Path, counters); err != nil {
+				return RateLimitDecision{}, err
+			}
 		}
+		return RateLimitDecision{Allowed: result.Allowed, Used: result.Used, Remaining: result.Remaining}, nil
+	})
+	if err != nil {
+		return RateLimitCounterResult{}, err
+	}
+	return RateLimitCounterResult{Allowed: decision.Allowed, Used: decision.Used, Remaining: decision.Remaining}, nil
+}
 
-		used++
-		counters[counterKey] = used
-		if err := writeRateLimitCounters(statePath, counters); err != nil {
-			return RateLimitDecision{}, err
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{counters: map[string]rateLimitCounter{}}
+}
+
+func (store *MemoryRateLimitStore) Consume(request RateLimitCounterRequest) (RateLimitCounterResult, error) {
+	if err := validateRateLimitCounterRequest(request); err != nil {
+		return RateLimitCounterResult{}, err
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.counters == nil {
+		store.counters = map[string]rateLimitCounter{}
+	}
+	pruneRateLimitCounters(store.counters, request.Now.UTC())
+	return consumeRateLimitCounter(store.counters, request), nil
+}
+
+func validateRateLimitCounterRequest(request RateLimitCounterRequest) error {
+	if request.Limit <= 0 {
+		return fmt.Errorf("rate limit counter request requires limit >= 1")
+	}
+	if _, ok := allowedRateLimitAlgorithms[request.Algorithm]; !ok {
+		return fmt.Errorf("unsupported rate_limit algorithm: %s", request.Algorithm)
+	}
+	if _, ok := allowedRateLimitScopes[request.Scope]; !ok {
+		return fmt.Errorf("unsupported rate_limit scope: %s", request.Scope)
+	}
+	if _, err := rateLimitWindowDuration(request.Window); err != nil {
+		return err
+	}
+	if request.Now.IsZero() {
+		return fmt.Errorf("rate limit counter request requires now")
+	}
+	return nil
+}
+
+func consumeRateLimitCounter(counters map[string]rateLimitCounter, request RateLimitCounterRequest) RateLimitCounterResult {
+	now := request.Now.UTC()
+	duration, _ := rateLimitWindowDuration(request.Window)
+	switch request.Algorithm {
+	case "token_bucket":
+		key := request.Window + "|" + request.Scope + "|" + rateLimitTokenBucketMarker + "|" + request.ScopeKey
+		capacity := float64(request.Limit)
+		tokens := capacity
+		if counter, ok := counters[key]; ok {
+			elapsed := now.Sub(counter.UpdatedAt).Seconds()
+			if elapsed < 0 {
+				elapsed = 0
+			}
+			tokens = math.Min(capacity, counter.Tokens+elapsed*capacity/duration.Seconds())
+		}
+		if tokens < 1 {
+			counters[key] = rateLimitCounter{Tokens: tokens, UpdatedAt: now}
+			return RateLimitCounterResult{Allowed: false, Used: request.Limit, Remaining: 0}
 		}
+		tokens--
+		counters[key] = rateLimitCounter{Tokens: tokens, UpdatedAt: now}
+		remaining := int(math.Floor(tokens))
+		return RateLimitCounterResult{Allowed: true, Used: request.Limit - remaining, Remaining: remaining}
+	default:
+		bucketStart := rateLimitBucketStart(duration, now)
+		key := rateLimitBucketKey(request, bucketStart)
+		estimate := float64(counters[key].Count)
+		if request.Algorithm == "sliding_window" {
+			previous := counters[rateLimitBucketKey(request, bucketStart.Add(-duration))].Count
+			weight := float64(duration-now.Sub(bucketStart)) / float64(duration)
+			estimate += float64(previous) * weight
+		}
+		if estimate+1 > float64(request.Limit) {
+			return RateLimitCounterResult{Allowed: false, Used: min(int(math.Ceil(estimate)), request.Limit), Remaining: 0}
+		}
+		counters[key] = rateLimitCounter{Count: counters[key].Count + 1}
+		used := min(int(math.Ceil(estimate))+1, request.Limit)
+		return RateLimitCounterResult{Allowed: true, Used: used, Remaining: request.Limit - used}
+	}
+}
 
-		return RateLimitDecision{
-			Allowed:   true,
-			Limit:     limit.Requests,
-			Used:      used,
-			Remaining: limit.Requests - used,
-			Scope:     scope,
-			Key:       scopeKey,
-		}, nil
-	})
+func rateLimitBucketKey(request RateLimitCounterRequest, bucketStart time.Time) string {
+	return request.Window + "|" + request.Scope + "|" + bucketStart.Format(time.RFC3339) + "|" + request.ScopeKey
 }
 
 func rateLimitScopeKey(scope string, intent schemagate.IntentRequest) (string, error) {
@@ -128,20 +283,49 @@ func rateLimitScopeKey(scope string, intent schemagate.IntentRequest) (string, e
 		return intent.Context.Identity, nil
 	case "tool_identity":
 		return intent.ToolName + "|" + intent.Context.Identity, nil
+	case "workspace":
+		return intent.Context.Workspace, nil
+	case "session":
+		return intent.Context.SessionID, nil
+	case "target":
+		values := make([]string, 0, len(intent.Targets))
+		for _, target := range intent.Targets {
+			values = append(values, target.Kind+":"+target.Value)
+		}
+		return strings.Join(normalizeStringList(values), ","), nil
 	default:
 		return "", fmt.Errorf("unsupported rate_limit scope: %s", scope)
 	}
 }
 
-func loadRateLimitCounters(path string) (map[string]int, error) {
+func rateLimitWindowDuration(window string) (time.Duration, error) {
+	switch window {
+	case "minute":
+		return time.Minute, nil
+	case "hour":
+		return time.Hour, nil
+	case "day":
+		return 24 * time.Hour, nil
+	}
+	if !rateLimitCustomWindowPattern.MatchString(window) {
+		return 0, fmt.Errorf("unsupported rate_limit window: %s", window)
+	}
+	duration, err := time.ParseDuration(window)
+	if err != nil || duration < time.Second {
+		return 0, fmt.Errorf("unsupported rate_limit window: %s", window)
+	}
+	return duration, nil
+}
+
+func loadRateLimitCounters(path string) (map[string]rateLimitCounter, error) {
 	if strings.TrimSpace(path) == "" {
-		return map[string]int{}, nil
+		return map[string]rateLimitCounter{}, nil
 	}
 	// #nosec G304 -- state path is explicit local user input.
 	content, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return map[string]int{}, nil
+			return map[string]rateLimitCounter{}, nil
 		}
 		return nil, fmt.Errorf("read rate limit state: %w", err)
 	}
@@ -149,18 +333,29 @@ func loadRateLimitCounters(path string) (map[string]int, error) {
 	if err := json.Unmarshal(content, &state); err != nil {
 		return nil, fmt.Errorf("parse rate limit state: %w", err)
 	}
-	counters := make(map[string]int, len(state.Counters))
+	counters := make(map[string]rateLimitCounter, len(state.Counters))
 	for _, counter := range state.Counters {
 		key := strings.TrimSpace(counter.Key)
-		if key == "" || counter.Count <= 0 {
+		if key == "" {
 			continue
 		}
-		counters[key] = counter.Count
+		updatedAt := time.Time{}
+		if strings.TrimSpace(counter.UpdatedAt) != "" {
+			parsed, err := time.Parse(time.RFC3339Nano, counter.UpdatedAt)
+			if err != nil {
+				continue
+			}
+			updatedAt = parsed.UTC()
+		}
+		if counter.Count <= 0 && updatedAt.IsZero() {
+			continue
+		}
+		counters[key] = rateLimitCounter{Count: counter.Count, Tokens: counter.Tokens, UpdatedAt: updatedAt}
 	}
 	return counters, nil
 }
 
-func writeRateLimitCounters(path string, counters map[string]int) error {
+func writeRateLimitCounters(path string, counters map[string]rateLimitCounter) error {
 	if strings.TrimSpace(path) == "" {
 		return nil
 	}
@@ -172,8 +367,8 @@ func writeRateLimitCounters(path string, counters map[string]int) error {
 	}
 
 	keys := make([]string, 0, len(counters))
-	for key, count := range counters {
-		if strings.TrimSpace(key) == "" || count <= 0 {
+	for key, counter := range counters {
+		if strings.TrimSpace(key) == "" || (counter.Count <= 0 && counter.UpdatedAt.IsZero()) {
 			continue
 		}
 		keys = append(keys, key)
@@ -181,7 +376,12 @@ func writeRateLimitCounters(path string, counters map[string]int) error {
 	sort.Strings(keys)
 	entries := make([]persistedRateLimitBucket, 0, len(keys))
 	for _, key := range keys {
-		entries = append(entries, persistedRateLimitBucket{Key: key, Count: counters[key]})
+		counter := counters[key]
+		entry := persistedRateLimitBucket{Key: key, Count: counter.Count, Tokens: counter.Tokens}
+		if !counter.UpdatedAt.IsZero() {
+			entry.UpdatedAt = counter.UpdatedAt.UTC().Format(time.RFC3339Nano)
+		}
+		entries = append(entries, entry)
 	}
 	state := persistedRateLimitState{
 		SchemaID:      rateLimitStateSchemaID,
@@ -199,31 +399,36 @@ func writeRateLimitCounters(path string, counters map[string]int) error {
 	return nil
 }
 
-func pruneRateLimitCounters(counters map[string]int, window string, now time.Time) {
-	keepBucket := rateLimitBucketStart(window, now).Format(time.RFC3339)
-	for key := range counters {
+// pruneRateLimitCounters drops buckets that can no longer affect a decision:
+// window buckets older than the previous window and token buckets that have
+// fully refilled.
+func pruneRateLimitCounters(counters map[string]rateLimitCounter, now time.Time) {
+	for key, counter := range counters {
 		parts := strings.SplitN(key, "|", 4)
 		if len(parts) != 4 {
 			delete(counters, key)
 			continue
 		}
-		if parts[0] != window {
+		duration, err := rateLimitWindowDuration(parts[0])
+		if err != nil {
+			delete(counters, key)
 			continue
 		}
-		if parts[2] == keepBucket {
+		if parts[2] == rateLimitTokenBucketMarker {
+			if now.Sub(counter.UpdatedAt) >= duration {
+				delete(counters, key)
+			}
 			continue
 		}
-		delete(counters, key)
+		bucketStart, err := time.Parse(time.RFC3339, parts[2])
+		if err != nil || bucketStart.Before(rateLimitBucketStart(duration, now).Add(-duration)) {
+			delete(counters, key)
+		}
 	}
 }
 
-func rateLimitBucketStart(window string, now time.Time) time.Time {
-	switch window {
-	case "hour":
-		return now.UTC().Truncate(time.Hour)
-	default:
-		return now.UTC().Truncate(time.Minute)
-	}
+func rateLimitBucketStart(window time.Duration, now time.Time) time.Time {
+	return now.UTC().Truncate(window)
 }
 
 func withRateLimitLock(statePath string, fn func() (RateLimitDecision, error)) (RateLimitDecision, error) {
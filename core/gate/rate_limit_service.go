@@ -0,0 +1,154 @@
+package gate
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	coreerrors "github.com/Clyra-AI/gait/core/errors"
+)
+
+const (
+	RateLimitServicePath = "/v1/rate-limit/consume"
+
+	rateLimitServiceMaxBody = 64 << 10
+	rateLimitServiceTimeout = 3 * time.Second
+)
+
+// HTTPRateLimitStore consumes counters from a rate limit service hosted by
+// another gait process, so every replica pointed at the same URL shares one
+// budget.
+type HTTPRateLimitStore struct {
+	URL    string
+	Token  string // #nosec G117 -- field name is explicit config surface, not a hardcoded secret.
+	Client *http.Client
+}
+
+type rateLimitServiceError struct {
+	Error string `json:"error"`
+}
+
+// NewRateLimitServiceHandler exposes store over HTTP for HTTPRateLimitStore
+// clients. A non-empty token requires matching bearer authorization. Windows
+// and refills always use the service clock; the client's now is ignored so a
+// skewed or forged replica clock cannot reset the shared budget.
+func NewRateLimitServiceHandler(store RateLimitStore, token string) http.Handler {
+	expectedToken := strings.TrimSpace(token)
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			writer.Header().Set("Allow", http.MethodPost)
+			writeRateLimitServiceJSON(writer, http.StatusMethodNotAllowed, rateLimitServiceError{Error: "expected POST"})
+			return
+		}
+		if expectedToken != "" {
+			provided := strings.TrimSpace(strings.TrimPrefix(request.Header.Get("Authorization"), "Bearer "))
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(expectedToken)) != 1 {
+				writeRateLimitServiceJSON(writer, http.StatusUnauthorized, rateLimitServiceError{Error: "unauthorized"})
+				return
+			}
+		}
+		request.Body = http.MaxBytesReader(writer, request.Body, rateLimitServiceMaxBody)
+		var counterRequest RateLimitCounterRequest
+		if err := json.NewDecoder(request.Body).Decode(&counterRequest); err != nil {
+			writeRateLimitServiceJSON(writer, http.StatusBadRequest, rateLimitServiceError{Error: fmt.Sprintf("decode rate limit request: %v", err)})
+			return
+		}
+		counterRequest.Now = time.Now().UTC()
+		result, err := store.Consume(counterRequest)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if coreerrors.CategoryOf(err) == coreerrors.CategoryStateContention {
+				status = http.StatusServiceUnavailable
+			} else if validateRateLimitCounterRequest(counterRequest) != nil {
+				status = http.StatusBadRequest
+			}
+			writeRateLimitServiceJSON(writer, status, rateLimitServiceError{Error: err.Error()})
+			return
+		}
+		writeRateLimitServiceJSON(writer, http.StatusOK, result)
+	})
+}
+
+func (store *HTTPRateLimitStore) Consume(request RateLimitCounterRequest) (RateLimitCounterResult, error) {
+	if err := validateRateLimitCounterRequest(request); err != nil {
+		return RateLimitCounterResult{}, err
+	}
+	endpoint := strings.TrimSpace(store.URL)
+	if endpoint == "" {
+		return RateLimitCounterResult{}, fmt.Errorf("rate limit service url is required")
+	}
+	encoded, err := json.Marshal(request)
+	if err != nil {
+		return RateLimitCounterResult{}, fmt.Errorf("encode rate limit request: %w", err)
+	}
+	httpRequest, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(encoded))
+	if err != nil {
+		return RateLimitCounterResult{}, fmt.Errorf("build rate limit request: %w", err)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	if token := strings.TrimSpace(store.Token); token != "" {
+		httpRequest.Header.Set("Authorization", "Bearer "+token)
+	}
+	client := store.Client
+	if client == nil {
+		client = &http.Client{Timeout: rateLimitServiceTimeout}
+	}
+	// #nosec G107 -- rate limit service url is explicit operator configuration.
+	response, err := client.Do(httpRequest)
+	if err != nil {
+		return RateLimitCounterResult{}, coreerrors.Wrap(
+			fmt.Errorf("call rate limit service: %w", err),
+			coreerrors.CategoryNetworkTransient,
+			"rate_limit_service_unavailable",
+			"check that the rate limit service is reachable",
+			true,
+		)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	body, err := io.ReadAll(io.LimitReader(response.Body, rateLimitServiceMaxBody))
+	if err != nil {
+		return RateLimitCounterResult{}, fmt.Errorf("read rate limit service response: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		serviceErr := rateLimitServiceError{}
+		_ = json.Unmarshal(body, &serviceErr)
+		message := strings.TrimSpace(serviceErr.Error)
+		if message == "" {
+			message = http.StatusText(response.StatusCode)
+		}
+		if response.StatusCode == http.StatusServiceUnavailable {
+			return RateLimitCounterResult{}, coreerrors.Wrap(
+				fmt.Errorf("rate limit service: %s", message),
+				coreerrors.CategoryStateContention,
+				"rate_limit_service_contention",
+				"retry after contention subsides",
+				true,
+			)
+		}
+		return RateLimitCounterResult{}, coreerrors.Wrap(
+			fmt.Errorf("rate limit service returned %d: %s", response.StatusCode, message),
+			coreerrors.CategoryNetworkPermanent,
+			"rate_limit_service_rejected",
+			"check rate limit service url and token configuration",
+			false,
+		)
+	}
+	result := RateLimitCounterResult{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return RateLimitCounterResult{}, fmt.Errorf("parse rate limit service response: %w", err)
+	}
+	return result, nil
+}
+
+func writeRateLimitServiceJSON(writer http.ResponseWriter, status int, payload any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	_ = json.NewEncoder(writer).Encode(payload)
+}
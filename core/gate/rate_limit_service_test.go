@@ -0,0 +1,118 @@
+package gate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	coreerrors "github.com/Clyra-AI/gait/core/errors"
+)
+
+func TestHTTPRateLimitStoreSharesBudgetAcrossClients(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "rate_state.json")
+	server := httptest.NewServer(NewRateLimitServiceHandler(&FileRateLimitStore{Path: statePath}, "secret"))
+	t.Cleanup(server.Close)
+
+	replicaA := &HTTPRateLimitStore{URL: server.URL + RateLimitServicePath, Token: "secret"}
+	replicaB := &HTTPRateLimitStore{URL: server.URL + RateLimitServicePath, Token: "secret"}
+	limit := RateLimitPolicy{Requests: 2, Scope: "identity", Window: "hour"}
+	intent := rateLimitTestIntent()
+	now := time.Date(2026, time.February, 5, 10, 0, 0, 0, time.UTC)
+
+	first, err := EnforceRateLimitWithStore(replicaA, limit, intent, now)
+	if err != nil || !first.Allowed || first.Remaining != 1 {
+		t.Fatalf("unexpected first decision: %#v err=%v", first, err)
+	}
+	second, err := EnforceRateLimitWithStore(replicaB, limit, intent, now)
+	if err != nil || !second.Allowed || second.Remaining != 0 {
+		t.Fatalf("unexpected second decision: %#v err=%v", second, err)
+	}
+	third, err := EnforceRateLimitWithStore(replicaA, limit, intent, now)
+	if err != nil || third.Allowed {
+		t.Fatalf("expected shared budget to block on replica A: %#v err=%v", third, err)
+	}
+
+	counters, err := loadRateLimitCounters(statePath)
+	if err != nil {
+		t.Fatalf("load service state: %v", err)
+	}
+	if len(counters) != 1 {
+		t.Fatalf("expected service to persist one counter, got %#v", counters)
+	}
+}
+
+func TestRateLimitServiceIgnoresClientClock(t *testing.T) {
+	server := httptest.NewServer(NewRateLimitServiceHandler(NewMemoryRateLimitStore(), ""))
+	t.Cleanup(server.Close)
+	replica := &HTTPRateLimitStore{URL: server.URL + RateLimitServicePath}
+	for _, algorithm := range []string{"fixed_window", "token_bucket"} {
+		request := RateLimitCounterRequest{Algorithm: algorithm, Window: "hour", Scope: "tool", ScopeKey: "tool.write." + algorithm, Limit: 1, Now: time.Now().UTC()}
+		if result, err := replica.Consume(request); err != nil || !result.Allowed {
+			t.Fatalf("%s: expected first call allowed, got %#v err=%v", algorithm, result, err)
+		}
+		request.Now = request.Now.Add(48 * time.Hour)
+		if result, err := replica.Consume(request); err != nil || result.Allowed {
+			t.Fatalf("%s: expected forged clock to stay within the shared budget, got %#v err=%v", algorithm, result, err)
+		}
+	}
+}
+
+func TestHTTPRateLimitStoreErrors(t *testing.T) {
+	server := httptest.NewServer(NewRateLimitServiceHandler(NewMemoryRateLimitStore(), "secret"))
+	t.Cleanup(server.Close)
+	request := RateLimitCounterRequest{
+		Algorithm: "fixed_window",
+		Window:    "minute",
+		Scope:     "tool",
+		ScopeKey:  "tool.write",
+		Limit:     1,
+		Now:       time.Date(2026, time.February, 5, 10, 0, 0, 0, time.UTC),
+	}
+
+	_, err := (&HTTPRateLimitStore{URL: server.URL + RateLimitServicePath, Token: "wrong"}).Consume(request)
+	if err == nil || coreerrors.CategoryOf(err) != coreerrors.CategoryNetworkPermanent || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("expected unauthorized error, got %v", err)
+	}
+
+	_, err = (&HTTPRateLimitStore{URL: "http://127.0.0.1:1" + RateLimitServicePath}).Consume(request)
+	if err == nil || coreerrors.CategoryOf(err) != coreerrors.CategoryNetworkTransient || !coreerrors.RetryableOf(err) {
+		t.Fatalf("expected retryable network error, got %v", err)
+	}
+
+	invalid := request
+	invalid.Scope = "bad"
+	if _, err := (&HTTPRateLimitStore{URL: server.URL + RateLimitServicePath, Token: "secret"}).Consume(invalid); err == nil {
+		t.Fatalf("expected invalid request to fail before calling the service")
+	}
+
+	response, err := http.Post(server.URL+RateLimitServicePath, "application/json", strings.NewReader(`{"scope":"bad"}`))
+	if err != nil {
+		t.Fatalf("post invalid request: %v", err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected unauthenticated post to be rejected, got %d", response.StatusCode)
+	}
+
+	unauthenticated := httptest.NewServer(NewRateLimitServiceHandler(NewMemoryRateLimitStore(), ""))
+	t.Cleanup(unauthenticated.Close)
+	response, err = http.Post(unauthenticated.URL, "application/json", strings.NewReader(`{"scope":"bad"}`))
+	if err != nil {
+		t.Fatalf("post invalid request: %v", err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected invalid request status 400, got %d", response.StatusCode)
+	}
+	response, err = http.Get(unauthenticated.URL)
+	if err != nil {
+		t.Fatalf("get service: %v", err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for GET, got %d", response.StatusCode)
+	}
+}
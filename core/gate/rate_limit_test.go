@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -253,6 +254,200 @@ func TestIsWindowsAccessDeniedLockError(t *testing.T) {
 	}
 }
 
+func TestEnforceRateLimitDayAndCustomWindows(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "rate_state.json")
+	intent := rateLimitTestIntent()
+
+	dayLimit := RateLimitPolicy{Requests: 1, Scope: "tool_identity", Window: "day"}
+	if decision, err := EnforceRateLimit(statePath, dayLimit, intent, time.Date(2026, time.February, 5, 1, 0, 0, 0, time.UTC)); err != nil || !decision.Allowed {
+		t.Fatalf("expected first day request to allow: %#v err=%v", decision, err)
+	}
+	if decision, err := EnforceRateLimit(statePath, dayLimit, intent, time.Date(2026, time.February, 5, 23, 59, 0, 0, time.UTC)); err != nil || decision.Allowed {
+		t.Fatalf("expected same-day request to block: %#v err=%v", decision, err)
+	}
+	if decision, err := EnforceRateLimit(statePath, dayLimit, intent, time.Date(2026, time.February, 6, 0, 0, 0, 0, time.UTC)); err != nil || !decision.Allowed {
+		t.Fatalf("expected next-day request to allow: %#v err=%v", decision, err)
+	}
+
+	customLimit := RateLimitPolicy{Requests: 1, Scope: "tool_identity", Window: "15m"}
+	if decision, err := EnforceRateLimit(statePath, customLimit, intent, time.Date(2026, time.February, 5, 10, 0, 0, 0, time.UTC)); err != nil || !decision.Allowed || decision.Window != "15m" {
+		t.Fatalf("expected first custom window request to allow: %#v err=%v", decision, err)
+	}
+	if decision, err := EnforceRateLimit(statePath, customLimit, intent, time.Date(2026, time.February, 5, 10, 14, 0, 0, time.UTC)); err != nil || decision.Allowed {
+		t.Fatalf("expected request inside custom window to block: %#v err=%v", decision, err)
+	}
+	if decision, err := EnforceRateLimit(statePath, customLimit, intent, time.Date(2026, time.February, 5, 10, 15, 0, 0, time.UTC)); err != nil || !decision.Allowed {
+		t.Fatalf("expected request in next custom window to allow: %#v err=%v", decision, err)
+	}
+
+	for _, window := range []string{"500ms", "1.5h", "fortnight"} {
+		if _, err := EnforceRateLimit("", RateLimitPolicy{Requests: 1, Window: window}, intent, time.Now().UTC()); err == nil {
+			t.Fatalf("expected unsupported window error for %q", window)
+		}
+	}
+}
+
+func TestEnforceRateLimitSlidingWindow(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	intent := rateLimitTestIntent()
+	limit := RateLimitPolicy{Requests: 2, Scope: "tool_identity", Window: "minute", Algorithm: "sliding_window"}
+	base := time.Date(2026, time.February, 5, 10, 0, 0, 0, time.UTC)
+
+	for index := 0; index < 2; index++ {
+		if decision, err := EnforceRateLimitWithStore(store, limit, intent, base.Add(50*time.Second)); err != nil || !decision.Allowed {
+			t.Fatalf("expected request %d to allow: %#v err=%v", index, decision, err)
+		}
+	}
+	// A fixed window would reset at 10:01:00; the sliding window still
+	// carries most of the previous minute's requests.
+	decision, err := EnforceRateLimitWithStore(store, limit, intent, base.Add(65*time.Second))
+	if err != nil {
+		t.Fatalf("enforce sliding window: %v", err)
+	}
+	if decision.Allowed || decision.Algorithm != "sliding_window" {
+		t.Fatalf("expected sliding window to block early in next minute: %#v", decision)
+	}
+	decision, err = EnforceRateLimitWithStore(store, limit, intent, base.Add(95*time.Second))
+	if err != nil {
+		t.Fatalf("enforce sliding window: %v", err)
+	}
+	if !decision.Allowed {
+		t.Fatalf("expected sliding window to allow once previous weight decays: %#v", decision)
+	}
+}
+
+func TestEnforceRateLimitTokenBucket(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "rate_state.json")
+	intent := rateLimitTestIntent()
+	limit := RateLimitPolicy{Requests: 2, Scope: "tool_identity", Window: "minute", Algorithm: "token_bucket"}
+	base := time.Date(2026, time.February, 5, 10, 0, 0, 0, time.UTC)
+
+	for index := 0; index < 2; index++ {
+		if decision, err := EnforceRateLimit(statePath, limit, intent, base); err != nil || !decision.Allowed {
+			t.Fatalf("expected burst request %d to allow: %#v err=%v", index, decision, err)
+		}
+	}
+	if decision, err := EnforceRateLimit(statePath, limit, intent, base.Add(10*time.Second)); err != nil || decision.Allowed {
+		t.Fatalf("expected empty bucket to block: %#v err=%v", decision, err)
+	}
+	decision, err := EnforceRateLimit(statePath, limit, intent, base.Add(30*time.Second))
+	if err != nil {
+		t.Fatalf("enforce token bucket: %v", err)
+	}
+	if !decision.Allowed || decision.Remaining != 0 {
+		t.Fatalf("expected one refilled token after half a window: %#v", decision)
+	}
+
+	counters, err := loadRateLimitCounters(statePath)
+	if err != nil {
+		t.Fatalf("load counters: %v", err)
+	}
+	if len(counters) != 1 {
+		t.Fatalf("expected one persisted token bucket, got %#v", counters)
+	}
+	for key, counter := range counters {
+		if !strings.Contains(key, "|token_bucket|") || counter.UpdatedAt.IsZero() {
+			t.Fatalf("unexpected token bucket state %q: %#v", key, counter)
+		}
+	}
+}
+
+func TestEnforceRateLimitWorkspaceSessionAndTargetScopes(t *testing.T) {
+	now := time.Date(2026, time.February, 5, 10, 0, 0, 0, time.UTC)
+	intent := rateLimitTestIntent()
+	intent.Context.SessionID = "sess-1"
+
+	otherTool := intent
+	otherTool.ToolName = "tool.other"
+	otherTool.Context.Identity = "bob"
+
+	otherSession := intent
+	otherSession.Context.SessionID = "sess-2"
+
+	otherTarget := intent
+	otherTarget.Targets = []schemagate.IntentTarget{{Kind: "host", Value: "api.internal.com"}}
+
+	tests := []struct {
+		scope       string
+		sameBudget  schemagate.IntentRequest
+		otherBudget schemagate.IntentRequest
+	}{
+		{scope: "workspace", sameBudget: otherTool, otherBudget: func() schemagate.IntentRequest {
+			other := intent
+			other.Context.Workspace = "/repo/other"
+			return other
+		}()},
+		{scope: "session", sameBudget: otherTool, otherBudget: otherSession},
+		{scope: "target", sameBudget: otherTool, otherBudget: otherTarget},
+	}
+	for _, tc := range tests {
+		t.Run(tc.scope, func(t *testing.T) {
+			store := NewMemoryRateLimitStore()
+			limit := RateLimitPolicy{Requests: 1, Scope: tc.scope, Window: "minute"}
+			if decision, err := EnforceRateLimitWithStore(store, limit, intent, now); err != nil || !decision.Allowed {
+				t.Fatalf("expected first request to allow: %#v err=%v", decision, err)
+			}
+			if decision, err := EnforceRateLimitWithStore(store, limit, tc.sameBudget, now); err != nil || decision.Allowed {
+				t.Fatalf("expected request sharing the %s budget to block: %#v err=%v", tc.scope, decision, err)
+			}
+			if decision, err := EnforceRateLimitWithStore(store, limit, tc.otherBudget, now); err != nil || !decision.Allowed {
+				t.Fatalf("expected request with a different %s to allow: %#v err=%v", tc.scope, decision, err)
+			}
+		})
+	}
+}
+
+func TestParsePolicyRateLimitAlgorithmAndWindow(t *testing.T) {
+	policy, err := ParsePolicyYAML([]byte(`
+rules:
+  - name: bucket
+    effect: allow
+    rate_limit:
+      requests: 5
+      window: 90s
+      scope: session
+      algorithm: Token_Bucket
+    destructive_budget:
+      requests: 1
+      window: day
+      scope: target
+`))
+	if err != nil {
+		t.Fatalf("parse policy: %v", err)
+	}
+	rule := policy.Rules[0]
+	if rule.RateLimit.Algorithm != "token_bucket" || rule.RateLimit.Window != "90s" || rule.RateLimit.Scope != "session" {
+		t.Fatalf("unexpected rate limit normalization: %#v", rule.RateLimit)
+	}
+	if rule.DestructiveBudget.Algorithm != "fixed_window" {
+		t.Fatalf("expected destructive budget algorithm default, got %#v", rule.DestructiveBudget)
+	}
+
+	fixed, err := ParsePolicyYAML([]byte("rules:\n  - name: bucket\n    effect: allow\n    rate_limit:\n      requests: 5\n"))
+	if err != nil {
+		t.Fatalf("parse fixed policy: %v", err)
+	}
+	explicit, err := ParsePolicyYAML([]byte("rules:\n  - name: bucket\n    effect: allow\n    rate_limit:\n      requests: 5\n      algorithm: fixed_window\n"))
+	if err != nil {
+		t.Fatalf("parse explicit fixed policy: %v", err)
+	}
+	fixedDigest, err := PolicyDigest(fixed)
+	if err != nil {
+		t.Fatalf("digest fixed policy: %v", err)
+	}
+	explicitDigest, err := PolicyDigest(explicit)
+	if err != nil {
+		t.Fatalf("digest explicit policy: %v", err)
+	}
+	if fixedDigest != explicitDigest {
+		t.Fatalf("expected default algorithm to keep policy digest stable")
+	}
+
+	if _, err := ParsePolicyYAML([]byte("rules:\n  - name: bad\n    effect: allow\n    rate_limit:\n      requests: 1\n      algorithm: leaky\n")); err == nil {
+		t.Fatalf("expected unsupported algorithm to fail")
+	}
+}
+
 func rateLimitTestIntent() schemagate.IntentRequest {
 	return schemagate.IntentRequest{
 		SchemaID:        "gait.gate.intent_request",
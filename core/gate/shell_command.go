@@ -0,0 +1,862 @@
+package gate
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	maxShellCommandBytes = 64 << 10
+	maxShellNestingDepth = 4
+	maxShellCommands     = 256
+)
+
+// ShellCommand is one simple command found in a shell command string. Wrappers
+// such as sudo, env or xargs are reported as commands of their own and the
+// command they run is reported after them.
+type ShellCommand struct {
+	Executable string   `json:"executable"`
+	Path       string   `json:"path"`
+	Flags      []string `json:"flags,omitempty"`
+	Operands   []string `json:"operands,omitempty"`
+	Env        []string `json:"env,omitempty"`
+	Privileged bool     `json:"privileged,omitempty"`
+	Piped      bool     `json:"piped,omitempty"`
+	Depth      int      `json:"depth,omitempty"`
+}
+
+// ShellTarget is a file or URL touched by a shell command, taken from
+// redirections and from the operands of well known file utilities.
+type ShellTarget struct {
+	Kind      string `json:"kind"`
+	Value     string `json:"value"`
+	Operation string `json:"operation,omitempty"`
+}
+
+type ParsedShellCommand struct {
+	Commands []ShellCommand `json:"commands"`
+	Targets  []ShellTarget  `json:"targets,omitempty"`
+}
+
+type shellTokenKind int
+
+const (
+	shellTokenWord shellTokenKind = iota
+	shellTokenOperator
+	shellTokenRedirect
+)
+
+type shellToken struct {
+	kind   shellTokenKind
+	value  string
+	nested []string
+}
+
+type shellHeredoc struct {
+	delimiter string
+	stripTabs bool
+}
+
+type shellLexer struct {
+	input            string
+	pos              int
+	tokens           []shellToken
+	heredocs         []shellHeredoc
+	pendingHeredocOp string
+}
+
+type shellFileOperation struct {
+	operation string
+	skip      int
+}
+
+var (
+	shellReservedWords = map[string]struct{}{
+		"!": {}, "{": {}, "}": {}, "if": {}, "then": {}, "else": {}, "elif": {}, "fi": {},
+		"do": {}, "done": {}, "while": {}, "until": {}, "esac": {},
+	}
+	shellCompoundHeads = map[string]struct{}{
+		"for": {}, "case": {}, "select": {}, "function": {},
+	}
+	shellInterpreters = map[string]struct{}{
+		"sh": {}, "bash": {}, "zsh": {}, "dash": {}, "ksh": {}, "ash": {}, "fish": {},
+	}
+	// shellWrapperOptionArgs lists, per wrapper, the options that consume the
+	// following word as their value.
+	shellWrapperOptionArgs = map[string]map[string]bool{
+		"sudo": {
+			"-u": true, "-g": true, "-C": true, "-D": true, "-h": true, "-p": true, "-r": true, "-t": true, "-U": true, "-T": true,
+			"--user": true, "--group": true, "--close-from": true, "--chdir": true, "--host": true, "--prompt": true,
+			"--role": true, "--type": true, "--other-user": true, "--command-timeout": true,
+		},
+		"doas":    {"-u": true, "-C": true},
+		"env":     {"-u": true, "-C": true, "-S": true, "--unset": true, "--chdir": true, "--split-string": true},
+		"nice":    {"-n": true, "--adjustment": true},
+		"timeout": {"-s": true, "-k": true, "--signal": true, "--kill-after": true},
+		"stdbuf":  {"-i": true, "-o": true, "-e": true, "--input": true, "--output": true, "--error": true},
+		"xargs": {
+			"-I": true, "-n": true, "-P": true, "-L": true, "-s": true, "-d": true, "-E": true, "-a": true,
+			"--max-args": true, "--max-procs": true, "--max-lines": true, "--max-chars": true, "--delimiter": true,
+			"--eof": true, "--arg-file": true, "--replace": true,
+		},
+		"nohup":   {},
+		"exec":    {"-a": true},
+		"command": {},
+		"builtin": {},
+		"time":    {},
+	}
+	shellFileOperations = map[string]shellFileOperation{
+		"rm":     {operation: "delete"},
+		"rmdir":  {operation: "delete"},
+		"unlink": {operation: "delete"},
+		"shred":  {operation: "delete"},
+		"cp":     {operation: "write"},
+		"mv":     {operation: "write"},
+		"ln":     {operation: "write"},
+		"touch":  {operation: "write"},
+		"mkdir":  {operation: "write"},
+		"tee":    {operation: "write"},
+		"chmod":  {operation: "write", skip: 1},
+		"chown":  {operation: "write", skip: 1},
+		"chgrp":  {operation: "write", skip: 1},
+		"cat":    {operation: "read"},
+		"head":   {operation: "read"},
+		"tail":   {operation: "read"},
+		"less":   {operation: "read"},
+		"more":   {operation: "read"},
+		"grep":   {operation: "read", skip: 1},
+	}
+)
+
+// ParseShellCommand parses a POSIX shell command string into the simple
+// commands it runs and the files and URLs it touches. Pipelines, lists,
+// subshells, command substitutions, heredocs and redirections are understood;
+// sh -c, bash -c and eval scripts are parsed recursively.
+func ParseShellCommand(command string) (ParsedShellCommand, error) {
+	if len(command) > maxShellCommandBytes {
+		return ParsedShellCommand{}, fmt.Errorf("shell command exceeds %d bytes", maxShellCommandBytes)
+	}
+	parser := newShellParser()
+	if err := parser.parse(command, 0); err != nil {
+		return ParsedShellCommand{}, err
+	}
+	return parser.result(), nil
+}
+
+func parseShellArgv(argv []string) (ParsedShellCommand, error) {
+	size := 0
+	for _, word := range argv {
+		size += len(word) + 1
+	}
+	if size > maxShellCommandBytes {
+		return ParsedShellCommand{}, fmt.Errorf("shell command exceeds %d bytes", maxShellCommandBytes)
+	}
+	parser := newShellParser()
+	if err := parser.addSimpleCommand(argv, false, 0); err != nil {
+		return ParsedShellCommand{}, err
+	}
+	return parser.result(), nil
+}
+
+type shellParser struct {
+	commands    []ShellCommand
+	targets     []ShellTarget
+	seenTargets map[string]struct{}
+}
+
+func newShellParser() *shellParser {
+	return &shellParser{seenTargets: map[string]struct{}{}}
+}
+
+func (p *shellParser) result() ParsedShellCommand {
+	targets := append([]ShellTarget{}, p.targets...)
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Kind != targets[j].Kind {
+			return targets[i].Kind < targets[j].Kind
+		}
+		if targets[i].Value != targets[j].Value {
+			return targets[i].Value < targets[j].Value
+		}
+		return targets[i].Operation < targets[j].Operation
+	})
+	commands := p.commands
+	if commands == nil {
+		commands = []ShellCommand{}
+	}
+	return ParsedShellCommand{Commands: commands, Targets: targets}
+}
+
+func (p *shellParser) parse(command string, depth int) error {
+	if depth > maxShellNestingDepth {
+		return fmt.Errorf("shell command nesting exceeds %d levels", maxShellNestingDepth)
+	}
+	tokens, err := lexShell(command)
+	if err != nil {
+		return err
+	}
+	words := []string{}
+	piped := false
+	for index := 0; index < len(tokens); index++ {
+		token := tokens[index]
+		for _, nested := range token.nested {
+			if err := p.parse(nested, depth+1); err != nil {
+				return err
+			}
+		}
+		switch token.kind {
+		case shellTokenWord:
+			words = append(words, token.value)
+		case shellTokenRedirect:
+			if index+1 >= len(tokens) || tokens[index+1].kind != shellTokenWord {
+				return fmt.Errorf("redirection %s is missing a target", token.value)
+			}
+			index++
+			target := tokens[index]
+			for _, nested := range target.nested {
+				if err := p.parse(nested, depth+1); err != nil {
+					return err
+				}
+			}
+			p.addRedirectTarget(token.value, target.value)
+		case shellTokenOperator:
+			if len(words) == 0 && token.value == "(" {
+				continue
+			}
+			if err := p.addSimpleCommand(words, piped, depth); err != nil {
+				return err
+			}
+			words = []string{}
+			piped = token.value == "|"
+		}
+	}
+	return p.addSimpleCommand(words, piped, depth)
+}
+
+func (p *shellParser) addSimpleCommand(words []string, piped bool, depth int) error {
+	if len(words) == 0 {
+		return nil
+	}
+	for len(words) > 0 {
+		if _, ok := shellReservedWords[words[0]]; !ok {
+			break
+		}
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	if _, ok := shellCompoundHeads[words[0]]; ok {
+		return nil
+	}
+	env := []string{}
+	privileged := false
+	for {
+		for len(words) > 0 && rawEnvAssignmentPattern.MatchString(words[0]) {
+			name, _, _ := strings.Cut(words[0], "=")
+			env = append(env, name)
+			words = words[1:]
+		}
+		if len(words) == 0 {
+			return nil
+		}
+		if len(p.commands) >= maxShellCommands {
+			return fmt.Errorf("shell command exceeds %d commands", maxShellCommands)
+		}
+		name := shellExecutableName(words[0])
+		if _, ok := shellWrapperOptionArgs[name]; !ok {
+			break
+		}
+		options, rest, splitString := splitShellWrapper(name, words[1:])
+		if name == "sudo" || name == "doas" {
+			privileged = true
+		}
+		wrapper := newShellCommand(name, words[0], env, privileged, piped, depth)
+		wrapper.Flags = shellFlags(options)
+		p.commands = append(p.commands, wrapper)
+		if name == "command" && (contains(wrapper.Flags, "-v") || contains(wrapper.Flags, "-V")) {
+			return nil
+		}
+		if splitString != "" {
+			splitWords, err := shellWords(splitString)
+			if err != nil {
+				return err
+			}
+			rest = append(splitWords, rest...)
+		}
+		words = rest
+	}
+
+	name := shellExecutableName(words[0])
+	command := newShellCommand(name, words[0], env, privileged, piped, depth)
+	flags := []string{}
+	operandsOnly := false
+	for _, word := range words[1:] {
+		if !operandsOnly && word == "--" {
+			operandsOnly = true
+			continue
+		}
+		if !operandsOnly && len(word) > 1 && strings.HasPrefix(word, "-") {
+			flags = append(flags, word)
+			continue
+		}
+		command.Operands = append(command.Operands, word)
+	}
+	command.Flags = shellFlags(flags)
+	p.commands = append(p.commands, command)
+
+	if _, ok := shellInterpreters[name]; ok {
+		if script, ok := shellInlineScript(words[1:]); ok {
+			return p.parse(script, depth+1)
+		}
+	}
+	if name == "eval" {
+		return p.parse(strings.Join(command.Operands, " "), depth+1)
+	}
+	fileOperation, knownFileUtility := shellFileOperations[name]
+	for index, operand := range command.Operands {
+		switch {
+		case isShellURL(operand):
+			p.addTarget("url", operand, "")
+		case knownFileUtility && index >= fileOperation.skip:
+			p.addTarget("path", operand, fileOperation.operation)
+		case isShellPath(operand):
+			p.addTarget("path", operand, "")
+		}
+	}
+	return nil
+}
+
+func (p *shellParser) addRedirectTarget(operator string, target string) {
+	switch operator {
+	case "<<", "<<-", "<<<":
+		return
+	case "<&", ">&":
+		if target == "-" || isShellDigits(target) {
+			return
+		}
+	}
+	switch target {
+	case "", "/dev/null", "/dev/stdin", "/dev/stdout", "/dev/stderr":
+		return
+	}
+	operation := "write"
+	if strings.HasPrefix(operator, "<") && operator != "<>" {
+		operation = "read"
+	}
+	p.addTarget("path", target, operation)
+}
+
+func (p *shellParser) addTarget(kind string, value string, operation string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	key := kind + "\x00" + value + "\x00" + operation
+	if _, ok := p.seenTargets[key]; ok {
+		return
+	}
+	p.seenTargets[key] = struct{}{}
+	p.targets = append(p.targets, ShellTarget{Kind: kind, Value: value, Operation: operation})
+}
+
+func newShellCommand(name string, rawPath string, env []string, privileged bool, piped bool, depth int) ShellCommand {
+	command := ShellCommand{
+		Executable: name,
+		Path:       rawPath,
+		Privileged: privileged,
+		Piped:      piped,
+		Depth:      depth,
+	}
+	if len(env) > 0 {
+		command.Env = uniqueSorted(env)
+	}
+	return command
+}
+
+func shellExecutableName(word string) string {
+	return strings.ToLower(path.Base(strings.TrimSpace(word)))
+}
+
+// shellFlags strips option values and also lists each letter of a short
+// option cluster, so -rf yields -rf, -r and -f.
+func shellFlags(words []string) []string {
+	if len(words) == 0 {
+		return nil
+	}
+	flags := make([]string, 0, len(words))
+	for _, word := range words {
+		if !strings.HasPrefix(word, "-") || word == "-" || word == "--" {
+			continue
+		}
+		flag, _, _ := strings.Cut(word, "=")
+		flags = append(flags, flag)
+		if strings.HasPrefix(flag, "--") || len(flag) <= 2 {
+			continue
+		}
+		for _, letter := range flag[1:] {
+			if !isShellLetter(letter) {
+				break
+			}
+			flags = append(flags, "-"+string(letter))
+		}
+	}
+	return uniqueSorted(flags)
+}
+
+func splitShellWrapper(name string, words []string) ([]string, []string, string) {
+	optionArgs := shellWrapperOptionArgs[name]
+	splitString := ""
+	index := 0
+	for index < len(words) {
+		word := words[index]
+		if word == "--" {
+			index++
+			break
+		}
+		if name == "env" && word == "-" {
+			index++
+			continue
+		}
+		if len(word) < 2 || !strings.HasPrefix(word, "-") {
+			break
+		}
+		index++
+		flag, value, hasValue := strings.Cut(word, "=")
+		isSplit := name == "env" && (flag == "-S" || flag == "--split-string")
+		if hasValue {
+			if isSplit {
+				splitString = value
+			}
+			continue
+		}
+		if optionArgs[flag] && index < len(words) {
+			if isSplit {
+				splitString = words[index]
+			}
+			index++
+		}
+	}
+	if name == "timeout" && index < len(words) {
+		index++
+	}
+	return words[:index], words[index:], splitString
+}
+
+// shellInlineScript returns the script passed to a shell with -c.
+func shellInlineScript(args []string) (string, bool) {
+	inline := false
+	for index := 0; index < len(args); index++ {
+		word := args[index]
+		if word == "--" {
+			if inline && index+1 < len(args) {
+				return args[index+1], true
+			}
+			return "", false
+		}
+		switch {
+		case word == "--rcfile" || word == "--init-file":
+			index++
+		case strings.HasPrefix(word, "--"):
+		case len(word) > 1 && (word[0] == '-' || word[0] == '+'):
+			if word[0] == '-' && strings.Contains(word[1:], "c") {
+				inline = true
+			}
+			if strings.ContainsAny(word[1:], "oO") {
+				index++
+			}
+		default:
+			return word, inline
+		}
+	}
+	return "", false
+}
+
+func shellWords(input string) ([]string, error) {
+	tokens, err := lexShell(input)
+	if err != nil {
+		return nil, err
+	}
+	words := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if token.kind == shellTokenWord {
+			words = append(words, token.value)
+		}
+	}
+	return words, nil
+}
+
+func isShellPath(value string) bool {
+	switch value {
+	case ".", "..", "~":
+		return true
+	}
+	for _, prefix := range []string{"/", "./", "../", "~/"} {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isShellURL(value string) bool {
+	lower := strings.ToLower(value)
+	for _, prefix := range []string{"http://", "https://", "ftp://"} {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isShellDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, char := range value {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isShellLetter(char rune) bool {
+	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+func lexShell(input string) ([]shellToken, error) {
+	lexer := &shellLexer{input: input}
+	for lexer.pos < len(input) {
+		char := input[lexer.pos]
+		switch {
+		case char == ' ' || char == '\t' || char == '\r':
+			lexer.pos++
+		case char == '\\' && lexer.peek(1) == '\n':
+			lexer.pos += 2
+		case char == '\n':
+			lexer.pos++
+			lexer.emit(shellTokenOperator, ";", nil)
+			lexer.skipHeredocBodies()
+		case char == '#':
+			for lexer.pos < len(input) && input[lexer.pos] != '\n' {
+				lexer.pos++
+			}
+		case char == '|' || char == '&' || char == ';' || char == '(' || char == ')':
+			lexer.lexOperator()
+		case char == '<' || char == '>' || lexer.atFDRedirect():
+			if err := lexer.lexRedirect(); err != nil {
+				return nil, err
+			}
+		default:
+			if err := lexer.lexWord(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return lexer.tokens, nil
+}
+
+func (l *shellLexer) peek(offset int) byte {
+	if l.pos+offset >= len(l.input) {
+		return 0
+	}
+	return l.input[l.pos+offset]
+}
+
+func (l *shellLexer) emit(kind shellTokenKind, value string, nested []string) {
+	l.tokens = append(l.tokens, shellToken{kind: kind, value: value, nested: nested})
+}
+
+func (l *shellLexer) atFDRedirect() bool {
+	index := l.pos
+	for index < len(l.input) && l.input[index] >= '0' && l.input[index] <= '9' {
+		index++
+	}
+	return index > l.pos && index < len(l.input) && (l.input[index] == '<' || l.input[index] == '>')
+}
+
+func (l *shellLexer) lexOperator() {
+	char := l.input[l.pos]
+	next := l.peek(1)
+	switch char {
+	case '|':
+		if next == '|' {
+			l.pos += 2
+			l.emit(shellTokenOperator, "||", nil)
+			return
+		}
+		if next == '&' {
+			l.pos++
+		}
+		l.pos++
+		l.emit(shellTokenOperator, "|", nil)
+	case '&':
+		switch {
+		case next == '&':
+			l.pos += 2
+			l.emit(shellTokenOperator, "&&", nil)
+		case next == '>' && l.peek(2) == '>':
+			l.pos += 3
+			l.emit(shellTokenRedirect, "&>>", nil)
+		case next == '>':
+			l.pos += 2
+			l.emit(shellTokenRedirect, "&>", nil)
+		default:
+			l.pos++
+			l.emit(shellTokenOperator, "&", nil)
+		}
+	case ';':
+		for l.pos < len(l.input) && (l.input[l.pos] == ';' || l.input[l.pos] == '&') {
+			l.pos++
+		}
+		l.emit(shellTokenOperator, ";", nil)
+	default:
+		l.pos++
+		l.emit(shellTokenOperator, string(char), nil)
+	}
+}
+
+func (l *shellLexer) lexRedirect() error {
+	hasFD := false
+	for l.pos < len(l.input) && l.input[l.pos] >= '0' && l.input[l.pos] <= '9' {
+		l.pos++
+		hasFD = true
+	}
+	rest := l.input[l.pos:]
+	if !hasFD && (strings.HasPrefix(rest, "<(") || strings.HasPrefix(rest, ">(")) {
+		start := l.pos
+		inner, err := l.readBalanced(l.pos + 1)
+		if err != nil {
+			return err
+		}
+		l.emit(shellTokenWord, l.input[start:l.pos], []string{inner})
+		return nil
+	}
+	operator := ""
+	for _, candidate := range []string{"<<<", "<<-", "<<", "<>", "<&", ">>", ">|", ">&", "<", ">"} {
+		if strings.HasPrefix(rest, candidate) {
+			operator = candidate
+			break
+		}
+	}
+	l.pos += len(operator)
+	if operator == "<<" || operator == "<<-" {
+		l.pendingHeredocOp = operator
+	}
+	l.emit(shellTokenRedirect, operator, nil)
+	return nil
+}
+
+func (l *shellLexer) lexWord() error {
+	var builder strings.Builder
+	nested := []string{}
+	for l.pos < len(l.input) {
+		char := l.input[l.pos]
+		switch char {
+		case ' ', '\t', '\r', '\n', '|', '&', ';', '(', ')', '<', '>':
+			l.finishWord(builder.String(), nested)
+			return nil
+		case '\\':
+			if l.pos+1 < len(l.input) && l.input[l.pos+1] != '\n' {
+				builder.WriteByte(l.input[l.pos+1])
+			}
+			l.pos += 2
+		case '\'':
+			end := strings.IndexByte(l.input[l.pos+1:], '\'')
+			if end < 0 {
+				return fmt.Errorf("unterminated single quote in shell command")
+			}
+			builder.WriteString(l.input[l.pos+1 : l.pos+1+end])
+			l.pos += end + 2
+		case '"':
+			if err := l.lexDoubleQuoted(&builder, &nested); err != nil {
+				return err
+			}
+		case '`':
+			if err := l.lexBackticks(&builder, &nested); err != nil {
+				return err
+			}
+		case '$':
+			if err := l.lexDollar(&builder, &nested); err != nil {
+				return err
+			}
+		default:
+			builder.WriteByte(char)
+			l.pos++
+		}
+	}
+	l.finishWord(builder.String(), nested)
+	return nil
+}
+
+func (l *shellLexer) finishWord(value string, nested []string) {
+	if l.pendingHeredocOp != "" {
+		l.heredocs = append(l.heredocs, shellHeredoc{delimiter: value, stripTabs: l.pendingHeredocOp == "<<-"})
+		l.pendingHeredocOp = ""
+	}
+	if len(nested) == 0 {
+		nested = nil
+	}
+	l.emit(shellTokenWord, value, nested)
+}
+
+func (l *shellLexer) lexDoubleQuoted(builder *strings.Builder, nested *[]string) error {
+	l.pos++
+	for l.pos < len(l.input) {
+		char := l.input[l.pos]
+		switch char {
+		case '"':
+			l.pos++
+			return nil
+		case '\\':
+			next := l.peek(1)
+			switch next {
+			case '$', '`', '"', '\\':
+				builder.WriteByte(next)
+			case '\n':
+			default:
+				builder.WriteByte(char)
+				if next != 0 {
+					builder.WriteByte(next)
+				}
+			}
+			l.pos += 2
+		case '`':
+			if err := l.lexBackticks(builder, nested); err != nil {
+				return err
+			}
+		case '$':
+			if err := l.lexDollar(builder, nested); err != nil {
+				return err
+			}
+		default:
+			builder.WriteByte(char)
+			l.pos++
+		}
+	}
+	return fmt.Errorf("unterminated double quote in shell command")
+}
+
+func (l *shellLexer) lexBackticks(builder *strings.Builder, nested *[]string) error {
+	var inner strings.Builder
+	for index := l.pos + 1; index < len(l.input); index++ {
+		char := l.input[index]
+		if char == '\\' && index+1 < len(l.input) {
+			index++
+			inner.WriteByte(l.input[index])
+			continue
+		}
+		if char == '`' {
+			builder.WriteString(l.input[l.pos : index+1])
+			*nested = append(*nested, inner.String())
+			l.pos = index + 1
+			return nil
+		}
+		inner.WriteByte(char)
+	}
+	return fmt.Errorf("unterminated backquote in shell command")
+}
+
+func (l *shellLexer) lexDollar(builder *strings.Builder, nested *[]string) error {
+	start := l.pos
+	switch l.peek(1) {
+	case '(':
+		arithmetic := l.peek(2) == '('
+		inner, err := l.readBalanced(l.pos + 1)
+		if err != nil {
+			return err
+		}
+		if !arithmetic {
+			*nested = append(*nested, inner)
+		}
+		builder.WriteString(l.input[start:l.pos])
+	case '{':
+		end := strings.IndexByte(l.input[l.pos:], '}')
+		if end < 0 {
+			return fmt.Errorf("unterminated parameter expansion in shell command")
+		}
+		l.pos += end + 1
+		builder.WriteString(l.input[start:l.pos])
+	case '\'':
+		for index := l.pos + 2; index < len(l.input); index++ {
+			switch l.input[index] {
+			case '\\':
+				index++
+				if index < len(l.input) {
+					builder.WriteByte(l.input[index])
+				}
+			case '\'':
+				l.pos = index + 1
+				return nil
+			default:
+				builder.WriteByte(l.input[index])
+			}
+		}
+		return fmt.Errorf("unterminated single quote in shell command")
+	default:
+		builder.WriteByte('$')
+		l.pos++
+	}
+	return nil
+}
+
+// readBalanced consumes the parenthesized text that opens at open and returns
+// its contents, skipping over quoted sections.
+func (l *shellLexer) readBalanced(open int) (string, error) {
+	depth := 0
+	for index := open; index < len(l.input); index++ {
+		switch l.input[index] {
+		case '\\':
+			index++
+		case '\'':
+			end := strings.IndexByte(l.input[index+1:], '\'')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated single quote in shell command")
+			}
+			index += end + 1
+		case '"':
+			index++
+			for index < len(l.input) && l.input[index] != '"' {
+				if l.input[index] == '\\' {
+					index++
+				}
+				index++
+			}
+			if index >= len(l.input) {
+				return "", fmt.Errorf("unterminated double quote in shell command")
+			}
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				l.pos = index + 1
+				return l.input[open+1 : index], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unterminated command substitution in shell command")
+}
+
+func (l *shellLexer) skipHeredocBodies() {
+	for _, heredoc := range l.heredocs {
+		for l.pos < len(l.input) {
+			end := strings.IndexByte(l.input[l.pos:], '\n')
+			line := l.input[l.pos:]
+			if end >= 0 {
+				line = l.input[l.pos : l.pos+end]
+				l.pos += end + 1
+			} else {
+				l.pos = len(l.input)
+			}
+			if heredoc.stripTabs {
+				line = strings.TrimLeft(line, "\t")
+			}
+			if line == heredoc.delimiter {
+				break
+			}
+		}
+	}
+	l.heredocs = nil
+}
@@ -12,7 +12,9 @@ import (
 	"time"
 
 	"github.com/Clyra-AI/gait/core/fsx"
+	"github.com/Clyra-AI/gait/core/redact"
 	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	"github.com/Clyra-AI/gait/core/trust"
 	jcs "github.com/Clyra-AI/proof/canon"
 	sign "github.com/Clyra-AI/proof/signing"
 )
@@ -185,6 +187,11 @@ func EmitSignedTrace(policy Policy, intent schemagate.IntentRequest, gateResult
 		}
 	}
 
+	trace, err = redactTraceRecord(policy, trace)
+	if err != nil {
+		return EmitTraceResult{}, err
+	}
+
 	signable := trace
 	signable.Signature = nil
 	signableRaw, err := json.Marshal(signable)
@@ -217,6 +224,32 @@ func EmitSignedTrace(policy Policy, intent schemagate.IntentRequest, gateResult
 	}, nil
 }
 
+// traceProtectedFields are never redacted: they are digests, identifiers and
+// the verdict that verifiers and evidence indexes match on.
+var traceProtectedFields = []string{
+	"schema_id", "schema_version", "created_at", "observed_at", "producer_version", "trace_id",
+	"event_id", "tool_name", "args_digest", "intent_digest", "policy_digest", "policy_id",
+	"policy_version", "verdict", "matched_rule_ids", "context_set_digest", "script_hash",
+	"broker_request_digest", "relationship", "signature",
+}
+
+func redactTraceRecord(policy Policy, trace schemagate.TraceRecord) (schemagate.TraceRecord, error) {
+	redactor, err := redact.New(policy.Redaction)
+	if err != nil || redactor == nil {
+		return trace, err
+	}
+	redacted := schemagate.TraceRecord{}
+	entries, err := redactor.RedactStruct(trace, &redacted, traceProtectedFields...)
+	if err != nil {
+		return schemagate.TraceRecord{}, fmt.Errorf("redact trace: %w", err)
+	}
+	if len(entries) == 0 {
+		return trace, nil
+	}
+	redacted.Redaction = redactor.Summary(entries)
+	return redacted, nil
+}
+
 func digestDelegationChain(delegation schemagate.IntentDelegation) (string, error) {
 	raw, err := json.Marshal(delegation)
 	if err != nil {
@@ -296,6 +329,23 @@ func VerifyTraceRecordSignature(trace schemagate.TraceRecord, publicKey ed25519.
 	return sign.VerifyTraceRecordJSON(publicKey, signature, signableRaw)
 }
 
+// VerifyTraceRecordSignatureWithTrustStore verifies trace with the trace
+// signer key named by its signature, as trusted when the trace was created.
+func VerifyTraceRecordSignatureWithTrustStore(trace schemagate.TraceRecord, store *trust.Store) (bool, error) {
+	if trace.Signature == nil {
+		return false, fmt.Errorf("trace signature missing")
+	}
+	publicKey, err := store.Resolve(trust.Lookup{
+		Role:     trust.RoleTraceSigner,
+		KeyID:    trace.Signature.KeyID,
+		SignedAt: trace.CreatedAt,
+	})
+	if err != nil {
+		return false, err
+	}
+	return VerifyTraceRecordSignature(trace, publicKey)
+}
+
 func computeTraceID(policyDigest, intentDigest, verdict string) string {
 	sum := sha256.Sum256([]byte(policyDigest + ":" + intentDigest + ":" + verdict))
 	return hex.EncodeToString(sum[:12])
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -92,6 +93,55 @@ rules:
 	}
 }
 
+func TestEmitSignedTraceAppliesPolicyRedaction(t *testing.T) {
+	keyPair, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	policy, err := ParsePolicyYAML([]byte(`
+default_verdict: allow
+redaction:
+  paths: [$.workflow_id]
+  pii: [email]
+`))
+	if err != nil {
+		t.Fatalf("parse policy: %v", err)
+	}
+	intent := baseIntent()
+	intent.Context.WorkflowID = "deploy-prod"
+	intent.Context.CredentialOwner = "ops@example.com"
+	result, err := EvaluatePolicy(policy, intent, EvalOptions{ProducerVersion: "test"})
+	if err != nil {
+		t.Fatalf("evaluate policy: %v", err)
+	}
+	tracePath := filepath.Join(t.TempDir(), "trace.json")
+	emitted, err := EmitSignedTrace(policy, intent, result, EmitTraceOptions{
+		ProducerVersion:   "test",
+		SigningPrivateKey: keyPair.Private,
+		TracePath:         tracePath,
+	})
+	if err != nil {
+		t.Fatalf("emit trace: %v", err)
+	}
+	raw, err := os.ReadFile(tracePath)
+	if err != nil {
+		t.Fatalf("read trace: %v", err)
+	}
+	if strings.Contains(string(raw), "ops@example.com") || strings.Contains(string(raw), "deploy-prod") {
+		t.Fatalf("expected trace to be redacted: %s", raw)
+	}
+	if emitted.Trace.Redaction == nil || len(emitted.Trace.Redaction.Entries) != 2 || emitted.Trace.ArgsDigest == "" || emitted.Trace.ToolName != intent.ToolName {
+		t.Fatalf("expected redaction summary with protected fields intact, got %#v", emitted.Trace)
+	}
+	readTrace, err := ReadTraceRecord(tracePath)
+	if err != nil {
+		t.Fatalf("read trace record: %v", err)
+	}
+	if ok, err := VerifyTraceRecordSignature(readTrace, keyPair.Public); err != nil || !ok {
+		t.Fatalf("expected redacted trace signature to verify, ok=%v err=%v", ok, err)
+	}
+}
+
 func TestEmitSignedTraceIncludesScriptMetadata(t *testing.T) {
 	keyPair, err := sign.GenerateKeyPair()
 	if err != nil {
@@ -22,6 +22,7 @@ import (
 	schemaguard "github.com/Clyra-AI/gait/core/schema/v1/guard"
 	schemaregress "github.com/Clyra-AI/gait/core/schema/v1/regress"
 	schemascout "github.com/Clyra-AI/gait/core/schema/v1/scout"
+	"github.com/Clyra-AI/gait/core/transparency"
 	"github.com/Clyra-AI/gait/core/zipx"
 	jcs "github.com/Clyra-AI/proof/canon"
 	sign "github.com/Clyra-AI/proof/signing"
@@ -32,6 +33,7 @@ type BuildOptions struct {
 	OutputPath              string
 	CaseID                  string
 	TemplateID              string
+	RegistryCacheDir        string
 	InventoryPaths          []string
 	TracePaths              []string
 	RegressPaths            []string
@@ -46,25 +48,31 @@ type BuildOptions struct {
 }
 
 type BuildResult struct {
-	PackPath string
-	Manifest schemaguard.PackManifest
+	PackPath     string
+	Manifest     schemaguard.PackManifest
+	RequiredGaps []string
 }
 
 type VerifyResult struct {
-	PackID          string         `json:"pack_id,omitempty"`
-	RunID           string         `json:"run_id,omitempty"`
-	FilesChecked    int            `json:"files_checked"`
-	MissingFiles    []string       `json:"missing_files,omitempty"`
-	HashMismatches  []HashMismatch `json:"hash_mismatches,omitempty"`
-	SignatureStatus string         `json:"signature_status,omitempty"`
-	SignatureErrors []string       `json:"signature_errors,omitempty"`
-	SignaturesTotal int            `json:"signatures_total"`
-	SignaturesValid int            `json:"signatures_valid"`
+	PackID          string                        `json:"pack_id,omitempty"`
+	RunID           string                        `json:"run_id,omitempty"`
+	FilesChecked    int                           `json:"files_checked"`
+	MissingFiles    []string                      `json:"missing_files,omitempty"`
+	HashMismatches  []HashMismatch                `json:"hash_mismatches,omitempty"`
+	SignatureStatus string                        `json:"signature_status,omitempty"`
+	SignatureErrors []string                      `json:"signature_errors,omitempty"`
+	SignaturesTotal int                           `json:"signatures_total"`
+	SignaturesValid int                           `json:"signatures_valid"`
+	Inclusion       *transparency.InclusionResult `json:"inclusion,omitempty"`
 }
 
+// VerifyOptions.InclusionProof, when set, is checked offline against the
+// sha256 of pack_manifest.json; its tree head signature is verified with
+// PublicKey.
 type VerifyOptions struct {
 	PublicKey        ed25519.PublicKey
 	RequireSignature bool
+	InclusionProof   *transparency.InclusionProof
 }
 
 type HashMismatch struct {
@@ -189,7 +197,11 @@ func buildPackWithRunpack(options BuildOptions, runpackData runpack.Runpack) (Bu
 		}
 	}
 
-	templateID := normalizeTemplateID(options.TemplateID)
+	template, err := resolveControlTemplate(options.TemplateID, options.RegistryCacheDir)
+	if err != nil {
+		return BuildResult{}, err
+	}
+	templateID := template.ID
 	if options.RenderPDF {
 		pdfPayload, err := renderAuditSummaryPDF(auditSummaryPDFOptions{
 			RunID:         runpackData.Run.RunID,
@@ -205,7 +217,8 @@ func buildPackWithRunpack(options BuildOptions, runpackData runpack.Runpack) (Bu
 	}
 
 	contents := buildPackEntries(evidenceFiles)
-	controlIndex := buildControlIndex(templateID, contents)
+	controlIndex := buildControlIndex(template, contents)
+	gaps, requiredGaps := controlGaps(controlIndex)
 	evidencePointers := buildEvidencePointers(contents)
 	controlIndexPayload, err := marshalCanonicalJSON(controlIndex)
 	if err != nil {
@@ -253,8 +266,11 @@ func buildPackWithRunpack(options BuildOptions, runpackData runpack.Runpack) (Bu
 		RunID:           runpackData.Run.RunID,
 		CaseID:          strings.TrimSpace(options.CaseID),
 		TemplateID:      templateID,
+		TemplateVersion: template.Version,
+		TemplateDigest:  template.Digest,
 		GeneratedAt:     manifestTime,
 		ControlIndex:    controlIndex,
+		ControlGaps:     gaps,
 		EvidencePtrs:    evidencePointers,
 		IncidentWindow:  normalizeIncidentWindow(options.IncidentWindow),
 		Rendered:        renderedArtifacts,
@@ -314,8 +330,9 @@ func buildPackWithRunpack(options BuildOptions, runpackData runpack.Runpack) (Bu
 	}
 
 	return BuildResult{
-		PackPath: outputPath,
-		Manifest: manifest,
+		PackPath:     outputPath,
+		Manifest:     manifest,
+		RequiredGaps: requiredGaps,
 	}, nil
 }
 
@@ -389,6 +406,11 @@ func VerifyPackWithOptions(path string, opts VerifyOptions) (VerifyResult, error
 			})
 		}
 	}
+	if opts.InclusionProof != nil {
+		manifestDigest := sha256.Sum256(manifestBytes)
+		inclusion := transparency.CheckInclusion(*opts.InclusionProof, hex.EncodeToString(manifestDigest[:]), opts.PublicKey)
+		result.Inclusion = &inclusion
+	}
 	if len(result.MissingFiles) > 1 {
 		sort.Strings(result.MissingFiles)
 	}
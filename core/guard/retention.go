@@ -7,6 +7,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/Clyra-AI/gait/core/transparency"
 )
 
 type RetentionOptions struct {
@@ -17,6 +19,9 @@ type RetentionOptions struct {
 	ReportOutput    string
 	Now             time.Time
 	ProducerVersion string
+	// TransparencyLog, when set, receives a retention_delete entry for every
+	// deleted file so deletions are recorded rather than erased from the log.
+	TransparencyLog string
 }
 
 type RetentionResult struct {
@@ -39,6 +44,8 @@ type RetentionFileEvent struct {
 	ModifiedAt time.Time `json:"modified_at"`
 	AgeSeconds int64     `json:"age_seconds"`
 	Action     string    `json:"action"`
+	Digest     string    `json:"digest,omitempty"`
+	LogIndex   *int64    `json:"log_index,omitempty"`
 }
 
 func ApplyRetention(options RetentionOptions) (RetentionResult, error) {
@@ -98,10 +105,31 @@ func ApplyRetention(options RetentionOptions) (RetentionResult, error) {
 		}
 		if ttl > 0 && age > ttl {
 			event.Action = "deleted"
+			logDir := strings.TrimSpace(options.TransparencyLog)
+			var logEntry transparency.Entry
+			if logDir != "" {
+				logEntry, err = transparency.DescribeArtifact(candidate.path)
+				if err != nil {
+					return RetentionResult{}, fmt.Errorf("describe retained file %s: %w", candidate.path, err)
+				}
+				event.Digest = logEntry.Digest
+			}
 			if !options.DryRun {
 				if err := os.Remove(candidate.path); err != nil {
 					return RetentionResult{}, fmt.Errorf("delete retained file %s: %w", candidate.path, err)
 				}
+				if logDir != "" {
+					appended, err := transparency.Append(logDir, transparency.Entry{
+						Kind:       transparency.KindRetentionDelete,
+						Digest:     logEntry.Digest,
+						Ref:        filepath.ToSlash(candidate.path),
+						RecordedAt: now,
+					}, transparency.AppendOptions{Now: now})
+					if err != nil {
+						return RetentionResult{}, fmt.Errorf("record deletion of %s: %w", candidate.path, err)
+					}
+					event.LogIndex = &appended.Index
+				}
 			}
 			result.DeletedFiles = append(result.DeletedFiles, event)
 			continue
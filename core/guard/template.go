@@ -2,11 +2,14 @@ package guard
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/Clyra-AI/gait/core/registry"
 	schemaguard "github.com/Clyra-AI/gait/core/schema/v1/guard"
+	"github.com/goccy/go-yaml"
 )
 
 const (
@@ -18,16 +21,49 @@ const (
 	entryTypeTrace    = "trace"
 	entryTypeReport   = "report"
 	entryTypeEvidence = "evidence"
+
+	controlTemplateSchemaID      = "gait.guard.control_template"
+	controlTemplateSchemaVersion = "1.0.0"
+	registryTemplateRefPrefix    = "registry:"
+	maxControlTemplateBytes      = 1 << 20
 )
 
 type controlTemplate struct {
 	ID          string
 	Title       string
+	Required    bool
 	EntryTypes  []string
 	PathPrefix  []string
 	PathMatches []string
 }
 
+// controlTemplateSet is a resolved template: a built-in set or one loaded from
+// a versioned YAML file, in which case Version and Digest identify it.
+type controlTemplateSet struct {
+	ID       string
+	Version  string
+	Digest   string
+	Controls []controlTemplate
+}
+
+type controlTemplateFile struct {
+	SchemaID      string                       `yaml:"schema_id"`
+	SchemaVersion string                       `yaml:"schema_version"`
+	TemplateID    string                       `yaml:"template_id"`
+	Version       string                       `yaml:"version"`
+	Title         string                       `yaml:"title"`
+	Controls      []controlTemplateFileControl `yaml:"controls"`
+}
+
+type controlTemplateFileControl struct {
+	ID           string   `yaml:"id"`
+	Title        string   `yaml:"title"`
+	Required     bool     `yaml:"required"`
+	EntryTypes   []string `yaml:"entry_types"`
+	PathPrefixes []string `yaml:"path_prefixes"`
+	PathMatches  []string `yaml:"path_matches"`
+}
+
 var controlTemplates = map[string][]controlTemplate{
 	templateSOC2: {
 		{ID: "CC6.6", Title: "Change Management Evidence", EntryTypes: []string{entryTypeTrace, entryTypeEvidence}, PathMatches: []string{"approval_audit_", "credential_evidence_"}},
@@ -45,41 +81,148 @@ var controlTemplates = map[string][]controlTemplate{
 	},
 }
 
-func normalizeTemplateID(templateID string) string {
-	trimmed := strings.ToLower(strings.TrimSpace(templateID))
+// resolveControlTemplate accepts a built-in template id, a path to a YAML
+// control template, or registry:<pack>[#artifact] for a template shipped in an
+// installed registry pack. Unknown ids are rejected rather than guessed.
+func resolveControlTemplate(ref string, registryCacheDir string) (controlTemplateSet, error) {
+	trimmed := strings.TrimSpace(ref)
 	if trimmed == "" {
-		return defaultTemplateID
+		trimmed = defaultTemplateID
+	}
+	if strings.HasPrefix(trimmed, registryTemplateRefPrefix) {
+		packName, artifactPath, _ := strings.Cut(strings.TrimPrefix(trimmed, registryTemplateRefPrefix), "#")
+		artifact, err := registry.ResolveArtifact(registry.ArtifactOptions{
+			CacheDir:     registryCacheDir,
+			PackName:     packName,
+			ArtifactPath: artifactPath,
+			Extensions:   []string{".yaml", ".yml"},
+		})
+		if err != nil {
+			return controlTemplateSet{}, fmt.Errorf("resolve control template %s: %w", trimmed, err)
+		}
+		return parseControlTemplate(artifact.Content)
+	}
+	lowered := strings.ToLower(trimmed)
+	if lowered == "incident" {
+		lowered = templateIncident
+	}
+	if controls, ok := controlTemplates[lowered]; ok {
+		return controlTemplateSet{ID: lowered, Controls: controls}, nil
+	}
+	if extension := strings.ToLower(filepath.Ext(trimmed)); extension == ".yaml" || extension == ".yml" {
+		// #nosec G304 -- control template path is explicit local user input.
+		content, err := os.ReadFile(trimmed)
+		if err != nil {
+			return controlTemplateSet{}, fmt.Errorf("read control template: %w", err)
+		}
+		return parseControlTemplate(content)
+	}
+	return controlTemplateSet{}, fmt.Errorf("unknown control template %q (use soc2, pci, incident_response, a .yaml template path, or registry:<pack>)", trimmed)
+}
+
+func parseControlTemplate(content []byte) (controlTemplateSet, error) {
+	if len(content) > maxControlTemplateBytes {
+		return controlTemplateSet{}, fmt.Errorf("control template exceeds %d bytes", maxControlTemplateBytes)
+	}
+	var file controlTemplateFile
+	if err := yaml.UnmarshalWithOptions(content, &file, yaml.Strict(), yaml.DisallowUnknownField()); err != nil {
+		return controlTemplateSet{}, fmt.Errorf("parse control template: %w", err)
 	}
-	if _, ok := controlTemplates[trimmed]; ok {
-		return trimmed
+	if strings.TrimSpace(file.SchemaID) != controlTemplateSchemaID {
+		return controlTemplateSet{}, fmt.Errorf("control template schema_id must be %s", controlTemplateSchemaID)
 	}
-	return templateIncident
+	if strings.TrimSpace(file.SchemaVersion) != controlTemplateSchemaVersion {
+		return controlTemplateSet{}, fmt.Errorf("unsupported control template schema_version %q", file.SchemaVersion)
+	}
+	set := controlTemplateSet{
+		ID:      strings.TrimSpace(file.TemplateID),
+		Version: strings.TrimSpace(file.Version),
+		Digest:  sha256Hex(content),
+	}
+	if set.ID == "" || set.Version == "" {
+		return controlTemplateSet{}, fmt.Errorf("control template requires template_id and version")
+	}
+	if len(file.Controls) == 0 {
+		return controlTemplateSet{}, fmt.Errorf("control template %s declares no controls", set.ID)
+	}
+	seen := make(map[string]struct{}, len(file.Controls))
+	for index, control := range file.Controls {
+		template := controlTemplate{
+			ID:          strings.TrimSpace(control.ID),
+			Title:       strings.TrimSpace(control.Title),
+			Required:    control.Required,
+			EntryTypes:  uniqueSortedStrings(trimStrings(control.EntryTypes)),
+			PathPrefix:  uniqueSortedStrings(trimStrings(control.PathPrefixes)),
+			PathMatches: uniqueSortedStrings(trimStrings(control.PathMatches)),
+		}
+		if template.ID == "" || template.Title == "" {
+			return controlTemplateSet{}, fmt.Errorf("control template controls[%d] requires id and title", index)
+		}
+		if _, ok := seen[template.ID]; ok {
+			return controlTemplateSet{}, fmt.Errorf("control template declares control %s more than once", template.ID)
+		}
+		seen[template.ID] = struct{}{}
+		for _, entryType := range template.EntryTypes {
+			switch entryType {
+			case entryTypeRunpack, entryTypeTrace, entryTypeReport, entryTypeEvidence:
+			default:
+				return controlTemplateSet{}, fmt.Errorf("control %s has unsupported entry type %q", template.ID, entryType)
+			}
+		}
+		if len(template.EntryTypes) == 0 && len(template.PathPrefix) == 0 && len(template.PathMatches) == 0 {
+			return controlTemplateSet{}, fmt.Errorf("control %s requires entry_types, path_prefixes, or path_matches", template.ID)
+		}
+		set.Controls = append(set.Controls, template)
+	}
+	return set, nil
 }
 
-func buildControlIndex(templateID string, contents []schemaguard.PackEntry) []schemaguard.Control {
-	templates, ok := controlTemplates[templateID]
-	if !ok {
-		templates = controlTemplates[defaultTemplateID]
+func trimStrings(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		out = append(out, strings.TrimSpace(value))
 	}
-	controls := make([]schemaguard.Control, 0, len(templates))
-	for _, template := range templates {
+	return out
+}
+
+func buildControlIndex(template controlTemplateSet, contents []schemaguard.PackEntry) []schemaguard.Control {
+	controls := make([]schemaguard.Control, 0, len(template.Controls))
+	for _, control := range template.Controls {
 		matchedPaths := make([]string, 0, len(contents))
 		for _, entry := range contents {
-			if !matchesControlTemplate(entry, template) {
+			if !matchesControlTemplate(entry, control) {
 				continue
 			}
 			matchedPaths = append(matchedPaths, entry.Path)
 		}
 		matchedPaths = uniqueSortedStrings(matchedPaths)
 		controls = append(controls, schemaguard.Control{
-			ControlID:     template.ID,
-			Title:         template.Title,
+			ControlID:     control.ID,
+			Title:         control.Title,
+			Required:      control.Required,
 			EvidencePaths: matchedPaths,
 		})
 	}
 	return controls
 }
 
+// controlGaps returns the ids of controls without evidence and, separately,
+// the required ones among them.
+func controlGaps(controls []schemaguard.Control) ([]string, []string) {
+	gaps := []string{}
+	required := []string{}
+	for _, control := range controls {
+		if len(control.EvidencePaths) > 0 {
+			continue
+		}
+		gaps = append(gaps, control.ControlID)
+		if control.Required {
+			required = append(required, control.ControlID)
+		}
+	}
+	return gaps, required
+}
+
 func matchesControlTemplate(entry schemaguard.PackEntry, template controlTemplate) bool {
 	path := strings.TrimSpace(entry.Path)
 	if path == "" {
@@ -14,6 +14,7 @@ import (
 	schemaguard "github.com/Clyra-AI/gait/core/schema/v1/guard"
 	schemaregress "github.com/Clyra-AI/gait/core/schema/v1/regress"
 	schemarunpack "github.com/Clyra-AI/gait/core/schema/v1/runpack"
+	"github.com/Clyra-AI/gait/core/transparency"
 )
 
 func TestBuildPackV14TemplateAndPDF(t *testing.T) {
@@ -72,6 +73,68 @@ func TestBuildPackV14TemplateAndPDF(t *testing.T) {
 	}
 }
 
+func TestBuildPackYAMLControlTemplateReportsGaps(t *testing.T) {
+	workDir := t.TempDir()
+	runpackPath := filepath.Join(workDir, "runpack_run_iso.zip")
+	if _, err := runpack.WriteRunpack(runpackPath, runpack.RecordOptions{
+		Run:         schemarunpack.Run{RunID: "run_iso", CreatedAt: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), ProducerVersion: "0.0.0-dev"},
+		Refs:        schemarunpack.Refs{RunID: "run_iso"},
+		CaptureMode: "reference",
+	}); err != nil {
+		t.Fatalf("write runpack: %v", err)
+	}
+	templatePath := filepath.Join(workDir, "iso27001.yaml")
+	templateYAML := `schema_id: gait.guard.control_template
+schema_version: 1.0.0
+template_id: iso27001
+version: "2022.1"
+title: ISO/IEC 27001:2022 Annex A
+controls:
+  - id: A.8.15
+    title: Logging
+    required: true
+    entry_types: [runpack]
+  - id: A.5.24
+    title: Incident management planning
+    required: true
+    path_matches: [approval_audit_]
+  - id: A.5.1
+    title: Policies for information security
+    path_prefixes: [policies/]
+`
+	if err := os.WriteFile(templatePath, []byte(templateYAML), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	result, err := BuildPack(BuildOptions{
+		RunpackPath:     runpackPath,
+		OutputPath:      filepath.Join(workDir, "evidence_pack_iso.zip"),
+		TemplateID:      templatePath,
+		ProducerVersion: "0.0.0-dev",
+	})
+	if err != nil {
+		t.Fatalf("build pack with yaml template: %v", err)
+	}
+	manifest := result.Manifest
+	if manifest.TemplateID != "iso27001" || manifest.TemplateVersion != "2022.1" || len(manifest.TemplateDigest) != 64 {
+		t.Fatalf("unexpected template identity: id=%s version=%s digest=%s", manifest.TemplateID, manifest.TemplateVersion, manifest.TemplateDigest)
+	}
+	if len(manifest.ControlIndex) != 3 || !manifest.ControlIndex[0].Required || len(manifest.ControlIndex[0].EvidencePaths) == 0 {
+		t.Fatalf("unexpected control index: %#v", manifest.ControlIndex)
+	}
+	if strings.Join(manifest.ControlGaps, ",") != "A.5.24,A.5.1" || strings.Join(result.RequiredGaps, ",") != "A.5.24" {
+		t.Fatalf("unexpected gaps: all=%v required=%v", manifest.ControlGaps, result.RequiredGaps)
+	}
+
+	invalidPath := filepath.Join(workDir, "invalid.yaml")
+	if err := os.WriteFile(invalidPath, []byte(strings.Replace(templateYAML, "entry_types: [runpack]", "entry_types: [ticket]", 1)), 0o600); err != nil {
+		t.Fatalf("write invalid template: %v", err)
+	}
+	if _, err := resolveControlTemplate(invalidPath, ""); err == nil || !strings.Contains(err.Error(), "unsupported entry type") {
+		t.Fatalf("expected unsupported entry type error, got %v", err)
+	}
+}
+
 func TestApplyRetentionV14(t *testing.T) {
 	workDir := t.TempDir()
 	oldTrace := filepath.Join(workDir, "trace_old.json")
@@ -136,6 +199,54 @@ func TestApplyRetentionV14(t *testing.T) {
 	}
 }
 
+func TestApplyRetentionRecordsDeletionsInTransparencyLog(t *testing.T) {
+	workDir := t.TempDir()
+	logDir := filepath.Join(t.TempDir(), "transparency")
+	tracePath := filepath.Join(workDir, "trace_old.json")
+	if err := os.WriteFile(tracePath, []byte(`{"schema_id":"gait.gate.trace","trace_id":"trace_old"}`), 0o600); err != nil {
+		t.Fatalf("write trace: %v", err)
+	}
+	recorded, err := transparency.DescribeArtifact(tracePath)
+	if err != nil {
+		t.Fatalf("describe trace: %v", err)
+	}
+	if _, err := transparency.Append(logDir, recorded, transparency.AppendOptions{}); err != nil {
+		t.Fatalf("record trace: %v", err)
+	}
+	now := time.Date(2026, time.February, 6, 10, 0, 0, 0, time.UTC)
+	oldTime := now.Add(-200 * time.Hour)
+	if err := os.Chtimes(tracePath, oldTime, oldTime); err != nil {
+		t.Fatalf("set old mtime: %v", err)
+	}
+
+	result, err := ApplyRetention(RetentionOptions{
+		RootPath:        workDir,
+		TraceTTL:        24 * time.Hour,
+		Now:             now,
+		TransparencyLog: logDir,
+	})
+	if err != nil {
+		t.Fatalf("apply retention: %v", err)
+	}
+	if len(result.DeletedFiles) != 1 || result.DeletedFiles[0].Digest != recorded.Digest || result.DeletedFiles[0].LogIndex == nil || *result.DeletedFiles[0].LogIndex != 1 {
+		t.Fatalf("expected deletion to be logged after the original entry: %#v", result.DeletedFiles)
+	}
+	entries, err := transparency.LoadEntries(logDir)
+	if err != nil {
+		t.Fatalf("load entries: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Kind != transparency.KindTrace || entries[1].Kind != transparency.KindRetentionDelete || entries[1].Digest != recorded.Digest {
+		t.Fatalf("expected original entry to survive retention: %#v", entries)
+	}
+	proof, err := transparency.ProveInclusion(logDir, transparency.ProveOptions{Digest: recorded.Digest})
+	if err != nil {
+		t.Fatalf("prove deleted trace: %v", err)
+	}
+	if proof.LeafIndex != 0 || transparency.VerifyInclusion(proof, recorded.Digest, nil) != nil {
+		t.Fatalf("expected deleted trace to stay provable: %#v", proof)
+	}
+}
+
 func TestEncryptDecryptArtifactV14(t *testing.T) {
 	workDir := t.TempDir()
 	sourcePath := filepath.Join(workDir, "artifact.json")
@@ -281,14 +392,17 @@ func TestGuardV14HelperBranches(t *testing.T) {
 		t.Fatalf("normalizePackPath valid path mismatch: path=%s err=%v", normalized, err)
 	}
 
-	if got := normalizeTemplateID(""); got != defaultTemplateID {
-		t.Fatalf("normalizeTemplateID default mismatch: %s", got)
+	if got, err := resolveControlTemplate("", ""); err != nil || got.ID != defaultTemplateID {
+		t.Fatalf("resolveControlTemplate default mismatch: %#v err=%v", got, err)
+	}
+	if _, err := resolveControlTemplate("unknown-template", ""); err == nil || !strings.Contains(err.Error(), "unknown control template") {
+		t.Fatalf("expected unknown template id to be rejected, got %v", err)
 	}
-	if got := normalizeTemplateID("unknown-template"); got != templateIncident {
-		t.Fatalf("normalizeTemplateID fallback mismatch: %s", got)
+	if got, err := resolveControlTemplate("SOC2", ""); err != nil || got.ID != templateSOC2 {
+		t.Fatalf("resolveControlTemplate case mismatch: %#v err=%v", got, err)
 	}
-	if got := normalizeTemplateID("SOC2"); got != templateSOC2 {
-		t.Fatalf("normalizeTemplateID case mismatch: %s", got)
+	if got, err := resolveControlTemplate("incident", ""); err != nil || got.ID != templateIncident {
+		t.Fatalf("resolveControlTemplate incident alias mismatch: %#v err=%v", got, err)
 	}
 
 	if normalizeIncidentWindow(nil) != nil {
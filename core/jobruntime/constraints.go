@@ -0,0 +1,253 @@
+package jobruntime
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	StopReasonJobBudgetExhausted = "job_budget_exhausted"
+
+	ReasonJobPolicyDigestMismatch       = "job_policy_digest_mismatch"
+	ReasonJobToolNotAllowed             = "job_tool_not_allowed"
+	ReasonJobNotRunning                 = "job_not_running"
+	ReasonJobToolCallBudgetExhausted    = "job_tool_call_budget_exhausted"
+	ReasonJobDestructiveBudgetExhausted = "job_destructive_budget_exhausted"
+	ReasonJobSpendCeilingExceeded       = "job_spend_ceiling_exceeded"
+)
+
+// JobConstraints are job-scoped limits enforced on every gate call that
+// carries the job id. Zero values leave a limit unset.
+type JobConstraints struct {
+	MaxToolCalls      int      `json:"max_tool_calls,omitempty"`
+	MaxDestructiveOps int      `json:"max_destructive_ops,omitempty"`
+	AllowedTools      []string `json:"allowed_tools,omitempty"`
+	SpendCeiling      float64  `json:"spend_ceiling,omitempty"`
+}
+
+type JobUsage struct {
+	ToolCalls      int     `json:"tool_calls"`
+	DestructiveOps int     `json:"destructive_ops"`
+	Spend          float64 `json:"spend"`
+}
+
+type ToolCallCharge struct {
+	ToolName     string
+	PolicyDigest string
+	Destructive  bool
+	Cost         float64
+	Actor        string
+	Now          time.Time
+	// OnTransition runs when the charge exhausts a budget and moves the job
+	// to decision_needed.
+	OnTransition func(Transition)
+}
+
+type ChargeDecision struct {
+	Allowed     bool
+	ReasonCodes []string
+	State       JobState
+	Checkpoint  *Checkpoint
+}
+
+type ConstraintOptions struct {
+	Constraints JobConstraints
+	Actor       string
+	Now         time.Time
+}
+
+func (c JobConstraints) IsZero() bool {
+	return c.MaxToolCalls == 0 && c.MaxDestructiveOps == 0 && len(c.AllowedTools) == 0 && c.SpendCeiling == 0
+}
+
+func normalizeConstraints(constraints JobConstraints) (JobConstraints, error) {
+	if constraints.MaxToolCalls < 0 || constraints.MaxDestructiveOps < 0 {
+		return JobConstraints{}, fmt.Errorf("job constraints must be >= 0")
+	}
+	if math.IsNaN(constraints.SpendCeiling) || math.IsInf(constraints.SpendCeiling, 0) || constraints.SpendCeiling < 0 {
+		return JobConstraints{}, fmt.Errorf("job spend ceiling must be a finite value >= 0")
+	}
+	seen := map[string]struct{}{}
+	tools := make([]string, 0, len(constraints.AllowedTools))
+	for _, tool := range constraints.AllowedTools {
+		trimmed := strings.TrimSpace(tool)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		tools = append(tools, trimmed)
+	}
+	sort.Strings(tools)
+	constraints.AllowedTools = tools
+	if len(tools) == 0 {
+		constraints.AllowedTools = nil
+	}
+	return constraints, nil
+}
+
+// UpdateConstraints replaces the job's constraints, for example to raise a
+// budget before approving and resuming a job paused on exhaustion. Usage
+// accumulated so far is kept.
+func UpdateConstraints(root string, jobID string, opts ConstraintOptions) (JobState, error) {
+	constraints, err := normalizeConstraints(opts.Constraints)
+	if err != nil {
+		return JobState{}, err
+	}
+	return mutateWithResult(root, jobID, opts.Now, func(state *JobState, _ time.Time) (JobState, Event, error) {
+		switch state.Status {
+		case StatusCompleted, StatusCancelled, StatusEmergencyStop:
+			return JobState{}, Event{}, fmt.Errorf("%w: constraints from %s", ErrInvalidTransition, state.Status)
+		}
+		if constraints.IsZero() {
+			state.Constraints = nil
+		} else {
+			state.Constraints = &constraints
+			if state.Usage == nil {
+				state.Usage = &JobUsage{}
+			}
+		}
+		updated := *state
+		return updated, Event{
+			Type:       "constraints_updated",
+			Actor:      strings.TrimSpace(opts.Actor),
+			ReasonCode: "constraints_updated",
+			Payload:    constraintsPayload(constraints),
+		}, nil
+	})
+}
+
+// ChargeToolCall checks a gate-allowed call against the job's pinned policy
+// digest and constraints and, when it fits, records its usage in the same
+// locked mutation. A call that would exceed a budget is refused, and the job
+// moves to decision_needed with a checkpoint naming the exhausted budget.
+// Jobs without a pinned digest or constraints are not mutated.
+func ChargeToolCall(root string, jobID string, charge ToolCallCharge) (ChargeDecision, error) {
+	if math.IsNaN(charge.Cost) || math.IsInf(charge.Cost, 0) || charge.Cost < 0 {
+		return ChargeDecision{}, fmt.Errorf("tool call cost must be a finite value >= 0")
+	}
+	current, err := Status(root, jobID)
+	if err != nil {
+		return ChargeDecision{}, err
+	}
+	if current.Constraints == nil && strings.TrimSpace(current.PolicyDigest) == "" {
+		return ChargeDecision{Allowed: true, State: current}, nil
+	}
+
+	decision := ChargeDecision{}
+	updated, err := mutateWithResult(root, jobID, charge.Now, func(state *JobState, now time.Time) (JobState, Event, error) {
+		toolName := strings.TrimSpace(charge.ToolName)
+		event := Event{
+			Actor:   strings.TrimSpace(charge.Actor),
+			Payload: map[string]any{"tool_name": toolName},
+		}
+		block := func(reasonCode string) (JobState, Event, error) {
+			decision.ReasonCodes = []string{reasonCode}
+			event.Type = "tool_call_blocked"
+			event.ReasonCode = reasonCode
+			return *state, event, nil
+		}
+
+		pinned := strings.TrimSpace(state.PolicyDigest)
+		if pinned != "" && pinned != strings.TrimSpace(charge.PolicyDigest) {
+			event.Payload["expected_policy_digest"] = pinned
+			event.Payload["actual_policy_digest"] = strings.TrimSpace(charge.PolicyDigest)
+			return block(ReasonJobPolicyDigestMismatch)
+		}
+		constraints := state.Constraints
+		if constraints == nil {
+			decision.Allowed = true
+			event.Type = "tool_call_charged"
+			event.ReasonCode = "tool_call_charged"
+			return *state, event, nil
+		}
+		if state.Status != StatusRunning {
+			event.Payload["status"] = state.Status
+			return block(ReasonJobNotRunning)
+		}
+		if len(constraints.AllowedTools) > 0 && !contains(constraints.AllowedTools, toolName) {
+			return block(ReasonJobToolNotAllowed)
+		}
+
+		usage := JobUsage{}
+		if state.Usage != nil {
+			usage = *state.Usage
+		}
+		exhausted := ""
+		summary := ""
+		switch {
+		case constraints.MaxToolCalls > 0 && usage.ToolCalls+1 > constraints.MaxToolCalls:
+			exhausted = ReasonJobToolCallBudgetExhausted
+			summary = fmt.Sprintf("tool call budget exhausted (%d/%d) at %s", usage.ToolCalls, constraints.MaxToolCalls, toolName)
+		case charge.Destructive && constraints.MaxDestructiveOps > 0 && usage.DestructiveOps+1 > constraints.MaxDestructiveOps:
+			exhausted = ReasonJobDestructiveBudgetExhausted
+			summary = fmt.Sprintf("destructive operation budget exhausted (%d/%d) at %s", usage.DestructiveOps, constraints.MaxDestructiveOps, toolName)
+		case constraints.SpendCeiling > 0 && usage.Spend+charge.Cost > constraints.SpendCeiling:
+			exhausted = ReasonJobSpendCeilingExceeded
+			summary = fmt.Sprintf("spend ceiling %g reached (spent %g, call cost %g) at %s", constraints.SpendCeiling, usage.Spend, charge.Cost, toolName)
+		}
+		if exhausted != "" {
+			checkpoint := Checkpoint{
+				CheckpointID:   fmt.Sprintf("cp_%04d", len(state.Checkpoints)+1),
+				CreatedAt:      now,
+				Type:           CheckpointTypeDecisionNeeded,
+				Summary:        summary,
+				RequiredAction: "raise the job constraints and approve, or cancel the job",
+				ReasonCode:     exhausted,
+				Actor:          strings.TrimSpace(charge.Actor),
+			}
+			state.Checkpoints = append(state.Checkpoints, checkpoint)
+			state.Status = StatusDecisionNeeded
+			state.StopReason = StopReasonJobBudgetExhausted
+			state.StatusReasonCode = exhausted
+			decision.Checkpoint = &checkpoint
+			event.Payload["checkpoint_id"] = checkpoint.CheckpointID
+			event.Payload["checkpoint_type"] = checkpoint.Type
+			return block(exhausted)
+		}
+
+		usage.ToolCalls++
+		if charge.Destructive {
+			usage.DestructiveOps++
+		}
+		usage.Spend += charge.Cost
+		state.Usage = &usage
+		decision.Allowed = true
+		event.Type = "tool_call_charged"
+		event.ReasonCode = "tool_call_charged"
+		event.Payload["tool_calls"] = usage.ToolCalls
+		event.Payload["destructive_ops"] = usage.DestructiveOps
+		event.Payload["spend"] = usage.Spend
+		return *state, event, nil
+	})
+	if err != nil {
+		return ChargeDecision{}, err
+	}
+	decision.State = updated
+	if decision.Checkpoint != nil {
+		reportTransition(charge.OnTransition, TransitionDecisionNeeded, updated, charge.Actor, decision.Checkpoint.ReasonCode)
+	}
+	return decision, nil
+}
+
+func constraintsPayload(constraints JobConstraints) map[string]any {
+	payload := map[string]any{}
+	if constraints.MaxToolCalls > 0 {
+		payload["max_tool_calls"] = constraints.MaxToolCalls
+	}
+	if constraints.MaxDestructiveOps > 0 {
+		payload["max_destructive_ops"] = constraints.MaxDestructiveOps
+	}
+	if len(constraints.AllowedTools) > 0 {
+		payload["allowed_tools"] = append([]string{}, constraints.AllowedTools...)
+	}
+	if constraints.SpendCeiling > 0 {
+		payload["spend_ceiling"] = constraints.SpendCeiling
+	}
+	return payload
+}
@@ -0,0 +1,121 @@
+package jobruntime
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSubmitNormalizesConstraints(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "jobs")
+	state, err := Submit(root, SubmitOptions{JobID: "job-constraints", Constraints: JobConstraints{
+		MaxToolCalls: 5,
+		AllowedTools: []string{" tool.write", "tool.read", "tool.write", ""},
+	}})
+	if err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	if state.Constraints == nil || !reflect.DeepEqual(state.Constraints.AllowedTools, []string{"tool.read", "tool.write"}) || state.Usage == nil {
+		t.Fatalf("unexpected constraints: %#v usage=%#v", state.Constraints, state.Usage)
+	}
+	if _, err := Submit(root, SubmitOptions{JobID: "job-negative", Constraints: JobConstraints{MaxToolCalls: -1}}); err == nil {
+		t.Fatalf("expected negative budget to be rejected")
+	}
+	plain, err := Submit(root, SubmitOptions{JobID: "job-plain"})
+	if err != nil || plain.Constraints != nil || plain.Usage != nil {
+		t.Fatalf("expected unconstrained job, state=%#v err=%v", plain, err)
+	}
+	decision, err := ChargeToolCall(root, "job-plain", ToolCallCharge{ToolName: "tool.any", Cost: 10})
+	if err != nil || !decision.Allowed || decision.State.Revision != plain.Revision {
+		t.Fatalf("expected unconstrained job to pass without mutation, decision=%#v err=%v", decision, err)
+	}
+}
+
+func TestChargeToolCallPausesJobWhenBudgetIsExhausted(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "jobs")
+	if _, err := Submit(root, SubmitOptions{JobID: "job-budget", Constraints: JobConstraints{
+		MaxDestructiveOps: 1,
+		SpendCeiling:      1.5,
+		AllowedTools:      []string{"tool.delete", "tool.write"},
+	}}); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+
+	decision, err := ChargeToolCall(root, "job-budget", ToolCallCharge{ToolName: "tool.read"})
+	if err != nil || decision.Allowed || !reflect.DeepEqual(decision.ReasonCodes, []string{ReasonJobToolNotAllowed}) || decision.State.Status != StatusRunning {
+		t.Fatalf("expected disallowed tool to be blocked without pausing, decision=%#v err=%v", decision, err)
+	}
+	decision, err = ChargeToolCall(root, "job-budget", ToolCallCharge{ToolName: "tool.delete", Destructive: true, Cost: 1})
+	if err != nil || !decision.Allowed || decision.State.Usage.DestructiveOps != 1 || decision.State.Usage.Spend != 1 {
+		t.Fatalf("expected first destructive call to be charged, decision=%#v err=%v", decision, err)
+	}
+	decision, err = ChargeToolCall(root, "job-budget", ToolCallCharge{ToolName: "tool.delete", Destructive: true})
+	if err != nil || decision.Allowed || decision.Checkpoint == nil || decision.Checkpoint.ReasonCode != ReasonJobDestructiveBudgetExhausted {
+		t.Fatalf("expected destructive budget exhaustion, decision=%#v err=%v", decision, err)
+	}
+	if decision.State.Status != StatusDecisionNeeded || decision.State.StopReason != StopReasonJobBudgetExhausted || decision.State.Usage.DestructiveOps != 1 {
+		t.Fatalf("expected job to wait for a decision, state=%#v", decision.State)
+	}
+	decision, err = ChargeToolCall(root, "job-budget", ToolCallCharge{ToolName: "tool.write"})
+	if err != nil || decision.Allowed || !reflect.DeepEqual(decision.ReasonCodes, []string{ReasonJobNotRunning}) {
+		t.Fatalf("expected calls to be refused while paused, decision=%#v err=%v", decision, err)
+	}
+	if _, err := Resume(root, "job-budget", ResumeOptions{}); !errors.Is(err, ErrApprovalRequired) {
+		t.Fatalf("expected resume to require approval, got %v", err)
+	}
+
+	if _, err := UpdateConstraints(root, "job-budget", ConstraintOptions{Constraints: JobConstraints{SpendCeiling: 1.5}, Actor: "alice"}); err != nil {
+		t.Fatalf("update constraints: %v", err)
+	}
+	if _, err := Approve(root, "job-budget", ApprovalOptions{Actor: "alice"}); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if _, err := Resume(root, "job-budget", ResumeOptions{}); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	decision, err = ChargeToolCall(root, "job-budget", ToolCallCharge{ToolName: "tool.read", Cost: 0.5})
+	if err != nil || !decision.Allowed || decision.State.Usage.ToolCalls != 2 || decision.State.Usage.Spend != 1.5 {
+		t.Fatalf("expected raised constraints to allow the call, decision=%#v err=%v", decision, err)
+	}
+	decision, err = ChargeToolCall(root, "job-budget", ToolCallCharge{ToolName: "tool.read", Cost: 0.01})
+	if err != nil || decision.Allowed || !reflect.DeepEqual(decision.ReasonCodes, []string{ReasonJobSpendCeilingExceeded}) {
+		t.Fatalf("expected spend ceiling to be enforced, decision=%#v err=%v", decision, err)
+	}
+
+	_, events, err := Inspect(root, "job-budget")
+	if err != nil {
+		t.Fatalf("inspect: %v", err)
+	}
+	counts := map[string]int{}
+	for _, event := range events {
+		counts[event.Type]++
+	}
+	if counts["tool_call_charged"] != 2 || counts["tool_call_blocked"] != 4 || counts["constraints_updated"] != 1 {
+		t.Fatalf("unexpected event counts: %#v", counts)
+	}
+}
+
+func TestChargeToolCallEnforcesPinnedPolicyDigest(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "jobs")
+	if _, err := Submit(root, SubmitOptions{JobID: "job-pinned", PolicyDigest: "sha256:aaa"}); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	decision, err := ChargeToolCall(root, "job-pinned", ToolCallCharge{ToolName: "tool.write", PolicyDigest: "sha256:bbb"})
+	if err != nil || decision.Allowed || !reflect.DeepEqual(decision.ReasonCodes, []string{ReasonJobPolicyDigestMismatch}) {
+		t.Fatalf("expected digest mismatch block, decision=%#v err=%v", decision, err)
+	}
+	decision, err = ChargeToolCall(root, "job-pinned", ToolCallCharge{ToolName: "tool.write", PolicyDigest: "sha256:aaa"})
+	if err != nil || !decision.Allowed || decision.State.Usage != nil {
+		t.Fatalf("expected matching digest to pass, decision=%#v err=%v", decision, err)
+	}
+	if _, err := ChargeToolCall(root, "job-missing", ToolCallCharge{ToolName: "tool.write"}); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("expected missing job error, got %v", err)
+	}
+	if _, err := Cancel(root, "job-pinned", TransitionOptions{}); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if _, err := UpdateConstraints(root, "job-pinned", ConstraintOptions{Constraints: JobConstraints{MaxToolCalls: 1}}); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected constraints update on cancelled job to fail, got %v", err)
+	}
+}
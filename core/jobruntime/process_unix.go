@@ -0,0 +1,23 @@
+//go:build !windows
+
+package jobruntime
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+func setAgentProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func signalAgentProcess(cmd *exec.Cmd, force bool) error {
+	if cmd == nil || cmd.Process == nil {
+		return nil
+	}
+	signal := syscall.SIGTERM
+	if force {
+		signal = syscall.SIGKILL
+	}
+	return syscall.Kill(-cmd.Process.Pid, signal)
+}
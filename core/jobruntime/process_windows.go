@@ -0,0 +1,14 @@
+//go:build windows
+
+package jobruntime
+
+import "os/exec"
+
+func setAgentProcessGroup(_ *exec.Cmd) {}
+
+func signalAgentProcess(cmd *exec.Cmd, _ bool) error {
+	if cmd == nil || cmd.Process == nil {
+		return nil
+	}
+	return cmd.Process.Kill()
+}
@@ -44,6 +44,16 @@ const (
 	StopReasonCancelledByUser        = "cancelled_by_user"
 	StopReasonEmergencyStopped       = "emergency_stopped"
 	StopReasonEnvFingerprintMismatch = "env_fingerprint_mismatch"
+	StopReasonWallClockBudget        = "wall_clock_budget_exceeded"
+	StopReasonStepBudget             = "step_budget_exceeded"
+)
+
+const (
+	TransitionPaused           = "paused"
+	TransitionDecisionNeeded   = "decision_needed"
+	TransitionEmergencyStopped = "emergency_stopped"
+	TransitionCancelled        = "cancelled"
+	TransitionApproved         = "approved"
 )
 
 const (
@@ -76,25 +86,27 @@ var (
 var safeJobIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]{0,127}$`)
 
 type JobState struct {
-	SchemaID               string       `json:"schema_id"`
-	SchemaVersion          string       `json:"schema_version"`
-	CreatedAt              time.Time    `json:"created_at"`
-	UpdatedAt              time.Time    `json:"updated_at"`
-	ProducerVersion        string       `json:"producer_version"`
-	JobID                  string       `json:"job_id"`
-	Status                 string       `json:"status"`
-	StopReason             string       `json:"stop_reason"`
-	StatusReasonCode       string       `json:"status_reason_code"`
-	EnvironmentFingerprint string       `json:"environment_fingerprint"`
-	SafetyInvariantVersion string       `json:"safety_invariant_version,omitempty"`
-	SafetyInvariantHash    string       `json:"safety_invariant_hash,omitempty"`
-	SafetyInvariants       []string     `json:"safety_invariants,omitempty"`
-	PolicyDigest           string       `json:"policy_digest,omitempty"`
-	PolicyRef              string       `json:"policy_ref,omitempty"`
-	Identity               string       `json:"identity,omitempty"`
-	Revision               int64        `json:"revision"`
-	Checkpoints            []Checkpoint `json:"checkpoints"`
-	Approvals              []Approval   `json:"approvals,omitempty"`
+	SchemaID               string          `json:"schema_id"`
+	SchemaVersion          string          `json:"schema_version"`
+	CreatedAt              time.Time       `json:"created_at"`
+	UpdatedAt              time.Time       `json:"updated_at"`
+	ProducerVersion        string          `json:"producer_version"`
+	JobID                  string          `json:"job_id"`
+	Status                 string          `json:"status"`
+	StopReason             string          `json:"stop_reason"`
+	StatusReasonCode       string          `json:"status_reason_code"`
+	EnvironmentFingerprint string          `json:"environment_fingerprint"`
+	SafetyInvariantVersion string          `json:"safety_invariant_version,omitempty"`
+	SafetyInvariantHash    string          `json:"safety_invariant_hash,omitempty"`
+	SafetyInvariants       []string        `json:"safety_invariants,omitempty"`
+	PolicyDigest           string          `json:"policy_digest,omitempty"`
+	PolicyRef              string          `json:"policy_ref,omitempty"`
+	Identity               string          `json:"identity,omitempty"`
+	Constraints            *JobConstraints `json:"constraints,omitempty"`
+	Usage                  *JobUsage       `json:"usage,omitempty"`
+	Revision               int64           `json:"revision"`
+	Checkpoints            []Checkpoint    `json:"checkpoints"`
+	Approvals              []Approval      `json:"approvals,omitempty"`
 }
 
 type Checkpoint struct {
@@ -155,6 +167,7 @@ type SubmitOptions struct {
 	PolicyDigest           string
 	PolicyRef              string
 	Identity               string
+	Constraints            JobConstraints
 	Actor                  string
 	Now                    time.Time
 }
@@ -165,6 +178,10 @@ type CheckpointOptions struct {
 	RequiredAction string
 	Actor          string
 	Now            time.Time
+	// OnStop runs after a completed checkpoint is committed.
+	OnStop func(JobState)
+
+	requireRunning bool
 }
 
 type ResumeOptions struct {
@@ -183,14 +200,30 @@ type ResumeOptions struct {
 }
 
 type ApprovalOptions struct {
-	Actor  string
-	Reason string
-	Now    time.Time
+	Actor        string
+	Reason       string
+	Now          time.Time
+	OnTransition func(Transition)
 }
 
 type TransitionOptions struct {
 	Actor string
 	Now   time.Time
+	// OnStop runs after Cancel or EmergencyStop commits, so callers can release
+	// job-bound resources such as brokered credentials.
+	OnStop func(JobState)
+	// OnTransition runs after the transition commits, whoever drove it.
+	OnTransition func(Transition)
+}
+
+// Transition reports a committed pause, decision pause, emergency stop,
+// cancel or approval. Reason is the approval reason or the reason code that
+// caused a pause.
+type Transition struct {
+	Kind   string
+	State  JobState
+	Actor  string
+	Reason string
 }
 
 type DispatchRecordOptions struct {
@@ -220,6 +253,10 @@ func Submit(root string, opts SubmitOptions) (JobState, error) {
 	if identity == "" {
 		identity = strings.TrimSpace(opts.Actor)
 	}
+	constraints, err := normalizeConstraints(opts.Constraints)
+	if err != nil {
+		return JobState{}, err
+	}
 	files, err := resolveJobFiles(root, jobID)
 	if err != nil {
 		return JobState{}, err
@@ -262,6 +299,10 @@ func Submit(root string, opts SubmitOptions) (JobState, error) {
 		Revision:               1,
 		Checkpoints:            []Checkpoint{},
 	}
+	if !constraints.IsZero() {
+		state.Constraints = &constraints
+		state.Usage = &JobUsage{}
+	}
 	state.SafetyInvariants = deriveSafetyInvariants(state)
 	state.SafetyInvariantHash = hashSafetyInvariants(state.SafetyInvariants)
 	eventPayload := map[string]any{
@@ -276,6 +317,9 @@ func Submit(root string, opts SubmitOptions) (JobState, error) {
 	if identity != "" {
 		eventPayload["identity"] = identity
 	}
+	if state.Constraints != nil {
+		eventPayload["constraints"] = constraintsPayload(constraints)
+	}
 	event := Event{
 		SchemaID:      eventSchemaID,
 		SchemaVersion: jobSchemaVersion,
@@ -344,6 +388,9 @@ func GetCheckpoint(root string, jobID string, checkpointID string) (Checkpoint,
 func AddCheckpoint(root string, jobID string, opts CheckpointOptions) (JobState, Checkpoint, error) {
 	var emitted Checkpoint
 	updated, err := mutate(root, jobID, func(state *JobState, now time.Time) (Event, error) {
+		if opts.requireRunning && state.Status != StatusRunning {
+			return Event{}, fmt.Errorf("%w: checkpoint from %s", ErrInvalidTransition, state.Status)
+		}
 		typeValue := strings.TrimSpace(opts.Type)
 		if !isCheckpointType(typeValue) {
 			return Event{}, fmt.Errorf("%w: type must be one of plan|progress|decision-needed|blocked|completed", ErrInvalidCheckpoint)
@@ -404,19 +451,33 @@ func AddCheckpoint(root string, jobID string, opts CheckpointOptions) (JobState,
 	if err != nil {
 		return JobState{}, Checkpoint{}, err
 	}
+	if updated.Status == StatusCompleted && opts.OnStop != nil {
+		opts.OnStop(updated)
+	}
 	return updated, emitted, nil
 }
 
 func Pause(root string, jobID string, opts TransitionOptions) (JobState, error) {
-	return simpleTransition(root, jobID, opts.Now, opts.Actor, "paused", []string{StatusRunning, StatusDecisionNeeded}, StatusPaused, StopReasonPausedByUser, "paused")
+	state, err := simpleTransition(root, jobID, opts.Now, opts.Actor, "paused", []string{StatusRunning, StatusDecisionNeeded}, StatusPaused, StopReasonPausedByUser, "paused")
+	if err == nil {
+		reportTransition(opts.OnTransition, TransitionPaused, state, opts.Actor, "")
+	}
+	return state, err
 }
 
 func Cancel(root string, jobID string, opts TransitionOptions) (JobState, error) {
-	return simpleTransition(root, jobID, opts.Now, opts.Actor, "cancelled", []string{StatusRunning, StatusPaused, StatusDecisionNeeded, StatusBlocked, StatusEmergencyStop}, StatusCancelled, StopReasonCancelledByUser, "cancelled")
+	state, err := simpleTransition(root, jobID, opts.Now, opts.Actor, "cancelled", []string{StatusRunning, StatusPaused, StatusDecisionNeeded, StatusBlocked, StatusEmergencyStop}, StatusCancelled, StopReasonCancelledByUser, "cancelled")
+	if err == nil && opts.OnStop != nil {
+		opts.OnStop(state)
+	}
+	if err == nil {
+		reportTransition(opts.OnTransition, TransitionCancelled, state, opts.Actor, "")
+	}
+	return state, err
 }
 
 func EmergencyStop(root string, jobID string, opts TransitionOptions) (JobState, error) {
-	return simpleTransition(
+	state, err := simpleTransition(
 		root,
 		jobID,
 		opts.Now,
@@ -427,6 +488,20 @@ func EmergencyStop(root string, jobID string, opts TransitionOptions) (JobState,
 		StopReasonEmergencyStopped,
 		"emergency_stop_preempted",
 	)
+	if err == nil && opts.OnStop != nil {
+		opts.OnStop(state)
+	}
+	if err == nil {
+		reportTransition(opts.OnTransition, TransitionEmergencyStopped, state, opts.Actor, "")
+	}
+	return state, err
+}
+
+func reportTransition(hook func(Transition), kind string, state JobState, actor string, reason string) {
+	if hook == nil {
+		return
+	}
+	hook(Transition{Kind: kind, State: state, Actor: strings.TrimSpace(actor), Reason: reason})
 }
 
 func RecordBlockedDispatch(root string, jobID string, opts DispatchRecordOptions) (JobState, error) {
@@ -459,15 +534,15 @@ func IsEmergencyStopped(state JobState) bool {
 }
 
 func Approve(root string, jobID string, opts ApprovalOptions) (JobState, error) {
-	return mutateWithResult(root, jobID, opts.Now, func(state *JobState, now time.Time) (JobState, Event, error) {
+	reason := strings.TrimSpace(opts.Reason)
+	if reason == "" {
+		reason = "approved"
+	}
+	state, err := mutateWithResult(root, jobID, opts.Now, func(state *JobState, now time.Time) (JobState, Event, error) {
 		actor := strings.TrimSpace(opts.Actor)
 		if actor == "" {
 			return JobState{}, Event{}, fmt.Errorf("approval actor is required")
 		}
-		reason := strings.TrimSpace(opts.Reason)
-		if reason == "" {
-			reason = "approved"
-		}
 		state.Approvals = append(state.Approvals, Approval{
 			CreatedAt: now,
 			Actor:     actor,
@@ -484,6 +559,10 @@ func Approve(root string, jobID string, opts ApprovalOptions) (JobState, error)
 			},
 		}, nil
 	})
+	if err == nil {
+		reportTransition(opts.OnTransition, TransitionApproved, state, opts.Actor, reason)
+	}
+	return state, err
 }
 
 func Resume(root string, jobID string, opts ResumeOptions) (JobState, error) {
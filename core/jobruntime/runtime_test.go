@@ -1286,3 +1286,75 @@ func TestJobPathAndInvariantHelperValidationBranches(t *testing.T) {
 		t.Fatalf("expected blank invariants to be ignored, with=%s without=%s", withBlanks, withoutBlanks)
 	}
 }
+
+func TestOnStopRunsOnlyForTerminalTransitions(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "jobs")
+	stopped := []string{}
+	onStop := func(state JobState) {
+		stopped = append(stopped, state.JobID+":"+state.Status)
+	}
+	for _, jobID := range []string{"job-cancel", "job-stop", "job-complete"} {
+		if _, err := Submit(root, SubmitOptions{JobID: jobID}); err != nil {
+			t.Fatalf("submit %s: %v", jobID, err)
+		}
+	}
+	if _, err := Pause(root, "job-cancel", TransitionOptions{OnStop: onStop}); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if _, err := Cancel(root, "job-cancel", TransitionOptions{OnStop: onStop}); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if _, err := EmergencyStop(root, "job-stop", TransitionOptions{OnStop: onStop}); err != nil {
+		t.Fatalf("emergency stop: %v", err)
+	}
+	if _, _, err := AddCheckpoint(root, "job-complete", CheckpointOptions{Type: CheckpointTypeProgress, Summary: "halfway", OnStop: onStop}); err != nil {
+		t.Fatalf("progress checkpoint: %v", err)
+	}
+	if _, _, err := AddCheckpoint(root, "job-complete", CheckpointOptions{Type: CheckpointTypeCompleted, Summary: "done", OnStop: onStop}); err != nil {
+		t.Fatalf("completed checkpoint: %v", err)
+	}
+	expected := []string{"job-cancel:" + StatusCancelled, "job-stop:" + StatusEmergencyStop, "job-complete:" + StatusCompleted}
+	if strings.Join(stopped, ",") != strings.Join(expected, ",") {
+		t.Fatalf("unexpected on-stop calls: %v", stopped)
+	}
+}
+
+func TestOnTransitionReportsCommittedTransitions(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "jobs")
+	recorder := &transitionRecorder{}
+	if _, err := Submit(root, SubmitOptions{JobID: "job-hook", Constraints: JobConstraints{MaxToolCalls: 1}}); err != nil {
+		t.Fatalf("submit: %v", err)
+	}
+	for range 2 {
+		if _, err := ChargeToolCall(root, "job-hook", ToolCallCharge{ToolName: "tool.write", Actor: "agent", OnTransition: recorder.record}); err != nil {
+			t.Fatalf("charge: %v", err)
+		}
+	}
+	if _, err := Approve(root, "job-hook", ApprovalOptions{Actor: "oncall", Reason: "raise budget", OnTransition: recorder.record}); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if _, err := Pause(root, "job-hook", TransitionOptions{Actor: "oncall", OnTransition: recorder.record}); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if _, err := Pause(root, "job-hook", TransitionOptions{Actor: "oncall", OnTransition: recorder.record}); err == nil {
+		t.Fatalf("expected second pause to be an invalid transition")
+	}
+	if _, err := EmergencyStop(root, "job-hook", TransitionOptions{Actor: "oncall", OnTransition: recorder.record}); err != nil {
+		t.Fatalf("emergency stop: %v", err)
+	}
+	if _, err := Cancel(root, "job-hook", TransitionOptions{Actor: "oncall", OnTransition: recorder.record}); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+
+	recorded := recorder.all()
+	expected := []string{TransitionDecisionNeeded, TransitionApproved, TransitionPaused, TransitionEmergencyStopped, TransitionCancelled}
+	if strings.Join(recorder.kinds(), ",") != strings.Join(expected, ",") {
+		t.Fatalf("unexpected transitions: %v", recorder.kinds())
+	}
+	if recorded[0].Reason != ReasonJobToolCallBudgetExhausted || recorded[0].Actor != "agent" || recorded[0].State.Status != StatusDecisionNeeded {
+		t.Fatalf("unexpected decision transition: %#v", recorded[0])
+	}
+	if recorded[1].Reason != "raise budget" || recorded[4].State.Status != StatusCancelled {
+		t.Fatalf("unexpected approval or cancel transition: %#v", recorded)
+	}
+}
@@ -0,0 +1,416 @@
+package jobruntime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EnvJobID   = "GAIT_JOB_ID"
+	EnvJobRoot = "GAIT_JOB_ROOT"
+
+	defaultSupervisorPollInterval = 500 * time.Millisecond
+	defaultAgentStopGracePeriod   = 10 * time.Second
+	defaultAgentRestartBackoff    = time.Second
+)
+
+// SupervisorOptions configures a supervised agent run. WallClockBudget is
+// measured from the start of Run; MaxSteps bounds the number of checkpoints
+// recorded on the job. Zero disables a budget.
+type SupervisorOptions struct {
+	Root            string
+	JobID           string
+	Command         []string
+	Dir             string
+	Env             []string
+	Stdout          io.Writer
+	Stderr          io.Writer
+	WallClockBudget time.Duration
+	MaxSteps        int
+	MaxRestarts     int
+	RestartBackoff  time.Duration
+	PollInterval    time.Duration
+	StopGracePeriod time.Duration
+	Actor           string
+	OnStop          func(JobState)
+	OnTransition    func(Transition)
+	Now             func() time.Time
+}
+
+type SupervisorStatus struct {
+	JobID             string    `json:"job_id"`
+	Status            string    `json:"status"`
+	StopReason        string    `json:"stop_reason"`
+	AgentRunning      bool      `json:"agent_running"`
+	AgentPID          int       `json:"agent_pid,omitempty"`
+	Launches          int       `json:"launches"`
+	Restarts          int       `json:"restarts"`
+	LastExitCode      *int      `json:"last_exit_code,omitempty"`
+	Steps             int       `json:"steps"`
+	MaxSteps          int       `json:"max_steps,omitempty"`
+	StartedAt         time.Time `json:"started_at"`
+	ElapsedMS         int64     `json:"elapsed_ms"`
+	WallClockBudgetMS int64     `json:"wall_clock_budget_ms,omitempty"`
+}
+
+// Supervisor drives a job by running its agent command while the job is
+// running and stopping it whenever the job leaves the running state.
+type Supervisor struct {
+	opts SupervisorOptions
+	wake chan struct{}
+
+	mu     sync.Mutex
+	status SupervisorStatus
+
+	cmd      *exec.Cmd
+	exited   chan error
+	failures int
+}
+
+func NewSupervisor(opts SupervisorOptions) (*Supervisor, error) {
+	opts.JobID = strings.TrimSpace(opts.JobID)
+	if _, err := resolveJobFiles(opts.Root, opts.JobID); err != nil {
+		return nil, err
+	}
+	if len(opts.Command) == 0 || strings.TrimSpace(opts.Command[0]) == "" {
+		return nil, fmt.Errorf("agent command is required")
+	}
+	if opts.WallClockBudget < 0 || opts.MaxSteps < 0 || opts.MaxRestarts < 0 {
+		return nil, fmt.Errorf("budgets and restart limit must be >= 0")
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultSupervisorPollInterval
+	}
+	if opts.StopGracePeriod <= 0 {
+		opts.StopGracePeriod = defaultAgentStopGracePeriod
+	}
+	if opts.RestartBackoff <= 0 {
+		opts.RestartBackoff = defaultAgentRestartBackoff
+	}
+	if opts.Stdout == nil {
+		opts.Stdout = os.Stdout
+	}
+	if opts.Stderr == nil {
+		opts.Stderr = os.Stderr
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Supervisor{
+		opts: opts,
+		wake: make(chan struct{}, 1),
+		status: SupervisorStatus{
+			JobID:             opts.JobID,
+			MaxSteps:          opts.MaxSteps,
+			WallClockBudgetMS: opts.WallClockBudget.Milliseconds(),
+		},
+	}, nil
+}
+
+// Run supervises the job until it reaches a terminal state, a budget is
+// exhausted, or ctx is cancelled. A job still running when ctx is cancelled
+// is paused so it can be resumed later. Every state read recovers a pending
+// mutation left behind by a crashed agent or supervisor first.
+func (s *Supervisor) Run(ctx context.Context) (SupervisorStatus, error) {
+	startedAt := s.opts.Now().UTC()
+	s.update(func(status *SupervisorStatus) {
+		status.StartedAt = startedAt
+	})
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+
+	var relaunchAt time.Time
+	for {
+		state, err := Status(s.opts.Root, s.opts.JobID)
+		if err != nil {
+			s.stopAgent(true)
+			return s.Snapshot(), err
+		}
+		s.observe(state)
+		elapsed := s.opts.Now().UTC().Sub(startedAt)
+
+		switch state.Status {
+		case StatusCompleted, StatusCancelled, StatusEmergencyStop:
+			s.stopAgent(state.Status == StatusEmergencyStop)
+			return s.Snapshot(), nil
+		case StatusRunning:
+			if stopReason := s.budgetExceeded(state, elapsed); stopReason != "" {
+				s.stopAgent(false)
+				paused, err := pauseForBudget(s.opts.Root, s.opts.JobID, s.opts.Actor, stopReason)
+				if errors.Is(err, ErrInvalidTransition) {
+					continue
+				}
+				if err != nil {
+					return s.Snapshot(), err
+				}
+				s.observe(paused)
+				reportTransition(s.opts.OnTransition, TransitionPaused, paused, s.opts.Actor, stopReason)
+				return s.Snapshot(), nil
+			}
+			if s.cmd == nil && !s.opts.Now().Before(relaunchAt) {
+				if err := s.launch(); err != nil {
+					return s.Snapshot(), err
+				}
+			}
+		default:
+			s.stopAgent(false)
+			s.failures = 0
+			if s.opts.WallClockBudget > 0 && elapsed >= s.opts.WallClockBudget {
+				return s.Snapshot(), nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			s.stopAgent(false)
+			if state.Status == StatusRunning {
+				paused, err := Pause(s.opts.Root, s.opts.JobID, TransitionOptions{Actor: s.opts.Actor, OnTransition: s.opts.OnTransition})
+				if err != nil && !errors.Is(err, ErrInvalidTransition) {
+					return s.Snapshot(), err
+				}
+				if err == nil {
+					s.observe(paused)
+				}
+			}
+			return s.Snapshot(), nil
+		case waitErr := <-s.exited:
+			restart, err := s.handleExit(waitErr)
+			if err != nil {
+				return s.Snapshot(), err
+			}
+			if restart {
+				relaunchAt = s.opts.Now().Add(s.opts.RestartBackoff)
+			}
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+func (s *Supervisor) Snapshot() SupervisorStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot := s.status
+	if !snapshot.StartedAt.IsZero() {
+		snapshot.ElapsedMS = s.opts.Now().UTC().Sub(snapshot.StartedAt).Milliseconds()
+	}
+	return snapshot
+}
+
+// ControlHandler serves the live control API: GET /v1/job reports the
+// supervisor status and POST /v1/job/{pause,stop,cancel} apply the matching
+// job transition. It carries no authentication, so callers bind it to
+// loopback or a unix socket.
+func (s *Supervisor) ControlHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/job", func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet {
+			writeControlJSON(writer, http.StatusMethodNotAllowed, map[string]any{"ok": false, "error": "expected GET"})
+			return
+		}
+		writeControlJSON(writer, http.StatusOK, map[string]any{"ok": true, "supervisor": s.Snapshot()})
+	})
+	transitions := map[string]func(string, string, TransitionOptions) (JobState, error){
+		"pause":  Pause,
+		"stop":   EmergencyStop,
+		"cancel": Cancel,
+	}
+	for operation, transition := range transitions {
+		mux.HandleFunc("/v1/job/"+operation, func(writer http.ResponseWriter, request *http.Request) {
+			if request.Method != http.MethodPost {
+				writeControlJSON(writer, http.StatusMethodNotAllowed, map[string]any{"ok": false, "error": "expected POST"})
+				return
+			}
+			var body struct {
+				Actor string `json:"actor"`
+			}
+			if request.ContentLength != 0 {
+				if err := json.NewDecoder(io.LimitReader(request.Body, 1<<16)).Decode(&body); err != nil {
+					writeControlJSON(writer, http.StatusBadRequest, map[string]any{"ok": false, "error": "invalid request body"})
+					return
+				}
+			}
+			actor := strings.TrimSpace(body.Actor)
+			if actor == "" {
+				actor = s.opts.Actor
+			}
+			state, err := transition(s.opts.Root, s.opts.JobID, TransitionOptions{Actor: actor, OnStop: s.opts.OnStop, OnTransition: s.opts.OnTransition})
+			if err != nil {
+				status := http.StatusInternalServerError
+				if errors.Is(err, ErrInvalidTransition) {
+					status = http.StatusConflict
+				}
+				writeControlJSON(writer, status, map[string]any{"ok": false, "operation": operation, "error": err.Error()})
+				return
+			}
+			s.observe(state)
+			select {
+			case s.wake <- struct{}{}:
+			default:
+			}
+			writeControlJSON(writer, http.StatusOK, map[string]any{"ok": true, "operation": operation, "job": state})
+		})
+	}
+	return mux
+}
+
+func (s *Supervisor) launch() error {
+	root, err := filepath.Abs(strings.TrimSpace(s.opts.Root))
+	if err != nil {
+		return fmt.Errorf("resolve job root: %w", err)
+	}
+	env := s.opts.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	// #nosec G204 -- the agent command is explicit operator input.
+	cmd := exec.Command(s.opts.Command[0], s.opts.Command[1:]...)
+	cmd.Dir = s.opts.Dir
+	cmd.Env = append(append([]string{}, env...), EnvJobID+"="+s.opts.JobID, EnvJobRoot+"="+root)
+	cmd.Stdout = s.opts.Stdout
+	cmd.Stderr = s.opts.Stderr
+	setAgentProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start agent command: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+	s.cmd = cmd
+	s.exited = exited
+	s.update(func(status *SupervisorStatus) {
+		status.AgentRunning = true
+		status.AgentPID = cmd.Process.Pid
+		status.Launches++
+	})
+	return nil
+}
+
+// stopAgent terminates the agent process group, escalating to a kill after
+// the grace period. force kills immediately.
+func (s *Supervisor) stopAgent(force bool) {
+	if s.cmd == nil {
+		return
+	}
+	_ = signalAgentProcess(s.cmd, force)
+	var waitErr error
+	select {
+	case waitErr = <-s.exited:
+	case <-time.After(s.opts.StopGracePeriod):
+		_ = signalAgentProcess(s.cmd, true)
+		waitErr = <-s.exited
+	}
+	s.recordExit(waitErr)
+}
+
+// handleExit reacts to an agent that exited on its own while the job may
+// still be running: a clean exit completes the job, a failure is restarted
+// until MaxRestarts consecutive failures are spent and then blocks the job.
+func (s *Supervisor) handleExit(waitErr error) (bool, error) {
+	s.recordExit(waitErr)
+	state, err := Status(s.opts.Root, s.opts.JobID)
+	if err != nil {
+		return false, err
+	}
+	if state.Status != StatusRunning {
+		return false, nil
+	}
+	if waitErr == nil {
+		_, _, err := AddCheckpoint(s.opts.Root, s.opts.JobID, CheckpointOptions{
+			Type:    CheckpointTypeCompleted,
+			Summary: "agent command exited successfully",
+			Actor:   s.opts.Actor,
+			OnStop:  s.opts.OnStop,
+
+			requireRunning: true,
+		})
+		return false, ignoreInvalidTransition(err)
+	}
+	if s.failures >= s.opts.MaxRestarts {
+		_, _, err := AddCheckpoint(s.opts.Root, s.opts.JobID, CheckpointOptions{
+			Type:    CheckpointTypeBlocked,
+			Summary: fmt.Sprintf("agent command failed after %d restarts: %v", s.failures, waitErr),
+			Actor:   s.opts.Actor,
+
+			requireRunning: true,
+		})
+		return false, ignoreInvalidTransition(err)
+	}
+	s.failures++
+	s.update(func(status *SupervisorStatus) {
+		status.Restarts++
+	})
+	return true, nil
+}
+
+func (s *Supervisor) recordExit(waitErr error) {
+	exitCode := 0
+	if waitErr != nil {
+		exitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(waitErr, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		}
+	}
+	s.cmd = nil
+	s.exited = nil
+	s.update(func(status *SupervisorStatus) {
+		status.AgentRunning = false
+		status.AgentPID = 0
+		status.LastExitCode = &exitCode
+	})
+}
+
+func (s *Supervisor) budgetExceeded(state JobState, elapsed time.Duration) string {
+	if s.opts.WallClockBudget > 0 && elapsed >= s.opts.WallClockBudget {
+		return StopReasonWallClockBudget
+	}
+	if s.opts.MaxSteps > 0 && len(state.Checkpoints) >= s.opts.MaxSteps {
+		return StopReasonStepBudget
+	}
+	return ""
+}
+
+func (s *Supervisor) observe(state JobState) {
+	s.update(func(status *SupervisorStatus) {
+		status.Status = state.Status
+		status.StopReason = state.StopReason
+		status.Steps = len(state.Checkpoints)
+	})
+}
+
+func (s *Supervisor) update(apply func(*SupervisorStatus)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	apply(&s.status)
+}
+
+func pauseForBudget(root string, jobID string, actor string, stopReason string) (JobState, error) {
+	return simpleTransition(root, jobID, time.Time{}, actor, "paused", []string{StatusRunning}, StatusPaused, stopReason, stopReason)
+}
+
+func ignoreInvalidTransition(err error) error {
+	if errors.Is(err, ErrInvalidTransition) {
+		return nil
+	}
+	return err
+}
+
+func writeControlJSON(writer http.ResponseWriter, status int, payload any) {
+	writer.Header().Set("content-type", "application/json")
+	writer.WriteHeader(status)
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(payload)
+}
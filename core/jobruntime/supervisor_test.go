@@ -0,0 +1,230 @@
+package jobruntime
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+const supervisorHelperEnv = "GAIT_JOB_SUPERVISOR_HELPER"
+
+// TestSupervisorHelperProcess is the agent command launched by the
+// supervisor tests; it does nothing unless the helper env is set.
+func TestSupervisorHelperProcess(t *testing.T) {
+	mode := os.Getenv(supervisorHelperEnv)
+	if mode == "" {
+		return
+	}
+	root, jobID := os.Getenv(EnvJobRoot), os.Getenv(EnvJobID)
+	checkpoint := func(checkpointType string, requiredAction string) {
+		if _, _, err := AddCheckpoint(root, jobID, CheckpointOptions{Type: checkpointType, Summary: "helper " + checkpointType, RequiredAction: requiredAction}); err != nil {
+			os.Exit(2)
+		}
+	}
+	switch mode {
+	case "crash-once":
+		marker := filepath.Join(root, jobID+".crashed")
+		if _, err := os.Stat(marker); os.IsNotExist(err) {
+			_ = os.WriteFile(marker, []byte("1"), 0o600)
+			os.Exit(3)
+		}
+		checkpoint(CheckpointTypeProgress, "")
+		os.Exit(0)
+	case "crash":
+		os.Exit(3)
+	case "decision":
+		checkpoint(CheckpointTypeDecisionNeeded, "approve deploy")
+	case "progress":
+		for {
+			checkpoint(CheckpointTypeProgress, "")
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+	time.Sleep(time.Minute)
+	os.Exit(0)
+}
+
+func TestSupervisorRestartsCrashedAgentAndCompletesOnCleanExit(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "jobs")
+	submitSupervisedJob(t, root, "job-crash-once")
+	supervisor := newHelperSupervisor(t, root, "job-crash-once", "crash-once", SupervisorOptions{MaxRestarts: 2})
+	status, err := supervisor.Run(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if status.Status != StatusCompleted || status.Restarts != 1 || status.Launches != 2 || status.Steps != 2 {
+		t.Fatalf("unexpected supervisor status: %#v", status)
+	}
+
+	submitSupervisedJob(t, root, "job-crash")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	crashing := newHelperSupervisor(t, root, "job-crash", "crash", SupervisorOptions{MaxRestarts: 1})
+	done := runSupervisorInBackground(ctx, crashing)
+	waitForSupervisor(t, crashing, func(snapshot SupervisorStatus) bool {
+		return snapshot.Status == StatusBlocked
+	})
+	cancel()
+	status = <-done
+	if status.Status != StatusBlocked || status.Restarts != 1 || status.LastExitCode == nil || *status.LastExitCode != 3 {
+		t.Fatalf("expected job blocked after restart limit, got %#v", status)
+	}
+}
+
+func TestSupervisorStopsAgentAtDecisionAndHonorsControlAPI(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "jobs")
+	submitSupervisedJob(t, root, "job-decision")
+	transitions := &transitionRecorder{}
+	supervisor := newHelperSupervisor(t, root, "job-decision", "decision", SupervisorOptions{OnTransition: transitions.record})
+	control := httptest.NewServer(supervisor.ControlHandler())
+	defer control.Close()
+
+	done := runSupervisorInBackground(context.Background(), supervisor)
+	waitForSupervisor(t, supervisor, func(snapshot SupervisorStatus) bool {
+		return snapshot.Status == StatusDecisionNeeded && !snapshot.AgentRunning && snapshot.Launches == 1
+	})
+
+	response, err := http.Get(control.URL + "/v1/job")
+	if err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	var payload struct {
+		Supervisor SupervisorStatus `json:"supervisor"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	_ = response.Body.Close()
+	if payload.Supervisor.JobID != "job-decision" || payload.Supervisor.Status != StatusDecisionNeeded {
+		t.Fatalf("unexpected control status: %#v", payload.Supervisor)
+	}
+
+	response, err = http.Post(control.URL+"/v1/job/cancel", "application/json", nil)
+	if err != nil {
+		t.Fatalf("post cancel: %v", err)
+	}
+	_ = response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("expected cancel to succeed, got %d", response.StatusCode)
+	}
+	select {
+	case status := <-done:
+		if status.Status != StatusCancelled {
+			t.Fatalf("expected cancelled job, got %#v", status)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatalf("supervisor did not exit after cancel")
+	}
+	if kinds := transitions.kinds(); len(kinds) != 1 || kinds[0] != TransitionCancelled {
+		t.Fatalf("expected control API cancel to report a transition, got %v", kinds)
+	}
+}
+
+func TestSupervisorPausesJobWhenBudgetIsExhausted(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "jobs")
+	submitSupervisedJob(t, root, "job-steps")
+	transitions := &transitionRecorder{}
+	status, err := newHelperSupervisor(t, root, "job-steps", "progress", SupervisorOptions{MaxSteps: 3, OnTransition: transitions.record}).Run(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if status.Status != StatusPaused || status.StopReason != StopReasonStepBudget || status.AgentRunning {
+		t.Fatalf("expected step budget pause, got %#v", status)
+	}
+	if recorded := transitions.all(); len(recorded) != 1 || recorded[0].Kind != TransitionPaused || recorded[0].Reason != StopReasonStepBudget {
+		t.Fatalf("expected budget pause to report a transition, got %#v", recorded)
+	}
+
+	submitSupervisedJob(t, root, "job-clock")
+	status, err = newHelperSupervisor(t, root, "job-clock", "sleep", SupervisorOptions{WallClockBudget: 300 * time.Millisecond}).Run(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if status.Status != StatusPaused || status.StopReason != StopReasonWallClockBudget {
+		t.Fatalf("expected wall clock budget pause, got %#v", status)
+	}
+	if _, err := Resume(root, "job-clock", ResumeOptions{}); err != nil {
+		t.Fatalf("expected budget pause to be resumable: %v", err)
+	}
+}
+
+type transitionRecorder struct {
+	mu          sync.Mutex
+	transitions []Transition
+}
+
+func (recorder *transitionRecorder) record(transition Transition) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.transitions = append(recorder.transitions, transition)
+}
+
+func (recorder *transitionRecorder) all() []Transition {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	return append([]Transition(nil), recorder.transitions...)
+}
+
+func (recorder *transitionRecorder) kinds() []string {
+	kinds := []string{}
+	for _, transition := range recorder.all() {
+		kinds = append(kinds, transition.Kind)
+	}
+	return kinds
+}
+
+func submitSupervisedJob(t *testing.T, root string, jobID string) {
+	t.Helper()
+	if _, err := Submit(root, SubmitOptions{JobID: jobID}); err != nil {
+		t.Fatalf("submit %s: %v", jobID, err)
+	}
+}
+
+func newHelperSupervisor(t *testing.T, root string, jobID string, mode string, opts SupervisorOptions) *Supervisor {
+	t.Helper()
+	opts.Root = root
+	opts.JobID = jobID
+	opts.Command = []string{os.Args[0], "-test.run=^TestSupervisorHelperProcess$"}
+	opts.Env = append(os.Environ(), supervisorHelperEnv+"="+mode)
+	opts.Stdout = io.Discard
+	opts.Stderr = io.Discard
+	opts.PollInterval = 20 * time.Millisecond
+	opts.RestartBackoff = 10 * time.Millisecond
+	opts.StopGracePeriod = 2 * time.Second
+	supervisor, err := NewSupervisor(opts)
+	if err != nil {
+		t.Fatalf("new supervisor: %v", err)
+	}
+	return supervisor
+}
+
+func runSupervisorInBackground(ctx context.Context, supervisor *Supervisor) <-chan SupervisorStatus {
+	done := make(chan SupervisorStatus, 1)
+	go func() {
+		status, _ := supervisor.Run(ctx)
+		done <- status
+	}()
+	return done
+}
+
+func waitForSupervisor(t *testing.T, supervisor *Supervisor, ready func(SupervisorStatus) bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		snapshot := supervisor.Snapshot()
+		if ready(snapshot) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("supervisor did not reach expected state: %#v", snapshot)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,603 @@
+package mcp
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/fsx"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	jcs "github.com/Clyra-AI/proof/canon"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+const (
+	mcpToolAnnotationSnapshotSchemaID      = "gait.mcp.tool_annotation_snapshot"
+	mcpToolAnnotationSnapshotSchemaVersion = "1.0.0"
+	maxToolsListPages                      = 64
+)
+
+type ToolAnnotationSnapshot struct {
+	SchemaID        string                 `json:"schema_id"`
+	SchemaVersion   string                 `json:"schema_version"`
+	CreatedAt       time.Time              `json:"created_at"`
+	ProducerVersion string                 `json:"producer_version"`
+	Servers         []ToolAnnotationServer `json:"servers"`
+	Signature       *schemagate.Signature  `json:"signature,omitempty"`
+}
+
+type ToolAnnotationServer struct {
+	ServerID        string                `json:"server_id"`
+	ServerName      string                `json:"server_name,omitempty"`
+	ServerVersion   string                `json:"server_version,omitempty"`
+	Endpoint        string                `json:"endpoint,omitempty"`
+	ProtocolVersion string                `json:"protocol_version,omitempty"`
+	CapturedAt      time.Time             `json:"captured_at"`
+	ToolsDigest     string                `json:"tools_digest"`
+	Tools           []ToolAnnotationEntry `json:"tools"`
+}
+
+// ToolAnnotationEntry keeps the raw upstream hints; nil means the server did not
+// declare the hint and the MCP default applies.
+type ToolAnnotationEntry struct {
+	Name              string `json:"name"`
+	Title             string `json:"title,omitempty"`
+	ReadOnlyHint      *bool  `json:"read_only_hint,omitempty"`
+	DestructiveHint   *bool  `json:"destructive_hint,omitempty"`
+	IdempotentHint    *bool  `json:"idempotent_hint,omitempty"`
+	OpenWorldHint     *bool  `json:"open_world_hint,omitempty"`
+	InputSchemaDigest string `json:"input_schema_digest,omitempty"`
+}
+
+type toolsListResult struct {
+	Tools      []ListedTool `json:"tools"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+}
+
+// ListedTool is a tool as advertised by an upstream tools/list response.
+type ListedTool struct {
+	Name        string                 `json:"name"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema json.RawMessage        `json:"inputSchema,omitempty"`
+	Annotations *ListedToolAnnotations `json:"annotations,omitempty"`
+}
+
+type ListedToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
+}
+
+// ListedServer is the identity an upstream reports during initialize together
+// with every tool it lists.
+type ListedServer struct {
+	Name            string
+	Version         string
+	ProtocolVersion string
+	Tools           []ListedTool
+}
+
+type initializeResult struct {
+	ProtocolVersion string `json:"protocolVersion"`
+	ServerInfo      struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"serverInfo"`
+}
+
+// CaptureToolAnnotations runs the MCP initialize handshake against upstream and
+// records every tool returned by (paginated) tools/list.
+func CaptureToolAnnotations(ctx context.Context, upstream Upstream, server ServerInfo, now time.Time) (ToolAnnotationServer, error) {
+	listed, err := ListServerTools(ctx, upstream)
+	if err != nil {
+		return ToolAnnotationServer{}, err
+	}
+	if strings.TrimSpace(server.ServerName) == "" {
+		server.ServerName = listed.Name
+	}
+	return buildToolAnnotationServer(server, listed.Version, listed.ProtocolVersion, listed.Tools, now)
+}
+
+// ListServerTools runs the MCP initialize handshake against upstream and
+// returns every tool from (paginated) tools/list.
+func ListServerTools(ctx context.Context, upstream Upstream) (ListedServer, error) {
+	if upstream == nil {
+		return ListedServer{}, fmt.Errorf("upstream is required")
+	}
+	initParams, err := json.Marshal(map[string]any{
+		"protocolVersion": "2025-06-18",
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "gait", "version": "capture"},
+	})
+	if err != nil {
+		return ListedServer{}, fmt.Errorf("encode initialize params: %w", err)
+	}
+	initReply, err := upstream.Call(ctx, RPCMessage{JSONRPC: jsonRPCVersion, ID: json.RawMessage(`"gait-init"`), Method: "initialize", Params: initParams})
+	if err != nil {
+		return ListedServer{}, fmt.Errorf("initialize upstream: %w", err)
+	}
+	if initReply.Error != nil {
+		return ListedServer{}, fmt.Errorf("initialize upstream: %w", initReply.Error)
+	}
+	var initialized initializeResult
+	if len(initReply.Result) > 0 {
+		if err := json.Unmarshal(initReply.Result, &initialized); err != nil {
+			return ListedServer{}, fmt.Errorf("parse initialize result: %w", err)
+		}
+	}
+	if err := upstream.Notify(ctx, RPCMessage{JSONRPC: jsonRPCVersion, Method: "notifications/initialized"}); err != nil {
+		return ListedServer{}, fmt.Errorf("notify upstream initialized: %w", err)
+	}
+
+	tools := []ListedTool{}
+	cursor := ""
+	for page := 0; ; page++ {
+		if page >= maxToolsListPages {
+			return ListedServer{}, fmt.Errorf("tools/list exceeded %d pages", maxToolsListPages)
+		}
+		var params json.RawMessage
+		if cursor != "" {
+			params, err = json.Marshal(map[string]string{"cursor": cursor})
+			if err != nil {
+				return ListedServer{}, fmt.Errorf("encode tools/list params: %w", err)
+			}
+		}
+		reply, err := upstream.Call(ctx, RPCMessage{
+			JSONRPC: jsonRPCVersion,
+			ID:      json.RawMessage(fmt.Sprintf(`"gait-tools-%d"`, page)),
+			Method:  "tools/list",
+			Params:  params,
+		})
+		if err != nil {
+			return ListedServer{}, fmt.Errorf("list upstream tools: %w", err)
+		}
+		if reply.Error != nil {
+			return ListedServer{}, fmt.Errorf("list upstream tools: %w", reply.Error)
+		}
+		var result toolsListResult
+		if err := json.Unmarshal(reply.Result, &result); err != nil {
+			return ListedServer{}, fmt.Errorf("parse tools/list result: %w", err)
+		}
+		tools = append(tools, result.Tools...)
+		cursor = strings.TrimSpace(result.NextCursor)
+		if cursor == "" {
+			break
+		}
+	}
+
+	return ListedServer{
+		Name:            strings.TrimSpace(initialized.ServerInfo.Name),
+		Version:         strings.TrimSpace(initialized.ServerInfo.Version),
+		ProtocolVersion: strings.TrimSpace(initialized.ProtocolVersion),
+		Tools:           tools,
+	}, nil
+}
+
+func buildToolAnnotationServer(server ServerInfo, serverVersion string, protocolVersion string, tools []ListedTool, now time.Time) (ToolAnnotationServer, error) {
+	output := ToolAnnotationServer{
+		ServerID:        strings.TrimSpace(server.ServerID),
+		ServerName:      strings.TrimSpace(server.ServerName),
+		ServerVersion:   strings.TrimSpace(serverVersion),
+		Endpoint:        strings.TrimSpace(server.Endpoint),
+		ProtocolVersion: strings.TrimSpace(protocolVersion),
+		CapturedAt:      now.UTC(),
+		Tools:           make([]ToolAnnotationEntry, 0, len(tools)),
+	}
+	if normalizedServerKey(output.ServerID, output.ServerName) == "" {
+		return ToolAnnotationServer{}, fmt.Errorf("captured server requires server_id or server_name")
+	}
+	for index, tool := range tools {
+		entry := ToolAnnotationEntry{
+			Name:  strings.TrimSpace(tool.Name),
+			Title: strings.TrimSpace(tool.Title),
+		}
+		if entry.Name == "" {
+			return ToolAnnotationServer{}, fmt.Errorf("tools/list tools[%d] has no name", index)
+		}
+		if tool.Annotations != nil {
+			if entry.Title == "" {
+				entry.Title = strings.TrimSpace(tool.Annotations.Title)
+			}
+			entry.ReadOnlyHint = tool.Annotations.ReadOnlyHint
+			entry.DestructiveHint = tool.Annotations.DestructiveHint
+			entry.IdempotentHint = tool.Annotations.IdempotentHint
+			entry.OpenWorldHint = tool.Annotations.OpenWorldHint
+		}
+		if len(tool.InputSchema) > 0 {
+			digest, err := jcs.DigestJCS(tool.InputSchema)
+			if err != nil {
+				return ToolAnnotationServer{}, fmt.Errorf("digest input schema for tool %q: %w", entry.Name, err)
+			}
+			entry.InputSchemaDigest = digest
+		}
+		output.Tools = append(output.Tools, entry)
+	}
+	normalized, err := normalizeToolAnnotationServer(output)
+	if err != nil {
+		return ToolAnnotationServer{}, err
+	}
+	return normalized, nil
+}
+
+// UpsertToolAnnotationServer replaces the entry for the same server identity or
+// appends a new one.
+func UpsertToolAnnotationServer(snapshot ToolAnnotationSnapshot, server ToolAnnotationServer) ToolAnnotationSnapshot {
+	key := normalizedServerKey(server.ServerID, server.ServerName)
+	servers := make([]ToolAnnotationServer, 0, len(snapshot.Servers)+1)
+	for _, existing := range snapshot.Servers {
+		if normalizedServerKey(existing.ServerID, existing.ServerName) == key {
+			continue
+		}
+		servers = append(servers, existing)
+	}
+	snapshot.Servers = append(servers, server)
+	snapshot.Signature = nil
+	return snapshot
+}
+
+func NormalizeToolAnnotationSnapshot(input ToolAnnotationSnapshot) (ToolAnnotationSnapshot, error) {
+	output := input
+	if strings.TrimSpace(output.SchemaID) == "" {
+		output.SchemaID = mcpToolAnnotationSnapshotSchemaID
+	}
+	if output.SchemaID != mcpToolAnnotationSnapshotSchemaID {
+		return ToolAnnotationSnapshot{}, fmt.Errorf("unsupported tool annotation snapshot schema_id %q", output.SchemaID)
+	}
+	if strings.TrimSpace(output.SchemaVersion) == "" {
+		output.SchemaVersion = mcpToolAnnotationSnapshotSchemaVersion
+	}
+	if output.SchemaVersion != mcpToolAnnotationSnapshotSchemaVersion {
+		return ToolAnnotationSnapshot{}, fmt.Errorf("unsupported tool annotation snapshot schema_version %q", output.SchemaVersion)
+	}
+	output.CreatedAt = output.CreatedAt.UTC()
+	output.ProducerVersion = strings.TrimSpace(output.ProducerVersion)
+	servers := make([]ToolAnnotationServer, 0, len(output.Servers))
+	seen := make(map[string]int, len(output.Servers))
+	for index, server := range output.Servers {
+		normalized, err := normalizeToolAnnotationServer(server)
+		if err != nil {
+			return ToolAnnotationSnapshot{}, fmt.Errorf("tool annotation snapshot servers[%d]: %w", index, err)
+		}
+		key := normalizedServerKey(normalized.ServerID, normalized.ServerName)
+		if previous, ok := seen[key]; ok {
+			return ToolAnnotationSnapshot{}, fmt.Errorf("tool annotation snapshot contains duplicate server identity %q at servers[%d] and servers[%d]", key, previous, index)
+		}
+		seen[key] = index
+		servers = append(servers, normalized)
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		return normalizedServerKey(servers[i].ServerID, servers[i].ServerName) <
+			normalizedServerKey(servers[j].ServerID, servers[j].ServerName)
+	})
+	output.Servers = servers
+	return output, nil
+}
+
+func normalizeToolAnnotationServer(input ToolAnnotationServer) (ToolAnnotationServer, error) {
+	output := input
+	output.ServerID = strings.TrimSpace(output.ServerID)
+	output.ServerName = strings.TrimSpace(output.ServerName)
+	output.ServerVersion = strings.TrimSpace(output.ServerVersion)
+	output.Endpoint = strings.TrimSpace(output.Endpoint)
+	output.ProtocolVersion = strings.TrimSpace(output.ProtocolVersion)
+	output.CapturedAt = output.CapturedAt.UTC()
+	if normalizedServerKey(output.ServerID, output.ServerName) == "" {
+		return ToolAnnotationServer{}, fmt.Errorf("server_id or server_name is required")
+	}
+	tools := make([]ToolAnnotationEntry, 0, len(output.Tools))
+	seen := make(map[string]struct{}, len(output.Tools))
+	for index, tool := range output.Tools {
+		tool.Name = strings.TrimSpace(tool.Name)
+		tool.Title = strings.TrimSpace(tool.Title)
+		tool.InputSchemaDigest = strings.ToLower(strings.TrimSpace(tool.InputSchemaDigest))
+		if tool.Name == "" {
+			return ToolAnnotationServer{}, fmt.Errorf("tools[%d].name is required", index)
+		}
+		if _, ok := seen[tool.Name]; ok {
+			return ToolAnnotationServer{}, fmt.Errorf("duplicate tool name %q", tool.Name)
+		}
+		seen[tool.Name] = struct{}{}
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	output.Tools = tools
+	raw, err := json.Marshal(tools)
+	if err != nil {
+		return ToolAnnotationServer{}, fmt.Errorf("marshal tools: %w", err)
+	}
+	digest, err := jcs.DigestJCS(raw)
+	if err != nil {
+		return ToolAnnotationServer{}, fmt.Errorf("digest tools: %w", err)
+	}
+	if output.ToolsDigest != "" && !strings.EqualFold(strings.TrimSpace(output.ToolsDigest), digest) {
+		return ToolAnnotationServer{}, fmt.Errorf("tools_digest does not match tools")
+	}
+	output.ToolsDigest = digest
+	return output, nil
+}
+
+func SignToolAnnotationSnapshot(input ToolAnnotationSnapshot, privateKey ed25519.PrivateKey) (ToolAnnotationSnapshot, error) {
+	if len(privateKey) == 0 {
+		return ToolAnnotationSnapshot{}, fmt.Errorf("signing private key is required")
+	}
+	normalized, err := NormalizeToolAnnotationSnapshot(input)
+	if err != nil {
+		return ToolAnnotationSnapshot{}, err
+	}
+	signable := normalized
+	signable.Signature = nil
+	raw, err := json.Marshal(signable)
+	if err != nil {
+		return ToolAnnotationSnapshot{}, fmt.Errorf("marshal tool annotation snapshot: %w", err)
+	}
+	signature, err := sign.SignTraceRecordJSON(privateKey, raw)
+	if err != nil {
+		return ToolAnnotationSnapshot{}, fmt.Errorf("sign tool annotation snapshot: %w", err)
+	}
+	normalized.Signature = &schemagate.Signature{
+		Alg:          signature.Alg,
+		KeyID:        signature.KeyID,
+		Sig:          signature.Sig,
+		SignedDigest: signature.SignedDigest,
+	}
+	return normalized, nil
+}
+
+func VerifyToolAnnotationSnapshot(input ToolAnnotationSnapshot, publicKey ed25519.PublicKey) error {
+	normalized, err := NormalizeToolAnnotationSnapshot(input)
+	if err != nil {
+		return err
+	}
+	if normalized.Signature == nil {
+		return fmt.Errorf("tool annotation snapshot signature is required")
+	}
+	if len(publicKey) == 0 {
+		return fmt.Errorf("verify key is required")
+	}
+	signable := normalized
+	signable.Signature = nil
+	raw, err := json.Marshal(signable)
+	if err != nil {
+		return fmt.Errorf("marshal signable tool annotation snapshot: %w", err)
+	}
+	ok, err := sign.VerifyTraceRecordJSON(publicKey, sign.Signature{
+		Alg:          normalized.Signature.Alg,
+		KeyID:        normalized.Signature.KeyID,
+		Sig:          normalized.Signature.Sig,
+		SignedDigest: normalized.Signature.SignedDigest,
+	}, raw)
+	if err != nil {
+		return fmt.Errorf("verify tool annotation snapshot signature: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("tool annotation snapshot signature did not verify")
+	}
+	return nil
+}
+
+func LoadToolAnnotationSnapshot(path string) (ToolAnnotationSnapshot, error) {
+	trimmedPath := strings.TrimSpace(path)
+	if trimmedPath == "" {
+		return ToolAnnotationSnapshot{}, fmt.Errorf("tool annotation snapshot path is required")
+	}
+	// #nosec G304 -- tool annotation snapshot path is explicit local user input.
+	raw, err := os.ReadFile(trimmedPath)
+	if err != nil {
+		return ToolAnnotationSnapshot{}, fmt.Errorf("read tool annotation snapshot: %w", err)
+	}
+	var snapshot ToolAnnotationSnapshot
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return ToolAnnotationSnapshot{}, fmt.Errorf("parse tool annotation snapshot: %w", err)
+	}
+	return NormalizeToolAnnotationSnapshot(snapshot)
+}
+
+func WriteToolAnnotationSnapshot(path string, snapshot ToolAnnotationSnapshot) error {
+	trimmedPath := strings.TrimSpace(path)
+	if trimmedPath == "" {
+		return fmt.Errorf("tool annotation snapshot path is required")
+	}
+	encoded, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode tool annotation snapshot: %w", err)
+	}
+	if dir := filepath.Dir(trimmedPath); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return fmt.Errorf("create tool annotation snapshot directory: %w", err)
+		}
+	}
+	if err := fsx.WriteFileAtomic(trimmedPath, append(encoded, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write tool annotation snapshot: %w", err)
+	}
+	return nil
+}
+
+// Lookup resolves a tool by server identity. Without a server identity the tool
+// name must be unique across the snapshot.
+func (snapshot ToolAnnotationSnapshot) Lookup(server *ServerInfo, toolName string) (ToolAnnotationEntry, bool) {
+	name := strings.TrimSpace(toolName)
+	if name == "" {
+		return ToolAnnotationEntry{}, false
+	}
+	serverKey := ""
+	if server != nil {
+		serverKey = normalizedServerKey(server.ServerID, server.ServerName)
+	}
+	var found ToolAnnotationEntry
+	matches := 0
+	for _, entry := range snapshot.Servers {
+		if serverKey != "" && !toolAnnotationServerMatches(entry, server) {
+			continue
+		}
+		for _, tool := range entry.Tools {
+			if tool.Name == name {
+				found = tool
+				matches++
+			}
+		}
+	}
+	if matches != 1 {
+		return ToolAnnotationEntry{}, false
+	}
+	return found, true
+}
+
+func toolAnnotationServerMatches(entry ToolAnnotationServer, server *ServerInfo) bool {
+	if normalizedServerKey(entry.ServerID, entry.ServerName) == normalizedServerKey(server.ServerID, server.ServerName) {
+		return true
+	}
+	name := strings.TrimSpace(server.ServerName)
+	return name != "" && strings.EqualFold(strings.TrimSpace(entry.ServerName), name)
+}
+
+// resolvedHints applies the MCP specification defaults for undeclared hints.
+func (entry ToolAnnotationEntry) resolvedHints() targetHints {
+	readOnly := boolValue(entry.ReadOnlyHint, false)
+	return targetHints{
+		ReadOnlyHint:    readOnly,
+		DestructiveHint: !readOnly && boolValue(entry.DestructiveHint, true),
+		IdempotentHint:  !readOnly && boolValue(entry.IdempotentHint, false),
+		OpenWorldHint:   boolValue(entry.OpenWorldHint, true),
+	}
+}
+
+func boolValue(value *bool, fallback bool) bool {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
+
+// applyToolAnnotations replaces caller-supplied target hints with the snapshot
+// hints and reports the reason codes describing what was overridden.
+func applyToolAnnotations(call ToolCall, snapshot *ToolAnnotationSnapshot) (ToolCall, []string) {
+	if snapshot == nil || (call.Script != nil && len(call.Script.Steps) > 0) {
+		return call, nil
+	}
+	targets := append([]Target(nil), call.Targets...)
+	if len(targets) == 0 && strings.TrimSpace(call.Target) != "" {
+		targets = []Target{inferLegacyTarget(call.Target)}
+	}
+	entry, ok := snapshot.Lookup(call.Server, call.Name)
+	if !ok {
+		claimed := false
+		for index := range targets {
+			if len(claimedTargetHints(targets[index])) > 0 {
+				claimed = true
+			}
+			targets[index] = stripTargetHints(targets[index])
+		}
+		call.Targets = targets
+		call.Target = ""
+		if claimed {
+			return call, []string{"mcp_tool_annotation_unknown", "mcp_tool_annotation_untrusted_claim"}
+		}
+		return call, []string{"mcp_tool_annotation_unknown"}
+	}
+	hints := entry.resolvedHints()
+	if len(targets) == 0 {
+		targets = []Target{{
+			Kind:            "other",
+			Value:           "mcp_tool:" + strings.TrimSpace(call.Name),
+			DiscoveryMethod: "mcp",
+		}}
+	}
+	mismatch := false
+	for index := range targets {
+		for hint, claimedValue := range claimedTargetHints(targets[index]) {
+			if claimedValue != hints.value(hint) {
+				mismatch = true
+			}
+		}
+		target := stripTargetHints(targets[index])
+		target.ReadOnlyHint = hints.ReadOnlyHint
+		target.DestructiveHint = hints.DestructiveHint
+		target.IdempotentHint = hints.IdempotentHint
+		target.OpenWorldHint = hints.OpenWorldHint
+		targets[index] = target
+	}
+	call.Targets = targets
+	call.Target = ""
+	if mismatch {
+		return call, []string{"mcp_tool_annotation_mismatch"}
+	}
+	return call, nil
+}
+
+func (hints targetHints) value(name string) bool {
+	switch name {
+	case "read_only_hint":
+		return hints.ReadOnlyHint
+	case "destructive_hint":
+		return hints.DestructiveHint
+	case "idempotent_hint":
+		return hints.IdempotentHint
+	default:
+		return hints.OpenWorldHint
+	}
+}
+
+// claimedTargetHints returns the hints the caller asserted; a false struct field
+// is indistinguishable from an omitted one, so only explicit annotations count
+// as a false claim.
+func claimedTargetHints(target Target) map[string]bool {
+	claims := map[string]bool{}
+	if target.ReadOnlyHint || target.ReadOnlyHintAlias {
+		claims["read_only_hint"] = true
+	}
+	if target.DestructiveHint || target.DestructiveHintAlias {
+		claims["destructive_hint"] = true
+	}
+	if target.IdempotentHint || target.IdempotentHintAlias {
+		claims["idempotent_hint"] = true
+	}
+	if target.OpenWorldHint || target.OpenWorldHintAlias {
+		claims["open_world_hint"] = true
+	}
+	if value, ok := annotationBool(target.Annotations, "read_only_hint", "readOnlyHint"); ok {
+		claims["read_only_hint"] = value
+	}
+	if value, ok := annotationBool(target.Annotations, "destructive_hint", "destructiveHint"); ok {
+		claims["destructive_hint"] = value
+	}
+	if value, ok := annotationBool(target.Annotations, "idempotent_hint", "idempotentHint"); ok {
+		claims["idempotent_hint"] = value
+	}
+	if value, ok := annotationBool(target.Annotations, "open_world_hint", "openWorldHint"); ok {
+		claims["open_world_hint"] = value
+	}
+	return claims
+}
+
+func stripTargetHints(target Target) Target {
+	target.ReadOnlyHint = false
+	target.ReadOnlyHintAlias = false
+	target.DestructiveHint = false
+	target.DestructiveHintAlias = false
+	target.IdempotentHint = false
+	target.IdempotentHintAlias = false
+	target.OpenWorldHint = false
+	target.OpenWorldHintAlias = false
+	if len(target.Annotations) > 0 {
+		annotations := make(map[string]any, len(target.Annotations))
+		for key, value := range target.Annotations {
+			switch key {
+			case "read_only_hint", "readOnlyHint", "destructive_hint", "destructiveHint",
+				"idempotent_hint", "idempotentHint", "open_world_hint", "openWorldHint":
+				continue
+			}
+			annotations[key] = value
+		}
+		target.Annotations = annotations
+	}
+	return target
+}
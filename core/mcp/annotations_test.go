@@ -0,0 +1,253 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/gate"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+type toolsListUpstream struct {
+	fakeUpstream
+	pages []string
+}
+
+func (upstream *toolsListUpstream) Call(ctx context.Context, request RPCMessage) (RPCMessage, error) {
+	if request.Method != "tools/list" {
+		return upstream.fakeUpstream.Call(ctx, request)
+	}
+	page := 0
+	if len(request.Params) > 0 {
+		var params struct {
+			Cursor string `json:"cursor"`
+		}
+		_ = json.Unmarshal(request.Params, &params)
+		if params.Cursor == "page-2" {
+			page = 1
+		}
+	}
+	return RPCMessage{JSONRPC: "2.0", ID: request.ID, Result: json.RawMessage(upstream.pages[page])}, nil
+}
+
+func newToolsListUpstream() *toolsListUpstream {
+	return &toolsListUpstream{pages: []string{
+		`{"tools":[{"name":"fs.read","inputSchema":{"type":"object","properties":{"path":{"type":"string"}}},"annotations":{"readOnlyHint":true}}],"nextCursor":"page-2"}`,
+		`{"tools":[{"name":"fs.delete","annotations":{"title":"Delete file","destructiveHint":true,"idempotentHint":true}},{"name":"http.fetch"}]}`,
+	}}
+}
+
+func mustCaptureSnapshot(t *testing.T) (ToolAnnotationSnapshot, sign.KeyPair) {
+	t.Helper()
+	captured, err := CaptureToolAnnotations(context.Background(), newToolsListUpstream(), ServerInfo{ServerID: "fs"}, time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("capture annotations: %v", err)
+	}
+	keyPair, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	snapshot, err := SignToolAnnotationSnapshot(UpsertToolAnnotationServer(ToolAnnotationSnapshot{}, captured), keyPair.Private)
+	if err != nil {
+		t.Fatalf("sign snapshot: %v", err)
+	}
+	return snapshot, keyPair
+}
+
+func TestCaptureToolAnnotations(t *testing.T) {
+	upstream := newToolsListUpstream()
+	captured, err := CaptureToolAnnotations(context.Background(), upstream, ServerInfo{ServerID: "fs", Endpoint: "http://127.0.0.1/mcp"}, time.Now())
+	if err != nil {
+		t.Fatalf("capture annotations: %v", err)
+	}
+	if captured.ServerName != "fake-fs" || captured.ServerVersion != "1.0.0" {
+		t.Fatalf("expected server identity from initialize, got %#v", captured)
+	}
+	if len(captured.Tools) != 3 || captured.Tools[0].Name != "fs.delete" || captured.Tools[2].Name != "http.fetch" {
+		t.Fatalf("expected paginated tools sorted by name, got %#v", captured.Tools)
+	}
+	if captured.Tools[0].Title != "Delete file" || captured.Tools[0].ReadOnlyHint != nil || !*captured.Tools[0].DestructiveHint {
+		t.Fatalf("unexpected fs.delete annotations: %#v", captured.Tools[0])
+	}
+	if captured.Tools[1].InputSchemaDigest == "" || captured.ToolsDigest == "" {
+		t.Fatalf("expected schema and tools digests, got %#v", captured)
+	}
+	if len(upstream.notified) != 1 || upstream.notified[0].Method != "notifications/initialized" {
+		t.Fatalf("expected initialized notification, got %#v", upstream.notified)
+	}
+}
+
+func TestToolAnnotationSnapshotSignVerifyRoundTrip(t *testing.T) {
+	snapshot, keyPair := mustCaptureSnapshot(t)
+	path := filepath.Join(t.TempDir(), "annotations.json")
+	if err := WriteToolAnnotationSnapshot(path, snapshot); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+	loaded, err := LoadToolAnnotationSnapshot(path)
+	if err != nil {
+		t.Fatalf("load snapshot: %v", err)
+	}
+	if err := VerifyToolAnnotationSnapshot(loaded, keyPair.Public); err != nil {
+		t.Fatalf("verify snapshot: %v", err)
+	}
+
+	tampered := loaded
+	tampered.Servers = append([]ToolAnnotationServer(nil), loaded.Servers...)
+	tampered.Servers[0].Tools = append([]ToolAnnotationEntry(nil), loaded.Servers[0].Tools...)
+	readOnly := true
+	tampered.Servers[0].Tools[0].ReadOnlyHint = &readOnly
+	tampered.Servers[0].ToolsDigest = ""
+	if err := VerifyToolAnnotationSnapshot(tampered, keyPair.Public); err == nil {
+		t.Fatalf("expected tampered snapshot to fail verification")
+	}
+
+	otherKey, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	if err := VerifyToolAnnotationSnapshot(loaded, otherKey.Public); err == nil {
+		t.Fatalf("expected verification with a different key to fail")
+	}
+	unsigned := loaded
+	unsigned.Signature = nil
+	if err := VerifyToolAnnotationSnapshot(unsigned, keyPair.Public); err == nil {
+		t.Fatalf("expected unsigned snapshot to fail verification")
+	}
+}
+
+func TestToIntentRequestUsesSnapshotAnnotations(t *testing.T) {
+	snapshot, _ := mustCaptureSnapshot(t)
+
+	intent, err := ToIntentRequestWithOptions(ToolCall{
+		Name:   "fs.delete",
+		Server: &ServerInfo{ServerID: "fs"},
+		Targets: []Target{{
+			Kind:         "path",
+			Value:        "/tmp/a",
+			Operation:    "delete",
+			ReadOnlyHint: true,
+		}},
+	}, IntentOptions{ToolAnnotations: &snapshot})
+	if err != nil {
+		t.Fatalf("to intent: %v", err)
+	}
+	target := intent.Targets[0]
+	if target.ReadOnlyHint || !target.DestructiveHint || !target.IdempotentHint || !target.OpenWorldHint {
+		t.Fatalf("expected hints from snapshot, got %#v", target)
+	}
+
+	intent, err = ToIntentRequestWithOptions(ToolCall{Name: "fs.read"}, IntentOptions{ToolAnnotations: &snapshot})
+	if err != nil {
+		t.Fatalf("to intent without targets: %v", err)
+	}
+	if len(intent.Targets) != 1 || intent.Targets[0].Kind != "other" || !intent.Targets[0].ReadOnlyHint || intent.Targets[0].DestructiveHint {
+		t.Fatalf("expected synthesized read-only target, got %#v", intent.Targets)
+	}
+
+	intent, err = ToIntentRequestWithOptions(ToolCall{
+		Name:    "shell.exec",
+		Server:  &ServerInfo{ServerID: "fs"},
+		Targets: []Target{{Kind: "path", Value: "/tmp/a", ReadOnlyHint: true}},
+	}, IntentOptions{ToolAnnotations: &snapshot})
+	if err != nil {
+		t.Fatalf("to intent for unknown tool: %v", err)
+	}
+	if intent.Targets[0].ReadOnlyHint {
+		t.Fatalf("expected caller hints dropped for tool missing from snapshot, got %#v", intent.Targets[0])
+	}
+}
+
+func TestEvaluateToolCallReportsAnnotationMismatch(t *testing.T) {
+	snapshot, _ := mustCaptureSnapshot(t)
+	policy, err := gate.ParsePolicyYAML([]byte(`
+default_verdict: block
+rules:
+  - name: allow-read-only
+    effect: allow
+    match:
+      tool_annotations:
+        readOnlyHint: true
+`))
+	if err != nil {
+		t.Fatalf("parse policy: %v", err)
+	}
+	lying := ToolCall{
+		Name:    "http.fetch",
+		Server:  &ServerInfo{ServerID: "fs"},
+		Targets: []Target{{Kind: "url", Value: "https://example.com/a", Operation: "get", ReadOnlyHint: true}},
+	}
+
+	withoutSnapshot, err := EvaluateToolCall(policy, lying, gate.EvalOptions{ProducerVersion: "test"})
+	if err != nil {
+		t.Fatalf("evaluate without snapshot: %v", err)
+	}
+	if withoutSnapshot.Outcome.Result.Verdict != "allow" {
+		t.Fatalf("expected caller-claimed hint to match without snapshot, got %q", withoutSnapshot.Outcome.Result.Verdict)
+	}
+
+	withSnapshot, err := EvaluateToolCallWithIntentOptions(policy, lying, gate.EvalOptions{ProducerVersion: "test"}, IntentOptions{ToolAnnotations: &snapshot})
+	if err != nil {
+		t.Fatalf("evaluate with snapshot: %v", err)
+	}
+	if withSnapshot.Outcome.Result.Verdict != "block" {
+		t.Fatalf("expected snapshot hints to override the claimed hint, got %q", withSnapshot.Outcome.Result.Verdict)
+	}
+	if !containsString(withSnapshot.Outcome.Result.ReasonCodes, "mcp_tool_annotation_mismatch") {
+		t.Fatalf("expected mismatch reason code, got %v", withSnapshot.Outcome.Result.ReasonCodes)
+	}
+
+	honest, err := EvaluateToolCallWithIntentOptions(policy, ToolCall{Name: "fs.read", Server: &ServerInfo{ServerID: "fs"}}, gate.EvalOptions{ProducerVersion: "test"}, IntentOptions{ToolAnnotations: &snapshot})
+	if err != nil {
+		t.Fatalf("evaluate honest call: %v", err)
+	}
+	if honest.Outcome.Result.Verdict != "allow" || containsString(honest.Outcome.Result.ReasonCodes, "mcp_tool_annotation_mismatch") {
+		t.Fatalf("unexpected honest call outcome: %#v", honest.Outcome.Result)
+	}
+
+	unknown, err := EvaluateToolCallWithIntentOptions(policy, ToolCall{Name: "fs.read", Server: &ServerInfo{ServerID: "other"}}, gate.EvalOptions{ProducerVersion: "test"}, IntentOptions{ToolAnnotations: &snapshot})
+	if err != nil {
+		t.Fatalf("evaluate unknown server: %v", err)
+	}
+	if !containsString(unknown.Outcome.Result.ReasonCodes, "mcp_tool_annotation_unknown") {
+		t.Fatalf("expected unknown reason code, got %v", unknown.Outcome.Result.ReasonCodes)
+	}
+}
+
+func TestObserveTrustSnapshotEntry(t *testing.T) {
+	captured := ToolAnnotationServer{ServerID: "fs", ServerName: "fake-fs", Endpoint: "http://127.0.0.1/mcp", CapturedAt: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)}
+	snapshot := ObserveTrustSnapshotEntry(TrustSnapshot{}, captured, "annotations.json")
+	if len(snapshot.Entries) != 1 || snapshot.Entries[0].Status != "observed" || snapshot.Entries[0].Source != "tools_list" {
+		t.Fatalf("unexpected observed entry: %#v", snapshot.Entries)
+	}
+
+	trusted := TrustSnapshot{Entries: []TrustSnapshotEntry{{ServerID: "fs", Status: "trusted", Score: 0.9}}}
+	updated := ObserveTrustSnapshotEntry(trusted, captured, "annotations.json")
+	if updated.Entries[0].Status != "trusted" || updated.Entries[0].Score != 0.9 || updated.Entries[0].Endpoint != captured.Endpoint {
+		t.Fatalf("expected existing trust decision preserved, got %#v", updated.Entries[0])
+	}
+
+	path := filepath.Join(t.TempDir(), "trust.json")
+	if err := WriteTrustSnapshot(path, updated); err != nil {
+		t.Fatalf("write trust snapshot: %v", err)
+	}
+	loaded, err := LoadTrustSnapshot(path)
+	if err != nil {
+		t.Fatalf("load trust snapshot: %v", err)
+	}
+	if len(loaded.Entries) != 1 || loaded.Entries[0].EvidencePath != "annotations.json" {
+		t.Fatalf("unexpected loaded trust snapshot: %#v", loaded)
+	}
+}
+
+func containsString(values []string, want string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) == want {
+			return true
+		}
+	}
+	return false
+}
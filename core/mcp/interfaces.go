@@ -65,6 +65,7 @@ type ArgProvenance struct {
 type CallContext struct {
 	Identity               string         `json:"identity,omitempty"`
 	Workspace              string         `json:"workspace,omitempty"`
+	AgentID                string         `json:"agent_id,omitempty"`
 	RiskClass              string         `json:"risk_class,omitempty"`
 	Phase                  string         `json:"phase,omitempty"`
 	JobID                  string         `json:"job_id,omitempty"`
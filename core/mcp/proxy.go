@@ -26,6 +26,9 @@ type EvalResult struct {
 
 type IntentOptions struct {
 	RequireExplicitContext bool
+	// ToolAnnotations is a verified snapshot; when set, target hints come from
+	// the snapshot instead of the caller payload.
+	ToolAnnotations *ToolAnnotationSnapshot
 }
 
 func EvaluateToolCall(policy gate.Policy, call ToolCall, opts gate.EvalOptions) (EvalResult, error) {
@@ -49,6 +52,9 @@ func EvaluateToolCallWithIntentOptions(policy gate.Policy, call ToolCall, opts g
 		}
 	}
 	outcome = ApplyTrustPolicy(policy.MCPTrust, call, outcome, time.Now().UTC())
+	if _, annotationReasons := applyToolAnnotations(call, intentOpts.ToolAnnotations); len(annotationReasons) > 0 {
+		outcome.Result.ReasonCodes = mergeUniqueSorted(outcome.Result.ReasonCodes, annotationReasons)
+	}
 	return EvalResult{
 		Call:    call,
 		Intent:  normalizedIntent,
@@ -62,6 +68,7 @@ func ToIntentRequest(call ToolCall) (schemagate.IntentRequest, error) {
 }
 
 func ToIntentRequestWithOptions(call ToolCall, opts IntentOptions) (schemagate.IntentRequest, error) {
+	call, _ = applyToolAnnotations(call, opts.ToolAnnotations)
 	name := strings.TrimSpace(call.Name)
 	hasScript := call.Script != nil && len(call.Script.Steps) > 0
 	if name == "" && !hasScript {
@@ -221,6 +228,7 @@ func ToIntentRequestWithOptions(call ToolCall, opts IntentOptions) (schemagate.I
 			Workspace:              workspace,
 			RiskClass:              riskClass,
 			Phase:                  strings.TrimSpace(call.Context.Phase),
+			AgentID:                strings.TrimSpace(call.Context.AgentID),
 			JobID:                  strings.TrimSpace(call.Context.JobID),
 			SessionID:              strings.TrimSpace(call.Context.SessionID),
 			RequestID:              strings.TrimSpace(call.Context.RequestID),
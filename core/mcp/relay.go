@@ -0,0 +1,267 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/gate"
+)
+
+type CallDecision struct {
+	Verdict           string   `json:"verdict"`
+	ReasonCodes       []string `json:"reason_codes,omitempty"`
+	Violations        []string `json:"violations,omitempty"`
+	TraceID           string   `json:"trace_id,omitempty"`
+	PolicyDigest      string   `json:"policy_digest,omitempty"`
+	IntentDigest      string   `json:"intent_digest,omitempty"`
+	ApprovalRequestID string   `json:"approval_request_id,omitempty"`
+	ApprovalStatus    string   `json:"approval_request_status,omitempty"`
+}
+
+// CallEvaluator decides whether a relayed tools/call may reach the upstream server.
+type CallEvaluator func(context.Context, ToolCall) (CallDecision, error)
+
+type RelayOptions struct {
+	Upstream Upstream
+	Evaluate CallEvaluator
+	Server   *ServerInfo
+	Context  CallContext
+	Now      func() time.Time
+}
+
+type Relay struct {
+	upstream Upstream
+	evaluate CallEvaluator
+	context  CallContext
+	now      func() time.Time
+
+	mu     sync.Mutex
+	server *ServerInfo
+}
+
+type relayToolCallParams struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+}
+
+func NewRelay(opts RelayOptions) (*Relay, error) {
+	if opts.Upstream == nil {
+		return nil, fmt.Errorf("relay upstream is required")
+	}
+	if opts.Evaluate == nil {
+		return nil, fmt.Errorf("relay evaluator is required")
+	}
+	now := opts.Now
+	if now == nil {
+		now = time.Now
+	}
+	var server *ServerInfo
+	if opts.Server != nil {
+		copied := *opts.Server
+		server = &copied
+	}
+	return &Relay{
+		upstream: opts.Upstream,
+		evaluate: opts.Evaluate,
+		context:  opts.Context,
+		now:      now,
+		server:   server,
+	}, nil
+}
+
+func PolicyCallEvaluator(policy gate.Policy, opts gate.EvalOptions, intentOpts IntentOptions) CallEvaluator {
+	return func(_ context.Context, call ToolCall) (CallDecision, error) {
+		result, err := EvaluateToolCallWithIntentOptions(policy, call, opts, intentOpts)
+		if err != nil {
+			return CallDecision{}, err
+		}
+		policyDigest, err := gate.PolicyDigest(policy)
+		if err != nil {
+			return CallDecision{}, err
+		}
+		return CallDecision{
+			Verdict:      result.Outcome.Result.Verdict,
+			ReasonCodes:  result.Outcome.Result.ReasonCodes,
+			Violations:   result.Outcome.Result.Violations,
+			PolicyDigest: policyDigest,
+			IntentDigest: result.Intent.IntentDigest,
+		}, nil
+	}
+}
+
+func (relay *Relay) Server() *ServerInfo {
+	relay.mu.Lock()
+	defer relay.mu.Unlock()
+	if relay.server == nil {
+		return nil
+	}
+	copied := *relay.server
+	return &copied
+}
+
+// Handle processes one client payload (a single message or a batch) and returns
+// the encoded reply, or nil when the payload only carried notifications.
+func (relay *Relay) Handle(ctx context.Context, sessionID string, raw []byte) []byte {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(trimmed, &batch); err != nil || len(batch) == 0 {
+			return encodeRPCMessage(newRPCError(nil, RPCErrorInvalidRequest, "invalid json-rpc batch", nil))
+		}
+		replies := make([]RPCMessage, 0, len(batch))
+		for _, item := range batch {
+			if reply := relay.handleMessage(ctx, sessionID, item); reply != nil {
+				replies = append(replies, *reply)
+			}
+		}
+		if len(replies) == 0 {
+			return nil
+		}
+		encoded, err := json.Marshal(replies)
+		if err != nil {
+			return encodeRPCMessage(newRPCError(nil, RPCErrorInternal, "encode batch response", nil))
+		}
+		return encoded
+	}
+	reply := relay.handleMessage(ctx, sessionID, trimmed)
+	if reply == nil {
+		return nil
+	}
+	return encodeRPCMessage(*reply)
+}
+
+func (relay *Relay) handleMessage(ctx context.Context, sessionID string, raw []byte) *RPCMessage {
+	message, err := DecodeRPCMessage(raw)
+	if err != nil {
+		reply := newRPCError(nil, RPCErrorParse, err.Error(), nil)
+		if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+			reply.Error.Code = RPCErrorInvalidRequest
+		}
+		return &reply
+	}
+	switch {
+	case message.Method == "tools/call" && !message.HasID():
+		// A tools/call without an id would reach the upstream as a notification
+		// that skips the gate, and many servers still run the tool.
+		reply := newRPCError(nil, RPCErrorInvalidRequest, "tools/call requires an id", nil)
+		return &reply
+	case message.IsResponse(), message.IsNotification():
+		_ = relay.upstream.Notify(ctx, message)
+		return nil
+	case !message.IsRequest():
+		reply := newRPCError(message.ID, RPCErrorInvalidRequest, "json-rpc request requires method", nil)
+		return &reply
+	}
+	switch message.Method {
+	case "tools/call":
+		reply := relay.handleToolCall(ctx, sessionID, message)
+		return &reply
+	case "initialize":
+		reply := relay.forward(ctx, message)
+		relay.captureServerInfo(reply)
+		return &reply
+	default:
+		reply := relay.forward(ctx, message)
+		return &reply
+	}
+}
+
+func (relay *Relay) handleToolCall(ctx context.Context, sessionID string, message RPCMessage) RPCMessage {
+	var params relayToolCallParams
+	if err := json.Unmarshal(message.Params, &params); err != nil || strings.TrimSpace(params.Name) == "" {
+		return newRPCError(message.ID, RPCErrorInvalidParams, "tools/call requires params.name", nil)
+	}
+	callContext := relay.context
+	if trimmed := strings.TrimSpace(sessionID); trimmed != "" {
+		callContext.SessionID = trimmed
+	}
+	call := ToolCall{
+		Name:      strings.TrimSpace(params.Name),
+		Args:      nonNilArgs(params.Arguments),
+		Server:    relay.Server(),
+		Context:   callContext,
+		CreatedAt: relay.now().UTC(),
+	}
+	decision, err := relay.evaluate(ctx, call)
+	if err != nil {
+		return newRPCError(message.ID, RPCErrorEvaluationFailed, "gate evaluation failed", map[string]any{
+			"verdict":      "block",
+			"reason_codes": []string{"gate_evaluation_failed"},
+			"error":        err.Error(),
+		})
+	}
+	if strings.TrimSpace(decision.Verdict) == "allow" {
+		return relay.forward(ctx, message)
+	}
+	code, text := relayVerdictError(decision.Verdict)
+	return newRPCError(message.ID, code, text, decision)
+}
+
+func (relay *Relay) forward(ctx context.Context, message RPCMessage) RPCMessage {
+	reply, err := relay.upstream.Call(ctx, message)
+	if err != nil {
+		reason := "upstream_unavailable"
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			reason = "upstream_timeout"
+		}
+		return newRPCError(message.ID, RPCErrorUpstreamUnavailable, "upstream mcp server unavailable", map[string]any{
+			"reason_codes": []string{reason},
+			"error":        err.Error(),
+		})
+	}
+	reply.JSONRPC = jsonRPCVersion
+	reply.ID = message.ID
+	return reply
+}
+
+func (relay *Relay) captureServerInfo(reply RPCMessage) {
+	if reply.Error != nil || len(reply.Result) == 0 {
+		return
+	}
+	var result struct {
+		ServerInfo struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"serverInfo"`
+	}
+	if err := json.Unmarshal(reply.Result, &result); err != nil {
+		return
+	}
+	name := strings.TrimSpace(result.ServerInfo.Name)
+	if name == "" {
+		return
+	}
+	relay.mu.Lock()
+	defer relay.mu.Unlock()
+	if relay.server == nil {
+		relay.server = &ServerInfo{}
+	}
+	if strings.TrimSpace(relay.server.ServerName) == "" {
+		relay.server.ServerName = name
+	}
+}
+
+func relayVerdictError(verdict string) (int, string) {
+	switch strings.TrimSpace(verdict) {
+	case "require_approval":
+		return RPCErrorApprovalRequired, "tool call requires approval"
+	case "dry_run":
+		return RPCErrorDryRun, "tool call evaluated in dry-run mode and was not executed"
+	default:
+		return RPCErrorPolicyBlocked, "tool call blocked by policy"
+	}
+}
+
+func encodeRPCMessage(message RPCMessage) []byte {
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return []byte(`{"jsonrpc":"2.0","id":null,"error":{"code":-32603,"message":"encode response"}}`)
+	}
+	return encoded
+}
@@ -0,0 +1,469 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/gate"
+)
+
+type fakeUpstream struct {
+	mu       sync.Mutex
+	calls    []RPCMessage
+	notified []RPCMessage
+	fail     error
+}
+
+func (upstream *fakeUpstream) Call(_ context.Context, request RPCMessage) (RPCMessage, error) {
+	upstream.mu.Lock()
+	defer upstream.mu.Unlock()
+	upstream.calls = append(upstream.calls, request)
+	if upstream.fail != nil {
+		return RPCMessage{}, upstream.fail
+	}
+	switch request.Method {
+	case "initialize":
+		return RPCMessage{JSONRPC: "2.0", ID: request.ID, Result: json.RawMessage(`{"protocolVersion":"2025-06-18","serverInfo":{"name":"fake-fs","version":"1.0.0"}}`)}, nil
+	case "tools/call":
+		return RPCMessage{JSONRPC: "2.0", ID: request.ID, Result: json.RawMessage(`{"content":[{"type":"text","text":"done"}]}`)}, nil
+	default:
+		return RPCMessage{JSONRPC: "2.0", ID: request.ID, Result: json.RawMessage(`{"tools":[]}`)}, nil
+	}
+}
+
+func (upstream *fakeUpstream) Notify(_ context.Context, message RPCMessage) error {
+	upstream.mu.Lock()
+	defer upstream.mu.Unlock()
+	upstream.notified = append(upstream.notified, message)
+	return nil
+}
+
+func (upstream *fakeUpstream) Close() error {
+	return nil
+}
+
+func (upstream *fakeUpstream) methods() []string {
+	upstream.mu.Lock()
+	defer upstream.mu.Unlock()
+	methods := make([]string, 0, len(upstream.calls))
+	for _, call := range upstream.calls {
+		methods = append(methods, call.Method)
+	}
+	return methods
+}
+
+func mustRelayPolicy(t *testing.T) gate.Policy {
+	t.Helper()
+	policy, err := gate.ParsePolicyYAML([]byte(`
+default_verdict: allow
+rules:
+  - name: block-delete
+    effect: block
+    match:
+      tool_names: [fs.delete]
+  - name: approve-write
+    effect: require_approval
+    match:
+      tool_names: [fs.write]
+`))
+	if err != nil {
+		t.Fatalf("parse policy: %v", err)
+	}
+	return policy
+}
+
+func mustDecodeReply(t *testing.T, raw []byte) RPCMessage {
+	t.Helper()
+	var reply RPCMessage
+	if err := json.Unmarshal(raw, &reply); err != nil {
+		t.Fatalf("decode reply %s: %v", string(raw), err)
+	}
+	return reply
+}
+
+func TestRelayForwardsAllowedToolCalls(t *testing.T) {
+	upstream := &fakeUpstream{}
+	relay, err := NewRelay(RelayOptions{
+		Upstream: upstream,
+		Evaluate: PolicyCallEvaluator(mustRelayPolicy(t), gate.EvalOptions{ProducerVersion: "test"}, IntentOptions{}),
+	})
+	if err != nil {
+		t.Fatalf("new relay: %v", err)
+	}
+
+	reply := mustDecodeReply(t, relay.Handle(context.Background(), "", []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)))
+	if reply.Error != nil || string(reply.ID) != "1" {
+		t.Fatalf("unexpected initialize reply: %#v", reply)
+	}
+	if server := relay.Server(); server == nil || server.ServerName != "fake-fs" {
+		t.Fatalf("expected server name captured from initialize, got %#v", server)
+	}
+
+	reply = mustDecodeReply(t, relay.Handle(context.Background(), "sess-1", []byte(`{"jsonrpc":"2.0","id":"call-1","method":"tools/call","params":{"name":"fs.read","arguments":{"path":"/tmp/a"}}}`)))
+	if reply.Error != nil {
+		t.Fatalf("expected allowed call to be forwarded, got error %#v", reply.Error)
+	}
+	if string(reply.ID) != `"call-1"` || !strings.Contains(string(reply.Result), "done") {
+		t.Fatalf("unexpected tools/call reply: %#v", reply)
+	}
+	if methods := upstream.methods(); strings.Join(methods, ",") != "initialize,tools/call" {
+		t.Fatalf("unexpected upstream methods: %v", methods)
+	}
+}
+
+func TestRelayRejectsNonAllowVerdicts(t *testing.T) {
+	upstream := &fakeUpstream{}
+	relay, err := NewRelay(RelayOptions{
+		Upstream: upstream,
+		Evaluate: PolicyCallEvaluator(mustRelayPolicy(t), gate.EvalOptions{ProducerVersion: "test"}, IntentOptions{}),
+	})
+	if err != nil {
+		t.Fatalf("new relay: %v", err)
+	}
+
+	cases := []struct {
+		tool    string
+		code    int
+		verdict string
+	}{
+		{tool: "fs.delete", code: RPCErrorPolicyBlocked, verdict: "block"},
+		{tool: "fs.write", code: RPCErrorApprovalRequired, verdict: "require_approval"},
+	}
+	for _, testCase := range cases {
+		raw := relay.Handle(context.Background(), "", []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":%q,"arguments":{}}}`, testCase.tool)))
+		reply := mustDecodeReply(t, raw)
+		if reply.Error == nil || reply.Error.Code != testCase.code {
+			t.Fatalf("%s: expected error code %d, got %#v", testCase.tool, testCase.code, reply)
+		}
+		data, ok := reply.Error.Data.(map[string]any)
+		if !ok || data["verdict"] != testCase.verdict {
+			t.Fatalf("%s: expected structured verdict data, got %#v", testCase.tool, reply.Error.Data)
+		}
+		if data["policy_digest"] == "" || data["intent_digest"] == "" {
+			t.Fatalf("%s: expected digests in error data, got %#v", testCase.tool, data)
+		}
+	}
+	if methods := upstream.methods(); len(methods) != 0 {
+		t.Fatalf("expected no upstream calls for non-allow verdicts, got %v", methods)
+	}
+}
+
+func TestRelayFailsClosedOnEvaluationError(t *testing.T) {
+	upstream := &fakeUpstream{}
+	relay, err := NewRelay(RelayOptions{
+		Upstream: upstream,
+		Evaluate: func(context.Context, ToolCall) (CallDecision, error) {
+			return CallDecision{}, errors.New("policy unavailable")
+		},
+	})
+	if err != nil {
+		t.Fatalf("new relay: %v", err)
+	}
+	reply := mustDecodeReply(t, relay.Handle(context.Background(), "", []byte(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"fs.read"}}`)))
+	if reply.Error == nil || reply.Error.Code != RPCErrorEvaluationFailed {
+		t.Fatalf("expected evaluation failure error, got %#v", reply)
+	}
+	if len(upstream.methods()) != 0 {
+		t.Fatalf("expected no upstream call after evaluation failure")
+	}
+
+	unknown, err := NewRelay(RelayOptions{
+		Upstream: upstream,
+		Evaluate: func(context.Context, ToolCall) (CallDecision, error) {
+			return CallDecision{Verdict: "maybe"}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("new relay: %v", err)
+	}
+	reply = mustDecodeReply(t, unknown.Handle(context.Background(), "", []byte(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"fs.read"}}`)))
+	if reply.Error == nil || reply.Error.Code != RPCErrorPolicyBlocked {
+		t.Fatalf("expected unknown verdict to block, got %#v", reply)
+	}
+}
+
+func TestRelayPassesCallContextToEvaluator(t *testing.T) {
+	var captured ToolCall
+	relay, err := NewRelay(RelayOptions{
+		Upstream: &fakeUpstream{},
+		Evaluate: func(_ context.Context, call ToolCall) (CallDecision, error) {
+			captured = call
+			return CallDecision{Verdict: "allow"}, nil
+		},
+		Server:  &ServerInfo{ServerID: "github"},
+		Context: CallContext{Identity: "alice", Workspace: "/repo", RiskClass: "high"},
+	})
+	if err != nil {
+		t.Fatalf("new relay: %v", err)
+	}
+	relay.Handle(context.Background(), "sess-9", []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"repo.push","arguments":{"branch":"main"}}}`))
+	if captured.Name != "repo.push" || captured.Args["branch"] != "main" {
+		t.Fatalf("unexpected captured call: %#v", captured)
+	}
+	if captured.Context.Identity != "alice" || captured.Context.SessionID != "sess-9" {
+		t.Fatalf("unexpected captured context: %#v", captured.Context)
+	}
+	if captured.Server == nil || captured.Server.ServerID != "github" {
+		t.Fatalf("expected configured server info, got %#v", captured.Server)
+	}
+	if captured.CreatedAt.IsZero() {
+		t.Fatalf("expected relay to stamp created_at")
+	}
+}
+
+func TestRelayNotificationsBatchesAndErrors(t *testing.T) {
+	upstream := &fakeUpstream{}
+	relay, err := NewRelay(RelayOptions{
+		Upstream: upstream,
+		Evaluate: PolicyCallEvaluator(mustRelayPolicy(t), gate.EvalOptions{ProducerVersion: "test"}, IntentOptions{}),
+	})
+	if err != nil {
+		t.Fatalf("new relay: %v", err)
+	}
+	if reply := relay.Handle(context.Background(), "", []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)); reply != nil {
+		t.Fatalf("expected no reply for notification, got %s", string(reply))
+	}
+	if len(upstream.notified) != 1 || upstream.notified[0].Method != "notifications/initialized" {
+		t.Fatalf("expected notification forwarded upstream, got %#v", upstream.notified)
+	}
+
+	batch := relay.Handle(context.Background(), "", []byte(`[
+  {"jsonrpc":"2.0","id":1,"method":"tools/list"},
+  {"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"fs.delete"}},
+  {"jsonrpc":"2.0","method":"notifications/progress"}
+]`))
+	var replies []RPCMessage
+	if err := json.Unmarshal(batch, &replies); err != nil {
+		t.Fatalf("decode batch reply: %v", err)
+	}
+	if len(replies) != 2 || replies[0].Error != nil || replies[1].Error == nil || replies[1].Error.Code != RPCErrorPolicyBlocked {
+		t.Fatalf("unexpected batch replies: %#v", replies)
+	}
+
+	reply := mustDecodeReply(t, relay.Handle(context.Background(), "", []byte(`not json`)))
+	if reply.Error == nil || reply.Error.Code != RPCErrorParse || string(reply.ID) != "null" {
+		t.Fatalf("expected parse error, got %#v", reply)
+	}
+	reply = mustDecodeReply(t, relay.Handle(context.Background(), "", []byte(`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{}}`)))
+	if reply.Error == nil || reply.Error.Code != RPCErrorInvalidParams {
+		t.Fatalf("expected invalid params error, got %#v", reply)
+	}
+
+	upstream.fail = ErrUpstreamClosed
+	reply = mustDecodeReply(t, relay.Handle(context.Background(), "", []byte(`{"jsonrpc":"2.0","id":6,"method":"tools/list"}`)))
+	if reply.Error == nil || reply.Error.Code != RPCErrorUpstreamUnavailable {
+		t.Fatalf("expected upstream unavailable error, got %#v", reply)
+	}
+}
+
+func TestRelayRejectsToolCallsWithoutID(t *testing.T) {
+	upstream := &fakeUpstream{}
+	relay, err := NewRelay(RelayOptions{
+		Upstream: upstream,
+		Evaluate: PolicyCallEvaluator(mustRelayPolicy(t), gate.EvalOptions{ProducerVersion: "test"}, IntentOptions{}),
+	})
+	if err != nil {
+		t.Fatalf("new relay: %v", err)
+	}
+	for _, payload := range []string{
+		`{"jsonrpc":"2.0","method":"tools/call","params":{"name":"fs.delete"}}`,
+		`{"jsonrpc":"2.0","id":null,"method":"tools/call","params":{"name":"fs.delete"}}`,
+	} {
+		reply := mustDecodeReply(t, relay.Handle(context.Background(), "", []byte(payload)))
+		if reply.Error == nil || reply.Error.Code != RPCErrorInvalidRequest {
+			t.Fatalf("expected invalid request for %s, got %#v", payload, reply)
+		}
+	}
+	if len(upstream.notified) != 0 || len(upstream.calls) != 0 {
+		t.Fatalf("expected nothing sent upstream, notified=%#v calls=%#v", upstream.notified, upstream.calls)
+	}
+}
+
+func TestNewRelayValidation(t *testing.T) {
+	if _, err := NewRelay(RelayOptions{Evaluate: func(context.Context, ToolCall) (CallDecision, error) { return CallDecision{}, nil }}); err == nil {
+		t.Fatalf("expected missing upstream error")
+	}
+	if _, err := NewRelay(RelayOptions{Upstream: &fakeUpstream{}}); err == nil {
+		t.Fatalf("expected missing evaluator error")
+	}
+}
+
+func TestHTTPUpstreamJSONAndEventStream(t *testing.T) {
+	var sessionHeaders []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		mu.Lock()
+		sessionHeaders = append(sessionHeaders, request.Header.Get("Mcp-Session-Id"))
+		mu.Unlock()
+		raw, _ := io.ReadAll(request.Body)
+		message, err := DecodeRPCMessage(raw)
+		if err != nil {
+			http.Error(writer, "bad request", http.StatusBadRequest)
+			return
+		}
+		switch {
+		case message.IsNotification():
+			writer.WriteHeader(http.StatusAccepted)
+		case message.Method == "initialize":
+			writer.Header().Set("Mcp-Session-Id", "upstream-session-1")
+			writer.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(writer, `{"jsonrpc":"2.0","id":%s,"result":{"serverInfo":{"name":"http-fake"}}}`, string(message.ID))
+		default:
+			writer.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprintf(writer, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n")
+			_, _ = fmt.Fprintf(writer, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":%s,\"result\":{\"content\":[],\"isError\":true}}\n\n", string(message.ID))
+		}
+	}))
+	defer server.Close()
+
+	var unsolicited []RPCMessage
+	upstream, err := NewHTTPUpstream(HTTPUpstreamOptions{
+		URL: server.URL,
+		OnMessage: func(message RPCMessage) {
+			unsolicited = append(unsolicited, message)
+		},
+	})
+	if err != nil {
+		t.Fatalf("new http upstream: %v", err)
+	}
+	response, err := upstream.Call(context.Background(), RPCMessage{JSONRPC: "2.0", ID: json.RawMessage(`1`), Method: "initialize"})
+	if err != nil || !strings.Contains(string(response.Result), "http-fake") {
+		t.Fatalf("initialize: response=%#v err=%v", response, err)
+	}
+	if err := upstream.Notify(context.Background(), RPCMessage{JSONRPC: "2.0", Method: "notifications/initialized"}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+
+	adapter := &UpstreamAdapter{Upstream: upstream}
+	result, err := adapter.CallTool(context.Background(), ToolCall{Name: "fs.read"})
+	if err != nil {
+		t.Fatalf("adapter call tool: %v", err)
+	}
+	if result.Status != "error" {
+		t.Fatalf("expected isError result to map to error status, got %#v", result)
+	}
+	if len(unsolicited) != 1 || unsolicited[0].Method != "notifications/progress" {
+		t.Fatalf("expected unsolicited progress notification, got %#v", unsolicited)
+	}
+	if err := upstream.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sessionHeaders) != 4 || sessionHeaders[0] != "" || sessionHeaders[1] != "upstream-session-1" || sessionHeaders[3] != "upstream-session-1" {
+		t.Fatalf("unexpected session header propagation: %#v", sessionHeaders)
+	}
+
+	if _, err := NewHTTPUpstream(HTTPUpstreamOptions{URL: "ftp://example"}); err == nil {
+		t.Fatalf("expected non-http upstream url to fail")
+	}
+}
+
+func TestStdioUpstreamRoundTrip(t *testing.T) {
+	t.Setenv("GAIT_MCP_HELPER_PROCESS", "1")
+	notifications := make(chan RPCMessage, 1)
+	upstream, err := StartStdioUpstream(StdioUpstreamOptions{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=TestStdioUpstreamHelperProcess", "--"},
+		OnMessage: func(message RPCMessage) {
+			notifications <- message
+		},
+	})
+	if err != nil {
+		t.Fatalf("start stdio upstream: %v", err)
+	}
+	defer func() {
+		_ = upstream.Close()
+	}()
+
+	var wg sync.WaitGroup
+	for index := 0; index < 4; index++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			clientID := json.RawMessage(fmt.Sprintf(`"client-%d"`, index))
+			response, err := upstream.Call(context.Background(), RPCMessage{JSONRPC: "2.0", ID: clientID, Method: "tools/list"})
+			if err != nil {
+				t.Errorf("call %d: %v", index, err)
+				return
+			}
+			if string(response.ID) != string(clientID) {
+				t.Errorf("call %d: expected client id restored, got %s", index, string(response.ID))
+			}
+		}(index)
+	}
+	wg.Wait()
+
+	if err := upstream.Notify(context.Background(), RPCMessage{JSONRPC: "2.0", Method: "notifications/initialized"}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	message := <-notifications
+	if message.Method != "notifications/tools/list_changed" {
+		t.Fatalf("expected unsolicited upstream notification, got %#v", message)
+	}
+	if err := upstream.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := upstream.Call(context.Background(), RPCMessage{JSONRPC: "2.0", ID: json.RawMessage(`9`), Method: "ping"}); !errors.Is(err, ErrUpstreamClosed) {
+		t.Fatalf("expected closed upstream error, got %v", err)
+	}
+}
+
+func TestStdioUpstreamSurvivesDuplicateResponses(t *testing.T) {
+	t.Setenv("GAIT_MCP_HELPER_PROCESS", "1")
+	upstream, err := StartStdioUpstream(StdioUpstreamOptions{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=TestStdioUpstreamHelperProcess", "--"},
+	})
+	if err != nil {
+		t.Fatalf("start stdio upstream: %v", err)
+	}
+	defer func() {
+		_ = upstream.Close()
+	}()
+
+	if _, err := upstream.Call(context.Background(), RPCMessage{JSONRPC: "2.0", ID: json.RawMessage(`1`), Method: "test/duplicate_response"}); err != nil {
+		t.Fatalf("duplicate call: %v", err)
+	}
+	for index := 2; index < 5; index++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_, err := upstream.Call(ctx, RPCMessage{JSONRPC: "2.0", ID: json.RawMessage(fmt.Sprint(index)), Method: "tools/list"})
+		cancel()
+		if err != nil {
+			t.Fatalf("call %d after duplicate response: %v", index, err)
+		}
+	}
+}
+
+func TestStdioUpstreamHelperProcess(t *testing.T) {
+	if os.Getenv("GAIT_MCP_HELPER_PROCESS") != "1" {
+		return
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		message, err := DecodeRPCMessage(scanner.Bytes())
+		if err != nil {
+			continue
+		}
+		if message.IsNotification() {
+			_, _ = fmt.Fprintln(os.Stdout, `{"jsonrpc":"2.0","method":"notifications/tools/list_changed"}`)
+			continue
+		}
+		_, _ = fmt.Fprintf(os.Stdout, "{\"jsonrpc\":\"2.0\",\"id\":%s,\"result\":{\"tools\":[]}}\n", string(message.ID))
+		for index := 0; message.Method == "test/duplicate_response" && index < 2; index++ {
+			_, _ = fmt.Fprintf(os.Stdout, "{\"jsonrpc\":\"2.0\",\"id\":%s,\"result\":{\"tools\":[]}}\n", string(message.ID))
+		}
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const jsonRPCVersion = "2.0"
+
+const (
+	RPCErrorParse               = -32700
+	RPCErrorInvalidRequest      = -32600
+	RPCErrorMethodNotFound      = -32601
+	RPCErrorInvalidParams       = -32602
+	RPCErrorInternal            = -32603
+	RPCErrorPolicyBlocked       = -32001
+	RPCErrorApprovalRequired    = -32002
+	RPCErrorDryRun              = -32003
+	RPCErrorUpstreamUnavailable = -32004
+	RPCErrorEvaluationFailed    = -32005
+)
+
+type RPCMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (rpcError *RPCError) Error() string {
+	if rpcError == nil {
+		return "json-rpc error"
+	}
+	return fmt.Sprintf("json-rpc error %d: %s", rpcError.Code, rpcError.Message)
+}
+
+func (message RPCMessage) HasID() bool {
+	trimmed := bytes.TrimSpace(message.ID)
+	return len(trimmed) > 0 && !bytes.Equal(trimmed, []byte("null"))
+}
+
+func (message RPCMessage) IsRequest() bool {
+	return strings.TrimSpace(message.Method) != "" && message.HasID()
+}
+
+func (message RPCMessage) IsNotification() bool {
+	return strings.TrimSpace(message.Method) != "" && !message.HasID()
+}
+
+func (message RPCMessage) IsResponse() bool {
+	return strings.TrimSpace(message.Method) == "" && (message.HasID() || message.Error != nil)
+}
+
+func DecodeRPCMessage(raw []byte) (RPCMessage, error) {
+	var message RPCMessage
+	if err := json.Unmarshal(raw, &message); err != nil {
+		return RPCMessage{}, fmt.Errorf("parse json-rpc message: %w", err)
+	}
+	if message.JSONRPC != jsonRPCVersion {
+		return RPCMessage{}, fmt.Errorf("unsupported json-rpc version %q", message.JSONRPC)
+	}
+	return message, nil
+}
+
+func newRPCError(id json.RawMessage, code int, message string, data any) RPCMessage {
+	return RPCMessage{
+		JSONRPC: jsonRPCVersion,
+		ID:      rpcResponseID(id),
+		Error: &RPCError{
+			Code:    code,
+			Message: message,
+			Data:    data,
+		},
+	}
+}
+
+func rpcResponseID(id json.RawMessage) json.RawMessage {
+	if len(bytes.TrimSpace(id)) == 0 {
+		return json.RawMessage("null")
+	}
+	return id
+}
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/Clyra-AI/gait/core/fsx"
 	"github.com/Clyra-AI/gait/core/gate"
 	"github.com/Clyra-AI/gait/core/registry"
 	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
@@ -231,6 +233,72 @@ func LoadTrustSnapshot(path string) (TrustSnapshot, error) {
 	return snapshot, nil
 }
 
+// ObserveTrustSnapshotEntry records a captured server in the trust snapshot. New
+// servers start as "observed" so they are not trusted until an operator says so;
+// existing entries keep their status, score and freshness.
+func ObserveTrustSnapshotEntry(snapshot TrustSnapshot, server ToolAnnotationServer, evidencePath string) TrustSnapshot {
+	key := normalizedServerKey(server.ServerID, server.ServerName)
+	for index := range snapshot.Entries {
+		entry := &snapshot.Entries[index]
+		if normalizedServerKey(entry.ServerID, entry.ServerName) != key {
+			continue
+		}
+		if strings.TrimSpace(entry.ServerName) == "" {
+			entry.ServerName = server.ServerName
+		}
+		if strings.TrimSpace(entry.Endpoint) == "" {
+			entry.Endpoint = server.Endpoint
+		}
+		if strings.TrimSpace(entry.EvidencePath) == "" {
+			entry.EvidencePath = strings.TrimSpace(evidencePath)
+		}
+		return snapshot
+	}
+	snapshot.Entries = append(snapshot.Entries, TrustSnapshotEntry{
+		ServerID:     server.ServerID,
+		ServerName:   server.ServerName,
+		Source:       "tools_list",
+		Endpoint:     server.Endpoint,
+		Status:       "observed",
+		UpdatedAt:    server.CapturedAt.UTC(),
+		EvidencePath: strings.TrimSpace(evidencePath),
+	})
+	sort.Slice(snapshot.Entries, func(i, j int) bool {
+		return normalizedServerKey(snapshot.Entries[i].ServerID, snapshot.Entries[i].ServerName) <
+			normalizedServerKey(snapshot.Entries[j].ServerID, snapshot.Entries[j].ServerName)
+	})
+	return snapshot
+}
+
+func WriteTrustSnapshot(path string, snapshot TrustSnapshot) error {
+	trimmedPath := strings.TrimSpace(path)
+	if trimmedPath == "" {
+		return fmt.Errorf("trust snapshot path is required")
+	}
+	if strings.TrimSpace(snapshot.SchemaID) == "" {
+		snapshot.SchemaID = mcpTrustSnapshotSchemaID
+	}
+	if strings.TrimSpace(snapshot.SchemaVersion) == "" {
+		snapshot.SchemaVersion = mcpTrustSnapshotSchemaVersion
+	}
+	if snapshot.Entries == nil {
+		snapshot.Entries = []TrustSnapshotEntry{}
+	}
+	encoded, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode trust snapshot: %w", err)
+	}
+	if dir := filepath.Dir(trimmedPath); dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return fmt.Errorf("create trust snapshot directory: %w", err)
+		}
+	}
+	if err := fsx.WriteFileAtomic(trimmedPath, append(encoded, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write trust snapshot: %w", err)
+	}
+	return nil
+}
+
 func findTrustSnapshotEntry(entries []TrustSnapshotEntry, serverKey string) (TrustSnapshotEntry, bool) {
 	for _, entry := range entries {
 		if normalizedServerKey(entry.ServerID, entry.ServerName) == serverKey {
@@ -0,0 +1,446 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	maxUpstreamMessageBytes = 10 << 20
+	upstreamSessionHeader   = "Mcp-Session-Id"
+)
+
+var ErrUpstreamClosed = errors.New("mcp upstream closed")
+
+// Upstream is a JSON-RPC connection to the MCP server that sits behind the relay.
+type Upstream interface {
+	Call(context.Context, RPCMessage) (RPCMessage, error)
+	Notify(context.Context, RPCMessage) error
+	Close() error
+}
+
+type StdioUpstreamOptions struct {
+	Command   string
+	Args      []string
+	Env       []string
+	Dir       string
+	Stderr    io.Writer
+	OnMessage func(RPCMessage)
+}
+
+type StdioUpstream struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	onMessage func(RPCMessage)
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	pending map[string]chan RPCMessage
+	closed  bool
+	nextID  atomic.Int64
+	done    chan struct{}
+}
+
+func StartStdioUpstream(opts StdioUpstreamOptions) (*StdioUpstream, error) {
+	command := strings.TrimSpace(opts.Command)
+	if command == "" {
+		return nil, fmt.Errorf("upstream command is required")
+	}
+	// #nosec G204 -- upstream command is explicit local operator configuration.
+	cmd := exec.Command(command, opts.Args...)
+	cmd.Dir = strings.TrimSpace(opts.Dir)
+	if len(opts.Env) > 0 {
+		cmd.Env = append(cmd.Environ(), opts.Env...)
+	}
+	cmd.Stderr = opts.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("open upstream stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("open upstream stdout: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start upstream command: %w", err)
+	}
+	upstream := &StdioUpstream{
+		cmd:       cmd,
+		stdin:     stdin,
+		onMessage: opts.OnMessage,
+		pending:   map[string]chan RPCMessage{},
+		done:      make(chan struct{}),
+	}
+	go upstream.readLoop(stdout)
+	return upstream, nil
+}
+
+func (upstream *StdioUpstream) Call(ctx context.Context, request RPCMessage) (RPCMessage, error) {
+	if !request.IsRequest() {
+		return RPCMessage{}, fmt.Errorf("upstream call requires a json-rpc request with id")
+	}
+	clientID := request.ID
+	internalID := strconv.FormatInt(upstream.nextID.Add(1), 10)
+	request.ID = json.RawMessage(internalID)
+	responseCh := make(chan RPCMessage, 1)
+
+	upstream.mu.Lock()
+	if upstream.closed {
+		upstream.mu.Unlock()
+		return RPCMessage{}, ErrUpstreamClosed
+	}
+	upstream.pending[internalID] = responseCh
+	upstream.mu.Unlock()
+	defer func() {
+		upstream.mu.Lock()
+		delete(upstream.pending, internalID)
+		upstream.mu.Unlock()
+	}()
+
+	if err := upstream.write(request); err != nil {
+		return RPCMessage{}, err
+	}
+	select {
+	case response, ok := <-responseCh:
+		if !ok {
+			return RPCMessage{}, ErrUpstreamClosed
+		}
+		response.ID = clientID
+		return response, nil
+	case <-upstream.done:
+		return RPCMessage{}, ErrUpstreamClosed
+	case <-ctx.Done():
+		return RPCMessage{}, ctx.Err()
+	}
+}
+
+func (upstream *StdioUpstream) Notify(_ context.Context, message RPCMessage) error {
+	return upstream.write(message)
+}
+
+func (upstream *StdioUpstream) Close() error {
+	upstream.mu.Lock()
+	alreadyClosed := upstream.closed
+	upstream.closed = true
+	upstream.mu.Unlock()
+	if alreadyClosed {
+		return nil
+	}
+	_ = upstream.stdin.Close()
+	select {
+	case <-upstream.done:
+	case <-time.After(2 * time.Second):
+		if upstream.cmd.Process != nil {
+			_ = upstream.cmd.Process.Kill()
+		}
+		<-upstream.done
+	}
+	return nil
+}
+
+func (upstream *StdioUpstream) write(message RPCMessage) error {
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("encode upstream message: %w", err)
+	}
+	encoded = append(encoded, '\n')
+	upstream.writeMu.Lock()
+	defer upstream.writeMu.Unlock()
+	select {
+	case <-upstream.done:
+		return ErrUpstreamClosed
+	default:
+	}
+	if _, err := upstream.stdin.Write(encoded); err != nil {
+		return fmt.Errorf("write upstream message: %w", err)
+	}
+	return nil
+}
+
+func (upstream *StdioUpstream) readLoop(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxUpstreamMessageBytes)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		message, err := DecodeRPCMessage(line)
+		if err != nil {
+			continue
+		}
+		if message.IsResponse() && message.HasID() {
+			// The first response claims the pending call; a duplicate id from a
+			// misbehaving upstream must not block the read loop.
+			key := string(bytes.TrimSpace(message.ID))
+			upstream.mu.Lock()
+			responseCh, ok := upstream.pending[key]
+			delete(upstream.pending, key)
+			upstream.mu.Unlock()
+			if ok {
+				select {
+				case responseCh <- message:
+				default:
+				}
+				continue
+			}
+		}
+		if upstream.onMessage != nil {
+			upstream.onMessage(message)
+		}
+	}
+	_ = upstream.cmd.Wait()
+	upstream.mu.Lock()
+	upstream.closed = true
+	upstream.mu.Unlock()
+	close(upstream.done)
+}
+
+type HTTPUpstreamOptions struct {
+	URL       string
+	Headers   map[string]string
+	Client    *http.Client
+	OnMessage func(RPCMessage)
+}
+
+type HTTPUpstream struct {
+	url       string
+	headers   map[string]string
+	client    *http.Client
+	onMessage func(RPCMessage)
+
+	mu        sync.Mutex
+	sessionID string
+}
+
+func NewHTTPUpstream(opts HTTPUpstreamOptions) (*HTTPUpstream, error) {
+	endpoint := strings.TrimSpace(opts.URL)
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return nil, fmt.Errorf("upstream url must use http or https")
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 60 * time.Second}
+	}
+	headers := map[string]string{}
+	for key, value := range opts.Headers {
+		headers[key] = value
+	}
+	return &HTTPUpstream{
+		url:       endpoint,
+		headers:   headers,
+		client:    client,
+		onMessage: opts.OnMessage,
+	}, nil
+}
+
+func (upstream *HTTPUpstream) Call(ctx context.Context, request RPCMessage) (RPCMessage, error) {
+	if !request.IsRequest() {
+		return RPCMessage{}, fmt.Errorf("upstream call requires a json-rpc request with id")
+	}
+	response, err := upstream.post(ctx, request)
+	if err != nil {
+		return RPCMessage{}, err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	if response.StatusCode == http.StatusAccepted || response.StatusCode == http.StatusNoContent {
+		return RPCMessage{}, fmt.Errorf("upstream returned no response for request")
+	}
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	body := io.LimitReader(response.Body, maxUpstreamMessageBytes)
+	if mediaType == "text/event-stream" {
+		return upstream.readEventStream(body, request.ID)
+	}
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return RPCMessage{}, fmt.Errorf("read upstream response: %w", err)
+	}
+	message, err := DecodeRPCMessage(raw)
+	if err != nil {
+		return RPCMessage{}, err
+	}
+	return message, nil
+}
+
+func (upstream *HTTPUpstream) Notify(ctx context.Context, message RPCMessage) error {
+	response, err := upstream.post(ctx, message)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxUpstreamMessageBytes))
+	_ = response.Body.Close()
+	return nil
+}
+
+func (upstream *HTTPUpstream) Close() error {
+	upstream.mu.Lock()
+	sessionID := upstream.sessionID
+	upstream.sessionID = ""
+	upstream.mu.Unlock()
+	if sessionID == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, upstream.url, nil)
+	if err != nil {
+		return nil
+	}
+	upstream.applyHeaders(request, sessionID)
+	response, err := upstream.client.Do(request)
+	if err != nil {
+		return nil
+	}
+	_ = response.Body.Close()
+	return nil
+}
+
+func (upstream *HTTPUpstream) post(ctx context.Context, message RPCMessage) (*http.Response, error) {
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("encode upstream message: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, upstream.url, bytes.NewReader(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("build upstream request: %w", err)
+	}
+	upstream.mu.Lock()
+	sessionID := upstream.sessionID
+	upstream.mu.Unlock()
+	upstream.applyHeaders(request, sessionID)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json, text/event-stream")
+	// #nosec G704 -- upstream url is explicit local operator configuration.
+	response, err := upstream.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUpstreamClosed, err)
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		_ = response.Body.Close()
+		return nil, fmt.Errorf("upstream returned http status %d", response.StatusCode)
+	}
+	if assigned := strings.TrimSpace(response.Header.Get(upstreamSessionHeader)); assigned != "" {
+		upstream.mu.Lock()
+		upstream.sessionID = assigned
+		upstream.mu.Unlock()
+	}
+	return response, nil
+}
+
+func (upstream *HTTPUpstream) applyHeaders(request *http.Request, sessionID string) {
+	for key, value := range upstream.headers {
+		request.Header.Set(key, value)
+	}
+	if sessionID != "" {
+		request.Header.Set(upstreamSessionHeader, sessionID)
+	}
+}
+
+func (upstream *HTTPUpstream) readEventStream(body io.Reader, requestID json.RawMessage) (RPCMessage, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxUpstreamMessageBytes)
+	var data bytes.Buffer
+	flush := func() (RPCMessage, bool) {
+		defer data.Reset()
+		if data.Len() == 0 {
+			return RPCMessage{}, false
+		}
+		message, err := DecodeRPCMessage(data.Bytes())
+		if err != nil {
+			return RPCMessage{}, false
+		}
+		if message.IsResponse() && bytes.Equal(bytes.TrimSpace(message.ID), bytes.TrimSpace(requestID)) {
+			return message, true
+		}
+		if upstream.onMessage != nil {
+			upstream.onMessage(message)
+		}
+		return RPCMessage{}, false
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if message, ok := flush(); ok {
+				return message, nil
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "data:") {
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if message, ok := flush(); ok {
+		return message, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return RPCMessage{}, fmt.Errorf("read upstream event stream: %w", err)
+	}
+	return RPCMessage{}, fmt.Errorf("upstream event stream ended without a response")
+}
+
+// UpstreamAdapter exposes an MCP upstream through the Adapter interface.
+type UpstreamAdapter struct {
+	Upstream Upstream
+	nextID   atomic.Int64
+}
+
+func (adapter *UpstreamAdapter) CallTool(ctx context.Context, call ToolCall) (ToolResult, error) {
+	if adapter.Upstream == nil {
+		return ToolResult{}, fmt.Errorf("upstream is required")
+	}
+	params, err := json.Marshal(map[string]any{
+		"name":      strings.TrimSpace(call.Name),
+		"arguments": nonNilArgs(call.Args),
+	})
+	if err != nil {
+		return ToolResult{}, fmt.Errorf("encode tools/call params: %w", err)
+	}
+	response, err := adapter.Upstream.Call(ctx, RPCMessage{
+		JSONRPC: jsonRPCVersion,
+		ID:      json.RawMessage(strconv.Quote(fmt.Sprintf("gait-adapter-%d", adapter.nextID.Add(1)))),
+		Method:  "tools/call",
+		Params:  params,
+	})
+	if err != nil {
+		return ToolResult{}, err
+	}
+	if response.Error != nil {
+		return ToolResult{}, response.Error
+	}
+	output := map[string]any{}
+	if len(response.Result) > 0 {
+		if err := json.Unmarshal(response.Result, &output); err != nil {
+			return ToolResult{}, fmt.Errorf("parse tools/call result: %w", err)
+		}
+	}
+	status := "ok"
+	if isError, ok := output["isError"].(bool); ok && isError {
+		status = "error"
+	}
+	return ToolResult{Status: status, Output: output}, nil
+}
+
+func nonNilArgs(args map[string]any) map[string]any {
+	if args == nil {
+		return map[string]any{}
+	}
+	return args
+}
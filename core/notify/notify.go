@@ -0,0 +1,538 @@
+package notify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	coreerrors "github.com/Clyra-AI/gait/core/errors"
+	"github.com/Clyra-AI/gait/core/fsx"
+)
+
+const (
+	eventSchemaID         = "gait.notify.event"
+	eventSchemaVersion    = "1.0.0"
+	deliverySchemaID      = "gait.notify.delivery"
+	deliverySchemaVersion = "1.0.0"
+
+	DefaultOutboxDir      = "./.gait-out/notify_outbox"
+	defaultMaxAttempts    = 8
+	defaultInitialBackoff = 5 * time.Second
+	defaultMaxBackoff     = 10 * time.Minute
+	defaultTimeout        = 5 * time.Second
+	inflightStaleAfter    = 5 * time.Minute
+
+	pendingDir   = "pending"
+	inflightDir  = "inflight"
+	failedDir    = "failed"
+	deliveredLog = "delivered.jsonl"
+)
+
+const (
+	EventApprovalRequired    = "approval_required"
+	EventKillSwitchEngaged   = "kill_switch_engaged"
+	EventKillSwitchTriggered = "kill_switch_triggered"
+	EventJobPaused           = "job_paused"
+	EventJobEmergencyStopped = "job_emergency_stopped"
+	EventJobCancelled        = "job_cancelled"
+	EventJobApproved         = "job_approved"
+)
+
+const (
+	SinkTypeWebhook = "webhook"
+	SinkTypeSlack   = "slack"
+	SinkTypeEmail   = "email"
+)
+
+type Config struct {
+	Outbox         string       `yaml:"outbox"`
+	MaxAttempts    int          `yaml:"max_attempts"`
+	InitialBackoff string       `yaml:"initial_backoff"`
+	MaxBackoff     string       `yaml:"max_backoff"`
+	Timeout        string       `yaml:"timeout"`
+	Sinks          []SinkConfig `yaml:"sinks"`
+}
+
+type SinkConfig struct {
+	Name        string   `yaml:"name"`
+	Type        string   `yaml:"type"`
+	URL         string   `yaml:"url"`
+	URLEnv      string   `yaml:"url_env"`
+	SecretEnv   string   `yaml:"secret_env"`
+	Events      []string `yaml:"events"`
+	SMTPAddr    string   `yaml:"smtp_addr"`
+	From        string   `yaml:"from"`
+	To          []string `yaml:"to"`
+	UsernameEnv string   `yaml:"username_env"`
+	PasswordEnv string   `yaml:"password_env"` // #nosec G117 -- config key names the env var, not a secret value.
+}
+
+type Event struct {
+	SchemaID        string            `json:"schema_id"`
+	SchemaVersion   string            `json:"schema_version"`
+	EventID         string            `json:"event_id"`
+	CreatedAt       time.Time         `json:"created_at"`
+	ProducerVersion string            `json:"producer_version"`
+	Kind            string            `json:"kind"`
+	Source          string            `json:"source"`
+	Summary         string            `json:"summary"`
+	TraceID         string            `json:"trace_id,omitempty"`
+	JobID           string            `json:"job_id,omitempty"`
+	ToolName        string            `json:"tool_name,omitempty"`
+	Identity        string            `json:"identity,omitempty"`
+	Actor           string            `json:"actor,omitempty"`
+	ReasonCodes     []string          `json:"reason_codes,omitempty"`
+	Details         map[string]string `json:"details,omitempty"`
+}
+
+type Delivery struct {
+	SchemaID      string    `json:"schema_id"`
+	SchemaVersion string    `json:"schema_version"`
+	DeliveryID    string    `json:"delivery_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	Sink          string    `json:"sink"`
+	Event         Event     `json:"event"`
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LastError     string    `json:"last_error,omitempty"`
+	DeliveredAt   time.Time `json:"delivered_at,omitempty"`
+}
+
+type FlushResult struct {
+	Delivered int      `json:"delivered"`
+	Retrying  int      `json:"retrying"`
+	Failed    int      `json:"failed"`
+	Pending   int      `json:"pending"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// Notifier fans events out to the configured sinks through a durable outbox.
+// Each event is written to the outbox before any delivery is attempted, so
+// deliveries that fail or are interrupted are retried by a later Flush.
+type Notifier struct {
+	outbox         string
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	sinks          []sink
+	now            func() time.Time
+}
+
+func (configuration Config) Enabled() bool {
+	return len(configuration.Sinks) > 0
+}
+
+func New(configuration Config) (*Notifier, error) {
+	notifier := &Notifier{
+		outbox:         strings.TrimSpace(configuration.Outbox),
+		maxAttempts:    configuration.MaxAttempts,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+	if notifier.outbox == "" {
+		notifier.outbox = DefaultOutboxDir
+	}
+	if notifier.maxAttempts <= 0 {
+		notifier.maxAttempts = defaultMaxAttempts
+	}
+	var err error
+	if notifier.initialBackoff, err = parseDurationDefault(configuration.InitialBackoff, defaultInitialBackoff, "initial_backoff"); err != nil {
+		return nil, err
+	}
+	if notifier.maxBackoff, err = parseDurationDefault(configuration.MaxBackoff, defaultMaxBackoff, "max_backoff"); err != nil {
+		return nil, err
+	}
+	timeout, err := parseDurationDefault(configuration.Timeout, defaultTimeout, "timeout")
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]struct{}{}
+	for index, sinkConfig := range configuration.Sinks {
+		built, err := newSink(sinkConfig, timeout)
+		if err != nil {
+			return nil, invalidConfig(fmt.Errorf("notify sink %d: %w", index, err))
+		}
+		if _, ok := seen[built.name()]; ok {
+			return nil, invalidConfig(fmt.Errorf("duplicate notify sink name %q", built.name()))
+		}
+		seen[built.name()] = struct{}{}
+		notifier.sinks = append(notifier.sinks, built)
+	}
+	return notifier, nil
+}
+
+func (notifier *Notifier) OutboxDir() string {
+	if notifier == nil {
+		return ""
+	}
+	return notifier.outbox
+}
+
+// Notify records one delivery per subscribed sink and attempts each once.
+// Delivery failures stay in the outbox for Flush; only outbox write failures
+// are returned.
+func (notifier *Notifier) Notify(ctx context.Context, event Event) error {
+	queued, err := notifier.enqueue(event)
+	if err != nil {
+		return err
+	}
+	for _, deliveryID := range queued {
+		_, _ = notifier.attempt(ctx, deliveryID)
+	}
+	return nil
+}
+
+// Enqueue records one delivery per subscribed sink without attempting any,
+// so callers on a decision path never wait on a sink. A later Flush delivers.
+func (notifier *Notifier) Enqueue(event Event) error {
+	_, err := notifier.enqueue(event)
+	return err
+}
+
+func (notifier *Notifier) enqueue(event Event) ([]string, error) {
+	if notifier == nil || len(notifier.sinks) == 0 {
+		return nil, nil
+	}
+	event = normalizeEvent(event, notifier.now())
+	if strings.TrimSpace(event.Kind) == "" {
+		return nil, invalidConfig(fmt.Errorf("notify event kind is required"))
+	}
+	queued := []string{}
+	for _, target := range notifier.sinks {
+		if !target.accepts(event.Kind) {
+			continue
+		}
+		delivery := Delivery{
+			SchemaID:      deliverySchemaID,
+			SchemaVersion: deliverySchemaVersion,
+			DeliveryID:    digestParts(event.EventID, target.name()),
+			CreatedAt:     event.CreatedAt,
+			Sink:          target.name(),
+			Event:         event,
+			NextAttemptAt: event.CreatedAt,
+		}
+		if err := writeDelivery(filepath.Join(notifier.outbox, pendingDir), delivery); err != nil {
+			return nil, err
+		}
+		queued = append(queued, delivery.DeliveryID)
+	}
+	return queued, nil
+}
+
+// Flush retries every pending delivery whose backoff has elapsed. Deliveries
+// claimed by a process that died mid-send are returned to pending first.
+func (notifier *Notifier) Flush(ctx context.Context) (FlushResult, error) {
+	result := FlushResult{}
+	if notifier == nil {
+		return result, nil
+	}
+	if err := notifier.recoverInflight(); err != nil {
+		return result, err
+	}
+	deliveries, err := readDeliveries(filepath.Join(notifier.outbox, pendingDir))
+	if err != nil {
+		return result, err
+	}
+	now := notifier.now()
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+		if delivery.NextAttemptAt.After(now) {
+			result.Pending++
+			continue
+		}
+		outcome, err := notifier.attempt(ctx, delivery.DeliveryID)
+		switch outcome {
+		case attemptDelivered:
+			result.Delivered++
+		case attemptRetrying:
+			result.Retrying++
+		case attemptFailed:
+			result.Failed++
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", delivery.DeliveryID, err))
+		}
+	}
+	return result, nil
+}
+
+func (notifier *Notifier) Pending() ([]Delivery, error) {
+	if notifier == nil {
+		return []Delivery{}, nil
+	}
+	return readDeliveries(filepath.Join(notifier.outbox, pendingDir))
+}
+
+type attemptOutcome int
+
+const (
+	attemptSkipped attemptOutcome = iota
+	attemptDelivered
+	attemptRetrying
+	attemptFailed
+)
+
+func (notifier *Notifier) attempt(ctx context.Context, deliveryID string) (attemptOutcome, error) {
+	pendingPath := filepath.Join(notifier.outbox, pendingDir, deliveryID+".json")
+	inflightPath := filepath.Join(notifier.outbox, inflightDir, deliveryID+".json")
+	if err := os.MkdirAll(filepath.Dir(inflightPath), 0o750); err != nil {
+		return attemptSkipped, fmt.Errorf("create notify outbox: %w", err)
+	}
+	// Claiming by rename keeps concurrent flushers from sending twice.
+	if err := os.Rename(pendingPath, inflightPath); err != nil {
+		return attemptSkipped, nil
+	}
+	delivery, err := readDelivery(inflightPath)
+	if err != nil {
+		return attemptSkipped, err
+	}
+	target := notifier.sinkByName(delivery.Sink)
+	var sendErr error
+	if target == nil {
+		sendErr = fmt.Errorf("notify sink %q is no longer configured", delivery.Sink)
+	} else {
+		sendErr = target.send(ctx, delivery)
+	}
+	now := notifier.now()
+	delivery.Attempts++
+	if sendErr == nil {
+		delivery.DeliveredAt = now
+		delivery.LastError = ""
+		encoded, err := json.Marshal(delivery)
+		if err != nil {
+			return attemptDelivered, fmt.Errorf("marshal notify delivery: %w", err)
+		}
+		if err := fsx.AppendLineLocked(filepath.Join(notifier.outbox, deliveredLog), encoded, 0o600); err != nil {
+			return attemptDelivered, fmt.Errorf("append notify delivery log: %w", err)
+		}
+		return attemptDelivered, removeIfExists(inflightPath)
+	}
+	delivery.LastError = sendErr.Error()
+	if target == nil || delivery.Attempts >= notifier.maxAttempts || !coreerrors.RetryableOf(sendErr) {
+		if err := writeDelivery(filepath.Join(notifier.outbox, failedDir), delivery); err != nil {
+			return attemptFailed, err
+		}
+		return attemptFailed, errors.Join(sendErr, removeIfExists(inflightPath))
+	}
+	delivery.NextAttemptAt = now.Add(notifier.backoff(delivery.Attempts))
+	if err := writeDelivery(filepath.Join(notifier.outbox, pendingDir), delivery); err != nil {
+		return attemptRetrying, err
+	}
+	return attemptRetrying, errors.Join(sendErr, removeIfExists(inflightPath))
+}
+
+func (notifier *Notifier) backoff(attempts int) time.Duration {
+	delay := notifier.initialBackoff
+	for index := 1; index < attempts; index++ {
+		delay *= 2
+		if delay >= notifier.maxBackoff {
+			return notifier.maxBackoff
+		}
+	}
+	return delay
+}
+
+func (notifier *Notifier) recoverInflight() error {
+	dir := filepath.Join(notifier.outbox, inflightDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("read notify inflight: %w", err)
+	}
+	now := notifier.now()
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) < inflightStaleAfter {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Join(notifier.outbox, pendingDir), 0o750); err != nil {
+			return fmt.Errorf("create notify outbox: %w", err)
+		}
+		if err := os.Rename(filepath.Join(dir, entry.Name()), filepath.Join(notifier.outbox, pendingDir, entry.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("recover notify delivery: %w", err)
+		}
+	}
+	return nil
+}
+
+func (notifier *Notifier) sinkByName(name string) sink {
+	for _, target := range notifier.sinks {
+		if target.name() == name {
+			return target
+		}
+	}
+	return nil
+}
+
+func normalizeEvent(event Event, now time.Time) Event {
+	event.SchemaID = eventSchemaID
+	event.SchemaVersion = eventSchemaVersion
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = now
+	}
+	event.CreatedAt = event.CreatedAt.UTC()
+	if strings.TrimSpace(event.ProducerVersion) == "" {
+		event.ProducerVersion = "0.0.0-dev"
+	}
+	event.Kind = strings.ToLower(strings.TrimSpace(event.Kind))
+	event.Source = strings.TrimSpace(event.Source)
+	event.Summary = strings.TrimSpace(event.Summary)
+	event.TraceID = strings.TrimSpace(event.TraceID)
+	event.JobID = strings.TrimSpace(event.JobID)
+	event.ToolName = strings.TrimSpace(event.ToolName)
+	event.Identity = strings.TrimSpace(event.Identity)
+	event.Actor = strings.TrimSpace(event.Actor)
+	event.ReasonCodes = uniqueSorted(event.ReasonCodes)
+	for key, value := range event.Details {
+		if strings.TrimSpace(value) == "" {
+			delete(event.Details, key)
+		}
+	}
+	if len(event.Details) == 0 {
+		event.Details = nil
+	}
+	if event.Summary == "" {
+		event.Summary = defaultSummary(event)
+	}
+	if strings.TrimSpace(event.EventID) == "" {
+		encoded, _ := json.Marshal(event)
+		event.EventID = digestParts(string(encoded))
+	}
+	return event
+}
+
+func defaultSummary(event Event) string {
+	subject := event.ToolName
+	if event.JobID != "" {
+		subject = "job " + event.JobID
+	}
+	if subject == "" {
+		subject = event.Source
+	}
+	return strings.TrimSpace(strings.ReplaceAll(event.Kind, "_", " ") + ": " + subject)
+}
+
+func writeDelivery(dir string, delivery Delivery) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("create notify outbox: %w", err)
+	}
+	encoded, err := json.MarshalIndent(delivery, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal notify delivery: %w", err)
+	}
+	encoded = append(encoded, '\n')
+	if err := fsx.WriteFileAtomic(filepath.Join(dir, delivery.DeliveryID+".json"), encoded, 0o600); err != nil {
+		return fmt.Errorf("write notify delivery: %w", err)
+	}
+	return nil
+}
+
+func readDelivery(path string) (Delivery, error) {
+	// #nosec G304 -- outbox paths are derived from operator configuration.
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		return Delivery{}, fmt.Errorf("read notify delivery: %w", err)
+	}
+	var delivery Delivery
+	if err := json.Unmarshal(payload, &delivery); err != nil {
+		return Delivery{}, fmt.Errorf("parse notify delivery %s: %w", filepath.Base(path), err)
+	}
+	return delivery, nil
+}
+
+func readDeliveries(dir string) ([]Delivery, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []Delivery{}, nil
+		}
+		return nil, fmt.Errorf("read notify outbox: %w", err)
+	}
+	deliveries := make([]Delivery, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		delivery, err := readDelivery(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].CreatedAt.Equal(deliveries[j].CreatedAt) {
+			return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+		}
+		return deliveries[i].DeliveryID < deliveries[j].DeliveryID
+	})
+	return deliveries, nil
+}
+
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("remove notify delivery: %w", err)
+	}
+	return nil
+}
+
+func parseDurationDefault(raw string, fallback time.Duration, field string) (time.Duration, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(trimmed)
+	if err != nil || parsed <= 0 {
+		return 0, invalidConfig(fmt.Errorf("notify %s must be a positive duration", field))
+	}
+	return parsed, nil
+}
+
+func invalidConfig(err error) error {
+	return coreerrors.Wrap(err, coreerrors.CategoryInvalidInput, "notify_config_invalid", "fix the notify section of the project config", false)
+}
+
+func digestParts(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:12])
+}
+
+func uniqueSorted(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	seen := map[string]struct{}{}
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		trimmed := strings.TrimSpace(value)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		out = append(out, trimmed)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,253 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type stubReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   [][]byte
+	headers  []http.Header
+}
+
+func (receiver *stubReceiver) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	body, _ := io.ReadAll(request.Body)
+	receiver.bodies = append(receiver.bodies, body)
+	receiver.headers = append(receiver.headers, request.Header.Clone())
+	status := http.StatusOK
+	if len(receiver.statuses) > 0 {
+		status = receiver.statuses[0]
+		receiver.statuses = receiver.statuses[1:]
+	}
+	writer.WriteHeader(status)
+}
+
+func (receiver *stubReceiver) count() int {
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	return len(receiver.bodies)
+}
+
+func TestWebhookDeliverySignedAndRetried(t *testing.T) {
+	t.Setenv("GAIT_TEST_NOTIFY_SECRET", "s3cret")
+	receiver := &stubReceiver{statuses: []int{http.StatusServiceUnavailable}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	outbox := filepath.Join(t.TempDir(), "outbox")
+	configuration := Config{
+		Outbox:         outbox,
+		InitialBackoff: "1m",
+		Sinks: []SinkConfig{{
+			Name:      "ops",
+			Type:      SinkTypeWebhook,
+			URL:       server.URL,
+			SecretEnv: "GAIT_TEST_NOTIFY_SECRET",
+			Events:    []string{EventApprovalRequired},
+		}},
+	}
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	notifier, err := New(configuration)
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+	notifier.now = func() time.Time { return now }
+
+	if err := notifier.Notify(context.Background(), Event{Kind: EventJobPaused, JobID: "job_1"}); err != nil {
+		t.Fatalf("notify unsubscribed kind: %v", err)
+	}
+	if err := notifier.Notify(context.Background(), Event{Kind: EventApprovalRequired, ToolName: "tool.write", ReasonCodes: []string{"approval_required"}}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if receiver.count() != 1 {
+		t.Fatalf("expected one inline attempt, got %d", receiver.count())
+	}
+	pending, err := notifier.Pending()
+	if err != nil || len(pending) != 1 || pending[0].Attempts != 1 || !pending[0].NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected one delivery waiting for backoff, got %#v err=%v", pending, err)
+	}
+
+	result, err := notifier.Flush(context.Background())
+	if err != nil || result.Pending != 1 || receiver.count() != 1 {
+		t.Fatalf("expected flush to respect backoff, got %#v err=%v", result, err)
+	}
+
+	// A fresh notifier over the same outbox picks up the delivery, as after a restart.
+	restarted, err := New(configuration)
+	if err != nil {
+		t.Fatalf("new notifier after restart: %v", err)
+	}
+	restarted.now = func() time.Time { return now.Add(2 * time.Minute) }
+	result, err = restarted.Flush(context.Background())
+	if err != nil || result.Delivered != 1 {
+		t.Fatalf("expected delivery after backoff, got %#v err=%v", result, err)
+	}
+	if pending, _ := restarted.Pending(); len(pending) != 0 {
+		t.Fatalf("expected empty outbox, got %#v", pending)
+	}
+
+	receiver.mu.Lock()
+	body := receiver.bodies[1]
+	header := receiver.headers[1]
+	receiver.mu.Unlock()
+	if header.Get(HeaderEvent) != EventApprovalRequired || header.Get(HeaderDelivery) == "" {
+		t.Fatalf("unexpected headers: %#v", header)
+	}
+	if err := VerifySignature([]byte("s3cret"), header.Get(HeaderTimestamp), header.Get(HeaderSignature), body, time.Now(), 5*time.Minute); err != nil {
+		t.Fatalf("verify signature: %v", err)
+	}
+	if err := VerifySignature([]byte("wrong"), header.Get(HeaderTimestamp), header.Get(HeaderSignature), body, time.Now(), 5*time.Minute); err == nil {
+		t.Fatalf("expected signature mismatch with wrong secret")
+	}
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatalf("decode webhook body: %v", err)
+	}
+	if event.SchemaID != eventSchemaID || event.ToolName != "tool.write" || event.EventID == "" || event.Summary == "" {
+		t.Fatalf("unexpected event body: %#v", event)
+	}
+}
+
+func TestEnqueueDefersDeliveryToFlush(t *testing.T) {
+	receiver := &stubReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	notifier, err := New(Config{Outbox: filepath.Join(t.TempDir(), "outbox"), Sinks: []SinkConfig{{Type: SinkTypeSlack, URL: server.URL}}})
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+	if err := notifier.Enqueue(Event{Kind: EventJobCancelled, JobID: "job_1"}); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+	if receiver.count() != 0 {
+		t.Fatalf("expected enqueue not to contact the sink, got %d calls", receiver.count())
+	}
+	if pending, err := notifier.Pending(); err != nil || len(pending) != 1 || pending[0].Attempts != 0 {
+		t.Fatalf("expected one untried delivery, got %#v err=%v", pending, err)
+	}
+	result, err := notifier.Flush(context.Background())
+	if err != nil || result.Delivered != 1 || receiver.count() != 1 {
+		t.Fatalf("expected flush to deliver, got %#v err=%v calls=%d", result, err, receiver.count())
+	}
+}
+
+func TestPermanentFailureMovesToFailed(t *testing.T) {
+	receiver := &stubReceiver{statuses: []int{http.StatusBadRequest}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	outbox := filepath.Join(t.TempDir(), "outbox")
+	notifier, err := New(Config{Outbox: outbox, Sinks: []SinkConfig{{Type: SinkTypeSlack, URL: server.URL}}})
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+	if err := notifier.Notify(context.Background(), Event{Kind: EventKillSwitchEngaged, Actor: "oncall", Details: map[string]string{"entry_id": "abc", "empty": ""}}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if pending, _ := notifier.Pending(); len(pending) != 0 {
+		t.Fatalf("expected permanent failure to leave pending, got %#v", pending)
+	}
+	failed, err := readDeliveries(filepath.Join(outbox, failedDir))
+	if err != nil || len(failed) != 1 || !strings.Contains(failed[0].LastError, "400") {
+		t.Fatalf("expected failed delivery, got %#v err=%v", failed, err)
+	}
+	var payload map[string]string
+	if err := json.Unmarshal(receiver.bodies[0], &payload); err != nil {
+		t.Fatalf("decode slack payload: %v", err)
+	}
+	text := payload["text"]
+	if !strings.Contains(text, "kill switch engaged") || !strings.Contains(text, "entry_id: `abc`") || strings.Contains(text, "empty") {
+		t.Fatalf("unexpected slack text: %q", text)
+	}
+}
+
+func TestRetriesStopAtMaxAttempts(t *testing.T) {
+	receiver := &stubReceiver{statuses: []int{500, 500, 500}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	outbox := filepath.Join(t.TempDir(), "outbox")
+	notifier, err := New(Config{Outbox: outbox, MaxAttempts: 2, InitialBackoff: "1s", MaxBackoff: "1s", Sinks: []SinkConfig{{Type: SinkTypeSlack, URL: server.URL}}})
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	notifier.now = func() time.Time { return now }
+	if err := notifier.Notify(context.Background(), Event{Kind: EventJobEmergencyStopped, JobID: "job_1"}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	notifier.now = func() time.Time { return now.Add(time.Hour) }
+	result, err := notifier.Flush(context.Background())
+	if err != nil || result.Failed != 1 || receiver.count() != 2 {
+		t.Fatalf("expected failure after max attempts, got %#v err=%v calls=%d", result, err, receiver.count())
+	}
+}
+
+func TestEmailSink(t *testing.T) {
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotMessage []byte
+	original := sendMail
+	sendMail = func(addr string, _ smtp.Auth, from string, to []string, message []byte) error {
+		gotAddr, gotFrom, gotTo, gotMessage = addr, from, to, message
+		return nil
+	}
+	defer func() { sendMail = original }()
+
+	notifier, err := New(Config{
+		Outbox: filepath.Join(t.TempDir(), "outbox"),
+		Sinks: []SinkConfig{{
+			Type:     SinkTypeEmail,
+			SMTPAddr: "127.0.0.1:2525",
+			From:     "gait@example.test",
+			To:       []string{"oncall@example.test"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+	if err := notifier.Notify(context.Background(), Event{Kind: EventJobApproved, JobID: "job_7", Actor: "alice"}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if gotAddr != "127.0.0.1:2525" || gotFrom != "gait@example.test" || len(gotTo) != 1 {
+		t.Fatalf("unexpected smtp envelope: %s %s %v", gotAddr, gotFrom, gotTo)
+	}
+	if !strings.Contains(string(gotMessage), "Subject: [gait] job approved: job job_7") {
+		t.Fatalf("unexpected email message: %s", gotMessage)
+	}
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	cases := []SinkConfig{
+		{Type: SinkTypeWebhook, URL: "https://example.test/hook"},
+		{Type: SinkTypeSlack, URL: "ftp://example.test"},
+		{Type: SinkTypeEmail, SMTPAddr: "smtp.example.test", From: "a@example.test", To: []string{"b@example.test"}},
+		{Type: "pager"},
+		{Type: SinkTypeSlack, URLEnv: "GAIT_TEST_NOTIFY_UNSET_URL"},
+	}
+	for index, sinkConfig := range cases {
+		t.Run(fmt.Sprintf("case_%d", index), func(t *testing.T) {
+			if _, err := New(Config{Sinks: []SinkConfig{sinkConfig}}); err == nil {
+				t.Fatalf("expected config error for %#v", sinkConfig)
+			}
+		})
+	}
+	if _, err := New(Config{Sinks: []SinkConfig{{Type: SinkTypeSlack, URL: "https://a.test"}, {Type: SinkTypeSlack, URL: "https://b.test"}}}); err == nil {
+		t.Fatalf("expected duplicate sink name error")
+	}
+}
@@ -0,0 +1,317 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	coreerrors "github.com/Clyra-AI/gait/core/errors"
+)
+
+const (
+	HeaderEvent     = "X-Gait-Event"
+	HeaderDelivery  = "X-Gait-Delivery"
+	HeaderTimestamp = "X-Gait-Timestamp"
+	HeaderSignature = "X-Gait-Signature"
+
+	signatureVersion = "v1="
+	maxResponseBody  = 64 << 10
+)
+
+var sendMail = smtp.SendMail
+
+type sink interface {
+	name() string
+	accepts(kind string) bool
+	send(ctx context.Context, delivery Delivery) error
+}
+
+type sinkBase struct {
+	sinkName string
+	events   map[string]struct{}
+	secret   []byte
+}
+
+type httpSink struct {
+	sinkBase
+	kind     string
+	endpoint string
+	client   *http.Client
+}
+
+type emailSink struct {
+	sinkBase
+	addr     string
+	from     string
+	to       []string
+	username string
+	password string
+}
+
+func newSink(configuration SinkConfig, timeout time.Duration) (sink, error) {
+	kind := strings.ToLower(strings.TrimSpace(configuration.Type))
+	base := sinkBase{sinkName: strings.TrimSpace(configuration.Name)}
+	if base.sinkName == "" {
+		base.sinkName = kind
+	}
+	if base.sinkName == "" {
+		return nil, fmt.Errorf("name or type is required")
+	}
+	if len(configuration.Events) > 0 {
+		base.events = map[string]struct{}{}
+		for _, event := range configuration.Events {
+			if trimmed := strings.ToLower(strings.TrimSpace(event)); trimmed != "" {
+				base.events[trimmed] = struct{}{}
+			}
+		}
+	}
+	if envName := strings.TrimSpace(configuration.SecretEnv); envName != "" {
+		secret := strings.TrimSpace(os.Getenv(envName))
+		if secret == "" {
+			return nil, fmt.Errorf("secret env %s is empty", envName)
+		}
+		base.secret = []byte(secret)
+	}
+
+	switch kind {
+	case SinkTypeWebhook, SinkTypeSlack:
+		endpoint, err := resolveValue(configuration.URL, configuration.URLEnv, "url")
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := url.Parse(endpoint)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("url must be an absolute http(s) url")
+		}
+		if kind == SinkTypeWebhook && len(base.secret) == 0 {
+			return nil, fmt.Errorf("webhook sinks require secret_env for HMAC signing")
+		}
+		return &httpSink{
+			sinkBase: base,
+			kind:     kind,
+			endpoint: endpoint,
+			client:   &http.Client{Timeout: timeout},
+		}, nil
+	case SinkTypeEmail:
+		addr := strings.TrimSpace(configuration.SMTPAddr)
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return nil, fmt.Errorf("smtp_addr must be host:port")
+		}
+		from := strings.TrimSpace(configuration.From)
+		to := uniqueSorted(configuration.To)
+		if from == "" || len(to) == 0 {
+			return nil, fmt.Errorf("email sinks require from and to")
+		}
+		target := &emailSink{sinkBase: base, addr: addr, from: from, to: to}
+		if envName := strings.TrimSpace(configuration.UsernameEnv); envName != "" {
+			target.username = os.Getenv(envName)
+		}
+		if envName := strings.TrimSpace(configuration.PasswordEnv); envName != "" {
+			target.password = os.Getenv(envName)
+		}
+		return target, nil
+	default:
+		return nil, fmt.Errorf("unsupported sink type %q (expected webhook, slack, or email)", configuration.Type)
+	}
+}
+
+func (base sinkBase) name() string {
+	return base.sinkName
+}
+
+func (base sinkBase) accepts(kind string) bool {
+	if len(base.events) == 0 {
+		return true
+	}
+	_, ok := base.events[kind]
+	return ok
+}
+
+func (target *httpSink) send(ctx context.Context, delivery Delivery) error {
+	var body []byte
+	var err error
+	if target.kind == SinkTypeSlack {
+		body, err = json.Marshal(map[string]string{"text": formatText(delivery.Event)})
+	} else {
+		body, err = json.Marshal(delivery.Event)
+	}
+	if err != nil {
+		return fmt.Errorf("encode notification: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, target.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build notification request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderEvent, delivery.Event.Kind)
+	request.Header.Set(HeaderDelivery, delivery.DeliveryID)
+	if len(target.secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().UTC().Unix(), 10)
+		request.Header.Set(HeaderTimestamp, timestamp)
+		request.Header.Set(HeaderSignature, Sign(target.secret, timestamp, body))
+	}
+	// #nosec G107 -- sink url is explicit operator configuration.
+	response, err := target.client.Do(request)
+	if err != nil {
+		return coreerrors.Wrap(
+			fmt.Errorf("send notification to %s: %w", target.sinkName, err),
+			coreerrors.CategoryNetworkTransient,
+			"notify_sink_unavailable",
+			"check that the notification endpoint is reachable",
+			true,
+		)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxResponseBody))
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+	retryable := response.StatusCode == http.StatusRequestTimeout || response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500
+	category := coreerrors.CategoryNetworkPermanent
+	if retryable {
+		category = coreerrors.CategoryNetworkTransient
+	}
+	return coreerrors.Wrap(
+		fmt.Errorf("notification sink %s returned %d", target.sinkName, response.StatusCode),
+		category,
+		"notify_sink_rejected",
+		"check the sink url and receiver configuration",
+		retryable,
+	)
+}
+
+func (target *emailSink) send(_ context.Context, delivery Delivery) error {
+	event := delivery.Event
+	encoded, err := json.MarshalIndent(event, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode notification: %w", err)
+	}
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", target.from)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(target.to, ", "))
+	fmt.Fprintf(&message, "Subject: [gait] %s\r\n", sanitizeHeader(event.Summary))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().UTC().Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "%s: %s\r\n", HeaderEvent, event.Kind)
+	fmt.Fprintf(&message, "%s: %s\r\n", HeaderDelivery, delivery.DeliveryID)
+	if len(target.secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().UTC().Unix(), 10)
+		fmt.Fprintf(&message, "%s: %s\r\n", HeaderTimestamp, timestamp)
+		fmt.Fprintf(&message, "%s: %s\r\n", HeaderSignature, Sign(target.secret, timestamp, encoded))
+	}
+	message.WriteString("Content-Type: application/json; charset=utf-8\r\n\r\n")
+	message.Write(encoded)
+	message.WriteString("\r\n")
+
+	var auth smtp.Auth
+	if target.username != "" {
+		host, _, _ := net.SplitHostPort(target.addr)
+		auth = smtp.PlainAuth("", target.username, target.password, host)
+	}
+	if err := sendMail(target.addr, auth, target.from, target.to, message.Bytes()); err != nil {
+		return coreerrors.Wrap(
+			fmt.Errorf("send notification email via %s: %w", target.sinkName, err),
+			coreerrors.CategoryNetworkTransient,
+			"notify_sink_unavailable",
+			"check smtp_addr and smtp credentials",
+			true,
+		)
+	}
+	return nil
+}
+
+// Sign returns the X-Gait-Signature value for body: an HMAC-SHA256 over the
+// timestamp and body joined by ".".
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(timestamp))
+	_, _ = mac.Write([]byte("."))
+	_, _ = mac.Write(body)
+	return signatureVersion + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature authenticates a received notification. A positive
+// tolerance rejects timestamps further than tolerance from now.
+func VerifySignature(secret []byte, timestamp string, signature string, body []byte, now time.Time, tolerance time.Duration) error {
+	if len(secret) == 0 {
+		return fmt.Errorf("notification secret is required")
+	}
+	seconds, err := strconv.ParseInt(strings.TrimSpace(timestamp), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid notification timestamp")
+	}
+	if tolerance > 0 {
+		skew := now.Sub(time.Unix(seconds, 0))
+		if skew < 0 {
+			skew = -skew
+		}
+		if skew > tolerance {
+			return fmt.Errorf("notification timestamp outside tolerance")
+		}
+	}
+	expected := Sign(secret, strings.TrimSpace(timestamp), body)
+	if !hmac.Equal([]byte(expected), []byte(strings.TrimSpace(signature))) {
+		return fmt.Errorf("notification signature mismatch")
+	}
+	return nil
+}
+
+func formatText(event Event) string {
+	lines := []string{"*gait* " + event.Summary}
+	fields := []struct {
+		label string
+		value string
+	}{
+		{"tool", event.ToolName},
+		{"job", event.JobID},
+		{"identity", event.Identity},
+		{"actor", event.Actor},
+		{"trace", event.TraceID},
+		{"source", event.Source},
+		{"reasons", strings.Join(event.ReasonCodes, ", ")},
+	}
+	for _, field := range fields {
+		if field.value != "" {
+			lines = append(lines, fmt.Sprintf("%s: `%s`", field.label, field.value))
+		}
+	}
+	keys := make([]string, 0, len(event.Details))
+	for key := range event.Details {
+		keys = append(keys, key)
+	}
+	for _, key := range uniqueSorted(keys) {
+		lines = append(lines, fmt.Sprintf("%s: `%s`", key, event.Details[key]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func resolveValue(value string, envName string, field string) (string, error) {
+	if trimmed := strings.TrimSpace(value); trimmed != "" {
+		return trimmed, nil
+	}
+	if trimmedEnv := strings.TrimSpace(envName); trimmedEnv != "" {
+		if resolved := strings.TrimSpace(os.Getenv(trimmedEnv)); resolved != "" {
+			return resolved, nil
+		}
+		return "", fmt.Errorf("%s env %s is empty", field, trimmedEnv)
+	}
+	return "", fmt.Errorf("%s is required", field)
+}
+
+func sanitizeHeader(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
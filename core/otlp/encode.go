@@ -0,0 +1,302 @@
+package otlp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	instrumentationScope = "gait.otlp"
+
+	SpanName           = "gait.gate.evaluate"
+	LogBody            = "gait.gate.decision"
+	MetricDecisions    = "gait.gate.decisions"
+	MetricDecisionTime = "gait.gate.decision.duration"
+
+	spanKindInternal            = 1
+	severityNumberInfo          = 9
+	aggregationTemporalityDelta = 1
+)
+
+// latencyBoundsMS are the explicit histogram bucket bounds for decision latency.
+var latencyBoundsMS = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string     `json:"stringValue,omitempty"`
+	DoubleValue *float64    `json:"doubleValue,omitempty"`
+	ArrayValue  *arrayValue `json:"arrayValue,omitempty"`
+}
+
+type arrayValue struct {
+	Values []anyValue `json:"values"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+func (exporter *Exporter) encode(signal string, batch []Decision) ([]byte, error) {
+	var payload any
+	switch signal {
+	case SignalTraces:
+		payload = exporter.tracesPayload(batch)
+	case SignalMetrics:
+		payload = exporter.metricsPayload(batch)
+	case SignalLogs:
+		payload = exporter.logsPayload(batch)
+	default:
+		return nil, invalidConfig(fmt.Errorf("unsupported otel signal %q", signal))
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode otlp %s: %w", signal, err)
+	}
+	return encoded, nil
+}
+
+func (exporter *Exporter) resource() resource {
+	attributes := []keyValue{
+		stringAttribute("service.name", exporter.serviceName),
+		stringAttribute("telemetry.sdk.name", "gait"),
+		stringAttribute("telemetry.sdk.language", "go"),
+	}
+	if exporter.serviceVersion != "" {
+		attributes = append(attributes, stringAttribute("service.version", exporter.serviceVersion))
+	}
+	return resource{Attributes: attributes}
+}
+
+func (exporter *Exporter) scope() scope {
+	return scope{Name: instrumentationScope, Version: exporter.serviceVersion}
+}
+
+func (exporter *Exporter) tracesPayload(batch []Decision) map[string]any {
+	spans := make([]map[string]any, 0, len(batch))
+	for _, decision := range batch {
+		traceID, spanID := decisionIDs(decision)
+		spans = append(spans, map[string]any{
+			"traceId":           traceID,
+			"spanId":            spanID,
+			"name":              SpanName,
+			"kind":              spanKindInternal,
+			"startTimeUnixNano": unixNano(decision.StartedAt),
+			"endTimeUnixNano":   unixNano(decision.StartedAt.Add(decision.Latency)),
+			"attributes":        decisionAttributes(decision),
+			"status":            map[string]any{},
+		})
+	}
+	return map[string]any{"resourceSpans": []map[string]any{{
+		"resource":   exporter.resource(),
+		"scopeSpans": []map[string]any{{"scope": exporter.scope(), "spans": spans}},
+	}}}
+}
+
+func (exporter *Exporter) logsPayload(batch []Decision) map[string]any {
+	records := make([]map[string]any, 0, len(batch))
+	for _, decision := range batch {
+		traceID, spanID := decisionIDs(decision)
+		timestamp := unixNano(decision.StartedAt.Add(decision.Latency))
+		records = append(records, map[string]any{
+			"timeUnixNano":         timestamp,
+			"observedTimeUnixNano": timestamp,
+			"severityNumber":       severityNumberInfo,
+			"severityText":         "INFO",
+			"body":                 stringValue(LogBody),
+			"attributes":           decisionAttributes(decision),
+			"traceId":              traceID,
+			"spanId":               spanID,
+		})
+	}
+	return map[string]any{"resourceLogs": []map[string]any{{
+		"resource":  exporter.resource(),
+		"scopeLogs": []map[string]any{{"scope": exporter.scope(), "logRecords": records}},
+	}}}
+}
+
+type metricSeries struct {
+	source    string
+	verdict   string
+	count     int64
+	sumMS     float64
+	minMS     float64
+	maxMS     float64
+	buckets   []int64
+	startTime time.Time
+	endTime   time.Time
+}
+
+// metricsPayload reports each batch as delta points keyed by source and
+// verdict, so spooled batches replayed later still add up at the collector.
+func (exporter *Exporter) metricsPayload(batch []Decision) map[string]any {
+	series := map[string]*metricSeries{}
+	for _, decision := range batch {
+		key := decision.Source + "\x00" + decision.Verdict
+		entry, ok := series[key]
+		latencyMS := float64(decision.Latency) / float64(time.Millisecond)
+		end := decision.StartedAt.Add(decision.Latency)
+		if !ok {
+			entry = &metricSeries{
+				source:    decision.Source,
+				verdict:   decision.Verdict,
+				minMS:     latencyMS,
+				maxMS:     latencyMS,
+				buckets:   make([]int64, len(latencyBoundsMS)+1),
+				startTime: decision.StartedAt,
+				endTime:   end,
+			}
+			series[key] = entry
+		}
+		entry.count++
+		entry.sumMS += latencyMS
+		entry.minMS = min(entry.minMS, latencyMS)
+		entry.maxMS = max(entry.maxMS, latencyMS)
+		entry.buckets[sort.SearchFloat64s(latencyBoundsMS, latencyMS)]++
+		if decision.StartedAt.Before(entry.startTime) {
+			entry.startTime = decision.StartedAt
+		}
+		if end.After(entry.endTime) {
+			entry.endTime = end
+		}
+	}
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	countPoints := make([]map[string]any, 0, len(keys))
+	latencyPoints := make([]map[string]any, 0, len(keys))
+	for _, key := range keys {
+		entry := series[key]
+		attributes := []keyValue{stringAttribute("gait.source", entry.source), stringAttribute("gait.verdict", entry.verdict)}
+		countPoints = append(countPoints, map[string]any{
+			"attributes":        attributes,
+			"startTimeUnixNano": unixNano(entry.startTime),
+			"timeUnixNano":      unixNano(entry.endTime),
+			"asInt":             strconv.FormatInt(entry.count, 10),
+		})
+		bucketCounts := make([]string, len(entry.buckets))
+		for index, count := range entry.buckets {
+			bucketCounts[index] = strconv.FormatInt(count, 10)
+		}
+		latencyPoints = append(latencyPoints, map[string]any{
+			"attributes":        attributes,
+			"startTimeUnixNano": unixNano(entry.startTime),
+			"timeUnixNano":      unixNano(entry.endTime),
+			"count":             strconv.FormatInt(entry.count, 10),
+			"sum":               entry.sumMS,
+			"min":               entry.minMS,
+			"max":               entry.maxMS,
+			"bucketCounts":      bucketCounts,
+			"explicitBounds":    latencyBoundsMS,
+		})
+	}
+	metrics := []map[string]any{
+		{
+			"name":        MetricDecisions,
+			"description": "Gate decisions by source and verdict.",
+			"unit":        "{decision}",
+			"sum": map[string]any{
+				"dataPoints":             countPoints,
+				"aggregationTemporality": aggregationTemporalityDelta,
+				"isMonotonic":            true,
+			},
+		},
+		{
+			"name":        MetricDecisionTime,
+			"description": "Gate decision latency by source and verdict.",
+			"unit":        "ms",
+			"histogram": map[string]any{
+				"dataPoints":             latencyPoints,
+				"aggregationTemporality": aggregationTemporalityDelta,
+			},
+		},
+	}
+	return map[string]any{"resourceMetrics": []map[string]any{{
+		"resource":     exporter.resource(),
+		"scopeMetrics": []map[string]any{{"scope": exporter.scope(), "metrics": metrics}},
+	}}}
+}
+
+func decisionAttributes(decision Decision) []keyValue {
+	attributes := []keyValue{
+		stringAttribute("gait.source", decision.Source),
+		stringAttribute("gait.tool_name", decision.ToolName),
+		stringAttribute("gait.verdict", decision.Verdict),
+		{Key: "gait.decision_latency_ms", Value: doubleValue(float64(decision.Latency) / float64(time.Millisecond))},
+	}
+	optional := []struct{ key, value string }{
+		{"gait.policy_digest", decision.PolicyDigest},
+		{"gait.policy_id", decision.PolicyID},
+		{"gait.policy_version", decision.PolicyVersion},
+		{"gait.matched_rule", strings.Join(decision.MatchedRuleIDs, ",")},
+		{"gait.intent_digest", decision.IntentDigest},
+		{"gait.trace_id", decision.TraceID},
+		{"gait.run_id", decision.RunID},
+		{"gait.session_id", decision.SessionID},
+		{"gait.job_id", decision.JobID},
+	}
+	for _, entry := range optional {
+		if strings.TrimSpace(entry.value) != "" {
+			attributes = append(attributes, stringAttribute(entry.key, entry.value))
+		}
+	}
+	if len(decision.MatchedRuleIDs) > 0 {
+		attributes = append(attributes, keyValue{Key: "gait.matched_rule_ids", Value: stringArray(decision.MatchedRuleIDs)})
+	}
+	if len(decision.ReasonCodes) > 0 {
+		attributes = append(attributes, keyValue{Key: "gait.reason_codes", Value: stringArray(decision.ReasonCodes)})
+	}
+	return attributes
+}
+
+// decisionIDs derives stable OTLP trace and span ids from the gait trace id,
+// so re-exporting a spooled decision produces the same span.
+func decisionIDs(decision Decision) (string, string) {
+	seed := decision.TraceID
+	if strings.TrimSpace(seed) == "" {
+		seed = strings.Join([]string{decision.IntentDigest, decision.ToolName, decision.StartedAt.Format(time.RFC3339Nano)}, "|")
+	}
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:16]), hex.EncodeToString(sum[16:24])
+}
+
+func stringAttribute(key string, value string) keyValue {
+	return keyValue{Key: key, Value: stringValue(value)}
+}
+
+func stringValue(value string) anyValue {
+	return anyValue{StringValue: &value}
+}
+
+func doubleValue(value float64) anyValue {
+	return anyValue{DoubleValue: &value}
+}
+
+func stringArray(values []string) anyValue {
+	array := &arrayValue{Values: make([]anyValue, 0, len(values))}
+	for _, value := range values {
+		array.Values = append(array.Values, stringValue(value))
+	}
+	return anyValue{ArrayValue: array}
+}
+
+func unixNano(value time.Time) string {
+	return strconv.FormatInt(value.UnixNano(), 10)
+}
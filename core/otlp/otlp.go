@@ -0,0 +1,501 @@
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	coreerrors "github.com/Clyra-AI/gait/core/errors"
+)
+
+const (
+	DefaultSpoolDir = "./.gait-out/otlp_spool"
+
+	defaultServiceName    = "gait"
+	defaultTimeout        = 5 * time.Second
+	defaultFlushInterval  = 5 * time.Second
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+	defaultBatchSize      = 256
+	defaultQueueSize      = 4096
+	defaultMaxAttempts    = 4
+	defaultMaxSpoolFiles  = 1000
+	maxResponseBytes      = 64 << 10
+)
+
+const (
+	SignalTraces  = "traces"
+	SignalMetrics = "metrics"
+	SignalLogs    = "logs"
+)
+
+var allSignals = []string{SignalTraces, SignalMetrics, SignalLogs}
+
+type Config struct {
+	// Endpoint is the OTLP/HTTP base URL, e.g. http://127.0.0.1:4318; the
+	// exporter posts to /v1/traces, /v1/metrics and /v1/logs under it.
+	Endpoint    string            `yaml:"endpoint"`
+	EndpointEnv string            `yaml:"endpoint_env"`
+	Headers     map[string]string `yaml:"headers"`
+	// HeadersEnv maps a header name to the env var holding its value.
+	HeadersEnv     map[string]string `yaml:"headers_env"`
+	ServiceName    string            `yaml:"service_name"`
+	Signals        []string          `yaml:"signals"`
+	Timeout        string            `yaml:"timeout"`
+	FlushInterval  string            `yaml:"flush_interval"`
+	InitialBackoff string            `yaml:"initial_backoff"`
+	MaxBackoff     string            `yaml:"max_backoff"`
+	BatchSize      int               `yaml:"batch_size"`
+	QueueSize      int               `yaml:"queue_size"`
+	MaxAttempts    int               `yaml:"max_attempts"`
+	Spool          string            `yaml:"spool"`
+	MaxSpoolFiles  int               `yaml:"max_spool_files"`
+}
+
+// Decision is one gate evaluation as exported to OTLP.
+type Decision struct {
+	Source         string        `json:"source"`
+	StartedAt      time.Time     `json:"started_at"`
+	Latency        time.Duration `json:"latency_ns"`
+	ToolName       string        `json:"tool_name"`
+	Verdict        string        `json:"verdict"`
+	ReasonCodes    []string      `json:"reason_codes,omitempty"`
+	PolicyDigest   string        `json:"policy_digest,omitempty"`
+	PolicyID       string        `json:"policy_id,omitempty"`
+	PolicyVersion  string        `json:"policy_version,omitempty"`
+	MatchedRuleIDs []string      `json:"matched_rule_ids,omitempty"`
+	IntentDigest   string        `json:"intent_digest,omitempty"`
+	TraceID        string        `json:"trace_id,omitempty"`
+	RunID          string        `json:"run_id,omitempty"`
+	SessionID      string        `json:"session_id,omitempty"`
+	JobID          string        `json:"job_id,omitempty"`
+}
+
+type FlushResult struct {
+	Exported int      `json:"exported"`
+	Replayed int      `json:"replayed"`
+	Spooled  int      `json:"spooled"`
+	Dropped  int      `json:"dropped"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// Exporter batches decisions in a bounded in-memory queue and exports them as
+// OTLP/JSON spans, metrics and logs. Batches that cannot be delivered after
+// retries are written to the spool and replayed by a later Flush. One-shot
+// commands call Spool instead, so gate evaluation never waits on a collector.
+type Exporter struct {
+	endpoint       string
+	headers        map[string]string
+	serviceName    string
+	serviceVersion string
+	signals        []string
+	client         *http.Client
+	flushInterval  time.Duration
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	batchSize      int
+	queueSize      int
+	maxAttempts    int
+	spool          string
+	maxSpoolFiles  int
+	now            func() time.Time
+
+	mu      sync.Mutex
+	queue   []Decision
+	dropped int
+	wake    chan struct{}
+	flushMu sync.Mutex
+}
+
+func (configuration Config) Enabled() bool {
+	return strings.TrimSpace(configuration.Endpoint) != "" || strings.TrimSpace(configuration.EndpointEnv) != ""
+}
+
+func New(configuration Config, serviceVersion string) (*Exporter, error) {
+	endpoint := strings.TrimSpace(configuration.Endpoint)
+	if envName := strings.TrimSpace(configuration.EndpointEnv); envName != "" {
+		endpoint = strings.TrimSpace(os.Getenv(envName))
+		if endpoint == "" {
+			return nil, invalidConfig(fmt.Errorf("otel endpoint_env %s is empty", envName))
+		}
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, invalidConfig(fmt.Errorf("otel endpoint must be an absolute http(s) url"))
+	}
+	exporter := &Exporter{
+		endpoint:       strings.TrimRight(parsed.String(), "/"),
+		headers:        map[string]string{},
+		serviceName:    strings.TrimSpace(configuration.ServiceName),
+		serviceVersion: strings.TrimSpace(serviceVersion),
+		batchSize:      configuration.BatchSize,
+		queueSize:      configuration.QueueSize,
+		maxAttempts:    configuration.MaxAttempts,
+		spool:          strings.TrimSpace(configuration.Spool),
+		maxSpoolFiles:  configuration.MaxSpoolFiles,
+		now:            func() time.Time { return time.Now().UTC() },
+		wake:           make(chan struct{}, 1),
+	}
+	if exporter.serviceName == "" {
+		exporter.serviceName = defaultServiceName
+	}
+	if exporter.batchSize <= 0 {
+		exporter.batchSize = defaultBatchSize
+	}
+	if exporter.queueSize <= 0 {
+		exporter.queueSize = defaultQueueSize
+	}
+	if exporter.maxAttempts <= 0 {
+		exporter.maxAttempts = defaultMaxAttempts
+	}
+	if exporter.spool == "" {
+		exporter.spool = DefaultSpoolDir
+	}
+	if exporter.maxSpoolFiles <= 0 {
+		exporter.maxSpoolFiles = defaultMaxSpoolFiles
+	}
+	timeout, err := parseDurationDefault(configuration.Timeout, defaultTimeout, "timeout")
+	if err != nil {
+		return nil, err
+	}
+	exporter.client = &http.Client{Timeout: timeout}
+	if exporter.flushInterval, err = parseDurationDefault(configuration.FlushInterval, defaultFlushInterval, "flush_interval"); err != nil {
+		return nil, err
+	}
+	if exporter.initialBackoff, err = parseDurationDefault(configuration.InitialBackoff, defaultInitialBackoff, "initial_backoff"); err != nil {
+		return nil, err
+	}
+	if exporter.maxBackoff, err = parseDurationDefault(configuration.MaxBackoff, defaultMaxBackoff, "max_backoff"); err != nil {
+		return nil, err
+	}
+	if exporter.signals, err = normalizeSignals(configuration.Signals); err != nil {
+		return nil, err
+	}
+	for name, value := range configuration.Headers {
+		if strings.TrimSpace(name) != "" {
+			exporter.headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+	for name, envName := range configuration.HeadersEnv {
+		value := strings.TrimSpace(os.Getenv(strings.TrimSpace(envName)))
+		if value == "" {
+			return nil, invalidConfig(fmt.Errorf("otel headers_env %s for header %s is empty", envName, name))
+		}
+		exporter.headers[strings.TrimSpace(name)] = value
+	}
+	return exporter, nil
+}
+
+func (exporter *Exporter) SpoolDir() string {
+	if exporter == nil {
+		return ""
+	}
+	return exporter.spool
+}
+
+// Record queues a decision without blocking. When the queue is full the
+// decision is dropped and counted in the next FlushResult.
+func (exporter *Exporter) Record(decision Decision) bool {
+	if exporter == nil {
+		return false
+	}
+	exporter.mu.Lock()
+	if len(exporter.queue) >= exporter.queueSize {
+		exporter.dropped++
+		exporter.mu.Unlock()
+		return false
+	}
+	exporter.queue = append(exporter.queue, normalizeDecision(decision, exporter.now()))
+	full := len(exporter.queue) >= exporter.batchSize
+	exporter.mu.Unlock()
+	if full {
+		select {
+		case exporter.wake <- struct{}{}:
+		default:
+		}
+	}
+	return true
+}
+
+// Run flushes every flush interval, or sooner when a batch fills, until ctx
+// is done. Callers should Close the exporter afterwards.
+func (exporter *Exporter) Run(ctx context.Context) {
+	if exporter == nil {
+		return
+	}
+	ticker := time.NewTicker(exporter.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-exporter.wake:
+		}
+		_, _ = exporter.Flush(ctx)
+	}
+}
+
+// Flush replays spooled batches, then exports the queue in batches. Once an
+// export fails the rest of the queue is spooled instead of retried.
+func (exporter *Exporter) Flush(ctx context.Context) (FlushResult, error) {
+	result := FlushResult{}
+	if exporter == nil {
+		return result, nil
+	}
+	exporter.flushMu.Lock()
+	defer exporter.flushMu.Unlock()
+
+	offline := false
+	if err := exporter.recoverInflight(); err != nil {
+		return result, err
+	}
+	spooled, err := exporter.pendingSpoolFiles()
+	if err != nil {
+		return result, err
+	}
+	for _, name := range spooled {
+		if ctx.Err() != nil {
+			offline = true
+			break
+		}
+		replayed, replayErr := exporter.replay(ctx, name)
+		result.Replayed += replayed
+		if replayErr != nil {
+			result.Errors = append(result.Errors, replayErr.Error())
+			if coreerrors.RetryableOf(replayErr) {
+				offline = true
+				break
+			}
+		}
+	}
+
+	for {
+		exporter.mu.Lock()
+		count := min(len(exporter.queue), exporter.batchSize)
+		batch := append([]Decision(nil), exporter.queue[:count]...)
+		exporter.queue = exporter.queue[count:]
+		result.Dropped += exporter.dropped
+		exporter.dropped = 0
+		exporter.mu.Unlock()
+		if len(batch) == 0 {
+			break
+		}
+		if offline || ctx.Err() != nil {
+			offline = true
+			if err := exporter.writeSpool(spoolBatch{Signals: exporter.signals, Decisions: batch}); err != nil {
+				result.Dropped += len(batch)
+				result.Errors = append(result.Errors, err.Error())
+				continue
+			}
+			result.Spooled += len(batch)
+			continue
+		}
+		remaining, exportErr := exporter.exportBatch(ctx, exporter.signals, batch)
+		if exportErr == nil {
+			result.Exported += len(batch)
+			continue
+		}
+		result.Errors = append(result.Errors, exportErr.Error())
+		if !coreerrors.RetryableOf(exportErr) {
+			result.Dropped += len(batch)
+			continue
+		}
+		offline = true
+		if err := exporter.writeSpool(spoolBatch{Signals: remaining, Decisions: batch}); err != nil {
+			result.Dropped += len(batch)
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.Spooled += len(batch)
+	}
+	return result, nil
+}
+
+// Spool writes the queue to the spool without contacting the collector, for
+// one-shot commands that should not wait on the network. A later Flush or Run
+// delivers the batches.
+func (exporter *Exporter) Spool() (FlushResult, error) {
+	result := FlushResult{}
+	if exporter == nil {
+		return result, nil
+	}
+	exporter.flushMu.Lock()
+	defer exporter.flushMu.Unlock()
+	for {
+		exporter.mu.Lock()
+		count := min(len(exporter.queue), exporter.batchSize)
+		batch := append([]Decision(nil), exporter.queue[:count]...)
+		exporter.queue = exporter.queue[count:]
+		result.Dropped += exporter.dropped
+		exporter.dropped = 0
+		exporter.mu.Unlock()
+		if len(batch) == 0 {
+			return result, nil
+		}
+		if err := exporter.writeSpool(spoolBatch{Signals: exporter.signals, Decisions: batch}); err != nil {
+			result.Dropped += len(batch)
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		result.Spooled += len(batch)
+	}
+}
+
+// Close flushes everything queued; undelivered batches end up in the spool.
+func (exporter *Exporter) Close(ctx context.Context) (FlushResult, error) {
+	return exporter.Flush(ctx)
+}
+
+// exportBatch sends each signal in turn and returns the signals still
+// outstanding when one fails, so a spooled batch never re-sends a signal the
+// collector already accepted.
+func (exporter *Exporter) exportBatch(ctx context.Context, signals []string, batch []Decision) ([]string, error) {
+	for index, signal := range signals {
+		payload, err := exporter.encode(signal, batch)
+		if err != nil {
+			return nil, err
+		}
+		if err := exporter.post(ctx, signal, payload); err != nil {
+			return append([]string(nil), signals[index:]...), err
+		}
+	}
+	return nil, nil
+}
+
+func (exporter *Exporter) post(ctx context.Context, signal string, payload []byte) error {
+	var lastErr error
+	for attempt := 1; attempt <= exporter.maxAttempts; attempt++ {
+		retryAfter, err := exporter.send(ctx, signal, payload)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !coreerrors.RetryableOf(err) || attempt == exporter.maxAttempts {
+			break
+		}
+		delay := exporter.backoff(attempt)
+		if retryAfter > 0 {
+			delay = min(retryAfter, exporter.maxBackoff)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return transientError(fmt.Errorf("export otlp %s: %w", signal, ctx.Err()))
+		case <-timer.C:
+		}
+	}
+	return lastErr
+}
+
+func (exporter *Exporter) send(ctx context.Context, signal string, payload []byte) (time.Duration, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, exporter.endpoint+"/v1/"+signal, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("content-type", "application/json")
+	for name, value := range exporter.headers {
+		request.Header.Set(name, value)
+	}
+	response, err := exporter.client.Do(request)
+	if err != nil {
+		return 0, transientError(fmt.Errorf("export otlp %s: %w", signal, err))
+	}
+	defer func() { _ = response.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxResponseBytes))
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return 0, nil
+	}
+	rejected := fmt.Errorf("otlp collector rejected %s with status %d", signal, response.StatusCode)
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return parseRetryAfter(response.Header.Get("retry-after")), transientError(rejected)
+	default:
+		return 0, coreerrors.Wrap(rejected, coreerrors.CategoryNetworkPermanent, "otlp_export_rejected", "check the otel endpoint and collector configuration", false)
+	}
+}
+
+func (exporter *Exporter) backoff(attempt int) time.Duration {
+	delay := exporter.initialBackoff
+	for index := 1; index < attempt; index++ {
+		delay *= 2
+		if delay >= exporter.maxBackoff {
+			return exporter.maxBackoff
+		}
+	}
+	return delay
+}
+
+func normalizeDecision(decision Decision, now time.Time) Decision {
+	if decision.StartedAt.IsZero() {
+		decision.StartedAt = now.Add(-decision.Latency)
+	}
+	decision.StartedAt = decision.StartedAt.UTC()
+	if decision.Latency < 0 {
+		decision.Latency = 0
+	}
+	decision.Source = strings.TrimSpace(decision.Source)
+	decision.ToolName = strings.TrimSpace(decision.ToolName)
+	decision.Verdict = strings.TrimSpace(decision.Verdict)
+	return decision
+}
+
+func normalizeSignals(values []string) ([]string, error) {
+	if len(values) == 0 {
+		return append([]string(nil), allSignals...), nil
+	}
+	requested := map[string]bool{}
+	for _, value := range values {
+		signal := strings.ToLower(strings.TrimSpace(value))
+		switch signal {
+		case SignalTraces, SignalMetrics, SignalLogs:
+			requested[signal] = true
+		default:
+			return nil, invalidConfig(fmt.Errorf("unsupported otel signal %q (expected traces, metrics or logs)", value))
+		}
+	}
+	signals := []string{}
+	for _, signal := range allSignals {
+		if requested[signal] {
+			signals = append(signals, signal)
+		}
+	}
+	return signals, nil
+}
+
+func parseRetryAfter(raw string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func parseDurationDefault(raw string, fallback time.Duration, field string) (time.Duration, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return fallback, nil
+	}
+	parsed, err := time.ParseDuration(trimmed)
+	if err != nil || parsed <= 0 {
+		return 0, invalidConfig(fmt.Errorf("otel %s must be a positive duration", field))
+	}
+	return parsed, nil
+}
+
+func transientError(err error) error {
+	return coreerrors.Wrap(err, coreerrors.CategoryNetworkTransient, "otlp_collector_unavailable", "check that the otel endpoint is reachable; batches are spooled until it is", true)
+}
+
+func invalidConfig(err error) error {
+	return coreerrors.Wrap(err, coreerrors.CategoryInvalidInput, "otel_config_invalid", "fix the otel section of the project config", false)
+}
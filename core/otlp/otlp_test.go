@@ -0,0 +1,245 @@
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type receiverStub struct {
+	mu       sync.Mutex
+	payloads map[string][]map[string]any
+	headers  http.Header
+	failures map[string]int
+	status   int
+}
+
+func newReceiverStub(t *testing.T) (*receiverStub, *httptest.Server) {
+	t.Helper()
+	stub := &receiverStub{payloads: map[string][]map[string]any{}, failures: map[string]int{}, status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+		if request.Header.Get("content-type") != "application/json" {
+			t.Errorf("unexpected content type %q", request.Header.Get("content-type"))
+		}
+		signal := strings.TrimPrefix(request.URL.Path, "/v1/")
+		if stub.failures[signal] > 0 {
+			stub.failures[signal]--
+			writer.WriteHeader(stub.status)
+			return
+		}
+		var payload map[string]any
+		if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+			t.Errorf("decode %s payload: %v", signal, err)
+		}
+		stub.payloads[signal] = append(stub.payloads[signal], payload)
+		stub.headers = request.Header.Clone()
+		writer.Header().Set("content-type", "application/json")
+		_, _ = writer.Write([]byte("{}"))
+	}))
+	t.Cleanup(server.Close)
+	return stub, server
+}
+
+func (stub *receiverStub) count(signal string) int {
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	return len(stub.payloads[signal])
+}
+
+func testConfig(endpoint string, spool string) Config {
+	return Config{
+		Endpoint:       endpoint,
+		Spool:          spool,
+		InitialBackoff: "1ms",
+		MaxBackoff:     "2ms",
+		MaxAttempts:    2,
+	}
+}
+
+func testDecision(verdict string, latency time.Duration) Decision {
+	return Decision{
+		Source:         "gate_eval",
+		StartedAt:      time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC),
+		Latency:        latency,
+		ToolName:       "tool.write",
+		Verdict:        verdict,
+		ReasonCodes:    []string{"matched_rule_" + verdict},
+		PolicyDigest:   "sha256:policy",
+		MatchedRuleIDs: []string{"rule-" + verdict},
+		TraceID:        "trace_" + verdict,
+	}
+}
+
+func TestExporterSendsSpansMetricsAndLogs(t *testing.T) {
+	stub, server := newReceiverStub(t)
+	t.Setenv("GAIT_TEST_OTLP_TOKEN", "secret")
+	configuration := testConfig(server.URL+"/", filepath.Join(t.TempDir(), "spool"))
+	configuration.HeadersEnv = map[string]string{"authorization": "GAIT_TEST_OTLP_TOKEN"}
+	exporter, err := New(configuration, "1.2.3")
+	if err != nil {
+		t.Fatalf("new exporter: %v", err)
+	}
+	exporter.Record(testDecision("allow", 3*time.Millisecond))
+	exporter.Record(testDecision("allow", 40*time.Millisecond))
+	exporter.Record(testDecision("block", time.Millisecond))
+
+	result, err := exporter.Close(context.Background())
+	if err != nil || result.Exported != 3 || result.Spooled != 0 || len(result.Errors) != 0 {
+		t.Fatalf("unexpected flush result=%#v err=%v", result, err)
+	}
+	if stub.count(SignalTraces) != 1 || stub.count(SignalMetrics) != 1 || stub.count(SignalLogs) != 1 {
+		t.Fatalf("expected one request per signal, got %#v", stub.payloads)
+	}
+	if stub.headers.Get("authorization") != "secret" {
+		t.Fatalf("expected header from env, got %q", stub.headers.Get("authorization"))
+	}
+
+	encodedTraces, _ := json.Marshal(stub.payloads[SignalTraces][0])
+	for _, expected := range []string{`"name":"gait.gate.evaluate"`, `"key":"gait.policy_digest"`, `"stringValue":"sha256:policy"`, `"key":"gait.matched_rule"`, `"stringValue":"rule-block"`, `"key":"gait.decision_latency_ms"`, `"doubleValue":40`, `"stringValue":"1.2.3"`} {
+		if !strings.Contains(string(encodedTraces), expected) {
+			t.Fatalf("expected %s in traces payload: %s", expected, encodedTraces)
+		}
+	}
+	encodedMetrics, _ := json.Marshal(stub.payloads[SignalMetrics][0])
+	for _, expected := range []string{`"name":"gait.gate.decisions"`, `"asInt":"2"`, `"asInt":"1"`, `"name":"gait.gate.decision.duration"`, `"aggregationTemporality":1`} {
+		if !strings.Contains(string(encodedMetrics), expected) {
+			t.Fatalf("expected %s in metrics payload: %s", expected, encodedMetrics)
+		}
+	}
+	encodedLogs, _ := json.Marshal(stub.payloads[SignalLogs][0])
+	if !strings.Contains(string(encodedLogs), `"stringValue":"gait.gate.decision"`) {
+		t.Fatalf("unexpected logs payload: %s", encodedLogs)
+	}
+}
+
+func TestExporterRetriesTransientFailures(t *testing.T) {
+	stub, server := newReceiverStub(t)
+	stub.failures[SignalTraces] = 1
+	exporter, err := New(testConfig(server.URL, filepath.Join(t.TempDir(), "spool")), "")
+	if err != nil {
+		t.Fatalf("new exporter: %v", err)
+	}
+	exporter.Record(testDecision("allow", time.Millisecond))
+	result, err := exporter.Flush(context.Background())
+	if err != nil || result.Exported != 1 || stub.count(SignalTraces) != 1 {
+		t.Fatalf("expected retry to deliver, result=%#v err=%v", result, err)
+	}
+
+	stub.status = http.StatusBadRequest
+	stub.failures[SignalMetrics] = 1
+	exporter.Record(testDecision("block", time.Millisecond))
+	result, err = exporter.Flush(context.Background())
+	if err != nil || result.Dropped != 1 || result.Spooled != 0 || len(result.Errors) != 1 {
+		t.Fatalf("expected rejected batch to be dropped, result=%#v err=%v", result, err)
+	}
+}
+
+func TestExporterSpoolsWhileOfflineAndReplays(t *testing.T) {
+	spool := filepath.Join(t.TempDir(), "spool")
+	stub, server := newReceiverStub(t)
+	stub.failures[SignalMetrics] = 2
+
+	configuration := testConfig(server.URL, spool)
+	configuration.BatchSize = 1
+	offline, err := New(configuration, "")
+	if err != nil {
+		t.Fatalf("new exporter: %v", err)
+	}
+	offline.Record(testDecision("allow", time.Millisecond))
+	offline.Record(testDecision("block", time.Millisecond))
+	result, err := offline.Close(context.Background())
+	if err != nil || result.Spooled != 2 || result.Exported != 0 {
+		t.Fatalf("expected both batches to be spooled, result=%#v err=%v", result, err)
+	}
+	if stub.count(SignalTraces) != 1 {
+		t.Fatalf("expected only the first batch's traces to be sent, got %d", stub.count(SignalTraces))
+	}
+	pending, err := os.ReadDir(filepath.Join(spool, pendingDir))
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("expected two spooled batches, entries=%v err=%v", pending, err)
+	}
+
+	online, err := New(configuration, "")
+	if err != nil {
+		t.Fatalf("new exporter: %v", err)
+	}
+	result, err = online.Flush(context.Background())
+	if err != nil || result.Replayed != 2 || len(result.Errors) != 0 {
+		t.Fatalf("expected spool replay, result=%#v err=%v", result, err)
+	}
+	// Traces already accepted for the first batch are not sent again.
+	if stub.count(SignalTraces) != 2 || stub.count(SignalMetrics) != 2 || stub.count(SignalLogs) != 2 {
+		t.Fatalf("unexpected delivery counts traces=%d metrics=%d logs=%d", stub.count(SignalTraces), stub.count(SignalMetrics), stub.count(SignalLogs))
+	}
+	pending, _ = os.ReadDir(filepath.Join(spool, pendingDir))
+	if len(pending) != 0 {
+		t.Fatalf("expected empty spool, got %d entries", len(pending))
+	}
+}
+
+func TestExporterSpoolWritesQueueWithoutContactingCollector(t *testing.T) {
+	spool := filepath.Join(t.TempDir(), "spool")
+	stub, server := newReceiverStub(t)
+
+	configuration := testConfig(server.URL, spool)
+	configuration.BatchSize = 1
+	exporter, err := New(configuration, "")
+	if err != nil {
+		t.Fatalf("new exporter: %v", err)
+	}
+	exporter.Record(testDecision("allow", time.Millisecond))
+	exporter.Record(testDecision("block", time.Millisecond))
+	result, err := exporter.Spool()
+	if err != nil || result.Spooled != 2 || result.Exported != 0 {
+		t.Fatalf("expected both batches to be spooled, result=%#v err=%v", result, err)
+	}
+	if stub.count(SignalTraces) != 0 {
+		t.Fatalf("expected no export while spooling, got %d trace payloads", stub.count(SignalTraces))
+	}
+
+	result, err = exporter.Flush(context.Background())
+	if err != nil || result.Replayed != 2 {
+		t.Fatalf("expected spooled batches to replay, result=%#v err=%v", result, err)
+	}
+	if stub.count(SignalTraces) != 2 {
+		t.Fatalf("expected two trace payloads after flush, got %d", stub.count(SignalTraces))
+	}
+}
+
+func TestExporterBoundsQueueAndValidatesConfig(t *testing.T) {
+	configuration := testConfig("http://127.0.0.1:1", filepath.Join(t.TempDir(), "spool"))
+	configuration.QueueSize = 2
+	exporter, err := New(configuration, "")
+	if err != nil {
+		t.Fatalf("new exporter: %v", err)
+	}
+	if !exporter.Record(testDecision("allow", 0)) || !exporter.Record(testDecision("allow", 0)) || exporter.Record(testDecision("allow", 0)) {
+		t.Fatalf("expected third record to be dropped")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := exporter.Flush(ctx)
+	if err != nil || result.Dropped != 1 || result.Spooled != 2 {
+		t.Fatalf("expected cancelled flush to spool the queue, result=%#v err=%v", result, err)
+	}
+
+	for _, invalid := range []Config{
+		{Endpoint: "collector:4318"},
+		{Endpoint: "http://127.0.0.1:4318", Signals: []string{"profiles"}},
+		{Endpoint: "http://127.0.0.1:4318", Timeout: "soon"},
+		{EndpointEnv: "GAIT_TEST_OTLP_ENDPOINT_UNSET"},
+	} {
+		if _, err := New(invalid, ""); err == nil {
+			t.Fatalf("expected invalid config to fail: %#v", invalid)
+		}
+	}
+}
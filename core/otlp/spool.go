@@ -0,0 +1,187 @@
+package otlp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	coreerrors "github.com/Clyra-AI/gait/core/errors"
+	"github.com/Clyra-AI/gait/core/fsx"
+)
+
+const (
+	spoolSchemaID      = "gait.otlp.spool"
+	spoolSchemaVersion = "1.0.0"
+
+	pendingDir         = "pending"
+	inflightDir        = "inflight"
+	inflightStaleAfter = 5 * time.Minute
+)
+
+// spoolBatch is one undelivered batch. Signals lists only the signals the
+// collector has not accepted yet.
+type spoolBatch struct {
+	SchemaID      string     `json:"schema_id"`
+	SchemaVersion string     `json:"schema_version"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Signals       []string   `json:"signals"`
+	Decisions     []Decision `json:"decisions"`
+}
+
+func (exporter *Exporter) writeSpool(batch spoolBatch) error {
+	dir := filepath.Join(exporter.spool, pendingDir)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("create otlp spool: %w", err)
+	}
+	batch.SchemaID = spoolSchemaID
+	batch.SchemaVersion = spoolSchemaVersion
+	if batch.CreatedAt.IsZero() {
+		batch.CreatedAt = exporter.now()
+	}
+	encoded, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("marshal otlp spool batch: %w", err)
+	}
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Errorf("name otlp spool batch: %w", err)
+	}
+	// Names sort by creation time so replay keeps export order.
+	name := fmt.Sprintf("batch_%s_%s.json", batch.CreatedAt.UTC().Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+	if err := fsx.WriteFileAtomic(filepath.Join(dir, name), append(encoded, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write otlp spool batch: %w", err)
+	}
+	return exporter.trimSpool()
+}
+
+// trimSpool keeps the newest max_spool_files batches.
+func (exporter *Exporter) trimSpool() error {
+	names, err := exporter.pendingSpoolFiles()
+	if err != nil {
+		return err
+	}
+	for len(names) > exporter.maxSpoolFiles {
+		if err := os.Remove(filepath.Join(exporter.spool, pendingDir, names[0])); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("trim otlp spool: %w", err)
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+func (exporter *Exporter) pendingSpoolFiles() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(exporter.spool, pendingDir))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read otlp spool: %w", err)
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// replay claims one spooled batch by rename and exports its outstanding
+// signals. A batch that still fails transiently goes back to pending with the
+// signals that remain; a rejected or unreadable batch is discarded.
+func (exporter *Exporter) replay(ctx context.Context, name string) (int, error) {
+	pendingPath := filepath.Join(exporter.spool, pendingDir, name)
+	inflightPath := filepath.Join(exporter.spool, inflightDir, name)
+	if err := os.MkdirAll(filepath.Dir(inflightPath), 0o750); err != nil {
+		return 0, fmt.Errorf("create otlp spool: %w", err)
+	}
+	if err := os.Rename(pendingPath, inflightPath); err != nil {
+		return 0, nil
+	}
+	// #nosec G304 -- spool paths are derived from operator configuration.
+	payload, err := os.ReadFile(inflightPath)
+	if err != nil {
+		return 0, fmt.Errorf("read otlp spool batch: %w", err)
+	}
+	var batch spoolBatch
+	if err := json.Unmarshal(payload, &batch); err != nil || len(batch.Decisions) == 0 {
+		_ = os.Remove(inflightPath)
+		return 0, fmt.Errorf("discard unreadable otlp spool batch %s", name)
+	}
+	signals := exporter.configuredSignals(batch.Signals)
+	remaining, exportErr := exporter.exportBatch(ctx, signals, batch.Decisions)
+	if exportErr == nil {
+		return len(batch.Decisions), removeIfExists(inflightPath)
+	}
+	if !coreerrors.RetryableOf(exportErr) {
+		return 0, errors.Join(exportErr, removeIfExists(inflightPath))
+	}
+	batch.Signals = remaining
+	encoded, err := json.Marshal(batch)
+	if err != nil {
+		return 0, fmt.Errorf("marshal otlp spool batch: %w", err)
+	}
+	if err := fsx.WriteFileAtomic(pendingPath, append(encoded, '\n'), 0o600); err != nil {
+		return 0, fmt.Errorf("write otlp spool batch: %w", err)
+	}
+	return 0, errors.Join(exportErr, removeIfExists(inflightPath))
+}
+
+// configuredSignals drops spooled signals the exporter no longer sends.
+func (exporter *Exporter) configuredSignals(spooled []string) []string {
+	signals := []string{}
+	for _, signal := range exporter.signals {
+		for _, candidate := range spooled {
+			if candidate == signal {
+				signals = append(signals, signal)
+				break
+			}
+		}
+	}
+	return signals
+}
+
+func (exporter *Exporter) recoverInflight() error {
+	dir := filepath.Join(exporter.spool, inflightDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("read otlp spool inflight: %w", err)
+	}
+	now := exporter.now()
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) < inflightStaleAfter {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Join(exporter.spool, pendingDir), 0o750); err != nil {
+			return fmt.Errorf("create otlp spool: %w", err)
+		}
+		if err := os.Rename(filepath.Join(dir, entry.Name()), filepath.Join(exporter.spool, pendingDir, entry.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("recover otlp spool batch: %w", err)
+		}
+	}
+	return nil
+}
+
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	schemaguard "github.com/Clyra-AI/gait/core/schema/v1/guard"
 	schemapack "github.com/Clyra-AI/gait/core/schema/v1/pack"
 	schemarunpack "github.com/Clyra-AI/gait/core/schema/v1/runpack"
+	"github.com/Clyra-AI/gait/core/transparency"
 	"github.com/Clyra-AI/gait/core/zipx"
 	jcs "github.com/Clyra-AI/proof/canon"
 	sign "github.com/Clyra-AI/proof/signing"
@@ -71,9 +72,12 @@ type BuildResult struct {
 	Manifest schemapack.Manifest
 }
 
+// VerifyOptions.InclusionProof, when set, is checked offline against the
+// sha256 of the pack manifest (manifest.json for legacy runpacks).
 type VerifyOptions struct {
 	PublicKey        ed25519.PublicKey
 	RequireSignature bool
+	InclusionProof   *transparency.InclusionProof
 }
 
 type HashMismatch struct {
@@ -83,19 +87,20 @@ type HashMismatch struct {
 }
 
 type VerifyResult struct {
-	PackID          string         `json:"pack_id,omitempty"`
-	PackType        string         `json:"pack_type,omitempty"`
-	SourceRef       string         `json:"source_ref,omitempty"`
-	FilesChecked    int            `json:"files_checked"`
-	ProofRecords    int            `json:"proof_records_verified,omitempty"`
-	MissingFiles    []string       `json:"missing_files,omitempty"`
-	HashMismatches  []HashMismatch `json:"hash_mismatches,omitempty"`
-	UndeclaredFiles []string       `json:"undeclared_files,omitempty"`
-	SignatureStatus string         `json:"signature_status,omitempty"`
-	SignatureErrors []string       `json:"signature_errors,omitempty"`
-	SignaturesTotal int            `json:"signatures_total,omitempty"`
-	SignaturesValid int            `json:"signatures_valid,omitempty"`
-	LegacyType      string         `json:"legacy_type,omitempty"`
+	PackID          string                        `json:"pack_id,omitempty"`
+	PackType        string                        `json:"pack_type,omitempty"`
+	SourceRef       string                        `json:"source_ref,omitempty"`
+	FilesChecked    int                           `json:"files_checked"`
+	ProofRecords    int                           `json:"proof_records_verified,omitempty"`
+	MissingFiles    []string                      `json:"missing_files,omitempty"`
+	HashMismatches  []HashMismatch                `json:"hash_mismatches,omitempty"`
+	UndeclaredFiles []string                      `json:"undeclared_files,omitempty"`
+	SignatureStatus string                        `json:"signature_status,omitempty"`
+	SignatureErrors []string                      `json:"signature_errors,omitempty"`
+	SignaturesTotal int                           `json:"signatures_total,omitempty"`
+	SignaturesValid int                           `json:"signatures_valid,omitempty"`
+	LegacyType      string                        `json:"legacy_type,omitempty"`
+	Inclusion       *transparency.InclusionResult `json:"inclusion,omitempty"`
 }
 
 type InspectResult struct {
@@ -395,6 +400,10 @@ func Verify(path string, options VerifyOptions) (VerifyResult, error) {
 			if err != nil {
 				return VerifyResult{}, err
 			}
+			inclusion, err := checkManifestInclusion(bundle.Files["manifest.json"], options)
+			if err != nil {
+				return VerifyResult{}, err
+			}
 			return VerifyResult{
 				PackID:          legacy.ManifestDigest,
 				PackType:        string(BuildTypeRun),
@@ -407,6 +416,7 @@ func Verify(path string, options VerifyOptions) (VerifyResult, error) {
 				SignaturesTotal: legacy.SignaturesTotal,
 				SignaturesValid: legacy.SignaturesValid,
 				LegacyType:      "runpack",
+				Inclusion:       inclusion,
 			}, nil
 		}
 		return VerifyResult{}, fmt.Errorf("missing %s", manifestFileName)
@@ -420,7 +430,7 @@ func Verify(path string, options VerifyOptions) (VerifyResult, error) {
 	if err != nil {
 		var guardManifest schemaguard.PackManifest
 		if json.Unmarshal(manifestBytes, &guardManifest) == nil && guardManifest.SchemaID == "gait.guard.pack_manifest" {
-			legacy, verifyErr := guard.VerifyPackWithOptions(path, guard.VerifyOptions{PublicKey: options.PublicKey, RequireSignature: options.RequireSignature})
+			legacy, verifyErr := guard.VerifyPackWithOptions(path, guard.VerifyOptions{PublicKey: options.PublicKey, RequireSignature: options.RequireSignature, InclusionProof: options.InclusionProof})
 			if verifyErr != nil {
 				return VerifyResult{}, verifyErr
 			}
@@ -436,6 +446,7 @@ func Verify(path string, options VerifyOptions) (VerifyResult, error) {
 				SignaturesTotal: legacy.SignaturesTotal,
 				SignaturesValid: legacy.SignaturesValid,
 				LegacyType:      "guard",
+				Inclusion:       legacy.Inclusion,
 			}, nil
 		}
 		return VerifyResult{}, verificationError(fmt.Errorf("parse manifest: %w", err))
@@ -456,6 +467,10 @@ func Verify(path string, options VerifyOptions) (VerifyResult, error) {
 		SignatureStatus: "missing",
 		SignaturesTotal: len(manifest.Signatures),
 	}
+	if options.InclusionProof != nil {
+		inclusion := transparency.CheckInclusion(*options.InclusionProof, sha256Hex(manifestBytes), options.PublicKey)
+		result.Inclusion = &inclusion
+	}
 	proofSignatureErrors := make([]string, 0)
 
 	declared := make(map[string]schemapack.PackEntry, len(manifest.Contents))
@@ -1084,6 +1099,18 @@ func computePackID(manifest schemapack.Manifest) (string, error) {
 	return jcs.DigestJCS(raw)
 }
 
+func checkManifestInclusion(manifestFile *zip.File, options VerifyOptions) (*transparency.InclusionResult, error) {
+	if options.InclusionProof == nil || manifestFile == nil {
+		return nil, nil
+	}
+	manifestBytes, err := readZipFile(manifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", manifestFile.Name, err)
+	}
+	inclusion := transparency.CheckInclusion(*options.InclusionProof, sha256Hex(manifestBytes), options.PublicKey)
+	return &inclusion, nil
+}
+
 func sha256Hex(data []byte) string {
 	sum := sha256.Sum256(data)
 	return hex.EncodeToString(sum[:])
@@ -0,0 +1,271 @@
+package policytest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Clyra-AI/gait/core/runpack"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	schemapolicytest "github.com/Clyra-AI/gait/core/schema/v1/policytest"
+)
+
+const (
+	traceSchemaID         = "gait.gate.trace"
+	intentRequestSchemaID = "gait.gate.intent_request"
+
+	replayIdentity  = "policy-replay"
+	replayWorkspace = "."
+	replayRiskClass = "low"
+)
+
+// ReplaySample is one replayable intent and the file (and line) it came from.
+type ReplaySample struct {
+	Source string
+	Intent schemagate.IntentRequest
+}
+
+// LoadReplaySamples collects replayable intents from trace records, intent
+// requests (.json/.jsonl), session journals (.jsonl) and runpack archives
+// (.zip). Directories are walked recursively; JSON documents with other schema
+// IDs are ignored. Trace records and session events carry no args or targets,
+// so rules that match on those only fire for intent requests and runpack
+// intents.
+func LoadReplaySamples(paths []string) ([]ReplaySample, []schemapolicytest.PolicyReplayError, error) {
+	files := []string{}
+	seen := map[string]struct{}{}
+	for _, input := range paths {
+		trimmed := strings.TrimSpace(input)
+		if trimmed == "" {
+			continue
+		}
+		info, err := os.Stat(trimmed)
+		if err != nil {
+			return nil, nil, fmt.Errorf("replay input: %w", err)
+		}
+		if !info.IsDir() {
+			files = appendUniquePath(files, seen, filepath.Clean(trimmed))
+			continue
+		}
+		walkErr := filepath.WalkDir(trimmed, func(path string, entry os.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".json", ".jsonl", ".zip":
+				files = appendUniquePath(files, seen, filepath.Clean(path))
+			}
+			return nil
+		})
+		if walkErr != nil {
+			return nil, nil, fmt.Errorf("walk replay input: %w", walkErr)
+		}
+	}
+	sort.Strings(files)
+
+	samples := []ReplaySample{}
+	loadErrors := []schemapolicytest.PolicyReplayError{}
+	for _, path := range files {
+		var fileSamples []ReplaySample
+		var err error
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".zip":
+			fileSamples, err = runpackSamples(path)
+		case ".jsonl":
+			fileSamples, err = jsonlSamples(path)
+		default:
+			fileSamples, err = jsonSamples(path)
+		}
+		if err != nil {
+			loadErrors = append(loadErrors, schemapolicytest.PolicyReplayError{Source: path, Error: err.Error()})
+			continue
+		}
+		samples = append(samples, fileSamples...)
+	}
+	return samples, loadErrors, nil
+}
+
+func jsonSamples(path string) ([]ReplaySample, error) {
+	// #nosec G304 -- replay inputs are explicit local user paths.
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sample, ok, err := decodeSample(path, bytes.TrimSpace(content))
+	if err != nil || !ok {
+		return nil, err
+	}
+	return []ReplaySample{sample}, nil
+}
+
+func jsonlSamples(path string) ([]ReplaySample, error) {
+	// #nosec G304 -- replay inputs are explicit local user paths.
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isSessionJournal(content) {
+		return sessionJournalSamples(path)
+	}
+	samples := []ReplaySample{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		sample, ok, err := decodeSample(fmt.Sprintf("%s:%d", path, line), raw)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if ok {
+			samples = append(samples, sample)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+func decodeSample(source string, raw []byte) (ReplaySample, bool, error) {
+	var header struct {
+		SchemaID string `json:"schema_id"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return ReplaySample{}, false, fmt.Errorf("decode json: %w", err)
+	}
+	switch header.SchemaID {
+	case traceSchemaID:
+		var trace schemagate.TraceRecord
+		if err := json.Unmarshal(raw, &trace); err != nil {
+			return ReplaySample{}, false, fmt.Errorf("decode trace: %w", err)
+		}
+		return ReplaySample{Source: source, Intent: intentFromTrace(trace)}, true, nil
+	case intentRequestSchemaID:
+		var intent schemagate.IntentRequest
+		if err := json.Unmarshal(raw, &intent); err != nil {
+			return ReplaySample{}, false, fmt.Errorf("decode intent: %w", err)
+		}
+		return ReplaySample{Source: source, Intent: intent}, true, nil
+	default:
+		return ReplaySample{}, false, nil
+	}
+}
+
+func runpackSamples(path string) ([]ReplaySample, error) {
+	pack, err := runpack.ReadRunpack(path)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]ReplaySample, 0, len(pack.Intents))
+	for _, record := range pack.Intents {
+		args := record.Args
+		if args == nil {
+			args = map[string]any{}
+		}
+		runID := record.RunID
+		if runID == "" {
+			runID = pack.Run.RunID
+		}
+		samples = append(samples, ReplaySample{
+			Source: path + "#" + record.IntentID,
+			Intent: replayIntent(record.ToolName, args, schemagate.IntentContext{RunID: runID}),
+		})
+	}
+	return samples, nil
+}
+
+func isSessionJournal(content []byte) bool {
+	firstLine, _, _ := bytes.Cut(bytes.TrimSpace(content), []byte("\n"))
+	var record struct {
+		RecordType string `json:"record_type"`
+	}
+	if err := json.Unmarshal(firstLine, &record); err != nil {
+		return false
+	}
+	return record.RecordType == "header"
+}
+
+func sessionJournalSamples(path string) ([]ReplaySample, error) {
+	journal, err := runpack.ReadSessionJournal(path)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]ReplaySample, 0, len(journal.Events))
+	for _, event := range journal.Events {
+		if strings.TrimSpace(event.ToolName) == "" {
+			continue
+		}
+		samples = append(samples, ReplaySample{
+			Source: fmt.Sprintf("%s#%d", path, event.Sequence),
+			Intent: replayIntent(event.ToolName, map[string]any{}, schemagate.IntentContext{
+				RunID:     event.RunID,
+				SessionID: event.SessionID,
+			}),
+		})
+	}
+	return samples, nil
+}
+
+func intentFromTrace(trace schemagate.TraceRecord) schemagate.IntentRequest {
+	intent := replayIntent(trace.ToolName, map[string]any{}, schemagate.IntentContext{
+		Identity:    trace.AgentID,
+		Workspace:   trace.Repo,
+		RiskClass:   trace.CompositeRiskClass,
+		AgentID:     trace.AgentID,
+		RunID:       trace.RunID,
+		WorkflowID:  trace.WorkflowID,
+		Repo:        trace.Repo,
+		Environment: trace.Environment,
+	})
+	if trace.Script && len(trace.StepVerdicts) > 0 {
+		steps := make([]schemagate.IntentScriptStep, 0, len(trace.StepVerdicts))
+		for _, step := range trace.StepVerdicts {
+			steps = append(steps, schemagate.IntentScriptStep{ToolName: step.ToolName, Args: map[string]any{}})
+		}
+		intent.Script = &schemagate.IntentScript{Steps: steps}
+	}
+	return intent
+}
+
+// replayIntent fills the context fields that intent normalization requires
+// but recorded artifacts do not always carry.
+func replayIntent(toolName string, args map[string]any, context schemagate.IntentContext) schemagate.IntentRequest {
+	if strings.TrimSpace(context.Identity) == "" {
+		context.Identity = replayIdentity
+	}
+	if strings.TrimSpace(context.Workspace) == "" {
+		context.Workspace = replayWorkspace
+	}
+	if strings.TrimSpace(context.RiskClass) == "" {
+		context.RiskClass = replayRiskClass
+	}
+	return schemagate.IntentRequest{
+		SchemaID:      intentRequestSchemaID,
+		SchemaVersion: "1.0.0",
+		ToolName:      toolName,
+		Args:          args,
+		Targets:       []schemagate.IntentTarget{},
+		Context:       context,
+	}
+}
+
+func appendUniquePath(paths []string, seen map[string]struct{}, path string) []string {
+	if _, ok := seen[path]; ok {
+		return paths
+	}
+	seen[path] = struct{}{}
+	return append(paths, path)
+}
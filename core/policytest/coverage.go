@@ -0,0 +1,332 @@
+package policytest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/fsx"
+	"github.com/Clyra-AI/gait/core/gate"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	schemapolicytest "github.com/Clyra-AI/gait/core/schema/v1/policytest"
+)
+
+const (
+	policyCoverageSchemaID      = "gait.policytest.coverage"
+	policyCoverageSchemaVersion = "1.0.0"
+
+	RuleStatusHit         = "hit"
+	RuleStatusShadowed    = "shadowed"
+	RuleStatusUnreachable = "unreachable"
+)
+
+type CoverageOptions struct {
+	Policy          gate.Policy
+	Samples         []ReplaySample
+	LoadErrors      []schemapolicytest.PolicyReplayError
+	ProducerVersion string
+	Now             time.Time
+}
+
+type ruleTally struct {
+	hits           int
+	matchedSamples int
+	shadowedBy     map[string]struct{}
+	tools          map[string]struct{}
+}
+
+func Coverage(opts CoverageOptions) (schemapolicytest.PolicyCoverageReport, error) {
+	policyDigest, err := gate.PolicyDigest(opts.Policy)
+	if err != nil {
+		return schemapolicytest.PolicyCoverageReport{}, fmt.Errorf("policy digest: %w", err)
+	}
+	rules := append([]gate.PolicyRule(nil), opts.Policy.Rules...)
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority < rules[j].Priority
+		}
+		return rules[i].Name < rules[j].Name
+	})
+	tallies := make(map[string]*ruleTally, len(rules))
+	for _, rule := range rules {
+		tallies[rule.Name] = &ruleTally{shadowedBy: map[string]struct{}{}, tools: map[string]struct{}{}}
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	producerVersion := strings.TrimSpace(opts.ProducerVersion)
+	if producerVersion == "" {
+		producerVersion = "0.0.0-dev"
+	}
+	defaultReason := "default_" + opts.Policy.DefaultVerdict
+	errors := append([]schemapolicytest.PolicyReplayError(nil), opts.LoadErrors...)
+	ruleTools := map[string]struct{}{}
+	defaultTools := map[string]struct{}{}
+	evaluated := 0
+	defaultHits := 0
+
+	for _, sample := range opts.Samples {
+		outcome, err := gate.EvaluatePolicyDetailed(opts.Policy, sample.Intent, gate.EvalOptions{
+			ProducerVersion: producerVersion,
+			EvaluationTime:  now,
+		})
+		if err != nil {
+			errors = append(errors, schemapolicytest.PolicyReplayError{Source: sample.Source, Error: err.Error()})
+			continue
+		}
+		evaluated++
+
+		fired := splitMatchedRules(outcome.MatchedRule)
+		steps := outcome.StepVerdicts
+		if len(steps) == 0 {
+			steps = []schemagate.TraceStepVerdict{{
+				ToolName:    outcome.PreparedIntent.ToolName,
+				ReasonCodes: outcome.Result.ReasonCodes,
+				MatchedRule: outcome.MatchedRule,
+			}}
+		}
+		for _, step := range steps {
+			toolName := strings.TrimSpace(step.ToolName)
+			if toolName == "" {
+				continue
+			}
+			stepRules := splitMatchedRules(step.MatchedRule)
+			fired = mergeNames(fired, stepRules)
+			if len(stepRules) > 0 {
+				ruleTools[toolName] = struct{}{}
+				for _, name := range stepRules {
+					if tally, ok := tallies[name]; ok {
+						tally.tools[toolName] = struct{}{}
+					}
+				}
+				continue
+			}
+			if containsString(step.ReasonCodes, defaultReason) {
+				defaultTools[toolName] = struct{}{}
+			}
+		}
+		if outcome.MatchedRule == "" && containsString(outcome.Result.ReasonCodes, defaultReason) {
+			defaultHits++
+		}
+		for _, name := range fired {
+			if tally, ok := tallies[name]; ok {
+				tally.hits++
+			}
+		}
+
+		if strings.TrimSpace(outcome.PreparedIntent.ToolName) == "" {
+			continue
+		}
+		matching, err := gate.RulesMatchingIntent(opts.Policy, outcome.PreparedIntent)
+		if err != nil {
+			return schemapolicytest.PolicyCoverageReport{}, err
+		}
+		for _, name := range matching {
+			tally, ok := tallies[name]
+			if !ok {
+				continue
+			}
+			tally.matchedSamples++
+			if containsString(fired, name) {
+				continue
+			}
+			for _, winner := range fired {
+				tally.shadowedBy[winner] = struct{}{}
+			}
+		}
+	}
+
+	report := schemapolicytest.PolicyCoverageReport{
+		SchemaID:         policyCoverageSchemaID,
+		SchemaVersion:    policyCoverageSchemaVersion,
+		CreatedAt:        now,
+		ProducerVersion:  producerVersion,
+		PolicyDigest:     policyDigest,
+		DefaultVerdict:   opts.Policy.DefaultVerdict,
+		SamplesTotal:     len(opts.Samples),
+		SamplesEvaluated: evaluated,
+		DefaultHits:      defaultHits,
+		DefaultOnlyTools: []string{},
+		Rules:            make([]schemapolicytest.PolicyRuleCoverage, 0, len(rules)),
+		UnreachableRules: []string{},
+		ShadowedRules:    []string{},
+		Errors:           errors,
+	}
+	for _, rule := range rules {
+		tally := tallies[rule.Name]
+		entry := schemapolicytest.PolicyRuleCoverage{
+			Name:           rule.Name,
+			Effect:         rule.Effect,
+			Priority:       rule.Priority,
+			Hits:           tally.hits,
+			MatchedSamples: tally.matchedSamples,
+			Tools:          sortedKeys(tally.tools),
+		}
+		switch {
+		case tally.hits > 0:
+			entry.Status = RuleStatusHit
+		case len(tally.shadowedBy) > 0:
+			entry.Status = RuleStatusShadowed
+			entry.ShadowedBy = sortedKeys(tally.shadowedBy)
+			report.ShadowedRules = append(report.ShadowedRules, rule.Name)
+		default:
+			entry.Status = RuleStatusUnreachable
+			report.UnreachableRules = append(report.UnreachableRules, rule.Name)
+		}
+		report.Rules = append(report.Rules, entry)
+	}
+
+	observed := map[string]struct{}{}
+	for tool := range ruleTools {
+		observed[tool] = struct{}{}
+	}
+	for tool := range defaultTools {
+		observed[tool] = struct{}{}
+		if _, ok := ruleTools[tool]; !ok {
+			report.DefaultOnlyTools = append(report.DefaultOnlyTools, tool)
+		}
+	}
+	sort.Strings(report.DefaultOnlyTools)
+	report.ToolsObserved = len(observed)
+	if report.ToolsObserved > 0 {
+		report.DefaultOnlyFraction = float64(len(report.DefaultOnlyTools)) / float64(report.ToolsObserved)
+	}
+	return report, nil
+}
+
+func WriteCoverageJUnit(path string, report schemapolicytest.PolicyCoverageReport) error {
+	dir := filepath.Dir(path)
+	if dir != "." && dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return fmt.Errorf("create junit directory: %w", err)
+		}
+	}
+	encoded, err := xml.MarshalIndent(buildCoverageJUnit(report), "", "  ")
+	if err != nil {
+		return err
+	}
+	document := append([]byte(xml.Header), encoded...)
+	document = append(document, '\n')
+	return fsx.WriteFileAtomic(path, document, 0o600)
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func buildCoverageJUnit(report schemapolicytest.PolicyCoverageReport) junitTestSuites {
+	testCases := make([]junitTestCase, 0, len(report.Rules)+1)
+	failures := 0
+	for _, rule := range report.Rules {
+		testCase := junitTestCase{Name: rule.Name, ClassName: "gait.policy.coverage.rules", Time: "0"}
+		switch rule.Status {
+		case RuleStatusShadowed:
+			failures++
+			body := "rule matched observed intents but was shadowed by " + strings.Join(rule.ShadowedBy, ",")
+			testCase.Failure = &junitFailure{Message: body, Type: "policy_rule_shadowed", Body: body}
+		case RuleStatusUnreachable:
+			failures++
+			body := "rule matched no observed intents"
+			testCase.Failure = &junitFailure{Message: body, Type: "policy_rule_unreachable", Body: body}
+		}
+		testCases = append(testCases, testCase)
+	}
+	defaultCase := junitTestCase{Name: "default_only_tools", ClassName: "gait.policy.coverage.tools", Time: "0"}
+	if len(report.DefaultOnlyTools) > 0 {
+		failures++
+		body := fmt.Sprintf("%d of %d observed tools only reached default_verdict: %s", len(report.DefaultOnlyTools), report.ToolsObserved, strings.Join(report.DefaultOnlyTools, ","))
+		defaultCase.Failure = &junitFailure{Message: body, Type: "policy_default_only_tools", Body: body}
+	}
+	testCases = append(testCases, defaultCase)
+
+	suite := junitTestSuite{
+		Name:      "gait.policy.coverage",
+		Tests:     len(testCases),
+		Failures:  failures,
+		Errors:    len(report.Errors),
+		Time:      "0",
+		TestCases: testCases,
+	}
+	return junitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Time:     "0",
+		Suites:   []junitTestSuite{suite},
+	}
+}
+
+func splitMatchedRules(value string) []string {
+	names := []string{}
+	for _, name := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(name); trimmed != "" {
+			names = mergeNames(names, []string{trimmed})
+		}
+	}
+	return names
+}
+
+func mergeNames(current []string, additions []string) []string {
+	for _, name := range additions {
+		if !containsString(current, name) {
+			current = append(current, name)
+		}
+	}
+	return current
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(values map[string]struct{}) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,127 @@
+package policytest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/gate"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+)
+
+func TestCoverageReportsHitShadowedAndUnreachableRules(t *testing.T) {
+	policy, err := gate.ParsePolicyYAML([]byte(`
+default_verdict: allow
+rules:
+  - name: block-delete
+    priority: 10
+    effect: block
+    match:
+      tool_names: [tool.delete]
+  - name: approve-delete
+    priority: 20
+    effect: require_approval
+    match:
+      tool_names: [tool.delete]
+  - name: block-network
+    priority: 30
+    effect: block
+    match:
+      tool_names: [tool.http]
+`))
+	if err != nil {
+		t.Fatalf("parse policy: %v", err)
+	}
+
+	dir := t.TempDir()
+	writeJSONFile(t, filepath.Join(dir, "trace_delete.json"), schemagate.TraceRecord{
+		SchemaID:      traceSchemaID,
+		SchemaVersion: "1.0.0",
+		ToolName:      "tool.delete",
+		Verdict:       "block",
+	})
+	writeJSONFile(t, filepath.Join(dir, "nested", "trace_read.json"), schemagate.TraceRecord{
+		SchemaID:      traceSchemaID,
+		SchemaVersion: "1.0.0",
+		ToolName:      "tool.read",
+		Verdict:       "allow",
+	})
+	intentLine, err := json.Marshal(schemagate.IntentRequest{
+		SchemaID: intentRequestSchemaID,
+		ToolName: "tool.delete",
+		Args:     map[string]any{"path": "/tmp/x"},
+		Context:  schemagate.IntentContext{Identity: "alice", Workspace: "/repo", RiskClass: "high"},
+	})
+	if err != nil {
+		t.Fatalf("marshal intent: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "intents.jsonl"), []byte(string(intentLine)+"\n{\"schema_id\":\"gait.other\"}\n"), 0o600); err != nil {
+		t.Fatalf("write jsonl: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o600); err != nil {
+		t.Fatalf("write broken: %v", err)
+	}
+
+	samples, loadErrors, err := LoadReplaySamples([]string{dir})
+	if err != nil {
+		t.Fatalf("load samples: %v", err)
+	}
+	if len(samples) != 3 || len(loadErrors) != 1 || !strings.HasSuffix(loadErrors[0].Source, "broken.json") {
+		t.Fatalf("unexpected samples=%d errors=%#v", len(samples), loadErrors)
+	}
+
+	now := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	report, err := Coverage(CoverageOptions{Policy: policy, Samples: samples, LoadErrors: loadErrors, ProducerVersion: "test", Now: now})
+	if err != nil {
+		t.Fatalf("coverage: %v", err)
+	}
+	if report.SamplesEvaluated != 3 || report.DefaultHits != 1 || len(report.Errors) != 1 {
+		t.Fatalf("unexpected totals: %#v", report)
+	}
+	statuses := map[string]string{}
+	for _, rule := range report.Rules {
+		statuses[rule.Name] = rule.Status
+		if rule.Name == "block-delete" && rule.Hits != 2 {
+			t.Fatalf("expected two hits for block-delete, got %#v", rule)
+		}
+		if rule.Name == "approve-delete" && strings.Join(rule.ShadowedBy, ",") != "block-delete" {
+			t.Fatalf("expected approve-delete shadowed by block-delete, got %#v", rule)
+		}
+	}
+	if statuses["block-delete"] != RuleStatusHit || statuses["approve-delete"] != RuleStatusShadowed || statuses["block-network"] != RuleStatusUnreachable {
+		t.Fatalf("unexpected rule statuses: %#v", statuses)
+	}
+	if strings.Join(report.DefaultOnlyTools, ",") != "tool.read" || report.ToolsObserved != 2 || report.DefaultOnlyFraction != 0.5 {
+		t.Fatalf("unexpected default-only tools: %#v fraction=%v", report.DefaultOnlyTools, report.DefaultOnlyFraction)
+	}
+
+	junitPath := filepath.Join(dir, "out", "coverage.xml")
+	if err := WriteCoverageJUnit(junitPath, report); err != nil {
+		t.Fatalf("write junit: %v", err)
+	}
+	raw, err := os.ReadFile(junitPath)
+	if err != nil {
+		t.Fatalf("read junit: %v", err)
+	}
+	document := string(raw)
+	if !strings.Contains(document, `failures="3"`) || !strings.Contains(document, "policy_rule_shadowed") || !strings.Contains(document, "policy_rule_unreachable") {
+		t.Fatalf("unexpected junit document: %s", document)
+	}
+}
+
+func writeJSONFile(t *testing.T, path string, value any) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
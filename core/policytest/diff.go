@@ -0,0 +1,256 @@
+package policytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/gate"
+	schemapolicytest "github.com/Clyra-AI/gait/core/schema/v1/policytest"
+)
+
+const (
+	policyDiffSchemaID      = "gait.policytest.diff"
+	policyDiffSchemaVersion = "1.0.0"
+
+	DiffChangeVerdict     = "verdict"
+	DiffChangeReasonCodes = "reason_codes"
+	DiffChangeApprovals   = "approvals"
+
+	diffGroupDefaultVerdict = "default_verdict"
+	diffGroupNoRule         = "no_rule"
+)
+
+type DiffOptions struct {
+	Base            gate.Policy
+	Head            gate.Policy
+	Samples         []ReplaySample
+	LoadErrors      []schemapolicytest.PolicyReplayError
+	ProducerVersion string
+	Now             time.Time
+}
+
+// Diff compares two policies structurally and replays every sample under both,
+// reporting intents whose verdict, reason codes, or approval requirements
+// changed. A sample that evaluates under base but errors under head is counted
+// in HeadErrors, since head would no longer decide it.
+func Diff(opts DiffOptions) (schemapolicytest.PolicyDiffReport, error) {
+	baseDigest, err := gate.PolicyDigest(opts.Base)
+	if err != nil {
+		return schemapolicytest.PolicyDiffReport{}, fmt.Errorf("base policy digest: %w", err)
+	}
+	headDigest, err := gate.PolicyDigest(opts.Head)
+	if err != nil {
+		return schemapolicytest.PolicyDiffReport{}, fmt.Errorf("head policy digest: %w", err)
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	producerVersion := strings.TrimSpace(opts.ProducerVersion)
+	if producerVersion == "" {
+		producerVersion = "0.0.0-dev"
+	}
+
+	report := schemapolicytest.PolicyDiffReport{
+		SchemaID:         policyDiffSchemaID,
+		SchemaVersion:    policyDiffSchemaVersion,
+		CreatedAt:        now,
+		ProducerVersion:  producerVersion,
+		BasePolicyDigest: baseDigest,
+		HeadPolicyDigest: headDigest,
+		SamplesTotal:     len(opts.Samples),
+		Changes:          []schemapolicytest.PolicyIntentChange{},
+		Groups:           []schemapolicytest.PolicyDiffGroup{},
+		Errors:           append([]schemapolicytest.PolicyReplayError(nil), opts.LoadErrors...),
+	}
+	report.PolicyChanges, report.AddedRules, report.RemovedRules, report.ChangedRules = structuralPolicyDiff(opts.Base, opts.Head)
+
+	evalOptions := gate.EvalOptions{ProducerVersion: producerVersion, EvaluationTime: now}
+	groups := map[string]*schemapolicytest.PolicyDiffGroup{}
+	for _, sample := range opts.Samples {
+		baseOutcome, err := gate.EvaluatePolicyDetailed(opts.Base, sample.Intent, evalOptions)
+		if err != nil {
+			report.Errors = append(report.Errors, schemapolicytest.PolicyReplayError{Source: sample.Source, Error: "base: " + err.Error()})
+			continue
+		}
+		headOutcome, err := gate.EvaluatePolicyDetailed(opts.Head, sample.Intent, evalOptions)
+		if err != nil {
+			report.Errors = append(report.Errors, schemapolicytest.PolicyReplayError{Source: sample.Source, Error: "head: " + err.Error()})
+			report.HeadErrors++
+			continue
+		}
+		report.SamplesEvaluated++
+
+		change := schemapolicytest.PolicyIntentChange{
+			Source:                       sample.Source,
+			ToolName:                     baseOutcome.PreparedIntent.ToolName,
+			IntentDigest:                 baseOutcome.PreparedIntent.IntentDigest,
+			Changed:                      []string{},
+			BaseVerdict:                  baseOutcome.Result.Verdict,
+			HeadVerdict:                  headOutcome.Result.Verdict,
+			BaseReasonCodes:              baseOutcome.Result.ReasonCodes,
+			HeadReasonCodes:              headOutcome.Result.ReasonCodes,
+			BaseMatchedRule:              baseOutcome.MatchedRule,
+			HeadMatchedRule:              headOutcome.MatchedRule,
+			BaseMinApprovals:             baseOutcome.MinApprovals,
+			HeadMinApprovals:             headOutcome.MinApprovals,
+			BaseRequireDistinctApprovers: baseOutcome.RequireDistinctApprovers,
+			HeadRequireDistinctApprovers: headOutcome.RequireDistinctApprovers,
+		}
+		if change.ToolName == "" {
+			change.ToolName = sample.Intent.ToolName
+		}
+		if change.BaseVerdict != change.HeadVerdict {
+			change.Changed = append(change.Changed, DiffChangeVerdict)
+		}
+		if !reflect.DeepEqual(uniqueSortedStrings(change.BaseReasonCodes), uniqueSortedStrings(change.HeadReasonCodes)) {
+			change.Changed = append(change.Changed, DiffChangeReasonCodes)
+		}
+		if change.BaseMinApprovals != change.HeadMinApprovals || change.BaseRequireDistinctApprovers != change.HeadRequireDistinctApprovers {
+			change.Changed = append(change.Changed, DiffChangeApprovals)
+		}
+		if len(change.Changed) == 0 {
+			continue
+		}
+		change.MorePermissive = morePermissive(change)
+		change.Rules = mergeNames(diffGroupRules(baseOutcome, opts.Base.DefaultVerdict), diffGroupRules(headOutcome, opts.Head.DefaultVerdict))
+		sort.Strings(change.Rules)
+
+		report.ChangedIntents++
+		if change.MorePermissive {
+			report.MorePermissive++
+		}
+		for _, rule := range change.Rules {
+			group, ok := groups[rule]
+			if !ok {
+				group = &schemapolicytest.PolicyDiffGroup{Rule: rule, Sources: []string{}}
+				groups[rule] = group
+			}
+			group.Intents++
+			if change.MorePermissive {
+				group.MorePermissive++
+			}
+			group.Sources = append(group.Sources, sample.Source)
+		}
+		report.Changes = append(report.Changes, change)
+	}
+
+	ruleNames := make([]string, 0, len(groups))
+	for name := range groups {
+		ruleNames = append(ruleNames, name)
+	}
+	sort.Strings(ruleNames)
+	for _, name := range ruleNames {
+		report.Groups = append(report.Groups, *groups[name])
+	}
+	return report, nil
+}
+
+func morePermissive(change schemapolicytest.PolicyIntentChange) bool {
+	baseRank, headRank := verdictRank(change.BaseVerdict), verdictRank(change.HeadVerdict)
+	if headRank != baseRank {
+		return headRank < baseRank
+	}
+	if change.HeadVerdict != "require_approval" {
+		return false
+	}
+	return change.HeadMinApprovals < change.BaseMinApprovals ||
+		(change.BaseRequireDistinctApprovers && !change.HeadRequireDistinctApprovers)
+}
+
+func verdictRank(verdict string) int {
+	switch verdict {
+	case "block":
+		return 3
+	case "require_approval":
+		return 2
+	case "dry_run":
+		return 1
+	default:
+		return 0
+	}
+}
+
+func diffGroupRules(outcome gate.EvalOutcome, defaultVerdict string) []string {
+	names := splitMatchedRules(outcome.MatchedRule)
+	for _, step := range outcome.StepVerdicts {
+		names = mergeNames(names, splitMatchedRules(step.MatchedRule))
+	}
+	if len(names) > 0 {
+		return names
+	}
+	if containsString(outcome.Result.ReasonCodes, "default_"+defaultVerdict) {
+		return []string{diffGroupDefaultVerdict}
+	}
+	return []string{diffGroupNoRule}
+}
+
+func structuralPolicyDiff(base gate.Policy, head gate.Policy) ([]string, []string, []string, []schemapolicytest.PolicyRuleChange) {
+	policyChanges := changedFields(reflect.ValueOf(base), reflect.ValueOf(head), map[string]bool{"rules": true})
+
+	baseRules := map[string]gate.PolicyRule{}
+	for _, rule := range base.Rules {
+		baseRules[rule.Name] = rule
+	}
+	headRules := map[string]gate.PolicyRule{}
+	for _, rule := range head.Rules {
+		headRules[rule.Name] = rule
+	}
+	added := []string{}
+	removed := []string{}
+	changed := []schemapolicytest.PolicyRuleChange{}
+	for name, headRule := range headRules {
+		baseRule, ok := baseRules[name]
+		if !ok {
+			added = append(added, name)
+			continue
+		}
+		fields := changedFields(reflect.ValueOf(baseRule), reflect.ValueOf(headRule), map[string]bool{"name": true})
+		if len(fields) > 0 {
+			changed = append(changed, schemapolicytest.PolicyRuleChange{Name: name, Fields: fields})
+		}
+	}
+	for name := range baseRules {
+		if _, ok := headRules[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Slice(changed, func(i, j int) bool { return changed[i].Name < changed[j].Name })
+	return policyChanges, added, removed, changed
+}
+
+// changedFields compares the yaml-tagged fields of two structs of the same type
+// and returns the tags of the fields whose JSON encodings differ.
+func changedFields(base reflect.Value, head reflect.Value, skip map[string]bool) []string {
+	fields := []string{}
+	structType := base.Type()
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		if !field.IsExported() {
+			continue
+		}
+		tag, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if tag == "" || tag == "-" || skip[tag] {
+			continue
+		}
+		baseJSON, baseErr := json.Marshal(base.Field(index).Interface())
+		headJSON, headErr := json.Marshal(head.Field(index).Interface())
+		if baseErr != nil || headErr != nil || string(baseJSON) != string(headJSON) {
+			fields = append(fields, tag)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func uniqueSortedStrings(values []string) []string {
+	out := mergeNames([]string{}, values)
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,142 @@
+package policytest
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/runpack"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+)
+
+func TestDiffReportsStructuralAndBehavioralChanges(t *testing.T) {
+	base, err := gate.ParsePolicyYAML([]byte(`
+default_verdict: allow
+rules:
+  - name: block-delete
+    effect: block
+    match:
+      tool_names: [tool.delete]
+  - name: approve-write
+    effect: require_approval
+    min_approvals: 2
+    match:
+      tool_names: [tool.write]
+  - name: retired
+    effect: block
+    match:
+      tool_names: [tool.legacy]
+`))
+	if err != nil {
+		t.Fatalf("parse base: %v", err)
+	}
+	head, err := gate.ParsePolicyYAML([]byte(`
+default_verdict: allow
+rules:
+  - name: block-delete
+    effect: require_approval
+    match:
+      tool_names: [tool.delete]
+  - name: approve-write
+    effect: require_approval
+    min_approvals: 1
+    match:
+      tool_names: [tool.write]
+  - name: block-exec
+    effect: block
+    match:
+      tool_names: [tool.exec]
+`))
+	if err != nil {
+		t.Fatalf("parse head: %v", err)
+	}
+
+	dir := t.TempDir()
+	journalPath := filepath.Join(dir, "session.journal.jsonl")
+	now := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := runpack.StartSession(journalPath, runpack.SessionStartOptions{SessionID: "sess_1", RunID: "run_1", Now: now}); err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	for _, toolName := range []string{"tool.delete", "tool.write", "tool.exec", "tool.read"} {
+		if _, err := runpack.AppendSessionEvent(journalPath, runpack.SessionAppendOptions{CreatedAt: now, ToolName: toolName, Verdict: "allow"}); err != nil {
+			t.Fatalf("append %s: %v", toolName, err)
+		}
+	}
+	writeJSONFile(t, filepath.Join(dir, "traces", "trace_read.json"), schemagate.TraceRecord{
+		SchemaID: traceSchemaID,
+		ToolName: "tool.read",
+		Verdict:  "allow",
+	})
+
+	samples, loadErrors, err := LoadReplaySamples([]string{dir})
+	if err != nil || len(loadErrors) != 0 || len(samples) != 5 {
+		t.Fatalf("load samples: %d errors=%#v err=%v", len(samples), loadErrors, err)
+	}
+	report, err := Diff(DiffOptions{Base: base, Head: head, Samples: samples, Now: now})
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+
+	if strings.Join(report.AddedRules, ",") != "block-exec" || strings.Join(report.RemovedRules, ",") != "retired" {
+		t.Fatalf("unexpected added/removed rules: %#v %#v", report.AddedRules, report.RemovedRules)
+	}
+	if len(report.ChangedRules) != 2 || report.ChangedRules[0].Name != "approve-write" || strings.Join(report.ChangedRules[0].Fields, ",") != "min_approvals,require_distinct_approvers" ||
+		strings.Join(report.ChangedRules[1].Fields, ",") != "effect" {
+		t.Fatalf("unexpected changed rules: %#v", report.ChangedRules)
+	}
+	if report.SamplesEvaluated != 5 || report.ChangedIntents != 3 || report.MorePermissive != 2 {
+		t.Fatalf("unexpected totals: evaluated=%d changed=%d permissive=%d", report.SamplesEvaluated, report.ChangedIntents, report.MorePermissive)
+	}
+	byTool := map[string]bool{}
+	for _, change := range report.Changes {
+		byTool[change.ToolName] = change.MorePermissive
+		if change.ToolName == "tool.write" && strings.Join(change.Changed, ",") != DiffChangeApprovals {
+			t.Fatalf("expected approvals-only change for tool.write, got %#v", change)
+		}
+	}
+	if !byTool["tool.delete"] || !byTool["tool.write"] || byTool["tool.exec"] {
+		t.Fatalf("unexpected permissiveness: %#v", byTool)
+	}
+	groups := map[string]int{}
+	for _, group := range report.Groups {
+		groups[group.Rule] = group.Intents
+	}
+	if groups["block-delete"] != 1 || groups["approve-write"] != 1 || groups["block-exec"] != 1 || groups[diffGroupDefaultVerdict] != 1 {
+		t.Fatalf("unexpected groups: %#v", report.Groups)
+	}
+}
+
+func TestDiffCountsSamplesThatOnlyFailUnderHead(t *testing.T) {
+	base, err := gate.ParsePolicyYAML([]byte(`
+default_verdict: allow
+fail_closed:
+  enabled: true
+  risk_classes: [high]
+`))
+	if err != nil {
+		t.Fatalf("parse base: %v", err)
+	}
+	head, err := gate.ParsePolicyYAML([]byte("default_verdict: allow\n"))
+	if err != nil {
+		t.Fatalf("parse head: %v", err)
+	}
+	invalid := schemagate.IntentRequest{Context: schemagate.IntentContext{RiskClass: "high"}}
+
+	report, err := Diff(DiffOptions{Base: base, Head: head, Samples: []ReplaySample{{Source: "invalid.json", Intent: invalid}}})
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if report.HeadErrors != 1 || report.SamplesEvaluated != 0 || len(report.Errors) != 1 || !strings.HasPrefix(report.Errors[0].Error, "head: ") {
+		t.Fatalf("expected head-only error to be counted, got %#v", report)
+	}
+
+	report, err = Diff(DiffOptions{Base: head, Head: base, Samples: []ReplaySample{{Source: "invalid.json", Intent: invalid}}})
+	if err != nil {
+		t.Fatalf("reverse diff: %v", err)
+	}
+	if report.HeadErrors != 0 || len(report.Errors) != 1 || !strings.HasPrefix(report.Errors[0].Error, "base: ") {
+		t.Fatalf("expected base error to be skipped, got %#v", report)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"strings"
 
+	"github.com/Clyra-AI/gait/core/notify"
+	"github.com/Clyra-AI/gait/core/otlp"
 	"github.com/goccy/go-yaml"
 )
 
@@ -20,6 +22,8 @@ type Config struct {
 	Gate      GateDefaults      `yaml:"gate"`
 	MCPServe  MCPServeDefaults  `yaml:"mcp_serve"`
 	Retention RetentionDefaults `yaml:"retention"`
+	Notify    notify.Config     `yaml:"notify"`
+	OTel      otlp.Config       `yaml:"otel"`
 }
 
 type GateDefaults struct {
@@ -46,13 +50,27 @@ type GateDefaults struct {
 }
 
 type MCPServeDefaults struct {
-	Enabled                  bool   `yaml:"enabled"`
-	Listen                   string `yaml:"listen"`
-	AuthMode                 string `yaml:"auth_mode"`
-	AuthTokenEnv             string `yaml:"auth_token_env"`
-	MaxRequestBytes          int64  `yaml:"max_request_bytes"`
-	HTTPVerdictStatus        string `yaml:"http_verdict_status"`
-	AllowClientArtifactPaths bool   `yaml:"allow_client_artifact_paths"`
+	Enabled                  bool                `yaml:"enabled"`
+	Listen                   string              `yaml:"listen"`
+	AuthMode                 string              `yaml:"auth_mode"`
+	AuthTokenEnv             string              `yaml:"auth_token_env"`
+	TLSCert                  string              `yaml:"tls_cert"`
+	TLSKey                   string              `yaml:"tls_key"`
+	TLSClientCA              string              `yaml:"tls_client_ca"`
+	JWT                      MCPServeJWTDefaults `yaml:"jwt"`
+	IdentityBinding          string              `yaml:"identity_binding"`
+	MaxRequestBytes          int64               `yaml:"max_request_bytes"`
+	HTTPVerdictStatus        string              `yaml:"http_verdict_status"`
+	AllowClientArtifactPaths bool                `yaml:"allow_client_artifact_paths"`
+}
+
+type MCPServeJWTDefaults struct {
+	JWKS           string `yaml:"jwks"`
+	Issuer         string `yaml:"issuer"`
+	Audience       string `yaml:"audience"`
+	IdentityClaim  string `yaml:"identity_claim"`
+	WorkspaceClaim string `yaml:"workspace_claim"`
+	AgentClaim     string `yaml:"agent_claim"`
 }
 
 type RetentionDefaults struct {
@@ -111,6 +129,16 @@ func (configuration *Config) normalize() {
 	configuration.MCPServe.Listen = strings.TrimSpace(configuration.MCPServe.Listen)
 	configuration.MCPServe.AuthMode = strings.ToLower(strings.TrimSpace(configuration.MCPServe.AuthMode))
 	configuration.MCPServe.AuthTokenEnv = strings.TrimSpace(configuration.MCPServe.AuthTokenEnv)
+	configuration.MCPServe.TLSCert = strings.TrimSpace(configuration.MCPServe.TLSCert)
+	configuration.MCPServe.TLSKey = strings.TrimSpace(configuration.MCPServe.TLSKey)
+	configuration.MCPServe.TLSClientCA = strings.TrimSpace(configuration.MCPServe.TLSClientCA)
+	configuration.MCPServe.JWT.JWKS = strings.TrimSpace(configuration.MCPServe.JWT.JWKS)
+	configuration.MCPServe.JWT.Issuer = strings.TrimSpace(configuration.MCPServe.JWT.Issuer)
+	configuration.MCPServe.JWT.Audience = strings.TrimSpace(configuration.MCPServe.JWT.Audience)
+	configuration.MCPServe.JWT.IdentityClaim = strings.TrimSpace(configuration.MCPServe.JWT.IdentityClaim)
+	configuration.MCPServe.JWT.WorkspaceClaim = strings.TrimSpace(configuration.MCPServe.JWT.WorkspaceClaim)
+	configuration.MCPServe.JWT.AgentClaim = strings.TrimSpace(configuration.MCPServe.JWT.AgentClaim)
+	configuration.MCPServe.IdentityBinding = strings.ToLower(strings.TrimSpace(configuration.MCPServe.IdentityBinding))
 	configuration.MCPServe.HTTPVerdictStatus = strings.ToLower(strings.TrimSpace(configuration.MCPServe.HTTPVerdictStatus))
 	configuration.Retention.TraceTTL = strings.TrimSpace(configuration.Retention.TraceTTL)
 	configuration.Retention.SessionTTL = strings.TrimSpace(configuration.Retention.SessionTTL)
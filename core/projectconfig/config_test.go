@@ -63,6 +63,10 @@ mcp_serve:
   listen: " 0.0.0.0:8787 "
   auth_mode: " TOKEN "
   auth_token_env: " GAIT_TOKEN "
+  jwt:
+    issuer: " https://idp.example.com "
+    workspace_claim: " workspace "
+  identity_binding: " STRICT "
   max_request_bytes: 1048576
   http_verdict_status: " STRICT "
 retention:
@@ -105,6 +109,9 @@ retention:
 	if configuration.MCPServe.AuthMode != "token" {
 		t.Fatalf("unexpected mcp_serve.auth_mode %q", configuration.MCPServe.AuthMode)
 	}
+	if configuration.MCPServe.JWT.Issuer != "https://idp.example.com" || configuration.MCPServe.JWT.WorkspaceClaim != "workspace" || configuration.MCPServe.IdentityBinding != "strict" {
+		t.Fatalf("unexpected mcp_serve auth defaults: %#v", configuration.MCPServe)
+	}
 	if configuration.MCPServe.HTTPVerdictStatus != "strict" {
 		t.Fatalf("unexpected mcp_serve.http_verdict_status %q", configuration.MCPServe.HTTPVerdictStatus)
 	}
@@ -0,0 +1,484 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	schemacommon "github.com/Clyra-AI/gait/core/schema/v1/common"
+)
+
+const (
+	Algorithm      = "hmac-sha256"
+	DefaultSaltEnv = "GAIT_REDACTION_SALT"
+	markerPrefix   = "redacted:" + Algorithm + ":"
+
+	ClassSecret = "secret"
+	ClassPII    = "pii"
+)
+
+// Config is the redaction section of a gate policy. Paths select values by
+// JSON path ($.args.password, $.args.headers.*, $.result.items[*].token),
+// detectors scan every remaining string for common secret formats and pii
+// scans for personal data classes.
+type Config struct {
+	Paths     []string `yaml:"paths" json:"paths,omitempty"`
+	Detectors []string `yaml:"detectors" json:"detectors,omitempty"`
+	PII       []string `yaml:"pii" json:"pii,omitempty"`
+	SaltEnv   string   `yaml:"salt_env" json:"salt_env,omitempty"`
+}
+
+type detector struct {
+	name    string
+	class   string
+	pattern *regexp.Regexp
+	valid   func(string) bool
+}
+
+var (
+	secretDetectors = map[string]detector{
+		"aws_access_key_id": {pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+		"bearer_token":      {pattern: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9._~+/=-]{16,})`)},
+		"github_token":      {pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{30,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+		"jwt":               {pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+		"private_key":       {pattern: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+		"sk_api_key":        {pattern: regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`)},
+		"slack_token":       {pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	}
+	piiDetectors = map[string]detector{
+		"credit_card": {pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`), valid: luhnValid},
+		"email":       {pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)},
+		"us_ssn":      {pattern: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+	}
+	pathSegmentPattern = regexp.MustCompile(`^[A-Za-z0-9_@:-]+$`)
+	markerPattern      = regexp.MustCompile(regexp.QuoteMeta(markerPrefix) + `[a-f0-9]{64}`)
+)
+
+func (config Config) Enabled() bool {
+	return len(config.Paths) > 0 || len(config.Detectors) > 0 || len(config.PII) > 0
+}
+
+// Normalize validates the configuration and returns it sorted and
+// deduplicated. The name all expands to every known detector or pii class.
+func (config Config) Normalize() (Config, error) {
+	output := Config{SaltEnv: strings.TrimSpace(config.SaltEnv)}
+	for _, path := range config.Paths {
+		trimmed := strings.TrimSpace(path)
+		if trimmed == "" {
+			continue
+		}
+		if _, err := parsePath(trimmed); err != nil {
+			return Config{}, err
+		}
+		output.Paths = append(output.Paths, trimmed)
+	}
+	var err error
+	if output.Detectors, err = normalizeNames("detector", config.Detectors, secretDetectors); err != nil {
+		return Config{}, err
+	}
+	if output.PII, err = normalizeNames("pii class", config.PII, piiDetectors); err != nil {
+		return Config{}, err
+	}
+	output.Paths = uniqueSorted(output.Paths)
+	return output, nil
+}
+
+// Redactor replaces selected values with salted digests. A nil Redactor
+// leaves values untouched.
+type Redactor struct {
+	paths     []compiledPath
+	detectors []detector
+	salt      []byte
+	saltID    string
+}
+
+type compiledPath struct {
+	raw      string
+	segments []string
+}
+
+// New compiles config. The salt is read from config.SaltEnv (default
+// GAIT_REDACTION_SALT); without one a random salt is used so digests cannot
+// be correlated across redactors. It returns nil when nothing is configured.
+func New(config Config) (*Redactor, error) {
+	normalized, err := config.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	if !normalized.Enabled() {
+		return nil, nil
+	}
+	saltEnv := normalized.SaltEnv
+	if saltEnv == "" {
+		saltEnv = DefaultSaltEnv
+	}
+	salt := []byte(os.Getenv(saltEnv))
+	if len(salt) == 0 {
+		salt = make([]byte, 32)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("generate redaction salt: %w", err)
+		}
+	}
+	saltSum := sha256.Sum256(salt)
+	redactor := &Redactor{salt: salt, saltID: hex.EncodeToString(saltSum[:8])}
+	for _, path := range normalized.Paths {
+		segments, err := parsePath(path)
+		if err != nil {
+			return nil, err
+		}
+		redactor.paths = append(redactor.paths, compiledPath{raw: path, segments: segments})
+	}
+	for _, name := range normalized.Detectors {
+		entry := secretDetectors[name]
+		entry.name = name
+		entry.class = ClassSecret
+		redactor.detectors = append(redactor.detectors, entry)
+	}
+	for _, name := range normalized.PII {
+		entry := piiDetectors[name]
+		entry.name = name
+		entry.class = ClassPII
+		redactor.detectors = append(redactor.detectors, entry)
+	}
+	return redactor, nil
+}
+
+// SaltID identifies the salt without revealing it, so digests from the same
+// salt can be matched across artifacts.
+func (r *Redactor) SaltID() string {
+	if r == nil {
+		return ""
+	}
+	return r.saltID
+}
+
+// Digest returns the salted digest recorded for a redacted value.
+func (r *Redactor) Digest(value []byte) string {
+	mac := hmac.New(sha256.New, r.salt)
+	_, _ = mac.Write(value)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Redact returns a copy of value with matched paths and detector hits
+// replaced. root is the JSON path of value inside its record (for example
+// $.args) and protected lists top-level keys that are never touched, such as
+// digests a verifier recomputes.
+func (r *Redactor) Redact(root string, value any, protected ...string) (any, []schemacommon.RedactionEntry) {
+	if r == nil || value == nil {
+		return value, nil
+	}
+	skip := make(map[string]struct{}, len(protected))
+	for _, key := range protected {
+		skip[key] = struct{}{}
+	}
+	rootSegments, err := parsePath(root)
+	if err != nil {
+		rootSegments = nil
+	}
+	entries := []schemacommon.RedactionEntry{}
+	redacted := r.walk(value, rootSegments, len(rootSegments), skip, &entries)
+	return redacted, entries
+}
+
+// RedactObject is Redact for JSON objects.
+func (r *Redactor) RedactObject(root string, value map[string]any, protected ...string) (map[string]any, []schemacommon.RedactionEntry) {
+	if r == nil || value == nil {
+		return value, nil
+	}
+	redacted, entries := r.Redact(root, value, protected...)
+	object, _ := redacted.(map[string]any)
+	return object, entries
+}
+
+// RedactStruct round-trips a record through JSON, redacts it and decodes the
+// result back into target.
+func (r *Redactor) RedactStruct(source any, target any, protected ...string) ([]schemacommon.RedactionEntry, error) {
+	if r == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(source)
+	if err != nil {
+		return nil, fmt.Errorf("encode record for redaction: %w", err)
+	}
+	var object map[string]any
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, fmt.Errorf("decode record for redaction: %w", err)
+	}
+	redacted, entries := r.RedactObject("$", object, protected...)
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(redacted)
+	if err != nil {
+		return nil, fmt.Errorf("encode redacted record: %w", err)
+	}
+	if err := json.Unmarshal(encoded, target); err != nil {
+		return nil, fmt.Errorf("decode redacted record: %w", err)
+	}
+	return entries, nil
+}
+
+// Summary wraps entries with the algorithm and salt id, or returns nil when
+// nothing was redacted.
+func (r *Redactor) Summary(entries []schemacommon.RedactionEntry) *schemacommon.RedactionSummary {
+	if r == nil || len(entries) == 0 {
+		return nil
+	}
+	return &schemacommon.RedactionSummary{
+		Algorithm: Algorithm,
+		SaltID:    r.saltID,
+		Entries:   entries,
+	}
+}
+
+func (r *Redactor) walk(value any, segments []string, rootDepth int, skip map[string]struct{}, entries *[]schemacommon.RedactionEntry) any {
+	if rule, ok := r.matchPath(segments); ok {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			raw = []byte(fmt.Sprint(value))
+		}
+		digest := r.Digest(raw)
+		*entries = append(*entries, schemacommon.RedactionEntry{
+			Path:   renderPath(segments),
+			Rule:   "path:" + rule,
+			Class:  ClassSecret,
+			Digest: digest,
+		})
+		return markerPrefix + digest
+	}
+	switch typed := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		output := make(map[string]any, len(typed))
+		for _, key := range keys {
+			if len(segments) == rootDepth {
+				if _, protected := skip[key]; protected {
+					output[key] = typed[key]
+					continue
+				}
+			}
+			output[key] = r.walk(typed[key], appendSegment(segments, key), rootDepth, skip, entries)
+		}
+		return output
+	case []any:
+		output := make([]any, len(typed))
+		for index, item := range typed {
+			output[index] = r.walk(item, appendSegment(segments, "["+strconv.Itoa(index)+"]"), rootDepth, skip, entries)
+		}
+		return output
+	case string:
+		return r.scanString(typed, segments, entries)
+	default:
+		return value
+	}
+}
+
+func (r *Redactor) scanString(value string, segments []string, entries *[]schemacommon.RedactionEntry) string {
+	if value == "" || len(r.detectors) == 0 {
+		return value
+	}
+	output := value
+	for _, entry := range r.detectors {
+		output = replaceOutsideMarkers(output, func(chunk string) string {
+			return entry.pattern.ReplaceAllStringFunc(chunk, func(match string) string {
+				secret := match
+				prefix := ""
+				if groups := entry.pattern.FindStringSubmatchIndex(match); len(groups) >= 4 && groups[2] >= 0 {
+					prefix = match[:groups[2]]
+					secret = match[groups[2]:groups[3]]
+				}
+				if entry.valid != nil && !entry.valid(secret) {
+					return match
+				}
+				digest := r.Digest([]byte(secret))
+				*entries = append(*entries, schemacommon.RedactionEntry{
+					Path:   renderPath(segments),
+					Rule:   entry.class + ":" + entry.name,
+					Class:  entry.class,
+					Digest: digest,
+				})
+				return prefix + markerPrefix + digest
+			})
+		})
+	}
+	return output
+}
+
+// replaceOutsideMarkers applies replace to the parts of value between markers
+// so later detectors never match inside an earlier digest.
+func replaceOutsideMarkers(value string, replace func(string) string) string {
+	locations := markerPattern.FindAllStringIndex(value, -1)
+	if len(locations) == 0 {
+		return replace(value)
+	}
+	var builder strings.Builder
+	previous := 0
+	for _, location := range locations {
+		builder.WriteString(replace(value[previous:location[0]]))
+		builder.WriteString(value[location[0]:location[1]])
+		previous = location[1]
+	}
+	builder.WriteString(replace(value[previous:]))
+	return builder.String()
+}
+
+func (r *Redactor) matchPath(segments []string) (string, bool) {
+	for _, path := range r.paths {
+		if len(path.segments) != len(segments) {
+			continue
+		}
+		matched := true
+		for index, segment := range path.segments {
+			actual := segments[index]
+			switch {
+			case segment == "*" && !strings.HasPrefix(actual, "["):
+			case segment == "[*]" && strings.HasPrefix(actual, "["):
+			case segment == actual:
+			default:
+				matched = false
+			}
+			if !matched {
+				break
+			}
+		}
+		if matched {
+			return path.raw, true
+		}
+	}
+	return "", false
+}
+
+// parsePath splits $.a.b[*].c into [a b [*] c].
+func parsePath(path string) ([]string, error) {
+	trimmed := strings.TrimSpace(path)
+	if trimmed != "$" && !strings.HasPrefix(trimmed, "$.") && !strings.HasPrefix(trimmed, "$[") {
+		return nil, fmt.Errorf("redaction path %q must start with $", path)
+	}
+	rest := strings.TrimPrefix(trimmed, "$")
+	segments := []string{}
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			segment := rest[:end]
+			if segment != "*" && !pathSegmentPattern.MatchString(segment) {
+				return nil, fmt.Errorf("redaction path %q has invalid segment %q", path, segment)
+			}
+			segments = append(segments, segment)
+			rest = rest[end:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("redaction path %q has unterminated index", path)
+			}
+			index := rest[1:end]
+			if index != "*" {
+				if _, err := strconv.Atoi(index); err != nil {
+					return nil, fmt.Errorf("redaction path %q index must be a number or *", path)
+				}
+			}
+			segments = append(segments, "["+index+"]")
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("redaction path %q is malformed", path)
+		}
+	}
+	return segments, nil
+}
+
+func renderPath(segments []string) string {
+	var builder strings.Builder
+	builder.WriteString("$")
+	for _, segment := range segments {
+		if !strings.HasPrefix(segment, "[") {
+			builder.WriteString(".")
+		}
+		builder.WriteString(segment)
+	}
+	return builder.String()
+}
+
+func appendSegment(segments []string, segment string) []string {
+	next := make([]string, len(segments)+1)
+	copy(next, segments)
+	next[len(segments)] = segment
+	return next
+}
+
+func normalizeNames(kind string, names []string, known map[string]detector) ([]string, error) {
+	output := []string{}
+	for _, name := range names {
+		normalized := strings.ToLower(strings.TrimSpace(name))
+		if normalized == "" {
+			continue
+		}
+		if normalized == "all" {
+			for known := range known {
+				output = append(output, known)
+			}
+			continue
+		}
+		if _, ok := known[normalized]; !ok {
+			return nil, fmt.Errorf("unsupported redaction %s %q", kind, name)
+		}
+		output = append(output, normalized)
+	}
+	return uniqueSorted(output), nil
+}
+
+func uniqueSorted(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(values))
+	output := make([]string, 0, len(values))
+	for _, value := range values {
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		output = append(output, value)
+	}
+	sort.Strings(output)
+	return output
+}
+
+func luhnValid(value string) bool {
+	digits := make([]int, 0, len(value))
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, int(r-'0'))
+		}
+	}
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	for index := len(digits) - 1; index >= 0; index-- {
+		digit := digits[index]
+		if (len(digits)-1-index)%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,199 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Clyra-AI/gait/core/fsx"
+	schemaregistry "github.com/Clyra-AI/gait/core/schema/v1/registry"
+)
+
+const maxArtifactBytes = 4 << 20
+
+type ArtifactOptions struct {
+	CacheDir     string
+	PackName     string
+	ArtifactPath string
+	Extensions   []string
+}
+
+type ResolvedArtifact struct {
+	PackName     string `json:"pack_name"`
+	PackVersion  string `json:"pack_version"`
+	Digest       string `json:"digest"`
+	ArtifactPath string `json:"artifact_path"`
+	SHA256       string `json:"sha256"`
+	Path         string `json:"path"`
+	Content      []byte `json:"-"`
+}
+
+// ResolveArtifact returns one materialized artifact of the pinned install of
+// a pack after checking the pin against the cached manifest and the artifact
+// bytes against the manifest sha256.
+func ResolveArtifact(options ArtifactOptions) (ResolvedArtifact, error) {
+	packName := strings.TrimSpace(options.PackName)
+	if packName == "" || !filepath.IsLocal(packName) || strings.ContainsAny(packName, `/\`) {
+		return ResolvedArtifact{}, fmt.Errorf("invalid registry pack name %q", options.PackName)
+	}
+	cacheDir, err := resolveCacheDir(options.CacheDir)
+	if err != nil {
+		return ResolvedArtifact{}, err
+	}
+	pinPath := filepath.Join(cacheDir, "pins", packName+".pin")
+	// #nosec G304 -- pin path is derived from local cache root.
+	pinRaw, err := os.ReadFile(pinPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ResolvedArtifact{}, fmt.Errorf("registry pack %s is not installed in %s", packName, cacheDir)
+		}
+		return ResolvedArtifact{}, fmt.Errorf("read pin file: %w", err)
+	}
+	pinDigest := normalizeDigest(string(pinRaw))
+	if pinDigest == "" {
+		return ResolvedArtifact{}, fmt.Errorf("registry pack %s has an empty pin", packName)
+	}
+	candidates, err := filepath.Glob(filepath.Join(cacheDir, packName, "*", pinDigest, "registry_pack.json"))
+	if err != nil {
+		return ResolvedArtifact{}, fmt.Errorf("glob pinned manifest: %w", err)
+	}
+	if len(candidates) != 1 {
+		return ResolvedArtifact{}, fmt.Errorf("expected one cached manifest for %s pin %s, found %d", packName, pinDigest, len(candidates))
+	}
+	metadataPath := candidates[0]
+	// #nosec G304 -- metadata path is discovered from local cache structure.
+	rawManifest, err := os.ReadFile(metadataPath)
+	if err != nil {
+		return ResolvedArtifact{}, fmt.Errorf("read metadata file: %w", err)
+	}
+	manifest, err := parseRegistryManifest(rawManifest)
+	if err != nil {
+		return ResolvedArtifact{}, err
+	}
+	digest, _, err := digestSignableManifest(manifest)
+	if err != nil {
+		return ResolvedArtifact{}, err
+	}
+	if digest != pinDigest {
+		return ResolvedArtifact{}, fmt.Errorf("cached manifest digest %s does not match pin %s", digest, pinDigest)
+	}
+	artifact, err := selectArtifact(manifest, options.ArtifactPath, options.Extensions)
+	if err != nil {
+		return ResolvedArtifact{}, err
+	}
+	artifactPath := filepath.Join(filepath.Dir(metadataPath), filepath.FromSlash(artifact.Path))
+	// #nosec G304 -- artifact path is validated as local to the pinned cache entry.
+	content, err := os.ReadFile(artifactPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ResolvedArtifact{}, fmt.Errorf("artifact %s of %s is not materialized in the cache; reinstall the pack from a source that ships it", artifact.Path, packName)
+		}
+		return ResolvedArtifact{}, fmt.Errorf("read artifact: %w", err)
+	}
+	if err := verifyArtifactDigest(artifact, content); err != nil {
+		return ResolvedArtifact{}, err
+	}
+	return ResolvedArtifact{
+		PackName:     manifest.PackName,
+		PackVersion:  manifest.PackVersion,
+		Digest:       digest,
+		ArtifactPath: artifact.Path,
+		SHA256:       normalizeDigest(artifact.SHA256),
+		Path:         artifactPath,
+		Content:      content,
+	}, nil
+}
+
+func selectArtifact(manifest schemaregistry.RegistryPack, wanted string, extensions []string) (schemaregistry.PackArtifact, error) {
+	wanted = filepath.ToSlash(strings.TrimSpace(wanted))
+	matches := []schemaregistry.PackArtifact{}
+	for _, artifact := range manifest.Artifacts {
+		if !isLocalArtifactPath(artifact.Path) {
+			continue
+		}
+		if wanted != "" {
+			if artifact.Path == wanted {
+				return artifact, nil
+			}
+			continue
+		}
+		if hasAnySuffix(strings.ToLower(artifact.Path), extensions) {
+			matches = append(matches, artifact)
+		}
+	}
+	if wanted != "" {
+		return schemaregistry.PackArtifact{}, fmt.Errorf("artifact %s not found in %s", wanted, manifest.PackName)
+	}
+	if len(matches) != 1 {
+		paths := make([]string, 0, len(matches))
+		for _, match := range matches {
+			paths = append(paths, match.Path)
+		}
+		sort.Strings(paths)
+		return schemaregistry.PackArtifact{}, fmt.Errorf("expected one %s artifact in %s, found [%s]; name it explicitly", strings.Join(extensions, "|"), manifest.PackName, strings.Join(paths, ","))
+	}
+	return matches[0], nil
+}
+
+// materializeLocalArtifacts copies artifacts shipped next to a local manifest
+// into the install directory so they can be resolved offline later. Files that
+// are missing or do not match the manifest sha256 are not copied.
+func materializeLocalArtifacts(source string, manifest schemaregistry.RegistryPack, installDir string) error {
+	sourceDir := filepath.Dir(source)
+	for _, artifact := range manifest.Artifacts {
+		if !isLocalArtifactPath(artifact.Path) {
+			continue
+		}
+		sourcePath := filepath.Join(sourceDir, filepath.FromSlash(artifact.Path))
+		// #nosec G304 -- artifact path is validated as local to the manifest directory.
+		content, err := os.ReadFile(sourcePath)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return fmt.Errorf("read artifact %s: %w", artifact.Path, err)
+		}
+		if int64(len(content)) > maxArtifactBytes || verifyArtifactDigest(artifact, content) != nil {
+			continue
+		}
+		targetPath := filepath.Join(installDir, filepath.FromSlash(artifact.Path))
+		if err := os.MkdirAll(filepath.Dir(targetPath), 0o750); err != nil {
+			return fmt.Errorf("mkdir artifact dir: %w", err)
+		}
+		if err := fsx.WriteFileAtomic(targetPath, content, 0o600); err != nil {
+			return fmt.Errorf("write artifact %s: %w", artifact.Path, err)
+		}
+	}
+	return nil
+}
+
+func verifyArtifactDigest(artifact schemaregistry.PackArtifact, content []byte) error {
+	sum := sha256.Sum256(content)
+	actual := hex.EncodeToString(sum[:])
+	if actual != normalizeDigest(artifact.SHA256) {
+		return fmt.Errorf("artifact %s sha256 mismatch: manifest=%s actual=%s", artifact.Path, normalizeDigest(artifact.SHA256), actual)
+	}
+	return nil
+}
+
+func isLocalArtifactPath(value string) bool {
+	trimmed := strings.TrimSpace(value)
+	return trimmed != "" && trimmed != "registry_pack.json" && filepath.IsLocal(filepath.FromSlash(trimmed))
+}
+
+func hasAnySuffix(value string, suffixes []string) bool {
+	if len(suffixes) == 0 {
+		return true
+	}
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(value, strings.ToLower(suffix)) {
+			return true
+		}
+	}
+	return false
+}
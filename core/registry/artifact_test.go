@@ -0,0 +1,128 @@
+package registry
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	schemaregistry "github.com/Clyra-AI/gait/core/schema/v1/registry"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+func TestInstallMaterializesLocalArtifactsAndResolves(t *testing.T) {
+	workDir := t.TempDir()
+	cacheDir := filepath.Join(workDir, "cache")
+	policyContent := []byte("default_verdict: block\n")
+	if err := os.WriteFile(filepath.Join(workDir, "policy.yaml"), policyContent, 0o600); err != nil {
+		t.Fatalf("write artifact: %v", err)
+	}
+	manifestPath, publicKey := mustWriteSignedArtifactManifest(t, workDir, "org-baseline", []schemaregistry.PackArtifact{
+		{Path: "policy.yaml", SHA256: sha256Hex(policyContent), Media: "application/yaml"},
+		{Path: "README.md", SHA256: strings.Repeat("b", 64)},
+	})
+
+	installResult, err := Install(context.Background(), InstallOptions{Source: manifestPath, CacheDir: cacheDir, PublicKey: publicKey})
+	if err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(installResult.MetadataPath), "policy.yaml")); err != nil {
+		t.Fatalf("expected materialized artifact: %v", err)
+	}
+
+	resolved, err := ResolveArtifact(ArtifactOptions{CacheDir: cacheDir, PackName: "org-baseline", Extensions: []string{".yaml", ".yml"}})
+	if err != nil {
+		t.Fatalf("resolve artifact: %v", err)
+	}
+	if resolved.ArtifactPath != "policy.yaml" || resolved.Digest != installResult.Digest || string(resolved.Content) != string(policyContent) {
+		t.Fatalf("unexpected resolved artifact: %#v", resolved)
+	}
+	if _, err := ResolveArtifact(ArtifactOptions{CacheDir: cacheDir, PackName: "org-baseline", ArtifactPath: "README.md"}); err == nil || !strings.Contains(err.Error(), "not materialized") {
+		t.Fatalf("expected missing artifact error, got %v", err)
+	}
+	if _, err := ResolveArtifact(ArtifactOptions{CacheDir: cacheDir, PackName: "missing"}); err == nil || !strings.Contains(err.Error(), "not installed") {
+		t.Fatalf("expected not installed error, got %v", err)
+	}
+	if _, err := ResolveArtifact(ArtifactOptions{CacheDir: cacheDir, PackName: "../org-baseline"}); err == nil {
+		t.Fatalf("expected invalid pack name error")
+	}
+
+	if err := os.WriteFile(resolved.Path, []byte("default_verdict: allow\n"), 0o600); err != nil {
+		t.Fatalf("tamper artifact: %v", err)
+	}
+	if _, err := ResolveArtifact(ArtifactOptions{CacheDir: cacheDir, PackName: "org-baseline", ArtifactPath: "policy.yaml"}); err == nil || !strings.Contains(err.Error(), "sha256 mismatch") {
+		t.Fatalf("expected tampered artifact to fail, got %v", err)
+	}
+}
+
+func TestInstallSkipsArtifactDigestMismatch(t *testing.T) {
+	workDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workDir, "policy.yaml"), []byte("rules: []\n"), 0o600); err != nil {
+		t.Fatalf("write artifact: %v", err)
+	}
+	manifestPath, publicKey := mustWriteSignedArtifactManifest(t, workDir, "org-baseline", []schemaregistry.PackArtifact{
+		{Path: "policy.yaml", SHA256: strings.Repeat("a", 64)},
+	})
+	cacheDir := filepath.Join(workDir, "cache")
+	installResult, err := Install(context.Background(), InstallOptions{Source: manifestPath, CacheDir: cacheDir, PublicKey: publicKey})
+	if err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(installResult.MetadataPath), "policy.yaml")); !os.IsNotExist(err) {
+		t.Fatalf("expected mismatched artifact not to be copied, got %v", err)
+	}
+	if _, err := ResolveArtifact(ArtifactOptions{CacheDir: cacheDir, PackName: "org-baseline"}); err == nil || !strings.Contains(err.Error(), "not materialized") {
+		t.Fatalf("expected unresolved artifact, got %v", err)
+	}
+}
+
+func mustWriteSignedArtifactManifest(t *testing.T, dir string, packName string, artifacts []schemaregistry.PackArtifact) (string, ed25519.PublicKey) {
+	t.Helper()
+	keyPair, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	manifest := schemaregistry.RegistryPack{
+		SchemaID:        "gait.registry.pack",
+		SchemaVersion:   "1.0.0",
+		CreatedAt:       time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		ProducerVersion: "0.0.0-test",
+		PackName:        packName,
+		PackVersion:     "1.0.0",
+		Artifacts:       artifacts,
+	}
+	digest, err := signableManifestDigest(manifest)
+	if err != nil {
+		t.Fatalf("digest signable manifest: %v", err)
+	}
+	signature, err := sign.SignDigestHex(keyPair.Private, digest)
+	if err != nil {
+		t.Fatalf("sign digest: %v", err)
+	}
+	manifest.Signatures = []schemaregistry.SignatureRef{{
+		Alg:          signature.Alg,
+		KeyID:        signature.KeyID,
+		Sig:          signature.Sig,
+		SignedDigest: signature.SignedDigest,
+	}}
+	path := filepath.Join(dir, "registry_pack.json")
+	raw, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("marshal manifest: %v", err)
+	}
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	return path, keyPair.Public
+}
+
+func sha256Hex(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
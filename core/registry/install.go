@@ -17,6 +17,7 @@ import (
 
 	"github.com/Clyra-AI/gait/core/fsx"
 	schemaregistry "github.com/Clyra-AI/gait/core/schema/v1/registry"
+	"github.com/Clyra-AI/gait/core/trust"
 	jcs "github.com/Clyra-AI/proof/canon"
 	sign "github.com/Clyra-AI/proof/signing"
 )
@@ -25,6 +26,7 @@ type InstallOptions struct {
 	Source              string
 	CacheDir            string
 	PublicKey           ed25519.PublicKey
+	TrustStore          *trust.Store
 	AllowHosts          []string
 	PublisherAllowlist  []string
 	PinDigest           string
@@ -116,7 +118,7 @@ func installManifest(cacheDir string, source string, rawManifest []byte, options
 	if err := enforcePin(options.PinDigest, signableDigest); err != nil {
 		return InstallResult{}, err
 	}
-	if err := verifySignatures(manifest.Signatures, options.PublicKey, signableDigest); err != nil {
+	if err := verifyManifestSignatures(manifest, options.PublicKey, options.TrustStore, signableDigest); err != nil {
 		return InstallResult{}, err
 	}
 	metadataPath := filepath.Join(
@@ -129,6 +131,11 @@ func installManifest(cacheDir string, source string, rawManifest []byte, options
 	if err := os.MkdirAll(filepath.Dir(metadataPath), 0o750); err != nil {
 		return InstallResult{}, fmt.Errorf("mkdir metadata dir: %w", err)
 	}
+	if !fallbackUsed && !isRemoteSource(source) {
+		if err := materializeLocalArtifacts(source, manifest, filepath.Dir(metadataPath)); err != nil {
+			return InstallResult{}, err
+		}
+	}
 	if err := fsx.WriteFileAtomic(metadataPath, metadataBytes, 0o600); err != nil {
 		return InstallResult{}, fmt.Errorf("write registry metadata: %w", err)
 	}
@@ -454,7 +461,34 @@ func verifySignatures(signatures []schemaregistry.SignatureRef, publicKey ed2551
 	if len(publicKey) == 0 {
 		return fmt.Errorf("public key is required for signature verification")
 	}
+	return verifySignaturesWith(signatures, digest, func(sign.Signature) (ed25519.PublicKey, error) {
+		return publicKey, nil
+	})
+}
+
+// verifyManifestSignatures checks manifest signatures against publicKey or,
+// when store is set, against registry publisher keys trusted for the
+// manifest publisher at its created_at.
+func verifyManifestSignatures(manifest schemaregistry.RegistryPack, publicKey ed25519.PublicKey, store *trust.Store, digest string) error {
+	if store == nil {
+		return verifySignatures(manifest.Signatures, publicKey, digest)
+	}
+	if len(manifest.Signatures) == 0 {
+		return fmt.Errorf("registry manifest has no signatures")
+	}
+	return verifySignaturesWith(manifest.Signatures, digest, func(signature sign.Signature) (ed25519.PublicKey, error) {
+		return store.Resolve(trust.Lookup{
+			Role:     trust.RoleRegistryPublisher,
+			KeyID:    signature.KeyID,
+			Identity: strings.TrimSpace(manifest.Publisher),
+			SignedAt: manifest.CreatedAt,
+		})
+	})
+}
+
+func verifySignaturesWith(signatures []schemaregistry.SignatureRef, digest string, keyFor func(sign.Signature) (ed25519.PublicKey, error)) error {
 	valid := 0
+	var keyErr error
 	for _, signatureRef := range signatures {
 		signature := sign.Signature{
 			Alg:          signatureRef.Alg,
@@ -465,6 +499,11 @@ func verifySignatures(signatures []schemaregistry.SignatureRef, publicKey ed2551
 		if !strings.EqualFold(signature.SignedDigest, digest) {
 			continue
 		}
+		publicKey, err := keyFor(signature)
+		if err != nil {
+			keyErr = err
+			continue
+		}
 		ok, err := sign.VerifyDigestHex(publicKey, signature)
 		if err != nil {
 			continue
@@ -474,6 +513,9 @@ func verifySignatures(signatures []schemaregistry.SignatureRef, publicKey ed2551
 		}
 	}
 	if valid == 0 {
+		if keyErr != nil {
+			return fmt.Errorf("no valid signature for manifest digest: %w", keyErr)
+		}
 		return fmt.Errorf("no valid signature for manifest digest")
 	}
 	return nil
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/ed25519"
 	"encoding/json"
+	"errors"
 	"io"
 	"net"
 	"net/http"
@@ -18,6 +19,7 @@ import (
 	"time"
 
 	schemaregistry "github.com/Clyra-AI/gait/core/schema/v1/registry"
+	"github.com/Clyra-AI/gait/core/trust"
 	jcs "github.com/Clyra-AI/proof/canon"
 	sign "github.com/Clyra-AI/proof/signing"
 )
@@ -594,3 +596,43 @@ func signableManifestDigest(manifest schemaregistry.RegistryPack) (string, error
 	}
 	return jcs.DigestJCS(raw)
 }
+
+func TestVerifyManifestSignaturesWithTrustStore(t *testing.T) {
+	keyPair, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	digest := strings.Repeat("a", 64)
+	sig, err := sign.SignDigestHex(keyPair.Private, digest)
+	if err != nil {
+		t.Fatalf("sign digest: %v", err)
+	}
+	manifest := schemaregistry.RegistryPack{
+		CreatedAt: time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC),
+		Publisher: "acme",
+		Signatures: []schemaregistry.SignatureRef{{
+			Alg:          sig.Alg,
+			KeyID:        sig.KeyID,
+			Sig:          sig.Sig,
+			SignedDigest: sig.SignedDigest,
+		}},
+	}
+	retiredAt := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	store := trust.NewStore()
+	if _, err := store.Add(keyPair.Public, trust.Key{Roles: []string{trust.RoleRegistryPublisher}, Identity: "acme", NotAfter: &retiredAt}); err != nil {
+		t.Fatalf("add trusted key: %v", err)
+	}
+
+	if err := verifyManifestSignatures(manifest, nil, store, digest); err != nil {
+		t.Fatalf("expected trusted publisher signature to verify: %v", err)
+	}
+	manifest.Publisher = "mallory"
+	if err := verifyManifestSignatures(manifest, nil, store, digest); !errors.Is(err, trust.ErrIdentity) {
+		t.Fatalf("expected publisher identity error, got %v", err)
+	}
+	manifest.Publisher = "acme"
+	manifest.CreatedAt = retiredAt.Add(time.Hour)
+	if err := verifyManifestSignatures(manifest, nil, store, digest); !errors.Is(err, trust.ErrKeyNotValid) {
+		t.Fatalf("expected retired key error, got %v", err)
+	}
+}
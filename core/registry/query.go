@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/Clyra-AI/gait/core/trust"
 )
 
 type InstalledPack struct {
@@ -27,6 +29,7 @@ type VerifyOptions struct {
 	MetadataPath       string
 	CacheDir           string
 	PublicKey          ed25519.PublicKey
+	TrustStore         *trust.Store
 	PublisherAllowlist []string
 }
 
@@ -158,7 +161,7 @@ func Verify(options VerifyOptions) (VerifyResult, error) {
 		Source:       strings.TrimSpace(manifest.Source),
 	}
 
-	if sigErr := verifySignatures(manifest.Signatures, options.PublicKey, digest); sigErr != nil {
+	if sigErr := verifyManifestSignatures(manifest, options.PublicKey, options.TrustStore, digest); sigErr != nil {
 		result.SignatureVerified = false
 		result.SignatureError = sigErr.Error()
 	} else {
@@ -20,6 +20,8 @@ type Runpack struct {
 	Intents  []schemarunpack.IntentRecord
 	Results  []schemarunpack.ResultRecord
 	Refs     schemarunpack.Refs
+	// Redactions is set when the manifest lists redactions.json.
+	Redactions *schemarunpack.Redactions
 }
 
 func ReadRunpack(path string) (Runpack, error) {
@@ -180,12 +182,29 @@ func ReadRunpack(path string) (Runpack, error) {
 		return Runpack{}, fmt.Errorf("context evidence mode required but context_set_digest is missing")
 	}
 
+	var redactions *schemarunpack.Redactions
+	for _, entry := range manifest.Files {
+		if filepath.ToSlash(entry.Path) != redactionsFileName {
+			continue
+		}
+		redactionsFile, _ := findZipFile(zipReader.File, redactionsFileName)
+		redactionsBytes, err := readZipFile(redactionsFile)
+		if err != nil {
+			return Runpack{}, fmt.Errorf("read redactions: %w", err)
+		}
+		redactions = &schemarunpack.Redactions{}
+		if err := json.Unmarshal(redactionsBytes, redactions); err != nil {
+			return Runpack{}, fmt.Errorf("parse redactions: %w", err)
+		}
+	}
+
 	return Runpack{
-		Manifest: manifest,
-		Run:      run,
-		Intents:  intents,
-		Results:  results,
-		Refs:     refs,
+		Manifest:   manifest,
+		Run:        run,
+		Intents:    intents,
+		Results:    results,
+		Refs:       refs,
+		Redactions: redactions,
 	}, nil
 }
 
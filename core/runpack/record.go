@@ -16,6 +16,8 @@ import (
 
 	"github.com/Clyra-AI/gait/core/contextproof"
 	"github.com/Clyra-AI/gait/core/fsx"
+	"github.com/Clyra-AI/gait/core/redact"
+	schemacommon "github.com/Clyra-AI/gait/core/schema/v1/common"
 	schemarunpack "github.com/Clyra-AI/gait/core/schema/v1/runpack"
 	"github.com/Clyra-AI/gait/core/zipx"
 	jcs "github.com/Clyra-AI/proof/canon"
@@ -30,6 +32,9 @@ type RecordOptions struct {
 	CaptureMode   string
 	SignKey       ed25519.PrivateKey
 	Normalization DigestNormalizationOptions
+	// Redactor, when set, replaces matched argument and result values with
+	// salted digests after digests are computed and records redactions.json.
+	Redactor *redact.Redactor
 }
 
 type DigestNormalizationOptions struct {
@@ -38,9 +43,10 @@ type DigestNormalizationOptions struct {
 }
 
 type RecordResult struct {
-	RunID    string
-	Manifest schemarunpack.Manifest
-	ZipBytes []byte
+	RunID          string
+	Manifest       schemarunpack.Manifest
+	ZipBytes       []byte
+	RedactionCount int
 }
 
 func RecordRun(options RecordOptions) (RecordResult, error) {
@@ -69,6 +75,7 @@ func RecordRun(options RecordOptions) (RecordResult, error) {
 		captureMode = "reference"
 	}
 	intents, results = applyCaptureModePayloadPolicy(intents, results, captureMode)
+	intents, results, redactions := applyRedaction(intents, results, run, options.Redactor)
 
 	runBytes, err := canonicalJSON(run)
 	if err != nil {
@@ -86,6 +93,13 @@ func RecordRun(options RecordOptions) (RecordResult, error) {
 	if err != nil {
 		return RecordResult{}, fmt.Errorf("encode refs.json: %w", err)
 	}
+	var redactionsBytes []byte
+	if redactions != nil {
+		redactionsBytes, err = canonicalJSON(redactions)
+		if err != nil {
+			return RecordResult{}, fmt.Errorf("encode redactions.json: %w", err)
+		}
+	}
 
 	manifest := schemarunpack.Manifest{
 		SchemaID:        "gait.runpack.manifest",
@@ -101,6 +115,9 @@ func RecordRun(options RecordOptions) (RecordResult, error) {
 			{Path: "refs.json", SHA256: sha256Hex(refsBytes)},
 		},
 	}
+	if redactionsBytes != nil {
+		manifest.Files = append(manifest.Files, schemarunpack.ManifestFile{Path: redactionsFileName, SHA256: sha256Hex(redactionsBytes)})
+	}
 	sort.Slice(manifest.Files, func(i, j int) bool {
 		return manifest.Files[i].Path < manifest.Files[j].Path
 	})
@@ -143,16 +160,23 @@ func RecordRun(options RecordOptions) (RecordResult, error) {
 		{Path: "results.jsonl", Data: resultsBytes, Mode: 0o644},
 		{Path: "refs.json", Data: refsBytes, Mode: 0o644},
 	}
+	if redactionsBytes != nil {
+		files = append(files, zipx.File{Path: redactionsFileName, Data: redactionsBytes, Mode: 0o644})
+	}
 	var buffer bytes.Buffer
 	if err := zipx.WriteDeterministicZip(&buffer, files); err != nil {
 		return RecordResult{}, fmt.Errorf("write runpack zip: %w", err)
 	}
 
-	return RecordResult{
+	result := RecordResult{
 		RunID:    run.RunID,
 		Manifest: manifest,
 		ZipBytes: buffer.Bytes(),
-	}, nil
+	}
+	if redactions != nil {
+		result.RedactionCount = len(redactions.Entries)
+	}
+	return result, nil
 }
 
 func WriteRunpack(path string, options RecordOptions) (RecordResult, error) {
@@ -484,3 +508,47 @@ func applyCaptureModePayloadPolicy(
 	}
 	return intents, results
 }
+
+const redactionsFileName = "redactions.json"
+
+// applyRedaction runs after digest normalization so args_digest and
+// result_digest keep describing the original payloads.
+func applyRedaction(
+	intents []schemarunpack.IntentRecord,
+	results []schemarunpack.ResultRecord,
+	run schemarunpack.Run,
+	redactor *redact.Redactor,
+) ([]schemarunpack.IntentRecord, []schemarunpack.ResultRecord, *schemarunpack.Redactions) {
+	if redactor == nil {
+		return intents, results, nil
+	}
+	manifest := &schemarunpack.Redactions{
+		SchemaID:        "gait.runpack.redactions",
+		SchemaVersion:   "1.0.0",
+		CreatedAt:       run.CreatedAt,
+		ProducerVersion: run.ProducerVersion,
+		RunID:           run.RunID,
+		Algorithm:       redact.Algorithm,
+		SaltID:          redactor.SaltID(),
+		Entries:         []schemacommon.RedactionEntry{},
+	}
+	for index := range intents {
+		args, entries := redactor.RedactObject("$.args", intents[index].Args)
+		intents[index].Args = args
+		for _, entry := range entries {
+			entry.File = "intents.jsonl"
+			entry.RecordID = intents[index].IntentID
+			manifest.Entries = append(manifest.Entries, entry)
+		}
+	}
+	for index := range results {
+		payload, entries := redactor.RedactObject("$.result", results[index].Result)
+		results[index].Result = payload
+		for _, entry := range entries {
+			entry.File = "results.jsonl"
+			entry.RecordID = results[index].IntentID
+			manifest.Entries = append(manifest.Entries, entry)
+		}
+	}
+	return intents, results, manifest
+}
@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Clyra-AI/gait/core/redact"
 	schemarunpack "github.com/Clyra-AI/gait/core/schema/v1/runpack"
 	validate "github.com/Clyra-AI/proof/schema"
 	sign "github.com/Clyra-AI/proof/signing"
@@ -564,6 +565,70 @@ func TestRecordRunRawModeRetainsRawPayloads(test *testing.T) {
 	}
 }
 
+func TestRecordRunRedactsPayloadsAndRecordsManifest(test *testing.T) {
+	test.Setenv(redact.DefaultSaltEnv, "runpack-test-salt")
+	redactor, err := redact.New(redact.Config{Paths: []string{"$.args.api_key"}, Detectors: []string{"github_token"}, PII: []string{"email"}})
+	if err != nil {
+		test.Fatalf("new redactor: %v", err)
+	}
+	run := schemarunpack.Run{
+		RunID: "run_redacted",
+		Env:   schemarunpack.RunEnv{OS: "linux", Arch: "amd64", Runtime: "go"},
+		Timeline: []schemarunpack.TimelineEvt{
+			{Event: "start", TS: time.Date(2026, time.February, 5, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	rawArgs := map[string]any{"message": "hello", "api_key": "sk-live"}
+	expectedArgsDigest, err := digestJSONValue(rawArgs)
+	if err != nil {
+		test.Fatalf("digest args: %v", err)
+	}
+	intents := []schemarunpack.IntentRecord{
+		{IntentID: "intent_secret", ToolName: "tool.echo", Args: rawArgs},
+		{IntentID: "intent_clean", ToolName: "tool.echo", Args: map[string]any{"message": "plain"}},
+	}
+	results := []schemarunpack.ResultRecord{{
+		IntentID: "intent_secret",
+		Status:   "ok",
+		Result:   map[string]any{"owner": "ops@example.com", "token": "ghp_" + strings.Repeat("x", 36)},
+	}}
+
+	result, err := RecordRun(RecordOptions{Run: run, Intents: intents, Results: results, CaptureMode: "raw", Redactor: redactor})
+	if err != nil {
+		test.Fatalf("record run: %v", err)
+	}
+	if result.RedactionCount != 3 {
+		test.Fatalf("expected three redactions, got %d", result.RedactionCount)
+	}
+	files := readZipFiles(test, result.ZipBytes)
+	for _, secret := range []string{"sk-live", "ops@example.com", "ghp_"} {
+		if bytes.Contains(files["intents.jsonl"], []byte(secret)) || bytes.Contains(files["results.jsonl"], []byte(secret)) {
+			test.Fatalf("expected %q to be redacted from payloads", secret)
+		}
+	}
+	mustValidateJSON(test, filepath.Join(repoRoot(test), "schemas", "v1", "runpack", "redactions.schema.json"), files["redactions.json"])
+
+	path := writeTempZip(test, result.ZipBytes)
+	verifyResult, err := VerifyZip(path, VerifyOptions{})
+	if err != nil || len(verifyResult.HashMismatches) > 0 || len(verifyResult.MissingFiles) > 0 {
+		test.Fatalf("expected redacted runpack to verify, result=%#v err=%v", verifyResult, err)
+	}
+	pack, err := ReadRunpack(path)
+	if err != nil {
+		test.Fatalf("read runpack: %v", err)
+	}
+	if pack.Redactions == nil || len(pack.Redactions.Entries) != 3 || pack.Intents[1].ArgsDigest == "" || pack.Intents[0].ArgsDigest != expectedArgsDigest {
+		test.Fatalf("expected redaction manifest and original args digest, got %#v", pack)
+	}
+
+	if _, err := ReplayReal(path, RealReplayOptions{AllowTools: []string{"tool.echo"}}); err == nil || !strings.Contains(err.Error(), "intent_secret") {
+		test.Fatalf("expected real replay to refuse redacted args, got %v", err)
+	}
+	if _, err := ReplayReal(path, RealReplayOptions{AllowTools: []string{"tool.other"}}); err != nil {
+		test.Fatalf("expected replay without redacted executions to succeed: %v", err)
+	}
+}
+
 func TestRecordRunRejectsDigestMismatchAgainstNormalization(test *testing.T) {
 	run := schemarunpack.Run{
 		RunID: "run_mismatch",
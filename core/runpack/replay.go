@@ -70,6 +70,11 @@ func replay(path string, mode ReplayMode, allowSet map[string]struct{}) (ReplayR
 	if mode == ReplayModeReal && strings.EqualFold(strings.TrimSpace(pack.Manifest.CaptureMode), "reference") {
 		return ReplayResult{}, fmt.Errorf("real replay requires capture_mode=raw; reference-mode runpacks strip raw tool inputs")
 	}
+	if mode == ReplayModeReal {
+		if err := rejectRedactedRealReplay(pack, allowSet); err != nil {
+			return ReplayResult{}, err
+		}
+	}
 	seenIntents := make(map[string]struct{}, len(pack.Intents))
 	for _, intent := range pack.Intents {
 		if _, exists := seenIntents[intent.IntentID]; exists {
@@ -143,6 +148,29 @@ func replay(path string, mode ReplayMode, allowSet map[string]struct{}) (ReplayR
 	}, nil
 }
 
+// rejectRedactedRealReplay refuses real replay when an intent that would be
+// executed had arguments replaced by redaction digests.
+func rejectRedactedRealReplay(pack Runpack, allowSet map[string]struct{}) error {
+	if pack.Redactions == nil {
+		return nil
+	}
+	redactedPaths := map[string][]string{}
+	for _, entry := range pack.Redactions.Entries {
+		if entry.File == "intents.jsonl" {
+			redactedPaths[entry.RecordID] = append(redactedPaths[entry.RecordID], entry.Path)
+		}
+	}
+	for _, intent := range pack.Intents {
+		if !isAllowedRealTool(allowSet, intent.ToolName) {
+			continue
+		}
+		if paths := redactedPaths[intent.IntentID]; len(paths) > 0 {
+			return fmt.Errorf("real replay refused: intent %s (%s) has redacted args %s", intent.IntentID, intent.ToolName, strings.Join(uniqueSortedStrings(paths), ","))
+		}
+	}
+	return nil
+}
+
 func isAllowedRealTool(allowSet map[string]struct{}, toolName string) bool {
 	if len(allowSet) == 0 {
 		return false
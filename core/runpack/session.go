@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/Clyra-AI/gait/core/fsx"
+	"github.com/Clyra-AI/gait/core/redact"
 	schemacommon "github.com/Clyra-AI/gait/core/schema/v1/common"
 	schemarunpack "github.com/Clyra-AI/gait/core/schema/v1/runpack"
 	jcs "github.com/Clyra-AI/proof/canon"
@@ -67,6 +68,7 @@ type SessionAppendOptions struct {
 	Violations             []string
 	SafetyInvariantVersion string
 	SafetyInvariantHash    string
+	Redactor               *redact.Redactor
 }
 
 type SessionStatus struct {
@@ -293,6 +295,15 @@ func StartSession(path string, opts SessionStartOptions) (SessionStatus, error)
 	return GetSessionStatus(normalizedPath)
 }
 
+// sessionEventProtectedFields are never redacted because chain verification
+// and checkpoint runpacks depend on them.
+var sessionEventProtectedFields = []string{
+	"schema_id", "schema_version", "created_at", "producer_version", "session_id", "run_id",
+	"sequence", "intent_id", "intent_digest", "policy_digest", "policy_id", "policy_version",
+	"matched_rule_ids", "trace_id", "verdict", "relationship", "safety_invariant_version",
+	"safety_invariant_hash",
+}
+
 // AppendSessionEvent appends one deterministic event entry to the journal.
 func AppendSessionEvent(path string, opts SessionAppendOptions) (schemarunpack.SessionEvent, error) {
 	normalizedPath, err := normalizeOutputPath(path)
@@ -349,6 +360,17 @@ func AppendSessionEvent(path string, opts SessionAppendOptions) (schemarunpack.S
 			SafetyInvariantVersion: strings.TrimSpace(opts.SafetyInvariantVersion),
 			SafetyInvariantHash:    strings.ToLower(strings.TrimSpace(opts.SafetyInvariantHash)),
 		}
+		if opts.Redactor != nil {
+			redacted := schemarunpack.SessionEvent{}
+			entries, redactErr := opts.Redactor.RedactStruct(appended, &redacted, sessionEventProtectedFields...)
+			if redactErr != nil {
+				return redactErr
+			}
+			if len(entries) > 0 {
+				redacted.Redaction = opts.Redactor.Summary(entries)
+				appended = redacted
+			}
+		}
 		record := sessionJournalRecord{
 			RecordType: "event",
 			Event:      &appended,
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Clyra-AI/gait/core/redact"
 	schemacommon "github.com/Clyra-AI/gait/core/schema/v1/common"
 	schemarunpack "github.com/Clyra-AI/gait/core/schema/v1/runpack"
 )
@@ -239,6 +240,53 @@ func TestSessionJournalValidationErrors(t *testing.T) {
 	}
 }
 
+func TestAppendSessionEventRedactsUnprotectedFields(t *testing.T) {
+	workDir := t.TempDir()
+	journalPath := filepath.Join(workDir, "sessions", "redact.journal.jsonl")
+	now := time.Date(2026, time.February, 11, 1, 0, 0, 0, time.UTC)
+	t.Setenv(redact.DefaultSaltEnv, "session-salt")
+	redactor, err := redact.New(redact.Config{PII: []string{"email"}})
+	if err != nil {
+		t.Fatalf("new redactor: %v", err)
+	}
+
+	if _, err := StartSession(journalPath, SessionStartOptions{SessionID: "sess_redact", RunID: "run_redact", Now: now}); err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	event, err := AppendSessionEvent(journalPath, SessionAppendOptions{
+		CreatedAt:  now.Add(time.Second),
+		IntentID:   "intent_1",
+		ToolName:   "tool.email",
+		Verdict:    "block",
+		Violations: []string{"recipient bob@example.com not allowed"},
+		Redactor:   redactor,
+	})
+	if err != nil {
+		t.Fatalf("append session event: %v", err)
+	}
+	if event.Redaction == nil || len(event.Redaction.Entries) != 1 {
+		t.Fatalf("expected one redaction entry, got %#v", event.Redaction)
+	}
+	if event.Redaction.SaltID != redactor.SaltID() || event.Redaction.Entries[0].Rule != "pii:email" {
+		t.Fatalf("unexpected redaction summary: %#v", event.Redaction)
+	}
+	if event.IntentID != "intent_1" || event.Verdict != "block" {
+		t.Fatalf("protected fields must not change: %#v", event)
+	}
+
+	journal, err := ReadSessionJournal(journalPath)
+	if err != nil {
+		t.Fatalf("read session journal: %v", err)
+	}
+	if len(journal.Events) != 1 {
+		t.Fatalf("expected one journal event, got %d", len(journal.Events))
+	}
+	violation := strings.Join(journal.Events[0].Violations, ",")
+	if strings.Contains(violation, "bob@example.com") || !strings.Contains(violation, "redacted:hmac-sha256:") {
+		t.Fatalf("expected email to be redacted in journal, got %q", violation)
+	}
+}
+
 func TestSessionInputAndChainValidation(t *testing.T) {
 	workDir := t.TempDir()
 	journalPath := filepath.Join(workDir, "sessions", "input.journal.jsonl")
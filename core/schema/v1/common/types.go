@@ -45,3 +45,20 @@ type AgentLineage struct {
 	DelegatedBy        string `json:"delegated_by,omitempty"`
 	DelegationRecordID string `json:"delegation_record_id,omitempty"`
 }
+
+// RedactionEntry records one value replaced by a salted digest before an
+// artifact was written.
+type RedactionEntry struct {
+	File     string `json:"file,omitempty"`
+	RecordID string `json:"record_id,omitempty"`
+	Path     string `json:"path"`
+	Rule     string `json:"rule"`
+	Class    string `json:"class"`
+	Digest   string `json:"digest"`
+}
+
+type RedactionSummary struct {
+	Algorithm string           `json:"algorithm"`
+	SaltID    string           `json:"salt_id"`
+	Entries   []RedactionEntry `json:"entries"`
+}
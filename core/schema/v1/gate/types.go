@@ -72,6 +72,7 @@ type TraceRecord struct {
 	MCPTrust                   *MCPTrustDecision                  `json:"mcp_trust,omitempty"`
 	Relationship               *schemacommon.RelationshipEnvelope `json:"relationship,omitempty"`
 	SkillProvenance            *SkillProvenance                   `json:"skill_provenance,omitempty"`
+	Redaction                  *schemacommon.RedactionSummary     `json:"redaction,omitempty"`
 	Signature                  *Signature                         `json:"signature,omitempty"`
 }
 
@@ -135,7 +136,9 @@ type KillSwitchState struct {
 	CreatedAt       time.Time         `json:"created_at"`
 	UpdatedAt       time.Time         `json:"updated_at"`
 	ProducerVersion string            `json:"producer_version"`
+	Revision        int64             `json:"revision,omitempty"`
 	Entries         []KillSwitchEntry `json:"entries"`
+	Signature       *Signature        `json:"signature,omitempty"`
 }
 
 type KillSwitchEntry struct {
@@ -355,9 +358,12 @@ type PolicyExplain struct {
 }
 
 type PolicyExplainRule struct {
-	Name     string `json:"name"`
-	Priority int    `json:"priority"`
-	Effect   string `json:"effect"`
+	Name          string   `json:"name"`
+	Priority      int      `json:"priority"`
+	Effect        string   `json:"effect"`
+	Source        string   `json:"source,omitempty"`
+	Locked        bool     `json:"locked,omitempty"`
+	ArgPredicates []string `json:"arg_predicates,omitempty"`
 }
 
 type PolicyCredentialState struct {
@@ -465,6 +471,40 @@ type ApprovalAuditRecord struct {
 	Entries           []ApprovalAuditEntry               `json:"entries"`
 }
 
+type ApprovalRequest struct {
+	SchemaID                 string     `json:"schema_id"`
+	SchemaVersion            string     `json:"schema_version"`
+	CreatedAt                time.Time  `json:"created_at"`
+	ProducerVersion          string     `json:"producer_version"`
+	RequestID                string     `json:"request_id"`
+	TraceID                  string     `json:"trace_id,omitempty"`
+	ToolName                 string     `json:"tool_name"`
+	Identity                 string     `json:"identity,omitempty"`
+	IntentDigest             string     `json:"intent_digest"`
+	PolicyDigest             string     `json:"policy_digest"`
+	DelegationBindingDigest  string     `json:"delegation_binding_digest,omitempty"`
+	RequiredScope            []string   `json:"required_scope"`
+	MinApprovals             int        `json:"min_approvals"`
+	RequireDistinctApprovers bool       `json:"require_distinct_approvers,omitempty"`
+	MatchedRule              string     `json:"matched_rule,omitempty"`
+	ReasonCodes              []string   `json:"reason_codes,omitempty"`
+	ExpiresAt                time.Time  `json:"expires_at"`
+	Signature                *Signature `json:"signature,omitempty"`
+}
+
+type ApprovalDecision struct {
+	SchemaID         string    `json:"schema_id"`
+	SchemaVersion    string    `json:"schema_version"`
+	CreatedAt        time.Time `json:"created_at"`
+	ProducerVersion  string    `json:"producer_version"`
+	RequestID        string    `json:"request_id"`
+	Decision         string    `json:"decision"`
+	ApproverIdentity string    `json:"approver_identity"`
+	ReasonCode       string    `json:"reason_code"`
+	TokenID          string    `json:"token_id,omitempty"`
+	TokenExpiresAt   time.Time `json:"token_expires_at,omitempty"`
+}
+
 type BrokerCredentialRecord struct {
 	SchemaID             string    `json:"schema_id"`
 	SchemaVersion        string    `json:"schema_version"`
@@ -11,8 +11,11 @@ type PackManifest struct {
 	RunID           string      `json:"run_id"`
 	CaseID          string      `json:"case_id,omitempty"`
 	TemplateID      string      `json:"template_id,omitempty"`
+	TemplateVersion string      `json:"template_version,omitempty"`
+	TemplateDigest  string      `json:"template_digest,omitempty"`
 	GeneratedAt     time.Time   `json:"generated_at"`
 	ControlIndex    []Control   `json:"control_index,omitempty"`
+	ControlGaps     []string    `json:"control_gaps,omitempty"`
 	EvidencePtrs    []Evidence  `json:"evidence_pointers,omitempty"`
 	IncidentWindow  *Window     `json:"incident_window,omitempty"`
 	Rendered        []Rendered  `json:"rendered_artifacts,omitempty"`
@@ -29,6 +32,7 @@ type PackEntry struct {
 type Control struct {
 	ControlID     string   `json:"control_id"`
 	Title         string   `json:"title"`
+	Required      bool     `json:"required,omitempty"`
 	EvidencePaths []string `json:"evidence_paths"`
 }
 
@@ -17,3 +17,90 @@ type PolicyTestResult struct {
 	DelegationDepth   int       `json:"delegation_depth,omitempty"`
 	DelegationScope   string    `json:"delegation_scope,omitempty"`
 }
+
+type PolicyCoverageReport struct {
+	SchemaID            string               `json:"schema_id"`
+	SchemaVersion       string               `json:"schema_version"`
+	CreatedAt           time.Time            `json:"created_at"`
+	ProducerVersion     string               `json:"producer_version"`
+	PolicyDigest        string               `json:"policy_digest"`
+	DefaultVerdict      string               `json:"default_verdict"`
+	SamplesTotal        int                  `json:"samples_total"`
+	SamplesEvaluated    int                  `json:"samples_evaluated"`
+	DefaultHits         int                  `json:"default_hits"`
+	ToolsObserved       int                  `json:"tools_observed"`
+	DefaultOnlyTools    []string             `json:"default_only_tools"`
+	DefaultOnlyFraction float64              `json:"default_only_fraction"`
+	Rules               []PolicyRuleCoverage `json:"rules"`
+	UnreachableRules    []string             `json:"unreachable_rules"`
+	ShadowedRules       []string             `json:"shadowed_rules"`
+	Errors              []PolicyReplayError  `json:"errors,omitempty"`
+}
+
+type PolicyRuleCoverage struct {
+	Name           string   `json:"name"`
+	Effect         string   `json:"effect"`
+	Priority       int      `json:"priority"`
+	Status         string   `json:"status"`
+	Hits           int      `json:"hits"`
+	MatchedSamples int      `json:"matched_samples"`
+	ShadowedBy     []string `json:"shadowed_by,omitempty"`
+	Tools          []string `json:"tools,omitempty"`
+}
+
+type PolicyReplayError struct {
+	Source string `json:"source"`
+	Error  string `json:"error"`
+}
+
+type PolicyDiffReport struct {
+	SchemaID         string               `json:"schema_id"`
+	SchemaVersion    string               `json:"schema_version"`
+	CreatedAt        time.Time            `json:"created_at"`
+	ProducerVersion  string               `json:"producer_version"`
+	BasePolicyDigest string               `json:"base_policy_digest"`
+	HeadPolicyDigest string               `json:"head_policy_digest"`
+	PolicyChanges    []string             `json:"policy_changes"`
+	AddedRules       []string             `json:"added_rules"`
+	RemovedRules     []string             `json:"removed_rules"`
+	ChangedRules     []PolicyRuleChange   `json:"changed_rules"`
+	SamplesTotal     int                  `json:"samples_total"`
+	SamplesEvaluated int                  `json:"samples_evaluated"`
+	ChangedIntents   int                  `json:"changed_intents"`
+	MorePermissive   int                  `json:"more_permissive"`
+	HeadErrors       int                  `json:"head_errors"`
+	Changes          []PolicyIntentChange `json:"changes"`
+	Groups           []PolicyDiffGroup    `json:"groups"`
+	Errors           []PolicyReplayError  `json:"errors,omitempty"`
+}
+
+type PolicyRuleChange struct {
+	Name   string   `json:"name"`
+	Fields []string `json:"fields"`
+}
+
+type PolicyIntentChange struct {
+	Source                       string   `json:"source"`
+	ToolName                     string   `json:"tool_name"`
+	IntentDigest                 string   `json:"intent_digest,omitempty"`
+	Changed                      []string `json:"changed"`
+	MorePermissive               bool     `json:"more_permissive"`
+	Rules                        []string `json:"rules"`
+	BaseVerdict                  string   `json:"base_verdict"`
+	HeadVerdict                  string   `json:"head_verdict"`
+	BaseReasonCodes              []string `json:"base_reason_codes"`
+	HeadReasonCodes              []string `json:"head_reason_codes"`
+	BaseMatchedRule              string   `json:"base_matched_rule,omitempty"`
+	HeadMatchedRule              string   `json:"head_matched_rule,omitempty"`
+	BaseMinApprovals             int      `json:"base_min_approvals,omitempty"`
+	HeadMinApprovals             int      `json:"head_min_approvals,omitempty"`
+	BaseRequireDistinctApprovers bool     `json:"base_require_distinct_approvers,omitempty"`
+	HeadRequireDistinctApprovers bool     `json:"head_require_distinct_approvers,omitempty"`
+}
+
+type PolicyDiffGroup struct {
+	Rule           string   `json:"rule"`
+	Intents        int      `json:"intents"`
+	MorePermissive int      `json:"more_permissive"`
+	Sources        []string `json:"sources"`
+}
@@ -77,6 +77,19 @@ type ResultRecord struct {
 	Result          map[string]any `json:"result,omitempty"`
 }
 
+// Redactions is the redactions.json manifest of a runpack whose payloads were
+// redacted before recording.
+type Redactions struct {
+	SchemaID        string                        `json:"schema_id"`
+	SchemaVersion   string                        `json:"schema_version"`
+	CreatedAt       time.Time                     `json:"created_at"`
+	ProducerVersion string                        `json:"producer_version"`
+	RunID           string                        `json:"run_id"`
+	Algorithm       string                        `json:"algorithm"`
+	SaltID          string                        `json:"salt_id"`
+	Entries         []schemacommon.RedactionEntry `json:"entries"`
+}
+
 type Refs struct {
 	SchemaID            string       `json:"schema_id"`
 	SchemaVersion       string       `json:"schema_version"`
@@ -138,6 +151,7 @@ type SessionEvent struct {
 	Relationship           *schemacommon.RelationshipEnvelope `json:"relationship,omitempty"`
 	SafetyInvariantVersion string                             `json:"safety_invariant_version,omitempty"`
 	SafetyInvariantHash    string                             `json:"safety_invariant_hash,omitempty"`
+	Redaction              *schemacommon.RedactionSummary     `json:"redaction,omitempty"`
 }
 
 type SessionCheckpoint struct {
@@ -17,14 +17,36 @@ type InventorySnapshot struct {
 }
 
 type InventoryItem struct {
-	ID           string                             `json:"id"`
-	Kind         string                             `json:"kind"`
-	Name         string                             `json:"name"`
-	Locator      string                             `json:"locator"`
-	RiskLevel    string                             `json:"risk_level,omitempty"`
-	Tags         []string                           `json:"tags,omitempty"`
-	LastSeenRun  string                             `json:"last_seen_run,omitempty"`
-	Relationship *schemacommon.RelationshipEnvelope `json:"relationship,omitempty"`
+	ID                string                             `json:"id"`
+	Kind              string                             `json:"kind"`
+	Name              string                             `json:"name"`
+	Locator           string                             `json:"locator"`
+	RiskLevel         string                             `json:"risk_level,omitempty"`
+	Tags              []string                           `json:"tags,omitempty"`
+	InputFields       []string                           `json:"input_fields,omitempty"`
+	InputSchemaDigest string                             `json:"input_schema_digest,omitempty"`
+	Annotations       *ToolAnnotations                   `json:"annotations,omitempty"`
+	Server            *ServerIdentity                    `json:"server,omitempty"`
+	LastSeenRun       string                             `json:"last_seen_run,omitempty"`
+	Relationship      *schemacommon.RelationshipEnvelope `json:"relationship,omitempty"`
+}
+
+// ToolAnnotations are the hints a live MCP server declares for a tool; nil
+// means the server did not declare the hint.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"read_only_hint,omitempty"`
+	DestructiveHint *bool  `json:"destructive_hint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotent_hint,omitempty"`
+	OpenWorldHint   *bool  `json:"open_world_hint,omitempty"`
+}
+
+// ServerIdentity is what a live MCP server reported during initialize.
+type ServerIdentity struct {
+	Name            string `json:"name,omitempty"`
+	Version         string `json:"version,omitempty"`
+	ProtocolVersion string `json:"protocol_version,omitempty"`
+	Endpoint        string `json:"endpoint,omitempty"`
 }
 
 type AdoptionEvent struct {
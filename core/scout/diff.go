@@ -27,13 +27,14 @@ type DiffItem struct {
 }
 
 type DiffChange struct {
-	ID              string `json:"id"`
-	Kind            string `json:"kind"`
-	Name            string `json:"name"`
-	BeforeLocator   string `json:"before_locator"`
-	AfterLocator    string `json:"after_locator"`
-	BeforeRiskLevel string `json:"before_risk_level,omitempty"`
-	AfterRiskLevel  string `json:"after_risk_level,omitempty"`
+	ID              string   `json:"id"`
+	Kind            string   `json:"kind"`
+	Name            string   `json:"name"`
+	BeforeLocator   string   `json:"before_locator"`
+	AfterLocator    string   `json:"after_locator"`
+	BeforeRiskLevel string   `json:"before_risk_level,omitempty"`
+	AfterRiskLevel  string   `json:"after_risk_level,omitempty"`
+	Fields          []string `json:"fields,omitempty"`
 }
 
 func DiffSnapshots(left schemascout.InventorySnapshot, right schemascout.InventorySnapshot) SnapshotDiff {
@@ -56,7 +57,8 @@ func DiffSnapshots(left schemascout.InventorySnapshot, right schemascout.Invento
 			added = append(added, toDiffItem(rightItem))
 			continue
 		}
-		if inventoryItemEquivalent(leftItem, rightItem) {
+		fields := inventoryItemChanges(leftItem, rightItem)
+		if len(fields) == 0 {
 			continue
 		}
 		changed = append(changed, DiffChange{
@@ -67,6 +69,7 @@ func DiffSnapshots(left schemascout.InventorySnapshot, right schemascout.Invento
 			AfterLocator:    rightItem.Locator,
 			BeforeRiskLevel: leftItem.RiskLevel,
 			AfterRiskLevel:  rightItem.RiskLevel,
+			Fields:          fields,
 		})
 	}
 	for id, leftItem := range leftIndex {
@@ -97,13 +100,45 @@ func DiffSnapshots(left schemascout.InventorySnapshot, right schemascout.Invento
 	}
 }
 
-func inventoryItemEquivalent(left schemascout.InventoryItem, right schemascout.InventoryItem) bool {
-	if left.Kind != right.Kind || left.Name != right.Name || left.Locator != right.Locator || left.RiskLevel != right.RiskLevel {
-		return false
+// inventoryItemChanges lists the fields that differ between two versions of
+// the same item, including the input schema digest and annotations a live MCP
+// server reports, so silent tool changes show up in the diff.
+func inventoryItemChanges(left schemascout.InventoryItem, right schemascout.InventoryItem) []string {
+	fields := make([]string, 0)
+	if left.Kind != right.Kind {
+		fields = append(fields, "kind")
 	}
-	leftTags, _ := json.Marshal(uniqueSorted(left.Tags))
-	rightTags, _ := json.Marshal(uniqueSorted(right.Tags))
-	return string(leftTags) == string(rightTags)
+	if left.Name != right.Name {
+		fields = append(fields, "name")
+	}
+	if left.Locator != right.Locator {
+		fields = append(fields, "locator")
+	}
+	if left.RiskLevel != right.RiskLevel {
+		fields = append(fields, "risk_level")
+	}
+	if !jsonEqual(uniqueSorted(left.Tags), uniqueSorted(right.Tags)) {
+		fields = append(fields, "tags")
+	}
+	if !jsonEqual(uniqueSorted(left.InputFields), uniqueSorted(right.InputFields)) {
+		fields = append(fields, "input_fields")
+	}
+	if left.InputSchemaDigest != right.InputSchemaDigest {
+		fields = append(fields, "input_schema_digest")
+	}
+	if !jsonEqual(left.Annotations, right.Annotations) {
+		fields = append(fields, "annotations")
+	}
+	if !jsonEqual(left.Server, right.Server) {
+		fields = append(fields, "server")
+	}
+	return fields
+}
+
+func jsonEqual(left any, right any) bool {
+	leftJSON, _ := json.Marshal(left)
+	rightJSON, _ := json.Marshal(right)
+	return string(leftJSON) == string(rightJSON)
 }
 
 func toDiffItem(item schemascout.InventoryItem) DiffItem {
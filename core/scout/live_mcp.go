@@ -0,0 +1,330 @@
+package scout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/mcp"
+	schemascout "github.com/Clyra-AI/gait/core/schema/v1/scout"
+	jcs "github.com/Clyra-AI/proof/canon"
+)
+
+const (
+	liveMCPTag            = "live"
+	liveMCPUnreachableTag = "live:unreachable"
+	defaultLiveMCPTimeout = 10 * time.Second
+)
+
+// MCPServerConfig is an MCP server entry declared in a client config file
+// (mcpServers / servers). Stdio servers set Command; HTTP servers set URL.
+type MCPServerConfig struct {
+	Name    string
+	Locator string
+	Command string
+	Args    []string
+	Env     map[string]string
+	Dir     string
+	URL     string
+	Headers map[string]string
+}
+
+func (config MCPServerConfig) Endpoint() string {
+	if config.URL != "" {
+		return config.URL
+	}
+	return strings.TrimSpace(strings.Join(append([]string{config.Command}, config.Args...), " "))
+}
+
+// LiveMCPProvider launches or connects to every MCP server declared in config
+// files under the snapshot roots and records the tools each server lists, so
+// the snapshot reflects what servers expose rather than what config files
+// name. Servers that cannot be reached are kept with a live:unreachable tag.
+type LiveMCPProvider struct {
+	Options SnapshotOptions
+	Timeout time.Duration
+	Connect func(context.Context, MCPServerConfig) (mcp.Upstream, error)
+}
+
+func (provider LiveMCPProvider) Snapshot(ctx context.Context, req SnapshotRequest) (schemascout.InventorySnapshot, error) {
+	roots := normalizeRoots(req.Roots)
+	configs, err := DiscoverMCPServerConfigs(roots, req.Include, req.Exclude)
+	if err != nil {
+		return schemascout.InventorySnapshot{}, err
+	}
+	items := make(map[string]schemascout.InventoryItem)
+	for _, config := range configs {
+		serverItems, err := provider.inspectServer(ctx, config)
+		if err != nil {
+			return schemascout.InventorySnapshot{}, err
+		}
+		addItems(items, serverItems)
+	}
+	return buildInventorySnapshot(provider.Options, roots, items)
+}
+
+// inspectServer only returns an error when ctx itself is done; a server that
+// fails to start or answer is recorded as unreachable.
+func (provider LiveMCPProvider) inspectServer(ctx context.Context, config MCPServerConfig) ([]schemascout.InventoryItem, error) {
+	serverItem := makeMCPServerItem(config.Locator, config.Name)
+	unreachable := func() ([]schemascout.InventoryItem, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		serverItem.Tags = mergeTags(serverItem.Tags, []string{liveMCPUnreachableTag})
+		return []schemascout.InventoryItem{serverItem}, nil
+	}
+
+	timeout := provider.Timeout
+	if timeout <= 0 {
+		timeout = defaultLiveMCPTimeout
+	}
+	serverCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	connect := provider.Connect
+	if connect == nil {
+		connect = ConnectMCPServer
+	}
+	upstream, err := connect(serverCtx, config)
+	if err != nil {
+		return unreachable()
+	}
+	defer func() {
+		_ = upstream.Close()
+	}()
+	listed, err := mcp.ListServerTools(serverCtx, upstream)
+	if err != nil {
+		return unreachable()
+	}
+
+	identity := schemascout.ServerIdentity{
+		Name:            listed.Name,
+		Version:         listed.Version,
+		ProtocolVersion: listed.ProtocolVersion,
+		Endpoint:        config.Endpoint(),
+	}
+	serverItem.Tags = mergeTags(serverItem.Tags, []string{liveMCPTag})
+	serverItem.Server = &identity
+	items := []schemascout.InventoryItem{serverItem}
+	for _, tool := range listed.Tools {
+		if strings.TrimSpace(tool.Name) == "" {
+			continue
+		}
+		items = append(items, makeLiveToolItem(config, identity, tool))
+	}
+	return dedupeItems(items), nil
+}
+
+func makeLiveToolItem(config MCPServerConfig, identity schemascout.ServerIdentity, tool mcp.ListedTool) schemascout.InventoryItem {
+	name := strings.TrimSpace(tool.Name)
+	item := makeDeclaredToolItem(config.Locator, name, "mcp:"+normalizeIdentifier(config.Name), tool.Description, jsonSchemaPropertyNames(tool.InputSchema))
+	item.Tags = mergeTags(item.Tags, []string{"mcp", liveMCPTag})
+	item.Server = &identity
+	if len(tool.InputSchema) > 0 {
+		if digest, err := jcs.DigestJCS(tool.InputSchema); err == nil {
+			item.InputSchemaDigest = digest
+		}
+	}
+	title := strings.TrimSpace(tool.Title)
+	if tool.Annotations != nil {
+		if title == "" {
+			title = strings.TrimSpace(tool.Annotations.Title)
+		}
+		item.Annotations = &schemascout.ToolAnnotations{
+			Title:           title,
+			ReadOnlyHint:    tool.Annotations.ReadOnlyHint,
+			DestructiveHint: tool.Annotations.DestructiveHint,
+			IdempotentHint:  tool.Annotations.IdempotentHint,
+			OpenWorldHint:   tool.Annotations.OpenWorldHint,
+		}
+		readOnly := tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint
+		destructive := tool.Annotations.DestructiveHint != nil && *tool.Annotations.DestructiveHint
+		if destructive && !readOnly && riskScore(item.RiskLevel) < riskScore("high") {
+			item.RiskLevel = "high"
+		}
+	} else if title != "" {
+		item.Annotations = &schemascout.ToolAnnotations{Title: title}
+	}
+	return item
+}
+
+func jsonSchemaPropertyNames(schema json.RawMessage) []string {
+	if len(schema) == 0 {
+		return nil
+	}
+	var decoded struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(schema, &decoded); err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(decoded.Properties))
+	for name := range decoded.Properties {
+		names = append(names, name)
+	}
+	return names
+}
+
+// ConnectMCPServer starts a stdio server or opens an HTTP session for config.
+func ConnectMCPServer(_ context.Context, config MCPServerConfig) (mcp.Upstream, error) {
+	if config.URL != "" {
+		return mcp.NewHTTPUpstream(mcp.HTTPUpstreamOptions{URL: config.URL, Headers: config.Headers})
+	}
+	env := make([]string, 0, len(config.Env))
+	for key, value := range config.Env {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+	upstream, err := mcp.StartStdioUpstream(mcp.StdioUpstreamOptions{
+		Command: config.Command,
+		Args:    config.Args,
+		Env:     env,
+		Dir:     config.Dir,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return upstream, nil
+}
+
+// DiscoverMCPServerConfigs returns the launchable MCP server entries declared in
+// JSON and YAML config files under roots, sorted by name and locator.
+func DiscoverMCPServerConfigs(roots []string, include []string, exclude []string) ([]MCPServerConfig, error) {
+	includePatterns := normalizePatterns(include)
+	excludePatterns := normalizePatterns(exclude)
+	configs := make([]MCPServerConfig, 0)
+	seen := map[string]struct{}{}
+	for _, root := range normalizeRoots(roots) {
+		err := walkWorkspace(root, includePatterns, excludePatterns, func(path string) error {
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".json", ".yaml", ".yml":
+			default:
+				return nil
+			}
+			payload, _, err := readMCPConfigPayload(path)
+			if err != nil || payload == nil {
+				return err
+			}
+			for _, config := range collectMCPServerConfigs(path, payload) {
+				key := config.Name + "\x00" + config.Endpoint()
+				if _, exists := seen[key]; exists {
+					continue
+				}
+				seen[key] = struct{}{}
+				configs = append(configs, config)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		if configs[i].Name != configs[j].Name {
+			return configs[i].Name < configs[j].Name
+		}
+		return configs[i].Locator < configs[j].Locator
+	})
+	return configs, nil
+}
+
+func collectMCPServerConfigs(path string, value any) []MCPServerConfig {
+	configs := make([]MCPServerConfig, 0)
+	switch typed := normalizeConfigValue(value).(type) {
+	case map[string]any:
+		for key, nested := range typed {
+			lowerKey := strings.ToLower(strings.TrimSpace(key))
+			if lowerKey == "mcpservers" || lowerKey == "servers" {
+				if servers, ok := normalizeConfigValue(nested).(map[string]any); ok {
+					for name, entry := range servers {
+						if config, ok := parseMCPServerConfig(path, name, entry); ok {
+							configs = append(configs, config)
+						}
+					}
+				}
+			}
+			configs = append(configs, collectMCPServerConfigs(path, nested)...)
+		}
+	case []any:
+		for _, nested := range typed {
+			configs = append(configs, collectMCPServerConfigs(path, nested)...)
+		}
+	}
+	return configs
+}
+
+func parseMCPServerConfig(path string, name string, value any) (MCPServerConfig, bool) {
+	entry, ok := normalizeConfigValue(value).(map[string]any)
+	trimmedName := strings.TrimSpace(name)
+	if !ok || trimmedName == "" {
+		return MCPServerConfig{}, false
+	}
+	config := MCPServerConfig{
+		Name:    trimmedName,
+		Locator: path,
+		Command: configString(entry, "command"),
+		Args:    configStrings(entry["args"]),
+		Env:     configStringMap(entry["env"]),
+		Dir:     configString(entry, "cwd"),
+		URL:     configString(entry, "url"),
+		Headers: configStringMap(entry["headers"]),
+	}
+	if config.URL == "" {
+		config.URL = configString(entry, "serverUrl")
+	}
+	if config.URL == "" && config.Command == "" {
+		return MCPServerConfig{}, false
+	}
+	if config.Dir == "" {
+		config.Dir = filepath.Dir(filepath.FromSlash(path))
+	} else if !filepath.IsAbs(config.Dir) {
+		config.Dir = filepath.Join(filepath.Dir(filepath.FromSlash(path)), config.Dir)
+	}
+	return config, true
+}
+
+func normalizeConfigValue(value any) any {
+	if typed, ok := value.(map[any]any); ok {
+		normalized := make(map[string]any, len(typed))
+		for key, nested := range typed {
+			normalized[fmt.Sprintf("%v", key)] = nested
+		}
+		return normalized
+	}
+	return value
+}
+
+func configString(entry map[string]any, key string) string {
+	if value, ok := entry[key].(string); ok {
+		return strings.TrimSpace(value)
+	}
+	return ""
+}
+
+func configStrings(value any) []string {
+	list, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		values = append(values, fmt.Sprintf("%v", item))
+	}
+	return values
+}
+
+func configStringMap(value any) map[string]string {
+	entries, ok := normalizeConfigValue(value).(map[string]any)
+	if !ok || len(entries) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(entries))
+	for key, item := range entries {
+		values[key] = fmt.Sprintf("%v", item)
+	}
+	return values
+}
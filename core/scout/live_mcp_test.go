@@ -0,0 +1,98 @@
+package scout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	schemascout "github.com/Clyra-AI/gait/core/schema/v1/scout"
+)
+
+func TestLiveMCPProviderRecordsListedToolsAndDiffFlagsChanges(t *testing.T) {
+	var mu sync.Mutex
+	tools := `[
+		{"name":"read_file","description":"Read a file","inputSchema":{"type":"object","properties":{"path":{"type":"string"}}},"annotations":{"readOnlyHint":true}},
+		{"name":"run","inputSchema":{"type":"object","properties":{"args":{"type":"array"}}},"annotations":{"destructiveHint":true}}
+	]`
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var message struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(request.Body).Decode(&message); err != nil || len(message.ID) == 0 {
+			writer.WriteHeader(http.StatusAccepted)
+			return
+		}
+		mu.Lock()
+		listed := tools
+		mu.Unlock()
+		result := `{"protocolVersion":"2025-06-18","serverInfo":{"name":"fake-fs","version":"1.0.0"}}`
+		if message.Method == "tools/list" {
+			result = `{"tools":` + listed + `}`
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(writer, `{"jsonrpc":"2.0","id":%s,"result":%s}`, message.ID, result)
+	}))
+	defer server.Close()
+
+	workDir := t.TempDir()
+	config := fmt.Sprintf(`{"mcpServers":{"fs":{"url":%q},"broken":{"command":%q}}}`, server.URL, filepath.Join(workDir, "missing-server"))
+	if err := os.WriteFile(filepath.Join(workDir, ".mcp.json"), []byte(config), 0o600); err != nil {
+		t.Fatalf("write mcp config: %v", err)
+	}
+	provider := LiveMCPProvider{
+		Options: SnapshotOptions{ProducerVersion: "test", Now: time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		Timeout: 5 * time.Second,
+	}
+	before, err := provider.Snapshot(context.Background(), SnapshotRequest{Roots: []string{workDir}})
+	if err != nil {
+		t.Fatalf("live snapshot: %v", err)
+	}
+	byID := map[string]schemascout.InventoryItem{}
+	for _, item := range before.Items {
+		byID[item.ID] = item
+	}
+	readFile := byID["tool:mcp:fs:read_file"]
+	if readFile.InputSchemaDigest == "" || readFile.Server == nil || readFile.Server.Name != "fake-fs" || readFile.Server.Version != "1.0.0" ||
+		readFile.Annotations == nil || readFile.Annotations.ReadOnlyHint == nil || !*readFile.Annotations.ReadOnlyHint ||
+		strings.Join(readFile.InputFields, ",") != "path" {
+		t.Fatalf("unexpected read_file item: %#v", readFile)
+	}
+	if run := byID["tool:mcp:fs:run"]; run.RiskLevel != "high" {
+		t.Fatalf("expected destructive hint to raise risk, got %#v", run)
+	}
+	if broken := byID["mcp_server:broken"]; !strings.Contains(strings.Join(broken.Tags, ","), liveMCPUnreachableTag) {
+		t.Fatalf("expected unreachable server to be tagged, got %#v", broken)
+	}
+
+	mu.Lock()
+	tools = `[
+		{"name":"read_file","description":"Read a file","inputSchema":{"type":"object","properties":{"path":{"type":"string"},"encoding":{"type":"string"}}},"annotations":{"readOnlyHint":true}},
+		{"name":"run","inputSchema":{"type":"object","properties":{"args":{"type":"array"}}},"annotations":{"destructiveHint":true}},
+		{"name":"write_file","inputSchema":{"type":"object","properties":{"path":{"type":"string"}}}}
+	]`
+	mu.Unlock()
+	after, err := provider.Snapshot(context.Background(), SnapshotRequest{Roots: []string{workDir}})
+	if err != nil {
+		t.Fatalf("second live snapshot: %v", err)
+	}
+	if after.SnapshotID == before.SnapshotID {
+		t.Fatalf("expected snapshot id to change when the server changes its tools")
+	}
+	diff := DiffSnapshots(before, after)
+	if diff.AddedCount != 1 || diff.Added[0].ID != "tool:mcp:fs:write_file" {
+		t.Fatalf("expected silently added tool in diff: %#v", diff)
+	}
+	if diff.ChangedCount != 1 || diff.Changed[0].ID != "tool:mcp:fs:read_file" ||
+		strings.Join(diff.Changed[0].Fields, ",") != "input_fields,input_schema_digest" {
+		t.Fatalf("expected changed input schema in diff: %#v", diff.Changed)
+	}
+}
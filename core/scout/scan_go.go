@@ -0,0 +1,259 @@
+package scout
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	schemascout "github.com/Clyra-AI/gait/core/schema/v1/scout"
+)
+
+const mcpGoToolTag = "framework:mcp_go"
+
+var mcpGoImportPrefixes = []string{
+	"github.com/mark3labs/mcp-go",
+	"github.com/modelcontextprotocol/go-sdk",
+}
+
+// mcpGoFieldOptions are the mcp-go NewTool options that declare an input
+// property whose name is the first argument.
+var mcpGoFieldOptions = map[string]struct{}{
+	"WithString":  {},
+	"WithNumber":  {},
+	"WithInteger": {},
+	"WithBoolean": {},
+	"WithObject":  {},
+	"WithArray":   {},
+	"WithAny":     {},
+}
+
+// scanGoFile discovers MCP tools and servers declared with mcp-go
+// (mcp.NewTool, server.NewMCPServer) and the official Go SDK
+// (mcp.AddTool with an &mcp.Tool literal, &mcp.Implementation). Files that do
+// not import an MCP package or do not parse are skipped.
+func scanGoFile(path string) ([]schemascout.InventoryItem, error) {
+	// #nosec G304 -- scanning user workspace files is intentional.
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read go file %s: %w", path, err)
+	}
+	if !bytes.Contains(content, []byte("mcp-go")) && !bytes.Contains(content, []byte("modelcontextprotocol/go-sdk")) {
+		return nil, nil
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), path, content, parser.SkipObjectResolution)
+	if err != nil || !importsMCPGo(file) {
+		return nil, nil
+	}
+
+	structFields := goStructFieldNames(file)
+	handlerInputs := map[string]string{}
+	for _, decl := range file.Decls {
+		if function, ok := decl.(*ast.FuncDecl); ok && function.Recv == nil {
+			handlerInputs[function.Name.Name] = goLastParamTypeName(function.Type)
+		}
+	}
+
+	items := make([]schemascout.InventoryItem, 0)
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch typed := node.(type) {
+		case *ast.CallExpr:
+			switch goCalleeName(typed.Fun) {
+			case "NewTool":
+				name, ok := goStringArg(typed.Args, 0)
+				if !ok {
+					return true
+				}
+				description := ""
+				fields := []string{}
+				for _, option := range typed.Args[1:] {
+					optionCall, ok := option.(*ast.CallExpr)
+					if !ok {
+						continue
+					}
+					optionName := goCalleeName(optionCall.Fun)
+					value, ok := goStringArg(optionCall.Args, 0)
+					if !ok {
+						continue
+					}
+					if optionName == "WithDescription" {
+						description = value
+					} else if _, isField := mcpGoFieldOptions[optionName]; isField {
+						fields = append(fields, value)
+					}
+				}
+				items = append(items, makeDeclaredToolItem(path, name, mcpGoToolTag, description, fields))
+			case "AddTool":
+				tool := goToolLiteral(typed.Args)
+				if tool == nil {
+					return true
+				}
+				name := goCompositeString(tool, "Name")
+				if name == "" {
+					return true
+				}
+				fields := structFields[goHandlerInputType(typed.Args[len(typed.Args)-1], handlerInputs)]
+				items = append(items, makeDeclaredToolItem(path, name, mcpGoToolTag, goCompositeString(tool, "Description"), fields))
+			case "NewMCPServer":
+				if name, ok := goStringArg(typed.Args, 0); ok && strings.TrimSpace(name) != "" {
+					items = append(items, makeMCPServerItem(path, name))
+				}
+			}
+		case *ast.CompositeLit:
+			if goCalleeName(typed.Type) == "Implementation" {
+				if name := goCompositeString(typed, "Name"); name != "" {
+					items = append(items, makeMCPServerItem(path, name))
+				}
+			}
+		}
+		return true
+	})
+	return dedupeItems(items), nil
+}
+
+func importsMCPGo(file *ast.File) bool {
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		for _, prefix := range mcpGoImportPrefixes {
+			if strings.HasPrefix(importPath, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func goCalleeName(expr ast.Expr) string {
+	switch typed := expr.(type) {
+	case *ast.Ident:
+		return typed.Name
+	case *ast.SelectorExpr:
+		return typed.Sel.Name
+	case *ast.IndexExpr:
+		return goCalleeName(typed.X)
+	case *ast.IndexListExpr:
+		return goCalleeName(typed.X)
+	}
+	return ""
+}
+
+func goStringArg(args []ast.Expr, index int) (string, bool) {
+	if index >= len(args) {
+		return "", false
+	}
+	literal, ok := args[index].(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(literal.Value)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+func goToolLiteral(args []ast.Expr) *ast.CompositeLit {
+	for _, arg := range args {
+		if unary, ok := arg.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+			arg = unary.X
+		}
+		if literal, ok := arg.(*ast.CompositeLit); ok && goCalleeName(literal.Type) == "Tool" {
+			return literal
+		}
+	}
+	return nil
+}
+
+func goCompositeString(literal *ast.CompositeLit, key string) string {
+	for _, element := range literal.Elts {
+		pair, ok := element.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if ident, ok := pair.Key.(*ast.Ident); !ok || ident.Name != key {
+			continue
+		}
+		if value, ok := goStringArg([]ast.Expr{pair.Value}, 0); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// goHandlerInputType resolves the input struct of an official SDK tool handler,
+// which is its last parameter (or the type argument of a generic params type).
+func goHandlerInputType(handler ast.Expr, handlerInputs map[string]string) string {
+	switch typed := handler.(type) {
+	case *ast.FuncLit:
+		return goLastParamTypeName(typed.Type)
+	case *ast.Ident:
+		return handlerInputs[typed.Name]
+	}
+	return ""
+}
+
+func goLastParamTypeName(funcType *ast.FuncType) string {
+	if funcType == nil || funcType.Params == nil || len(funcType.Params.List) == 0 {
+		return ""
+	}
+	return goLocalTypeName(funcType.Params.List[len(funcType.Params.List)-1].Type)
+}
+
+func goLocalTypeName(expr ast.Expr) string {
+	switch typed := expr.(type) {
+	case *ast.Ident:
+		return typed.Name
+	case *ast.StarExpr:
+		return goLocalTypeName(typed.X)
+	case *ast.IndexExpr:
+		return goLocalTypeName(typed.Index)
+	}
+	return ""
+}
+
+func goStructFieldNames(file *ast.File) map[string][]string {
+	fields := map[string][]string{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.TypeSpec)
+		if !ok {
+			return true
+		}
+		structType, ok := spec.Type.(*ast.StructType)
+		if !ok {
+			return true
+		}
+		names := []string{}
+		for _, field := range structType.Fields.List {
+			jsonName := ""
+			if field.Tag != nil {
+				if tag, err := strconv.Unquote(field.Tag.Value); err == nil {
+					jsonName, _, _ = strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+				}
+			}
+			if jsonName == "-" {
+				continue
+			}
+			for _, name := range field.Names {
+				if !name.IsExported() {
+					continue
+				}
+				if jsonName != "" {
+					names = append(names, jsonName)
+				} else {
+					names = append(names, name.Name)
+				}
+			}
+		}
+		fields[spec.Name.Name] = names
+		return true
+	})
+	return fields
+}
@@ -0,0 +1,329 @@
+package scout
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	schemascout "github.com/Clyra-AI/gait/core/schema/v1/scout"
+)
+
+const (
+	vercelAIToolTag  = "framework:vercel_ai"
+	langchainToolTag = "framework:langchain"
+	mcpSDKToolTag    = "framework:mcp_sdk"
+)
+
+var (
+	jsVercelImportRegexp     = regexp.MustCompile(`(?:from\s+|require\s*\(\s*)['"]ai['"]`)
+	jsLangChainImportRegexp  = regexp.MustCompile(`['"](?:@langchain/[^'"]+|langchain/[^'"]*tools[^'"]*)['"]`)
+	jsMCPImportRegexp        = regexp.MustCompile(`['"]@modelcontextprotocol/sdk[^'"]*['"]`)
+	jsVercelToolRegexp       = regexp.MustCompile(`([A-Za-z_$][\w$]*|'[^']+'|"[^"]+")\s*[:=]\s*tool\s*\(\s*\{`)
+	jsLangChainClassRegexp   = regexp.MustCompile(`new\s+(?:DynamicStructuredTool|DynamicTool|StructuredTool)\s*\(\s*\{`)
+	jsLangChainToolFuncRegex = regexp.MustCompile(`(?:^|[^\w$.])tool\s*\(`)
+	jsMCPToolRegexp          = regexp.MustCompile("\\.\\s*(tool|registerTool)\\s*(\\()\\s*['\"`]([^'\"`]+)['\"`]")
+	jsMCPServerRegexp        = regexp.MustCompile(`new\s+(?:McpServer|Server)\s*\(\s*\{`)
+	jsNamePropertyRegexp     = regexp.MustCompile("\\bname\\s*:\\s*['\"`]([^'\"`]+)['\"`]")
+	jsDescriptionRegexp      = regexp.MustCompile("\\bdescription\\s*:\\s*['\"`]([^'\"`]*)['\"`]")
+	jsSchemaObjectRegexp     = regexp.MustCompile(`\b(?:inputSchema|parameters|schema)\s*:\s*(?:z\s*\.\s*object\s*\(\s*)?\{`)
+	jsZodObjectRegexp        = regexp.MustCompile(`\bz\s*\.\s*object\s*\(\s*\{`)
+	jsPropertiesRegexp       = regexp.MustCompile(`\bproperties\s*:\s*\{`)
+	jsLeadingStringArgRegexp = regexp.MustCompile("^\\s*,\\s*['\"`]([^'\"`]*)['\"`]\\s*")
+)
+
+// scanJavaScriptFile discovers tools declared with the Vercel AI SDK, LangChain.js,
+// and the MCP TypeScript SDK in .ts/.tsx/.js/.mjs/.cjs sources.
+func scanJavaScriptFile(path string) ([]schemascout.InventoryItem, error) {
+	// #nosec G304 -- scanning user workspace files is intentional.
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read script file %s: %w", path, err)
+	}
+	text := string(content)
+	items := make([]schemascout.InventoryItem, 0)
+
+	if jsVercelImportRegexp.MatchString(text) {
+		for _, match := range jsVercelToolRegexp.FindAllStringSubmatchIndex(text, -1) {
+			name := strings.Trim(text[match[2]:match[3]], `'"`)
+			body, ok := jsDelimitedBody(text, match[1]-1)
+			if !ok {
+				continue
+			}
+			items = append(items, makeDeclaredToolItem(path, name, vercelAIToolTag, jsDescription(body), jsInputFields(body)))
+		}
+	}
+
+	if jsLangChainImportRegexp.MatchString(text) {
+		for _, match := range jsLangChainClassRegexp.FindAllStringIndex(text, -1) {
+			body, ok := jsDelimitedBody(text, match[1]-1)
+			if !ok {
+				continue
+			}
+			if name := jsNameProperty(body); name != "" {
+				items = append(items, makeDeclaredToolItem(path, name, langchainToolTag, jsDescription(body), jsInputFields(body)))
+			}
+		}
+		for _, match := range jsLangChainToolFuncRegex.FindAllStringIndex(text, -1) {
+			args, ok := jsDelimitedBody(text, match[1]-1)
+			if !ok || strings.HasPrefix(strings.TrimSpace(args), "{") {
+				continue
+			}
+			config, ok := jsLastTopLevelObject(args)
+			if !ok {
+				continue
+			}
+			if name := jsNameProperty(config); name != "" {
+				items = append(items, makeDeclaredToolItem(path, name, langchainToolTag, jsDescription(config), jsInputFields(config)))
+			}
+		}
+	}
+
+	if jsMCPImportRegexp.MatchString(text) {
+		for _, match := range jsMCPToolRegexp.FindAllStringSubmatchIndex(text, -1) {
+			method := text[match[2]:match[3]]
+			name := text[match[6]:match[7]]
+			args, ok := jsDelimitedBody(text, match[4])
+			if !ok {
+				continue
+			}
+			rest := strings.TrimPrefix(args, text[match[4]+1:match[1]])
+			description, fields := "", []string(nil)
+			if method == "registerTool" {
+				description, fields = jsDescription(rest), jsInputFields(rest)
+			} else {
+				if leading := jsLeadingStringArgRegexp.FindStringSubmatchIndex(rest); leading != nil {
+					description = rest[leading[2]:leading[3]]
+					rest = rest[leading[3]+1:]
+				}
+				fields = jsRawShapeFields(rest)
+			}
+			items = append(items, makeDeclaredToolItem(path, name, mcpSDKToolTag, description, fields))
+		}
+		for _, match := range jsMCPServerRegexp.FindAllStringIndex(text, -1) {
+			body, ok := jsDelimitedBody(text, match[1]-1)
+			if !ok {
+				continue
+			}
+			if name := jsNameProperty(body); name != "" {
+				items = append(items, makeMCPServerItem(path, name))
+			}
+		}
+	}
+
+	return dedupeItems(items), nil
+}
+
+func jsNameProperty(body string) string {
+	if match := jsNamePropertyRegexp.FindStringSubmatch(body); len(match) > 1 {
+		return strings.TrimSpace(match[1])
+	}
+	return ""
+}
+
+func jsDescription(body string) string {
+	if match := jsDescriptionRegexp.FindStringSubmatch(body); len(match) > 1 {
+		return match[1]
+	}
+	return ""
+}
+
+// jsInputFields returns the top-level property names of the first input
+// schema found in body: a zod object, a raw zod shape, or a JSON Schema.
+func jsInputFields(body string) []string {
+	for _, pattern := range []*regexp.Regexp{jsSchemaObjectRegexp, jsZodObjectRegexp, jsPropertiesRegexp} {
+		loc := pattern.FindStringIndex(body)
+		if loc == nil {
+			continue
+		}
+		object, ok := jsDelimitedBody(body, loc[1]-1)
+		if !ok {
+			continue
+		}
+		keys := jsTopLevelKeys(object)
+		if pattern != jsPropertiesRegexp && slices.Contains(keys, "properties") {
+			if nested := jsPropertiesRegexp.FindStringIndex(object); nested != nil {
+				if properties, ok := jsDelimitedBody(object, nested[1]-1); ok {
+					return jsTopLevelKeys(properties)
+				}
+			}
+		}
+		return keys
+	}
+	return nil
+}
+
+// jsRawShapeFields reads the raw zod shape argument of server.tool(name,
+// [description,] shape, handler) when present.
+func jsRawShapeFields(rest string) []string {
+	trimmed := strings.TrimLeft(rest, " \t\r\n")
+	if !strings.HasPrefix(trimmed, ",") {
+		return nil
+	}
+	trimmed = strings.TrimLeft(trimmed[1:], " \t\r\n")
+	if !strings.HasPrefix(trimmed, "{") {
+		return nil
+	}
+	shape, ok := jsDelimitedBody(trimmed, 0)
+	if !ok {
+		return nil
+	}
+	return jsTopLevelKeys(shape)
+}
+
+func jsLastTopLevelObject(args string) (string, bool) {
+	last := -1
+	depth := 0
+	for index := 0; index < len(args); {
+		char := args[index]
+		switch {
+		case char == '"' || char == '\'' || char == '`':
+			index = jsSkipString(args, index)
+			continue
+		case char == '/' && index+1 < len(args) && (args[index+1] == '/' || args[index+1] == '*'):
+			index = jsSkipComment(args, index)
+			continue
+		case char == '{' || char == '(' || char == '[':
+			if char == '{' && depth == 0 {
+				last = index
+			}
+			depth++
+		case char == '}' || char == ')' || char == ']':
+			depth--
+		}
+		index++
+	}
+	if last < 0 {
+		return "", false
+	}
+	return jsDelimitedBody(args, last)
+}
+
+// jsDelimitedBody returns the text between the bracket at open and its match.
+func jsDelimitedBody(text string, open int) (string, bool) {
+	if open < 0 || open >= len(text) {
+		return "", false
+	}
+	depth := 0
+	for index := open; index < len(text); {
+		char := text[index]
+		switch {
+		case char == '"' || char == '\'' || char == '`':
+			index = jsSkipString(text, index)
+			continue
+		case char == '/' && index+1 < len(text) && (text[index+1] == '/' || text[index+1] == '*'):
+			index = jsSkipComment(text, index)
+			continue
+		case char == '{' || char == '(' || char == '[':
+			depth++
+		case char == '}' || char == ')' || char == ']':
+			depth--
+			if depth == 0 {
+				return text[open+1 : index], true
+			}
+		}
+		index++
+	}
+	return "", false
+}
+
+func jsTopLevelKeys(body string) []string {
+	keys := []string{}
+	depth := 0
+	expectKey := true
+	for index := 0; index < len(body); {
+		if depth == 0 && expectKey {
+			index = jsSkipSpace(body, index)
+			key, next := jsReadKey(body, index)
+			if key != "" {
+				if colon := jsSkipSpace(body, next); colon < len(body) && body[colon] == ':' {
+					keys = append(keys, key)
+					next = colon + 1
+				}
+				index = next
+			}
+			expectKey = false
+			continue
+		}
+		char := body[index]
+		switch {
+		case char == '"' || char == '\'' || char == '`':
+			index = jsSkipString(body, index)
+			continue
+		case char == '/' && index+1 < len(body) && (body[index+1] == '/' || body[index+1] == '*'):
+			index = jsSkipComment(body, index)
+			continue
+		case char == '{' || char == '(' || char == '[':
+			depth++
+		case char == '}' || char == ')' || char == ']':
+			depth--
+		case char == ',' && depth == 0:
+			expectKey = true
+		}
+		index++
+	}
+	return keys
+}
+
+func jsReadKey(text string, index int) (string, int) {
+	if index >= len(text) {
+		return "", index
+	}
+	char := text[index]
+	if char == '"' || char == '\'' {
+		end := jsSkipString(text, index)
+		if end-1 <= index+1 {
+			return "", end
+		}
+		return text[index+1 : end-1], end
+	}
+	end := index
+	for end < len(text) && (text[end] == '_' || text[end] == '$' ||
+		(text[end] >= 'a' && text[end] <= 'z') || (text[end] >= 'A' && text[end] <= 'Z') ||
+		(end > index && text[end] >= '0' && text[end] <= '9')) {
+		end++
+	}
+	return text[index:end], end
+}
+
+func jsSkipSpace(text string, index int) int {
+	for index < len(text) {
+		switch {
+		case text[index] == ' ' || text[index] == '\t' || text[index] == '\r' || text[index] == '\n':
+			index++
+		case text[index] == '/' && index+1 < len(text) && (text[index+1] == '/' || text[index+1] == '*'):
+			index = jsSkipComment(text, index)
+		default:
+			return index
+		}
+	}
+	return index
+}
+
+func jsSkipString(text string, index int) int {
+	quote := text[index]
+	for cursor := index + 1; cursor < len(text); cursor++ {
+		if text[cursor] == '\\' {
+			cursor++
+			continue
+		}
+		if text[cursor] == quote {
+			return cursor + 1
+		}
+	}
+	return len(text)
+}
+
+func jsSkipComment(text string, index int) int {
+	if text[index+1] == '/' {
+		if newline := strings.IndexByte(text[index:], '\n'); newline >= 0 {
+			return index + newline + 1
+		}
+		return len(text)
+	}
+	if end := strings.Index(text[index+2:], "*/"); end >= 0 {
+		return index + 2 + end + 2
+	}
+	return len(text)
+}
@@ -278,3 +278,154 @@ func TestSortInventoryItemsDeterministicOrdering(t *testing.T) {
 		}
 	}
 }
+
+func TestSnapshotDiscoversTypeScriptAndGoTools(t *testing.T) {
+	workDir := t.TempDir()
+	writeSource := func(name, source string) {
+		t.Helper()
+		path := filepath.Join(workDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatalf("mkdir %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(source), 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	writeSource("agent.ts", `
+import { tool } from "ai";
+import { z } from "zod";
+import { DynamicStructuredTool } from "@langchain/core/tools";
+
+export const tools = {
+  getWeather: tool({
+    description: "Look up the weather",
+    parameters: z.object({ city: z.string(), unit: z.enum(["c", "f"]) }),
+    execute: async ({ city }) => ({ city }),
+  }),
+  runQuery: tool({
+    description: "Run a read query",
+    inputSchema: z.object({
+      // the statement to run
+      sql: z.string().describe("select only, no ':' tricks"),
+    }),
+    execute: async () => ({}),
+  }),
+};
+
+export const archive = new DynamicStructuredTool({
+  name: "archive_ticket",
+  description: "Archive a ticket",
+  schema: z.object({ ticket_id: z.string() }),
+  func: async () => "ok",
+});
+`)
+	writeSource("server/index.mjs", `
+import { McpServer } from "@modelcontextprotocol/sdk/server/mcp.js";
+const server = new McpServer({ name: "files", version: "1.0.0" });
+server.tool("read_file", "Read a file", { path: z.string() }, async ({ path }) => ({ content: [] }));
+server.registerTool("purge_cache", {
+  description: "Purge a cache region",
+  inputSchema: { region: z.string() },
+}, async () => ({ content: [] }));
+`)
+	writeSource("types.d.ts", `import { tool } from "ai"; export const ghost = tool({ description: "x" });`)
+	writeSource("mcpgo/main.go", `package main
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func main() {
+	s := server.NewMCPServer("ops", "1.0.0")
+	tool := mcp.NewTool("restart_service",
+		mcp.WithDescription("Restart a service"),
+		mcp.WithString("service", mcp.Required()),
+		mcp.WithTitleAnnotation("Restart"),
+	)
+	s.AddTool(tool, nil)
+}
+`)
+	writeSource("gosdk/main.go", `package main
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ExecArgs struct {
+	Command string `+"`json:\"command\"`"+`
+	Dir     string `+"`json:\"dir,omitempty\"`"+`
+}
+
+func Exec(ctx context.Context, req *mcp.CallToolRequest, input ExecArgs) (*mcp.CallToolResult, any, error) {
+	return nil, nil, nil
+}
+
+func main() {
+	server := mcp.NewServer(&mcp.Implementation{Name: "shell"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "exec", Description: "Run a command"}, Exec)
+}
+`)
+	writeSource("vendor/github.com/mark3labs/mcp-go/example.go", `package example
+
+import "github.com/mark3labs/mcp-go/mcp"
+
+var vendored = mcp.NewTool("vendored_tool")
+`)
+	writeSource("broken.go", `package broken
+import "github.com/mark3labs/mcp-go/mcp"
+func (`)
+
+	snapshot, err := DefaultProvider{}.Snapshot(context.Background(), SnapshotRequest{Roots: []string{workDir}})
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	byID := map[string]schemascout.InventoryItem{}
+	for _, item := range snapshot.Items {
+		byID[item.ID] = item
+	}
+	expectations := []struct {
+		id     string
+		fields string
+		risk   string
+	}{
+		{id: "tool:framework:vercel_ai:getweather", fields: "city,unit", risk: "low"},
+		{id: "tool:framework:vercel_ai:runquery", fields: "sql", risk: "critical"},
+		{id: "tool:framework:langchain:archive_ticket", fields: "ticket_id", risk: "low"},
+		{id: "tool:framework:mcp_sdk:read_file", fields: "path", risk: "high"},
+		{id: "tool:framework:mcp_sdk:purge_cache", fields: "region", risk: "low"},
+		{id: "tool:framework:mcp_go:restart_service", fields: "service", risk: "low"},
+		{id: "tool:framework:mcp_go:exec", fields: "command,dir", risk: "critical"},
+		{id: "mcp_server:files"},
+		{id: "mcp_server:ops"},
+		{id: "mcp_server:shell"},
+	}
+	for _, expected := range expectations {
+		item, ok := byID[expected.id]
+		if !ok {
+			t.Fatalf("expected inventory item %s in %#v", expected.id, snapshot.Items)
+		}
+		if got := strings.Join(item.InputFields, ","); got != expected.fields {
+			t.Fatalf("unexpected input fields for %s: %q", expected.id, got)
+		}
+		if expected.risk != "" && item.RiskLevel != expected.risk {
+			t.Fatalf("unexpected risk for %s: %s", expected.id, item.RiskLevel)
+		}
+	}
+	if len(snapshot.Items) != len(expectations) {
+		t.Fatalf("expected %d items, got %#v", len(expectations), snapshot.Items)
+	}
+
+	changed := snapshot
+	changed.Items = append([]schemascout.InventoryItem(nil), snapshot.Items...)
+	for index := range changed.Items {
+		if changed.Items[index].ID == "tool:framework:mcp_sdk:purge_cache" {
+			changed.Items[index].InputFields = []string{"region", "script"}
+		}
+	}
+	if diff := DiffSnapshots(snapshot, changed); diff.ChangedCount != 1 || diff.Changed[0].ID != "tool:framework:mcp_sdk:purge_cache" {
+		t.Fatalf("expected input field change to surface in diff: %#v", diff)
+	}
+}
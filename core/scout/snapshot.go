@@ -41,8 +41,32 @@ func (provider DefaultProvider) Snapshot(_ context.Context, req SnapshotRequest)
 		}
 	}
 
+	return buildInventorySnapshot(provider.Options, roots, items)
+}
+
+// CompositeProvider merges the items of several providers into one snapshot,
+// for example the filesystem walk together with live MCP server inventory.
+type CompositeProvider struct {
+	Options   SnapshotOptions
+	Providers []InventoryProvider
+}
+
+func (provider CompositeProvider) Snapshot(ctx context.Context, req SnapshotRequest) (schemascout.InventorySnapshot, error) {
+	items := make(map[string]schemascout.InventoryItem)
+	for _, nested := range provider.Providers {
+		snapshot, err := nested.Snapshot(ctx, req)
+		if err != nil {
+			return schemascout.InventorySnapshot{}, err
+		}
+		addItems(items, snapshot.Items)
+	}
+	return buildInventorySnapshot(provider.Options, normalizeRoots(req.Roots), items)
+}
+
+func buildInventorySnapshot(options SnapshotOptions, roots []string, items map[string]schemascout.InventoryItem) (schemascout.InventorySnapshot, error) {
 	snapshotItems := make([]schemascout.InventoryItem, 0, len(items))
 	for _, item := range items {
+		item.Relationship = nil
 		snapshotItems = append(snapshotItems, item)
 	}
 	sortInventoryItems(snapshotItems)
@@ -52,11 +76,11 @@ func (provider DefaultProvider) Snapshot(_ context.Context, req SnapshotRequest)
 		workspace = strings.Join(roots, ",")
 	}
 
-	createdAt := provider.Options.Now.UTC()
+	createdAt := options.Now.UTC()
 	if createdAt.IsZero() {
 		createdAt = time.Now().UTC()
 	}
-	producerVersion := strings.TrimSpace(provider.Options.ProducerVersion)
+	producerVersion := strings.TrimSpace(options.ProducerVersion)
 	if producerVersion == "" {
 		producerVersion = "0.0.0-dev"
 	}
@@ -121,6 +145,47 @@ func walkRoot(
 	excludePatterns []string,
 	items map[string]schemascout.InventoryItem,
 ) error {
+	return walkWorkspace(root, includePatterns, excludePatterns, func(normalizedPath string) error {
+		ext := strings.ToLower(filepath.Ext(normalizedPath))
+		switch ext {
+		case ".py":
+			pythonItems, err := scanPythonFile(normalizedPath)
+			if err != nil {
+				return err
+			}
+			addItems(items, pythonItems)
+		case ".ts", ".tsx", ".js", ".mjs", ".cjs":
+			if strings.HasSuffix(normalizedPath, ".d.ts") {
+				return nil
+			}
+			scriptItems, err := scanJavaScriptFile(normalizedPath)
+			if err != nil {
+				return err
+			}
+			addItems(items, scriptItems)
+		case ".go":
+			if strings.HasSuffix(normalizedPath, "_test.go") {
+				return nil
+			}
+			goItems, err := scanGoFile(normalizedPath)
+			if err != nil {
+				return err
+			}
+			addItems(items, goItems)
+		case ".json", ".yaml", ".yml":
+			mcpItems, err := scanMCPConfig(normalizedPath)
+			if err != nil {
+				return err
+			}
+			addItems(items, mcpItems)
+		}
+		return nil
+	})
+}
+
+// walkWorkspace calls visit for every file under root that passes the include
+// and exclude patterns, skipping VCS and dependency directories.
+func walkWorkspace(root string, includePatterns []string, excludePatterns []string, visit func(string) error) error {
 	info, err := os.Stat(root)
 	if err != nil {
 		return fmt.Errorf("stat root %s: %w", root, err)
@@ -135,7 +200,7 @@ func walkRoot(
 		}
 		if dirEntry.IsDir() {
 			name := strings.ToLower(dirEntry.Name())
-			if name == ".git" || name == ".beads" || name == "node_modules" || name == "__pycache__" {
+			if name == ".git" || name == ".beads" || name == "node_modules" || name == "__pycache__" || name == "vendor" {
 				return filepath.SkipDir
 			}
 			return nil
@@ -148,23 +213,7 @@ func walkRoot(
 		if len(includePatterns) > 0 && !matchesAnyPattern(normalizedPath, includePatterns) {
 			return nil
 		}
-
-		ext := strings.ToLower(filepath.Ext(normalizedPath))
-		switch ext {
-		case ".py":
-			pythonItems, err := scanPythonFile(normalizedPath)
-			if err != nil {
-				return err
-			}
-			addItems(items, pythonItems)
-		case ".json", ".yaml", ".yml":
-			mcpItems, err := scanMCPConfig(normalizedPath)
-			if err != nil {
-				return err
-			}
-			addItems(items, mcpItems)
-		}
-		return nil
+		return visit(normalizedPath)
 	})
 }
 
@@ -195,6 +244,8 @@ func addItems(target map[string]schemascout.InventoryItem, values []schemascout.
 			existing.RiskLevel = value.RiskLevel
 		}
 		existing.Tags = mergeTags(existing.Tags, value.Tags)
+		existing.InputFields = mergeInputFields(existing.InputFields, value.InputFields)
+		mergeLiveFields(&existing, value)
 		target[value.ID] = existing
 	}
 }
@@ -266,13 +317,70 @@ func makeToolItem(locator, name, tag string) schemascout.InventoryItem {
 	}
 }
 
+// makeDeclaredToolItem builds a tool item for a tool whose description and
+// input schema are declared in source, raising the name-based risk level when
+// the description or input fields indicate a more dangerous capability.
+func makeDeclaredToolItem(locator, name, tag, description string, inputFields []string) schemascout.InventoryItem {
+	item := makeToolItem(locator, name, tag)
+	item.InputFields = uniqueSorted(inputFields)
+	if len(item.InputFields) == 0 {
+		item.InputFields = nil
+	}
+	for _, level := range []string{classifyRisk(description), classifyInputFieldRisk(item.InputFields)} {
+		if riskScore(level) > riskScore(item.RiskLevel) {
+			item.RiskLevel = level
+		}
+	}
+	return item
+}
+
+func classifyInputFieldRisk(fields []string) string {
+	level := "low"
+	for _, field := range fields {
+		switch normalizeIdentifier(field) {
+		case "command", "cmd", "shell", "script", "sql", "amount", "account_number", "iban":
+			return "critical"
+		case "path", "file", "file_path", "filepath", "filename", "url", "recipient", "recipients", "to", "content", "body":
+			level = "high"
+		}
+	}
+	return level
+}
+
 func scanMCPConfig(path string) ([]schemascout.InventoryItem, error) {
+	payload, looksLikeMCP, err := readMCPConfigPayload(path)
+	if err != nil || payload == nil {
+		return nil, err
+	}
+
+	serverNames := map[string]struct{}{}
+	collectMCPServerNames(payload, serverNames)
+	if !looksLikeMCP && len(serverNames) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(serverNames))
+	for name := range serverNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	items := make([]schemascout.InventoryItem, 0, len(names))
+	for _, name := range names {
+		items = append(items, makeMCPServerItem(path, name))
+	}
+	return items, nil
+}
+
+// readMCPConfigPayload parses a JSON or YAML config file. Parse failures are
+// only reported for files whose name mentions mcp; other files yield nil.
+func readMCPConfigPayload(path string) (any, bool, error) {
 	base := strings.ToLower(filepath.Base(path))
 	looksLikeMCP := strings.Contains(base, "mcp")
 	// #nosec G304 -- scanning user workspace files is intentional.
 	content, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("read config %s: %w", path, err)
+		return nil, looksLikeMCP, fmt.Errorf("read config %s: %w", path, err)
 	}
 
 	var payload any
@@ -281,44 +389,30 @@ func scanMCPConfig(path string) ([]schemascout.InventoryItem, error) {
 	case ".yaml", ".yml":
 		if err := yaml.Unmarshal(content, &payload); err != nil {
 			if looksLikeMCP {
-				return nil, fmt.Errorf("parse mcp yaml %s: %w", path, err)
+				return nil, looksLikeMCP, fmt.Errorf("parse mcp yaml %s: %w", path, err)
 			}
-			return nil, nil
+			return nil, looksLikeMCP, nil
 		}
 	default:
 		if err := json.Unmarshal(content, &payload); err != nil {
 			if looksLikeMCP {
-				return nil, fmt.Errorf("parse mcp json %s: %w", path, err)
+				return nil, looksLikeMCP, fmt.Errorf("parse mcp json %s: %w", path, err)
 			}
-			return nil, nil
+			return nil, looksLikeMCP, nil
 		}
 	}
+	return payload, looksLikeMCP, nil
+}
 
-	serverNames := map[string]struct{}{}
-	collectMCPServerNames(payload, serverNames)
-	if !looksLikeMCP && len(serverNames) == 0 {
-		return nil, nil
-	}
-
-	names := make([]string, 0, len(serverNames))
-	for name := range serverNames {
-		names = append(names, name)
-	}
-	sort.Strings(names)
-
-	items := make([]schemascout.InventoryItem, 0, len(names))
-	for _, name := range names {
-		normalizedName := normalizeIdentifier(name)
-		items = append(items, schemascout.InventoryItem{
-			ID:        "mcp_server:" + normalizedName,
-			Kind:      "mcp_server",
-			Name:      name,
-			Locator:   path,
-			RiskLevel: classifyRisk(name),
-			Tags:      []string{"mcp"},
-		})
+func makeMCPServerItem(locator, name string) schemascout.InventoryItem {
+	return schemascout.InventoryItem{
+		ID:        "mcp_server:" + normalizeIdentifier(name),
+		Kind:      "mcp_server",
+		Name:      name,
+		Locator:   locator,
+		RiskLevel: classifyRisk(name),
+		Tags:      []string{"mcp"},
 	}
-	return items, nil
 }
 
 func collectMCPServerNames(value any, names map[string]struct{}) {
@@ -363,6 +457,20 @@ func computeSnapshotID(workspace string, items []schemascout.InventoryItem) (str
 		for _, tag := range item.Tags {
 			writeSnapshotIDField(digest, tag)
 		}
+		if len(item.InputFields) > 0 {
+			digest.Write([]byte{0xfe})
+			for _, field := range item.InputFields {
+				writeSnapshotIDField(digest, field)
+			}
+		}
+		if item.InputSchemaDigest != "" || item.Annotations != nil || item.Server != nil {
+			live, err := json.Marshal([]any{item.InputSchemaDigest, item.Annotations, item.Server})
+			if err != nil {
+				return "", err
+			}
+			digest.Write([]byte{0xfd})
+			writeSnapshotIDField(digest, string(live))
+		}
 		digest.Write([]byte{0xff})
 	}
 	return "snap_" + hex.EncodeToString(digest.Sum(nil)[:6]), nil
@@ -403,6 +511,27 @@ func riskScore(level string) int {
 	}
 }
 
+func mergeInputFields(left []string, right []string) []string {
+	if len(left) == 0 && len(right) == 0 {
+		return nil
+	}
+	return mergeTags(left, right)
+}
+
+// mergeLiveFields keeps what a live MCP server reported when the same item was
+// also found statically.
+func mergeLiveFields(existing *schemascout.InventoryItem, value schemascout.InventoryItem) {
+	if existing.InputSchemaDigest == "" {
+		existing.InputSchemaDigest = value.InputSchemaDigest
+	}
+	if existing.Annotations == nil {
+		existing.Annotations = value.Annotations
+	}
+	if existing.Server == nil {
+		existing.Server = value.Server
+	}
+}
+
 func mergeTags(left []string, right []string) []string {
 	merged := append(append([]string{}, left...), right...)
 	return uniqueSorted(merged)
@@ -441,6 +570,8 @@ func dedupeItems(items []schemascout.InventoryItem) []schemascout.InventoryItem
 			existing.Locator = item.Locator
 		}
 		existing.Tags = mergeTags(existing.Tags, item.Tags)
+		existing.InputFields = mergeInputFields(existing.InputFields, item.InputFields)
+		mergeLiveFields(&existing, item)
 		if riskScore(item.RiskLevel) > riskScore(existing.RiskLevel) {
 			existing.RiskLevel = item.RiskLevel
 		}
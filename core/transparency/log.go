@@ -0,0 +1,390 @@
+package transparency
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/fsx"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	jcs "github.com/Clyra-AI/proof/canon"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+const (
+	treeHeadSchemaID         = "gait.transparency.tree_head"
+	inclusionProofSchemaID   = "gait.transparency.inclusion_proof"
+	consistencyProofSchemaID = "gait.transparency.consistency_proof"
+	schemaVersion            = "1.0.0"
+	entriesFileName          = "entries.jsonl"
+	treeHeadsFileName        = "tree_heads.jsonl"
+	maxLogLineBytes          = 1024 * 1024
+)
+
+const (
+	KindTrace           = "trace"
+	KindPack            = "pack"
+	KindRunpack         = "runpack"
+	KindApprovalAudit   = "approval_audit"
+	KindDelegationAudit = "delegation_audit"
+	KindArtifact        = "artifact"
+	KindRetentionDelete = "retention_delete"
+)
+
+// Entry is one log leaf. Digest is the lowercase sha256 hex of the recorded
+// artifact (for zip packs, of the manifest inside the zip). Entries are never
+// rewritten: deleting an artifact appends a retention_delete entry carrying
+// the same digest.
+type Entry struct {
+	Kind       string    `json:"kind"`
+	Digest     string    `json:"digest"`
+	Ref        string    `json:"ref,omitempty"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+type TreeHead struct {
+	SchemaID      string                `json:"schema_id"`
+	SchemaVersion string                `json:"schema_version"`
+	TreeSize      int64                 `json:"tree_size"`
+	RootHash      string                `json:"root_hash"`
+	CreatedAt     time.Time             `json:"created_at"`
+	Signature     *schemagate.Signature `json:"signature,omitempty"`
+}
+
+type AppendOptions struct {
+	SigningKey ed25519.PrivateKey
+	Now        time.Time
+}
+
+type AppendResult struct {
+	Index    int64     `json:"index"`
+	Entry    Entry     `json:"entry"`
+	LeafHash string    `json:"leaf_hash"`
+	TreeHead *TreeHead `json:"tree_head,omitempty"`
+}
+
+// Append records entry at the end of the log in logDir. When a signing key is
+// provided a signed tree head covering the new entry is issued as well.
+func Append(logDir string, entry Entry, opts AppendOptions) (AppendResult, error) {
+	now := opts.Now.UTC()
+	if opts.Now.IsZero() {
+		now = time.Now().UTC()
+	}
+	normalized, err := normalizeEntry(entry, now)
+	if err != nil {
+		return AppendResult{}, err
+	}
+	line, err := json.Marshal(normalized)
+	if err != nil {
+		return AppendResult{}, fmt.Errorf("encode log entry: %w", err)
+	}
+	if err := fsx.AppendLineLocked(filepath.Join(logDir, entriesFileName), line, 0o600); err != nil {
+		return AppendResult{}, fmt.Errorf("append log entry: %w", err)
+	}
+	entries, err := LoadEntries(logDir)
+	if err != nil {
+		return AppendResult{}, err
+	}
+	index := int64(-1)
+	for position := len(entries) - 1; position >= 0; position-- {
+		candidate := entries[position]
+		if candidate.Kind == normalized.Kind && candidate.Digest == normalized.Digest && candidate.Ref == normalized.Ref && candidate.RecordedAt.Equal(normalized.RecordedAt) {
+			index = int64(position)
+			break
+		}
+	}
+	if index < 0 {
+		return AppendResult{}, fmt.Errorf("appended log entry not found")
+	}
+	leaf, err := LeafHash(normalized)
+	if err != nil {
+		return AppendResult{}, err
+	}
+	result := AppendResult{Index: index, Entry: normalized, LeafHash: leaf}
+	if len(opts.SigningKey) > 0 {
+		head, err := issueTreeHead(logDir, entries, opts.SigningKey, now)
+		if err != nil {
+			return AppendResult{}, err
+		}
+		result.TreeHead = &head
+	}
+	return result, nil
+}
+
+// LoadEntries returns every entry in the log, in leaf order. A missing log is
+// an empty log.
+func LoadEntries(logDir string) ([]Entry, error) {
+	entries := make([]Entry, 0)
+	err := readJSONLines(filepath.Join(logDir, entriesFileName), func(line []byte) error {
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("parse log entry %d: %w", len(entries), err)
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// LoadTreeHeads returns every tree head issued for the log, oldest first.
+func LoadTreeHeads(logDir string) ([]TreeHead, error) {
+	heads := make([]TreeHead, 0)
+	err := readJSONLines(filepath.Join(logDir, treeHeadsFileName), func(line []byte) error {
+		var head TreeHead
+		if err := json.Unmarshal(line, &head); err != nil {
+			return fmt.Errorf("parse tree head %d: %w", len(heads), err)
+		}
+		heads = append(heads, head)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return heads, nil
+}
+
+// IssueTreeHead computes the tree head for the current log. It is signed and
+// persisted when signingKey is set; unsigned heads are returned but not kept.
+func IssueTreeHead(logDir string, signingKey ed25519.PrivateKey, now time.Time) (TreeHead, error) {
+	entries, err := LoadEntries(logDir)
+	if err != nil {
+		return TreeHead{}, err
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return issueTreeHead(logDir, entries, signingKey, now.UTC())
+}
+
+func issueTreeHead(logDir string, entries []Entry, signingKey ed25519.PrivateKey, now time.Time) (TreeHead, error) {
+	leaves, err := leafHashes(entries)
+	if err != nil {
+		return TreeHead{}, err
+	}
+	head := TreeHead{
+		SchemaID:      treeHeadSchemaID,
+		SchemaVersion: schemaVersion,
+		TreeSize:      int64(len(leaves)),
+		RootHash:      hex.EncodeToString(rootHash(leaves)),
+		CreatedAt:     now,
+	}
+	if len(signingKey) == 0 {
+		return head, nil
+	}
+	head, err = SignTreeHead(head, signingKey)
+	if err != nil {
+		return TreeHead{}, err
+	}
+	line, err := json.Marshal(head)
+	if err != nil {
+		return TreeHead{}, fmt.Errorf("encode tree head: %w", err)
+	}
+	if err := fsx.AppendLineLocked(filepath.Join(logDir, treeHeadsFileName), line, 0o600); err != nil {
+		return TreeHead{}, fmt.Errorf("append tree head: %w", err)
+	}
+	return head, nil
+}
+
+func SignTreeHead(head TreeHead, privateKey ed25519.PrivateKey) (TreeHead, error) {
+	raw, err := signableTreeHead(head)
+	if err != nil {
+		return TreeHead{}, err
+	}
+	signature, err := sign.SignTraceRecordJSON(privateKey, raw)
+	if err != nil {
+		return TreeHead{}, fmt.Errorf("sign tree head: %w", err)
+	}
+	head.Signature = &schemagate.Signature{
+		Alg:          signature.Alg,
+		KeyID:        signature.KeyID,
+		Sig:          signature.Sig,
+		SignedDigest: signature.SignedDigest,
+	}
+	return head, nil
+}
+
+func VerifyTreeHead(head TreeHead, publicKey ed25519.PublicKey) error {
+	if head.Signature == nil {
+		return fmt.Errorf("tree head %d is not signed", head.TreeSize)
+	}
+	if len(publicKey) == 0 {
+		return fmt.Errorf("verify key is required")
+	}
+	raw, err := signableTreeHead(head)
+	if err != nil {
+		return err
+	}
+	ok, err := sign.VerifyTraceRecordJSON(publicKey, sign.Signature{
+		Alg:          head.Signature.Alg,
+		KeyID:        head.Signature.KeyID,
+		Sig:          head.Signature.Sig,
+		SignedDigest: head.Signature.SignedDigest,
+	}, raw)
+	if err != nil {
+		return fmt.Errorf("verify tree head signature: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("tree head %d signature did not verify", head.TreeSize)
+	}
+	return nil
+}
+
+func signableTreeHead(head TreeHead) ([]byte, error) {
+	signable := head
+	signable.Signature = nil
+	raw, err := json.Marshal(signable)
+	if err != nil {
+		return nil, fmt.Errorf("encode signable tree head: %w", err)
+	}
+	return raw, nil
+}
+
+type VerifyLogResult struct {
+	TreeSize     int64    `json:"tree_size"`
+	RootHash     string   `json:"root_hash"`
+	HeadsChecked int      `json:"heads_checked"`
+	HeadsSigned  int      `json:"heads_verified"`
+	Errors       []string `json:"errors,omitempty"`
+}
+
+// VerifyLog recomputes the tree from the stored entries and checks every
+// issued tree head against it. A head whose root no longer matches means
+// entries were removed or rewritten after the head was signed.
+func VerifyLog(logDir string, publicKey ed25519.PublicKey) (VerifyLogResult, error) {
+	entries, err := LoadEntries(logDir)
+	if err != nil {
+		return VerifyLogResult{}, err
+	}
+	heads, err := LoadTreeHeads(logDir)
+	if err != nil {
+		return VerifyLogResult{}, err
+	}
+	leaves, err := leafHashes(entries)
+	if err != nil {
+		return VerifyLogResult{}, err
+	}
+	result := VerifyLogResult{
+		TreeSize:     int64(len(leaves)),
+		RootHash:     hex.EncodeToString(rootHash(leaves)),
+		HeadsChecked: len(heads),
+	}
+	for _, head := range heads {
+		if head.TreeSize < 0 || head.TreeSize > result.TreeSize {
+			result.Errors = append(result.Errors, fmt.Sprintf("tree head %d exceeds log size %d", head.TreeSize, result.TreeSize))
+			continue
+		}
+		if expected := hex.EncodeToString(rootHash(leaves[:head.TreeSize])); !strings.EqualFold(expected, head.RootHash) {
+			result.Errors = append(result.Errors, fmt.Sprintf("tree head %d root mismatch: expected=%s actual=%s", head.TreeSize, expected, head.RootHash))
+			continue
+		}
+		if len(publicKey) > 0 {
+			if err := VerifyTreeHead(head, publicKey); err != nil {
+				result.Errors = append(result.Errors, err.Error())
+				continue
+			}
+			result.HeadsSigned++
+		}
+	}
+	return result, nil
+}
+
+// LeafHash returns the hex RFC 6962 leaf hash of the canonical JSON of entry.
+func LeafHash(entry Entry) (string, error) {
+	leaf, err := entryLeafHash(entry)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(leaf), nil
+}
+
+func entryLeafHash(entry Entry) ([]byte, error) {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("encode log entry: %w", err)
+	}
+	canonical, err := jcs.CanonicalizeJSON(raw)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalize log entry: %w", err)
+	}
+	return hashLeaf(canonical), nil
+}
+
+func leafHashes(entries []Entry) ([][]byte, error) {
+	leaves := make([][]byte, 0, len(entries))
+	for _, entry := range entries {
+		leaf, err := entryLeafHash(entry)
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, leaf)
+	}
+	return leaves, nil
+}
+
+func normalizeEntry(entry Entry, now time.Time) (Entry, error) {
+	entry.Kind = strings.ToLower(strings.TrimSpace(entry.Kind))
+	entry.Ref = strings.TrimSpace(entry.Ref)
+	digest, err := NormalizeDigest(entry.Digest)
+	if err != nil {
+		return Entry{}, err
+	}
+	entry.Digest = digest
+	if entry.Kind == "" {
+		return Entry{}, fmt.Errorf("log entry kind is required")
+	}
+	if entry.RecordedAt.IsZero() {
+		entry.RecordedAt = now
+	}
+	entry.RecordedAt = entry.RecordedAt.UTC()
+	return entry, nil
+}
+
+// NormalizeDigest accepts a sha256 hex digest with or without a "sha256:"
+// prefix and returns it lowercase without the prefix.
+func NormalizeDigest(value string) (string, error) {
+	digest := strings.ToLower(strings.TrimSpace(value))
+	digest = strings.TrimPrefix(digest, "sha256:")
+	if _, err := decodeHash(digest); err != nil {
+		return "", fmt.Errorf("digest must be a sha256 hex value: %q", value)
+	}
+	return digest, nil
+}
+
+func readJSONLines(path string, visit func([]byte) error) error {
+	// #nosec G304 -- log path is explicit local user input.
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("open %s: %w", filepath.Base(path), err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineBytes)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if err := visit(line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
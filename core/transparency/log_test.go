@@ -0,0 +1,132 @@
+package transparency
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testDigest(seed int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("artifact-%d", seed)))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestInclusionAndConsistencyProofsForEveryTreeSize(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	logDir := filepath.Join(t.TempDir(), "log")
+	now := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	const size = 13
+	for index := 0; index < size; index++ {
+		result, err := Append(logDir, Entry{Kind: KindTrace, Digest: "sha256:" + strings.ToUpper(testDigest(index)), Ref: fmt.Sprintf("trace_%d", index)}, AppendOptions{SigningKey: privateKey, Now: now})
+		if err != nil {
+			t.Fatalf("append %d: %v", index, err)
+		}
+		if result.Index != int64(index) || result.TreeHead == nil || result.TreeHead.TreeSize != int64(index+1) || result.Entry.Digest != testDigest(index) {
+			t.Fatalf("unexpected append result %d: %#v", index, result)
+		}
+	}
+
+	for index := int64(0); index < size; index++ {
+		proof, err := ProveInclusion(logDir, ProveOptions{Index: &index, SigningKey: privateKey, Now: now})
+		if err != nil {
+			t.Fatalf("prove %d: %v", index, err)
+		}
+		if err := VerifyInclusion(proof, testDigest(int(index)), publicKey); err != nil {
+			t.Fatalf("verify inclusion %d: %v", index, err)
+		}
+		if err := VerifyInclusion(proof, testDigest(int(index)+1), publicKey); err == nil {
+			t.Fatalf("expected digest mismatch for %d", index)
+		}
+		if len(proof.AuditPath) > 0 {
+			tampered := proof
+			tampered.AuditPath = append([]string(nil), proof.AuditPath...)
+			tampered.AuditPath[0] = testDigest(99)
+			if err := VerifyInclusion(tampered, "", nil); err == nil {
+				t.Fatalf("expected tampered audit path to fail for %d", index)
+			}
+		}
+	}
+
+	for oldSize := int64(1); oldSize <= size; oldSize++ {
+		for newSize := oldSize; newSize <= size; newSize++ {
+			proof, err := ProveConsistency(logDir, oldSize, newSize, privateKey, now)
+			if err != nil {
+				t.Fatalf("prove consistency %d->%d: %v", oldSize, newSize, err)
+			}
+			if err := VerifyConsistency(proof, publicKey); err != nil {
+				t.Fatalf("verify consistency %d->%d: %v", oldSize, newSize, err)
+			}
+			if oldSize < newSize {
+				tampered := proof
+				tampered.OldTreeHead.RootHash = testDigest(99)
+				tampered.OldTreeHead.Signature = nil
+				if err := VerifyConsistency(tampered, nil); err == nil {
+					t.Fatalf("expected tampered old root to fail %d->%d", oldSize, newSize)
+				}
+			}
+		}
+	}
+
+	verified, err := VerifyLog(logDir, publicKey)
+	if err != nil {
+		t.Fatalf("verify log: %v", err)
+	}
+	if len(verified.Errors) > 0 || verified.TreeSize != size || verified.HeadsSigned != verified.HeadsChecked {
+		t.Fatalf("unexpected log verification: %#v", verified)
+	}
+}
+
+func TestVerifyLogDetectsRemovedEntries(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	logDir := t.TempDir()
+	for index := 0; index < 4; index++ {
+		if _, err := Append(logDir, Entry{Kind: KindPack, Digest: testDigest(index)}, AppendOptions{SigningKey: privateKey}); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	entriesPath := filepath.Join(logDir, entriesFileName)
+	raw, err := os.ReadFile(entriesPath)
+	if err != nil {
+		t.Fatalf("read entries: %v", err)
+	}
+	lines := strings.SplitAfter(string(raw), "\n")
+	if err := os.WriteFile(entriesPath, []byte(lines[0]+strings.Join(lines[2:], "")), 0o600); err != nil {
+		t.Fatalf("rewrite entries: %v", err)
+	}
+	verified, err := VerifyLog(logDir, nil)
+	if err != nil {
+		t.Fatalf("verify log: %v", err)
+	}
+	if len(verified.Errors) == 0 {
+		t.Fatalf("expected removed entry to invalidate issued tree heads: %#v", verified)
+	}
+}
+
+func TestDescribeArtifactClassifiesTraceRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace_1.json")
+	content := []byte(`{"schema_id":"gait.gate.trace","trace_id":"trace_1"}`)
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("write trace: %v", err)
+	}
+	entry, err := DescribeArtifact(path)
+	if err != nil {
+		t.Fatalf("describe artifact: %v", err)
+	}
+	sum := sha256.Sum256(content)
+	if entry.Kind != KindTrace || entry.Ref != "trace_1" || entry.Digest != hex.EncodeToString(sum[:]) {
+		t.Fatalf("unexpected entry: %#v", entry)
+	}
+}
@@ -0,0 +1,185 @@
+package transparency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Tree hashing follows RFC 6962: leaves are hashed with a 0x00 prefix and
+// interior nodes with a 0x01 prefix, so a leaf can never be passed off as a
+// node.
+
+func hashLeaf(data []byte) []byte {
+	hasher := sha256.New()
+	hasher.Write([]byte{0x00})
+	hasher.Write(data)
+	return hasher.Sum(nil)
+}
+
+func hashChildren(left []byte, right []byte) []byte {
+	hasher := sha256.New()
+	hasher.Write([]byte{0x01})
+	hasher.Write(left)
+	hasher.Write(right)
+	return hasher.Sum(nil)
+}
+
+// splitPoint returns the largest power of two smaller than size.
+func splitPoint(size int) int {
+	split := 1
+	for split<<1 < size {
+		split <<= 1
+	}
+	return split
+}
+
+func rootHash(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		empty := sha256.Sum256(nil)
+		return empty[:]
+	case 1:
+		return leaves[0]
+	}
+	split := splitPoint(len(leaves))
+	return hashChildren(rootHash(leaves[:split]), rootHash(leaves[split:]))
+}
+
+func inclusionPath(index int, leaves [][]byte) [][]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	split := splitPoint(len(leaves))
+	if index < split {
+		return append(inclusionPath(index, leaves[:split]), rootHash(leaves[split:]))
+	}
+	return append(inclusionPath(index-split, leaves[split:]), rootHash(leaves[:split]))
+}
+
+func consistencyPath(oldSize int, leaves [][]byte, complete bool) [][]byte {
+	if oldSize == len(leaves) {
+		if complete {
+			return nil
+		}
+		return [][]byte{rootHash(leaves)}
+	}
+	split := splitPoint(len(leaves))
+	if oldSize <= split {
+		return append(consistencyPath(oldSize, leaves[:split], complete), rootHash(leaves[split:]))
+	}
+	return append(consistencyPath(oldSize-split, leaves[split:], false), rootHash(leaves[:split]))
+}
+
+func verifyInclusionPath(index int64, size int64, leaf []byte, path [][]byte, root []byte) error {
+	if index < 0 || index >= size {
+		return fmt.Errorf("leaf index %d outside tree of size %d", index, size)
+	}
+	node, last := index, size-1
+	computed := leaf
+	for _, sibling := range path {
+		if last == 0 {
+			return fmt.Errorf("inclusion path is longer than expected")
+		}
+		if node%2 == 1 || node == last {
+			computed = hashChildren(sibling, computed)
+			for node%2 == 0 && node != 0 {
+				node >>= 1
+				last >>= 1
+			}
+		} else {
+			computed = hashChildren(computed, sibling)
+		}
+		node >>= 1
+		last >>= 1
+	}
+	if last != 0 {
+		return fmt.Errorf("inclusion path is shorter than expected")
+	}
+	if !bytes.Equal(computed, root) {
+		return fmt.Errorf("inclusion path does not lead to root hash")
+	}
+	return nil
+}
+
+func verifyConsistencyPath(oldSize int64, newSize int64, oldRoot []byte, newRoot []byte, path [][]byte) error {
+	switch {
+	case oldSize <= 0 || oldSize > newSize:
+		return fmt.Errorf("invalid consistency sizes %d -> %d", oldSize, newSize)
+	case oldSize == newSize:
+		if len(path) != 0 {
+			return fmt.Errorf("consistency path must be empty for equal tree sizes")
+		}
+		if !bytes.Equal(oldRoot, newRoot) {
+			return fmt.Errorf("root hashes differ for equal tree sizes")
+		}
+		return nil
+	case len(path) == 0:
+		return fmt.Errorf("consistency path is empty")
+	}
+	if oldSize&(oldSize-1) == 0 {
+		path = append([][]byte{oldRoot}, path...)
+	}
+	node, last := oldSize-1, newSize-1
+	for node%2 == 1 {
+		node >>= 1
+		last >>= 1
+	}
+	oldComputed, newComputed := path[0], path[0]
+	for _, sibling := range path[1:] {
+		if last == 0 {
+			return fmt.Errorf("consistency path is longer than expected")
+		}
+		if node%2 == 1 || node == last {
+			oldComputed = hashChildren(sibling, oldComputed)
+			newComputed = hashChildren(sibling, newComputed)
+			for node%2 == 0 && node != 0 {
+				node >>= 1
+				last >>= 1
+			}
+		} else {
+			newComputed = hashChildren(newComputed, sibling)
+		}
+		node >>= 1
+		last >>= 1
+	}
+	if last != 0 {
+		return fmt.Errorf("consistency path is shorter than expected")
+	}
+	if !bytes.Equal(oldComputed, oldRoot) {
+		return fmt.Errorf("consistency path does not lead to old root hash")
+	}
+	if !bytes.Equal(newComputed, newRoot) {
+		return fmt.Errorf("consistency path does not lead to new root hash")
+	}
+	return nil
+}
+
+func encodeHashes(hashes [][]byte) []string {
+	encoded := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		encoded = append(encoded, hex.EncodeToString(hash))
+	}
+	return encoded
+}
+
+func decodeHash(value string) ([]byte, error) {
+	decoded, err := hex.DecodeString(value)
+	if err != nil || len(decoded) != sha256.Size {
+		return nil, fmt.Errorf("invalid sha256 hash %q", value)
+	}
+	return decoded, nil
+}
+
+func decodeHashes(values []string) ([][]byte, error) {
+	decoded := make([][]byte, 0, len(values))
+	for _, value := range values {
+		hash, err := decodeHash(value)
+		if err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, hash)
+	}
+	return decoded, nil
+}
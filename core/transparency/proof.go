@@ -0,0 +1,355 @@
+package transparency
+
+import (
+	"archive/zip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const maxManifestBytes = int64(16 * 1024 * 1024)
+
+type InclusionProof struct {
+	SchemaID      string   `json:"schema_id"`
+	SchemaVersion string   `json:"schema_version"`
+	LeafIndex     int64    `json:"leaf_index"`
+	Entry         Entry    `json:"entry"`
+	LeafHash      string   `json:"leaf_hash"`
+	AuditPath     []string `json:"audit_path"`
+	TreeHead      TreeHead `json:"tree_head"`
+}
+
+type ConsistencyProof struct {
+	SchemaID      string   `json:"schema_id"`
+	SchemaVersion string   `json:"schema_version"`
+	OldTreeHead   TreeHead `json:"old_tree_head"`
+	NewTreeHead   TreeHead `json:"new_tree_head"`
+	Path          []string `json:"path"`
+}
+
+// InclusionResult is the outcome of checking an inclusion proof against an
+// artifact. Status is verified when the audit path and the tree head signature
+// both check out, unsigned when the path checks out but the head signature was
+// not verified, and failed otherwise.
+type InclusionResult struct {
+	Status    string   `json:"status"`
+	LeafIndex int64    `json:"leaf_index"`
+	TreeSize  int64    `json:"tree_size"`
+	RootHash  string   `json:"root_hash"`
+	Kind      string   `json:"kind,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+type ProveOptions struct {
+	Digest     string
+	Index      *int64
+	SigningKey ed25519.PrivateKey
+	Now        time.Time
+}
+
+// ProveInclusion builds an inclusion proof for the first entry matching
+// opts.Digest (or the entry at opts.Index) against a fresh tree head.
+func ProveInclusion(logDir string, opts ProveOptions) (InclusionProof, error) {
+	entries, err := LoadEntries(logDir)
+	if err != nil {
+		return InclusionProof{}, err
+	}
+	index := int64(-1)
+	if opts.Index != nil {
+		index = *opts.Index
+		if index < 0 || index >= int64(len(entries)) {
+			return InclusionProof{}, fmt.Errorf("leaf index %d outside log of size %d", index, len(entries))
+		}
+	} else {
+		digest, err := NormalizeDigest(opts.Digest)
+		if err != nil {
+			return InclusionProof{}, err
+		}
+		for position, entry := range entries {
+			if entry.Digest == digest {
+				index = int64(position)
+				break
+			}
+		}
+		if index < 0 {
+			return InclusionProof{}, fmt.Errorf("digest %s is not in the log", digest)
+		}
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	head, err := issueTreeHead(logDir, entries, opts.SigningKey, now.UTC())
+	if err != nil {
+		return InclusionProof{}, err
+	}
+	leaves, err := leafHashes(entries)
+	if err != nil {
+		return InclusionProof{}, err
+	}
+	return InclusionProof{
+		SchemaID:      inclusionProofSchemaID,
+		SchemaVersion: schemaVersion,
+		LeafIndex:     index,
+		Entry:         entries[index],
+		LeafHash:      hex.EncodeToString(leaves[index]),
+		AuditPath:     encodeHashes(inclusionPath(int(index), leaves)),
+		TreeHead:      head,
+	}, nil
+}
+
+// VerifyInclusion checks proof offline. An empty digest skips the artifact
+// comparison; a nil publicKey skips the tree head signature check.
+func VerifyInclusion(proof InclusionProof, digest string, publicKey ed25519.PublicKey) error {
+	if proof.SchemaID != inclusionProofSchemaID {
+		return fmt.Errorf("unsupported inclusion proof schema %q", proof.SchemaID)
+	}
+	if strings.TrimSpace(digest) != "" {
+		normalized, err := NormalizeDigest(digest)
+		if err != nil {
+			return err
+		}
+		if normalized != proof.Entry.Digest {
+			return fmt.Errorf("artifact digest %s does not match logged digest %s", normalized, proof.Entry.Digest)
+		}
+	}
+	leaf, err := entryLeafHash(proof.Entry)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(hex.EncodeToString(leaf), proof.LeafHash) {
+		return fmt.Errorf("leaf hash does not match logged entry")
+	}
+	path, err := decodeHashes(proof.AuditPath)
+	if err != nil {
+		return err
+	}
+	root, err := decodeHash(strings.ToLower(proof.TreeHead.RootHash))
+	if err != nil {
+		return err
+	}
+	if err := verifyInclusionPath(proof.LeafIndex, proof.TreeHead.TreeSize, leaf, path, root); err != nil {
+		return err
+	}
+	if len(publicKey) > 0 {
+		return VerifyTreeHead(proof.TreeHead, publicKey)
+	}
+	return nil
+}
+
+// CheckInclusion reports the outcome of VerifyInclusion for embedding in
+// artifact verification results.
+func CheckInclusion(proof InclusionProof, digest string, publicKey ed25519.PublicKey) InclusionResult {
+	result := InclusionResult{
+		Status:    "verified",
+		LeafIndex: proof.LeafIndex,
+		TreeSize:  proof.TreeHead.TreeSize,
+		RootHash:  proof.TreeHead.RootHash,
+		Kind:      proof.Entry.Kind,
+	}
+	if err := VerifyInclusion(proof, digest, publicKey); err != nil {
+		result.Status = "failed"
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+	if len(publicKey) == 0 {
+		result.Status = "unsigned"
+		result.Errors = append(result.Errors, "public key not configured; tree head signature not checked")
+	}
+	return result
+}
+
+// ProveConsistency proves that the log at oldSize is a prefix of the log at
+// newSize. A newSize of zero means the current log size.
+func ProveConsistency(logDir string, oldSize int64, newSize int64, signingKey ed25519.PrivateKey, now time.Time) (ConsistencyProof, error) {
+	entries, err := LoadEntries(logDir)
+	if err != nil {
+		return ConsistencyProof{}, err
+	}
+	if newSize == 0 {
+		newSize = int64(len(entries))
+	}
+	if oldSize <= 0 || oldSize > newSize || newSize > int64(len(entries)) {
+		return ConsistencyProof{}, fmt.Errorf("invalid consistency sizes %d -> %d for log of size %d", oldSize, newSize, len(entries))
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+	leaves, err := leafHashes(entries[:newSize])
+	if err != nil {
+		return ConsistencyProof{}, err
+	}
+	oldHead := TreeHead{
+		SchemaID:      treeHeadSchemaID,
+		SchemaVersion: schemaVersion,
+		TreeSize:      oldSize,
+		RootHash:      hex.EncodeToString(rootHash(leaves[:oldSize])),
+		CreatedAt:     now.UTC(),
+	}
+	heads, err := LoadTreeHeads(logDir)
+	if err != nil {
+		return ConsistencyProof{}, err
+	}
+	for position := len(heads) - 1; position >= 0; position-- {
+		if heads[position].TreeSize == oldSize {
+			oldHead = heads[position]
+			break
+		}
+	}
+	var newHead TreeHead
+	if newSize == int64(len(entries)) {
+		newHead, err = issueTreeHead(logDir, entries, signingKey, now.UTC())
+		if err != nil {
+			return ConsistencyProof{}, err
+		}
+	} else {
+		newHead = TreeHead{
+			SchemaID:      treeHeadSchemaID,
+			SchemaVersion: schemaVersion,
+			TreeSize:      newSize,
+			RootHash:      hex.EncodeToString(rootHash(leaves)),
+			CreatedAt:     now.UTC(),
+		}
+		if len(signingKey) > 0 {
+			if newHead, err = SignTreeHead(newHead, signingKey); err != nil {
+				return ConsistencyProof{}, err
+			}
+		}
+	}
+	return ConsistencyProof{
+		SchemaID:      consistencyProofSchemaID,
+		SchemaVersion: schemaVersion,
+		OldTreeHead:   oldHead,
+		NewTreeHead:   newHead,
+		Path:          encodeHashes(consistencyPath(int(oldSize), leaves, true)),
+	}, nil
+}
+
+// VerifyConsistency checks proof offline. When publicKey is set, every signed
+// tree head in the proof must verify and the new head must be signed.
+func VerifyConsistency(proof ConsistencyProof, publicKey ed25519.PublicKey) error {
+	if proof.SchemaID != consistencyProofSchemaID {
+		return fmt.Errorf("unsupported consistency proof schema %q", proof.SchemaID)
+	}
+	oldRoot, err := decodeHash(strings.ToLower(proof.OldTreeHead.RootHash))
+	if err != nil {
+		return err
+	}
+	newRoot, err := decodeHash(strings.ToLower(proof.NewTreeHead.RootHash))
+	if err != nil {
+		return err
+	}
+	path, err := decodeHashes(proof.Path)
+	if err != nil {
+		return err
+	}
+	if err := verifyConsistencyPath(proof.OldTreeHead.TreeSize, proof.NewTreeHead.TreeSize, oldRoot, newRoot, path); err != nil {
+		return err
+	}
+	if len(publicKey) == 0 {
+		return nil
+	}
+	if proof.OldTreeHead.Signature != nil {
+		if err := VerifyTreeHead(proof.OldTreeHead, publicKey); err != nil {
+			return err
+		}
+	}
+	return VerifyTreeHead(proof.NewTreeHead, publicKey)
+}
+
+// DescribeArtifact returns the log entry for the artifact at path. Zip packs
+// and runpacks are identified by the digest of their manifest so the entry
+// stays stable however the zip is re-encoded; everything else by the digest
+// of the file. Kind and Ref come from the record's schema_id and id fields.
+func DescribeArtifact(path string) (Entry, error) {
+	if strings.HasSuffix(strings.ToLower(path), ".zip") {
+		if entry, ok := describeZipArtifact(path); ok {
+			return entry, nil
+		}
+	}
+	// #nosec G304 -- artifact path is explicit local user input.
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Entry{}, fmt.Errorf("read artifact: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	entry := Entry{Kind: KindArtifact, Digest: hex.EncodeToString(sum[:])}
+	var record struct {
+		SchemaID string `json:"schema_id"`
+		TraceID  string `json:"trace_id"`
+	}
+	if json.Unmarshal(content, &record) == nil {
+		switch record.SchemaID {
+		case "gait.gate.trace":
+			entry.Kind, entry.Ref = KindTrace, record.TraceID
+		case "gait.gate.approval_audit_record":
+			entry.Kind, entry.Ref = KindApprovalAudit, record.TraceID
+		case "gait.gate.delegation_audit_record":
+			entry.Kind, entry.Ref = KindDelegationAudit, record.TraceID
+		}
+	}
+	return entry, nil
+}
+
+func describeZipArtifact(path string) (Entry, bool) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return Entry{}, false
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	for _, candidate := range []struct {
+		name string
+		kind string
+	}{
+		{name: "pack_manifest.json", kind: KindPack},
+		{name: "manifest.json", kind: KindRunpack},
+	} {
+		for _, file := range reader.File {
+			if file.Name != candidate.name {
+				continue
+			}
+			manifest, err := readZipManifest(file)
+			if err != nil {
+				return Entry{}, false
+			}
+			sum := sha256.Sum256(manifest)
+			var ids struct {
+				PackID string `json:"pack_id"`
+				RunID  string `json:"run_id"`
+			}
+			_ = json.Unmarshal(manifest, &ids)
+			ref := ids.PackID
+			if ref == "" {
+				ref = ids.RunID
+			}
+			return Entry{Kind: candidate.kind, Digest: hex.EncodeToString(sum[:]), Ref: ref}, true
+		}
+	}
+	return Entry{}, false
+}
+
+func readZipManifest(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", file.Name, err)
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	content, err := io.ReadAll(io.LimitReader(reader, maxManifestBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", file.Name, err)
+	}
+	if int64(len(content)) > maxManifestBytes {
+		return nil, fmt.Errorf("%s exceeds %d bytes", file.Name, maxManifestBytes)
+	}
+	return content, nil
+}
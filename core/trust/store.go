@@ -0,0 +1,330 @@
+package trust
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/fsx"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+const (
+	SchemaID      = "gait.trust.store"
+	SchemaVersion = "1.0.0"
+	StorePathEnv  = "GAIT_TRUST_STORE"
+
+	RoleTraceSigner       = "trace_signer"
+	RoleApprover          = "approver"
+	RoleDelegator         = "delegator"
+	RoleRegistryPublisher = "registry_publisher"
+)
+
+var (
+	ErrKeyUnknown     = errors.New("signing key not in trust store")
+	ErrKeyRevoked     = errors.New("signing key revoked")
+	ErrKeyNotValid    = errors.New("signing key not valid at signing time")
+	ErrRoleNotAllowed = errors.New("signing key not trusted for role")
+	ErrIdentity       = errors.New("signing key bound to a different identity")
+
+	knownRoles = map[string]struct{}{
+		RoleTraceSigner:       {},
+		RoleApprover:          {},
+		RoleDelegator:         {},
+		RoleRegistryPublisher: {},
+	}
+)
+
+// Key is one trusted public key. NotBefore and NotAfter bound the signing
+// time of artifacts the key may verify, so a retired key keeps verifying
+// evidence it signed while it was current. A revoked key verifies nothing.
+type Key struct {
+	KeyID            string     `json:"key_id"`
+	PublicKey        string     `json:"public_key"`
+	Roles            []string   `json:"roles"`
+	Identity         string     `json:"identity,omitempty"`
+	NotBefore        *time.Time `json:"not_before,omitempty"`
+	NotAfter         *time.Time `json:"not_after,omitempty"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	RevocationReason string     `json:"revocation_reason,omitempty"`
+}
+
+type Store struct {
+	SchemaID      string    `json:"schema_id"`
+	SchemaVersion string    `json:"schema_version"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Keys          []Key     `json:"keys"`
+
+	publicKeys map[string]ed25519.PublicKey
+}
+
+// Lookup selects the key that must have produced a signature.
+type Lookup struct {
+	Role     string
+	KeyID    string
+	Identity string
+	SignedAt time.Time
+}
+
+func ResolveStorePath(path string) string {
+	if trimmed := strings.TrimSpace(path); trimmed != "" {
+		return trimmed
+	}
+	return strings.TrimSpace(os.Getenv(StorePathEnv))
+}
+
+func NewStore() *Store {
+	return &Store{SchemaID: SchemaID, SchemaVersion: SchemaVersion, publicKeys: map[string]ed25519.PublicKey{}}
+}
+
+func Load(path string) (*Store, error) {
+	trimmedPath := strings.TrimSpace(path)
+	if trimmedPath == "" {
+		return nil, fmt.Errorf("trust store path is required")
+	}
+	// #nosec G304 -- trust store path is explicit local user input.
+	content, err := os.ReadFile(trimmedPath)
+	if err != nil {
+		return nil, fmt.Errorf("read trust store: %w", err)
+	}
+	var store Store
+	if err := json.Unmarshal(content, &store); err != nil {
+		return nil, fmt.Errorf("parse trust store: %w", err)
+	}
+	if store.SchemaID != SchemaID {
+		return nil, fmt.Errorf("trust store has unsupported schema_id %q", store.SchemaID)
+	}
+	if err := store.normalize(); err != nil {
+		return nil, err
+	}
+	return &store, nil
+}
+
+// LoadOrNew returns an empty store when path does not exist yet.
+func LoadOrNew(path string) (*Store, error) {
+	store, err := Load(path)
+	if err != nil && errors.Is(err, os.ErrNotExist) {
+		return NewStore(), nil
+	}
+	return store, err
+}
+
+func Write(path string, store *Store, now time.Time) error {
+	trimmedPath := strings.TrimSpace(path)
+	if trimmedPath == "" {
+		return fmt.Errorf("trust store path is required")
+	}
+	if store == nil {
+		return fmt.Errorf("trust store is required")
+	}
+	if err := store.normalize(); err != nil {
+		return err
+	}
+	store.SchemaID = SchemaID
+	store.SchemaVersion = SchemaVersion
+	store.UpdatedAt = now.UTC()
+	encoded, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal trust store: %w", err)
+	}
+	encoded = append(encoded, '\n')
+	if err := os.MkdirAll(filepath.Dir(trimmedPath), 0o750); err != nil {
+		return fmt.Errorf("create trust store directory: %w", err)
+	}
+	if err := fsx.WriteFileAtomic(trimmedPath, encoded, 0o600); err != nil {
+		return fmt.Errorf("write trust store: %w", err)
+	}
+	return nil
+}
+
+// Add trusts publicKey for roles. The key id is derived from the key.
+func (s *Store) Add(publicKey ed25519.PublicKey, key Key) (Key, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return Key{}, fmt.Errorf("public key must be %d bytes", ed25519.PublicKeySize)
+	}
+	key.PublicKey = base64.StdEncoding.EncodeToString(publicKey)
+	key.KeyID = sign.KeyID(publicKey)
+	normalized, _, err := normalizeKey(key)
+	if err != nil {
+		return Key{}, err
+	}
+	if _, ok := s.find(normalized.KeyID); ok {
+		return Key{}, fmt.Errorf("key %s already in trust store", normalized.KeyID)
+	}
+	s.Keys = append(s.Keys, normalized)
+	return normalized, s.normalize()
+}
+
+func (s *Store) Revoke(keyID string, at time.Time, reason string) (Key, error) {
+	index, ok := s.find(keyID)
+	if !ok {
+		return Key{}, fmt.Errorf("%w: %s", ErrKeyUnknown, strings.TrimSpace(keyID))
+	}
+	revokedAt := at.UTC()
+	s.Keys[index].RevokedAt = &revokedAt
+	s.Keys[index].RevocationReason = strings.TrimSpace(reason)
+	return s.Keys[index], nil
+}
+
+// Retire closes the validity window at at for every current key sharing a
+// role and identity with the given key, except the key itself. It returns
+// the retired key ids.
+func (s *Store) Retire(keyID string, at time.Time) []string {
+	index, ok := s.find(keyID)
+	if !ok {
+		return nil
+	}
+	successor := s.Keys[index]
+	retiredAt := at.UTC()
+	retired := []string{}
+	for i := range s.Keys {
+		key := &s.Keys[i]
+		if i == index || key.RevokedAt != nil || key.Identity != successor.Identity || !sharesRole(key.Roles, successor.Roles) {
+			continue
+		}
+		if key.NotAfter != nil && !key.NotAfter.After(retiredAt) {
+			continue
+		}
+		key.NotAfter = &retiredAt
+		retired = append(retired, key.KeyID)
+	}
+	return retired
+}
+
+// Resolve returns the public key for lookup, checking role, identity
+// binding, validity window and revocation.
+func (s *Store) Resolve(lookup Lookup) (ed25519.PublicKey, error) {
+	if s == nil {
+		return nil, fmt.Errorf("trust store is required")
+	}
+	keyID := strings.ToLower(strings.TrimSpace(lookup.KeyID))
+	if keyID == "" {
+		return nil, fmt.Errorf("%w: signature has no key_id", ErrKeyUnknown)
+	}
+	index, ok := s.find(keyID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyUnknown, keyID)
+	}
+	key := s.Keys[index]
+	if s.publicKeys == nil {
+		if err := s.normalize(); err != nil {
+			return nil, err
+		}
+	}
+	if key.RevokedAt != nil {
+		return nil, fmt.Errorf("%w: %s at %s", ErrKeyRevoked, keyID, key.RevokedAt.UTC().Format(time.RFC3339))
+	}
+	if role := strings.TrimSpace(lookup.Role); role != "" && !containsString(key.Roles, role) {
+		return nil, fmt.Errorf("%w: %s is not a %s key", ErrRoleNotAllowed, keyID, role)
+	}
+	if identity := strings.TrimSpace(lookup.Identity); key.Identity != "" && identity != key.Identity {
+		return nil, fmt.Errorf("%w: %s is bound to %s", ErrIdentity, keyID, key.Identity)
+	}
+	signedAt := lookup.SignedAt.UTC()
+	if key.NotBefore != nil && signedAt.Before(key.NotBefore.UTC()) {
+		return nil, fmt.Errorf("%w: %s signed at %s before not_before", ErrKeyNotValid, keyID, signedAt.Format(time.RFC3339))
+	}
+	if key.NotAfter != nil && signedAt.After(key.NotAfter.UTC()) {
+		return nil, fmt.Errorf("%w: %s signed at %s after not_after", ErrKeyNotValid, keyID, signedAt.Format(time.RFC3339))
+	}
+	return s.publicKeys[keyID], nil
+}
+
+func (s *Store) find(keyID string) (int, bool) {
+	trimmed := strings.ToLower(strings.TrimSpace(keyID))
+	for index, key := range s.Keys {
+		if key.KeyID == trimmed {
+			return index, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Store) normalize() error {
+	publicKeys := make(map[string]ed25519.PublicKey, len(s.Keys))
+	for index, key := range s.Keys {
+		normalized, publicKey, err := normalizeKey(key)
+		if err != nil {
+			return fmt.Errorf("trust store key %d: %w", index, err)
+		}
+		if _, ok := publicKeys[normalized.KeyID]; ok {
+			return fmt.Errorf("trust store has duplicate key_id %s", normalized.KeyID)
+		}
+		publicKeys[normalized.KeyID] = publicKey
+		s.Keys[index] = normalized
+	}
+	sort.Slice(s.Keys, func(i, j int) bool {
+		return s.Keys[i].KeyID < s.Keys[j].KeyID
+	})
+	s.publicKeys = publicKeys
+	return nil
+}
+
+func normalizeKey(key Key) (Key, ed25519.PublicKey, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key.PublicKey))
+	if err != nil {
+		return Key{}, nil, fmt.Errorf("decode public_key: %w", err)
+	}
+	if len(decoded) != ed25519.PublicKeySize {
+		return Key{}, nil, fmt.Errorf("public_key must be %d bytes", ed25519.PublicKeySize)
+	}
+	publicKey := ed25519.PublicKey(decoded)
+	derivedID := sign.KeyID(publicKey)
+	key.KeyID = strings.ToLower(strings.TrimSpace(key.KeyID))
+	if key.KeyID == "" {
+		key.KeyID = derivedID
+	}
+	if key.KeyID != derivedID {
+		return Key{}, nil, fmt.Errorf("key_id %s does not match public_key", key.KeyID)
+	}
+	key.PublicKey = base64.StdEncoding.EncodeToString(publicKey)
+	roles := make([]string, 0, len(key.Roles))
+	for _, role := range key.Roles {
+		trimmed := strings.ToLower(strings.TrimSpace(role))
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := knownRoles[trimmed]; !ok {
+			return Key{}, nil, fmt.Errorf("unsupported role %q", trimmed)
+		}
+		if !containsString(roles, trimmed) {
+			roles = append(roles, trimmed)
+		}
+	}
+	if len(roles) == 0 {
+		return Key{}, nil, fmt.Errorf("key %s has no roles", key.KeyID)
+	}
+	sort.Strings(roles)
+	key.Roles = roles
+	key.Identity = strings.TrimSpace(key.Identity)
+	key.RevocationReason = strings.TrimSpace(key.RevocationReason)
+	if key.NotBefore != nil && key.NotAfter != nil && key.NotAfter.Before(*key.NotBefore) {
+		return Key{}, nil, fmt.Errorf("key %s not_after precedes not_before", key.KeyID)
+	}
+	return key, publicKey, nil
+}
+
+func sharesRole(left []string, right []string) bool {
+	for _, role := range left {
+		if containsString(right, role) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
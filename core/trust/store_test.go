@@ -0,0 +1,98 @@
+package trust
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+func TestStoreRotationRetiresWithoutBreakingHistory(t *testing.T) {
+	oldKey, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	newKey, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	rotatedAt := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	store := NewStore()
+	old, err := store.Add(oldKey.Public, Key{Roles: []string{"Trace_Signer", RoleApprover}})
+	if err != nil {
+		t.Fatalf("add old key: %v", err)
+	}
+	current, err := store.Add(newKey.Public, Key{Roles: []string{RoleTraceSigner}})
+	if err != nil {
+		t.Fatalf("add new key: %v", err)
+	}
+	if retired := store.Retire(current.KeyID, rotatedAt); len(retired) != 1 || retired[0] != old.KeyID {
+		t.Fatalf("expected old key retired, got %v", retired)
+	}
+
+	path := filepath.Join(t.TempDir(), "trust", "store.json")
+	if err := Write(path, store, rotatedAt); err != nil {
+		t.Fatalf("write store: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("load store: %v", err)
+	}
+
+	before := Lookup{Role: RoleTraceSigner, KeyID: old.KeyID, SignedAt: rotatedAt.Add(-time.Hour)}
+	if publicKey, err := loaded.Resolve(before); err != nil || !publicKey.Equal(oldKey.Public) {
+		t.Fatalf("expected old key to verify history, key=%v err=%v", publicKey, err)
+	}
+	after := before
+	after.SignedAt = rotatedAt.Add(time.Hour)
+	if _, err := loaded.Resolve(after); !errors.Is(err, ErrKeyNotValid) {
+		t.Fatalf("expected ErrKeyNotValid after retirement, got %v", err)
+	}
+	if _, err := loaded.Resolve(Lookup{Role: RoleRegistryPublisher, KeyID: current.KeyID, SignedAt: rotatedAt}); !errors.Is(err, ErrRoleNotAllowed) {
+		t.Fatalf("expected ErrRoleNotAllowed, got %v", err)
+	}
+	if _, err := loaded.Resolve(Lookup{Role: RoleTraceSigner, KeyID: strings.Repeat("0", 64)}); !errors.Is(err, ErrKeyUnknown) {
+		t.Fatalf("expected ErrKeyUnknown, got %v", err)
+	}
+
+	if _, err := loaded.Revoke(old.KeyID, rotatedAt, "compromised"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, err := loaded.Resolve(before); !errors.Is(err, ErrKeyRevoked) {
+		t.Fatalf("expected ErrKeyRevoked, got %v", err)
+	}
+}
+
+func TestLoadRejectsInvalidStores(t *testing.T) {
+	keyPair, err := sign.GenerateKeyPair()
+	if err != nil {
+		t.Fatalf("generate key pair: %v", err)
+	}
+	store := NewStore()
+	if _, err := store.Add(keyPair.Public, Key{Roles: []string{"auditor"}}); err == nil || !strings.Contains(err.Error(), "unsupported role") {
+		t.Fatalf("expected unsupported role error, got %v", err)
+	}
+	if _, err := store.Add(keyPair.Public, Key{Roles: []string{RoleDelegator}}); err != nil {
+		t.Fatalf("add key: %v", err)
+	}
+	if _, err := store.Add(keyPair.Public, Key{Roles: []string{RoleDelegator}}); err == nil {
+		t.Fatalf("expected duplicate key error")
+	}
+
+	workDir := t.TempDir()
+	mismatchPath := filepath.Join(workDir, "mismatch.json")
+	content := `{"schema_id":"gait.trust.store","schema_version":"1.0.0","keys":[{"key_id":"` + strings.Repeat("a", 64) + `","public_key":"` + store.Keys[0].PublicKey + `","roles":["delegator"]}]}`
+	if err := os.WriteFile(mismatchPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("write store: %v", err)
+	}
+	if _, err := Load(mismatchPath); err == nil || !strings.Contains(err.Error(), "does not match public_key") {
+		t.Fatalf("expected key id mismatch error, got %v", err)
+	}
+	if loaded, err := LoadOrNew(filepath.Join(workDir, "missing.json")); err != nil || len(loaded.Keys) != 0 {
+		t.Fatalf("expected empty store for missing path, store=%#v err=%v", loaded, err)
+	}
+}
@@ -0,0 +1,665 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/runpack"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+)
+
+const (
+	defaultTraceListLimit   = 200
+	maxTraceListLimit       = 2000
+	defaultApprovalQueueDir = ".gait-out/approval_queue"
+)
+
+// artifactDirs are walked recursively for traces, runpacks, and session
+// files; the workspace root itself is only scanned one level deep.
+var artifactDirs = []string{"gait-out", ".gait-out"}
+
+type traceFilter struct {
+	verdict  string
+	tool     string
+	identity string
+	since    time.Time
+	until    time.Time
+}
+
+func (handlerValue *handler) handleTraces(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writeError(writer, http.StatusMethodNotAllowed, "expected GET")
+		return
+	}
+	query := request.URL.Query()
+	filter := traceFilter{
+		verdict:  strings.ToLower(strings.TrimSpace(query.Get("verdict"))),
+		tool:     strings.TrimSpace(query.Get("tool")),
+		identity: strings.TrimSpace(query.Get("identity")),
+	}
+	for _, bound := range []struct {
+		name   string
+		target *time.Time
+	}{
+		{name: "since", target: &filter.since},
+		{name: "until", target: &filter.until},
+	} {
+		raw := strings.TrimSpace(query.Get(bound.name))
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeError(writer, http.StatusBadRequest, fmt.Sprintf("invalid %s %q (expected RFC3339)", bound.name, raw))
+			return
+		}
+		*bound.target = parsed.UTC()
+	}
+	limit := defaultTraceListLimit
+	if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > maxTraceListLimit {
+			writeError(writer, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxTraceListLimit))
+			return
+		}
+		limit = parsed
+	}
+
+	workspace, err := filepath.Abs(handlerValue.config.WorkDir)
+	if err != nil {
+		writeJSON(writer, http.StatusInternalServerError, TraceListResponse{OK: false, Error: err.Error()})
+		return
+	}
+	paths, err := collectArtifactFiles(workspace, isTraceFile)
+	if err != nil {
+		writeJSON(writer, http.StatusInternalServerError, TraceListResponse{OK: false, Error: err.Error()})
+		return
+	}
+	traces := make([]TraceSummary, 0, len(paths))
+	for _, tracePath := range paths {
+		trace, readErr := gate.ReadTraceRecord(tracePath)
+		if readErr != nil || trace.SchemaID != "gait.gate.trace" {
+			continue
+		}
+		summary := summarizeTrace(workspacePath(workspace, tracePath), trace)
+		if filter.matches(summary, trace) {
+			traces = append(traces, summary)
+		}
+	}
+	sort.Slice(traces, func(i, j int) bool {
+		if !traces[i].CreatedAt.Equal(traces[j].CreatedAt) {
+			return traces[i].CreatedAt.After(traces[j].CreatedAt)
+		}
+		return traces[i].Path < traces[j].Path
+	})
+	response := TraceListResponse{OK: true, Total: len(traces), Traces: traces}
+	if len(traces) > limit {
+		response.Traces = traces[:limit]
+	}
+	writeJSON(writer, http.StatusOK, response)
+}
+
+func (handlerValue *handler) handleTrace(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writeError(writer, http.StatusMethodNotAllowed, "expected GET")
+		return
+	}
+	workspace, tracePath, ok := handlerValue.resolveQueryPath(writer, request, "path")
+	if !ok {
+		return
+	}
+	trace, err := gate.ReadTraceRecord(tracePath)
+	if err != nil {
+		writeJSON(writer, artifactErrorStatus(err), TraceResponse{OK: false, Path: workspacePath(workspace, tracePath), Error: err.Error()})
+		return
+	}
+	writeJSON(writer, http.StatusOK, TraceResponse{OK: true, Path: workspacePath(workspace, tracePath), Trace: &trace})
+}
+
+func (handlerValue *handler) handleTraceExplain(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writeError(writer, http.StatusMethodNotAllowed, "expected GET")
+		return
+	}
+	workspace, tracePath, ok := handlerValue.resolveQueryPath(writer, request, "path")
+	if !ok {
+		return
+	}
+	response := TraceExplainResponse{TracePath: workspacePath(workspace, tracePath)}
+	trace, err := gate.ReadTraceRecord(tracePath)
+	if err != nil {
+		response.Error = err.Error()
+		writeJSON(writer, artifactErrorStatus(err), response)
+		return
+	}
+	response.RecordedVerdict = trace.Verdict
+
+	policyCandidates := []string{}
+	if raw := strings.TrimSpace(request.URL.Query().Get("policy_path")); raw != "" {
+		policyPath, resolveErr := resolveWorkspacePath(workspace, raw)
+		if resolveErr != nil {
+			writeError(writer, http.StatusBadRequest, resolveErr.Error())
+			return
+		}
+		policyCandidates = append(policyCandidates, policyPath)
+	} else {
+		policyCandidates = localPolicyCandidates(workspace)
+	}
+	policy, policyPath, digestMatch, err := selectPolicy(policyCandidates, trace.PolicyDigest)
+	if err != nil {
+		response.Error = err.Error()
+		writeJSON(writer, http.StatusNotFound, response)
+		return
+	}
+	response.PolicyPath = workspacePath(workspace, policyPath)
+	response.PolicyDigestMatch = digestMatch
+	if !digestMatch {
+		response.Warnings = append(response.Warnings, "policy digest differs from the digest recorded in the trace")
+	}
+
+	explainOptions := gate.BuildPolicyExplainOptions{
+		ProducerVersion: trace.ProducerVersion,
+		CreatedAt:       trace.CreatedAt,
+		TraceID:         trace.TraceID,
+		TracePath:       response.TracePath,
+	}
+	intent, intentPath, found := findIntentByDigest(workspace, trace.IntentDigest)
+	if found {
+		outcome, evalErr := gate.EvaluatePolicyDetailed(policy, intent, gate.EvalOptions{ProducerVersion: trace.ProducerVersion})
+		if evalErr != nil {
+			response.Error = evalErr.Error()
+			writeJSON(writer, http.StatusUnprocessableEntity, response)
+			return
+		}
+		explain := gate.BuildPolicyExplain(policy, outcome, explainOptions)
+		response.Explain = &explain
+		response.IntentPath = workspacePath(workspace, intentPath)
+		response.Replayed = true
+		if outcome.Result.Verdict != trace.Verdict {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("replayed verdict %s differs from recorded verdict %s", outcome.Result.Verdict, trace.Verdict))
+		}
+	} else {
+		explain := gate.BuildPolicyExplain(policy, gate.EvalOutcome{
+			Result: schemagate.GateResult{
+				CreatedAt:       trace.CreatedAt,
+				ProducerVersion: trace.ProducerVersion,
+				Verdict:         trace.Verdict,
+				Violations:      trace.Violations,
+			},
+			MatchedRule: strings.Join(trace.MatchedRuleIDs, ","),
+		}, explainOptions)
+		response.Explain = &explain
+		response.Warnings = append(response.Warnings, "no local intent matches the trace intent_digest; explain is built from the recorded verdict only")
+	}
+	response.OK = true
+	writeJSON(writer, http.StatusOK, response)
+}
+
+func (handlerValue *handler) handleRunpacks(writer http.ResponseWriter, request *http.Request) {
+	handlerValue.listArtifacts(writer, request, isRunpackFile)
+}
+
+func (handlerValue *handler) handleSessions(writer http.ResponseWriter, request *http.Request) {
+	handlerValue.listArtifacts(writer, request, isSessionFile)
+}
+
+func (handlerValue *handler) listArtifacts(writer http.ResponseWriter, request *http.Request, match func(string) bool) {
+	if request.Method != http.MethodGet {
+		writeError(writer, http.StatusMethodNotAllowed, "expected GET")
+		return
+	}
+	workspace, err := filepath.Abs(handlerValue.config.WorkDir)
+	if err != nil {
+		writeJSON(writer, http.StatusInternalServerError, ArtifactListResponse{OK: false, Error: err.Error()})
+		return
+	}
+	paths, err := collectArtifactFiles(workspace, match)
+	if err != nil {
+		writeJSON(writer, http.StatusInternalServerError, ArtifactListResponse{OK: false, Error: err.Error()})
+		return
+	}
+	artifacts := make([]ArtifactEntry, 0, len(paths))
+	for _, artifactPath := range paths {
+		info, statErr := os.Stat(artifactPath)
+		if statErr != nil {
+			continue
+		}
+		artifacts = append(artifacts, ArtifactEntry{
+			Path:       workspacePath(workspace, artifactPath),
+			SizeBytes:  info.Size(),
+			ModifiedAt: info.ModTime().UTC().Format(time.RFC3339Nano),
+		})
+	}
+	sort.Slice(artifacts, func(i, j int) bool {
+		if artifacts[i].ModifiedAt != artifacts[j].ModifiedAt {
+			return artifacts[i].ModifiedAt > artifacts[j].ModifiedAt
+		}
+		return artifacts[i].Path < artifacts[j].Path
+	})
+	writeJSON(writer, http.StatusOK, ArtifactListResponse{OK: true, Artifacts: artifacts})
+}
+
+func (handlerValue *handler) handleRunpack(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writeError(writer, http.StatusMethodNotAllowed, "expected GET")
+		return
+	}
+	workspace, runpackPath, ok := handlerValue.resolveQueryPath(writer, request, "path")
+	if !ok {
+		return
+	}
+	pack, err := runpack.ReadRunpack(runpackPath)
+	if err != nil {
+		writeJSON(writer, artifactErrorStatus(err), RunpackResponse{OK: false, Path: workspacePath(workspace, runpackPath), Error: err.Error()})
+		return
+	}
+	writeJSON(writer, http.StatusOK, RunpackResponse{
+		OK:             true,
+		Path:           workspacePath(workspace, runpackPath),
+		RunID:          pack.Run.RunID,
+		ManifestDigest: pack.Manifest.ManifestDigest,
+		CaptureMode:    pack.Manifest.CaptureMode,
+		CreatedAt:      pack.Run.CreatedAt,
+		Events:         pack.Run.Timeline,
+		Timeline:       buildRunpackTimeline(pack),
+	})
+}
+
+func (handlerValue *handler) handleRunpackDiff(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writeError(writer, http.StatusMethodNotAllowed, "expected GET")
+		return
+	}
+	workspace, leftPath, ok := handlerValue.resolveQueryPath(writer, request, "left")
+	if !ok {
+		return
+	}
+	_, rightPath, ok := handlerValue.resolveQueryPath(writer, request, "right")
+	if !ok {
+		return
+	}
+	privacy := runpack.DiffPrivacy(strings.ToLower(strings.TrimSpace(request.URL.Query().Get("privacy"))))
+	switch privacy {
+	case "", runpack.DiffPrivacyFull, runpack.DiffPrivacyMetadata:
+	default:
+		writeError(writer, http.StatusBadRequest, fmt.Sprintf("unsupported privacy %q", privacy))
+		return
+	}
+	response := RunpackDiffResponse{Left: workspacePath(workspace, leftPath), Right: workspacePath(workspace, rightPath)}
+	diff, err := runpack.DiffRunpacks(leftPath, rightPath, privacy)
+	if err != nil {
+		response.Error = err.Error()
+		writeJSON(writer, artifactErrorStatus(err), response)
+		return
+	}
+	response.OK = true
+	response.Diff = &diff
+	writeJSON(writer, http.StatusOK, response)
+}
+
+func (handlerValue *handler) handleSession(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writeError(writer, http.StatusMethodNotAllowed, "expected GET")
+		return
+	}
+	workspace, sessionPath, ok := handlerValue.resolveQueryPath(writer, request, "path")
+	if !ok {
+		return
+	}
+	response := SessionResponse{Path: workspacePath(workspace, sessionPath)}
+	if strings.HasSuffix(strings.ToLower(sessionPath), ".jsonl") {
+		journal, err := runpack.ReadSessionJournal(sessionPath)
+		if err != nil {
+			response.Error = err.Error()
+			writeJSON(writer, artifactErrorStatus(err), response)
+			return
+		}
+		response.SessionID = journal.SessionID
+		response.RunID = journal.RunID
+		response.Events = journal.Events
+		response.Checkpoints = journal.Checkpoints
+	} else {
+		chain, err := runpack.ReadSessionChain(sessionPath)
+		if err != nil {
+			response.Error = err.Error()
+			writeJSON(writer, artifactErrorStatus(err), response)
+			return
+		}
+		response.SessionID = chain.SessionID
+		response.RunID = chain.RunID
+		response.Checkpoints = chain.Checkpoints
+	}
+	response.OK = true
+	writeJSON(writer, http.StatusOK, response)
+}
+
+func (handlerValue *handler) handleApprovals(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writeError(writer, http.StatusMethodNotAllowed, "expected GET")
+		return
+	}
+	status := strings.ToLower(strings.TrimSpace(request.URL.Query().Get("status")))
+	switch status {
+	case "":
+		status = gate.ApprovalRequestStatusPending
+	case "all", gate.ApprovalRequestStatusPending, gate.ApprovalRequestStatusGranted, gate.ApprovalRequestStatusDenied, gate.ApprovalRequestStatusExpired:
+	default:
+		writeError(writer, http.StatusBadRequest, fmt.Sprintf("unsupported status %q", status))
+		return
+	}
+	queueDir := handlerValue.config.ApprovalQueueDir
+	if !filepath.IsAbs(queueDir) {
+		queueDir = filepath.Join(handlerValue.config.WorkDir, queueDir)
+	}
+	states, err := gate.ListApprovalRequests(queueDir, time.Now().UTC())
+	if err != nil {
+		writeJSON(writer, http.StatusInternalServerError, ApprovalListResponse{OK: false, QueueDir: queueDir, Error: err.Error()})
+		return
+	}
+	approvals := make([]gate.ApprovalRequestState, 0, len(states))
+	for _, state := range states {
+		if status == "all" || state.Status == status {
+			approvals = append(approvals, state)
+		}
+	}
+	writeJSON(writer, http.StatusOK, ApprovalListResponse{OK: true, QueueDir: queueDir, Approvals: approvals})
+}
+
+// resolveQueryPath reads a workspace-relative artifact path from the query
+// string and writes a 400 response when it is missing or escapes the
+// workspace.
+func (handlerValue *handler) resolveQueryPath(writer http.ResponseWriter, request *http.Request, name string) (string, string, bool) {
+	workspace, err := filepath.Abs(handlerValue.config.WorkDir)
+	if err != nil {
+		writeError(writer, http.StatusInternalServerError, err.Error())
+		return "", "", false
+	}
+	resolved, err := resolveWorkspacePath(workspace, request.URL.Query().Get(name))
+	if err != nil {
+		writeError(writer, http.StatusBadRequest, fmt.Sprintf("%s: %v", name, err))
+		return "", "", false
+	}
+	return workspace, resolved, true
+}
+
+func resolveWorkspacePath(workspace string, raw string) (string, error) {
+	trimmed := normalizePathValue(raw)
+	if trimmed == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	candidate := filepath.FromSlash(trimmed)
+	if !filepath.IsAbs(candidate) {
+		candidate = filepath.Join(workspace, candidate)
+	}
+	candidate = filepath.Clean(candidate)
+	relative, err := filepath.Rel(workspace, candidate)
+	if err != nil || !filepath.IsLocal(relative) {
+		return "", fmt.Errorf("path %q is outside the workspace", trimmed)
+	}
+	return candidate, nil
+}
+
+func workspacePath(workspace string, artifactPath string) string {
+	relative, err := filepath.Rel(workspace, artifactPath)
+	if err != nil {
+		return filepath.ToSlash(artifactPath)
+	}
+	return filepath.ToSlash(relative)
+}
+
+func artifactErrorStatus(err error) int {
+	if errors.Is(err, fs.ErrNotExist) {
+		return http.StatusNotFound
+	}
+	return http.StatusUnprocessableEntity
+}
+
+func collectArtifactFiles(workspace string, match func(string) bool) ([]string, error) {
+	paths := []string{}
+	entries, err := os.ReadDir(workspace)
+	if err != nil {
+		return nil, fmt.Errorf("read workspace: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && match(entry.Name()) {
+			paths = append(paths, filepath.Join(workspace, entry.Name()))
+		}
+	}
+	for _, dir := range artifactDirs {
+		root := filepath.Join(workspace, dir)
+		if info, statErr := os.Stat(root); statErr != nil || !info.IsDir() {
+			continue
+		}
+		walkErr := filepath.WalkDir(root, func(walkPath string, entry fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			if !entry.IsDir() && match(entry.Name()) {
+				paths = append(paths, walkPath)
+			}
+			return nil
+		})
+		if walkErr != nil {
+			return nil, fmt.Errorf("walk %s: %w", dir, walkErr)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func isTraceFile(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasPrefix(lower, "trace_") && strings.HasSuffix(lower, ".json")
+}
+
+func isRunpackFile(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasPrefix(lower, "runpack_") && strings.HasSuffix(lower, ".zip")
+}
+
+func isSessionFile(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasSuffix(lower, ".journal.jsonl") || strings.HasSuffix(lower, "_chain.json")
+}
+
+func summarizeTrace(tracePath string, trace schemagate.TraceRecord) TraceSummary {
+	identities := traceIdentities(trace)
+	summary := TraceSummary{
+		Path:           tracePath,
+		TraceID:        trace.TraceID,
+		CreatedAt:      trace.CreatedAt.UTC(),
+		ToolName:       trace.ToolName,
+		Verdict:        trace.Verdict,
+		RunID:          trace.RunID,
+		PolicyID:       trace.PolicyID,
+		PolicyDigest:   trace.PolicyDigest,
+		MatchedRuleIDs: trace.MatchedRuleIDs,
+		Violations:     trace.Violations,
+		Signed:         trace.Signature != nil,
+	}
+	if len(identities) > 0 {
+		summary.Identity = identities[0]
+	}
+	return summary
+}
+
+func traceIdentities(trace schemagate.TraceRecord) []string {
+	identities := []string{}
+	add := func(value string) {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			identities = append(identities, trimmed)
+		}
+	}
+	add(trace.AgentID)
+	if trace.Relationship != nil {
+		for _, link := range trace.Relationship.AgentChain {
+			add(link.Identity)
+		}
+	}
+	add(trace.CredentialSubject)
+	if trace.AgentIdentity != nil {
+		add(trace.AgentIdentity.Owner)
+	}
+	return identities
+}
+
+func (filter traceFilter) matches(summary TraceSummary, trace schemagate.TraceRecord) bool {
+	if filter.verdict != "" && !strings.EqualFold(summary.Verdict, filter.verdict) {
+		return false
+	}
+	if filter.tool != "" && !matchFilterValue(filter.tool, summary.ToolName) {
+		return false
+	}
+	if filter.identity != "" {
+		matched := false
+		for _, identity := range traceIdentities(trace) {
+			if matchFilterValue(filter.identity, identity) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if !filter.since.IsZero() && summary.CreatedAt.Before(filter.since) {
+		return false
+	}
+	if !filter.until.IsZero() && summary.CreatedAt.After(filter.until) {
+		return false
+	}
+	return true
+}
+
+// matchFilterValue compares case-insensitively and treats patterns with a
+// wildcard as path.Match globs, so tool=tool.* selects a tool family.
+func matchFilterValue(pattern string, value string) bool {
+	pattern = strings.ToLower(pattern)
+	value = strings.ToLower(value)
+	if strings.ContainsAny(pattern, "*?[") {
+		matched, err := path.Match(pattern, value)
+		return err == nil && matched
+	}
+	return pattern == value
+}
+
+func localPolicyCandidates(workspace string) []string {
+	candidates := []string{}
+	for _, policyPath := range supportedPolicyPaths {
+		candidates = append(candidates, filepath.Join(workspace, filepath.FromSlash(policyPath)))
+	}
+	for _, pattern := range []string{"*.yaml", "*.yml", filepath.Join(".gait", "*.yaml"), filepath.Join(".gait", "*.yml")} {
+		matches, err := filepath.Glob(filepath.Join(workspace, pattern))
+		if err != nil {
+			continue
+		}
+		sort.Strings(matches)
+		candidates = append(candidates, matches...)
+	}
+	return candidates
+}
+
+// selectPolicy returns the first candidate whose digest matches
+// policyDigest. A single explicit candidate is returned even when the digest
+// differs so callers can explain a trace against an edited policy.
+func selectPolicy(candidates []string, policyDigest string) (gate.Policy, string, bool, error) {
+	for _, candidate := range candidates {
+		if !fileExists(candidate) {
+			continue
+		}
+		policy, err := gate.LoadPolicyFile(candidate)
+		if err != nil {
+			if len(candidates) == 1 {
+				return gate.Policy{}, "", false, err
+			}
+			continue
+		}
+		digest, err := gate.PolicyDigest(policy)
+		if err != nil {
+			continue
+		}
+		if digest == policyDigest || len(candidates) == 1 {
+			return policy, candidate, digest == policyDigest, nil
+		}
+	}
+	if len(candidates) == 1 {
+		return gate.Policy{}, "", false, fmt.Errorf("policy not found: %s", candidates[0])
+	}
+	return gate.Policy{}, "", false, fmt.Errorf("no local policy matches policy_digest %s; pass policy_path", policyDigest)
+}
+
+func findIntentByDigest(workspace string, intentDigest string) (schemagate.IntentRequest, string, bool) {
+	if strings.TrimSpace(intentDigest) == "" {
+		return schemagate.IntentRequest{}, "", false
+	}
+	candidates := []string{}
+	for _, intentPath := range supportedIntentPaths {
+		candidates = append(candidates, filepath.Join(workspace, filepath.FromSlash(intentPath)))
+	}
+	localIntents, err := collectArtifactFiles(workspace, func(name string) bool {
+		lower := strings.ToLower(name)
+		return strings.HasPrefix(lower, "intent") && strings.HasSuffix(lower, ".json")
+	})
+	if err == nil {
+		candidates = append(candidates, localIntents...)
+	}
+	for _, candidate := range candidates {
+		// #nosec G304 -- candidates are restricted to the UI workspace.
+		content, readErr := os.ReadFile(candidate)
+		if readErr != nil {
+			continue
+		}
+		var intent schemagate.IntentRequest
+		if json.Unmarshal(content, &intent) != nil {
+			continue
+		}
+		digest, digestErr := gate.IntentDigest(intent)
+		if digestErr == nil && digest == intentDigest {
+			return intent, candidate, true
+		}
+	}
+	return schemagate.IntentRequest{}, "", false
+}
+
+func buildRunpackTimeline(pack runpack.Runpack) []RunpackTimelineEntry {
+	resultsByIntent := make(map[string]int, len(pack.Results))
+	for index, result := range pack.Results {
+		if _, ok := resultsByIntent[result.IntentID]; !ok {
+			resultsByIntent[result.IntentID] = index
+		}
+	}
+	timeline := make([]RunpackTimelineEntry, 0, len(pack.Intents))
+	for _, intent := range pack.Intents {
+		entry := RunpackTimelineEntry{
+			IntentID:   intent.IntentID,
+			ToolName:   intent.ToolName,
+			IntentAt:   intent.CreatedAt,
+			ArgsDigest: intent.ArgsDigest,
+			Args:       intent.Args,
+		}
+		if index, ok := resultsByIntent[intent.IntentID]; ok {
+			result := pack.Results[index]
+			resultAt := result.CreatedAt
+			entry.Status = result.Status
+			entry.ResultAt = &resultAt
+			entry.ResultDigest = result.ResultDigest
+			entry.Result = result.Result
+		}
+		timeline = append(timeline, entry)
+	}
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].IntentAt.Before(timeline[j].IntentAt)
+	})
+	return timeline
+}
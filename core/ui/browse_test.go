@@ -0,0 +1,244 @@
+package ui
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/runpack"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	schemarunpack "github.com/Clyra-AI/gait/core/schema/v1/runpack"
+)
+
+const browsePolicyYAML = `default_verdict: allow
+rules:
+  - name: block-delete
+    effect: block
+    match:
+      tool_names: [tool.delete]
+`
+
+func newBrowseHandler(t *testing.T, workspace string) http.Handler {
+	t.Helper()
+	handler, err := NewHandler(Config{
+		ExecutablePath: "/tmp/gait",
+		WorkDir:        workspace,
+		Runner: func(_ context.Context, _ string, _ []string) (runResult, error) {
+			return runResult{}, nil
+		},
+	}, http.NotFoundHandler())
+	if err != nil {
+		t.Fatalf("new handler: %v", err)
+	}
+	return handler
+}
+
+func getBrowseJSON(t *testing.T, handler http.Handler, target string, expectedStatus int, out any) {
+	t.Helper()
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, target, nil))
+	if response.Code != expectedStatus {
+		t.Fatalf("GET %s: expected %d got %d body=%s", target, expectedStatus, response.Code, response.Body.String())
+	}
+	if out != nil {
+		if err := json.Unmarshal(response.Body.Bytes(), out); err != nil {
+			t.Fatalf("decode %s: %v", target, err)
+		}
+	}
+}
+
+func testSigningKey(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return privateKey
+}
+
+func emitBrowseTrace(t *testing.T, workspace string, policy gate.Policy, intent schemagate.IntentRequest, tracePath string) {
+	t.Helper()
+	result, err := gate.EvaluatePolicy(policy, intent, gate.EvalOptions{ProducerVersion: "test"})
+	if err != nil {
+		t.Fatalf("evaluate policy: %v", err)
+	}
+	if _, err := gate.EmitSignedTrace(policy, intent, result, gate.EmitTraceOptions{ProducerVersion: "test", SigningPrivateKey: testSigningKey(t), TracePath: filepath.Join(workspace, tracePath)}); err != nil {
+		t.Fatalf("emit trace: %v", err)
+	}
+}
+
+func browseIntent(toolName string, identity string) schemagate.IntentRequest {
+	return schemagate.IntentRequest{
+		SchemaID:      "gait.gate.intent_request",
+		SchemaVersion: "1.0.0",
+		ToolName:      toolName,
+		Args:          map[string]any{"path": "/tmp/out.txt"},
+		Targets:       []schemagate.IntentTarget{{Kind: "path", Value: "/tmp/out.txt"}},
+		Context:       schemagate.IntentContext{Identity: identity, Workspace: "/tmp/workspace", RiskClass: "high", AgentID: identity},
+	}
+}
+
+func TestTraceRoutesFilterAndExplain(t *testing.T) {
+	workspace := t.TempDir()
+	policyPath := filepath.Join(workspace, "policy.yaml")
+	if err := os.WriteFile(policyPath, []byte(browsePolicyYAML), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	policy, err := gate.LoadPolicyFile(policyPath)
+	if err != nil {
+		t.Fatalf("load policy: %v", err)
+	}
+	deleteIntent := browseIntent("tool.delete", "alice")
+	encodedIntent, err := json.Marshal(deleteIntent)
+	if err != nil {
+		t.Fatalf("marshal intent: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workspace, "intent_delete.json"), encodedIntent, 0o600); err != nil {
+		t.Fatalf("write intent: %v", err)
+	}
+	emitBrowseTrace(t, workspace, policy, deleteIntent, "trace_delete.json")
+	emitBrowseTrace(t, workspace, policy, browseIntent("tool.read", "bob"), filepath.Join("gait-out", "mcp-serve", "traces", "trace_read.json"))
+	handler := newBrowseHandler(t, workspace)
+
+	var all TraceListResponse
+	getBrowseJSON(t, handler, "/api/traces", http.StatusOK, &all)
+	if all.Total != 2 || len(all.Traces) != 2 {
+		t.Fatalf("expected both traces, got %#v", all)
+	}
+	var blocked TraceListResponse
+	getBrowseJSON(t, handler, "/api/traces?verdict=block&tool=tool.*&identity=ALICE", http.StatusOK, &blocked)
+	if blocked.Total != 1 || blocked.Traces[0].Path != "trace_delete.json" || !blocked.Traces[0].Signed || blocked.Traces[0].Identity != "alice" {
+		t.Fatalf("unexpected filtered traces: %#v", blocked)
+	}
+	var future TraceListResponse
+	getBrowseJSON(t, handler, "/api/traces?since="+url.QueryEscape(time.Now().Add(time.Hour).UTC().Format(time.RFC3339)), http.StatusOK, &future)
+	if future.Total != 0 {
+		t.Fatalf("expected no traces after since bound, got %#v", future)
+	}
+	getBrowseJSON(t, handler, "/api/traces?since=yesterday", http.StatusBadRequest, nil)
+
+	var trace TraceResponse
+	getBrowseJSON(t, handler, "/api/trace?path=gait-out/mcp-serve/traces/trace_read.json", http.StatusOK, &trace)
+	if trace.Trace == nil || trace.Trace.ToolName != "tool.read" || trace.Trace.Verdict != "allow" {
+		t.Fatalf("unexpected trace response: %#v", trace)
+	}
+	getBrowseJSON(t, handler, "/api/trace?path=../outside.json", http.StatusBadRequest, nil)
+	getBrowseJSON(t, handler, "/api/trace?path=trace_missing.json", http.StatusNotFound, nil)
+
+	var replayed TraceExplainResponse
+	getBrowseJSON(t, handler, "/api/trace/explain?path=trace_delete.json", http.StatusOK, &replayed)
+	if !replayed.Replayed || !replayed.PolicyDigestMatch || replayed.PolicyPath != "policy.yaml" || replayed.IntentPath != "intent_delete.json" ||
+		replayed.Explain == nil || replayed.Explain.Verdict != "block" || replayed.Explain.MatchedRule != "block-delete" || len(replayed.Warnings) != 0 {
+		t.Fatalf("unexpected replayed explain: %#v", replayed)
+	}
+	var recorded TraceExplainResponse
+	getBrowseJSON(t, handler, "/api/trace/explain?path=gait-out/mcp-serve/traces/trace_read.json", http.StatusOK, &recorded)
+	if recorded.Replayed || recorded.Explain == nil || recorded.Explain.Verdict != "allow" || len(recorded.Warnings) != 1 {
+		t.Fatalf("unexpected recorded explain: %#v", recorded)
+	}
+}
+
+func TestRunpackAndSessionRoutes(t *testing.T) {
+	workspace := t.TempDir()
+	createdAt := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	writeRunpack := func(name string, runID string, status string) {
+		t.Helper()
+		_, err := runpack.WriteRunpack(filepath.Join(workspace, "gait-out", name), runpack.RecordOptions{
+			Run: schemarunpack.Run{
+				RunID:     runID,
+				CreatedAt: createdAt,
+				Env:       schemarunpack.RunEnv{OS: "linux", Arch: "amd64", Runtime: "go"},
+				Timeline:  []schemarunpack.TimelineEvt{{Event: "start", TS: createdAt}},
+			},
+			Intents: []schemarunpack.IntentRecord{
+				{IntentID: "intent_2", ToolName: "tool.write", CreatedAt: createdAt.Add(2 * time.Second), ArgsDigest: "2222222222222222222222222222222222222222222222222222222222222222"},
+				{IntentID: "intent_1", ToolName: "tool.read", CreatedAt: createdAt.Add(time.Second), ArgsDigest: "1111111111111111111111111111111111111111111111111111111111111111"},
+			},
+			Results: []schemarunpack.ResultRecord{
+				{IntentID: "intent_1", Status: status, CreatedAt: createdAt.Add(3 * time.Second), ResultDigest: "3333333333333333333333333333333333333333333333333333333333333333"},
+			},
+			Refs:        schemarunpack.Refs{RunID: runID},
+			CaptureMode: "reference",
+		})
+		if err != nil {
+			t.Fatalf("write runpack: %v", err)
+		}
+	}
+	writeRunpack("runpack_left.zip", "run_left", "ok")
+	writeRunpack("runpack_right.zip", "run_right", "error")
+	handler := newBrowseHandler(t, workspace)
+
+	var list ArtifactListResponse
+	getBrowseJSON(t, handler, "/api/runpacks", http.StatusOK, &list)
+	if len(list.Artifacts) != 2 {
+		t.Fatalf("expected two runpacks, got %#v", list)
+	}
+	var pack RunpackResponse
+	getBrowseJSON(t, handler, "/api/runpack?path=gait-out/runpack_left.zip", http.StatusOK, &pack)
+	if pack.RunID != "run_left" || len(pack.Timeline) != 2 || pack.Timeline[0].IntentID != "intent_1" || pack.Timeline[0].Status != "ok" || pack.Timeline[1].ResultAt != nil {
+		t.Fatalf("unexpected runpack timeline: %#v", pack)
+	}
+	var diff RunpackDiffResponse
+	getBrowseJSON(t, handler, "/api/runpacks/diff?left=gait-out/runpack_left.zip&right=gait-out/runpack_right.zip&privacy=metadata", http.StatusOK, &diff)
+	if diff.Diff == nil || !diff.Diff.Summary.ResultsChanged || diff.Diff.Summary.RunIDRight != "run_right" {
+		t.Fatalf("unexpected runpack diff: %#v", diff)
+	}
+	getBrowseJSON(t, handler, "/api/runpacks/diff?left=gait-out/runpack_left.zip&right=gait-out/runpack_right.zip&privacy=raw", http.StatusBadRequest, nil)
+
+	journalPath := filepath.Join(workspace, "gait-out", "sessions", "sess.journal.jsonl")
+	if _, err := runpack.StartSession(journalPath, runpack.SessionStartOptions{SessionID: "sess_1", RunID: "run_session", Now: createdAt}); err != nil {
+		t.Fatalf("start session: %v", err)
+	}
+	if _, err := runpack.AppendSessionEvent(journalPath, runpack.SessionAppendOptions{ToolName: "tool.read", Verdict: "allow", CreatedAt: createdAt}); err != nil {
+		t.Fatalf("append session event: %v", err)
+	}
+	var sessions ArtifactListResponse
+	getBrowseJSON(t, handler, "/api/sessions", http.StatusOK, &sessions)
+	if len(sessions.Artifacts) != 1 || sessions.Artifacts[0].Path != "gait-out/sessions/sess.journal.jsonl" {
+		t.Fatalf("unexpected sessions: %#v", sessions)
+	}
+	var session SessionResponse
+	getBrowseJSON(t, handler, "/api/session?path=gait-out/sessions/sess.journal.jsonl", http.StatusOK, &session)
+	if session.SessionID != "sess_1" || len(session.Events) != 1 || session.Events[0].ToolName != "tool.read" {
+		t.Fatalf("unexpected session: %#v", session)
+	}
+}
+
+func TestApprovalsRouteListsPendingRequests(t *testing.T) {
+	workspace := t.TempDir()
+	queueDir := filepath.Join(workspace, ".gait-out", "approval_queue")
+	if _, _, err := gate.EnqueueApprovalRequest(queueDir, gate.EnqueueApprovalRequestOptions{
+		TraceID:           "trace_1",
+		ToolName:          "tool.delete",
+		Identity:          "alice",
+		IntentDigest:      "1111111111111111111111111111111111111111111111111111111111111111",
+		PolicyDigest:      "2222222222222222222222222222222222222222222222222222222222222222",
+		RequiredScope:     []string{"tool:tool.delete"},
+		MinApprovals:      1,
+		SigningPrivateKey: testSigningKey(t),
+	}); err != nil {
+		t.Fatalf("enqueue approval request: %v", err)
+	}
+	handler := newBrowseHandler(t, workspace)
+
+	var pending ApprovalListResponse
+	getBrowseJSON(t, handler, "/api/approvals", http.StatusOK, &pending)
+	if len(pending.Approvals) != 1 || pending.Approvals[0].Status != gate.ApprovalRequestStatusPending || pending.Approvals[0].Request.ToolName != "tool.delete" {
+		t.Fatalf("unexpected pending approvals: %#v", pending)
+	}
+	var granted ApprovalListResponse
+	getBrowseJSON(t, handler, "/api/approvals?status=granted", http.StatusOK, &granted)
+	if len(granted.Approvals) != 0 {
+		t.Fatalf("expected no granted approvals: %#v", granted)
+	}
+	getBrowseJSON(t, handler, "/api/approvals?status=unknown", http.StatusBadRequest, nil)
+}
@@ -1,12 +1,20 @@
 package ui
 
-import "time"
+import (
+	"time"
+
+	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/runpack"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	schemarunpack "github.com/Clyra-AI/gait/core/schema/v1/runpack"
+)
 
 type Config struct {
-	ExecutablePath string
-	WorkDir        string
-	CommandTimeout time.Duration
-	Runner         Runner
+	ExecutablePath   string
+	WorkDir          string
+	CommandTimeout   time.Duration
+	Runner           Runner
+	ApprovalQueueDir string
 }
 
 type ExecRequest struct {
@@ -56,6 +64,115 @@ type ArtifactState struct {
 	ModifiedAt string `json:"modified_at,omitempty"`
 }
 
+type TraceSummary struct {
+	Path           string    `json:"path"`
+	TraceID        string    `json:"trace_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	ToolName       string    `json:"tool_name"`
+	Verdict        string    `json:"verdict"`
+	Identity       string    `json:"identity,omitempty"`
+	RunID          string    `json:"run_id,omitempty"`
+	PolicyID       string    `json:"policy_id,omitempty"`
+	PolicyDigest   string    `json:"policy_digest"`
+	MatchedRuleIDs []string  `json:"matched_rule_ids,omitempty"`
+	Violations     []string  `json:"violations,omitempty"`
+	Signed         bool      `json:"signed"`
+}
+
+type TraceListResponse struct {
+	OK     bool           `json:"ok"`
+	Total  int            `json:"total"`
+	Traces []TraceSummary `json:"traces"`
+	Error  string         `json:"error,omitempty"`
+}
+
+type TraceResponse struct {
+	OK    bool                    `json:"ok"`
+	Path  string                  `json:"path"`
+	Trace *schemagate.TraceRecord `json:"trace,omitempty"`
+	Error string                  `json:"error,omitempty"`
+}
+
+// TraceExplainResponse carries the policy explain output for a recorded
+// trace. Replayed is true when a local intent matching the trace's
+// intent_digest was re-evaluated; otherwise the explain is rebuilt from the
+// recorded verdict and violations only.
+type TraceExplainResponse struct {
+	OK                bool                      `json:"ok"`
+	TracePath         string                    `json:"trace_path"`
+	PolicyPath        string                    `json:"policy_path,omitempty"`
+	IntentPath        string                    `json:"intent_path,omitempty"`
+	PolicyDigestMatch bool                      `json:"policy_digest_match"`
+	Replayed          bool                      `json:"replayed"`
+	RecordedVerdict   string                    `json:"recorded_verdict"`
+	Explain           *schemagate.PolicyExplain `json:"explain,omitempty"`
+	Warnings          []string                  `json:"warnings,omitempty"`
+	Error             string                    `json:"error,omitempty"`
+}
+
+type ArtifactEntry struct {
+	Path       string `json:"path"`
+	SizeBytes  int64  `json:"size_bytes"`
+	ModifiedAt string `json:"modified_at"`
+}
+
+type ArtifactListResponse struct {
+	OK        bool            `json:"ok"`
+	Artifacts []ArtifactEntry `json:"artifacts"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// RunpackTimelineEntry pairs an intent with the result recorded for it, if
+// any.
+type RunpackTimelineEntry struct {
+	IntentID     string         `json:"intent_id"`
+	ToolName     string         `json:"tool_name"`
+	IntentAt     time.Time      `json:"intent_at"`
+	ArgsDigest   string         `json:"args_digest"`
+	Args         map[string]any `json:"args,omitempty"`
+	Status       string         `json:"status,omitempty"`
+	ResultAt     *time.Time     `json:"result_at,omitempty"`
+	ResultDigest string         `json:"result_digest,omitempty"`
+	Result       map[string]any `json:"result,omitempty"`
+}
+
+type RunpackResponse struct {
+	OK             bool                        `json:"ok"`
+	Path           string                      `json:"path"`
+	RunID          string                      `json:"run_id,omitempty"`
+	ManifestDigest string                      `json:"manifest_digest,omitempty"`
+	CaptureMode    string                      `json:"capture_mode,omitempty"`
+	CreatedAt      time.Time                   `json:"created_at,omitempty"`
+	Events         []schemarunpack.TimelineEvt `json:"events,omitempty"`
+	Timeline       []RunpackTimelineEntry      `json:"timeline"`
+	Error          string                      `json:"error,omitempty"`
+}
+
+type RunpackDiffResponse struct {
+	OK    bool                `json:"ok"`
+	Left  string              `json:"left"`
+	Right string              `json:"right"`
+	Diff  *runpack.DiffResult `json:"diff,omitempty"`
+	Error string              `json:"error,omitempty"`
+}
+
+type SessionResponse struct {
+	OK          bool                              `json:"ok"`
+	Path        string                            `json:"path"`
+	SessionID   string                            `json:"session_id,omitempty"`
+	RunID       string                            `json:"run_id,omitempty"`
+	Events      []schemarunpack.SessionEvent      `json:"events,omitempty"`
+	Checkpoints []schemarunpack.SessionCheckpoint `json:"checkpoints,omitempty"`
+	Error       string                            `json:"error,omitempty"`
+}
+
+type ApprovalListResponse struct {
+	OK        bool                        `json:"ok"`
+	QueueDir  string                      `json:"queue_dir"`
+	Approvals []gate.ApprovalRequestState `json:"approvals"`
+	Error     string                      `json:"error,omitempty"`
+}
+
 type runCommandSpec struct {
 	Command string
 	Argv    []string
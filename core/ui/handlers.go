@@ -58,12 +58,17 @@ func NewHandler(config Config, staticHandler http.Handler) (http.Handler, error)
 	if runner == nil {
 		runner = defaultRunner
 	}
+	approvalQueueDir := strings.TrimSpace(config.ApprovalQueueDir)
+	if approvalQueueDir == "" {
+		approvalQueueDir = defaultApprovalQueueDir
+	}
 	h := &handler{
 		config: Config{
-			ExecutablePath: executable,
-			WorkDir:        workDir,
-			CommandTimeout: timeout,
-			Runner:         runner,
+			ExecutablePath:   executable,
+			WorkDir:          workDir,
+			CommandTimeout:   timeout,
+			Runner:           runner,
+			ApprovalQueueDir: approvalQueueDir,
 		},
 	}
 
@@ -71,6 +76,15 @@ func NewHandler(config Config, staticHandler http.Handler) (http.Handler, error)
 	mux.HandleFunc("/api/health", h.handleHealth)
 	mux.HandleFunc("/api/state", h.handleState)
 	mux.HandleFunc("/api/exec", h.handleExec)
+	mux.HandleFunc("/api/traces", h.handleTraces)
+	mux.HandleFunc("/api/trace", h.handleTrace)
+	mux.HandleFunc("/api/trace/explain", h.handleTraceExplain)
+	mux.HandleFunc("/api/runpacks", h.handleRunpacks)
+	mux.HandleFunc("/api/runpack", h.handleRunpack)
+	mux.HandleFunc("/api/runpacks/diff", h.handleRunpackDiff)
+	mux.HandleFunc("/api/sessions", h.handleSessions)
+	mux.HandleFunc("/api/session", h.handleSession)
+	mux.HandleFunc("/api/approvals", h.handleApprovals)
 	mux.Handle("/", staticHandler)
 	return mux, nil
 }
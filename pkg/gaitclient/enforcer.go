@@ -0,0 +1,286 @@
+package gaitclient
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/credential"
+	"github.com/Clyra-AI/gait/core/enforce"
+	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/mcp"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+const (
+	defaultProducerVersion = "gaitclient"
+	defaultTraceDir        = "./gait-out/traces"
+	defaultJobRoot         = "./gait-out/jobs"
+	killSwitchFetchTimeout = 5 * time.Second
+)
+
+// KillSwitchOptions points an Enforcer at kill-switch state. Source is a local
+// path or an http(s) URL; remote sources must set PublicKey and CachePath.
+type KillSwitchOptions struct {
+	Source    string
+	PublicKey ed25519.PublicKey
+	CachePath string
+	MaxStale  time.Duration
+}
+
+type EnforcerOptions struct {
+	// Policy is used as-is when set; otherwise PolicyPath is loaded once.
+	Policy     *gate.Policy
+	PolicyPath string
+	// KeyConfig selects the trace signing key, as with `--key-mode`.
+	KeyConfig  sign.KeyConfig
+	KillSwitch KillSwitchOptions
+	// RateLimitStore backs rate_limit and destructive_budget. Nil uses an
+	// in-memory store scoped to the Enforcer.
+	RateLimitStore   gate.RateLimitStore
+	CredentialBroker credential.Broker
+	CredentialLedger string
+	// JobRoot holds job state for emergency stop and job constraints. Calls
+	// that name an unknown job are blocked, as with `gait mcp proxy`.
+	JobRoot         string
+	TraceDir        string
+	ToolAnnotations *mcp.ToolAnnotationSnapshot
+	ProducerVersion string
+	Now             func() time.Time
+}
+
+// Enforcer evaluates tool calls in process. It is safe for concurrent use.
+type Enforcer struct {
+	policy       gate.Policy
+	policyDigest string
+	keyPair      sign.KeyPair
+	keyWarnings  []string
+	options      EnforcerOptions
+	mu           sync.Mutex
+	traceSeq     uint64
+}
+
+func NewEnforcer(options EnforcerOptions) (*Enforcer, error) {
+	var policy gate.Policy
+	switch {
+	case options.Policy != nil:
+		policy = *options.Policy
+	case strings.TrimSpace(options.PolicyPath) != "":
+		loaded, err := gate.LoadPolicyFile(strings.TrimSpace(options.PolicyPath))
+		if err != nil {
+			return nil, err
+		}
+		policy = loaded
+	default:
+		return nil, fmt.Errorf("policy or policy path is required")
+	}
+	policyDigest, err := gate.PolicyDigest(policy)
+	if err != nil {
+		return nil, err
+	}
+	keyPair, keyWarnings, err := sign.LoadSigningKey(options.KeyConfig)
+	if err != nil {
+		return nil, fmt.Errorf("load signing key: %w", err)
+	}
+	if options.RateLimitStore == nil {
+		options.RateLimitStore = gate.NewMemoryRateLimitStore()
+	}
+	if strings.TrimSpace(options.JobRoot) == "" {
+		options.JobRoot = defaultJobRoot
+	}
+	if strings.TrimSpace(options.TraceDir) == "" {
+		options.TraceDir = defaultTraceDir
+	}
+	if strings.TrimSpace(options.ProducerVersion) == "" {
+		options.ProducerVersion = defaultProducerVersion
+	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+	return &Enforcer{
+		policy:       policy,
+		policyDigest: policyDigest,
+		keyPair:      keyPair,
+		keyWarnings:  keyWarnings,
+		options:      options,
+	}, nil
+}
+
+func (e *Enforcer) PolicyDigest() string {
+	return e.policyDigest
+}
+
+// Evaluate converts an MCP-style tool call to an intent and enforces it.
+func (e *Enforcer) Evaluate(ctx context.Context, call mcp.ToolCall) (Decision, error) {
+	evalOptions, warnings, err := e.evalOptions(ctx, call.Context.RiskClass)
+	if err != nil {
+		return Decision{}, err
+	}
+	evalResult, err := mcp.EvaluateToolCallWithIntentOptions(e.policy, call, evalOptions, mcp.IntentOptions{
+		ToolAnnotations: e.options.ToolAnnotations,
+	})
+	if err != nil {
+		return Decision{}, err
+	}
+	return e.enforce(ctx, evalResult.Intent, evalResult.Outcome, evalResult.Trust, warnings)
+}
+
+// EvaluateIntent enforces an intent request built by the caller.
+func (e *Enforcer) EvaluateIntent(ctx context.Context, intent schemagate.IntentRequest) (Decision, error) {
+	normalized, err := gate.NormalizeIntent(intent)
+	if err != nil {
+		return Decision{}, err
+	}
+	evalOptions, warnings, err := e.evalOptions(ctx, normalized.Context.RiskClass)
+	if err != nil {
+		return Decision{}, err
+	}
+	outcome, err := gate.EvaluatePolicyDetailed(e.policy, normalized, evalOptions)
+	if err != nil {
+		return Decision{}, err
+	}
+	return e.enforce(ctx, normalized, outcome, nil, warnings)
+}
+
+// evalOptions loads kill-switch state. A missing or unverifiable state fails
+// closed when the source is remote or the call is high/critical risk.
+func (e *Enforcer) evalOptions(ctx context.Context, riskClass string) (gate.EvalOptions, []string, error) {
+	evalOptions := gate.EvalOptions{ProducerVersion: e.options.ProducerVersion}
+	source := strings.TrimSpace(e.options.KillSwitch.Source)
+	if source == "" {
+		return evalOptions, nil, nil
+	}
+	required := gate.IsRemoteKillSwitchSource(source)
+	switch strings.ToLower(strings.TrimSpace(riskClass)) {
+	case "high", "critical":
+		required = true
+	}
+	fetchCtx, cancel := context.WithTimeout(ctx, killSwitchFetchTimeout)
+	defer cancel()
+	fetched, err := gate.FetchKillSwitchState(fetchCtx, gate.KillSwitchFetchOptions{
+		Source:       source,
+		PublicKey:    e.options.KillSwitch.PublicKey,
+		CachePath:    strings.TrimSpace(e.options.KillSwitch.CachePath),
+		MaxStaleness: e.options.KillSwitch.MaxStale,
+		Now:          e.options.Now().UTC(),
+	})
+	if err != nil {
+		if !required {
+			return evalOptions, nil, fmt.Errorf("load kill switch state: %w", err)
+		}
+		evalOptions.RequireKillSwitchState = true
+		evalOptions.KillSwitchStateError = err
+		return evalOptions, nil, nil
+	}
+	evalOptions.KillSwitchState = &fetched.State
+	evalOptions.RequireKillSwitchState = required
+	return evalOptions, fetched.Warnings, nil
+}
+
+func (e *Enforcer) enforce(
+	ctx context.Context,
+	intent schemagate.IntentRequest,
+	outcome gate.EvalOutcome,
+	trust *schemagate.MCPTrustDecision,
+	warnings []string,
+) (Decision, error) {
+	if err := ctx.Err(); err != nil {
+		return Decision{}, err
+	}
+	now := e.options.Now().UTC()
+	result := outcome.Result
+	warnings = mergeUniqueSorted(warnings, e.keyWarnings)
+
+	boundary := enforce.Boundary{
+		Actor:           "gaitclient",
+		DispatchPath:    "gaitclient.enforcer",
+		JournalSource:   "gaitclient",
+		ProducerVersion: e.options.ProducerVersion,
+	}
+	result, stopWarnings := enforce.EmergencyStop(e.options.JobRoot, boundary, intent, result)
+	warnings = mergeUniqueSorted(warnings, stopWarnings)
+
+	var err error
+	result, _, _, err = enforce.RateLimits(e.options.RateLimitStore, outcome, intent, result, now)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	var constraintWarnings []string
+	result, constraintWarnings = enforce.JobConstraints(e.options.JobRoot, boundary, intent, result, enforce.JobChargeOptions{
+		PolicyDigest: e.policyDigest,
+		Cost:         outcome.Cost,
+		Now:          now,
+	})
+	warnings = mergeUniqueSorted(warnings, constraintWarnings)
+
+	var issued *credential.Response
+	var brokerWarnings []string
+	result, issued, brokerWarnings, err = enforce.BrokerCredential(e.policy, outcome, intent, result, enforce.BrokerOptions{
+		Broker:     e.options.CredentialBroker,
+		LedgerPath: e.options.CredentialLedger,
+		Now:        now,
+	})
+	if err != nil {
+		return Decision{}, err
+	}
+	warnings = mergeUniqueSorted(warnings, brokerWarnings)
+	if result.Verdict != VerdictAllow {
+		issued = nil
+	}
+
+	traceResult, err := gate.EmitSignedTrace(e.policy, intent, result, gate.EmitTraceOptions{
+		ProducerVersion:    e.options.ProducerVersion,
+		ContextSource:      outcome.ContextSource,
+		CompositeRiskClass: outcome.CompositeRiskClass,
+		StepVerdicts:       outcome.StepVerdicts,
+		PreApproved:        outcome.PreApproved,
+		PatternID:          outcome.PatternID,
+		RegistryReason:     outcome.RegistryReason,
+		KillSwitch:         outcome.KillSwitch,
+		MCPTrust:           trust,
+		SigningPrivateKey:  e.keyPair.Private,
+		TracePath:          e.nextTracePath(now),
+	})
+	if err != nil {
+		return Decision{}, err
+	}
+	journalPath := enforce.KillSwitchJournalPath(strings.TrimSpace(e.options.KillSwitch.Source), e.options.KillSwitch.CachePath)
+	if err := enforce.AppendKillSwitchJournal(journalPath, boundary, intent, outcome, result, traceResult.Trace.TraceID); err != nil {
+		return Decision{}, err
+	}
+
+	return Decision{
+		Verdict:        result.Verdict,
+		ReasonCodes:    mergeUniqueSorted(nil, result.ReasonCodes),
+		Violations:     mergeUniqueSorted(nil, result.Violations),
+		ToolName:       intent.ToolName,
+		RunID:          intent.Context.RunID,
+		JobID:          intent.Context.JobID,
+		SessionID:      intent.Context.SessionID,
+		PolicyDigest:   traceResult.PolicyDigest,
+		PolicyID:       traceResult.Trace.PolicyID,
+		PolicyVersion:  traceResult.Trace.PolicyVersion,
+		MatchedRuleIDs: traceResult.Trace.MatchedRuleIDs,
+		IntentDigest:   traceResult.IntentDigest,
+		TraceID:        traceResult.Trace.TraceID,
+		TracePath:      traceResult.TracePath,
+		KillSwitch:     outcome.KillSwitch,
+		Warnings:       warnings,
+		Credential:     issued,
+	}, nil
+}
+
+func (e *Enforcer) nextTracePath(now time.Time) string {
+	e.mu.Lock()
+	e.traceSeq++
+	seq := e.traceSeq
+	e.mu.Unlock()
+	return filepath.Join(e.options.TraceDir, fmt.Sprintf("trace_%s_%d_%06d.json", now.Format("20060102T150405.000000000"), os.Getpid(), seq))
+}
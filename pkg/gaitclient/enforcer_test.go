@@ -0,0 +1,149 @@
+package gaitclient
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/Clyra-AI/gait/core/credential"
+	"github.com/Clyra-AI/gait/core/gate"
+	"github.com/Clyra-AI/gait/core/jobruntime"
+	"github.com/Clyra-AI/gait/core/mcp"
+	sign "github.com/Clyra-AI/proof/signing"
+)
+
+const enforcerTestPolicy = `
+default_verdict: allow
+rules:
+  - name: block-delete
+    priority: 1
+    effect: block
+    match:
+      tool_names: [tool.delete]
+  - name: limited-read
+    priority: 2
+    effect: allow
+    rate_limit:
+      requests: 1
+      scope: tool_identity
+      window: minute
+    match:
+      tool_names: [tool.read]
+  - name: brokered-fetch
+    priority: 3
+    effect: allow
+    require_broker_credential: true
+    broker_reference: egress
+    broker_scopes: [export]
+    match:
+      tool_names: [tool.fetch]
+`
+
+func newTestEnforcer(t *testing.T, options EnforcerOptions) *Enforcer {
+	t.Helper()
+	policy, err := gate.ParsePolicyYAML([]byte(enforcerTestPolicy))
+	if err != nil {
+		t.Fatalf("parse policy: %v", err)
+	}
+	workDir := t.TempDir()
+	options.Policy = &policy
+	options.KeyConfig = sign.KeyConfig{Mode: sign.ModeDev}
+	options.TraceDir = filepath.Join(workDir, "traces")
+	if options.JobRoot == "" {
+		options.JobRoot = filepath.Join(workDir, "jobs")
+	}
+	options.CredentialLedger = filepath.Join(workDir, "credential_leases.jsonl")
+	enforcer, err := NewEnforcer(options)
+	if err != nil {
+		t.Fatalf("new enforcer: %v", err)
+	}
+	return enforcer
+}
+
+func testCall(name string) mcp.ToolCall {
+	return mcp.ToolCall{
+		Name:    name,
+		Args:    map[string]any{"path": "/tmp/out.txt"},
+		Targets: []mcp.Target{{Kind: "path", Value: "/tmp/out.txt", Operation: "read"}},
+		Context: mcp.CallContext{Identity: "alice", Workspace: "/repo", RiskClass: "low", SessionID: "sess-1", RunID: "run-1"},
+	}
+}
+
+func TestEnforcerAppliesPolicyRateLimitsAndTraces(t *testing.T) {
+	enforcer := newTestEnforcer(t, EnforcerOptions{})
+	ctx := context.Background()
+
+	blocked, err := enforcer.Evaluate(ctx, testCall("tool.delete"))
+	if err != nil {
+		t.Fatalf("evaluate delete: %v", err)
+	}
+	if blocked.Allowed() || blocked.Verdict != VerdictBlock || blocked.PolicyDigest != enforcer.PolicyDigest() {
+		t.Fatalf("expected policy block, got %#v", blocked)
+	}
+	if blocked.TraceID == "" || blocked.TracePath == "" {
+		t.Fatalf("expected signed trace, got %#v", blocked)
+	}
+	if _, err := os.Stat(blocked.TracePath); err != nil {
+		t.Fatalf("trace not written: %v", err)
+	}
+
+	first, err := enforcer.Evaluate(ctx, testCall("tool.read"))
+	if err != nil || !first.Allowed() {
+		t.Fatalf("expected first read to be allowed, decision=%#v err=%v", first, err)
+	}
+	second, err := enforcer.Evaluate(ctx, testCall("tool.read"))
+	if err != nil || second.Allowed() || !slices.Contains(second.ReasonCodes, "rate_limit_exceeded") {
+		t.Fatalf("expected rate limit block, decision=%#v err=%v", second, err)
+	}
+	if first.TracePath == second.TracePath {
+		t.Fatalf("expected distinct trace paths, got %s", first.TracePath)
+	}
+}
+
+func TestEnforcerBrokersCredentials(t *testing.T) {
+	ctx := context.Background()
+	missing, err := newTestEnforcer(t, EnforcerOptions{}).Evaluate(ctx, testCall("tool.fetch"))
+	if err != nil || missing.Allowed() || !slices.Contains(missing.ReasonCodes, "broker_credential_required") {
+		t.Fatalf("expected missing broker block, decision=%#v err=%v", missing, err)
+	}
+
+	brokered, err := newTestEnforcer(t, EnforcerOptions{CredentialBroker: credential.StubBroker{}}).Evaluate(ctx, testCall("tool.fetch"))
+	if err != nil || !brokered.Allowed() || !slices.Contains(brokered.ReasonCodes, "broker_credential_present") {
+		t.Fatalf("expected brokered allow, decision=%#v err=%v", brokered, err)
+	}
+	if brokered.Credential == nil || brokered.Credential.CredentialRef == "" || !reflect.DeepEqual(brokered.Credential.Scope, []string{"export"}) {
+		t.Fatalf("expected issued credential, got %#v", brokered.Credential)
+	}
+}
+
+func TestEnforcerChargesJobs(t *testing.T) {
+	jobRoot := filepath.Join(t.TempDir(), "jobs")
+	enforcer := newTestEnforcer(t, EnforcerOptions{JobRoot: jobRoot})
+	if _, err := jobruntime.Submit(jobRoot, jobruntime.SubmitOptions{
+		JobID:        "job-1",
+		PolicyDigest: enforcer.PolicyDigest(),
+		Constraints:  jobruntime.JobConstraints{MaxToolCalls: 1},
+	}); err != nil {
+		t.Fatalf("submit job: %v", err)
+	}
+	call := testCall("tool.write")
+	call.Context.JobID = "job-1"
+
+	ctx := context.Background()
+	if decision, err := enforcer.Evaluate(ctx, call); err != nil || !decision.Allowed() {
+		t.Fatalf("expected first job call to be allowed, decision=%#v err=%v", decision, err)
+	}
+	decision, err := enforcer.Evaluate(ctx, call)
+	if err != nil || decision.Allowed() || !slices.Contains(decision.ReasonCodes, jobruntime.ReasonJobToolCallBudgetExhausted) {
+		t.Fatalf("expected job budget block, decision=%#v err=%v", decision, err)
+	}
+
+	call.Context.JobID = "job-missing"
+	decision, err = enforcer.Evaluate(ctx, call)
+	if err != nil || decision.Allowed() || !slices.Contains(decision.ReasonCodes, "emergency_stop_state_unavailable") {
+		t.Fatalf("expected unknown job to fail closed, decision=%#v err=%v", decision, err)
+	}
+}
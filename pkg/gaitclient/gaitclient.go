@@ -0,0 +1,98 @@
+// Package gaitclient embeds Gait enforcement in Go agents.
+//
+// An Enforcer runs the same pipeline as `gait gate eval` and `gait mcp proxy`
+// in process: policy evaluation, kill switch, rate limits, job constraints,
+// brokered credentials and signed trace emission. A Client sends calls to a
+// running `gait mcp serve`. Both implement Evaluator, which Guard uses to
+// block tool functions and HTTP handlers before they execute and to record
+// each call in a session journal.
+package gaitclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Clyra-AI/gait/core/credential"
+	"github.com/Clyra-AI/gait/core/mcp"
+	schemagate "github.com/Clyra-AI/gait/core/schema/v1/gate"
+)
+
+const (
+	VerdictAllow           = "allow"
+	VerdictBlock           = "block"
+	VerdictRequireApproval = "require_approval"
+	VerdictDryRun          = "dry_run"
+)
+
+// ErrBlocked matches every *BlockedError.
+var ErrBlocked = errors.New("gait blocked tool call")
+
+// Evaluator decides whether a tool call may execute.
+type Evaluator interface {
+	Evaluate(ctx context.Context, call mcp.ToolCall) (Decision, error)
+}
+
+// Decision is the enforced outcome of one tool call. Fields mirror the
+// `gait mcp serve` evaluate response.
+type Decision struct {
+	Verdict        string                         `json:"verdict"`
+	ReasonCodes    []string                       `json:"reason_codes,omitempty"`
+	Violations     []string                       `json:"violations,omitempty"`
+	ToolName       string                         `json:"tool_name,omitempty"`
+	RunID          string                         `json:"run_id,omitempty"`
+	JobID          string                         `json:"job_id,omitempty"`
+	SessionID      string                         `json:"session_id,omitempty"`
+	PolicyDigest   string                         `json:"policy_digest,omitempty"`
+	PolicyID       string                         `json:"policy_id,omitempty"`
+	PolicyVersion  string                         `json:"policy_version,omitempty"`
+	MatchedRuleIDs []string                       `json:"matched_rule_ids,omitempty"`
+	IntentDigest   string                         `json:"intent_digest,omitempty"`
+	TraceID        string                         `json:"trace_id,omitempty"`
+	TracePath      string                         `json:"trace_path,omitempty"`
+	KillSwitch     *schemagate.KillSwitchDecision `json:"kill_switch,omitempty"`
+	Warnings       []string                       `json:"warnings,omitempty"`
+	// Credential is the brokered credential issued for an allowed call. It is
+	// only set by an in-process Enforcer and never serialized.
+	Credential *credential.Response `json:"-"`
+}
+
+func (d Decision) Allowed() bool {
+	return d.Verdict == VerdictAllow
+}
+
+// BlockedError is returned by Guard when a call is not allowed.
+type BlockedError struct {
+	Decision Decision
+}
+
+func (e *BlockedError) Error() string {
+	if len(e.Decision.ReasonCodes) == 0 {
+		return fmt.Sprintf("gait %s: %s", e.Decision.Verdict, e.Decision.ToolName)
+	}
+	return fmt.Sprintf("gait %s: %s (%s)", e.Decision.Verdict, e.Decision.ToolName, strings.Join(e.Decision.ReasonCodes, ","))
+}
+
+func (e *BlockedError) Is(target error) bool {
+	return target == ErrBlocked
+}
+
+func mergeUniqueSorted(values []string, extra []string) []string {
+	seen := map[string]struct{}{}
+	out := make([]string, 0, len(values)+len(extra))
+	for _, value := range append(append([]string{}, values...), extra...) {
+		trimmed := strings.TrimSpace(value)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		out = append(out, trimmed)
+	}
+	sort.Strings(out)
+	return out
+}
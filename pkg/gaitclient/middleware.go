@@ -0,0 +1,225 @@
+package gaitclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/mcp"
+	"github.com/Clyra-AI/gait/core/runpack"
+)
+
+// ReasonToolExecutionFailed is appended to the journaled reason codes of an
+// allowed call whose tool function returned an error or whose handler
+// answered with a 5xx status.
+const ReasonToolExecutionFailed = "tool_execution_failed"
+
+type GuardOptions struct {
+	Evaluator Evaluator
+	// Context is copied into every call built by WrapTool and Middleware.
+	Context mcp.CallContext
+	// Targets derives call targets from tool arguments for WrapTool.
+	Targets func(toolName string, args map[string]any) []mcp.Target
+	// SessionJournal enables recording. SessionID and RunID are required with it.
+	SessionJournal  string
+	SessionID       string
+	RunID           string
+	ProducerVersion string
+	Now             func() time.Time
+}
+
+// Guard enforces decisions before tool code runs and records each call.
+type Guard struct {
+	options GuardOptions
+}
+
+type decisionContextKey struct{}
+
+func NewGuard(options GuardOptions) (*Guard, error) {
+	if options.Evaluator == nil {
+		return nil, fmt.Errorf("evaluator is required")
+	}
+	if strings.TrimSpace(options.ProducerVersion) == "" {
+		options.ProducerVersion = defaultProducerVersion
+	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+	if journal := strings.TrimSpace(options.SessionJournal); journal != "" {
+		if _, err := runpack.StartSession(journal, runpack.SessionStartOptions{
+			SessionID:       options.SessionID,
+			RunID:           options.RunID,
+			ProducerVersion: options.ProducerVersion,
+			Now:             options.Now().UTC(),
+		}); err != nil {
+			return nil, fmt.Errorf("start session journal: %w", err)
+		}
+	}
+	return &Guard{options: options}, nil
+}
+
+// Check evaluates the call. Evaluation errors are returned as-is and non-allow
+// verdicts as *BlockedError; blocked calls are journaled immediately.
+func (g *Guard) Check(ctx context.Context, call mcp.ToolCall) (Decision, error) {
+	decision, err := g.options.Evaluator.Evaluate(ctx, call)
+	if err != nil {
+		return Decision{}, fmt.Errorf("evaluate %s: %w", call.Name, err)
+	}
+	if decision.ToolName == "" {
+		decision.ToolName = call.Name
+	}
+	if !decision.Allowed() {
+		if recordErr := g.Record(decision, nil); recordErr != nil {
+			return decision, recordErr
+		}
+		return decision, &BlockedError{Decision: decision}
+	}
+	return decision, nil
+}
+
+// Record appends the decision and the tool outcome to the session journal.
+func (g *Guard) Record(decision Decision, execErr error) error {
+	journal := strings.TrimSpace(g.options.SessionJournal)
+	if journal == "" {
+		return nil
+	}
+	reasonCodes := decision.ReasonCodes
+	if execErr != nil {
+		reasonCodes = mergeUniqueSorted(reasonCodes, []string{ReasonToolExecutionFailed})
+	}
+	if _, err := runpack.AppendSessionEvent(journal, runpack.SessionAppendOptions{
+		CreatedAt:       g.options.Now().UTC(),
+		ProducerVersion: g.options.ProducerVersion,
+		ToolName:        decision.ToolName,
+		IntentDigest:    decision.IntentDigest,
+		PolicyDigest:    decision.PolicyDigest,
+		PolicyID:        decision.PolicyID,
+		PolicyVersion:   decision.PolicyVersion,
+		MatchedRuleIDs:  decision.MatchedRuleIDs,
+		TraceID:         decision.TraceID,
+		TracePath:       decision.TracePath,
+		Verdict:         decision.Verdict,
+		ReasonCodes:     reasonCodes,
+		Violations:      decision.Violations,
+	}); err != nil {
+		return fmt.Errorf("append session journal: %w", err)
+	}
+	return nil
+}
+
+// ToolFunc is a tool implementation that takes decoded arguments.
+type ToolFunc[T any] func(ctx context.Context, args map[string]any) (T, error)
+
+// WrapTool returns fn guarded by g. fn only runs on an allow verdict and
+// receives a context carrying the Decision (see DecisionFromContext).
+func WrapTool[T any](g *Guard, toolName string, fn ToolFunc[T]) ToolFunc[T] {
+	return func(ctx context.Context, args map[string]any) (T, error) {
+		var zero T
+		call := mcp.ToolCall{Name: toolName, Args: args, Context: g.options.Context}
+		if g.options.Targets != nil {
+			call.Targets = g.options.Targets(toolName, args)
+		}
+		decision, err := g.Check(ctx, call)
+		if err != nil {
+			return zero, err
+		}
+		result, execErr := fn(context.WithValue(ctx, decisionContextKey{}, decision), args)
+		if recordErr := g.Record(decision, execErr); recordErr != nil && execErr == nil {
+			return result, recordErr
+		}
+		return result, execErr
+	}
+}
+
+// Middleware guards an http.Handler. toolCall maps each request to the tool
+// call to evaluate; its Context is merged over the Guard context. Blocked
+// requests get a 403 JSON body and never reach next.
+func (g *Guard) Middleware(toolCall func(*http.Request) (mcp.ToolCall, error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			call, err := toolCall(request)
+			if err != nil {
+				writeMiddlewareJSON(writer, http.StatusBadRequest, map[string]any{"ok": false, "error": err.Error()})
+				return
+			}
+			call.Context = mergeCallContext(g.options.Context, call.Context)
+			decision, err := g.Check(request.Context(), call)
+			if err != nil {
+				var blocked *BlockedError
+				if errors.As(err, &blocked) {
+					writeMiddlewareJSON(writer, http.StatusForbidden, struct {
+						OK bool `json:"ok"`
+						Decision
+					}{Decision: blocked.Decision})
+					return
+				}
+				writeMiddlewareJSON(writer, http.StatusServiceUnavailable, map[string]any{"ok": false, "error": err.Error()})
+				return
+			}
+			recorder := &statusRecorder{ResponseWriter: writer, status: http.StatusOK}
+			next.ServeHTTP(recorder, request.WithContext(context.WithValue(request.Context(), decisionContextKey{}, decision)))
+			var execErr error
+			if recorder.status >= http.StatusInternalServerError {
+				execErr = fmt.Errorf("handler returned status %d", recorder.status)
+			}
+			_ = g.Record(decision, execErr)
+		})
+	}
+}
+
+// DecisionFromContext returns the allow decision for the guarded call.
+func DecisionFromContext(ctx context.Context) (Decision, bool) {
+	decision, ok := ctx.Value(decisionContextKey{}).(Decision)
+	return decision, ok
+}
+
+func mergeCallContext(base mcp.CallContext, override mcp.CallContext) mcp.CallContext {
+	encodedBase, err := json.Marshal(base)
+	if err != nil {
+		return override
+	}
+	encodedOverride, err := json.Marshal(override)
+	if err != nil {
+		return override
+	}
+	merged := mcp.CallContext{}
+	if json.Unmarshal(encodedBase, &merged) != nil || json.Unmarshal(encodedOverride, &merged) != nil {
+		return override
+	}
+	return merged
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(payload []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(payload)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func writeMiddlewareJSON(writer http.ResponseWriter, status int, payload any) {
+	writer.Header().Set("content-type", "application/json")
+	writer.WriteHeader(status)
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(payload)
+}
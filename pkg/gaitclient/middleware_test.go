@@ -0,0 +1,181 @@
+package gaitclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Clyra-AI/gait/core/mcp"
+)
+
+type staticEvaluator map[string]Decision
+
+func (e staticEvaluator) Evaluate(_ context.Context, call mcp.ToolCall) (Decision, error) {
+	decision, ok := e[call.Name]
+	if !ok {
+		return Decision{}, fmt.Errorf("no decision for %s", call.Name)
+	}
+	decision.ToolName = call.Name
+	return decision, nil
+}
+
+func newTestGuard(t *testing.T) (*Guard, string) {
+	t.Helper()
+	journal := filepath.Join(t.TempDir(), "session.journal.jsonl")
+	guard, err := NewGuard(GuardOptions{
+		Evaluator: staticEvaluator{
+			"tool.read":   {Verdict: VerdictAllow, TraceID: "trace-read"},
+			"tool.delete": {Verdict: VerdictBlock, ReasonCodes: []string{"blocked_by_rule"}},
+		},
+		Context:        mcp.CallContext{Identity: "alice", Workspace: "/repo", RiskClass: "low"},
+		SessionJournal: journal,
+		SessionID:      "sess-1",
+		RunID:          "run-1",
+	})
+	if err != nil {
+		t.Fatalf("new guard: %v", err)
+	}
+	return guard, journal
+}
+
+func TestWrapToolBlocksBeforeExecutionAndJournals(t *testing.T) {
+	guard, journal := newTestGuard(t)
+	ctx := context.Background()
+	executed := []string{}
+	read := WrapTool(guard, "tool.read", func(ctx context.Context, args map[string]any) (string, error) {
+		decision, ok := DecisionFromContext(ctx)
+		if !ok || decision.TraceID != "trace-read" {
+			t.Fatalf("expected decision in tool context, got %#v", decision)
+		}
+		executed = append(executed, "tool.read")
+		if args["fail"] == true {
+			return "", errors.New("disk full")
+		}
+		return "contents", nil
+	})
+	remove := WrapTool(guard, "tool.delete", func(context.Context, map[string]any) (string, error) {
+		executed = append(executed, "tool.delete")
+		return "", nil
+	})
+	unknown := WrapTool(guard, "tool.unknown", func(context.Context, map[string]any) (string, error) {
+		executed = append(executed, "tool.unknown")
+		return "", nil
+	})
+
+	if out, err := read(ctx, map[string]any{}); err != nil || out != "contents" {
+		t.Fatalf("expected read to run, out=%q err=%v", out, err)
+	}
+	if _, err := read(ctx, map[string]any{"fail": true}); err == nil || err.Error() != "disk full" {
+		t.Fatalf("expected tool error to pass through, got %v", err)
+	}
+	_, err := remove(ctx, nil)
+	var blocked *BlockedError
+	if !errors.Is(err, ErrBlocked) || !errors.As(err, &blocked) || blocked.Decision.Verdict != VerdictBlock {
+		t.Fatalf("expected blocked error, got %v", err)
+	}
+	if _, err := unknown(ctx, nil); err == nil || errors.Is(err, ErrBlocked) {
+		t.Fatalf("expected evaluator error to fail closed, got %v", err)
+	}
+	if !slices.Equal(executed, []string{"tool.read", "tool.read"}) {
+		t.Fatalf("unexpected executions: %v", executed)
+	}
+
+	raw, err := os.ReadFile(journal)
+	if err != nil {
+		t.Fatalf("read journal: %v", err)
+	}
+	content := string(raw)
+	if strings.Count(content, `"tool_name":"tool.read"`) != 2 || strings.Count(content, `"tool_name":"tool.delete"`) != 1 || !strings.Contains(content, ReasonToolExecutionFailed) {
+		t.Fatalf("unexpected journal content: %s", content)
+	}
+}
+
+func TestMiddlewareGuardsHandler(t *testing.T) {
+	guard, _ := newTestGuard(t)
+	calls := 0
+	handler := guard.Middleware(func(request *http.Request) (mcp.ToolCall, error) {
+		return mcp.ToolCall{Name: strings.TrimPrefix(request.URL.Path, "/")}, nil
+	})(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		calls++
+		if _, ok := DecisionFromContext(request.Context()); !ok {
+			t.Fatalf("expected decision in request context")
+		}
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/tool.read", nil))
+	if recorder.Code != http.StatusNoContent || calls != 1 {
+		t.Fatalf("expected allowed request to reach handler, status=%d calls=%d", recorder.Code, calls)
+	}
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/tool.delete", nil))
+	if recorder.Code != http.StatusForbidden || calls != 1 || !strings.Contains(recorder.Body.String(), `"verdict":"block"`) {
+		t.Fatalf("expected blocked request, status=%d calls=%d body=%s", recorder.Code, calls, recorder.Body.String())
+	}
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/tool.unknown", nil))
+	if recorder.Code != http.StatusServiceUnavailable || calls != 1 {
+		t.Fatalf("expected evaluator failure to fail closed, status=%d calls=%d", recorder.Code, calls)
+	}
+}
+
+func TestClientEvaluatesAgainstServe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/v1/evaluate" || request.Header.Get("authorization") != "Bearer secret" {
+			writer.WriteHeader(http.StatusUnauthorized)
+			_, _ = io.WriteString(writer, `{"ok":false,"error":"unauthorized"}`)
+			return
+		}
+		var input struct {
+			Adapter string       `json:"adapter"`
+			Call    mcp.ToolCall `json:"call"`
+			RunID   string       `json:"run_id"`
+		}
+		if err := json.NewDecoder(request.Body).Decode(&input); err != nil || input.Adapter != "mcp" || input.RunID != "run-1" {
+			t.Errorf("unexpected request: %#v err=%v", input, err)
+		}
+		writer.Header().Set("content-type", "application/json")
+		if input.Call.Name == "tool.delete" {
+			writer.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(writer, `{"ok":true,"tool_name":"tool.delete","verdict":"block","reason_codes":["blocked_by_rule"],"exit_code":3}`)
+			return
+		}
+		_, _ = io.WriteString(writer, `{"ok":true,"tool_name":"tool.read","verdict":"allow","trace_id":"trace-1","exit_code":0}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientOptions{BaseURL: server.URL, Token: "secret", RunID: "run-1"})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	ctx := context.Background()
+	allowed, err := client.Evaluate(ctx, mcp.ToolCall{Name: "tool.read"})
+	if err != nil || !allowed.Allowed() || allowed.TraceID != "trace-1" {
+		t.Fatalf("expected allow, decision=%#v err=%v", allowed, err)
+	}
+	blocked, err := client.Evaluate(ctx, mcp.ToolCall{Name: "tool.delete"})
+	if err != nil || blocked.Allowed() || !slices.Equal(blocked.ReasonCodes, []string{"blocked_by_rule"}) {
+		t.Fatalf("expected strict-mode block decision, decision=%#v err=%v", blocked, err)
+	}
+
+	unauthorized, err := NewClient(ClientOptions{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	if _, err := unauthorized.Evaluate(ctx, mcp.ToolCall{Name: "tool.read"}); err == nil || !strings.Contains(err.Error(), "unauthorized") {
+		t.Fatalf("expected auth error, got %v", err)
+	}
+	if _, err := NewClient(ClientOptions{BaseURL: "localhost:8787"}); err == nil {
+		t.Fatalf("expected relative base url to be rejected")
+	}
+}
@@ -0,0 +1,116 @@
+package gaitclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Clyra-AI/gait/core/mcp"
+)
+
+const (
+	defaultClientTimeout   = 30 * time.Second
+	maxClientResponseBytes = 4 << 20
+)
+
+type ClientOptions struct {
+	// BaseURL is the `gait mcp serve` listen address, e.g. http://127.0.0.1:8787.
+	BaseURL string
+	// Token is sent as a bearer token when the server requires --auth-mode token.
+	Token      string
+	HTTPClient *http.Client
+	RunID      string
+	SessionID  string
+}
+
+// Client evaluates tool calls against a running `gait mcp serve`.
+type Client struct {
+	endpoint string
+	options  ClientOptions
+}
+
+type evaluateRequest struct {
+	Adapter   string       `json:"adapter"`
+	Call      mcp.ToolCall `json:"call"`
+	RunID     string       `json:"run_id,omitempty"`
+	SessionID string       `json:"session_id,omitempty"`
+}
+
+type evaluateResponse struct {
+	Decision
+	OK       bool   `json:"ok"`
+	Error    string `json:"error,omitempty"`
+	ExitCode int    `json:"exit_code"`
+}
+
+func NewClient(options ClientOptions) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimSpace(options.BaseURL))
+	if err != nil {
+		return nil, fmt.Errorf("parse base url: %w", err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("base url must be an absolute http(s) url")
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = &http.Client{Timeout: defaultClientTimeout}
+	}
+	endpoint := parsed.JoinPath("/v1/evaluate")
+	return &Client{endpoint: endpoint.String(), options: options}, nil
+}
+
+// Evaluate posts the call to /v1/evaluate. Non-allow verdicts are returned as
+// decisions, including the 403/409 responses of a strict-mode server; an
+// error means no decision was made and the call must not run.
+func (c *Client) Evaluate(ctx context.Context, call mcp.ToolCall) (Decision, error) {
+	payload, err := json.Marshal(evaluateRequest{
+		Adapter:   "mcp",
+		Call:      call,
+		RunID:     strings.TrimSpace(c.options.RunID),
+		SessionID: strings.TrimSpace(c.options.SessionID),
+	})
+	if err != nil {
+		return Decision{}, fmt.Errorf("encode evaluate request: %w", err)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return Decision{}, err
+	}
+	request.Header.Set("content-type", "application/json")
+	if token := strings.TrimSpace(c.options.Token); token != "" {
+		request.Header.Set("authorization", "Bearer "+token)
+	}
+	response, err := c.options.HTTPClient.Do(request)
+	if err != nil {
+		return Decision{}, fmt.Errorf("evaluate request: %w", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxClientResponseBytes))
+	if err != nil {
+		return Decision{}, fmt.Errorf("read evaluate response: %w", err)
+	}
+	var decoded evaluateResponse
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return Decision{}, fmt.Errorf("decode evaluate response (status %d): %w", response.StatusCode, err)
+	}
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusForbidden, http.StatusConflict:
+	default:
+		if decoded.Error != "" {
+			return Decision{}, fmt.Errorf("evaluate failed (status %d): %s", response.StatusCode, decoded.Error)
+		}
+		return Decision{}, fmt.Errorf("evaluate failed (status %d)", response.StatusCode)
+	}
+	if !decoded.OK || strings.TrimSpace(decoded.Verdict) == "" {
+		if decoded.Error != "" {
+			return Decision{}, fmt.Errorf("evaluate failed: %s", decoded.Error)
+		}
+		return Decision{}, fmt.Errorf("evaluate response has no verdict")
+	}
+	return decoded.Decision, nil
+}
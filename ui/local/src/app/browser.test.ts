@@ -0,0 +1,22 @@
+import { describe, expect, test } from "vitest";
+import { EMPTY_TRACE_FILTERS, artifactQuery, buildTraceQuery, verdictClassName } from "./browser";
+
+describe("evidence browser helpers", () => {
+  test("buildTraceQuery only includes populated filters", () => {
+    expect(buildTraceQuery(EMPTY_TRACE_FILTERS)).toBe("/api/traces");
+    expect(buildTraceQuery({ ...EMPTY_TRACE_FILTERS, verdict: " block ", tool: "tool.*" })).toBe("/api/traces?verdict=block&tool=tool.*");
+  });
+
+  test("artifactQuery encodes workspace paths", () => {
+    expect(artifactQuery("/api/runpacks/diff", { left: "gait-out/a b.zip", right: "gait-out/c.zip" })).toBe(
+      "/api/runpacks/diff?left=gait-out%2Fa+b.zip&right=gait-out%2Fc.zip",
+    );
+  });
+
+  test("verdictClassName maps verdicts to styles", () => {
+    expect(verdictClassName("allow")).toBe("verdict-allow");
+    expect(verdictClassName("BLOCK")).toBe("verdict-block");
+    expect(verdictClassName("require_approval")).toBe("verdict-approval");
+    expect(verdictClassName(undefined)).toBe("verdict-unknown");
+  });
+});
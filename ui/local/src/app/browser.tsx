@@ -0,0 +1,431 @@
+"use client";
+
+import { useState } from "react";
+
+export type TraceSummary = {
+  path: string;
+  trace_id: string;
+  created_at: string;
+  tool_name: string;
+  verdict: string;
+  identity?: string;
+  run_id?: string;
+  policy_id?: string;
+  policy_digest: string;
+  matched_rule_ids?: string[];
+  violations?: string[];
+  signed: boolean;
+};
+
+export type TraceListResponse = {
+  ok: boolean;
+  total: number;
+  traces: TraceSummary[];
+  error?: string;
+};
+
+export type ArtifactEntry = {
+  path: string;
+  size_bytes: number;
+  modified_at: string;
+};
+
+export type ArtifactListResponse = {
+  ok: boolean;
+  artifacts: ArtifactEntry[];
+  error?: string;
+};
+
+export type RunpackTimelineEntry = {
+  intent_id: string;
+  tool_name: string;
+  intent_at: string;
+  args_digest: string;
+  status?: string;
+  result_at?: string;
+  result_digest?: string;
+};
+
+export type RunpackResponse = {
+  ok: boolean;
+  path: string;
+  run_id?: string;
+  manifest_digest?: string;
+  capture_mode?: string;
+  timeline: RunpackTimelineEntry[];
+  error?: string;
+};
+
+export type SessionEvent = {
+  sequence: number;
+  created_at: string;
+  tool_name?: string;
+  verdict?: string;
+  trace_id?: string;
+  reason_codes?: string[];
+};
+
+export type SessionResponse = {
+  ok: boolean;
+  path: string;
+  session_id?: string;
+  run_id?: string;
+  events?: SessionEvent[];
+  checkpoints?: { checkpoint_index: number; runpack_path: string; sequence_start: number; sequence_end: number }[];
+  error?: string;
+};
+
+export type ApprovalState = {
+  request: {
+    request_id: string;
+    created_at: string;
+    expires_at: string;
+    tool_name: string;
+    identity?: string;
+    trace_id?: string;
+    matched_rule?: string;
+    min_approvals?: number;
+  };
+  status: string;
+  decisions?: unknown[];
+};
+
+export type ApprovalListResponse = {
+  ok: boolean;
+  queue_dir: string;
+  approvals: ApprovalState[];
+  error?: string;
+};
+
+export type TraceFilters = {
+  verdict: string;
+  tool: string;
+  identity: string;
+  since: string;
+  until: string;
+};
+
+export type BrowserTab = "traces" | "runpacks" | "sessions" | "approvals";
+
+export const BROWSER_TABS: { id: BrowserTab; label: string }[] = [
+  { id: "traces", label: "Traces" },
+  { id: "runpacks", label: "Runpacks" },
+  { id: "sessions", label: "Sessions" },
+  { id: "approvals", label: "Pending Approvals" },
+];
+
+export const EMPTY_TRACE_FILTERS: TraceFilters = { verdict: "", tool: "", identity: "", since: "", until: "" };
+
+export function buildTraceQuery(filters: TraceFilters): string {
+  const params = new URLSearchParams();
+  for (const key of ["verdict", "tool", "identity", "since", "until"] as const) {
+    const value = filters[key].trim();
+    if (value !== "") {
+      params.set(key, value);
+    }
+  }
+  const query = params.toString();
+  return query === "" ? "/api/traces" : `/api/traces?${query}`;
+}
+
+export function artifactQuery(endpoint: string, params: Record<string, string>): string {
+  return `${endpoint}?${new URLSearchParams(params).toString()}`;
+}
+
+export function verdictClassName(verdict: string | undefined): string {
+  switch ((verdict ?? "").toLowerCase()) {
+    case "allow":
+      return "verdict-allow";
+    case "block":
+      return "verdict-block";
+    case "require_approval":
+      return "verdict-approval";
+    case "dry_run":
+      return "verdict-dry-run";
+    default:
+      return "verdict-unknown";
+  }
+}
+
+async function fetchJSON<T>(path: string): Promise<T> {
+  const response = await fetch(path);
+  const payload = (await response.json()) as T & { error?: string };
+  if (!response.ok) {
+    throw new Error(payload.error ?? `request failed (${response.status})`);
+  }
+  return payload;
+}
+
+export function EvidenceBrowser() {
+  const [tab, setTab] = useState<BrowserTab>("traces");
+  const [loading, setLoading] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+  const [filters, setFilters] = useState<TraceFilters>(EMPTY_TRACE_FILTERS);
+  const [traces, setTraces] = useState<TraceListResponse | null>(null);
+  const [runpacks, setRunpacks] = useState<ArtifactEntry[] | null>(null);
+  const [sessions, setSessions] = useState<ArtifactEntry[] | null>(null);
+  const [approvals, setApprovals] = useState<ApprovalListResponse | null>(null);
+  const [diffLeft, setDiffLeft] = useState("");
+  const [diffRight, setDiffRight] = useState("");
+  const [detail, setDetail] = useState<{ title: string; body: unknown } | null>(null);
+  const [runpackDetail, setRunpackDetail] = useState<RunpackResponse | null>(null);
+  const [sessionDetail, setSessionDetail] = useState<SessionResponse | null>(null);
+
+  const load = async <T,>(path: string, apply: (payload: T) => void) => {
+    setLoading(true);
+    setError(null);
+    try {
+      apply(await fetchJSON<T>(path));
+    } catch (loadError) {
+      setError(loadError instanceof Error ? loadError.message : "unknown error");
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const refresh = async (target: BrowserTab = tab) => {
+    switch (target) {
+      case "traces":
+        await load<TraceListResponse>(buildTraceQuery(filters), setTraces);
+        return;
+      case "runpacks":
+        await load<ArtifactListResponse>("/api/runpacks", (payload) => setRunpacks(payload.artifacts));
+        return;
+      case "sessions":
+        await load<ArtifactListResponse>("/api/sessions", (payload) => setSessions(payload.artifacts));
+        return;
+      case "approvals":
+        await load<ApprovalListResponse>("/api/approvals", setApprovals);
+        return;
+    }
+  };
+
+  const selectTab = (target: BrowserTab) => {
+    setTab(target);
+    setDetail(null);
+    setRunpackDetail(null);
+    setSessionDetail(null);
+    void refresh(target);
+  };
+
+  const showDetail = async (title: string, path: string) => {
+    await load<unknown>(path, (payload) => setDetail({ title, body: payload }));
+  };
+
+  return (
+    <section className="panel browser-panel">
+      <div className="panel-header">
+        <h2>Evidence Browser</h2>
+        <span className="panel-note">Read-only views over local artifacts.</span>
+      </div>
+      <div className="browser-tabs">
+        {BROWSER_TABS.map((candidate) => (
+          <button
+            key={candidate.id}
+            type="button"
+            className={`ghost-button ${tab === candidate.id ? "browser-tab-selected" : ""}`}
+            onClick={() => selectTab(candidate.id)}
+            disabled={loading}
+          >
+            {candidate.label}
+          </button>
+        ))}
+        <button type="button" className="run-button" onClick={() => void refresh()} disabled={loading}>
+          {loading ? "Loading..." : "Refresh"}
+        </button>
+      </div>
+      {error ? <p className="browser-error">{error}</p> : null}
+
+      {tab === "traces" ? (
+        <div>
+          <div className="input-grid browser-filters">
+            {(["verdict", "tool", "identity", "since", "until"] as const).map((key) => (
+              <label key={key}>
+                {key}
+                <input
+                  value={filters[key]}
+                  placeholder={key === "since" || key === "until" ? "2026-01-01T00:00:00Z" : key === "tool" ? "tool.*" : ""}
+                  onChange={(event) => setFilters({ ...filters, [key]: event.target.value })}
+                />
+              </label>
+            ))}
+          </div>
+          {traces === null ? (
+            <p className="panel-note">Select Refresh to load traces.</p>
+          ) : (
+            <table className="browser-table">
+              <thead>
+                <tr>
+                  <th>Created</th>
+                  <th>Tool</th>
+                  <th>Verdict</th>
+                  <th>Identity</th>
+                  <th>Trace</th>
+                  <th />
+                </tr>
+              </thead>
+              <tbody>
+                {traces.traces.map((trace) => (
+                  <tr key={trace.path}>
+                    <td>{trace.created_at}</td>
+                    <td>{trace.tool_name}</td>
+                    <td>
+                      <span className={`verdict-pill ${verdictClassName(trace.verdict)}`}>{trace.verdict}</span>
+                    </td>
+                    <td>{trace.identity ?? ""}</td>
+                    <td title={trace.path}>{trace.trace_id}</td>
+                    <td className="browser-actions">
+                      <button type="button" className="copy-button" onClick={() => void showDetail(`Trace ${trace.trace_id}`, artifactQuery("/api/trace", { path: trace.path }))}>
+                        Open
+                      </button>
+                      <button
+                        type="button"
+                        className="copy-button"
+                        onClick={() => void showDetail(`Explain ${trace.trace_id}`, artifactQuery("/api/trace/explain", { path: trace.path }))}
+                      >
+                        Explain
+                      </button>
+                    </td>
+                  </tr>
+                ))}
+              </tbody>
+              <tfoot>
+                <tr>
+                  <td colSpan={6}>
+                    Showing {traces.traces.length} of {traces.total}
+                  </td>
+                </tr>
+              </tfoot>
+            </table>
+          )}
+        </div>
+      ) : null}
+
+      {tab === "runpacks" ? (
+        <div>
+          {(runpacks ?? []).length === 0 ? <p className="panel-note">No runpacks loaded.</p> : null}
+          <ul className="browser-list">
+            {(runpacks ?? []).map((artifact) => (
+              <li key={artifact.path}>
+                <span title={artifact.modified_at}>{artifact.path}</span>
+                <span className="browser-actions">
+                  <button
+                    type="button"
+                    className="copy-button"
+                    onClick={() => void load<RunpackResponse>(artifactQuery("/api/runpack", { path: artifact.path }), setRunpackDetail)}
+                  >
+                    Timeline
+                  </button>
+                  <button type="button" className="copy-button" onClick={() => setDiffLeft(artifact.path)}>
+                    Left
+                  </button>
+                  <button type="button" className="copy-button" onClick={() => setDiffRight(artifact.path)}>
+                    Right
+                  </button>
+                </span>
+              </li>
+            ))}
+          </ul>
+          <div className="browser-diff">
+            <span>
+              Diff: {diffLeft || "(left)"} vs {diffRight || "(right)"}
+            </span>
+            <button
+              type="button"
+              className="run-button"
+              disabled={loading || diffLeft === "" || diffRight === ""}
+              onClick={() => void showDetail("Runpack diff", artifactQuery("/api/runpacks/diff", { left: diffLeft, right: diffRight }))}
+            >
+              Diff
+            </button>
+          </div>
+          {runpackDetail ? (
+            <div className="browser-timeline">
+              <strong>
+                {runpackDetail.run_id} ({runpackDetail.capture_mode}) {runpackDetail.manifest_digest}
+              </strong>
+              <ol>
+                {runpackDetail.timeline.map((entry) => (
+                  <li key={entry.intent_id}>
+                    <span>{entry.intent_at}</span> <strong>{entry.tool_name}</strong> <span>{entry.intent_id}</span>{" "}
+                    <span className="verdict-pill">{entry.status ?? "no result"}</span>
+                  </li>
+                ))}
+              </ol>
+            </div>
+          ) : null}
+        </div>
+      ) : null}
+
+      {tab === "sessions" ? (
+        <div>
+          {(sessions ?? []).length === 0 ? <p className="panel-note">No session journals or chains loaded.</p> : null}
+          <ul className="browser-list">
+            {(sessions ?? []).map((artifact) => (
+              <li key={artifact.path}>
+                <span title={artifact.modified_at}>{artifact.path}</span>
+                <button
+                  type="button"
+                  className="copy-button"
+                  onClick={() => void load<SessionResponse>(artifactQuery("/api/session", { path: artifact.path }), setSessionDetail)}
+                >
+                  Open
+                </button>
+              </li>
+            ))}
+          </ul>
+          {sessionDetail ? (
+            <div className="browser-timeline">
+              <strong>
+                {sessionDetail.session_id} / {sessionDetail.run_id}
+              </strong>
+              <ol>
+                {(sessionDetail.events ?? []).map((event) => (
+                  <li key={event.sequence}>
+                    <span>#{event.sequence}</span> <span>{event.created_at}</span> <strong>{event.tool_name}</strong>{" "}
+                    <span className={`verdict-pill ${verdictClassName(event.verdict)}`}>{event.verdict}</span>
+                  </li>
+                ))}
+                {(sessionDetail.checkpoints ?? []).map((checkpoint) => (
+                  <li key={`checkpoint-${checkpoint.checkpoint_index}`}>
+                    checkpoint {checkpoint.checkpoint_index}: {checkpoint.sequence_start}-{checkpoint.sequence_end} {checkpoint.runpack_path}
+                  </li>
+                ))}
+              </ol>
+            </div>
+          ) : null}
+        </div>
+      ) : null}
+
+      {tab === "approvals" ? (
+        <div>
+          {approvals === null || approvals.approvals.length === 0 ? <p className="panel-note">No pending approval requests.</p> : null}
+          <ul className="browser-list">
+            {(approvals?.approvals ?? []).map((approval) => (
+              <li key={approval.request.request_id}>
+                <span>
+                  <strong>{approval.request.tool_name}</strong> {approval.request.identity ?? ""} {approval.request.matched_rule ?? ""}
+                </span>
+                <span className="panel-note">
+                  expires {approval.request.expires_at} · gait approve grant --request-id {approval.request.request_id}
+                </span>
+              </li>
+            ))}
+          </ul>
+        </div>
+      ) : null}
+
+      {detail ? (
+        <div className="code-panel">
+          <div className="code-panel-header">
+            <span>{detail.title}</span>
+            <button type="button" className="copy-button" onClick={() => setDetail(null)}>
+              Close
+            </button>
+          </div>
+          <pre className="code-block">{JSON.stringify(detail.body, null, 2)}</pre>
+        </div>
+      ) : null}
+    </section>
+  );
+}
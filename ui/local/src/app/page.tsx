@@ -1,6 +1,7 @@
 "use client";
 
 import { useEffect, useMemo, useState } from "react";
+import { EvidenceBrowser } from "./browser";
 
 export type ExecResponse = {
   ok: boolean;
@@ -518,6 +519,8 @@ export default function Page() {
           </pre>
         </section>
       </main>
+
+      <EvidenceBrowser />
     </div>
   );
 }